@@ -154,12 +154,18 @@ func (cl *commandlineBck) dumpToFile(cmd *cobra.Command) {
 func (cl *commandlineBck) backup(cmd *cobra.Command) {
 	defaultDbDir := server.DefaultOptions().Dir
 	ccmd := &cobra.Command{
-		Use:   "backup [--dbdir] [--manual-stop-start] [--uncompressed]",
-		Short: "Make a copy of the database files and folders",
-		Long: "Pause the immudb server, create and save on the server machine a snapshot " +
+		Use:   "backup [database [file]] [--since-tx] [--server-path] [--retries] [--dbdir] [--manual-stop-start] [--uncompressed]",
+		Short: "Back up a database while the server is running, or make a copy of the database files and folders",
+		Long: "When a database is given, back it up while the server keeps running: the archive is downloaded " +
+			"into a local file (default <database>_<timestamp>.bak) or, with --server-path, stored by the server " +
+			"in its backup storage. Interrupted downloads are resumed from the last received transaction.\n" +
+			"Otherwise, pause the immudb server, create and save on the server machine a snapshot " +
 			"of the database files and folders (zip on Windows, tar.gz on Linux or uncompressed).",
 		PersistentPreRunE: cl.ConfigChain(nil),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return cl.onlineBackup(cmd, args)
+			}
 			dbDir, err := cmd.Flags().GetString("dbdir")
 			if err != nil {
 				cl.quit(err)
@@ -191,8 +197,11 @@ func (cl *commandlineBck) backup(cmd *cobra.Command) {
 			fmt.Printf("Database backup created: %s\n", backupPath)
 			return nil
 		},
-		Args: cobra.NoArgs,
+		Args: cobra.MaximumNArgs(2),
 	}
+	ccmd.Flags().Uint64("since-tx", 0, "only back up the transactions committed after the given one (incremental backup)")
+	ccmd.Flags().String("server-path", "", "store the backup in the server backup storage under the given name instead of downloading it")
+	ccmd.Flags().Int("retries", 3, "number of times an interrupted download is resumed")
 	ccmd.Flags().String("dbdir", defaultDbDir, fmt.Sprintf("path to the server database directory to backup (default %s)", defaultDbDir))
 	ccmd.Flags().Bool("manual-stop-start", false, "server stop before and restart after the backup are to be handled manually by the user (default false)")
 	ccmd.Flags().BoolP("uncompressed", "u", false, "create an uncompressed backup (i.e. make just a copy of the db directory)")
@@ -202,12 +211,31 @@ func (cl *commandlineBck) backup(cmd *cobra.Command) {
 func (cl *commandlineBck) restore(cmd *cobra.Command) {
 	defaultDbDir := server.DefaultOptions().Dir
 	ccmd := &cobra.Command{
-		Use:   "restore snapshot-path [--dbdir] [--manual-stop-start]",
-		Short: "Restore the database from a snapshot archive or folder",
-		Long: "Pause the immudb server and restore the database files and folders from a snapshot " +
-			"file (zip or tar.gz) or folder (uncompressed) residing on the server machine.",
+		Use:   "restore backup-path... [--database] [--until-tx] [--until-time] [--verify] [--copy-permissions] [--read-only-permissions] [--replica] [--src-database] [--src-address] [--src-port] [--offline] [--dbdir] [--manual-stop-start]",
+		Short: "Restore a database from backups, or the database files and folders from a snapshot",
+		Long: "Restore a database while the server keeps running, replaying a full backup followed by its " +
+			"incremental backups, all of them residing in the server backup storage. With --verify, the " +
+			"restored database is checked against inclusion and consistency proofs. When restored under a " +
+			"different name with --database, --copy-permissions grants users the same permissions they hold " +
+			"on the backed up database. With --replica, the database is restored as a replica of the given source, " +
+			"which then only has to replicate the transactions committed after the restored ones.\n" +
+			"With --offline (implied by --dbdir and --manual-stop-start), pause the immudb server and restore " +
+			"the database files and folders from a snapshot file (zip or tar.gz) or folder (uncompressed) " +
+			"residing on the server machine.",
 		PersistentPreRunE: cl.ConfigChain(nil),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			offline, err := cmd.Flags().GetBool("offline")
+			if err != nil {
+				cl.quit(err)
+				return nil
+			}
+			if !offline && !cmd.Flags().Changed("dbdir") && !cmd.Flags().Changed("manual-stop-start") {
+				return cl.onlineRestore(cmd, args)
+			}
+			if len(args) != 1 {
+				cl.quit(errors.New("exactly one snapshot path is required by an offline restore"))
+				return nil
+			}
 			snapshotPath := args[0]
 			dbDir, err := cmd.Flags().GetString("dbdir")
 			if err != nil {
@@ -232,8 +260,21 @@ func (cl *commandlineBck) restore(cmd *cobra.Command) {
 			fmt.Printf("A backup of the previous database has been also created: %s\n", autoBackupPath)
 			return nil
 		},
-		Args: cobra.ExactArgs(1),
+		Args: cobra.MinimumNArgs(1),
 	}
+	ccmd.Flags().String("database", "", "name of the restored database (default the name of the backed up database)")
+	ccmd.Flags().Uint64("until-tx", 0, "restore up to the given transaction")
+	ccmd.Flags().String("until-time", "", "restore up to the last transaction committed before the given time (RFC3339)")
+	ccmd.Flags().Bool("verify", false, "verify the restored database using inclusion and consistency proofs")
+	ccmd.Flags().Bool("copy-permissions", false, "grant users the permissions they hold on the backed up database on the restored one as well")
+	ccmd.Flags().Bool("read-only-permissions", false, "copied permissions are downgraded to read-only (requires --copy-permissions)")
+	ccmd.Flags().Bool("replica", false, "restore the database as a replica of the given source database")
+	ccmd.Flags().String("src-database", "", "source database name of the restored replica")
+	ccmd.Flags().String("src-address", "", "source database address of the restored replica")
+	ccmd.Flags().Uint32("src-port", 0, "source database port of the restored replica")
+	ccmd.Flags().String("follower-username", "", "username used by the restored replica to connect to the source database")
+	ccmd.Flags().String("follower-password", "", "password used by the restored replica to connect to the source database")
+	ccmd.Flags().Bool("offline", false, "stop the server and replace the database files and folders with a snapshot")
 	ccmd.Flags().String("dbdir", defaultDbDir, fmt.Sprintf("path to the server database directory which will be replaced by the backup (default %s)", defaultDbDir))
 	ccmd.Flags().Bool("manual-stop-start", false, "server stop before and restart after the backup are to be handled manually by the user (default false)")
 	cmd.AddCommand(ccmd)
@@ -0,0 +1,428 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/backup"
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// archiveDownload downloads an online backup into a local archive.
+// When the connection is lost, the backup is resumed from the last received transaction and
+// limited to the state pinned by the first attempt, so the resulting archive is the same
+type archiveDownload struct {
+	client     client.ImmuClient
+	database   string
+	sinceTx    uint64
+	retries    int
+	retryDelay time.Duration
+
+	// progress is notified about every received transaction
+	progress func(done, total uint64)
+	// resumed is notified before resuming an interrupted download
+	resumed func(fromTx uint64, err error)
+
+	aw      *backup.Writer
+	fromTx  uint64
+	untilTx uint64
+}
+
+func isTransientError(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+func (d *archiveDownload) run(ctx context.Context, w io.Writer) (*backup.Trailer, error) {
+	for attempt := 0; ; attempt++ {
+		trailer, err := d.fetch(ctx, w)
+		if err == nil {
+			return trailer, nil
+		}
+
+		if !isTransientError(err) || attempt == d.retries {
+			return nil, err
+		}
+
+		if d.resumed != nil {
+			d.resumed(d.nextTx(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(d.retryDelay):
+		}
+	}
+}
+
+func (d *archiveDownload) nextTx() uint64 {
+	if d.aw == nil {
+		return d.sinceTx + 1
+	}
+	return d.aw.Trailer().TxId + 1
+}
+
+func (d *archiveDownload) fetch(ctx context.Context, w io.Writer) (*backup.Trailer, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req := &schema.BackupRequest{
+		DatabaseName: d.database,
+		SinceTx:      d.nextTx() - 1,
+		UntilTx:      d.untilTx,
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	go func() {
+		pw.CloseWithError(d.client.Backup(ctx, req, pw))
+	}()
+
+	ar, err := backup.NewReader(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	hdr := ar.Header()
+
+	if d.aw == nil {
+		d.fromTx = hdr.FromTx
+		d.untilTx = hdr.UntilTx
+
+		d.aw, err = backup.NewWriter(w, hdr)
+		if err != nil {
+			return nil, err
+		}
+	} else if !hdr.Follows(d.aw.Trailer()) || hdr.UntilTx != d.untilTx {
+		return nil, backup.ErrBrokenChain
+	}
+
+	for {
+		rec, err := ar.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = d.aw.WriteTx(rec)
+		if err != nil {
+			return nil, err
+		}
+
+		if d.progress != nil {
+			d.progress(rec.TxID-d.fromTx+1, d.untilTx-d.fromTx+1)
+		}
+	}
+
+	// the signature covers the database state, thus it's the same for the whole archive
+	trailer := d.aw.Trailer()
+	trailer.Signature = ar.Trailer().Signature
+
+	err = d.aw.Close(trailer)
+	if err != nil {
+		return nil, err
+	}
+
+	return trailer, nil
+}
+
+// progressPrinter prints the progress of a backup at most once per interval
+func progressPrinter(out io.Writer, database string, interval time.Duration) func(done, total uint64) {
+	var last time.Time
+
+	return func(done, total uint64) {
+		if done < total && time.Since(last) < interval {
+			return
+		}
+		last = time.Now()
+
+		fmt.Fprintf(out, "\rbacking up database '%s': %d of %d transactions (%d%%)", database, done, total, 100*done/total)
+
+		if done == total {
+			fmt.Fprintln(out)
+		}
+	}
+}
+
+func (cl *commandlineBck) onlineBackup(cmd *cobra.Command, args []string) error {
+	sinceTx, err := cmd.Flags().GetUint64("since-tx")
+	if err != nil {
+		cl.quit(err)
+		return nil
+	}
+	serverPath, err := cmd.Flags().GetString("server-path")
+	if err != nil {
+		cl.quit(err)
+		return nil
+	}
+	retries, err := cmd.Flags().GetInt("retries")
+	if err != nil {
+		cl.quit(err)
+		return nil
+	}
+
+	database := args[0]
+
+	if serverPath != "" && len(args) > 1 {
+		cl.quit(errors.New("a local file can not be provided along with --server-path"))
+		return nil
+	}
+
+	if err := cl.checkLoggedInAndConnect(cmd, args); err != nil {
+		return nil
+	}
+	defer cl.disconnect(cmd, args)
+
+	if serverPath != "" {
+		req := &schema.BackupRequest{
+			DatabaseName: database,
+			Path:         serverPath,
+			SinceTx:      sinceTx,
+		}
+		if err := cl.immuClient.Backup(cl.context, req, nil); err != nil {
+			cl.quit(err)
+			return nil
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Database '%s' backed up into %s on the server\n", database, serverPath)
+		return nil
+	}
+
+	filename := fmt.Sprintf("%s_%s.bak", database, time.Now().Format("2006-01-02_15-04-05"))
+	if len(args) > 1 {
+		filename = args[1]
+	}
+
+	file, err := cl.os.Create(filename)
+	if err != nil {
+		cl.quit(err)
+		return nil
+	}
+
+	d := &archiveDownload{
+		client:     cl.immuClient,
+		database:   database,
+		sinceTx:    sinceTx,
+		retries:    retries,
+		retryDelay: time.Second,
+		progress:   progressPrinter(cmd.ErrOrStderr(), database, time.Second),
+		resumed: func(fromTx uint64, err error) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "\nconnection lost (%v), resuming from tx %d\n", err, fromTx)
+		},
+	}
+
+	w := bufio.NewWriter(file)
+
+	trailer, err := d.run(cl.context, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		cl.os.Remove(filename)
+		cl.quit(err)
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Database '%s' backed up into %s: %d transactions, up to tx %d\n",
+		database, filename, trailer.TxCount, trailer.TxId)
+	return nil
+}
+
+func (cl *commandlineBck) onlineRestore(cmd *cobra.Command, args []string) error {
+	database, err := cmd.Flags().GetString("database")
+	if err != nil {
+		cl.quit(err)
+		return nil
+	}
+	untilTx, err := cmd.Flags().GetUint64("until-tx")
+	if err != nil {
+		cl.quit(err)
+		return nil
+	}
+	untilTime, err := cmd.Flags().GetString("until-time")
+	if err != nil {
+		cl.quit(err)
+		return nil
+	}
+	verify, err := cmd.Flags().GetBool("verify")
+	if err != nil {
+		cl.quit(err)
+		return nil
+	}
+	copyPermissions, err := cmd.Flags().GetBool("copy-permissions")
+	if err != nil {
+		cl.quit(err)
+		return nil
+	}
+	readOnlyPermissions, err := cmd.Flags().GetBool("read-only-permissions")
+	if err != nil {
+		cl.quit(err)
+		return nil
+	}
+
+	replicationSettings, err := restoredReplicaSettings(cmd)
+	if err != nil {
+		cl.quit(err)
+		return nil
+	}
+
+	var untilTs int64
+
+	if untilTime != "" {
+		t, err := time.Parse(time.RFC3339, untilTime)
+		if err != nil {
+			cl.quit(fmt.Errorf("invalid --until-time, an RFC3339 time is expected: %v", err))
+			return nil
+		}
+		untilTs = t.Unix()
+	}
+
+	if err := cl.checkLoggedInAndConnect(cmd, args); err != nil {
+		return nil
+	}
+	defer cl.disconnect(cmd, args)
+
+	res, err := cl.immuClient.Restore(cl.context, &schema.RestoreRequest{
+		DatabaseName:        database,
+		Paths:               args,
+		UntilTx:             untilTx,
+		UntilTs:             untilTs,
+		CopyPermissions:     copyPermissions,
+		ReadOnlyPermissions: readOnlyPermissions,
+		ReplicationSettings: replicationSettings,
+	})
+	if err != nil {
+		cl.quit(err)
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Database '%s' restored up to tx %d\n", res.DatabaseName, res.TxId)
+
+	if replicationSettings != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "Replica '%s' resumes replication from tx %d\n", res.DatabaseName, res.TxId+1)
+	}
+
+	if copyPermissions {
+		fmt.Fprintf(cmd.OutOrStdout(), "Permissions of %d users copied to database '%s'\n", res.CopiedPermissions, res.DatabaseName)
+	}
+
+	if !verify {
+		return nil
+	}
+
+	if err := cl.verifyRestoredDatabase(res.DatabaseName, res.TxId); err != nil {
+		cl.quit(fmt.Errorf("verification of database '%s' failed: %v", res.DatabaseName, err))
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Database '%s' verified up to tx %d\n", res.DatabaseName, res.TxId)
+	return nil
+}
+
+// restoredReplicaSettings returns the replication settings of a database restored as a replica, nil otherwise
+func restoredReplicaSettings(cmd *cobra.Command) (*schema.ReplicationNullableSettings, error) {
+	flags := cmd.Flags()
+
+	replica, err := flags.GetBool("replica")
+	if err != nil || !replica {
+		return nil, err
+	}
+
+	srcDatabase, err := flags.GetString("src-database")
+	if err != nil {
+		return nil, err
+	}
+	srcAddress, err := flags.GetString("src-address")
+	if err != nil {
+		return nil, err
+	}
+	srcPort, err := flags.GetUint32("src-port")
+	if err != nil {
+		return nil, err
+	}
+	followerUsr, err := flags.GetString("follower-username")
+	if err != nil {
+		return nil, err
+	}
+	followerPwd, err := flags.GetString("follower-password")
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.ReplicationNullableSettings{
+		Replica:     &schema.NullableBool{Value: true},
+		SrcDatabase: &schema.NullableString{Value: srcDatabase},
+		SrcAddress:  &schema.NullableString{Value: srcAddress},
+		SrcPort:     &schema.NullableUint32{Value: srcPort},
+		FollowerUsr: &schema.NullableString{Value: followerUsr},
+		FollowerPwd: &schema.NullableString{Value: followerPwd},
+	}, nil
+}
+
+// verifyRestoredDatabase checks the first and the last restored transactions with inclusion and consistency
+// proofs, the local state is updated along the way so that any further divergence gets detected.
+// The previously used database is selected again once done
+func (cl *commandlineBck) verifyRestoredDatabase(database string, txID uint64) error {
+	prevDatabase := cl.immuClient.GetOptions().CurrentDatabase
+
+	if err := cl.useDatabase(database); err != nil {
+		return err
+	}
+
+	if prevDatabase != "" && prevDatabase != database {
+		defer cl.useDatabase(prevDatabase)
+	}
+
+	if txID == 0 {
+		return nil
+	}
+
+	if _, err := cl.immuClient.VerifiedTxByID(cl.context, 1); err != nil {
+		return err
+	}
+
+	if txID > 1 {
+		if _, err := cl.immuClient.VerifiedTxByID(cl.context, txID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cl *commandlineBck) useDatabase(database string) error {
+	resp, err := cl.immuClient.UseDatabase(cl.context, &schema.Database{DatabaseName: database})
+	if err != nil {
+		return err
+	}
+	return cl.ts.SetToken(database, resp.Token)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/backup"
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// flakyBackupClient drops the connection in the middle of the first backup
+type flakyBackupClient struct {
+	client.ImmuClient
+	calls int
+}
+
+func (c *flakyBackupClient) Backup(ctx context.Context, req *schema.BackupRequest, w io.Writer) error {
+	c.calls++
+
+	if c.calls > 1 {
+		return c.ImmuClient.Backup(ctx, req, w)
+	}
+
+	var buf bytes.Buffer
+
+	err := c.ImmuClient.Backup(ctx, req, &buf)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(buf.Bytes()[:2*buf.Len()/3])
+	if err != nil {
+		return err
+	}
+
+	return status.Error(codes.Unavailable, "connection lost")
+}
+
+func TestArchiveDownload(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	err := bs.Start()
+	require.NoError(t, err)
+	defer bs.Stop()
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	dialOptions := []grpc.DialOption{
+		grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+	}
+	ts := client.NewTokenService().WithHds(clienttest.DefaultHomedirServiceMock()).WithTokenFileName("testTokenFile")
+
+	cli, err := client.NewImmuClient(client.DefaultOptions().WithDialOptions(&dialOptions).WithTokenService(ts))
+	require.NoError(t, err)
+	defer cli.Disconnect()
+
+	lr, err := cli.Login(context.Background(), []byte("immudb"), []byte("immudb"))
+	require.NoError(t, err)
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	for i := 0; i < 10; i++ {
+		_, err = cli.Set(ctx, []byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+		require.NoError(t, err)
+	}
+
+	t.Run("resume after a transient error", func(t *testing.T) {
+		flaky := &flakyBackupClient{ImmuClient: cli}
+
+		var resumedFrom uint64
+		var done, total uint64
+
+		d := &archiveDownload{
+			client:   flaky,
+			database: "defaultdb",
+			retries:  1,
+			progress: func(d, t uint64) {
+				done, total = d, t
+			},
+			resumed: func(fromTx uint64, err error) {
+				resumedFrom = fromTx
+
+				// transactions committed meanwhile are not included
+				_, err = cli.Set(ctx, []byte("late"), []byte("late"))
+				require.NoError(t, err)
+			},
+		}
+
+		var buf bytes.Buffer
+
+		trailer, err := d.run(ctx, &buf)
+		require.NoError(t, err)
+		require.Equal(t, 2, flaky.calls)
+		require.Greater(t, resumedFrom, uint64(1))
+		require.Equal(t, uint64(11), trailer.TxId)
+		require.Equal(t, uint64(11), trailer.TxCount)
+		require.Equal(t, total, done)
+
+		ar, err := backup.NewReader(&buf)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), ar.Header().FromTx)
+		require.Equal(t, uint64(11), ar.Header().UntilTx)
+
+		for {
+			_, err = ar.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, trailer.TxHash, ar.Trailer().TxHash)
+	})
+
+	t.Run("fail once retries are exhausted", func(t *testing.T) {
+		d := &archiveDownload{
+			client:   &flakyBackupClient{ImmuClient: cli},
+			database: "defaultdb",
+			sinceTx:  5,
+		}
+
+		_, err := d.run(ctx, &bytes.Buffer{})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("non transient errors are not retried", func(t *testing.T) {
+		flaky := &flakyBackupClient{ImmuClient: cli}
+
+		d := &archiveDownload{
+			client:     flaky,
+			database:   "nonexistent",
+			retries:    3,
+			retryDelay: time.Millisecond,
+		}
+
+		_, err := d.run(ctx, &bytes.Buffer{})
+		require.Error(t, err)
+		require.Equal(t, 1, flaky.calls)
+	})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	c "github.com/codenotary/immudb/cmd/helper"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/spf13/cobra"
+)
+
+func (cl *commandline) cluster(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:               "cluster",
+		Short:             "Inspect the cluster the server is a node of",
+		PersistentPostRun: cl.disconnect,
+		ValidArgs:         []string{"status"},
+	}
+
+	cs := &cobra.Command{
+		Use:               "status",
+		Short:             "Show the role of the node and the leader in the Raft group of each database",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		Example:           "status [database_name]",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &schema.ClusterStatusRequest{}
+			if len(args) > 0 {
+				req.Database = args[0]
+			}
+
+			res, err := cl.immuClient.ClusterStatus(cl.context, req)
+			if err != nil {
+				return err
+			}
+
+			printClusterStatus(cmd.OutOrStdout(), res)
+
+			return nil
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	ccmd.AddCommand(cs)
+	cmd.AddCommand(ccmd)
+}
+
+func printClusterStatus(out io.Writer, res *schema.ClusterStatusResponse) {
+	fmt.Fprintf(out, "cluster node: %s\n", res.NodeId)
+
+	c.PrintTable(
+		out,
+		[]string{"Database", "Role", "Leader", "Term", "Commit Index", "Applied Index", "Applied Tx", "Members"},
+		len(res.Databases),
+		func(i int) []string {
+			status := res.Databases[i]
+
+			leader := "none"
+			if status.Leader != nil {
+				leader = fmt.Sprintf("%s (%s)", status.Leader.NodeId, status.Leader.Address)
+			}
+
+			role := status.Role
+			if status.Diverged {
+				role += " (diverged)"
+			}
+
+			members := make([]string, len(status.Members))
+			for i, m := range status.Members {
+				members[i] = m.NodeId
+			}
+
+			return []string{
+				status.Database,
+				role,
+				leader,
+				fmt.Sprintf("%d", status.Term),
+				fmt.Sprintf("%d", status.CommitIndex),
+				fmt.Sprintf("%d", status.AppliedIndex),
+				fmt.Sprintf("%d", status.AppliedTxId),
+				strings.Join(members, ", "),
+			}
+		},
+		fmt.Sprintf("%d database(s)", len(res.Databases)),
+	)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintClusterStatus(t *testing.T) {
+	var b bytes.Buffer
+
+	printClusterStatus(&b, &schema.ClusterStatusResponse{
+		NodeId: "node1",
+		Databases: []*schema.ClusterDatabaseStatus{
+			{
+				Database:     "defaultdb",
+				Role:         "leader",
+				Leader:       &schema.ClusterLeader{Database: "defaultdb", NodeId: "node1", Address: "10.0.0.1:3322"},
+				Term:         3,
+				CommitIndex:  42,
+				AppliedIndex: 42,
+				AppliedTxId:  40,
+				Members: []*schema.ClusterMember{
+					{NodeId: "node1"}, {NodeId: "node2"}, {NodeId: "node3"},
+				},
+			},
+			{Database: "db1", Role: "follower", Diverged: true},
+		},
+	})
+
+	out := b.String()
+	require.Contains(t, out, "cluster node: node1")
+	require.Contains(t, out, "node1 (10.0.0.1:3322)")
+	require.Contains(t, out, "node1, node2, node3")
+	require.Contains(t, out, "follower (diverged)")
+	require.Contains(t, out, "none")
+	require.Contains(t, out, "2 database(s)")
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	c "github.com/codenotary/immudb/cmd/helper"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/spf13/cobra"
+)
+
+func (cl *commandline) corruptionChecker(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:               "corruption-checker",
+		Short:             "Inspect the integrity scans of the server corruption checker",
+		Aliases:           []string{"cc"},
+		PersistentPostRun: cl.disconnect,
+		ValidArgs:         []string{"status", "scrub", "acknowledge"},
+	}
+
+	cs := &cobra.Command{
+		Use:               "status",
+		Short:             "Show the last completed and the running integrity scan of each database",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		Example:           "status [database_name]",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &schema.CorruptionCheckStatusRequest{}
+			if len(args) > 0 {
+				req.Database = args[0]
+			}
+
+			res, err := cl.immuClient.CorruptionCheckStatus(cl.context, req)
+			if err != nil {
+				return err
+			}
+
+			printCorruptionCheckStatus(cmd.OutOrStdout(), res)
+
+			return nil
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	csc := &cobra.Command{
+		Use:               "scrub",
+		Short:             "Verify a database and recover its damaged values out of another copy of it",
+		Long:              "Verify a database and recover its damaged values out of another copy of it: a database on the same server, a remote one or, when no source is given, the source of the database if it is a replica. Repairs are recorded in the system database.",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		Example:           "scrub {database_name} [--source-database replica_db] [--source-address 10.0.0.2 --source-port 3322 --source-username immudb]",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &schema.ScrubRequest{Database: args[0]}
+
+			var err error
+
+			if req.SourceDatabase, err = cmd.Flags().GetString("source-database"); err != nil {
+				return err
+			}
+			if req.SourceAddress, err = cmd.Flags().GetString("source-address"); err != nil {
+				return err
+			}
+			if req.SourcePort, err = cmd.Flags().GetUint32("source-port"); err != nil {
+				return err
+			}
+			if req.SourceUsername, err = cmd.Flags().GetString("source-username"); err != nil {
+				return err
+			}
+			if req.SourcePassword, err = cmd.Flags().GetString("source-password"); err != nil {
+				return err
+			}
+
+			res, err := cl.immuClient.Scrub(cl.context, req)
+			if err != nil {
+				return err
+			}
+
+			printScrubResult(cmd.OutOrStdout(), res)
+
+			return nil
+		},
+		Args: cobra.ExactArgs(1),
+	}
+	csc.Flags().String("source-database", "", "database holding the copy of the transactions, on this server unless a source address is given")
+	csc.Flags().String("source-address", "", "address of the remote immudb server holding the copy of the transactions")
+	csc.Flags().Uint32("source-port", 0, "port of the remote immudb server (default 3322)")
+	csc.Flags().String("source-username", "", "username used to connect to the remote immudb server")
+	csc.Flags().String("source-password", "", "password used to connect to the remote immudb server")
+
+	cack := &cobra.Command{
+		Use:               "acknowledge",
+		Short:             "Acknowledge the corruption detected in a quarantined database so it accepts writes again",
+		Long:              "Acknowledge the corruption detected in a quarantined database so it accepts writes again. Unless its damaged transactions got repaired, e.g. through scrub, the corruption checker detects them again.",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		Example:           "acknowledge {database_name}",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := cl.immuClient.AcknowledgeCorruption(cl.context, &schema.AcknowledgeCorruptionRequest{Database: args[0]})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "corruption of database '%s' acknowledged, writes are accepted again\n", args[0])
+
+			return nil
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	ccmd.AddCommand(cs)
+	ccmd.AddCommand(csc)
+	ccmd.AddCommand(cack)
+	cmd.AddCommand(ccmd)
+}
+
+func printCorruptionCheckStatus(out io.Writer, res *schema.CorruptionCheckStatusResponse) {
+	interval := "disabled"
+	if res.GetSettings().GetInterval() > 0 {
+		interval = (time.Duration(res.GetSettings().GetInterval()) * time.Second).String()
+	}
+
+	ioRateLimit := "unlimited"
+	if res.GetSettings().GetIoRateLimit() > 0 {
+		ioRateLimit = fmt.Sprintf("%d bytes/s", res.GetSettings().GetIoRateLimit())
+	}
+
+	fmt.Fprintf(out, "corruption checker interval: %s, IO rate limit: %s\n", interval, ioRateLimit)
+
+	c.PrintTable(
+		out,
+		[]string{"Database", "Enabled", "Degraded", "Quarantined", "Last Scan", "Txs", "Entries", "Mismatches", "Running Scan"},
+		len(res.Databases),
+		func(i int) []string {
+			status := res.Databases[i]
+			scan := status.GetLastScan()
+
+			row := []string{
+				status.Database,
+				fmt.Sprintf("%v", status.Enabled),
+				fmt.Sprintf("%v", status.Degraded),
+				fmt.Sprintf("%v", status.Quarantined),
+				"never",
+				"-",
+				"-",
+				"-",
+				"-",
+			}
+
+			if scan != nil {
+				row[4] = fmt.Sprintf("%s - %s",
+					time.Unix(scan.StartedAt, 0).UTC().Format(time.RFC3339),
+					time.Unix(scan.EndedAt, 0).UTC().Format(time.RFC3339))
+				row[5] = fmt.Sprintf("%d", scan.TxCount)
+				row[6] = fmt.Sprintf("%d", scan.EntriesCount)
+				row[7] = formatMismatches(scan.FailedTxs)
+			}
+
+			if running := status.GetRunningScan(); running != nil {
+				row[8] = fmt.Sprintf("tx %d/%d", running.LastTx, running.TargetTx)
+			}
+
+			return row
+		},
+		fmt.Sprintf("%d database(s)", len(res.Databases)),
+	)
+}
+
+func printScrubResult(out io.Writer, res *schema.ScrubResponse) {
+	fmt.Fprintf(out, "database '%s' scrubbed: %d transactions verified\n", res.Database, res.TxCount)
+
+	if len(res.RepairedTxs) > 0 {
+		fmt.Fprintf(out, "%d entries repaired in %s\n", res.RepairedEntries, formatTxs(res.RepairedTxs))
+	}
+
+	if len(res.UnrepairedTxs) > 0 {
+		c.PrintfColorW(out, c.Red, "%s could not be repaired, the database is still damaged\n", formatTxs(res.UnrepairedTxs))
+	}
+}
+
+func formatTxs(txs []uint64) string {
+	ids := make([]string, len(txs))
+	for i, tx := range txs {
+		ids[i] = fmt.Sprintf("%d", tx)
+	}
+
+	return fmt.Sprintf("%d transaction(s) (tx %s)", len(txs), strings.Join(ids, ", "))
+}
+
+func formatMismatches(failedTxs []uint64) string {
+	if len(failedTxs) == 0 {
+		return "0"
+	}
+
+	txs := make([]string, len(failedTxs))
+	for i, tx := range failedTxs {
+		txs[i] = fmt.Sprintf("%d", tx)
+	}
+
+	return fmt.Sprintf("%d (tx %s)", len(failedTxs), strings.Join(txs, ", "))
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintCorruptionCheckStatus(t *testing.T) {
+	var b bytes.Buffer
+
+	printCorruptionCheckStatus(&b, &schema.CorruptionCheckStatusResponse{
+		Settings: &schema.CorruptionCheckerSettings{Interval: 3600, IoRateLimit: 1024},
+		Databases: []*schema.CorruptionCheckDatabaseStatus{
+			{
+				Database:    "db1",
+				Enabled:     true,
+				Degraded:    true,
+				Quarantined: true,
+				LastScan: &schema.CorruptionCheckScan{
+					StartedAt:    1630490400,
+					EndedAt:      1630490460,
+					FirstTx:      1,
+					LastTx:       10,
+					TargetTx:     10,
+					TxCount:      10,
+					EntriesCount: 25,
+					FailedTxs:    []uint64{4, 7},
+				},
+				RunningScan: &schema.CorruptionCheckScan{LastTx: 40, TargetTx: 100},
+			},
+			{Database: "db2"},
+		},
+	})
+
+	out := b.String()
+	require.Contains(t, out, "interval: 1h0m0s, IO rate limit: 1024 bytes/s")
+	require.Contains(t, out, "2021-09-01T10:00:00Z - 2021-09-01T10:01:00Z")
+	require.Contains(t, out, "2 (tx 4, 7)")
+	require.Contains(t, out, "tx 40/100")
+	require.Contains(t, out, "never")
+	require.Contains(t, out, "2 database(s)")
+
+	b.Reset()
+	printCorruptionCheckStatus(&b, &schema.CorruptionCheckStatusResponse{Settings: &schema.CorruptionCheckerSettings{}})
+	require.Contains(t, b.String(), "interval: disabled, IO rate limit: unlimited")
+}
+
+func TestPrintScrubResult(t *testing.T) {
+	var b bytes.Buffer
+
+	printScrubResult(&b, &schema.ScrubResponse{Database: "db1", TxCount: 10})
+	require.Equal(t, "database 'db1' scrubbed: 10 transactions verified\n", b.String())
+
+	b.Reset()
+	printScrubResult(&b, &schema.ScrubResponse{
+		Database:        "db1",
+		TxCount:         10,
+		RepairedTxs:     []uint64{3, 5},
+		RepairedEntries: 4,
+		UnrepairedTxs:   []uint64{8},
+	})
+	require.Contains(t, b.String(), "4 entries repaired in 2 transaction(s) (tx 3, 5)")
+	require.Contains(t, b.String(), "1 transaction(s) (tx 8) could not be repaired")
+}
@@ -17,11 +17,17 @@ limitations under the License.
 package immuadmin
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	c "github.com/codenotary/immudb/cmd/helper"
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -32,7 +38,7 @@ func (cl *commandline) database(cmd *cobra.Command) {
 		Aliases: []string{"d"},
 		//PersistentPreRunE: cl.ConfigChain(cl.connect),
 		PersistentPostRun: cl.disconnect,
-		ValidArgs:         []string{"list", "create", "use", "clean"},
+		ValidArgs:         []string{"list", "create", "delete", "use", "clean"},
 	}
 
 	ccd := &cobra.Command{
@@ -97,33 +103,165 @@ func (cl *commandline) database(cmd *cobra.Command) {
 
 	cu := &cobra.Command{
 		Use:               "update",
-		Short:             "Update database",
+		Short:             "Update database settings, only the provided ones are changed",
 		PersistentPreRunE: cl.ConfigChain(cl.connect),
 		PersistentPostRun: cl.disconnect,
-		Example:           "update {database_name}",
+		Example:           "update {database_name} [--replica] [--corruption-checker] [--read-only] [--paranoid-reads] [--max-disk-size 1073741824] [--max-concurrency 30] ...",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			isReplica, err := cmd.Flags().GetBool("replica")
+			settings, err := prepareDatabaseNullableSettings(cmd.Flags())
 			if err != nil {
 				return err
 			}
 
-			if isReplica {
+			if settings.GetReplicationSettings().GetReplica().GetValue() {
 				c.PrintfColorW(cmd.OutOrStdout(), c.Yellow, "Replication is a work-in-progress feature. Not ready for production use\n")
 			}
 
-			if err := cl.immuClient.UpdateDatabase(cl.context, &schema.DatabaseSettings{
+			res, err := cl.immuClient.UpdateDatabaseSettings(cl.context, &schema.UpdateDatabaseSettingsRequest{
 				DatabaseName: args[0],
-				Replica:      isReplica,
-			}); err != nil {
+				Settings:     settings,
+			})
+			if err != nil {
 				return err
 			}
 
-			fmt.Fprintf(cmd.OutOrStdout(), "database '%s' (replica = %v) successfully updated\n", args[0], isReplica)
+			fmt.Fprintf(cmd.OutOrStdout(), "database '%s' successfully updated\n", args[0])
+			fmt.Fprintf(cmd.OutOrStdout(), "store settings will take effect the next time the database is loaded\n")
+
+			printDatabaseSettings(cmd.OutOrStdout(), res.Settings)
+
 			return nil
 		},
 		Args: cobra.ExactArgs(1),
 	}
 	cu.Flags().BoolP("replica", "r", false, "set database as a replica")
+	cu.Flags().String("src-database", "", "source database name")
+	cu.Flags().String("src-address", "", "source database address")
+	cu.Flags().Uint32("src-port", 0, "source database port")
+	cu.Flags().String("follower-username", "", "username used by the replica to connect to the source database")
+	cu.Flags().String("follower-password", "", "password used by the replica to connect to the source database")
+	cu.Flags().String("src-tls-certificate", "", "client certificate presented by the replica to the source database")
+	cu.Flags().String("src-tls-pkey", "", "client private key of the replica")
+	cu.Flags().String("src-tls-ca", "", "certificate authority used by the replica to verify the source database")
+	cu.Flags().String("src-tls-server-name", "", "name expected in the certificate of the source database (defaults to its address)")
+	cu.Flags().Uint32("sync-acks", 0, "number of replicas, not restricted by a replica filter, which must confirm a transaction before its commit is acknowledged (0 for asynchronous replication)")
+	cu.Flags().Uint32("sync-timeout", 0, "maximum time in milliseconds a commit waits for the replicas to confirm it (0 for the default)")
+	cu.Flags().String("sync-fallback", "", "behaviour once the replicas timed out: async (acknowledge anyway) or fail (report the commit as failed)")
+	cu.Flags().Uint32("max-replica-tx-rate", 0, "maximum number of transactions per second exported to each replica (0 for unlimited)")
+	cu.Flags().Uint64("max-replica-byte-rate", 0, "maximum number of bytes per second exported to each replica (0 for unlimited)")
+	cu.Flags().Uint32("max-replication-tx-rate", 0, "maximum number of transactions per second exported to all the replicas together (0 for unlimited)")
+	cu.Flags().Uint64("max-replication-byte-rate", 0, "maximum number of bytes per second exported to all the replicas together (0 for unlimited)")
+	cu.Flags().StringArray("replica-filter", nil, "only export to the replica the keys under the given prefixes, as replica_id=prefix1,prefix2 (repeatable, replaces the configured filters, an empty value removes them all)")
+	cu.Flags().Bool("partial", false, "the replica holds only the keys its source filters for it, applying them as its own transactions")
+	cu.Flags().Bool("auto-failover", false, "promote the replica once its source database failed the probes of the failover supervisor")
+	cu.Flags().Bool("corruption-checker", false, "enable the corruption checker for this database")
+	cu.Flags().Bool("read-only", false, "reject writes while still serving reads and proofs")
+	cu.Flags().Bool("paranoid-reads", false, "verify every entry returned by get and scan against the database state, at a latency cost")
+	cu.Flags().Uint64("max-disk-size", 0, "max on-disk size in bytes, writes are rejected once reached (0 means no limit)")
+	cu.Flags().Bool("synced", true, "synced mode, data is flushed to disk on every commit")
+	cu.Flags().Uint32("max-concurrency", 0, "max number of concurrent writes")
+	cu.Flags().Uint32("max-io-concurrency", 0, "max number of concurrent io operations")
+	cu.Flags().Uint32("tx-log-cache-size", 0, "size of the transaction log cache")
+	cu.Flags().Uint32("vlog-max-opened-files", 0, "max number of opened value log files")
+	cu.Flags().Uint32("txlog-max-opened-files", 0, "max number of opened transaction log files")
+	cu.Flags().Uint32("commitlog-max-opened-files", 0, "max number of opened commit log files")
+	cu.Flags().Uint32("index-cache-size", 0, "size of the index cache")
+	cu.Flags().Uint32("index-flush-threshold", 0, "number of new index entries between disk flushes")
+	cu.Flags().Uint32("index-max-active-snapshots", 0, "max number of active index snapshots")
+	cu.Flags().Uint32("index-compaction-threshold", 0, "number of new index dump files before compaction can be triggered")
+
+	cdel := &cobra.Command{
+		Use:               "delete",
+		Short:             "Delete a database, removing or archiving its data",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		Example:           "delete {database_name} [--archive] [--yes]",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			archive, err := cmd.Flags().GetBool("archive")
+			if err != nil {
+				return err
+			}
+
+			confirmed, err := cmd.Flags().GetBool("yes")
+			if err != nil {
+				return err
+			}
+
+			if !confirmed {
+				if archive {
+					fmt.Fprintf(cmd.OutOrStdout(), "Database '%s' will be unloaded and its data archived.\n", args[0])
+				} else {
+					fmt.Fprintf(cmd.OutOrStdout(), "Database '%s' will be unloaded and its data permanently removed.\n", args[0])
+				}
+				fmt.Fprint(cmd.OutOrStdout(), "Are you sure you want to proceed? [y/N]: ")
+
+				answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+				if strings.ToUpper(strings.TrimSpace(answer)) != "Y" {
+					return errors.New("Canceled")
+				}
+			}
+
+			if err := cl.immuClient.DeleteDatabase(cl.context, &schema.DeleteDatabaseRequest{
+				DatabaseName: args[0],
+				Archive:      archive,
+			}); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "database '%s' successfully deleted\n", args[0])
+			return nil
+		},
+		Args: cobra.ExactArgs(1),
+	}
+	cdel.Flags().BoolP("archive", "a", false, "archive database data instead of removing it")
+	cdel.Flags().BoolP("yes", "y", false, "do not ask for confirmation")
+
+	ccl := &cobra.Command{
+		Use:               "clone",
+		Short:             "Create a new database as a server-side copy of an existing one",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		Example:           "clone {source_database_name} {database_name} [--until-tx 100]",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			untilTx, err := cmd.Flags().GetUint64("until-tx")
+			if err != nil {
+				return err
+			}
+
+			res, err := cl.immuClient.CloneDatabase(cl.context, &schema.CloneDatabaseRequest{
+				SrcDatabase: args[0],
+				DstDatabase: args[1],
+				UntilTx:     untilTx,
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "database '%s' successfully cloned from '%s' up to tx %d\n", res.DatabaseName, args[0], res.TxId)
+			return nil
+		},
+		Args: cobra.ExactArgs(2),
+	}
+	ccl.Flags().Uint64("until-tx", 0, "last transaction to be cloned (0 means up to the latest one)")
+
+	crs := &cobra.Command{
+		Use:               "replicas",
+		Short:             "Show the replication progress of each replica of the selected database",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		Example:           "replicas",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, err := cl.immuClient.ReplicationStatus(cl.context)
+			if err != nil {
+				return err
+			}
+
+			printReplicationStatus(cmd.OutOrStdout(), res)
+
+			return nil
+		},
+		Args: cobra.ExactArgs(0),
+	}
 
 	ccu := &cobra.Command{
 		Use:               "use command",
@@ -175,10 +313,439 @@ func (cl *commandline) database(cmd *cobra.Command) {
 		Args: cobra.ExactArgs(0),
 	}
 
+	crp := &cobra.Command{
+		Use:               "replication",
+		Short:             "Switch a database between primary and replica or change its source database, fencing ongoing writes",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		Example:           "replication {database_name} [--replica --src-address 10.0.0.1 --src-port 3322 --src-database db1] [--expected-epoch 2] [--min-tx 100]",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req, err := prepareUpdateReplicationRequest(args[0], cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			if req.Replica {
+				c.PrintfColorW(cmd.OutOrStdout(), c.Yellow, "Replication is a work-in-progress feature. Not ready for production use\n")
+			}
+
+			res, err := cl.immuClient.UpdateReplication(cl.context, req)
+			if err != nil {
+				return err
+			}
+
+			role := "primary"
+			if res.Replica {
+				role = "replica"
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "database '%s' is now a %s as of tx %d (replication epoch %d)\n", res.DatabaseName, role, res.TxId, res.Epoch)
+			return nil
+		},
+		Args: cobra.ExactArgs(1),
+	}
+	crp.Flags().BoolP("replica", "r", false, "switch the database to a replica, otherwise it becomes a primary")
+	crp.Flags().String("src-database", "", "source database name (unchanged if not provided)")
+	crp.Flags().String("src-address", "", "source database address (unchanged if not provided)")
+	crp.Flags().Uint32("src-port", 0, "source database port (unchanged if not provided)")
+	crp.Flags().String("follower-username", "", "username used by the replica to connect to the source database (unchanged if not provided)")
+	crp.Flags().String("follower-password", "", "password used by the replica to connect to the source database (unchanged if not provided)")
+	crp.Flags().Uint64("expected-epoch", 0, "only switch if the replication epoch of the database is still the given one")
+	crp.Flags().Uint64("min-tx", 0, "only promote the replica if it already holds the given transaction")
+
+	cra := &cobra.Command{
+		Use:               "replication-audit",
+		Short:             "Show the audit trail of the replication changes of the selected database, or of the given one",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		Example:           "replication-audit [database_name] [--limit 10] [--desc]",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &schema.ReplicationAuditRequest{}
+
+			if len(args) > 0 {
+				req.Database = args[0]
+			}
+
+			var err error
+
+			req.Offset, err = cmd.Flags().GetUint64("offset")
+			if err != nil {
+				return err
+			}
+
+			req.Limit, err = cmd.Flags().GetInt32("limit")
+			if err != nil {
+				return err
+			}
+
+			req.Desc, err = cmd.Flags().GetBool("desc")
+			if err != nil {
+				return err
+			}
+
+			res, err := cl.immuClient.ReplicationAudit(cl.context, req)
+			if err != nil {
+				return err
+			}
+
+			printReplicationAudit(cmd.OutOrStdout(), res)
+
+			return nil
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+	cra.Flags().Uint64("offset", 0, "number of entries to skip")
+	cra.Flags().Int32("limit", 0, "maximum number of entries to show (0 for the server default)")
+	cra.Flags().Bool("desc", false, "show the most recent entries first")
+
+	css := &cobra.Command{
+		Use:               "sync-snapshot",
+		Short:             "Bring a replica in sync with its primary through a snapshot of the transactions it misses",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		Example:           "sync-snapshot {database_name} [--until-tx 100]",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			untilTx, err := cmd.Flags().GetUint64("until-tx")
+			if err != nil {
+				return err
+			}
+
+			res, err := cl.immuClient.SyncSnapshot(cl.context, &schema.SyncSnapshotRequest{
+				DatabaseName: args[0],
+				UntilTx:      untilTx,
+			})
+			if err != nil {
+				return err
+			}
+
+			if res.TxCount == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "replica '%s' already in sync up to tx %d\n", res.DatabaseName, res.TxId)
+				return nil
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "replica '%s' synced from tx %d up to tx %d, %d bytes transferred\n", res.DatabaseName, res.FromTxId, res.TxId, res.TransferredBytes)
+			return nil
+		},
+		Args: cobra.ExactArgs(1),
+	}
+	css.Flags().Uint64("until-tx", 0, "last transaction to be synced (0 means up to the latest one of the primary)")
+
 	ccmd.AddCommand(ccc)
 	ccmd.AddCommand(ccu)
 	ccmd.AddCommand(ccd)
 	ccmd.AddCommand(cc)
 	ccmd.AddCommand(cu)
+	ccmd.AddCommand(cdel)
+	ccmd.AddCommand(ccl)
+	ccmd.AddCommand(crs)
+	ccmd.AddCommand(crp)
+	ccmd.AddCommand(cra)
+	ccmd.AddCommand(css)
 	cmd.AddCommand(ccmd)
 }
+
+// prepareDatabaseNullableSettings sets only the settings whose flags were explicitly provided
+func prepareDatabaseNullableSettings(flags *pflag.FlagSet) (*schema.DatabaseNullableSettings, error) {
+	var err error
+
+	condBool := func(name string) *schema.NullableBool {
+		if err != nil || !flags.Changed(name) {
+			return nil
+		}
+		var v bool
+		v, err = flags.GetBool(name)
+		return &schema.NullableBool{Value: v}
+	}
+
+	condString := func(name string) *schema.NullableString {
+		if err != nil || !flags.Changed(name) {
+			return nil
+		}
+		var v string
+		v, err = flags.GetString(name)
+		return &schema.NullableString{Value: v}
+	}
+
+	condUInt32 := func(name string) *schema.NullableUint32 {
+		if err != nil || !flags.Changed(name) {
+			return nil
+		}
+		var v uint32
+		v, err = flags.GetUint32(name)
+		return &schema.NullableUint32{Value: v}
+	}
+
+	condUInt64 := func(name string) *schema.NullableUint64 {
+		if err != nil || !flags.Changed(name) {
+			return nil
+		}
+		var v uint64
+		v, err = flags.GetUint64(name)
+		return &schema.NullableUint64{Value: v}
+	}
+
+	settings := &schema.DatabaseNullableSettings{
+		CorruptionChecker: condBool("corruption-checker"),
+		ReplicationSettings: &schema.ReplicationNullableSettings{
+			Replica:                condBool("replica"),
+			SrcDatabase:            condString("src-database"),
+			SrcAddress:             condString("src-address"),
+			SrcPort:                condUInt32("src-port"),
+			FollowerUsr:            condString("follower-username"),
+			FollowerPwd:            condString("follower-password"),
+			SyncAcks:               condUInt32("sync-acks"),
+			SyncTimeout:            condUInt32("sync-timeout"),
+			SyncFallback:           condString("sync-fallback"),
+			MaxReplicaTxRate:       condUInt32("max-replica-tx-rate"),
+			MaxReplicaByteRate:     condUInt64("max-replica-byte-rate"),
+			MaxReplicationTxRate:   condUInt32("max-replication-tx-rate"),
+			MaxReplicationByteRate: condUInt64("max-replication-byte-rate"),
+			Partial:                condBool("partial"),
+			TlsCert:                condString("src-tls-certificate"),
+			TlsKey:                 condString("src-tls-pkey"),
+			TlsCa:                  condString("src-tls-ca"),
+			TlsServerName:          condString("src-tls-server-name"),
+			AutoFailover:           condBool("auto-failover"),
+		},
+		Synced:                  condBool("synced"),
+		MaxConcurrency:          condUInt32("max-concurrency"),
+		MaxIOConcurrency:        condUInt32("max-io-concurrency"),
+		TxLogCacheSize:          condUInt32("tx-log-cache-size"),
+		VLogMaxOpenedFiles:      condUInt32("vlog-max-opened-files"),
+		TxLogMaxOpenedFiles:     condUInt32("txlog-max-opened-files"),
+		CommitLogMaxOpenedFiles: condUInt32("commitlog-max-opened-files"),
+		IndexSettings: &schema.IndexNullableSettings{
+			CacheSize:          condUInt32("index-cache-size"),
+			FlushThld:          condUInt32("index-flush-threshold"),
+			MaxActiveSnapshots: condUInt32("index-max-active-snapshots"),
+			CompactionThld:     condUInt32("index-compaction-threshold"),
+		},
+		MaxDiskSize:   condUInt64("max-disk-size"),
+		ReadOnly:      condBool("read-only"),
+		ParanoidReads: condBool("paranoid-reads"),
+	}
+
+	if err == nil && flags.Changed("replica-filter") {
+		var filters []string
+
+		filters, err = flags.GetStringArray("replica-filter")
+		if err == nil {
+			settings.ReplicationSettings.ReplicaFilters, err = parseReplicaFilters(filters)
+		}
+	}
+
+	return settings, err
+}
+
+func prepareUpdateReplicationRequest(databaseName string, flags *pflag.FlagSet) (*schema.UpdateReplicationRequest, error) {
+	req := &schema.UpdateReplicationRequest{DatabaseName: databaseName}
+
+	var err error
+
+	if req.Replica, err = flags.GetBool("replica"); err != nil {
+		return nil, err
+	}
+	if req.SrcDatabase, err = flags.GetString("src-database"); err != nil {
+		return nil, err
+	}
+	if req.SrcAddress, err = flags.GetString("src-address"); err != nil {
+		return nil, err
+	}
+	if req.SrcPort, err = flags.GetUint32("src-port"); err != nil {
+		return nil, err
+	}
+	if req.FollowerUsr, err = flags.GetString("follower-username"); err != nil {
+		return nil, err
+	}
+	if req.FollowerPwd, err = flags.GetString("follower-password"); err != nil {
+		return nil, err
+	}
+	if req.MinTxId, err = flags.GetUint64("min-tx"); err != nil {
+		return nil, err
+	}
+
+	if flags.Changed("expected-epoch") {
+		epoch, err := flags.GetUint64("expected-epoch")
+		if err != nil {
+			return nil, err
+		}
+		req.ExpectedEpoch = &schema.NullableUint64{Value: epoch}
+	}
+
+	return req, nil
+}
+
+// parseReplicaFilters parses filters formatted as replica_id=prefix1,prefix2, empty filters are ignored
+func parseReplicaFilters(filters []string) (*schema.ReplicaFilters, error) {
+	res := &schema.ReplicaFilters{}
+
+	for _, f := range filters {
+		if f == "" {
+			continue
+		}
+
+		parts := strings.SplitN(f, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid replica filter '%s', expected replica_id=prefix1,prefix2", f)
+		}
+
+		filter := &schema.ReplicaFilter{ReplicaId: parts[0]}
+		for _, prefix := range strings.Split(parts[1], ",") {
+			filter.KeyPrefixes = append(filter.KeyPrefixes, []byte(prefix))
+		}
+
+		res.Filters = append(res.Filters, filter)
+	}
+
+	return res, nil
+}
+
+func formatKeyPrefixes(prefixes [][]byte) string {
+	ps := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		ps[i] = string(p)
+	}
+	return strings.Join(ps, ",")
+}
+
+func printDatabaseSettings(out io.Writer, settings *schema.DatabaseNullableSettings) {
+	rs := settings.GetReplicationSettings()
+	is := settings.GetIndexSettings()
+
+	rows := [][]string{
+		{"corruption-checker", fmt.Sprintf("%v", settings.GetCorruptionChecker().GetValue())},
+		{"read-only", fmt.Sprintf("%v", settings.GetReadOnly().GetValue())},
+		{"paranoid-reads", fmt.Sprintf("%v", settings.GetParanoidReads().GetValue())},
+		{"max-disk-size", fmt.Sprintf("%d", settings.GetMaxDiskSize().GetValue())},
+		{"replica", fmt.Sprintf("%v", rs.GetReplica().GetValue())},
+		{"src-database", rs.GetSrcDatabase().GetValue()},
+		{"src-address", rs.GetSrcAddress().GetValue()},
+		{"src-port", fmt.Sprintf("%d", rs.GetSrcPort().GetValue())},
+		{"follower-username", rs.GetFollowerUsr().GetValue()},
+		{"sync-acks", fmt.Sprintf("%d", rs.GetSyncAcks().GetValue())},
+		{"sync-timeout", fmt.Sprintf("%d", rs.GetSyncTimeout().GetValue())},
+		{"sync-fallback", rs.GetSyncFallback().GetValue()},
+		{"max-replica-tx-rate", fmt.Sprintf("%d", rs.GetMaxReplicaTxRate().GetValue())},
+		{"max-replica-byte-rate", fmt.Sprintf("%d", rs.GetMaxReplicaByteRate().GetValue())},
+		{"max-replication-tx-rate", fmt.Sprintf("%d", rs.GetMaxReplicationTxRate().GetValue())},
+		{"max-replication-byte-rate", fmt.Sprintf("%d", rs.GetMaxReplicationByteRate().GetValue())},
+		{"partial", fmt.Sprintf("%v", rs.GetPartial().GetValue())},
+		{"src-tls-certificate", rs.GetTlsCert().GetValue()},
+		{"src-tls-ca", rs.GetTlsCa().GetValue()},
+		{"src-tls-server-name", rs.GetTlsServerName().GetValue()},
+		{"auto-failover", fmt.Sprintf("%v", rs.GetAutoFailover().GetValue())},
+		{"synced", fmt.Sprintf("%v", settings.GetSynced().GetValue())},
+		{"max-concurrency", fmt.Sprintf("%d", settings.GetMaxConcurrency().GetValue())},
+		{"max-io-concurrency", fmt.Sprintf("%d", settings.GetMaxIOConcurrency().GetValue())},
+		{"tx-log-cache-size", fmt.Sprintf("%d", settings.GetTxLogCacheSize().GetValue())},
+		{"vlog-max-opened-files", fmt.Sprintf("%d", settings.GetVLogMaxOpenedFiles().GetValue())},
+		{"txlog-max-opened-files", fmt.Sprintf("%d", settings.GetTxLogMaxOpenedFiles().GetValue())},
+		{"commitlog-max-opened-files", fmt.Sprintf("%d", settings.GetCommitLogMaxOpenedFiles().GetValue())},
+		{"index-cache-size", fmt.Sprintf("%d", is.GetCacheSize().GetValue())},
+		{"index-flush-threshold", fmt.Sprintf("%d", is.GetFlushThld().GetValue())},
+		{"index-max-active-snapshots", fmt.Sprintf("%d", is.GetMaxActiveSnapshots().GetValue())},
+		{"index-compaction-threshold", fmt.Sprintf("%d", is.GetCompactionThld().GetValue())},
+	}
+
+	for _, f := range rs.GetReplicaFilters().GetFilters() {
+		rows = append(rows, []string{"replica-filter", fmt.Sprintf("%s=%s", f.ReplicaId, formatKeyPrefixes(f.KeyPrefixes))})
+	}
+
+	c.PrintTable(
+		out,
+		[]string{"Setting", "Value"},
+		len(rows),
+		func(i int) []string { return rows[i] },
+		"",
+	)
+}
+
+func printReplicationAudit(out io.Writer, res *schema.ReplicationAuditList) {
+	fmt.Fprintf(out, "database '%s', %d replication audit entries\n", res.Database, len(res.Entries))
+
+	c.PrintTable(
+		out,
+		[]string{"Time", "Kind", "Actor", "Tx", "Details"},
+		len(res.Entries),
+		func(i int) []string {
+			e := res.Entries[i]
+
+			return []string{
+				time.Unix(e.Timestamp, 0).UTC().Format(time.RFC3339),
+				e.Kind,
+				e.Actor,
+				fmt.Sprintf("%d", e.TxId),
+				e.Details,
+			}
+		},
+		"",
+	)
+}
+
+func printReplicationStatus(out io.Writer, res *schema.ReplicationStatusResponse) {
+	fmt.Fprintf(out, "database '%s' at tx %d, %d replicas\n", res.Database, res.TxId, len(res.Replicas))
+
+	if res.ReplicatedTxId > 0 {
+		fmt.Fprintf(out, "replicated up to tx %d of its source\n", res.ReplicatedTxId)
+	}
+
+	if d := res.Divergence; d != nil {
+		fmt.Fprintf(out, "replication stopped: at tx %d, diverges from its primary, %s, at tx %d\n", d.ReplicaTxId, d.Primary, d.PrimaryTxId)
+		if d.TxId > 0 {
+			fmt.Fprintf(out, "first divergent tx %d: hash %x on the replica, %x on the primary\n", d.TxId, d.ReplicaTxHash, d.PrimaryTxHash)
+		}
+	}
+
+	if s := res.Summary; s != nil && s.Depth > 1 {
+		fmt.Fprintf(out, "%d replicas over %d levels, max lag %d, %d diverged\n", s.Replicas, s.Depth, s.MaxLag, s.Diverged)
+	}
+
+	// downstream replicas are listed below the replica feeding them
+	var replicas []*schema.ReplicaStatus
+	var names []string
+
+	var walk func(rs []*schema.ReplicaStatus, indent string)
+	walk = func(rs []*schema.ReplicaStatus, indent string) {
+		for _, r := range rs {
+			replicas = append(replicas, r)
+			names = append(names, indent+r.ReplicaId)
+			walk(r.Downstream, indent+"  ")
+		}
+	}
+	walk(res.Replicas, "")
+
+	for _, r := range replicas {
+		if d := r.Divergence; d != nil {
+			fmt.Fprintf(out, "replication to '%s' stopped: tx %d confirmed with hash %x instead of %x\n", r.ReplicaId, d.TxId, d.ReplicaTxHash, d.PrimaryTxHash)
+		}
+	}
+
+	c.PrintTable(
+		out,
+		[]string{"Replica", "Exported Tx", "Confirmed Tx", "Lag", "Last Seen", "Key Prefixes"},
+		len(replicas),
+		func(i int) []string {
+			r := replicas[i]
+
+			lastSeen := "never"
+			if r.LastSeenAt > 0 {
+				lastSeen = time.Unix(r.LastSeenAt, 0).UTC().Format(time.RFC3339)
+			}
+
+			keyPrefixes := "all"
+			if len(r.KeyPrefixes) > 0 {
+				keyPrefixes = formatKeyPrefixes(r.KeyPrefixes)
+			}
+
+			return []string{
+				names[i],
+				fmt.Sprintf("%d", r.ExportedTxId),
+				fmt.Sprintf("%d", r.ConfirmedTxId),
+				fmt.Sprintf("%d", r.Lag),
+				lastSeen,
+				keyPrefixes,
+			}
+		},
+		"",
+	)
+}
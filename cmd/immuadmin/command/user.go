@@ -58,6 +58,7 @@ func (cl *commandline) user(cmd *cobra.Command) {
 		Long:  "Create a new user inside a database with permissions",
 		Example: `immuadmin user create user1 read mydb
 immuadmin user create user1 readwrite mydb
+immuadmin user create replica1 replication mydb
 immuadmin user create user1 admin mydb`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			resp, err := cl.userCreate(args)
@@ -115,7 +116,7 @@ immuadmin user create user1 admin mydb`,
 		Args: cobra.ExactArgs(1),
 	}
 	userPermission := &cobra.Command{
-		Use:     "permission [grant|revoke] {username} [read|readwrite|admin] {database}",
+		Use:     "permission [grant|revoke] {username} [read|readwrite|replication|admin] {database}",
 		Short:   "Set user permission",
 		Example: "immuadmin user permission grant user1 readwrite mydb",
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
@@ -226,6 +227,8 @@ func permissionToString(permission uint32) string {
 		return fmt.Sprintf("Read")
 	case auth.PermissionRW:
 		return fmt.Sprintf("Read/Write")
+	case auth.PermissionReplication:
+		return fmt.Sprintf("Replication")
 	default:
 		return fmt.Sprintf("unknown: %d", permission)
 	}
@@ -357,9 +360,11 @@ func permissionFromString(permissionStr string) (uint32, error) {
 		permission = auth.PermissionAdmin
 	case "readwrite":
 		permission = auth.PermissionRW
+	case "replication":
+		permission = auth.PermissionReplication
 	default:
 		return 0, fmt.Errorf(
-			"Permission %s not recognized: allowed permissions are read, readwrite, admin",
+			"Permission %s not recognized: allowed permissions are read, readwrite, replication, admin",
 			permissionStr)
 	}
 	return permission, nil
@@ -98,6 +98,7 @@ func (cAgent *auditAgent) InitAgent() (AuditAgent, error) {
 	auditNotificationURL := viper.GetString("audit-notification-url")
 	auditNotificationUsername := viper.GetString("audit-notification-username")
 	auditNotificationPassword := viper.GetString("audit-notification-password")
+	auditAlertURL := viper.GetString("audit-alert-url")
 	if len(auditUsername) > 0 || len(auditPassword) > 0 {
 		if _, err = cAgent.immuc.Login(ctx, []byte(auditUsername), []byte(auditPassword)); err != nil {
 			return nil, fmt.Errorf("Invalid login operation: %v", err)
@@ -125,6 +126,7 @@ func (cAgent *auditAgent) InitAgent() (AuditAgent, error) {
 		pk,
 		auditor.AuditNotificationConfig{
 			URL:            auditNotificationURL,
+			AlertURL:       auditAlertURL,
 			Username:       auditNotificationUsername,
 			Password:       auditNotificationPassword,
 			RequestTimeout: time.Duration(5) * time.Second,
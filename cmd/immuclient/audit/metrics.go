@@ -48,16 +48,25 @@ var (
 		"audit_prev_root_per_server",
 		"Previous root index used for the latest audit.",
 	)
+	AuditTamperingPerServer = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "audit_tampering_detected_per_server",
+			Help:      "Number of audits which detected tampering, meant to be alerted on when it increases.",
+		},
+		[]string{"server_id", "server_address"},
+	)
 )
 
 func (p *prometheusMetrics) init(serverid string, immudbAddress, immudbPort string) {
 	p.server_address = fmt.Sprintf("%s:%s", immudbAddress, immudbPort)
 	p.server_id = serverid
-	prometheus.MustRegister(AuditResultPerServer, AuditCurrRootPerServer, AuditRunAtPerServer, AuditPrevRootPerServer)
+	prometheus.MustRegister(AuditResultPerServer, AuditCurrRootPerServer, AuditRunAtPerServer, AuditPrevRootPerServer, AuditTamperingPerServer)
 	AuditResultPerServer.WithLabelValues(p.server_id, p.server_address).Set(-1)
 	AuditCurrRootPerServer.WithLabelValues(p.server_id, p.server_address).Set(-1)
 	AuditRunAtPerServer.WithLabelValues(p.server_id, p.server_address).SetToCurrentTime()
 	AuditPrevRootPerServer.WithLabelValues(p.server_id, p.server_address).Set(-1)
+	AuditTamperingPerServer.WithLabelValues(p.server_id, p.server_address).Add(0)
 }
 
 func newAuditGaugeVec(name string, help string) *prometheus.GaugeVec {
@@ -109,4 +118,8 @@ func (p *prometheusMetrics) updateMetrics(
 		WithLabelValues(p.server_id, p.server_address).Set(currRootTxID)
 	AuditRunAtPerServer.
 		WithLabelValues(p.server_id, p.server_address).SetToCurrentTime()
+	if checked && !result {
+		AuditTamperingPerServer.
+			WithLabelValues(p.server_id, p.server_address).Inc()
+	}
 }
@@ -18,6 +18,8 @@ package audit
 
 import (
 	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestMetrics(t *testing.T) {
@@ -28,3 +30,17 @@ func TestMetrics(t *testing.T) {
 		t.Fatal("fail prometheus init")
 	}
 }
+
+func TestMetricsTampering(t *testing.T) {
+	p := prometheusMetrics{server_id: "serverid2", server_address: "localhost:12345"}
+
+	p.updateMetrics("serverid2", "localhost:12345", true, false, true, nil, nil)
+	if testutil.ToFloat64(AuditTamperingPerServer.WithLabelValues(p.server_id, p.server_address)) != 0 {
+		t.Fatal("tampering counted for a consistent audit")
+	}
+
+	p.updateMetrics("serverid2", "localhost:12345", true, false, false, nil, nil)
+	if testutil.ToFloat64(AuditTamperingPerServer.WithLabelValues(p.server_id, p.server_address)) != 1 {
+		t.Fatal("tampering not counted")
+	}
+}
@@ -19,3 +19,11 @@ package cli
 func (cli *cli) currentState(args []string) (string, error) {
 	return cli.immucl.CurrentState(args)
 }
+
+func (cli *cli) complianceReport(args []string) (string, error) {
+	return cli.immucl.ComplianceReport(args)
+}
+
+func (cli *cli) verifyRange(args []string) (string, error) {
+	return cli.immucl.VerifyRange(args)
+}
@@ -43,13 +43,15 @@ func (cli *cli) initCommands() {
 
 	// Current status commands
 	cli.Register(&command{"current", "Return the last tx and hash stored locally", cli.currentState, nil, false})
+	cli.Register(&command{"compliance-report", "Return the signed compliance report of the current database, optionally for the [since] [until] period", cli.complianceReport, nil, true})
+	cli.Register(&command{"verify-range", "Verify the current database now, optionally only the [since-tx] [until-tx] interval and the keys with the [prefix]", cli.verifyRange, nil, true})
 
 	// Reference commands
 	cli.Register(&command{"reference", "Add new reference to an existing key", cli.reference, []string{"refkey", "key"}, false})
 	cli.Register(&command{"safereference", "Add and verify new reference to an existing key", cli.safereference, []string{"refkey", "key"}, false})
 
 	// Scannner commands
-	cli.Register(&command{"scan", "Iterate over keys having the specified prefix", cli.scan, []string{"prefix"}, false})
+	cli.Register(&command{"scan", "Iterate over keys having the specified prefix", cli.scan, []string{"prefix"}, true})
 	cli.Register(&command{"zscan", "Iterate over a sorted set", cli.zScan, []string{"prefix"}, false})
 	cli.Register(&command{"count", "Count keys having the specified prefix", cli.count, []string{"prefix"}, false})
 
@@ -63,4 +65,6 @@ func (cli *cli) initCommands() {
 	cli.Register(&command{"query", "Query sql statement", cli.sqlQuery, []string{"statement"}, true})
 	cli.Register(&command{"describe", "Describe table", cli.describeTable, []string{"table"}, false})
 	cli.Register(&command{"tables", "List tables", cli.listTables, nil, false})
+	cli.Register(&command{"dump", "Dump tables into a SQL or CSV file", cli.sqlDump, []string{"file"}, true})
+	cli.Register(&command{"load", "Load a SQL or CSV dump", cli.sqlLoad, []string{"file"}, false})
 }
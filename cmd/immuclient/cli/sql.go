@@ -32,6 +32,14 @@ func (cli *cli) listTables(args []string) (string, error) {
 	return cli.immucl.ListTables()
 }
 
+func (cli *cli) sqlDump(args []string) (string, error) {
+	return cli.immucl.SQLDump(args)
+}
+
+func (cli *cli) sqlLoad(args []string) (string, error) {
+	return cli.immucl.SQLLoad(args)
+}
+
 func (cli *cli) useDatabase(args []string) (string, error) {
 	return cli.immucl.UseDatabase(args)
 }
@@ -42,6 +42,8 @@ func (cl *commandline) Register(rootCmd *cobra.Command) *cobra.Command {
 	cl.logout(rootCmd)
 	// current status
 	cl.currentState(rootCmd)
+	cl.complianceReport(rootCmd)
+	cl.verifyRange(rootCmd)
 	// get operations
 	cl.getTxByID(rootCmd)
 	cl.safegetTxByID(rootCmd)
@@ -71,6 +73,8 @@ func (cl *commandline) Register(rootCmd *cobra.Command) *cobra.Command {
 	cl.sqlQuery(rootCmd)
 	cl.listTables(rootCmd)
 	cl.describeTable(rootCmd)
+	cl.sqlDump(rootCmd)
+	cl.sqlLoad(rootCmd)
 
 	return rootCmd
 }
@@ -39,3 +39,46 @@ func (cl *commandline) currentState(cmd *cobra.Command) {
 	}
 	cmd.AddCommand(ccmd)
 }
+
+func (cl *commandline) complianceReport(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:   "compliance-report [since] [until]",
+		Short: "Return the signed compliance report of the current database as JSON",
+		Long: `Return the signed compliance report of the current database as JSON.
+The optional period bounds are either unix timestamps or RFC3339 dates, the end of the period is excluded.`,
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := cl.immucl.ComplianceReport(args)
+			if err != nil {
+				cl.quit(err)
+			}
+			fprintln(cmd.OutOrStdout(), resp)
+			return nil
+		},
+		Args: cobra.MaximumNArgs(2),
+	}
+	cmd.AddCommand(ccmd)
+}
+
+func (cl *commandline) verifyRange(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:   "verify-range [since-tx] [until-tx] [prefix]",
+		Short: "Verify the current database now and return the verdict as JSON",
+		Long: `Verify the current database now and return the verdict as JSON.
+Only the transactions in the optional interval are verified, zero meaning an open bound,
+and when a prefix is given only the ones holding revisions of the keys with that prefix.`,
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := cl.immucl.VerifyRange(args)
+			if err != nil {
+				cl.quit(err)
+			}
+			fprintln(cmd.OutOrStdout(), resp)
+			return nil
+		},
+		Args: cobra.MaximumNArgs(3),
+	}
+	cmd.AddCommand(ccmd)
+}
@@ -42,7 +42,7 @@ func (cl *commandline) zScan(cmd *cobra.Command) {
 
 func (cl *commandline) scan(cmd *cobra.Command) {
 	ccmd := &cobra.Command{
-		Use:               "scan prefix",
+		Use:               "scan prefix [asc|desc]",
 		Short:             "Iterate over keys having the specified prefix",
 		Aliases:           []string{"scn"},
 		PersistentPreRunE: cl.ConfigChain(cl.connect),
@@ -55,7 +55,7 @@ func (cl *commandline) scan(cmd *cobra.Command) {
 			fprintln(cmd.OutOrStdout(), resp)
 			return nil
 		},
-		Args: cobra.ExactArgs(1),
+		Args: cobra.RangeArgs(1, 2),
 	}
 	cmd.AddCommand(ccmd)
 }
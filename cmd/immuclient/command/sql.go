@@ -100,3 +100,41 @@ func (cl *commandline) describeTable(cmd *cobra.Command) {
 	}
 	cmd.AddCommand(ccmd)
 }
+
+func (cl *commandline) sqlDump(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:               "dump file [tables]",
+		Short:             "Dump tables into a SQL script, or a single table into a CSV file",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := cl.immucl.SQLDump(args)
+			if err != nil {
+				cl.quit(err)
+			}
+			fprintln(cmd.OutOrStdout(), resp)
+			return nil
+		},
+		Args: cobra.MinimumNArgs(1),
+	}
+	cmd.AddCommand(ccmd)
+}
+
+func (cl *commandline) sqlLoad(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:               "load file",
+		Short:             "Load a SQL script or CSV file produced by dump",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := cl.immucl.SQLLoad(args)
+			if err != nil {
+				cl.quit(err)
+			}
+			fprintln(cmd.OutOrStdout(), resp)
+			return nil
+		},
+		Args: cobra.ExactArgs(1),
+	}
+	cmd.AddCommand(ccmd)
+}
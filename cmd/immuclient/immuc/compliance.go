@@ -0,0 +1,127 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package immuc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// ComplianceReport returns the JSON report of the current database. Optional arguments
+// are the start and the end of the period, either as unix timestamps or RFC3339 dates
+func (i *immuc) ComplianceReport(args []string) (string, error) {
+	req := &schema.ComplianceReportRequest{}
+
+	var err error
+
+	if len(args) > 0 {
+		req.SinceTime, err = parseReportTime(args[0])
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(args) > 1 {
+		req.UntilTime, err = parseReportTime(args[1])
+		if err != nil {
+			return "", err
+		}
+	}
+
+	ctx := context.Background()
+	report, err := i.Execute(func(immuClient client.ImmuClient) (interface{}, error) {
+		return immuClient.ComplianceReport(ctx, req)
+	})
+	if err != nil {
+		rpcerrors := strings.SplitAfter(err.Error(), "=")
+		if len(rpcerrors) > 1 {
+			return rpcerrors[len(rpcerrors)-1], nil
+		}
+		return "", err
+	}
+
+	b, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(report.(*schema.ComplianceReportResponse))
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// VerifyRange verifies the current database on demand and returns the verdict as JSON. Optional arguments
+// are the first and the last transaction to verify, zero meaning an open bound, and the prefix of the keys to verify
+func (i *immuc) VerifyRange(args []string) (string, error) {
+	req := &schema.VerifyRangeRequest{}
+
+	var err error
+
+	if len(args) > 0 {
+		req.SinceTx, err = strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid transaction id '%s'", args[0])
+		}
+	}
+
+	if len(args) > 1 {
+		req.UntilTx, err = strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid transaction id '%s'", args[1])
+		}
+	}
+
+	if len(args) > 2 {
+		req.Prefix = []byte(args[2])
+	}
+
+	ctx := context.Background()
+	res, err := i.Execute(func(immuClient client.ImmuClient) (interface{}, error) {
+		return immuClient.VerifyRange(ctx, req)
+	})
+	if err != nil {
+		rpcerrors := strings.SplitAfter(err.Error(), "=")
+		if len(rpcerrors) > 1 {
+			return rpcerrors[len(rpcerrors)-1], nil
+		}
+		return "", err
+	}
+
+	b, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(res.(*schema.VerifyRangeResponse))
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func parseReportTime(s string) (int64, error) {
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ts, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s': expected a unix timestamp or an RFC3339 date", s)
+	}
+
+	return t.Unix(), nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package immuc_test
+
+import (
+	"os"
+	"testing"
+
+	test "github.com/codenotary/immudb/cmd/immuclient/immuclienttest"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestComplianceReport(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	bs.Start()
+	defer bs.Stop()
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	ts := client.NewTokenService().WithTokenFileName("testTokenFile").WithHds(&test.HomedirServiceMock{})
+	ic := test.NewClientTest(&test.PasswordReader{
+		Pass: []string{"immudb"},
+	}, ts).WithOptions(client.DefaultOptions())
+	ic.Connect(bs.Dialer)
+	ic.Login("immudb")
+
+	_, err := ic.Imc.Set([]string{"key", "val"})
+	require.NoError(t, err)
+
+	msg, err := ic.Imc.ComplianceReport(nil)
+	require.NoError(t, err)
+
+	var report schema.ComplianceReportResponse
+	err = protojson.Unmarshal([]byte(msg), &report)
+	require.NoError(t, err)
+	require.Equal(t, "defaultdb", report.Db)
+	require.NotZero(t, report.TxCount)
+	require.Equal(t, report.TxCount, report.VerifiedTxCount)
+
+	msg, err = ic.Imc.ComplianceReport([]string{"0", "1970-01-02T00:00:00Z"})
+	require.NoError(t, err)
+
+	err = protojson.Unmarshal([]byte(msg), &report)
+	require.NoError(t, err)
+	require.Equal(t, int64(86400), report.UntilTime)
+	require.Zero(t, report.TxCount)
+
+	_, err = ic.Imc.ComplianceReport([]string{"yesterday"})
+	require.Error(t, err)
+}
+
+func TestVerifyRange(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	bs.Start()
+	defer bs.Stop()
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	ts := client.NewTokenService().WithTokenFileName("testTokenFile").WithHds(&test.HomedirServiceMock{})
+	ic := test.NewClientTest(&test.PasswordReader{
+		Pass: []string{"immudb"},
+	}, ts).WithOptions(client.DefaultOptions())
+	ic.Connect(bs.Dialer)
+	ic.Login("immudb")
+
+	_, err := ic.Imc.Set([]string{"key", "val"})
+	require.NoError(t, err)
+
+	msg, err := ic.Imc.VerifyRange(nil)
+	require.NoError(t, err)
+
+	var res schema.VerifyRangeResponse
+	err = protojson.Unmarshal([]byte(msg), &res)
+	require.NoError(t, err)
+	require.Equal(t, "defaultdb", res.Db)
+	require.True(t, res.Verified)
+	require.NotZero(t, res.TxCount)
+
+	msg, err = ic.Imc.VerifyRange([]string{"0", "0", "key"})
+	require.NoError(t, err)
+
+	err = protojson.Unmarshal([]byte(msg), &res)
+	require.NoError(t, err)
+	require.True(t, res.Verified)
+	require.Equal(t, uint64(1), res.KeyCount)
+	require.Equal(t, uint64(1), res.TxCount)
+
+	_, err = ic.Imc.VerifyRange([]string{"first"})
+	require.Error(t, err)
+}
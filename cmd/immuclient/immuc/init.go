@@ -43,6 +43,8 @@ type Client interface {
 	Execute(f func(immuClient client.ImmuClient) (interface{}, error)) (interface{}, error)
 	HealthCheck(args []string) (string, error)
 	CurrentState(args []string) (string, error)
+	ComplianceReport(args []string) (string, error)
+	VerifyRange(args []string) (string, error)
 	GetTxByID(args []string) (string, error)
 	VerifiedGetTxByID(args []string) (string, error)
 	Get(args []string) (string, error)
@@ -73,6 +75,8 @@ type Client interface {
 	SQLQuery(args []string) (string, error)
 	ListTables() (string, error)
 	DescribeTable(args []string) (string, error)
+	SQLDump(args []string) (string, error)
+	SQLLoad(args []string) (string, error)
 }
 
 // Init ...
@@ -121,8 +121,10 @@ func (i *immuc) UserCreate(args []string) (string, error) {
 		userpermission = auth.PermissionAdmin
 	case "readwrite":
 		userpermission = auth.PermissionRW
+	case "replication":
+		userpermission = auth.PermissionReplication
 	default:
-		return "permission value not recognized. Allowed permissions are read, readwrite, admin", nil
+		return "permission value not recognized. Allowed permissions are read, readwrite, replication, admin", nil
 	}
 	_, err = i.Execute(func(immuClient client.ImmuClient) (interface{}, error) {
 		return nil, immuClient.CreateUser(
@@ -157,6 +159,8 @@ func (i *immuc) UserList(args []string) (string, error) {
 				ris += "Read\n"
 			case auth.PermissionRW:
 				ris += "Read/Write\n"
+			case auth.PermissionReplication:
+				ris += "Replication\n"
 			default:
 				return "permission value not recognized. Allowed permissions are read, write, admin", nil
 			}
@@ -240,8 +244,10 @@ func (i *immuc) SetUserPermission(args []string) (string, error) {
 		userpermission = auth.PermissionAdmin
 	case "readwrite":
 		userpermission = auth.PermissionRW
+	case "replication":
+		userpermission = auth.PermissionReplication
 	default:
-		return "permission value not recognized. Allowed permissions are read, readwrite, admin", nil
+		return "permission value not recognized. Allowed permissions are read, readwrite, replication, admin", nil
 	}
 
 	dbname := args[3]
@@ -63,10 +63,21 @@ func (i *immuc) ZScan(args []string) (string, error) {
 func (i *immuc) Scan(args []string) (res string, err error) {
 	prefix := []byte(args[0])
 
+	desc := false
+	if len(args) > 1 {
+		switch args[1] {
+		case "asc":
+		case "desc":
+			desc = true
+		default:
+			return "", fmt.Errorf("invalid scan order %s, expected asc or desc", args[1])
+		}
+	}
+
 	ctx := context.Background()
 
 	response, err := i.Execute(func(immuClient client.ImmuClient) (interface{}, error) {
-		return immuClient.Scan(ctx, &schema.ScanRequest{Prefix: prefix, SinceTx: math.MaxUint64, NoWait: true})
+		return immuClient.Scan(ctx, &schema.ScanRequest{Prefix: prefix, SinceTx: math.MaxUint64, NoWait: true, Desc: desc})
 	})
 	if err != nil {
 		rpcerrors := strings.SplitAfter(err.Error(), "=")
@@ -120,6 +120,45 @@ func TestScan(t *testing.T) {
 	}
 }
 
+func TestScanDesc(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	bs.Start()
+	defer bs.Stop()
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	ts := client.NewTokenService().WithTokenFileName("testTokenFile").WithHds(&test.HomedirServiceMock{})
+	ic := test.NewClientTest(&test.PasswordReader{
+		Pass: []string{"immudb"},
+	}, ts).WithOptions(client.DefaultOptions())
+	ic.
+		Connect(bs.Dialer)
+	ic.Login("immudb")
+
+	for _, k := range []string{"key1", "key2"} {
+		_, err := ic.Imc.Set([]string{k, "val"})
+		if err != nil {
+			t.Fatal("Set fail", err)
+		}
+	}
+
+	msg, err := ic.Imc.Scan([]string{"key", "desc"})
+	if err != nil {
+		t.Fatal("Scan fail", err)
+	}
+	if strings.Index(msg, "key2") > strings.Index(msg, "key1") {
+		t.Fatalf("Scan desc failed: %s", msg)
+	}
+
+	_, err = ic.Imc.Scan([]string{"key", "sideways"})
+	if err == nil {
+		t.Fatal("Scan with invalid order should fail")
+	}
+}
+
 func _TestCount(t *testing.T) {
 	options := server.DefaultOptions().WithAuth(true)
 	bs := servertest.NewBufconnServer(options)
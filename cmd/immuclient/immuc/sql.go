@@ -19,10 +19,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/database"
 	"github.com/olekukonko/tablewriter"
 )
 
@@ -38,7 +42,7 @@ func (i *immuc) SQLExec(args []string) (string, error) {
 
 	txMetas := response.(*schema.SQLExecResult)
 
-	return fmt.Sprintf("Updated rows: %d", txMetas.UpdatedRows), nil
+	return renderExecResult(txMetas), nil
 }
 
 func (i *immuc) SQLQuery(args []string) (string, error) {
@@ -91,6 +95,79 @@ func (i *immuc) DescribeTable(args []string) (string, error) {
 	return response.(string), nil
 }
 
+// SQLDump writes the dump of the given tables, or of all of them, into a file.
+// Files with the csv extension get the rows of a single table as CSV records, any other file gets a SQL script
+func (i *immuc) SQLDump(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", client.ErrIllegalArguments
+	}
+
+	req := &schema.SQLDumpRequest{Format: database.SQLDumpFormatSQL, Tables: args[1:]}
+
+	if strings.EqualFold(filepath.Ext(args[0]), "."+database.SQLDumpFormatCSV) {
+		if len(req.Tables) != 1 {
+			return "", client.ErrIllegalArguments
+		}
+		req.Format = database.SQLDumpFormatCSV
+	}
+
+	f, err := os.Create(args[0])
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	ctx := context.Background()
+	_, err = i.Execute(func(immuClient client.ImmuClient) (interface{}, error) {
+		return nil, immuClient.SQLDump(ctx, req, f)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("SQL dump written into %s", args[0]), nil
+}
+
+// SQLLoad executes the dump held by a file
+func (i *immuc) SQLLoad(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", client.ErrIllegalArguments
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	ctx := context.Background()
+	response, err := i.Execute(func(immuClient client.ImmuClient) (interface{}, error) {
+		return immuClient.SQLLoad(ctx, f)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	res := response.(*schema.SQLExecResult)
+
+	return renderExecResult(res), nil
+}
+
+func renderExecResult(res *schema.SQLExecResult) string {
+	tables := make([]string, 0, len(res.LastInsertedPKs))
+	for t := range res.LastInsertedPKs {
+		tables = append(tables, t)
+	}
+	sort.Strings(tables)
+
+	result := fmt.Sprintf("Updated rows: %d", res.UpdatedRows)
+	for _, t := range tables {
+		result += fmt.Sprintf("\nLast inserted id in %s: %s", t, schema.RenderValue(res.LastInsertedPKs[t].Value))
+	}
+
+	return result
+}
+
 func renderTableResult(resp *schema.SQLQueryResult) string {
 	if resp == nil {
 		return ""
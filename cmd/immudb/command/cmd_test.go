@@ -67,6 +67,32 @@ func TestImmudbCommandFlagParser(t *testing.T) {
 	assert.Equal(t, o.Logfile, options.Logfile)
 }
 
+func TestImmudbCommandFlagParserMaxValueLen(t *testing.T) {
+	var options *server.Options
+	var err error
+	cmd := &cobra.Command{
+		Use: "immudb",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			options, err = parseOptions()
+			if err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+	cl := Commandline{}
+	cl.setupFlags(cmd, server.DefaultOptions())
+
+	err = viper.BindPFlags(cmd.Flags())
+	assert.Nil(t, err)
+
+	setupDefaults(server.DefaultOptions())
+
+	_, err = executeCommand(cmd, "--max-value-len=134217728")
+	assert.NoError(t, err)
+	assert.Equal(t, 128<<20, options.StoreOptions.MaxValueLen)
+}
+
 func TestImmudbCommandFlagParserWrongTLS(t *testing.T) {
 	viper.Set("mtls", true)
 	o := DefaultTestOptions()
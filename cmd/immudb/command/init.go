@@ -33,6 +33,7 @@ func (cl *Commandline) setupFlags(cmd *cobra.Command, options *server.Options) {
 	cmd.Flags().BoolP("mtls", "m", false, "enable mutual tls")
 	cmd.Flags().BoolP("auth", "s", false, "enable auth")
 	cmd.Flags().Int("max-recv-msg-size", options.MaxRecvMsgSize, "max message size in bytes the server can receive")
+	cmd.Flags().Int("max-value-len", options.StoreOptions.MaxValueLen, "max length in bytes of a value, larger values can be written with the streaming API. Databases keep the largest one they were opened with")
 	cmd.Flags().Bool("no-histograms", false, "disable collection of histogram metrics like query durations")
 	cmd.Flags().BoolP(c.DetachedFlag, c.DetachedShortFlag, options.Detached, "run immudb in background")
 	cmd.Flags().String("certificate", "", "server certificate file path")
@@ -44,8 +45,12 @@ func (cl *Commandline) setupFlags(cmd *cobra.Command, options *server.Options) {
 	cmd.Flags().String("signingKey", options.SigningKey, "signature private key path. If a valid one is provided, it enables the cryptographic signature of the root. E.g. \"./../test/signer/ec3.key\"")
 	cmd.Flags().Bool("synced", true, "synced mode prevents data lost under unexpected crashes but affects performance")
 	cmd.Flags().Int("token-expiry-time", options.TokenExpiryTimeMin, "client authentication token expiration time. Minutes")
-	cmd.Flags().Bool("web-server", options.WebServer, "enable or disable web/console server")
-	cmd.Flags().Int("web-server-port", options.WebServerPort, "web/console server port")
+	cmd.Flags().Bool("web-server", options.WebServer, "enable or disable web/console server and REST API (served under /api)")
+	cmd.Flags().Int("web-server-port", options.WebServerPort, "web/console server and REST API port")
+	cmd.Flags().Bool("grpc-web", false, "serve gRPC-Web requests on the web server port, so browsers can call the gRPC API directly")
+	cmd.Flags().StringSlice("grpc-web-allowed-origins", nil, "comma-separated list of origins allowed to send cross-origin gRPC-Web requests ('*' allows any origin, without sharing credentials with it)")
+	cmd.Flags().Bool("swagger-ui", false, "serve the Swagger UI exploring the REST API under /api/docs, its scripts and styles get loaded by the browser from the unpkg.com CDN unless --swagger-ui-assets-dir is set (the OpenAPI specification is served at /api/openapi.json)")
+	cmd.Flags().String("swagger-ui-assets-dir", "", "directory holding the swagger-ui.css and swagger-ui-bundle.js files of swagger-ui-dist 3.52.5, served along with the Swagger UI instead of loading them from the CDN")
 	cmd.Flags().Bool("pgsql-server", true, "enable or disable pgsql server")
 	cmd.Flags().Int("pgsql-server-port", 5432, "pgsql server port")
 	cmd.Flags().Bool("s3-storage", false, "enable or disable s3 storage")
@@ -54,6 +59,52 @@ func (cl *Commandline) setupFlags(cmd *cobra.Command, options *server.Options) {
 	cmd.Flags().String("s3-secret-key", "", "s3 secret access key")
 	cmd.Flags().String("s3-bucket-name", "", "s3 bucket name")
 	cmd.Flags().String("s3-path-prefix", "", "s3 path prefix (multiple immudb instances can share the same bucket if they have different prefixes)")
+	cmd.Flags().StringSlice("plugins", nil, "comma-separated list of Go plugin files (.so) to be loaded at startup")
+	cmd.Flags().Bool("lazy-database-loading", false, "open user databases on first use or in background after startup, speeds up startup with many databases")
+	cmd.Flags().Int("quota-warning-threshold", options.QuotaWarningThld, "percentage of a database storage quota at which a warning is logged")
+	cmd.Flags().Int("sql-stmt-cache-size", options.SQLStmtCacheSize, "number of parsed SQL statements each database keeps for reuse (0 disables the cache)")
+	cmd.Flags().String("backup-dir", "", "directory where server-side backups are written (default is a .backups directory inside the data dir)")
+	cmd.Flags().Bool("backup-s3-storage", false, "write server-side backups into s3 storage instead of the backup dir")
+	cmd.Flags().String("backup-s3-endpoint", "", "backup s3 endpoint")
+	cmd.Flags().String("backup-s3-access-key-id", "", "backup s3 access key id")
+	cmd.Flags().String("backup-s3-secret-key", "", "backup s3 secret access key")
+	cmd.Flags().String("backup-s3-bucket-name", "", "backup s3 bucket name")
+	cmd.Flags().String("backup-s3-path-prefix", "", "backup s3 path prefix")
+	cmd.Flags().StringArray("backup-schedule", nil, "backup taken automatically, in the form '<database> <full|incremental> <cron expression>', e.g. 'defaultdb full 0 3 * * 0' (can be repeated)")
+	cmd.Flags().String("backup-encryption-key", "", "file holding the hex-encoded AES key used to encrypt server-side backups")
+	cmd.Flags().Int("backup-retention", 0, "number of scheduled full backups kept per database along with their incremental ones, older ones are pruned (0 keeps all)")
+	cmd.Flags().Int("expiry-sweep-interval", options.ExpirySweepInterval, "interval between the deletions of expired keys. Seconds (0 disables them)")
+	cmd.Flags().String("tsa-url", "", "url of the RFC 3161 time-stamp authority the database roots are periodically submitted to")
+	cmd.Flags().Int("tsa-interval", options.TSAInterval, "interval between the submissions of the database roots to the time-stamp authority. Seconds")
+	cmd.Flags().String("anchor-backend", "", "external ledger the database roots are periodically published to (webhook, opentimestamps)")
+	cmd.Flags().String("anchor-url", "", "url of the external ledger, defaults to the public OpenTimestamps calendar for the opentimestamps backend")
+	cmd.Flags().Int("anchor-interval", options.AnchorInterval, "interval between the publications of the database roots to the external ledger. Seconds")
+	cmd.Flags().Int("corruption-check-interval", options.CorruptionCheckInterval, "interval between the verifications of the databases with the corruption checker enabled. Seconds (0 disables them)")
+	cmd.Flags().Uint64("corruption-check-io-rate-limit", 0, "maximum bytes per second read from disk by the corruption checker (0 means unlimited)")
+	cmd.Flags().String("corruption-policy", options.CorruptionPolicy, "action taken when the corruption checker detects tampering: 'alert', or 'quarantine' to also reject writes to the database until the corruption is acknowledged")
+	cmd.Flags().StringArray("corruption-alert-webhook", nil, "url notified with a JSON payload when the corruption checker detects tampering (can be repeated)")
+	cmd.Flags().String("corruption-alert-smtp-address", "", "address (host:port) of the SMTP server used to email corruption alerts")
+	cmd.Flags().String("corruption-alert-smtp-username", "", "SMTP username used to email corruption alerts")
+	cmd.Flags().String("corruption-alert-smtp-password", "", "SMTP password used to email corruption alerts")
+	cmd.Flags().String("corruption-alert-email-from", "", "sender of the corruption alert emails")
+	cmd.Flags().StringArray("corruption-alert-email", nil, "recipient of the corruption alert emails (can be repeated)")
+	cmd.Flags().Int("index-verification-interval", 0, "interval between the verifications of the database indexes against their commit logs. Seconds (0 disables them)")
+	cmd.Flags().Bool("index-auto-rebuild", false, "rebuild the database indexes found inconsistent with their commit logs")
+	cmd.Flags().Int("replica-verification-interval", 0, "interval between the verifications of the replicated databases against their primary through consistency proofs. Seconds (0 disables them)")
+	cmd.Flags().Int("failover-probe-interval", 0, "interval between the probes of the primary of the replicas with automatic failover, promoted once it failed enough consecutive probes. Seconds (0 disables them)")
+	cmd.Flags().Int("failover-threshold", 3, "number of consecutive failed probes of its primary after which a replica with automatic failover is promoted")
+	cmd.Flags().StringArray("replication-hook", nil, "url notified with a JSON payload of every replication event: sync started, caught up, lag exceeded, error and promotion (can be repeated)")
+	cmd.Flags().Uint64("replication-lag-threshold", 0, "number of transactions a replica may lag behind before a lag exceeded event fires (0 disables them)")
+	cmd.Flags().String("advertised-address", "", "address (host:port) clients are told to reach the server at when asking for the primary of a database (default is the server address and port)")
+	cmd.Flags().Int("replication-port", 0, "port of the listener dedicated to replication, accepting only replicas authenticated through client certificates (0 disables it)")
+	cmd.Flags().String("replication-certificate", "", "server certificate of the replication listener")
+	cmd.Flags().String("replication-pkey", "", "server private key of the replication listener")
+	cmd.Flags().String("replication-clientcas", "", "certificate authority of the replicas allowed to connect to the replication listener")
+	cmd.Flags().String("cluster-node-id", "", "id of the node in the cluster, each database is replicated through a Raft group of the nodes (empty disables the cluster mode)")
+	cmd.Flags().String("cluster-raft-address", "", "address (host:port) the node exchanges the consensus traffic on, secured with the replication certificates when the replication port is enabled")
+	cmd.Flags().String("cluster-address", "", "address (host:port) clients are redirected to when the node leads a database (default is the server address and port)")
+	cmd.Flags().StringArray("cluster-peer", nil, "other node of the cluster, in the form '<node id>=<raft address>,<address>', e.g. 'node2=10.0.0.2:3325,10.0.0.2:3322' (can be repeated)")
+	cmd.Flags().String("cluster-secret", "", "secret shared by the nodes of the cluster to authenticate the connections between them (required unless the replication certificates are set)")
 }
 
 func setupDefaults(options *server.Options) {
@@ -65,6 +116,7 @@ func setupDefaults(options *server.Options) {
 	viper.SetDefault("mtls", false)
 	viper.SetDefault("auth", options.GetAuth())
 	viper.SetDefault("max-recv-msg-size", options.MaxRecvMsgSize)
+	viper.SetDefault("max-value-len", options.StoreOptions.MaxValueLen)
 	viper.SetDefault("no-histograms", options.NoHistograms)
 	viper.SetDefault("detached", options.Detached)
 	viper.SetDefault("certificate", "")
@@ -77,6 +129,10 @@ func setupDefaults(options *server.Options) {
 	viper.SetDefault("token-expiry-time", options.TokenExpiryTimeMin)
 	viper.SetDefault("web-server", options.WebServer)
 	viper.SetDefault("web-server-port", options.WebServerPort)
+	viper.SetDefault("grpc-web", false)
+	viper.SetDefault("grpc-web-allowed-origins", []string{})
+	viper.SetDefault("swagger-ui", false)
+	viper.SetDefault("swagger-ui-assets-dir", "")
 	viper.SetDefault("pgsql-server", true)
 	viper.SetDefault("pgsql-server-port", 5432)
 	viper.SetDefault("s3-storage", false)
@@ -85,4 +141,50 @@ func setupDefaults(options *server.Options) {
 	viper.SetDefault("s3-secret-key", "")
 	viper.SetDefault("s3-bucket-name", "")
 	viper.SetDefault("s3-path-prefix", "")
+	viper.SetDefault("plugins", []string{})
+	viper.SetDefault("lazy-database-loading", false)
+	viper.SetDefault("quota-warning-threshold", options.QuotaWarningThld)
+	viper.SetDefault("sql-stmt-cache-size", options.SQLStmtCacheSize)
+	viper.SetDefault("backup-dir", "")
+	viper.SetDefault("backup-s3-storage", false)
+	viper.SetDefault("backup-s3-endpoint", "")
+	viper.SetDefault("backup-s3-access-key-id", "")
+	viper.SetDefault("backup-s3-secret-key", "")
+	viper.SetDefault("backup-s3-bucket-name", "")
+	viper.SetDefault("backup-s3-path-prefix", "")
+	viper.SetDefault("backup-schedule", []string{})
+	viper.SetDefault("backup-retention", 0)
+	viper.SetDefault("backup-encryption-key", "")
+	viper.SetDefault("expiry-sweep-interval", options.ExpirySweepInterval)
+	viper.SetDefault("tsa-url", "")
+	viper.SetDefault("tsa-interval", options.TSAInterval)
+	viper.SetDefault("anchor-backend", "")
+	viper.SetDefault("anchor-url", "")
+	viper.SetDefault("anchor-interval", options.AnchorInterval)
+	viper.SetDefault("corruption-check-interval", options.CorruptionCheckInterval)
+	viper.SetDefault("corruption-check-io-rate-limit", 0)
+	viper.SetDefault("corruption-policy", options.CorruptionPolicy)
+	viper.SetDefault("corruption-alert-webhook", []string{})
+	viper.SetDefault("corruption-alert-smtp-address", "")
+	viper.SetDefault("corruption-alert-smtp-username", "")
+	viper.SetDefault("corruption-alert-smtp-password", "")
+	viper.SetDefault("corruption-alert-email-from", "")
+	viper.SetDefault("corruption-alert-email", []string{})
+	viper.SetDefault("index-verification-interval", 0)
+	viper.SetDefault("index-auto-rebuild", false)
+	viper.SetDefault("replica-verification-interval", 0)
+	viper.SetDefault("failover-probe-interval", 0)
+	viper.SetDefault("failover-threshold", options.FailoverThreshold)
+	viper.SetDefault("advertised-address", "")
+	viper.SetDefault("replication-hook", []string{})
+	viper.SetDefault("replication-lag-threshold", 0)
+	viper.SetDefault("replication-port", 0)
+	viper.SetDefault("replication-certificate", "")
+	viper.SetDefault("replication-pkey", "")
+	viper.SetDefault("replication-clientcas", "")
+	viper.SetDefault("cluster-node-id", "")
+	viper.SetDefault("cluster-raft-address", "")
+	viper.SetDefault("cluster-address", "")
+	viper.SetDefault("cluster-peer", []string{})
+	viper.SetDefault("cluster-secret", "")
 }
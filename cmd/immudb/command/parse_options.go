@@ -17,6 +17,8 @@ limitations under the License.
 package immudb
 
 import (
+	"crypto/tls"
+
 	"github.com/codenotary/immudb/pkg/server"
 	"github.com/spf13/viper"
 )
@@ -32,6 +34,7 @@ func parseOptions() (options *server.Options, err error) {
 	mtls := viper.GetBool("mtls")
 	auth := viper.GetBool("auth")
 	maxRecvMsgSize := viper.GetInt("max-recv-msg-size")
+	maxValueLen := viper.GetInt("max-value-len")
 	noHistograms := viper.GetBool("no-histograms")
 	detached := viper.GetBool("detached")
 	certificate := viper.GetString("certificate")
@@ -47,6 +50,10 @@ func parseOptions() (options *server.Options, err error) {
 
 	webServer := viper.GetBool("web-server")
 	webServerPort := viper.GetInt("web-server-port")
+	grpcWeb := viper.GetBool("grpc-web")
+	grpcWebAllowedOrigins := viper.GetStringSlice("grpc-web-allowed-origins")
+	swaggerUI := viper.GetBool("swagger-ui")
+	swaggerUIAssetsDir := viper.GetString("swagger-ui-assets-dir")
 
 	pgsqlServer := viper.GetBool("pgsql-server")
 	pgsqlServerPort := viper.GetInt("pgsql-server-port")
@@ -58,6 +65,73 @@ func parseOptions() (options *server.Options, err error) {
 	s3BucketName := viper.GetString("s3-bucket-name")
 	s3PathPrefix := viper.GetString("s3-path-prefix")
 
+	plugins := viper.GetStringSlice("plugins")
+
+	lazyDatabaseLoading := viper.GetBool("lazy-database-loading")
+
+	quotaWarningThld := viper.GetInt("quota-warning-threshold")
+
+	sqlStmtCacheSize := viper.GetInt("sql-stmt-cache-size")
+
+	backupDir := viper.GetString("backup-dir")
+	backupS3Storage := viper.GetBool("backup-s3-storage")
+	backupS3Endpoint := viper.GetString("backup-s3-endpoint")
+	backupS3AccessKeyID := viper.GetString("backup-s3-access-key-id")
+	backupS3SecretKey := viper.GetString("backup-s3-secret-key")
+	backupS3BucketName := viper.GetString("backup-s3-bucket-name")
+	backupS3PathPrefix := viper.GetString("backup-s3-path-prefix")
+	backupRetention := viper.GetInt("backup-retention")
+	backupEncryptionKey := viper.GetString("backup-encryption-key")
+	expirySweepInterval := viper.GetInt("expiry-sweep-interval")
+	tsaURL := viper.GetString("tsa-url")
+	tsaInterval := viper.GetInt("tsa-interval")
+	anchorBackend := viper.GetString("anchor-backend")
+	anchorURL := viper.GetString("anchor-url")
+	anchorInterval := viper.GetInt("anchor-interval")
+	corruptionCheckInterval := viper.GetInt("corruption-check-interval")
+	corruptionCheckIORateLimit := viper.GetUint64("corruption-check-io-rate-limit")
+	corruptionPolicy := viper.GetString("corruption-policy")
+	indexVerificationInterval := viper.GetInt("index-verification-interval")
+	indexAutoRebuild := viper.GetBool("index-auto-rebuild")
+	replicaVerificationInterval := viper.GetInt("replica-verification-interval")
+	failoverProbeInterval := viper.GetInt("failover-probe-interval")
+	failoverThreshold := viper.GetInt("failover-threshold")
+	advertisedAddress := viper.GetString("advertised-address")
+	replicationHookURLs := viper.GetStringSlice("replication-hook")
+	replicationLagThreshold := viper.GetUint64("replication-lag-threshold")
+	replicationPort := viper.GetInt("replication-port")
+
+	var backupSchedules []*server.BackupSchedule
+
+	for _, bs := range viper.GetStringSlice("backup-schedule") {
+		schedule, err := server.ParseBackupSchedule(bs)
+		if err != nil {
+			return options, err
+		}
+		backupSchedules = append(backupSchedules, schedule)
+	}
+
+	var clusterOptions *server.ClusterOptions
+
+	if clusterNodeID := viper.GetString("cluster-node-id"); clusterNodeID != "" {
+		var peers []*server.ClusterPeer
+
+		for _, p := range viper.GetStringSlice("cluster-peer") {
+			peer, err := server.ParseClusterPeer(p)
+			if err != nil {
+				return options, err
+			}
+			peers = append(peers, peer)
+		}
+
+		clusterOptions = server.DefaultClusterOptions().
+			WithNodeID(clusterNodeID).
+			WithRaftAddress(viper.GetString("cluster-raft-address")).
+			WithAddress(viper.GetString("cluster-address")).
+			WithPeers(peers).
+			WithSecret(viper.GetString("cluster-secret"))
+	}
+
 	remoteStorageOptions := server.DefaultRemoteStorageOptions().
 		WithS3Storage(s3Storage).
 		WithS3Endpoint(s3Endpoint).
@@ -66,14 +140,39 @@ func parseOptions() (options *server.Options, err error) {
 		WithS3BucketName(s3BucketName).
 		WithS3PathPrefix(s3PathPrefix)
 
+	backupStorageOptions := server.DefaultRemoteStorageOptions().
+		WithS3Storage(backupS3Storage).
+		WithS3Endpoint(backupS3Endpoint).
+		WithS3AccessKeyID(backupS3AccessKeyID).
+		WithS3SecretKey(backupS3SecretKey).
+		WithS3BucketName(backupS3BucketName).
+		WithS3PathPrefix(backupS3PathPrefix)
+
+	corruptionAlertOptions := server.DefaultCorruptionAlertOptions().
+		WithWebhookURLs(viper.GetStringSlice("corruption-alert-webhook")).
+		WithSMTPAddress(viper.GetString("corruption-alert-smtp-address")).
+		WithSMTPUsername(viper.GetString("corruption-alert-smtp-username")).
+		WithSMTPPassword(viper.GetString("corruption-alert-smtp-password")).
+		WithEmailFrom(viper.GetString("corruption-alert-email-from")).
+		WithEmailTo(viper.GetStringSlice("corruption-alert-email"))
+
 	storeOpts := server.DefaultStoreOptions().
-		WithSynced(synced)
+		WithSynced(synced).
+		WithMaxValueLen(maxValueLen)
 
 	tlsConfig, err := setUpTLS(pkey, certificate, clientcas, mtls)
 	if err != nil {
 		return options, err
 	}
 
+	var replicationTLSConfig *tls.Config
+	if replicationPort > 0 {
+		replicationTLSConfig, err = setUpReplicationTLS(viper.GetString("replication-pkey"), viper.GetString("replication-certificate"), viper.GetString("replication-clientcas"))
+		if err != nil {
+			return options, err
+		}
+	}
+
 	options = server.
 		DefaultOptions().
 		WithDir(dir).
@@ -95,8 +194,42 @@ func parseOptions() (options *server.Options, err error) {
 		WithTokenExpiryTime(tokenExpTime).
 		WithWebServer(webServer).
 		WithWebServerPort(webServerPort).
+		WithGrpcWeb(grpcWeb).
+		WithGrpcWebAllowedOrigins(grpcWebAllowedOrigins).
+		WithSwaggerUI(swaggerUI).
+		WithSwaggerUIAssetsDir(swaggerUIAssetsDir).
 		WithPgsqlServer(pgsqlServer).
-		WithPgsqlServerPort(pgsqlServerPort)
+		WithPgsqlServerPort(pgsqlServerPort).
+		WithPlugins(plugins).
+		WithLazyDatabaseLoading(lazyDatabaseLoading).
+		WithQuotaWarningThld(quotaWarningThld).
+		WithSQLStmtCacheSize(sqlStmtCacheSize).
+		WithBackupDir(backupDir).
+		WithBackupStorageOptions(backupStorageOptions).
+		WithBackupSchedules(backupSchedules).
+		WithBackupRetention(backupRetention).
+		WithBackupEncryptionKey(backupEncryptionKey).
+		WithExpirySweepInterval(expirySweepInterval).
+		WithTSAURL(tsaURL).
+		WithTSAInterval(tsaInterval).
+		WithAnchorBackend(anchorBackend).
+		WithAnchorURL(anchorURL).
+		WithAnchorInterval(anchorInterval).
+		WithCorruptionCheckInterval(corruptionCheckInterval).
+		WithCorruptionCheckIORateLimit(corruptionCheckIORateLimit).
+		WithCorruptionPolicy(corruptionPolicy).
+		WithCorruptionAlertOptions(corruptionAlertOptions).
+		WithIndexVerificationInterval(indexVerificationInterval).
+		WithIndexAutoRebuild(indexAutoRebuild).
+		WithReplicaVerificationInterval(replicaVerificationInterval).
+		WithFailoverProbeInterval(failoverProbeInterval).
+		WithFailoverThreshold(failoverThreshold).
+		WithAdvertisedAddress(advertisedAddress).
+		WithReplicationHookURLs(replicationHookURLs).
+		WithReplicationLagThreshold(replicationLagThreshold).
+		WithReplicationPort(replicationPort).
+		WithReplicationTLSConfig(replicationTLSConfig).
+		WithClusterOptions(clusterOptions)
 
 	return options, nil
 }
@@ -31,10 +31,12 @@ Requires={{.Dependencies}}
 After={{.Dependencies}}
 
 [Service]
+Type=notify
 PIDFile=/var/lib/immudb/{{.Name}}.pid
 ExecStartPre=/bin/rm -f /var/lib/immudb/{{.Name}}.pid
 ExecStart={{.Path}} {{.Args}}
 Restart=on-failure
+WatchdogSec=30
 User=%s
 Group=%s
 
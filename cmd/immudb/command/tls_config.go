@@ -60,3 +60,20 @@ func setUpTLS(pkey, cert, ca string, mtls bool) (*tls.Config, error) {
 
 	return c, nil
 }
+
+// setUpReplicationTLS configures the replication listener, only replicas presenting a certificate
+// signed by the given CA are accepted
+func setUpReplicationTLS(pkey, cert, ca string) (*tls.Config, error) {
+	if cert == "" || pkey == "" || ca == "" {
+		return nil, errors.New("in order to enable the replication port a certificate, a private key and a client CA are required")
+	}
+
+	c, err := setUpTLS(pkey, cert, ca, true)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ClientAuth = tls.RequireAndVerifyClientCert
+
+	return c, nil
+}
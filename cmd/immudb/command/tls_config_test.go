@@ -17,6 +17,7 @@ limitations under the License.
 package immudb
 
 import (
+	"crypto/tls"
 	"fmt"
 	"github.com/stretchr/testify/require"
 	"os"
@@ -73,3 +74,28 @@ func TestSetUpTLS(t *testing.T) {
 	_, err = setUpTLS("xxkey.pem", "xxcert.pem", "banana", true)
 	require.Error(t, err)
 }
+
+func TestSetUpReplicationTLS(t *testing.T) {
+	_, err := setUpReplicationTLS("", "", "")
+	require.Error(t, err)
+	_, err = setUpReplicationTLS("xxreplkey.pem", "xxreplcert.pem", "")
+	require.Error(t, err)
+
+	defer os.Remove("xxreplkey.pem")
+	f, _ := os.Create("xxreplkey.pem")
+	fmt.Fprintf(f, key)
+	f.Close()
+
+	defer os.Remove("xxreplcert.pem")
+	f, _ = os.Create("xxreplcert.pem")
+	fmt.Fprintf(f, cert)
+	f.Close()
+
+	_, err = setUpReplicationTLS("xxreplkey.pem", "xxreplcert.pem", "banana")
+	require.Error(t, err)
+
+	c, err := setUpReplicationTLS("xxreplkey.pem", "xxreplcert.pem", "xxreplcert.pem")
+	require.NoError(t, err)
+	require.Equal(t, tls.RequireAndVerifyClientCert, c.ClientAuth)
+	require.NotNil(t, c.ClientCAs)
+}
@@ -26,6 +26,8 @@ const (
 	GZipCompression
 	LZWCompression
 	ZLibCompression
+	ZStdCompression
+	SnappyCompression
 )
 
 const (
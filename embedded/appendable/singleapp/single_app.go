@@ -25,10 +25,13 @@ import (
 	"encoding/binary"
 	"errors"
 	"io"
+	"io/ioutil"
 	"os"
 	"sync"
 
 	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
 )
 
 var ErrorPathIsNotADirectory = errors.New("path is not a directory")
@@ -51,6 +54,10 @@ type AppendableFile struct {
 
 	metadata []byte
 
+	// zstd encoders and decoders are expensive to create, they are lazily created and reused
+	zEncoder *zstd.Encoder
+	zDecoder *zstd.Decoder
+
 	readOnly bool
 	synced   bool
 
@@ -280,6 +287,8 @@ func (aof *AppendableFile) writer(w io.Writer) (cw io.Writer, err error) {
 		cw = lzw.NewWriter(w, lzw.MSB, 8)
 	case appendable.ZLibCompression:
 		cw, err = zlib.NewWriterLevel(w, aof.compressionLevel)
+	case appendable.SnappyCompression:
+		cw = s2.NewWriter(w, s2.WriterSnappyCompat(), s2.WriterConcurrency(1))
 	}
 	return
 }
@@ -294,10 +303,70 @@ func (aof *AppendableFile) reader(r io.Reader) (reader io.ReadCloser, err error)
 		reader = lzw.NewReader(r, lzw.MSB, 8)
 	case appendable.ZLibCompression:
 		reader, err = zlib.NewReader(r)
+	case appendable.SnappyCompression:
+		reader = ioutil.NopCloser(s2.NewReader(r))
 	}
 	return
 }
 
+func (aof *AppendableFile) compress(bs []byte) ([]byte, error) {
+	if aof.compressionFormat == appendable.ZStdCompression {
+		if aof.zEncoder == nil {
+			enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(aof.compressionLevel)), zstd.WithEncoderConcurrency(1))
+			if err != nil {
+				return nil, err
+			}
+			aof.zEncoder = enc
+		}
+
+		return aof.zEncoder.EncodeAll(bs, nil), nil
+	}
+
+	var b bytes.Buffer
+
+	w, err := aof.writer(&b)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.Write(bs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.(io.Closer).Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func (aof *AppendableFile) decompress(cbs []byte) ([]byte, error) {
+	if aof.compressionFormat == appendable.ZStdCompression {
+		if aof.zDecoder == nil {
+			dec, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+			if err != nil {
+				return nil, err
+			}
+			aof.zDecoder = dec
+		}
+
+		return aof.zDecoder.DecodeAll(cbs, nil)
+	}
+
+	r, err := aof.reader(bytes.NewReader(cbs))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+
+	return buf.Bytes(), nil
+}
+
 func (aof *AppendableFile) Append(bs []byte) (off int64, n int, err error) {
 	aof.mutex.Lock()
 	defer aof.mutex.Unlock()
@@ -322,22 +391,11 @@ func (aof *AppendableFile) Append(bs []byte) (off int64, n int, err error) {
 		return
 	}
 
-	var b bytes.Buffer
-
-	w, err := aof.writer(&b)
+	bb, err := aof.compress(bs)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	_, err = w.Write(bs)
-	if err != nil {
-		return 0, 0, err
-	}
-
-	w.(io.Closer).Close()
-
-	bb := b.Bytes()
-
 	bbLenBs := make([]byte, 4)
 	binary.BigEndian.PutUint32(bbLenBs, uint32(len(bb)))
 
@@ -398,15 +456,10 @@ func (aof *AppendableFile) ReadAt(bs []byte, off int64) (n int, err error) {
 		return 0, err
 	}
 
-	r, err := aof.reader(bytes.NewReader(cBs))
+	rbs, err := aof.decompress(cBs)
 	if err != nil {
 		return 0, err
 	}
-	defer r.Close()
-
-	var buf bytes.Buffer
-	buf.ReadFrom(r)
-	rbs := buf.Bytes()
 
 	n = minInt(len(rbs), len(bs))
 
@@ -483,6 +536,13 @@ func (aof *AppendableFile) Close() error {
 
 	aof.closed = true
 
+	if aof.zEncoder != nil {
+		aof.zEncoder.Close()
+	}
+	if aof.zDecoder != nil {
+		aof.zDecoder.Close()
+	}
+
 	return aof.f.Close()
 }
 
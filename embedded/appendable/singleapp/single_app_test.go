@@ -312,6 +312,54 @@ func TestSingleAppZLibCompression(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSingleAppZStdCompression(t *testing.T) {
+	opts := DefaultOptions().WithCompressionFormat(appendable.ZStdCompression)
+	a, err := Open("testdata.aof", opts)
+	defer os.Remove("testdata.aof")
+	require.NoError(t, err)
+
+	_, _, err = a.Append([]byte{1, 2, 3})
+	require.NoError(t, err)
+
+	off, _, err := a.Append([]byte{4, 5, 6})
+	require.NoError(t, err)
+
+	err = a.Flush()
+	require.NoError(t, err)
+
+	bs := make([]byte, 3)
+	_, err = a.ReadAt(bs, off)
+	require.NoError(t, err)
+	require.Equal(t, []byte{4, 5, 6}, bs)
+
+	err = a.Close()
+	require.NoError(t, err)
+}
+
+func TestSingleAppSnappyCompression(t *testing.T) {
+	opts := DefaultOptions().WithCompressionFormat(appendable.SnappyCompression)
+	a, err := Open("testdata.aof", opts)
+	defer os.Remove("testdata.aof")
+	require.NoError(t, err)
+
+	_, _, err = a.Append([]byte{1, 2, 3})
+	require.NoError(t, err)
+
+	off, _, err := a.Append([]byte{4, 5, 6})
+	require.NoError(t, err)
+
+	err = a.Flush()
+	require.NoError(t, err)
+
+	bs := make([]byte, 3)
+	_, err = a.ReadAt(bs, off)
+	require.NoError(t, err)
+	require.Equal(t, []byte{4, 5, 6}, bs)
+
+	err = a.Close()
+	require.NoError(t, err)
+}
+
 func TestSingleAppFlateCompression(t *testing.T) {
 	opts := DefaultOptions().WithCompressionFormat(appendable.FlateCompression)
 	a, err := Open("testdata.aof", opts)
@@ -393,3 +441,51 @@ func TestSingleAppCantCreateFile(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "exists")
 }
+
+func BenchmarkZStdAppend(b *testing.B) {
+	dir, _ := ioutil.TempDir(os.TempDir(), "singleapp")
+	defer os.RemoveAll(dir)
+
+	a, _ := Open(filepath.Join(dir, "bench.aof"), DefaultOptions().WithCompressionFormat(appendable.ZStdCompression))
+	defer a.Close()
+
+	value := make([]byte, 256)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _, err := a.Append(value)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func BenchmarkZStdReadAt(b *testing.B) {
+	dir, _ := ioutil.TempDir(os.TempDir(), "singleapp")
+	defer os.RemoveAll(dir)
+
+	a, _ := Open(filepath.Join(dir, "bench.aof"), DefaultOptions().WithCompressionFormat(appendable.ZStdCompression))
+	defer a.Close()
+
+	value := make([]byte, 256)
+
+	off, _, err := a.Append(value)
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.Flush()
+	if err != nil {
+		panic(err)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := a.ReadAt(value, off)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MinPartSize is the minimum size of each part of a multipart upload but the last one
+const MinPartSize = 5 * 1024 * 1024
+
+var (
+	maxRetries = 3
+	retryDelay = 500 * time.Millisecond
+)
+
+// Writer uploads content of unknown size as a multipart upload, so no local copy of it is needed.
+// Each request is retried on failure. Content becomes visible once the writer is closed,
+// Abort discards the parts uploaded so far
+type Writer struct {
+	s        *Storage
+	ctx      context.Context
+	url      string
+	uploadID string
+	partSize int
+	buf      []byte
+	parts    []completedPart
+}
+
+type completedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+// NewWriter starts a multipart upload
+func (s *Storage) NewWriter(ctx context.Context, name string, partSize int) (*Writer, error) {
+	if strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") || partSize < MinPartSize {
+		return nil, ErrInvalidArguments
+	}
+
+	url, err := s.originalRequestURL(name)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Writer{
+		s:        s,
+		ctx:      ctx,
+		url:      url,
+		partSize: partSize,
+		buf:      make([]byte, 0, partSize),
+	}
+
+	err = s.withRetries(ctx, func() error {
+		resp, err := s.requestWithRedirects(
+			ctx, "POST", url+"?uploads",
+			[]int{200},
+			func() (io.Reader, string, error) { return nil, "", nil },
+			func(req *http.Request) error { return nil },
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		respParsed := struct {
+			UploadId string
+		}{}
+
+		err = xml.NewDecoder(resp.Body).Decode(&respParsed)
+		if err != nil {
+			return err
+		}
+
+		if respParsed.UploadId == "" {
+			return ErrInvalidResponse
+		}
+
+		w.uploadID = respParsed.UploadId
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *Writer) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		c := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+c]
+		p = p[c:]
+		n += c
+
+		if len(w.buf) == cap(w.buf) {
+			err = w.uploadPart()
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+func (w *Writer) uploadPart() error {
+	partNumber := len(w.parts) + 1
+	data := w.buf
+
+	partURL := fmt.Sprintf("%s?partNumber=%d&uploadId=%s", w.url, partNumber, url.QueryEscape(w.uploadID))
+
+	err := w.s.withRetries(w.ctx, func() error {
+		resp, err := w.s.requestWithRedirects(
+			w.ctx, "PUT", partURL,
+			[]int{200},
+			func() (io.Reader, string, error) { return bytes.NewReader(data), "", nil },
+			func(req *http.Request) error {
+				req.ContentLength = int64(len(data))
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		etag := resp.Header.Get("ETag")
+		if etag == "" {
+			return ErrInvalidResponse
+		}
+
+		w.parts = append(w.parts, completedPart{PartNumber: partNumber, ETag: etag})
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	w.buf = w.buf[:0]
+
+	return nil
+}
+
+// Close uploads the remaining content and completes the upload
+func (w *Writer) Close() error {
+	if len(w.buf) > 0 || len(w.parts) == 0 {
+		err := w.uploadPart()
+		if err != nil {
+			return err
+		}
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: w.parts})
+	if err != nil {
+		return err
+	}
+
+	completeURL := fmt.Sprintf("%s?uploadId=%s", w.url, url.QueryEscape(w.uploadID))
+
+	return w.s.withRetries(w.ctx, func() error {
+		resp, err := w.s.requestWithRedirects(
+			w.ctx, "POST", completeURL,
+			[]int{200},
+			func() (io.Reader, string, error) { return bytes.NewReader(body), "application/xml", nil },
+			func(req *http.Request) error { return nil },
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		// Completion may fail even after a successful response code, in which case an error is returned in the body
+		respBody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		if bytes.Contains(respBody, []byte("<Error>")) {
+			log.Printf("S3 POST %s failed: %s", completeURL, respBody)
+			return ErrInvalidResponse
+		}
+
+		return nil
+	})
+}
+
+// Abort discards the upload
+func (w *Writer) Abort() error {
+	abortURL := fmt.Sprintf("%s?uploadId=%s", w.url, url.QueryEscape(w.uploadID))
+
+	return w.s.withRetries(w.ctx, func() error {
+		resp, err := w.s.requestWithRedirects(
+			w.ctx, "DELETE", abortURL,
+			[]int{204},
+			func() (io.Reader, string, error) { return nil, "", nil },
+			func(req *http.Request) error { return nil },
+		)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		return nil
+	})
+}
+
+func (s *Storage) withRetries(ctx context.Context, f func() error) error {
+	var err error
+
+	for i := 0; i <= maxRetries; i++ {
+		if i > 0 {
+			log.Printf("S3 request failed: %v, retrying (%d/%d)", err, i, maxRetries)
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(retryDelay * time.Duration(i)):
+			}
+		}
+
+		err = f()
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,190 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 emulates the multipart upload api of an s3 server
+type fakeS3 struct {
+	mutex       sync.Mutex
+	objects     map[string][]byte
+	parts       map[string]map[int][]byte
+	failures    int
+	uploadCount int
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		objects: map[string][]byte{},
+		parts:   map[string]map[int][]byte{},
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.failures > 0 {
+		f.failures--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+
+	switch {
+	case r.Method == "POST" && query.Get("uploads") == "" && len(query["uploads"]) > 0:
+		f.uploadCount++
+		uploadID := strconv.Itoa(f.uploadCount)
+		f.parts[uploadID] = map[int][]byte{}
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", uploadID)
+
+	case r.Method == "PUT" && query.Get("uploadId") != "":
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		data, _ := ioutil.ReadAll(r.Body)
+		f.parts[query.Get("uploadId")][partNumber] = data
+		w.Header().Set("ETag", fmt.Sprintf("\"etag%d\"", partNumber))
+
+	case r.Method == "POST" && query.Get("uploadId") != "":
+		req := struct {
+			Parts []completedPart `xml:"Part"`
+		}{}
+		xml.NewDecoder(r.Body).Decode(&req)
+
+		var data []byte
+		for i, p := range req.Parts {
+			if p.PartNumber != i+1 || p.ETag != fmt.Sprintf("\"etag%d\"", i+1) {
+				fmt.Fprint(w, "<Error><Code>InvalidPart</Code></Error>")
+				return
+			}
+			data = append(data, f.parts[query.Get("uploadId")][p.PartNumber]...)
+		}
+
+		delete(f.parts, query.Get("uploadId"))
+		f.objects[r.URL.Path] = data
+		fmt.Fprint(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+
+	case r.Method == "DELETE" && query.Get("uploadId") != "":
+		delete(f.parts, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "DELETE":
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestCanonicalSubResources(t *testing.T) {
+	query, err := url.ParseQuery("uploadId=abc&partNumber=2&list-type=2")
+	require.NoError(t, err)
+	require.Equal(t, "partNumber=2&uploadId=abc", canonicalSubResources(query))
+
+	query, err = url.ParseQuery("uploads")
+	require.NoError(t, err)
+	require.Equal(t, "uploads", canonicalSubResources(query))
+
+	require.Empty(t, canonicalSubResources(url.Values{}))
+}
+
+func TestMultipartUpload(t *testing.T) {
+	defer func(d time.Duration) { retryDelay = d }(retryDelay)
+	retryDelay = time.Millisecond
+
+	fake := newFakeS3()
+
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	s, err := Open(srv.URL, "minioadmin", "minioadmin", "immudb", "prefix")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = s.(*Storage).NewWriter(ctx, "/object", MinPartSize)
+	require.Equal(t, ErrInvalidArguments, err)
+
+	_, err = s.(*Storage).NewWriter(ctx, "object", MinPartSize-1)
+	require.Equal(t, ErrInvalidArguments, err)
+
+	w, err := s.(*Storage).NewWriter(ctx, "object", MinPartSize)
+	require.NoError(t, err)
+
+	content := make([]byte, 2*MinPartSize+100)
+	for i := range content {
+		content[i] = byte(i)
+	}
+
+	n, err := w.Write(content[:MinPartSize+10])
+	require.NoError(t, err)
+	require.Equal(t, MinPartSize+10, n)
+
+	// failed requests are retried
+	fake.failures = maxRetries
+
+	_, err = w.Write(content[MinPartSize+10:])
+	require.NoError(t, err)
+
+	err = w.Close()
+	require.NoError(t, err)
+	require.Len(t, w.parts, 3)
+	require.True(t, bytes.Equal(content, fake.objects["/immudb/prefix/object"]))
+
+	// empty uploads
+	w, err = s.(*Storage).NewWriter(ctx, "empty", MinPartSize)
+	require.NoError(t, err)
+
+	err = w.Close()
+	require.NoError(t, err)
+	require.Empty(t, fake.objects["/immudb/prefix/empty"])
+
+	// errors are returned once retries are exhausted
+	w, err = s.(*Storage).NewWriter(ctx, "failed", MinPartSize)
+	require.NoError(t, err)
+
+	fake.failures = maxRetries + 1
+
+	_, err = w.Write(content[:MinPartSize])
+	require.Equal(t, ErrInvalidResponse, err)
+
+	err = w.Abort()
+	require.NoError(t, err)
+	require.NotContains(t, fake.objects, "/immudb/prefix/failed")
+	require.Empty(t, fake.parts)
+}
+
+func TestRemove(t *testing.T) {
+	fake := newFakeS3()
+	fake.objects["/immudb/prefix/object"] = []byte("content")
+
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	s, err := Open(srv.URL, "minioadmin", "minioadmin", "immudb", "prefix")
+	require.NoError(t, err)
+
+	err = s.(*Storage).Remove(context.Background(), "/object")
+	require.Equal(t, ErrInvalidArguments, err)
+
+	err = s.(*Storage).Remove(context.Background(), "object")
+	require.NoError(t, err)
+	require.Empty(t, fake.objects)
+
+	err = s.(*Storage).Remove(context.Background(), "object")
+	require.NoError(t, err)
+}
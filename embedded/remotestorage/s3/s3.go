@@ -128,6 +128,11 @@ func (s *Storage) s3SignedRequest(
 		signedPath = "/" + s.bucket + signedPath
 	}
 
+	// Sub-resources, such as the ones of multipart uploads, are part of the signature
+	if subResources := canonicalSubResources(req.URL.Query()); subResources != "" {
+		signedPath = signedPath + "?" + subResources
+	}
+
 	mac := hmac.New(sha1.New, []byte(s.secretKey))
 	fmt.Fprintf(mac, "%s\n\n%s\n%s\n%s", method, contentType, date, signedPath)
 	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
@@ -140,6 +145,28 @@ func (s *Storage) s3SignedRequest(
 	return req, nil
 }
 
+// signedSubResources must be sorted alphabetically
+var signedSubResources = []string{"partNumber", "uploadId", "uploads"}
+
+func canonicalSubResources(query url.Values) string {
+	var params []string
+
+	for _, name := range signedSubResources {
+		values, ok := query[name]
+		if !ok {
+			continue
+		}
+
+		if len(values) == 0 || values[0] == "" {
+			params = append(params, name)
+		} else {
+			params = append(params, name+"="+values[0])
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
 // Get opens a remote s3 resource
 func (s *Storage) Get(ctx context.Context, name string, offs, size int64) (io.ReadCloser, error) {
 	if offs < 0 || size == 0 {
@@ -349,6 +376,31 @@ func (s *Storage) Exists(ctx context.Context, name string) (bool, error) {
 	return resp.StatusCode == 200, nil
 }
 
+// Remove deletes a remote s3 resource, removing a missing resource is not an error
+func (s *Storage) Remove(ctx context.Context, name string) error {
+	if strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") {
+		return ErrInvalidArguments
+	}
+
+	url, err := s.originalRequestURL(name)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.requestWithRedirects(
+		ctx, "DELETE", url,
+		[]int{200, 204},
+		func() (io.Reader, string, error) { return nil, "", nil },
+		func(req *http.Request) error { return nil },
+	)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
 func (s *Storage) ListEntries(ctx context.Context, path string) ([]remotestorage.EntryInfo, []string, error) {
 
 	if path != "" {
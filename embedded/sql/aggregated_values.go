@@ -106,7 +106,12 @@ func (v *SumValue) Compare(val TypedValue) (int, error) {
 	return -1, nil
 }
 
+// updateWith adds the value to the sum, NULL values are ignored
 func (v *SumValue) updateWith(val TypedValue) error {
+	if val.Value() == nil {
+		return nil
+	}
+
 	if val.Type() != IntegerType {
 		return ErrNotComparableValues
 	}
@@ -141,12 +146,17 @@ func (v *MinValue) Compare(val TypedValue) (int, error) {
 	return v.val.Compare(val)
 }
 
+// updateWith keeps the value if it's the minimum one, NULL values are ignored unless no other value was provided
 func (v *MinValue) updateWith(val TypedValue) error {
-	if v.val == nil {
+	if v.val == nil || v.val.Value() == nil {
 		v.val = val
 		return nil
 	}
 
+	if val.Value() == nil {
+		return nil
+	}
+
 	cmp, err := v.val.Compare(val)
 	if err != nil {
 		return err
@@ -184,12 +194,17 @@ func (v *MaxValue) Compare(val TypedValue) (int, error) {
 	return v.val.Compare(val)
 }
 
+// updateWith keeps the value if it's the maximum one, NULL values are ignored unless no other value was provided
 func (v *MaxValue) updateWith(val TypedValue) error {
-	if v.val == nil {
+	if v.val == nil || v.val.Value() == nil {
 		v.val = val
 		return nil
 	}
 
+	if val.Value() == nil {
+		return nil
+	}
+
 	cmp, err := v.val.Compare(val)
 	if err != nil {
 		return err
@@ -221,6 +236,14 @@ func (v *AVGValue) Type() SQLValueType {
 }
 
 func (v *AVGValue) Value() interface{} {
+	return v.avg()
+}
+
+func (v *AVGValue) avg() uint64 {
+	if v.c == 0 {
+		return 0
+	}
+
 	return v.s / v.c
 }
 
@@ -229,7 +252,7 @@ func (v *AVGValue) Compare(val TypedValue) (int, error) {
 		return 0, ErrNotComparableValues
 	}
 
-	avg := v.s / v.c
+	avg := v.avg()
 	nv := val.Value().(uint64)
 
 	if avg == nv {
@@ -243,7 +266,12 @@ func (v *AVGValue) Compare(val TypedValue) (int, error) {
 	return -1, nil
 }
 
+// updateWith adds the value to the average, NULL values are ignored
 func (v *AVGValue) updateWith(val TypedValue) error {
+	if val.Value() == nil {
+		return nil
+	}
+
 	if val.Type() != IntegerType {
 		return ErrNotComparableValues
 	}
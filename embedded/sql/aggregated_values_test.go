@@ -178,3 +178,32 @@ func TestAVGValue(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, -1, cmp)
 }
+
+func TestAggregatedNullValues(t *testing.T) {
+	sval := &SumValue{sel: "db1.table1.amount"}
+	require.NoError(t, sval.updateWith(&NullValue{t: IntegerType}))
+	require.NoError(t, sval.updateWith(&Number{val: 10}))
+	require.NoError(t, sval.updateWith(&NullValue{t: IntegerType}))
+	require.Equal(t, uint64(10), sval.Value())
+
+	minVal := &MinValue{sel: "db1.table1.amount"}
+	require.NoError(t, minVal.updateWith(&NullValue{t: IntegerType}))
+	require.Nil(t, minVal.Value())
+	require.Equal(t, IntegerType, minVal.Type())
+	require.NoError(t, minVal.updateWith(&Number{val: 10}))
+	require.NoError(t, minVal.updateWith(&NullValue{t: IntegerType}))
+	require.Equal(t, uint64(10), minVal.Value())
+
+	maxVal := &MaxValue{sel: "db1.table1.amount"}
+	require.NoError(t, maxVal.updateWith(&Number{val: 10}))
+	require.NoError(t, maxVal.updateWith(&NullValue{t: IntegerType}))
+	require.Equal(t, uint64(10), maxVal.Value())
+
+	avgVal := &AVGValue{sel: "db1.table1.amount"}
+	require.NoError(t, avgVal.updateWith(&NullValue{t: IntegerType}))
+	require.Equal(t, uint64(0), avgVal.Value())
+	require.NoError(t, avgVal.updateWith(&Number{val: 10}))
+	require.NoError(t, avgVal.updateWith(&NullValue{t: IntegerType}))
+	require.NoError(t, avgVal.updateWith(&Number{val: 20}))
+	require.Equal(t, uint64(15), avgVal.Value())
+}
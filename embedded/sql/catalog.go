@@ -47,6 +47,7 @@ type Column struct {
 	colType       SQLValueType
 	autoIncrement bool
 	notNull       bool
+	unique        bool
 }
 
 func newCatalog() *Catalog {
@@ -243,6 +244,7 @@ func (db *Database) newTable(name string, colsSpec []*ColSpec, pk string) (*Tabl
 			colType:       cs.colType,
 			autoIncrement: cs.autoIncrement,
 			notNull:       cs.notNull || cs.colName == pk,
+			unique:        cs.unique || cs.colName == pk,
 		}
 
 		table.colsByID[col.id] = col
@@ -257,6 +259,10 @@ func (db *Database) newTable(name string, colsSpec []*ColSpec, pk string) (*Tabl
 		return nil, ErrInvalidPK
 	}
 
+	if table.pk.colType == JSONType {
+		return nil, ErrLimitedJSON
+	}
+
 	db.tablesByID[table.id] = table
 	db.tablesByName[table.name] = table
 
@@ -278,3 +284,11 @@ func (c *Column) Type() SQLValueType {
 func (c *Column) IsNullable() bool {
 	return !c.notNull
 }
+
+func (c *Column) IsAutoIncremental() bool {
+	return c.autoIncrement
+}
+
+func (c *Column) IsUnique() bool {
+	return c.unique
+}
@@ -18,6 +18,7 @@ package sql
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"io"
 	"math"
@@ -46,6 +47,7 @@ var ErrInvalidColumn = errors.New("invalid column")
 var ErrPKCanNotBeNull = errors.New("primary key can not be null")
 var ErrNotNullableColumnCannotBeNull = errors.New("not nullable column can not be null")
 var ErrIndexedColumnCanNotBeNull = errors.New("indexed column can not be null")
+var ErrUniqueConstraintViolation = errors.New("unique constraint violation")
 var ErrIndexAlreadyExists = errors.New("index already exists")
 var ErrInvalidNumberOfValues = errors.New("invalid number of values provided")
 var ErrInvalidValue = errors.New("invalid value provided")
@@ -68,12 +70,16 @@ var ErrUnexpected = errors.New("unexpected error")
 var ErrMaxKeyLengthExceeded = errors.New("max key length exceeded")
 var ErrColumnIsNotAnAggregation = errors.New("column is not an aggregation")
 var ErrLimitedCount = errors.New("only unbounded counting is supported i.e. COUNT()")
+var ErrLimitedGroupBy = errors.New("grouped rows can only be ordered by the grouping column")
 var ErrTxDoesNotExist = errors.New("tx does not exist")
 var ErrDivisionByZero = errors.New("division by zero")
 var ErrMissingParameter = errors.New("missing paramter")
 var ErrUnsupportedParameter = errors.New("unsupported parameter")
 var ErrLimitedIndex = errors.New("index creation is only supported on empty tables")
 var ErrAlreadyClosed = errors.New("sql engine already closed")
+var ErrInvalidJSON = errors.New("invalid JSON document")
+var ErrInvalidJSONPath = errors.New("invalid JSON path")
+var ErrLimitedJSON = errors.New("JSON columns can not be used as primary keys nor indexed")
 
 var mKeyVal = [32]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
 
@@ -622,6 +628,7 @@ func (e *Engine) loadColSpecs(dbID, tableID, pkID uint64, snap *store.Snapshot)
 			colType:       colType,
 			autoIncrement: v[0]&autoIncrementFlag != 0,
 			notNull:       v[0]&nullableFlag != 0,
+			unique:        v[0]&uniqueFlag != 0,
 		}
 
 		specs = append(specs, spec)
@@ -746,7 +753,8 @@ func asType(t string) (SQLValueType, error) {
 		t == BooleanType ||
 		t == VarcharType ||
 		t == BLOBType ||
-		t == TimestampType {
+		t == TimestampType ||
+		t == JSONType {
 		return t, nil
 	}
 
@@ -827,6 +835,103 @@ func (e *Engine) unmapRow(mkey []byte) (dbID, tableID, colID uint64, encVal, enc
 	return
 }
 
+func (e *Engine) latestDataSnapshot() (*store.Snapshot, error) {
+	lastTxID, _ := e.dataStore.Alh()
+
+	err := e.dataStore.WaitForIndexingUpto(lastTxID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.dataStore.SnapshotSince(math.MaxUint64)
+}
+
+// checkUniqueness fails if a row, other than the one identified by pkEncVal, currently holds encVal in the unique column.
+// Index entries left behind by rows updated to a different value are skipped
+func (e *Engine) checkUniqueness(snap *store.Snapshot, col *Column, uniqueKey, encVal, pkEncVal []byte) error {
+	table := col.table
+
+	idxReader, err := snap.NewKeyReader(&store.KeyReaderSpec{SeekKey: uniqueKey, Prefix: uniqueKey})
+	if err != nil {
+		return err
+	}
+	defer idxReader.Close()
+
+	for {
+		mkey, _, _, _, err := idxReader.Read()
+		if err == store.ErrNoMoreEntries {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, _, _, _, encPKVal, err := e.unmapRow(mkey)
+		if err != nil {
+			return err
+		}
+
+		if bytes.Equal(encPKVal, pkEncVal) {
+			continue
+		}
+
+		pkKey := e.mapKey(RowPrefix, EncodeID(table.db.id), EncodeID(table.id), EncodeID(table.pk.id), encPKVal)
+
+		v, _, _, err := snap.Get(pkKey)
+		if err != nil {
+			return err
+		}
+
+		currVal, err := encodedColValue(v, table, col.id)
+		if err != nil {
+			return err
+		}
+
+		if bytes.Equal(currVal, encVal) {
+			return ErrUniqueConstraintViolation
+		}
+	}
+}
+
+// encodedColValue returns the encoded value of the column in the encoded row or nil if it's null
+func encodedColValue(v []byte, table *Table, colID uint64) ([]byte, error) {
+	if len(v) < EncLenLen {
+		return nil, ErrCorruptedData
+	}
+
+	voff := 0
+
+	cols := int(binary.BigEndian.Uint32(v[voff:]))
+	voff += EncLenLen
+
+	for i := 0; i < cols; i++ {
+		if len(v[voff:]) < EncIDLen {
+			return nil, ErrCorruptedData
+		}
+
+		id := binary.BigEndian.Uint64(v[voff:])
+		voff += EncIDLen
+
+		col, err := table.GetColumnByID(id)
+		if err != nil {
+			return nil, ErrCorruptedData
+		}
+
+		_, n, err := DecodeValue(v[voff:], col.colType)
+		if err != nil {
+			return nil, err
+		}
+
+		if id == colID {
+			return v[voff : voff+n], nil
+		}
+
+		voff += n
+	}
+
+	return nil, nil
+}
+
 func (e *Engine) mapKey(mappingPrefix string, encValues ...[]byte) []byte {
 	return MapKey(e.prefix, mappingPrefix, encValues...)
 }
@@ -874,13 +979,23 @@ func maxKeyVal(colType SQLValueType) []byte {
 
 func EncodeRawValue(val interface{}, colType SQLValueType, asKey bool) ([]byte, error) {
 	switch colType {
-	case VarcharType:
+	case VarcharType, JSONType:
 		{
 			strVal, ok := val.(string)
 			if !ok {
 				return nil, ErrInvalidValue
 			}
 
+			if colType == JSONType {
+				if asKey {
+					return nil, ErrLimitedJSON
+				}
+
+				if !json.Valid([]byte(strVal)) {
+					return nil, ErrInvalidJSON
+				}
+			}
+
 			if asKey && len(strVal) > len(maxKeyVal(VarcharType)) {
 				return nil, ErrInvalidPK
 			}
@@ -1022,6 +1137,34 @@ func EncodeValue(val TypedValue, colType SQLValueType, asKey bool) ([]byte, erro
 
 			return encv[:], nil
 		}
+	case JSONType:
+		{
+			var strVal string
+
+			switch v := val.(type) {
+			case *JSON:
+				strVal = v.val
+			case *Varchar:
+				strVal = v.val
+			default:
+				return nil, ErrInvalidValue
+			}
+
+			if asKey {
+				return nil, ErrLimitedJSON
+			}
+
+			if !json.Valid([]byte(strVal)) {
+				return nil, ErrInvalidJSON
+			}
+
+			// len(v) + v
+			encv := make([]byte, EncLenLen+len(strVal))
+			binary.BigEndian.PutUint32(encv[:], uint32(len(strVal)))
+			copy(encv[EncLenLen:], []byte(strVal))
+
+			return encv, nil
+		}
 	}
 
 	/*
@@ -1051,6 +1194,13 @@ func DecodeValue(b []byte, colType SQLValueType) (TypedValue, int, error) {
 
 			return &Varchar{val: v}, voff, nil
 		}
+	case JSONType:
+		{
+			v := string(b[voff : voff+vlen])
+			voff += vlen
+
+			return &JSON{val: v}, voff, nil
+		}
 	case IntegerType:
 		{
 			if vlen > 8 {
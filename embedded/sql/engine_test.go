@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -460,6 +461,66 @@ func TestAutoIncrementPK(t *testing.T) {
 	require.Len(t, summary.LastInsertedPKs, 1)
 	require.Equal(t, uint64(4), summary.LastInsertedPKs["table1"])
 	require.Equal(t, 2, summary.UpdatedRows)
+
+	t.Run("identity column", func(t *testing.T) {
+		_, err = engine.ExecStmt("CREATE TABLE table2 (id INTEGER IDENTITY, title VARCHAR, PRIMARY KEY id)", nil, true)
+		require.NoError(t, err)
+
+		summary, err = engine.ExecStmt("INSERT INTO table2(title) VALUES ('name1'), ('name2')", nil, true)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), summary.LastInsertedPKs["table2"])
+	})
+
+	t.Run("concurrent inserts should be assigned distinct ids", func(t *testing.T) {
+		workers := 10
+		insertsPerWorker := 10
+
+		var wg sync.WaitGroup
+		pks := make(chan uint64, workers*insertsPerWorker)
+		errs := make(chan error, workers*insertsPerWorker)
+
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+
+			go func(w int) {
+				defer wg.Done()
+
+				for i := 0; i < insertsPerWorker; i++ {
+					params := map[string]interface{}{"title": fmt.Sprintf("worker%d_name%d", w, i)}
+
+					summary, err := engine.ExecStmt("INSERT INTO table1(title) VALUES (@title)", params, false)
+					if err != nil {
+						errs <- err
+						return
+					}
+
+					pks <- summary.LastInsertedPKs["table1"]
+				}
+			}(w)
+		}
+
+		wg.Wait()
+		close(pks)
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		assigned := make(map[uint64]struct{})
+
+		for pk := range pks {
+			require.Greater(t, pk, uint64(4))
+			require.LessOrEqual(t, pk, uint64(4+workers*insertsPerWorker))
+
+			_, duplicated := assigned[pk]
+			require.False(t, duplicated)
+
+			assigned[pk] = struct{}{}
+		}
+
+		require.Len(t, assigned, workers*insertsPerWorker)
+	})
 }
 
 func TestTransactions(t *testing.T) {
@@ -579,6 +640,236 @@ func TestUseSnapshot(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestQueryAsBefore(t *testing.T) {
+	catalogStore, err := store.Open("catalog_as_before", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("catalog_as_before")
+
+	dataStore, err := store.Open("sqldata_as_before", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("sqldata_as_before")
+
+	engine, err := NewEngine(catalogStore, dataStore, prefix)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE DATABASE db1", nil, true)
+	require.NoError(t, err)
+
+	err = engine.UseDatabase("db1")
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE TABLE table1 (id INTEGER, title VARCHAR, age INTEGER, PRIMARY KEY id)", nil, true)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE INDEX ON table1(age)", nil, true)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("UPSERT INTO table1 (id, title, age) VALUES (1, 'title1', 10), (2, 'title2', 20)", nil, true)
+	require.NoError(t, err)
+
+	summary, err := engine.ExecStmt("UPSERT INTO table1 (id, title, age) VALUES (1, 'title1_v2', 10), (3, 'title3', 30)", nil, true)
+	require.NoError(t, err)
+	require.Len(t, summary.DMTxs, 1)
+
+	updateTx := summary.DMTxs[0].ID
+
+	titles := func(query string) []string {
+		r, err := engine.QueryStmt(query, nil, true)
+		require.NoError(t, err)
+		defer r.Close()
+
+		var titles []string
+
+		for {
+			row, err := r.Read()
+			if err == ErrNoMoreRows {
+				break
+			}
+			require.NoError(t, err)
+
+			titles = append(titles, row.Values[EncodeSelector("", "db1", "table1", "title")].Value().(string))
+		}
+
+		return titles
+	}
+
+	require.Equal(t, []string{"title1_v2", "title2", "title3"}, titles("SELECT title FROM table1"))
+
+	// rows are resolved as they were before the transaction, whichever index they are read through
+	for _, query := range []string{
+		"SELECT title FROM table1 BEFORE TX %d",
+		"SELECT title FROM (table1 BEFORE TX %d)",
+		"SELECT title FROM table1 BEFORE TX %d ORDER BY age",
+	} {
+		require.Equal(t, []string{"title1", "title2"}, titles(fmt.Sprintf(query, updateTx)))
+	}
+
+	require.Equal(t, []string{"title1"}, titles(fmt.Sprintf("SELECT title FROM table1 BEFORE TX %d WHERE id = 1", updateTx)))
+	require.Equal(t, []string{"title1_v2"}, titles(fmt.Sprintf("SELECT title FROM table1 BEFORE TX %d WHERE id = 1", updateTx+1)))
+	require.Empty(t, titles(fmt.Sprintf("SELECT title FROM table1 BEFORE TX %d WHERE id = 3", updateTx)))
+
+	err = engine.Close()
+	require.NoError(t, err)
+}
+
+func TestJSON(t *testing.T) {
+	catalogStore, err := store.Open("catalog_json", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("catalog_json")
+
+	dataStore, err := store.Open("sqldata_json", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("sqldata_json")
+
+	engine, err := NewEngine(catalogStore, dataStore, prefix)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE DATABASE db1", nil, true)
+	require.NoError(t, err)
+
+	err = engine.UseDatabase("db1")
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE TABLE table0 (doc JSON, PRIMARY KEY doc)", nil, true)
+	require.ErrorIs(t, err, ErrLimitedJSON)
+
+	_, err = engine.ExecStmt("CREATE TABLE table1 (id INTEGER, doc JSON, title VARCHAR, PRIMARY KEY id)", nil, true)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE INDEX ON table1(doc)", nil, true)
+	require.ErrorIs(t, err, ErrLimitedJSON)
+
+	_, err = engine.ExecStmt("UPSERT INTO table1 (id, doc) VALUES (1, '{\"name\": ')", nil, true)
+	require.ErrorIs(t, err, ErrInvalidJSON)
+
+	_, err = engine.ExecStmt(`
+		UPSERT INTO table1 (id, doc, title) VALUES
+			(1, '{"name": "alice", "age": 32, "admin": true, "address": {"city": "Rome"}, "tags": ["a", "b"]}', '{"age": 10}'),
+			(2, '{"name": "bob", "age": 17, "admin": false, "tags": []}', 'bob'),
+			(3, NULL, 'nobody')
+	`, nil, true)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("UPSERT INTO table1 (id, doc) VALUES (@id, @doc)", map[string]interface{}{"id": 4, "doc": `{"name": "carol", "age": 45.5}`}, true)
+	require.NoError(t, err)
+
+	query := func(query string) [][]TypedValue {
+		r, err := engine.QueryStmt(query, nil, true)
+		require.NoError(t, err)
+		defer r.Close()
+
+		cols, err := r.Columns()
+		require.NoError(t, err)
+
+		var rows [][]TypedValue
+
+		for {
+			row, err := r.Read()
+			if err == ErrNoMoreRows {
+				break
+			}
+			require.NoError(t, err)
+
+			values := make([]TypedValue, len(cols))
+			for i, col := range cols {
+				values[i] = row.Values[col.Selector()]
+			}
+
+			rows = append(rows, values)
+		}
+
+		return rows
+	}
+
+	r, err := engine.QueryStmt("SELECT id, doc, JSON_EXTRACT(doc, '$.address'), JSON_VALUE(doc, '$.age', INTEGER) AS age FROM table1", nil, true)
+	require.NoError(t, err)
+
+	cols, err := r.Columns()
+	require.NoError(t, err)
+	require.Len(t, cols, 4)
+	require.Equal(t, JSONType, cols[1].Type)
+	require.Equal(t, "(db1.table1.col2)", cols[2].Selector())
+	require.Equal(t, JSONType, cols[2].Type)
+	require.Equal(t, "(db1.table1.age)", cols[3].Selector())
+	require.Equal(t, IntegerType, cols[3].Type)
+
+	err = r.Close()
+	require.NoError(t, err)
+
+	rows := query("SELECT id, JSON_EXTRACT(doc, '$.address'), JSON_VALUE(doc, '$.age', INTEGER), JSON_VALUE(doc, '$.tags[1]') FROM table1")
+	require.Len(t, rows, 4)
+	require.Equal(t, []TypedValue{&Number{val: 1}, &JSON{val: `{"city":"Rome"}`}, &Number{val: 32}, &Varchar{val: "b"}}, rows[0])
+	require.Equal(t, []TypedValue{&Number{val: 2}, &NullValue{t: JSONType}, &Number{val: 17}, &NullValue{t: VarcharType}}, rows[1])
+	require.Equal(t, []TypedValue{&Number{val: 3}, &NullValue{t: JSONType}, &NullValue{t: IntegerType}, &NullValue{t: VarcharType}}, rows[2])
+	require.Equal(t, []TypedValue{&Number{val: 4}, &NullValue{t: JSONType}, &NullValue{t: IntegerType}, &NullValue{t: VarcharType}}, rows[3])
+
+	rows = query("SELECT id FROM table1 WHERE JSON_VALUE(doc, '$.age', INTEGER) >= 18 AND JSON_VALUE(doc, '$.admin', BOOLEAN) = TRUE")
+	require.Equal(t, [][]TypedValue{{&Number{val: 1}}}, rows)
+
+	rows = query("SELECT id FROM table1 WHERE JSON_VALUE(doc, '$.age') = '45.5'")
+	require.Equal(t, [][]TypedValue{{&Number{val: 4}}}, rows)
+
+	rows = query("SELECT id FROM table1 WHERE JSON_VALUE(doc, '$.name') LIKE '^[bc]'")
+	require.Equal(t, [][]TypedValue{{&Number{val: 2}}, {&Number{val: 4}}}, rows)
+
+	// documents stored as VARCHAR can be queried as well
+	rows = query("SELECT JSON_VALUE(title, '$.age', INTEGER) AS age FROM table1 WHERE id = 1")
+	require.Equal(t, [][]TypedValue{{&Number{val: 10}}}, rows)
+
+	for _, q := range []string{
+		"SELECT JSON_VALUE(title, '$.age') FROM table1 WHERE id = 2",
+		"SELECT JSON_VALUE(doc, 'age') FROM table1",
+	} {
+		r, err := engine.QueryStmt(q, nil, true)
+		require.NoError(t, err)
+
+		_, err = r.Read()
+		require.Error(t, err)
+
+		err = r.Close()
+		require.NoError(t, err)
+	}
+
+	for _, q := range []string{
+		"SELECT JSON_VALUE(id, '$.age') FROM table1",
+		"SELECT JSON_VALUE(doc, '$.age', BLOB) FROM table1",
+		"SELECT JSON_EXTRACT(doc, '$.age', INTEGER) FROM table1",
+	} {
+		r, err := engine.QueryStmt(q, nil, true)
+		require.NoError(t, err)
+
+		_, err = r.Columns()
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		err = r.Close()
+		require.NoError(t, err)
+	}
+
+	params, err := engine.InferParameters("SELECT id FROM table1 WHERE JSON_VALUE(doc, '$.age', INTEGER) > @age")
+	require.NoError(t, err)
+	require.Equal(t, map[string]SQLValueType{"age": IntegerType}, params)
+
+	params, err = engine.InferParameters("UPSERT INTO table1 (id, doc) VALUES (@id, @doc)")
+	require.NoError(t, err)
+	require.Equal(t, map[string]SQLValueType{"id": IntegerType, "doc": JSONType}, params)
+
+	b, err := EncodeRawValue(`{"name": "alice"}`, JSONType, false)
+	require.NoError(t, err)
+
+	v, _, err := DecodeValue(b, JSONType)
+	require.NoError(t, err)
+	require.Equal(t, &JSON{val: `{"name": "alice"}`}, v)
+
+	_, err = EncodeRawValue(`{"name": "alice"}`, JSONType, true)
+	require.ErrorIs(t, err, ErrLimitedJSON)
+
+	_, err = EncodeRawValue(`{"name": `, JSONType, false)
+	require.ErrorIs(t, err, ErrInvalidJSON)
+
+	err = engine.Close()
+	require.NoError(t, err)
+}
+
 func TestEncodeRawValue(t *testing.T) {
 	b, err := EncodeRawValue(uint64(1), IntegerType, true)
 	require.NoError(t, err)
@@ -1598,6 +1889,233 @@ func TestGroupByHaving(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestGroupByUnorderedRows(t *testing.T) {
+	catalogStore, err := store.Open("catalog_group_by_index", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("catalog_group_by_index")
+
+	dataStore, err := store.Open("sqldata_group_by_index", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("sqldata_group_by_index")
+
+	engine, err := NewEngine(catalogStore, dataStore, prefix)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE DATABASE db1", nil, true)
+	require.NoError(t, err)
+
+	err = engine.UseDatabase("db1")
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE TABLE table1 (id INTEGER, country VARCHAR, city VARCHAR, amount INTEGER, PRIMARY KEY id)", nil, true)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE INDEX ON table1(country)", nil, true)
+	require.NoError(t, err)
+
+	// rows of the same country are not stored next to each other
+	_, err = engine.ExecStmt(`
+		UPSERT INTO table1 (id, country, city, amount) VALUES
+			(1, 'IT', 'Rome', 10),
+			(2, 'NL', 'Utrecht', 20),
+			(3, 'IT', 'Milan', NULL),
+			(4, 'NL', 'Amsterdam', 40),
+			(5, 'IT', 'Turin', 30),
+			(6, 'ES', 'Madrid', NULL)
+	`, nil, true)
+	require.NoError(t, err)
+
+	r, err := engine.QueryStmt("SELECT country, COUNT() AS c, SUM(amount) AS s, MIN(amount) AS mi, MAX(amount) AS ma, AVG(amount) AS a FROM table1 GROUP BY country", nil, true)
+	require.NoError(t, err)
+
+	for _, expected := range []struct {
+		country string
+		count   uint64
+		sum     uint64
+		min     interface{}
+		max     interface{}
+		avg     uint64
+	}{
+		{"ES", 1, 0, nil, nil, 0},
+		{"IT", 3, 40, uint64(10), uint64(30), 20},
+		{"NL", 2, 60, uint64(20), uint64(40), 30},
+	} {
+		row, err := r.Read()
+		require.NoError(t, err)
+
+		require.Equal(t, expected.country, row.Values[EncodeSelector("", "db1", "table1", "country")].Value())
+		require.Equal(t, expected.count, row.Values[EncodeSelector("", "db1", "table1", "c")].Value())
+		require.Equal(t, expected.sum, row.Values[EncodeSelector("", "db1", "table1", "s")].Value())
+		require.Equal(t, expected.min, row.Values[EncodeSelector("", "db1", "table1", "mi")].Value())
+		require.Equal(t, expected.max, row.Values[EncodeSelector("", "db1", "table1", "ma")].Value())
+		require.Equal(t, expected.avg, row.Values[EncodeSelector("", "db1", "table1", "a")].Value())
+	}
+
+	_, err = r.Read()
+	require.Equal(t, ErrNoMoreRows, err)
+
+	err = r.Close()
+	require.NoError(t, err)
+
+	r, err = engine.QueryStmt("SELECT country, COUNT() AS c FROM table1 GROUP BY country HAVING COUNT() > 1 ORDER BY country DESC", nil, true)
+	require.NoError(t, err)
+
+	for _, country := range []string{"NL", "IT"} {
+		row, err := r.Read()
+		require.NoError(t, err)
+		require.Equal(t, country, row.Values[EncodeSelector("", "db1", "table1", "country")].Value())
+	}
+
+	_, err = r.Read()
+	require.Equal(t, ErrNoMoreRows, err)
+
+	err = r.Close()
+	require.NoError(t, err)
+
+	// rows are sorted in memory when there is no index to read them through
+	groups := func(q string, col string) map[interface{}]uint64 {
+		r, err := engine.QueryStmt(q, nil, true)
+		require.NoError(t, err)
+		defer r.Close()
+
+		counts := make(map[interface{}]uint64)
+
+		for {
+			row, err := r.Read()
+			if err == ErrNoMoreRows {
+				break
+			}
+			require.NoError(t, err)
+
+			v := row.Values[EncodeSelector("", "db1", "table1", col)].Value()
+			_, ok := counts[v]
+			require.False(t, ok, "fragmented group %v", v)
+
+			counts[v] = row.Values[EncodeSelector("", "db1", "table1", "c")].Value().(uint64)
+		}
+
+		return counts
+	}
+
+	require.Equal(t, map[interface{}]uint64{nil: 2, uint64(10): 1, uint64(20): 1, uint64(30): 1, uint64(40): 1},
+		groups("SELECT amount, COUNT() AS c FROM table1 GROUP BY amount", "amount"))
+
+	require.Equal(t, map[interface{}]uint64{"ES": 1, "IT": 3, "NL": 2},
+		groups("SELECT country, COUNT() AS c FROM (SELECT country FROM table1) GROUP BY country", "country"))
+
+	require.Equal(t, map[interface{}]uint64{"Madrid": 1, "Milan": 1, "Rome": 1, "Turin": 1, "Utrecht": 1, "Amsterdam": 1},
+		groups("SELECT country, city, COUNT() AS c FROM table1 GROUP BY country, city", "city"))
+
+	_, err = engine.QueryStmt("SELECT country, COUNT() FROM table1 GROUP BY country ORDER BY id", nil, true)
+	require.ErrorIs(t, err, ErrLimitedGroupBy)
+
+	err = engine.Close()
+	require.NoError(t, err)
+}
+
+func TestExplain(t *testing.T) {
+	catalogStore, err := store.Open("catalog_explain", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("catalog_explain")
+
+	dataStore, err := store.Open("sqldata_explain", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("sqldata_explain")
+
+	engine, err := NewEngine(catalogStore, dataStore, prefix)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE DATABASE db1", nil, true)
+	require.NoError(t, err)
+
+	err = engine.UseDatabase("db1")
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt(`
+		CREATE TABLE table1 (id INTEGER, country VARCHAR, amount INTEGER, fid INTEGER, PRIMARY KEY id);
+		CREATE INDEX ON table1(country);
+		CREATE TABLE table2 (id INTEGER, title VARCHAR, PRIMARY KEY id);
+	`, nil, true)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("UPSERT INTO table1 (id, country, amount, fid) VALUES (1, 'IT', 10, 1), (2, 'NL', 20, 1), (3, 'IT', 30, 2)", nil, true)
+	require.NoError(t, err)
+
+	explain := func(query string) [][]interface{} {
+		r, err := engine.QueryStmt(query, nil, true)
+		require.NoError(t, err)
+		defer r.Close()
+
+		cols, err := r.Columns()
+		require.NoError(t, err)
+		require.Len(t, cols, 5)
+		require.Equal(t, "(db1.plan.step)", cols[0].Selector())
+		require.Equal(t, "(db1.plan.estimated_rows)", cols[4].Selector())
+
+		var plan [][]interface{}
+
+		for {
+			row, err := r.Read()
+			if err == ErrNoMoreRows {
+				break
+			}
+			require.NoError(t, err)
+
+			step := make([]interface{}, len(cols))
+			for i, col := range cols {
+				step[i] = row.Values[col.Selector()].Value()
+			}
+
+			plan = append(plan, step)
+		}
+
+		return plan
+	}
+
+	require.Equal(t, [][]interface{}{
+		{uint64(1), "SCAN", "db1.table1", "primary key id, ascending order, full range", uint64(3)},
+		{uint64(2), "FILTER", nil, "where condition, evaluated on every row", nil},
+		{uint64(3), "PROJECT", nil, "all columns", nil},
+	}, explain("EXPLAIN SELECT * FROM table1 WHERE amount > 10"))
+
+	require.Equal(t, [][]interface{}{
+		{uint64(1), "SCAN", "db1.table1 AS t1", "index on country, descending order, full range, as before tx 2", uint64(3)},
+		{uint64(2), "JOIN", "db1.table2", "inner join, lookup by primary key id = db1.t1.fid", nil},
+		{uint64(3), "PROJECT", nil, "selected columns: 2", nil},
+		{uint64(4), "LIMIT", nil, "row limit: 1", nil},
+	}, explain("EXPLAIN SELECT t1.id, table2.title FROM (table1 BEFORE TX 2 AS t1) INNER JOIN table2 ON table2.id = t1.fid ORDER BY country DESC LIMIT 1"))
+
+	require.Equal(t, [][]interface{}{
+		{uint64(1), "SCAN", "db1.table1", "index on country, ascending order, full range", uint64(3)},
+		{uint64(2), "GROUP", nil, "group by country", nil},
+		{uint64(3), "FILTER", nil, "having condition, evaluated on every group", nil},
+		{uint64(4), "PROJECT", nil, "selected columns: 2", nil},
+	}, explain("EXPLAIN SELECT country, SUM(amount) FROM table1 GROUP BY country HAVING COUNT() > 1"))
+
+	require.Equal(t, [][]interface{}{
+		{uint64(1), "SCAN", "db1.table1", "primary key id, ascending order, full range", uint64(3)},
+		{uint64(2), "SORT", nil, "rows buffered in memory, ordered by amount", nil},
+		{uint64(3), "GROUP", nil, "group by amount", nil},
+		{uint64(4), "PROJECT", nil, "selected columns: 2", nil},
+	}, explain("EXPLAIN SELECT amount, COUNT() FROM table1 GROUP BY amount"))
+
+	require.Equal(t, [][]interface{}{
+		{uint64(1), "SCAN", "db1.table1", "primary key id, ascending order, full range", uint64(3)},
+		{uint64(2), "GROUP", nil, "all rows as a single group", nil},
+		{uint64(3), "PROJECT", nil, "selected columns: 1", nil},
+		{uint64(4), "PROJECT", nil, "all columns", nil},
+	}, explain("EXPLAIN SELECT * FROM (SELECT COUNT() FROM table1)"))
+
+	_, err = engine.QueryStmt("EXPLAIN SELECT id FROM table1 ORDER BY amount", nil, true)
+	require.ErrorIs(t, err, ErrLimitedOrderBy)
+
+	_, err = engine.QueryStmt("EXPLAIN SELECT id FROM table3", nil, true)
+	require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+	err = engine.Close()
+	require.NoError(t, err)
+}
+
 func TestJoins(t *testing.T) {
 	catalogStore, err := store.Open("catalog_innerjoin", store.DefaultOptions())
 	require.NoError(t, err)
@@ -1762,6 +2280,255 @@ func TestNestedJoins(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLeftAndIndexJoins(t *testing.T) {
+	catalogStore, err := store.Open("catalog_indexjoins", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("catalog_indexjoins")
+
+	dataStore, err := store.Open("sqldata_indexjoins", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("sqldata_indexjoins")
+
+	engine, err := NewEngine(catalogStore, dataStore, prefix)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE DATABASE db1", nil, true)
+	require.NoError(t, err)
+
+	err = engine.UseDatabase("db1")
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt(`
+		CREATE TABLE customers (id INTEGER, name VARCHAR, country VARCHAR, PRIMARY KEY id);
+		CREATE TABLE orders (id INTEGER, customer INTEGER, amount INTEGER, PRIMARY KEY id);
+		CREATE INDEX ON orders(customer);
+		CREATE TABLE items (id INTEGER, ord INTEGER, product VARCHAR, PRIMARY KEY id);
+		CREATE INDEX ON items(ord);
+	`, nil, true)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt(`
+		UPSERT INTO customers (id, name, country) VALUES (1, 'alice', 'IT'), (2, 'bob', 'ES'), (3, 'carol', NULL);
+		UPSERT INTO orders (id, customer, amount) VALUES (10, 1, 100), (11, 2, 200), (12, 1, 120);
+		UPSERT INTO items (id, ord, product) VALUES (100, 10, 'p1'), (101, 10, 'p2'), (102, 11, 'p3');
+	`, nil, true)
+	require.NoError(t, err)
+
+	readAll := func(query string) []*Row {
+		r, err := engine.QueryStmt(query, nil, true)
+		require.NoError(t, err)
+
+		var rows []*Row
+
+		for {
+			row, err := r.Read()
+			if err == ErrNoMoreRows {
+				break
+			}
+			require.NoError(t, err)
+
+			rows = append(rows, row)
+		}
+
+		err = r.Close()
+		require.NoError(t, err)
+
+		return rows
+	}
+
+	customer := EncodeSelector("", "db1", "customers", "id")
+	order := EncodeSelector("", "db1", "orders", "id")
+	amount := EncodeSelector("", "db1", "orders", "amount")
+
+	t.Run("inner join through an index", func(t *testing.T) {
+		rows := readAll("SELECT customers.id, orders.id, orders.amount FROM customers INNER JOIN orders ON orders.customer = customers.id")
+		require.Len(t, rows, 3)
+
+		require.Equal(t, uint64(1), rows[0].Values[customer].Value())
+		require.Equal(t, uint64(10), rows[0].Values[order].Value())
+		require.Equal(t, uint64(1), rows[1].Values[customer].Value())
+		require.Equal(t, uint64(12), rows[1].Values[order].Value())
+		require.Equal(t, uint64(2), rows[2].Values[customer].Value())
+		require.Equal(t, uint64(200), rows[2].Values[amount].Value())
+	})
+
+	t.Run("left join through an index", func(t *testing.T) {
+		rows := readAll("SELECT customers.id, orders.amount FROM customers LEFT JOIN orders ON customers.id = orders.customer")
+		require.Len(t, rows, 4)
+
+		require.Equal(t, uint64(3), rows[3].Values[customer].Value())
+		require.Nil(t, rows[3].Values[amount].Value())
+	})
+
+	t.Run("nested joins", func(t *testing.T) {
+		rows := readAll(`
+			SELECT customers.id, orders.id, items.product
+			FROM customers
+			LEFT JOIN orders ON orders.customer = customers.id
+			JOIN items ON items.ord = orders.id
+			WHERE customers.id < 3`)
+		require.Len(t, rows, 3)
+
+		require.Equal(t, "p1", rows[0].Values[EncodeSelector("", "db1", "items", "product")].Value())
+		require.Equal(t, "p2", rows[1].Values[EncodeSelector("", "db1", "items", "product")].Value())
+		require.Equal(t, uint64(11), rows[2].Values[order].Value())
+	})
+
+	t.Run("left join by primary key on NULL values", func(t *testing.T) {
+		rows := readAll("SELECT orders.id, customers.name FROM orders LEFT JOIN customers ON customers.id = orders.amount")
+		require.Len(t, rows, 3)
+
+		for _, row := range rows {
+			require.Nil(t, row.Values[EncodeSelector("", "db1", "customers", "name")].Value())
+		}
+	})
+
+	t.Run("joins on non-indexed columns", func(t *testing.T) {
+		r, err := engine.QueryStmt("SELECT id FROM orders LEFT JOIN customers ON customers.country = orders.amount", nil, true)
+		require.NoError(t, err)
+
+		_, err = r.Read()
+		require.Equal(t, ErrJointColumnNotFound, err)
+
+		err = r.Close()
+		require.NoError(t, err)
+
+		_, err = engine.QueryStmt("SELECT id FROM orders RIGHT JOIN customers ON customers.id = orders.customer", nil, true)
+		require.Equal(t, ErrUnsupportedJoinType, err)
+	})
+
+	t.Run("explain", func(t *testing.T) {
+		r, err := engine.QueryStmt("EXPLAIN SELECT customers.id FROM customers LEFT JOIN orders ON orders.customer = customers.id", nil, true)
+		require.NoError(t, err)
+
+		_, err = r.Read()
+		require.NoError(t, err)
+
+		row, err := r.Read()
+		require.NoError(t, err)
+		require.Equal(t, "left join, lookup by index on customer = db1.customers.id", row.Values[EncodeSelector("", "db1", "plan", "detail")].Value())
+
+		err = r.Close()
+		require.NoError(t, err)
+	})
+
+	err = engine.Close()
+	require.NoError(t, err)
+}
+
+func TestUniqueConstraint(t *testing.T) {
+	catalogStore, err := store.Open("catalog_unique", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("catalog_unique")
+
+	dataStore, err := store.Open("sqldata_unique", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("sqldata_unique")
+
+	engine, err := NewEngine(catalogStore, dataStore, prefix)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE DATABASE db1", nil, true)
+	require.NoError(t, err)
+
+	err = engine.UseDatabase("db1")
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE TABLE table0 (id INTEGER, doc JSON UNIQUE, PRIMARY KEY id)", nil, true)
+	require.Equal(t, ErrLimitedJSON, err)
+
+	_, err = engine.ExecStmt("CREATE TABLE users (id INTEGER, email VARCHAR UNIQUE, name VARCHAR NOT NULL, PRIMARY KEY id)", nil, true)
+	require.NoError(t, err)
+
+	table, err := engine.GetTableByName("db1", "users")
+	require.NoError(t, err)
+
+	indexed, err := table.IsIndexed("email")
+	require.NoError(t, err)
+	require.True(t, indexed)
+	require.True(t, table.ColsByName()["email"].IsUnique())
+	require.False(t, table.ColsByName()["name"].IsUnique())
+
+	_, err = engine.ExecStmt("CREATE INDEX ON users(email)", nil, true)
+	require.Equal(t, ErrIndexAlreadyExists, err)
+
+	_, err = engine.ExecStmt("INSERT INTO users (id, email) VALUES (1, 'user1@immudb.io')", nil, true)
+	require.Equal(t, ErrNotNullableColumnCannotBeNull, err)
+
+	_, err = engine.ExecStmt("INSERT INTO users (id, name) VALUES (1, 'user1')", nil, true)
+	require.Equal(t, ErrIndexedColumnCanNotBeNull, err)
+
+	_, err = engine.ExecStmt("INSERT INTO users (id, email, name) VALUES (1, 'user1@immudb.io', 'user1')", nil, true)
+	require.NoError(t, err)
+
+	t.Run("duplicated value in another row should fail", func(t *testing.T) {
+		_, err = engine.ExecStmt("INSERT INTO users (id, email, name) VALUES (2, 'user1@immudb.io', 'user2')", nil, true)
+		require.Equal(t, ErrUniqueConstraintViolation, err)
+
+		_, err = engine.ExecStmt("UPSERT INTO users (id, email, name) VALUES (2, 'user1@immudb.io', 'user2')", nil, true)
+		require.Equal(t, ErrUniqueConstraintViolation, err)
+	})
+
+	t.Run("duplicated values within the same statement should fail", func(t *testing.T) {
+		_, err = engine.ExecStmt("INSERT INTO users (id, email, name) VALUES (2, 'user2@immudb.io', 'user2'), (3, 'user2@immudb.io', 'user3')", nil, true)
+		require.Equal(t, ErrUniqueConstraintViolation, err)
+	})
+
+	t.Run("duplicated values within the same transaction should fail", func(t *testing.T) {
+		_, err = engine.ExecStmt(`
+			BEGIN TRANSACTION
+				INSERT INTO users (id, email, name) VALUES (2, 'user2@immudb.io', 'user2');
+				INSERT INTO users (id, email, name) VALUES (3, 'user2@immudb.io', 'user3');
+			COMMIT
+		`, nil, true)
+		require.Equal(t, ErrUniqueConstraintViolation, err)
+	})
+
+	t.Run("upserting the same row with its own value should succeed", func(t *testing.T) {
+		_, err = engine.ExecStmt("UPSERT INTO users (id, email, name) VALUES (1, 'user1@immudb.io', 'user1_renamed')", nil, true)
+		require.NoError(t, err)
+	})
+
+	t.Run("a value released by an update can be reused", func(t *testing.T) {
+		_, err = engine.ExecStmt("UPSERT INTO users (id, email, name) VALUES (1, 'user1@codenotary.com', 'user1')", nil, true)
+		require.NoError(t, err)
+
+		_, err = engine.ExecStmt("INSERT INTO users (id, email, name) VALUES (2, 'user1@immudb.io', 'user2')", nil, true)
+		require.NoError(t, err)
+
+		_, err = engine.ExecStmt("UPSERT INTO users (id, email, name) VALUES (1, 'user1@immudb.io', 'user1')", nil, true)
+		require.Equal(t, ErrUniqueConstraintViolation, err)
+	})
+
+	err = engine.Close()
+	require.NoError(t, err)
+
+	t.Run("unique constraint should be enforced after reopening", func(t *testing.T) {
+		engine, err := NewEngine(catalogStore, dataStore, prefix)
+		require.NoError(t, err)
+
+		err = engine.EnsureCatalogReady(nil)
+		require.NoError(t, err)
+
+		err = engine.UseDatabase("db1")
+		require.NoError(t, err)
+
+		table, err := engine.GetTableByName("db1", "users")
+		require.NoError(t, err)
+		require.True(t, table.ColsByName()["email"].IsUnique())
+		require.True(t, table.PrimaryKey().IsUnique())
+
+		_, err = engine.ExecStmt("INSERT INTO users (id, email, name) VALUES (3, 'user1@codenotary.com', 'user3')", nil, true)
+		require.Equal(t, ErrUniqueConstraintViolation, err)
+
+		_, err = engine.ExecStmt("INSERT INTO users (id, email, name) VALUES (3, 'user3@immudb.io', 'user3')", nil, true)
+		require.NoError(t, err)
+
+		err = engine.Close()
+		require.NoError(t, err)
+	})
+}
+
 func TestReOpening(t *testing.T) {
 	catalogStore, err := store.Open("catalog_reopening", store.DefaultOptions())
 	require.NoError(t, err)
@@ -2076,6 +2843,72 @@ func TestInferParametersPrepared(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestReusePreparedStmts(t *testing.T) {
+	catalogStore, err := store.Open("catalog_reuse_prepared", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("catalog_reuse_prepared")
+
+	dataStore, err := store.Open("sqldata_reuse_prepared", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("sqldata_reuse_prepared")
+
+	engine, err := NewEngine(catalogStore, dataStore, prefix)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE DATABASE db1", nil, true)
+	require.NoError(t, err)
+
+	err = engine.UseDatabase("db1")
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE TABLE table1(id INTEGER, title VARCHAR, PRIMARY KEY id)", nil, true)
+	require.NoError(t, err)
+
+	upserts, err := Parse(strings.NewReader("UPSERT INTO table1 (id, title) VALUES (@id, @title)"))
+	require.NoError(t, err)
+
+	queries, err := Parse(strings.NewReader("SELECT title FROM table1 WHERE id = $1"))
+	require.NoError(t, err)
+	require.Len(t, queries, 1)
+
+	// parsed statements are not bound to the parameters they are executed with
+	for i := 1; i <= 3; i++ {
+		_, err = engine.ExecPreparedStmts(upserts, map[string]interface{}{"id": i, "title": fmt.Sprintf("title%d", i)}, true)
+		require.NoError(t, err)
+	}
+
+	_, err = engine.ExecStmt("CREATE TABLE table2(id INTEGER AUTO_INCREMENT, title VARCHAR, PRIMARY KEY id)", nil, true)
+	require.NoError(t, err)
+
+	inserts, err := Parse(strings.NewReader("INSERT INTO table2 (title) VALUES (@title)"))
+	require.NoError(t, err)
+
+	// nor to the auto-incremental values they were assigned
+	for i := 1; i <= 3; i++ {
+		summary, err := engine.ExecPreparedStmts(inserts, map[string]interface{}{"title": fmt.Sprintf("title%d", i)}, true)
+		require.NoError(t, err)
+		require.Equal(t, uint64(i), summary.LastInsertedPKs["table2"])
+	}
+
+	for i := 1; i <= 3; i++ {
+		r, err := engine.QueryPreparedStmt(queries[0].(*SelectStmt), map[string]interface{}{"param1": i}, true)
+		require.NoError(t, err)
+
+		row, err := r.Read()
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("title%d", i), row.Values[EncodeSelector("", "db1", "table1", "title")].Value())
+
+		_, err = r.Read()
+		require.ErrorIs(t, err, ErrNoMoreRows)
+
+		err = r.Close()
+		require.NoError(t, err)
+	}
+
+	err = engine.Close()
+	require.NoError(t, err)
+}
+
 func TestInferParametersUnbounded(t *testing.T) {
 	catalogStore, err := store.Open("catalog_infer_params_unbounded", store.DefaultOptions())
 	require.NoError(t, err)
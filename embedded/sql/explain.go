@@ -0,0 +1,315 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+const explainAlias = "plan"
+
+// ExplainStmt describes how a query is executed, EXPLAIN queries are handled as SELECT * FROM (ExplainStmt)
+// thus the plan is returned as any other query result, with a row for each step in the order steps are executed
+type ExplainStmt struct {
+	stmt *SelectStmt
+}
+
+type planStep struct {
+	operation string
+	target    string
+	detail    string
+
+	// estimatedRows is only known for the steps reading rows from a table
+	estimatedRows *uint64
+}
+
+func (stmt *ExplainStmt) inferParameters(e *Engine, implicitDB *Database, params map[string]SQLValueType) error {
+	return stmt.stmt.inferParameters(e, implicitDB, params)
+}
+
+func (stmt *ExplainStmt) Resolve(e *Engine, implicitDB *Database, snap *store.Snapshot, params map[string]interface{}, ordCol *OrdCol) (RowReader, error) {
+	if ordCol != nil {
+		return nil, ErrLimitedOrderBy
+	}
+
+	_, err := stmt.stmt.compileUsing(e, implicitDB, params)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := stmt.stmt.explain(e, implicitDB, snap, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := []*ColDescriptor{
+		{Database: implicitDB.name, Table: explainAlias, Column: "step", Type: IntegerType},
+		{Database: implicitDB.name, Table: explainAlias, Column: "operation", Type: VarcharType},
+		{Database: implicitDB.name, Table: explainAlias, Column: "target", Type: VarcharType},
+		{Database: implicitDB.name, Table: explainAlias, Column: "detail", Type: VarcharType},
+		{Database: implicitDB.name, Table: explainAlias, Column: "estimated_rows", Type: IntegerType},
+	}
+
+	values := make([][]TypedValue, len(steps))
+
+	for i, step := range steps {
+		var target TypedValue = &NullValue{t: VarcharType}
+		if step.target != "" {
+			target = &Varchar{val: step.target}
+		}
+
+		var estimatedRows TypedValue = &NullValue{t: IntegerType}
+		if step.estimatedRows != nil {
+			estimatedRows = &Number{val: *step.estimatedRows}
+		}
+
+		values[i] = []TypedValue{
+			&Number{val: uint64(i + 1)},
+			&Varchar{val: step.operation},
+			target,
+			&Varchar{val: step.detail},
+			estimatedRows,
+		}
+	}
+
+	return newValuesRowReader(implicitDB.name, explainAlias, cols, values)
+}
+
+func (stmt *ExplainStmt) explain(e *Engine, implicitDB *Database, snap *store.Snapshot, ordCol *OrdCol) ([]*planStep, error) {
+	return nil, ErrNoSupported
+}
+
+func (stmt *ExplainStmt) Alias() string {
+	return explainAlias
+}
+
+// explain returns the steps followed by Resolve and the row readers it builds
+func (stmt *SelectStmt) explain(e *Engine, implicitDB *Database, snap *store.Snapshot, ordCol *OrdCol) ([]*planStep, error) {
+	steps, err := stmt.ds.explain(e, implicitDB, snap, stmt.orderByCol(e, implicitDB))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, join := range stmt.joins {
+		if join.joinType != InnerJoin && join.joinType != LeftJoin {
+			return nil, ErrUnsupportedJoinType
+		}
+
+		tableRef, ok := join.ds.(*TableRef)
+		if !ok {
+			return nil, ErrLimitedJoins
+		}
+
+		table, err := tableRef.referencedTable(e, implicitDB)
+		if err != nil {
+			return nil, err
+		}
+
+		joinCol, fkSel, err := joinColumn(join.cond, table, tableRef.Alias())
+		if err != nil {
+			return nil, err
+		}
+
+		_, db, tableAlias, col := fkSel.resolve(implicitDB.name, stmt.ds.Alias())
+
+		joinType := "inner join"
+		if join.joinType == LeftJoin {
+			joinType = "left join"
+		}
+
+		lookup := "primary key"
+		if joinCol != table.pk {
+			lookup = "index on"
+		}
+
+		steps = append(steps, &planStep{
+			operation: "JOIN",
+			target:    tableRef.target(table),
+			detail:    fmt.Sprintf("%s, lookup by %s %s = %s.%s.%s", joinType, lookup, joinCol.colName, db, tableAlias, col),
+		})
+	}
+
+	if stmt.where != nil {
+		steps = append(steps, &planStep{
+			operation: "FILTER",
+			detail:    "where condition, evaluated on every row",
+		})
+	}
+
+	containsAggregations := false
+	for _, sel := range stmt.selectors {
+		_, containsAggregations = sel.(*AggColSelector)
+		if containsAggregations {
+			break
+		}
+	}
+
+	if containsAggregations {
+		detail := "all rows as a single group"
+		if len(stmt.groupBy) > 0 {
+			cols := make([]string, len(stmt.groupBy))
+			for i, sel := range stmt.groupBy {
+				cols[i] = sel.col
+			}
+
+			detail = "group by " + strings.Join(cols, ", ")
+
+			if len(stmt.orderBy) == 0 && !stmt.groupedByIndex(e, implicitDB) {
+				steps = append(steps, &planStep{
+					operation: "SORT",
+					detail:    "rows buffered in memory, ordered by " + strings.Join(cols, ", "),
+				})
+			}
+		}
+
+		steps = append(steps, &planStep{
+			operation: "GROUP",
+			detail:    detail,
+		})
+
+		if stmt.having != nil {
+			steps = append(steps, &planStep{
+				operation: "FILTER",
+				detail:    "having condition, evaluated on every group",
+			})
+		}
+	}
+
+	detail := "all columns"
+	if len(stmt.selectors) > 0 {
+		detail = fmt.Sprintf("selected columns: %d", len(stmt.selectors))
+	}
+
+	steps = append(steps, &planStep{
+		operation: "PROJECT",
+		detail:    detail,
+	})
+
+	if stmt.limit > 0 {
+		steps = append(steps, &planStep{
+			operation: "LIMIT",
+			detail:    fmt.Sprintf("row limit: %d", stmt.limit),
+		})
+	}
+
+	return steps, nil
+}
+
+// explain describes how the rows of the table are read, as there is no other way to tell how many rows satisfy
+// the query, estimated rows are the rows of the table
+func (stmt *TableRef) explain(e *Engine, implicitDB *Database, snap *store.Snapshot, ordCol *OrdCol) ([]*planStep, error) {
+	table, err := stmt.referencedTable(e, implicitDB)
+	if err != nil {
+		return nil, err
+	}
+
+	col := table.pk
+	cmp := GreaterOrEqualTo
+
+	if ordCol != nil {
+		col, err = table.GetColumnByName(ordCol.sel.col)
+		if err != nil {
+			return nil, err
+		}
+
+		cmp = ordCol.cmp
+	}
+
+	var detail strings.Builder
+
+	if col.id == table.pk.id {
+		detail.WriteString("primary key " + col.colName)
+	} else {
+		_, indexed := table.indexes[col.id]
+		if !indexed {
+			return nil, ErrColumnNotIndexed
+		}
+
+		detail.WriteString("index on " + col.colName)
+	}
+
+	if cmp == LowerThan || cmp == LowerOrEqualTo {
+		detail.WriteString(", descending order")
+	} else {
+		detail.WriteString(", ascending order")
+	}
+
+	detail.WriteString(", full range")
+
+	asBefore := stmt.asBefore
+	if asBefore == 0 {
+		asBefore = e.snapAsBeforeTx
+	}
+
+	if asBefore > 0 {
+		detail.WriteString(fmt.Sprintf(", as before tx %d", asBefore))
+	}
+
+	rows, err := e.countRows(snap, table)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*planStep{{
+		operation:     "SCAN",
+		target:        stmt.target(table),
+		detail:        detail.String(),
+		estimatedRows: &rows,
+	}}, nil
+}
+
+func (stmt *TableRef) target(table *Table) string {
+	target := table.db.name + "." + table.name
+
+	if stmt.as != "" {
+		target += " AS " + stmt.as
+	}
+
+	return target
+}
+
+// countRows returns the number of rows of the table, by counting the entries of its primary key
+func (e *Engine) countRows(snap *store.Snapshot, table *Table) (uint64, error) {
+	prefix := e.mapKey(RowPrefix, EncodeID(table.db.id), EncodeID(table.id), EncodeID(table.pk.id))
+
+	r, err := snap.NewKeyReader(&store.KeyReaderSpec{
+		SeekKey:       prefix,
+		InclusiveSeek: true,
+		Prefix:        prefix,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	var rows uint64
+
+	for {
+		_, _, _, _, err := r.Read()
+		if err == store.ErrNoMoreEntries {
+			return rows, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		rows++
+	}
+}
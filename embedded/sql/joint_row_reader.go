@@ -17,6 +17,8 @@ limitations under the License.
 package sql
 
 import (
+	"sort"
+
 	"github.com/codenotary/immudb/embedded/store"
 )
 
@@ -31,6 +33,11 @@ type jointRowReader struct {
 	joins []*JoinSpec
 
 	params map[string]interface{}
+
+	// rows holds the row being joined, followed by its extensions with the rows of each of the joins resolved so far,
+	// while matches holds, for each of those joins, the matching rows not yet read
+	rows    []*Row
+	matches [][]*Row
 }
 
 func (e *Engine) newJointRowReader(db *Database, snap *store.Snapshot, params map[string]interface{}, rowReader RowReader, joins []*JoinSpec) (*jointRowReader, error) {
@@ -39,7 +46,7 @@ func (e *Engine) newJointRowReader(db *Database, snap *store.Snapshot, params ma
 	}
 
 	for _, jspec := range joins {
-		if jspec.joinType != InnerJoin {
+		if jspec.joinType != InnerJoin && jspec.joinType != LeftJoin {
 			return nil, ErrUnsupportedJoinType
 		}
 
@@ -146,54 +153,104 @@ func (jointr *jointRowReader) SetParameters(params map[string]interface{}) {
 
 func (jointr *jointRowReader) Read() (*Row, error) {
 	for {
-		row, err := jointr.rowReader.Read()
-		if err != nil {
-			return nil, err
-		}
-
-		unsolvedFK := false
-
-		for _, jspec := range jointr.joins {
-			tableRef := jspec.ds.(*TableRef)
-			table, err := tableRef.referencedTable(jointr.e, jointr.implicitDB)
+		if len(jointr.rows) == 0 {
+			row, err := jointr.rowReader.Read()
 			if err != nil {
 				return nil, err
 			}
 
-			fkSel, err := jspec.cond.jointColumnTo(table.pk, tableRef.Alias())
-			if err != nil {
-				return nil, err
-			}
+			jointr.rows = append(jointr.rows, row)
+		}
 
-			fkVal, ok := row.Values[EncodeSelector(fkSel.resolve(jointr.rowReader.ImplicitDB(), jointr.rowReader.ImplicitTable()))]
-			if !ok {
-				return nil, ErrInvalidJointColumn
-			}
+		i := len(jointr.rows) - 1
+
+		if i == len(jointr.joins) {
+			row := jointr.rows[i]
+			jointr.rows = jointr.rows[:i]
+			return row, nil
+		}
 
-			fkEncVal, err := EncodeValue(fkVal, table.pk.colType, asKey)
+		if len(jointr.matches) == i {
+			matches, err := jointr.matchingRows(jointr.joins[i], jointr.rows[i])
 			if err != nil {
 				return nil, err
 			}
 
-			pkOrd := &OrdCol{
-				sel: &ColSelector{
-					db:    table.db.name,
-					table: table.name,
-					col:   table.pk.colName,
-				},
-				initKeyVal:    fkEncVal,
-				useInitKeyVal: true,
-			}
+			jointr.matches = append(jointr.matches, matches)
+		}
 
-			jr, err := jspec.ds.Resolve(jointr.e, jointr.implicitDB, jointr.snap, jointr.params, pkOrd)
-			if err != nil {
-				return nil, err
-			}
+		if len(jointr.matches[i]) == 0 {
+			jointr.rows = jointr.rows[:i]
+			jointr.matches = jointr.matches[:i]
+			continue
+		}
+
+		match := jointr.matches[i][0]
+		jointr.matches[i] = jointr.matches[i][1:]
+
+		row := &Row{Values: make(map[string]TypedValue, len(jointr.rows[i].Values)+len(match.Values))}
+
+		// Note: by adding values this way joins behave as nested i.e. following joins will be able to seek values
+		// from previously resolved ones.
+		for c, v := range jointr.rows[i].Values {
+			row.Values[c] = v
+		}
+
+		for c, v := range match.Values {
+			row.Values[c] = v
+		}
+
+		jointr.rows = append(jointr.rows, row)
+	}
+}
+
+// matchingRows returns the rows of the joined table matching the given row, looked up by primary key or through an index.
+// When there is no matching row, left joins get a row holding NULL values
+func (jointr *jointRowReader) matchingRows(jspec *JoinSpec, row *Row) ([]*Row, error) {
+	tableRef := jspec.ds.(*TableRef)
+	table, err := tableRef.referencedTable(jointr.e, jointr.implicitDB)
+	if err != nil {
+		return nil, err
+	}
+
+	col, fkSel, err := joinColumn(jspec.cond, table, tableRef.Alias())
+	if err != nil {
+		return nil, err
+	}
 
+	fkVal, ok := row.Values[EncodeSelector(fkSel.resolve(jointr.rowReader.ImplicitDB(), jointr.rowReader.ImplicitTable()))]
+	if !ok {
+		return nil, ErrInvalidJointColumn
+	}
+
+	var matches []*Row
+
+	// NULL values match no row
+	if fkVal.Value() != nil {
+		fkEncVal, err := EncodeValue(fkVal, col.colType, asKey)
+		if err != nil {
+			return nil, err
+		}
+
+		ordCol := &OrdCol{
+			sel: &ColSelector{
+				db:    table.db.name,
+				table: table.name,
+				col:   col.colName,
+			},
+			cmp:           EqualTo,
+			initKeyVal:    fkEncVal,
+			useInitKeyVal: true,
+		}
+
+		jr, err := jspec.ds.Resolve(jointr.e, jointr.implicitDB, jointr.snap, jointr.params, ordCol)
+		if err != nil {
+			return nil, err
+		}
+
+		for {
 			jrow, err := jr.Read()
-			if err == store.ErrNoMoreEntries {
-				unsolvedFK = true
-				jr.Close()
+			if err == ErrNoMoreRows {
 				break
 			}
 			if err != nil {
@@ -201,22 +258,52 @@ func (jointr *jointRowReader) Read() (*Row, error) {
 				return nil, err
 			}
 
-			// Note: by adding values this way joins behave as nested i.e. following joins will be able to seek values
-			// from previously resolved ones.
-			for c, v := range jrow.Values {
-				row.Values[c] = v
-			}
+			matches = append(matches, jrow)
+		}
 
-			err = jr.Close()
-			if err != nil {
-				return nil, err
-			}
+		err = jr.Close()
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		if !unsolvedFK {
-			return row, nil
+	if len(matches) == 0 && jspec.joinType == LeftJoin {
+		nullRow := &Row{Values: make(map[string]TypedValue, len(table.colsByID))}
+
+		for _, c := range table.colsByID {
+			nullRow.Values[EncodeSelector("", table.db.name, tableRef.Alias(), c.colName)] = &NullValue{t: c.colType}
+		}
+
+		matches = append(matches, nullRow)
+	}
+
+	return matches, nil
+}
+
+// joinColumn returns the column of the joined table referenced by the join condition, together with the selector
+// of the value it must be equal to. Only the primary key and indexed columns can be referenced
+func joinColumn(cond ValueExp, table *Table, tableAlias string) (*Column, *ColSelector, error) {
+	cols := []*Column{table.pk}
+
+	indexedCols := make([]*Column, 0, len(table.indexes))
+	for colID := range table.indexes {
+		indexedCols = append(indexedCols, table.colsByID[colID])
+	}
+	sort.Slice(indexedCols, func(i, j int) bool { return indexedCols[i].id < indexedCols[j].id })
+
+	for _, col := range append(cols, indexedCols...) {
+		sel, err := cond.jointColumnTo(col, tableAlias)
+		if err == ErrJointColumnNotFound {
+			continue
 		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return col, sel, nil
 	}
+
+	return nil, nil, ErrJointColumnNotFound
 }
 
 func (jointr *jointRowReader) Close() error {
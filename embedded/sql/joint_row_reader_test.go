@@ -54,7 +54,7 @@ func TestJointRowReader(t *testing.T) {
 	r, err := engine.newRawRowReader(db, snap, table, 0, "", "id", EqualTo, nil)
 	require.NoError(t, err)
 
-	_, err = engine.newJointRowReader(db, snap, nil, r, []*JoinSpec{{joinType: LeftJoin}})
+	_, err = engine.newJointRowReader(db, snap, nil, r, []*JoinSpec{{joinType: RightJoin}})
 	require.Equal(t, ErrUnsupportedJoinType, err)
 
 	_, err = engine.newJointRowReader(db, snap, nil, r, []*JoinSpec{{joinType: InnerJoin, ds: &SelectStmt{}}})
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// jsonPathStep is either the key of an object field or the position of an array element
+type jsonPathStep struct {
+	key   string
+	index int
+}
+
+// parseJSONPath parses paths as $.field.nested[0], where $ refers to the whole document
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, ErrInvalidJSONPath
+	}
+
+	var steps []jsonPathStep
+
+	for i := 1; i < len(path); {
+		switch path[i] {
+		case '.':
+			{
+				end := i + 1
+				for end < len(path) && path[end] != '.' && path[end] != '[' {
+					end++
+				}
+
+				if end == i+1 {
+					return nil, ErrInvalidJSONPath
+				}
+
+				steps = append(steps, jsonPathStep{key: path[i+1 : end], index: -1})
+				i = end
+			}
+		case '[':
+			{
+				end := strings.IndexByte(path[i:], ']')
+				if end < 0 {
+					return nil, ErrInvalidJSONPath
+				}
+
+				index, err := strconv.ParseUint(path[i+1:i+end], 10, 31)
+				if err != nil {
+					return nil, ErrInvalidJSONPath
+				}
+
+				steps = append(steps, jsonPathStep{index: int(index)})
+				i += end + 1
+			}
+		default:
+			return nil, ErrInvalidJSONPath
+		}
+	}
+
+	return steps, nil
+}
+
+// extractJSONField returns the field of the document referred by the path, if any
+func extractJSONField(doc string, path []jsonPathStep) (field interface{}, found bool, err error) {
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+
+	err = dec.Decode(&field)
+	if err != nil {
+		return nil, false, ErrInvalidJSON
+	}
+
+	for _, step := range path {
+		if step.index < 0 {
+			obj, ok := field.(map[string]interface{})
+			if !ok {
+				return nil, false, nil
+			}
+
+			field, ok = obj[step.key]
+			if !ok {
+				return nil, false, nil
+			}
+
+			continue
+		}
+
+		arr, ok := field.([]interface{})
+		if !ok || step.index >= len(arr) {
+			return nil, false, nil
+		}
+
+		field = arr[step.index]
+	}
+
+	return field, true, nil
+}
+
+// jsonScalarAs converts a scalar JSON value into a value of the given type,
+// nil is returned for JSON nulls, objects, arrays and values that can not be converted
+func jsonScalarAs(field interface{}, t SQLValueType) TypedValue {
+	switch t {
+	case VarcharType:
+		{
+			switch v := field.(type) {
+			case string:
+				return &Varchar{val: v}
+			case json.Number:
+				return &Varchar{val: v.String()}
+			case bool:
+				return &Varchar{val: strconv.FormatBool(v)}
+			}
+		}
+	case IntegerType:
+		{
+			n, ok := field.(json.Number)
+			if !ok {
+				return nil
+			}
+
+			v, err := strconv.ParseUint(n.String(), 10, 64)
+			if err != nil {
+				return nil
+			}
+
+			return &Number{val: v}
+		}
+	case BooleanType:
+		{
+			v, ok := field.(bool)
+			if !ok {
+				return nil
+			}
+
+			return &Bool{val: v}
+		}
+	}
+
+	return nil
+}
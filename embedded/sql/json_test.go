@@ -0,0 +1,100 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	path, err := parseJSONPath("$")
+	require.NoError(t, err)
+	require.Empty(t, path)
+
+	path, err = parseJSONPath("$.address.lines[1].text")
+	require.NoError(t, err)
+	require.Equal(t, []jsonPathStep{
+		{key: "address", index: -1},
+		{key: "lines", index: -1},
+		{index: 1},
+		{key: "text", index: -1},
+	}, path)
+
+	for _, p := range []string{"", "address", "$address", "$.", "$..address", "$[", "$[a]", "$[-1]"} {
+		_, err = parseJSONPath(p)
+		require.ErrorIs(t, err, ErrInvalidJSONPath, p)
+	}
+}
+
+func TestExtractJSONField(t *testing.T) {
+	doc := `{"name": "immudb", "tags": ["sql", "kv"], "stars": 5, "address": null}`
+
+	extract := func(p string) (interface{}, bool) {
+		path, err := parseJSONPath(p)
+		require.NoError(t, err)
+
+		field, found, err := extractJSONField(doc, path)
+		require.NoError(t, err)
+
+		return field, found
+	}
+
+	field, found := extract("$.name")
+	require.True(t, found)
+	require.Equal(t, "immudb", field)
+
+	field, found = extract("$.tags[1]")
+	require.True(t, found)
+	require.Equal(t, "kv", field)
+
+	field, found = extract("$.stars")
+	require.True(t, found)
+	require.Equal(t, json.Number("5"), field)
+
+	field, found = extract("$.address")
+	require.True(t, found)
+	require.Nil(t, field)
+
+	for _, p := range []string{"$.missing", "$.tags[2]", "$.name.first", "$[0]"} {
+		_, found = extract(p)
+		require.False(t, found, p)
+	}
+
+	_, _, err := extractJSONField("{", nil)
+	require.ErrorIs(t, err, ErrInvalidJSON)
+}
+
+func TestJSONScalarAs(t *testing.T) {
+	require.Equal(t, &Varchar{val: "immudb"}, jsonScalarAs("immudb", VarcharType))
+	require.Equal(t, &Varchar{val: "1.5"}, jsonScalarAs(json.Number("1.5"), VarcharType))
+	require.Equal(t, &Varchar{val: "true"}, jsonScalarAs(true, VarcharType))
+	require.Nil(t, jsonScalarAs(nil, VarcharType))
+	require.Nil(t, jsonScalarAs([]interface{}{}, VarcharType))
+
+	require.Equal(t, &Number{val: 5}, jsonScalarAs(json.Number("5"), IntegerType))
+	require.Nil(t, jsonScalarAs(json.Number("-5"), IntegerType))
+	require.Nil(t, jsonScalarAs(json.Number("1.5"), IntegerType))
+	require.Nil(t, jsonScalarAs("5", IntegerType))
+
+	require.Equal(t, &Bool{val: false}, jsonScalarAs(false, BooleanType))
+	require.Nil(t, jsonScalarAs("false", BooleanType))
+
+	require.Nil(t, jsonScalarAs("immudb", BLOBType))
+}
@@ -70,8 +70,11 @@ var reservedWords = map[string]int{
 	"LIKE":           LIKE,
 	"EXISTS":         EXISTS,
 	"AUTO_INCREMENT": AUTO_INCREMENT,
+	"IDENTITY":       IDENTITY,
 	"NULL":           NULL,
+	"UNIQUE":         UNIQUE,
 	"IF":             IF,
+	"EXPLAIN":        EXPLAIN,
 }
 
 var joinTypes = map[string]JoinType{
@@ -86,6 +89,7 @@ var types = map[string]SQLValueType{
 	"VARCHAR":   VarcharType,
 	"BLOB":      BLOBType,
 	"TIMESTAMP": TimestampType,
+	"JSON":      JSONType,
 }
 
 var aggregateFns = map[string]AggregateFn{
@@ -96,6 +100,11 @@ var aggregateFns = map[string]AggregateFn{
 	"AVG":   AVG,
 }
 
+var jsonFns = map[string]JSONFn{
+	"JSON_EXTRACT": JSONExtract,
+	"JSON_VALUE":   JSONValue,
+}
+
 var boolValues = map[string]bool{
 	"TRUE":  true,
 	"FALSE": false,
@@ -161,13 +170,16 @@ func (ar *aheadByteReader) NextByte() (byte, error) {
 	return ar.nextChar, ar.nextErr
 }
 
+func init() {
+	yyErrorVerbose = true
+}
+
 func ParseString(sql string) ([]SQLStmt, error) {
 	return Parse(strings.NewReader(sql))
 }
 
 func Parse(r io.ByteReader) ([]SQLStmt, error) {
 	lexer := newLexer(r)
-	yyErrorVerbose = true
 
 	yyParse(lexer)
 
@@ -297,6 +309,12 @@ func (l *lexer) Lex(lval *yySymType) int {
 			return AGGREGATE_FUNC
 		}
 
+		jfn, ok := jsonFns[tid]
+		if ok {
+			lval.jsonFn = jfn
+			return JSON_FUNC
+		}
+
 		join, ok := joinTypes[tid]
 		if ok {
 			lval.joinType = join
@@ -350,14 +368,12 @@ func (l *lexer) Lex(lval *yySymType) int {
 	}
 
 	if isQuote(ch) {
-		tail, err := l.readString()
+		tail, err := l.readQuotedString()
 		if err != nil {
 			lval.err = err
 			return ERROR
 		}
 
-		l.r.ReadByte() // consume closing quote
-
 		lval.str = tail
 		return VARCHAR
 	}
@@ -450,6 +466,31 @@ func (l *lexer) readString() (string, error) {
 	})
 }
 
+// readQuotedString reads a string up to its closing quote, which gets consumed.
+// A quote within the string is escaped by doubling it
+func (l *lexer) readQuotedString() (string, error) {
+	var b bytes.Buffer
+
+	for {
+		tail, err := l.readString()
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(tail)
+
+		l.r.ReadByte() // consume closing quote
+
+		ch, err := l.r.NextByte()
+		if err != nil || !isQuote(ch) {
+			return b.String(), nil
+		}
+
+		l.r.ReadByte() // consume escaped quote
+		b.WriteByte(ch)
+	}
+}
+
 func (l *lexer) readComparison() (string, error) {
 	return l.readWhile(func(ch byte) bool {
 		return isComparison(ch)
@@ -143,6 +143,17 @@ func TestCreateTableStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "CREATE TABLE table1 (id INTEGER IDENTITY, PRIMARY KEY id)",
+			expectedOutput: []SQLStmt{
+				&CreateTableStmt{
+					table:       "table1",
+					ifNotExists: false,
+					colsSpec:    []*ColSpec{{colName: "id", colType: IntegerType, autoIncrement: true}},
+					pk:          "id",
+				}},
+			expectedError: nil,
+		},
 		{
 			input: "CREATE TABLE xtable1 (xid INTEGER, PRIMARY KEY xid)",
 			expectedOutput: []SQLStmt{
@@ -182,6 +193,21 @@ func TestCreateTableStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "CREATE TABLE table1 (id INTEGER, email VARCHAR NOT NULL UNIQUE, name VARCHAR UNIQUE, PRIMARY KEY id)",
+			expectedOutput: []SQLStmt{
+				&CreateTableStmt{
+					table:       "table1",
+					ifNotExists: false,
+					colsSpec: []*ColSpec{
+						{colName: "id", colType: IntegerType},
+						{colName: "email", colType: VarcharType, notNull: true, unique: true},
+						{colName: "name", colType: VarcharType, unique: true},
+					},
+					pk: "id",
+				}},
+			expectedError: nil,
+		},
 		{
 			input:          "CREATE table1",
 			expectedOutput: nil,
@@ -408,6 +434,24 @@ func TestInsertIntoStmt(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			input: "UPSERT INTO table1(id, title, note) VALUES (2, 'it''s untitled', '''')",
+			expectedOutput: []SQLStmt{
+				&UpsertIntoStmt{
+					tableRef: &TableRef{table: "table1"},
+					cols:     []string{"id", "title", "note"},
+					rows: []*RowSpec{
+						{Values: []ValueExp{
+							&Number{val: 2},
+							&Varchar{val: "it's untitled"},
+							&Varchar{val: "'"},
+						},
+						},
+					},
+				},
+			},
+			expectedError: nil,
+		},
 		{
 			input:          "UPSERT INTO table1() VALUES (2, 'untitled')",
 			expectedOutput: nil,
@@ -667,6 +711,32 @@ func TestSelectStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "SELECT id, title FROM table1 BEFORE TX 10",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					distinct: false,
+					selectors: []Selector{
+						&ColSelector{col: "id"},
+						&ColSelector{col: "title"},
+					},
+					ds: &TableRef{table: "table1", asBefore: 10},
+				}},
+			expectedError: nil,
+		},
+		{
+			input: "SELECT t1.id, title FROM (db1.table1 BEFORE TX 10 AS t1)",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					distinct: false,
+					selectors: []Selector{
+						&ColSelector{table: "t1", col: "id"},
+						&ColSelector{col: "title"},
+					},
+					ds: &TableRef{db: "db1", table: "table1", asBefore: 10, as: "t1"},
+				}},
+			expectedError: nil,
+		},
 		{
 			input: "SELECT db1.table1.id, title FROM (db1.table1 AS t1) WHERE payload >= x'AED0393F'",
 			expectedOutput: []SQLStmt{
@@ -824,6 +894,36 @@ func TestSelectStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "SELECT id, table2.status FROM table1 JOIN table2 ON table1.id = table2.fid",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					distinct: false,
+					selectors: []Selector{
+						&ColSelector{col: "id"},
+						&ColSelector{table: "table2", col: "status"},
+					},
+					ds: &TableRef{table: "table1"},
+					joins: []*JoinSpec{
+						{
+							joinType: InnerJoin,
+							ds:       &TableRef{table: "table2"},
+							cond: &CmpBoolExp{
+								op: EQ,
+								left: &ColSelector{
+									table: "table1",
+									col:   "id",
+								},
+								right: &ColSelector{
+									table: "table2",
+									col:   "fid",
+								},
+							},
+						},
+					},
+				}},
+			expectedError: nil,
+		},
 		{
 			input: "SELECT id, title FROM (SELECT col1 AS id, col2 AS title FROM table2 LIMIT 100) LIMIT 10",
 			expectedOutput: []SQLStmt{
@@ -940,6 +1040,112 @@ func TestAggFnStmt(t *testing.T) {
 	}
 }
 
+func TestJSONFnStmt(t *testing.T) {
+	testCases := []struct {
+		input          string
+		expectedOutput []SQLStmt
+		expectedError  error
+	}{
+		{
+			input: "CREATE TABLE table1 (id INTEGER, doc JSON, PRIMARY KEY id)",
+			expectedOutput: []SQLStmt{
+				&CreateTableStmt{
+					table:    "table1",
+					colsSpec: []*ColSpec{{colName: "id", colType: IntegerType}, {colName: "doc", colType: JSONType}},
+					pk:       "id",
+				}},
+			expectedError: nil,
+		},
+		{
+			input: "SELECT id, JSON_EXTRACT(doc, '$.address') AS address FROM table1 WHERE JSON_VALUE(t1.doc, '$.age', INTEGER) > 18",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					selectors: []Selector{
+						&ColSelector{col: "id"},
+						&JSONSelector{fn: JSONExtract, sel: &ColSelector{col: "doc"}, path: "$.address", as: "address"},
+					},
+					ds: &TableRef{table: "table1"},
+					where: &CmpBoolExp{
+						op:    GT,
+						left:  &JSONSelector{fn: JSONValue, sel: &ColSelector{table: "t1", col: "doc"}, path: "$.age", t: IntegerType},
+						right: &Number{val: 18},
+					},
+				}},
+			expectedError: nil,
+		},
+		{
+			input: "SELECT id FROM table1 WHERE json_value(doc, '$.tags[0]') LIKE 'immu'",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					selectors: []Selector{
+						&ColSelector{col: "id"},
+					},
+					ds: &TableRef{table: "table1"},
+					where: &LikeBoolExp{
+						sel:     &JSONSelector{fn: JSONValue, sel: &ColSelector{col: "doc"}, path: "$.tags[0]"},
+						pattern: "immu",
+					},
+				}},
+			expectedError: nil,
+		},
+		{
+			input:          "SELECT JSON_EXTRACT(doc) FROM table1",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected ')', expecting ','"),
+		},
+	}
+
+	for i, tc := range testCases {
+		res, err := ParseString(tc.input)
+		require.Equal(t, tc.expectedError, err, fmt.Sprintf("failed on iteration %d", i))
+
+		if tc.expectedError == nil {
+			require.Equal(t, tc.expectedOutput, res, fmt.Sprintf("failed on iteration %d", i))
+		}
+	}
+}
+
+func TestExplainStmt(t *testing.T) {
+	testCases := []struct {
+		input          string
+		expectedOutput []SQLStmt
+		expectedError  error
+	}{
+		{
+			input: "EXPLAIN SELECT id FROM table1 WHERE id > 0;",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					ds: &ExplainStmt{
+						stmt: &SelectStmt{
+							selectors: []Selector{&ColSelector{col: "id"}},
+							ds:        &TableRef{table: "table1"},
+							where: &CmpBoolExp{
+								op:    GT,
+								left:  &ColSelector{col: "id"},
+								right: &Number{val: 0},
+							},
+						},
+					},
+				}},
+			expectedError: nil,
+		},
+		{
+			input:          "EXPLAIN UPSERT INTO table1 (id) VALUES (1)",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected UPSERT, expecting SELECT"),
+		},
+	}
+
+	for i, tc := range testCases {
+		res, err := ParseString(tc.input)
+		require.Equal(t, tc.expectedError, err, fmt.Sprintf("failed on iteration %d", i))
+
+		if tc.expectedError == nil {
+			require.Equal(t, tc.expectedOutput, res, fmt.Sprintf("failed on iteration %d", i))
+		}
+	}
+}
+
 func TestExpressions(t *testing.T) {
 	testCases := []struct {
 		input          string
@@ -54,22 +54,14 @@ func (pr *projectedRowReader) ImplicitTable() string {
 }
 
 func (pr *projectedRowReader) Columns() ([]*ColDescriptor, error) {
-	colsBySel, err := pr.colsBySelector()
-	if err != nil {
-		return nil, err
-	}
-
 	// Special case: SELECT *
 	if len(pr.selectors) == 0 {
-		colsByPos := make([]*ColDescriptor, len(colsBySel))
-
-		i := 0
-		for _, c := range colsBySel {
-			colsByPos[i] = c
-			i++
-		}
+		return pr.rowReader.Columns()
+	}
 
-		return colsByPos, nil
+	colsBySel, err := pr.colsBySelector()
+	if err != nil {
+		return nil, err
 	}
 
 	colsByPos := make([]*ColDescriptor, len(pr.selectors))
@@ -86,7 +78,9 @@ func (pr *projectedRowReader) Columns() ([]*ColDescriptor, error) {
 			col = sel.alias()
 		}
 
-		if aggFn != "" {
+		_, isJSONSel := sel.(*JSONSelector)
+
+		if aggFn != "" || isJSONSel {
 			aggFn = ""
 			col = sel.alias()
 			if col == "" {
@@ -141,7 +135,9 @@ func (pr *projectedRowReader) colsBySelector() (map[string]*ColDescriptor, error
 			col = sel.alias()
 		}
 
-		if aggFn != "" {
+		_, isJSONSel := sel.(*JSONSelector)
+
+		if aggFn != "" || isJSONSel {
 			aggFn = ""
 			col = sel.alias()
 			if col == "" {
@@ -157,6 +153,17 @@ func (pr *projectedRowReader) colsBySelector() (map[string]*ColDescriptor, error
 			Type:     colDesc.Type,
 		}
 
+		if isJSONSel {
+			if colDesc.Type != JSONType && colDesc.Type != VarcharType {
+				return nil, ErrInvalidTypes
+			}
+
+			des.Type, err = sel.(*JSONSelector).valueType()
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		colDescriptors[des.Selector()] = des
 	}
 
@@ -212,6 +219,14 @@ func (pr *projectedRowReader) Read() (*Row, error) {
 			return nil, ErrColumnDoesNotExist
 		}
 
+		jsonSel, isJSONSel := sel.(*JSONSelector)
+		if isJSONSel {
+			val, err = jsonSel.extract(val)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		if pr.tableAlias != "" {
 			db = pr.ImplicitDB()
 			table = pr.tableAlias
@@ -221,7 +236,7 @@ func (pr *projectedRowReader) Read() (*Row, error) {
 			col = sel.alias()
 		}
 
-		if aggFn != "" {
+		if aggFn != "" || isJSONSel {
 			aggFn = ""
 			col = sel.alias()
 			if col == "" {
@@ -199,6 +199,22 @@ func (r *rawRowReader) InferParameters(params map[string]SQLValueType) error {
 func (r *rawRowReader) SetParameters(params map[string]interface{}) {
 }
 
+// readAsBefore returns the value the key had before the transaction the rows are read as of
+func (r *rawRowReader) readAsBefore(key []byte) ([]byte, error) {
+	kr, err := r.snap.NewKeyReader(&store.KeyReaderSpec{SeekKey: key, Prefix: key, InclusiveSeek: true})
+	if err != nil {
+		return nil, err
+	}
+	defer kr.Close()
+
+	_, vref, _, err := kr.ReadAsBefore(r.asBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	return vref.Resolve()
+}
+
 func (r *rawRowReader) Read() (row *Row, err error) {
 	var mkey []byte
 	var vref *store.ValueRef
@@ -226,7 +242,13 @@ func (r *rawRowReader) Read() (row *Row, err error) {
 			return nil, err
 		}
 
-		v, _, _, err = r.snap.Get(r.e.mapKey(RowPrefix, EncodeID(r.table.db.id), EncodeID(r.table.id), EncodeID(r.table.pk.id), encPKVal))
+		pkKey := r.e.mapKey(RowPrefix, EncodeID(r.table.db.id), EncodeID(r.table.id), EncodeID(r.table.pk.id), encPKVal)
+
+		if r.asBefore > 0 {
+			v, err = r.readAsBefore(pkKey)
+		} else {
+			v, _, _, err = r.snap.Get(pkKey)
+		}
 		if err != nil {
 			return nil, err
 		}
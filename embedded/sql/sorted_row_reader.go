@@ -0,0 +1,146 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sql
+
+import "sort"
+
+// sortedRowReader buffers all the rows of the underlying reader and returns them sorted by the given columns,
+// rows with NULL values come first
+type sortedRowReader struct {
+	e *Engine
+
+	rowReader RowReader
+
+	sortBy []*ColSelector
+
+	rows   []*Row
+	loaded bool
+}
+
+func (e *Engine) newSortedRowReader(rowReader RowReader, sortBy []*ColSelector) (*sortedRowReader, error) {
+	if rowReader == nil || len(sortBy) == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	return &sortedRowReader{
+		e:         e,
+		rowReader: rowReader,
+		sortBy:    sortBy,
+	}, nil
+}
+
+func (sr *sortedRowReader) ImplicitDB() string {
+	return sr.rowReader.ImplicitDB()
+}
+
+func (sr *sortedRowReader) ImplicitTable() string {
+	return sr.rowReader.ImplicitTable()
+}
+
+func (sr *sortedRowReader) SetParameters(params map[string]interface{}) {
+	sr.rowReader.SetParameters(params)
+}
+
+func (sr *sortedRowReader) Columns() ([]*ColDescriptor, error) {
+	return sr.rowReader.Columns()
+}
+
+func (sr *sortedRowReader) colsBySelector() (map[string]*ColDescriptor, error) {
+	return sr.rowReader.colsBySelector()
+}
+
+func (sr *sortedRowReader) InferParameters(params map[string]SQLValueType) error {
+	return sr.rowReader.InferParameters(params)
+}
+
+func (sr *sortedRowReader) Read() (*Row, error) {
+	if !sr.loaded {
+		err := sr.load()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(sr.rows) == 0 {
+		return nil, ErrNoMoreRows
+	}
+
+	row := sr.rows[0]
+	sr.rows[0] = nil
+	sr.rows = sr.rows[1:]
+
+	return row, nil
+}
+
+func (sr *sortedRowReader) load() error {
+	for {
+		row, err := sr.rowReader.Read()
+		if err == ErrNoMoreRows {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, sel := range sr.sortBy {
+			_, ok := row.Values[EncodeSelector(sel.resolve(sr.ImplicitDB(), sr.ImplicitTable()))]
+			if !ok {
+				return ErrInvalidColumn
+			}
+		}
+
+		sr.rows = append(sr.rows, row)
+	}
+
+	var err error
+
+	sort.SliceStable(sr.rows, func(i, j int) bool {
+		cmp, cmpErr := sr.compare(sr.rows[i], sr.rows[j])
+		if cmpErr != nil && err == nil {
+			err = cmpErr
+		}
+
+		return cmp < 0
+	})
+	if err != nil {
+		return err
+	}
+
+	sr.loaded = true
+
+	return nil
+}
+
+func (sr *sortedRowReader) compare(row1, row2 *Row) (int, error) {
+	for _, sel := range sr.sortBy {
+		c := EncodeSelector(sel.resolve(sr.ImplicitDB(), sr.ImplicitTable()))
+
+		cmp, err := row1.Values[c].Compare(row2.Values[c])
+		if err != nil {
+			return 0, err
+		}
+
+		if cmp != 0 {
+			return cmp, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (sr *sortedRowReader) Close() error {
+	return sr.rowReader.Close()
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sql
+
+import (
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortedRowReader(t *testing.T) {
+	catalogStore, err := store.Open("catalog_sorted_reader", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("catalog_sorted_reader")
+
+	dataStore, err := store.Open("sqldata_sorted_reader", store.DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("sqldata_sorted_reader")
+
+	engine, err := NewEngine(catalogStore, dataStore, prefix)
+	require.NoError(t, err)
+
+	_, err = engine.newSortedRowReader(nil, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = engine.ExecStmt("CREATE DATABASE db1", nil, true)
+	require.NoError(t, err)
+
+	err = engine.UseDatabase("db1")
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("CREATE TABLE table1 (id INTEGER, title VARCHAR, PRIMARY KEY id)", nil, true)
+	require.NoError(t, err)
+
+	_, err = engine.ExecStmt("UPSERT INTO table1 (id, title) VALUES (1, 'c'), (2, 'a'), (3, NULL), (4, 'b'), (5, 'a')", nil, true)
+	require.NoError(t, err)
+
+	r, err := engine.QueryStmt("SELECT id, title FROM table1", nil, true)
+	require.NoError(t, err)
+
+	sr, err := engine.newSortedRowReader(r, []*ColSelector{{col: "title"}})
+	require.NoError(t, err)
+
+	cols, err := sr.Columns()
+	require.NoError(t, err)
+	require.Len(t, cols, 2)
+
+	// NULL values come first, rows with equal values keep their order
+	for _, id := range []uint64{3, 2, 5, 4, 1} {
+		row, err := sr.Read()
+		require.NoError(t, err)
+		require.Equal(t, id, row.Values[EncodeSelector("", "db1", "table1", "id")].Value())
+	}
+
+	_, err = sr.Read()
+	require.Equal(t, ErrNoMoreRows, err)
+
+	err = sr.Close()
+	require.NoError(t, err)
+
+	r, err = engine.QueryStmt("SELECT id FROM table1", nil, true)
+	require.NoError(t, err)
+
+	sr, err = engine.newSortedRowReader(r, []*ColSelector{{col: "title"}})
+	require.NoError(t, err)
+
+	_, err = sr.Read()
+	require.Equal(t, ErrInvalidColumn, err)
+
+	err = sr.Close()
+	require.NoError(t, err)
+
+	err = engine.Close()
+	require.NoError(t, err)
+}
@@ -41,6 +41,7 @@ func setResult(l yyLexer, stmts []SQLStmt) {
     blob []byte
     sqlType SQLValueType
     aggFn AggregateFn
+    jsonFn JSONFn
     ids []string
     col *ColSelector
     sel Selector
@@ -65,8 +66,9 @@ func setResult(l yyLexer, stmts []SQLStmt) {
 %token BEGIN TRANSACTION COMMIT
 %token INSERT UPSERT INTO VALUES
 %token SELECT DISTINCT FROM BEFORE TX JOIN HAVING WHERE GROUP BY LIMIT ORDER ASC DESC AS
+%token EXPLAIN
 %token NOT LIKE IF EXISTS
-%token AUTO_INCREMENT NULL NPARAM
+%token AUTO_INCREMENT IDENTITY NULL UNIQUE NPARAM
 %token <pparam> PPARAM
 %token <joinType> JOINTYPE
 %token <logicOp> LOP
@@ -78,6 +80,7 @@ func setResult(l yyLexer, stmts []SQLStmt) {
 %token <boolean> BOOLEAN
 %token <blob> BLOB
 %token <aggFn> AGGREGATE_FUNC
+%token <jsonFn> JSON_FUNC
 %token <err> ERROR
 
 %left  ','
@@ -118,7 +121,7 @@ func setResult(l yyLexer, stmts []SQLStmt) {
 %type <id> opt_as
 %type <ordcols> ordcols opt_orderby
 %type <opt_ord> opt_ord
-%type <boolean> opt_if_not_exists opt_auto_increment opt_not_null
+%type <boolean> opt_if_not_exists opt_auto_increment opt_not_null opt_unique
 
 %start sql
     
@@ -140,6 +143,11 @@ sqlstmts:
     {
         $$ = []SQLStmt{$1}
     }
+|
+    EXPLAIN dqlstmt opt_separator
+    {
+        $$ = []SQLStmt{&SelectStmt{ds: &ExplainStmt{stmt: ($2).(*SelectStmt)}}}
+    }
 |
     sqlstmt STMT_SEPARATOR sqlstmts
     {
@@ -337,9 +345,9 @@ colsSpec:
     }
 
 colSpec:
-    IDENTIFIER TYPE opt_auto_increment opt_not_null
+    IDENTIFIER TYPE opt_auto_increment opt_not_null opt_unique
     {
-        $$ = &ColSpec{colName: $1, colType: $2, autoIncrement: $3, notNull: $4}
+        $$ = &ColSpec{colName: $1, colType: $2, autoIncrement: $3, notNull: $4, unique: $5}
     }
 
 opt_auto_increment:
@@ -351,6 +359,11 @@ opt_auto_increment:
     {
         $$ = true
     }
+|
+    IDENTITY
+    {
+        $$ = true
+    }
 
 opt_not_null:
     {
@@ -367,6 +380,16 @@ opt_not_null:
         $$ = true
     }
 
+opt_unique:
+    {
+        $$ = false
+    }
+|
+    UNIQUE
+    {
+        $$ = true
+    }
+
 dqlstmt:
     SELECT opt_distinct opt_selectors FROM ds opt_joins opt_where opt_groupby opt_having opt_orderby opt_limit opt_as
     {
@@ -433,6 +456,16 @@ selector:
     {
         $$ = &AggColSelector{aggFn: $1, db: $3.db, table: $3.table, col: $3.col}
     }
+|
+    JSON_FUNC '(' col ',' VARCHAR ')'
+    {
+        $$ = &JSONSelector{fn: $1, sel: $3, path: $5}
+    }
+|
+    JSON_FUNC '(' col ',' VARCHAR ',' TYPE ')'
+    {
+        $$ = &JSONSelector{fn: $1, sel: $3, path: $5, t: $7}
+    }
 
 col:
     IDENTIFIER
@@ -455,6 +488,12 @@ ds:
     {
         $$ = $1
     }
+|
+    tableRef BEFORE TX NUMBER
+    {
+        $1.asBefore = $4
+        $$ = $1
+    }
 |
     '(' tableRef opt_as_before opt_as ')'
     {
@@ -515,6 +554,11 @@ join:
     {
         $$ = &JoinSpec{joinType: $1, ds: $3, cond: $5}
     }
+|
+    JOIN ds ON boolExp
+    {
+        $$ = &JoinSpec{joinType: InnerJoin, ds: $2, cond: $4}
+    }
 
 opt_where:
     {
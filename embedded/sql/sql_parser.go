@@ -27,6 +27,7 @@ type yySymType struct {
 	blob     []byte
 	sqlType  SQLValueType
 	aggFn    AggregateFn
+	jsonFn   JSONFn
 	ids      []string
 	col      *ColSelector
 	sel      Selector
@@ -84,26 +85,30 @@ const ORDER = 57379
 const ASC = 57380
 const DESC = 57381
 const AS = 57382
-const NOT = 57383
-const LIKE = 57384
-const IF = 57385
-const EXISTS = 57386
-const AUTO_INCREMENT = 57387
-const NULL = 57388
-const NPARAM = 57389
-const PPARAM = 57390
-const JOINTYPE = 57391
-const LOP = 57392
-const CMPOP = 57393
-const IDENTIFIER = 57394
-const TYPE = 57395
-const NUMBER = 57396
-const VARCHAR = 57397
-const BOOLEAN = 57398
-const BLOB = 57399
-const AGGREGATE_FUNC = 57400
-const ERROR = 57401
-const STMT_SEPARATOR = 57402
+const EXPLAIN = 57383
+const NOT = 57384
+const LIKE = 57385
+const IF = 57386
+const EXISTS = 57387
+const AUTO_INCREMENT = 57388
+const IDENTITY = 57389
+const NULL = 57390
+const UNIQUE = 57391
+const NPARAM = 57392
+const PPARAM = 57393
+const JOINTYPE = 57394
+const LOP = 57395
+const CMPOP = 57396
+const IDENTIFIER = 57397
+const TYPE = 57398
+const NUMBER = 57399
+const VARCHAR = 57400
+const BOOLEAN = 57401
+const BLOB = 57402
+const AGGREGATE_FUNC = 57403
+const JSON_FUNC = 57404
+const ERROR = 57405
+const STMT_SEPARATOR = 57406
 
 var yyToknames = [...]string{
 	"$end",
@@ -146,12 +151,15 @@ var yyToknames = [...]string{
 	"ASC",
 	"DESC",
 	"AS",
+	"EXPLAIN",
 	"NOT",
 	"LIKE",
 	"IF",
 	"EXISTS",
 	"AUTO_INCREMENT",
+	"IDENTITY",
 	"NULL",
+	"UNIQUE",
 	"NPARAM",
 	"PPARAM",
 	"JOINTYPE",
@@ -164,6 +172,7 @@ var yyToknames = [...]string{
 	"BOOLEAN",
 	"BLOB",
 	"AGGREGATE_FUNC",
+	"JSON_FUNC",
 	"ERROR",
 	"','",
 	"'+'",
@@ -190,146 +199,156 @@ var yyExca = [...]int{
 
 const yyPrivate = 57344
 
-const yyLast = 255
+const yyLast = 279
 
 var yyAct = [...]int{
-	208, 37, 56, 145, 122, 4, 124, 144, 99, 71,
-	63, 90, 126, 72, 85, 129, 194, 137, 135, 136,
-	39, 200, 105, 134, 193, 130, 131, 132, 133, 38,
-	106, 199, 105, 127, 155, 156, 76, 188, 128, 97,
-	104, 165, 48, 50, 156, 151, 152, 154, 153, 116,
-	155, 156, 186, 112, 151, 152, 154, 153, 49, 96,
-	77, 151, 152, 154, 153, 162, 59, 68, 162, 151,
-	152, 154, 153, 73, 146, 59, 161, 95, 81, 94,
-	79, 69, 67, 58, 88, 53, 18, 93, 16, 154,
-	153, 39, 207, 103, 198, 170, 114, 38, 55, 137,
-	135, 136, 34, 109, 111, 173, 39, 130, 131, 132,
-	133, 185, 38, 203, 139, 123, 102, 83, 5, 31,
-	138, 115, 7, 141, 39, 190, 163, 147, 140, 118,
-	113, 158, 159, 160, 100, 101, 36, 86, 32, 87,
-	78, 75, 62, 60, 49, 47, 44, 40, 49, 92,
-	169, 191, 178, 172, 176, 168, 179, 180, 181, 182,
-	183, 184, 143, 100, 80, 42, 157, 187, 61, 189,
-	57, 74, 32, 70, 192, 209, 210, 175, 196, 197,
-	150, 121, 15, 108, 149, 110, 82, 17, 65, 64,
-	54, 21, 10, 11, 7, 119, 117, 29, 202, 205,
-	206, 201, 12, 10, 11, 28, 51, 6, 166, 211,
-	13, 14, 212, 12, 7, 52, 19, 2, 84, 66,
-	22, 13, 14, 164, 43, 23, 24, 27, 46, 25,
-	26, 167, 142, 41, 30, 174, 204, 195, 120, 125,
-	148, 107, 91, 89, 45, 20, 35, 33, 171, 177,
-	98, 9, 8, 3, 1,
+	40, 230, 136, 134, 60, 162, 161, 4, 108, 76,
+	68, 138, 96, 20, 141, 77, 91, 149, 222, 147,
+	148, 43, 221, 209, 146, 216, 142, 143, 144, 145,
+	41, 42, 207, 215, 155, 139, 172, 173, 81, 183,
+	140, 64, 154, 179, 172, 173, 52, 54, 168, 169,
+	171, 170, 114, 114, 173, 205, 168, 169, 171, 170,
+	115, 113, 53, 82, 83, 168, 169, 171, 170, 128,
+	123, 104, 179, 168, 169, 171, 170, 163, 78, 229,
+	178, 87, 85, 74, 103, 72, 102, 63, 62, 57,
+	43, 94, 19, 17, 101, 106, 41, 42, 73, 112,
+	171, 170, 37, 64, 149, 220, 147, 148, 189, 120,
+	118, 192, 122, 142, 143, 144, 145, 126, 105, 43,
+	204, 59, 135, 124, 225, 41, 42, 153, 152, 150,
+	6, 34, 111, 89, 184, 157, 127, 43, 164, 210,
+	156, 180, 175, 176, 177, 39, 130, 125, 109, 110,
+	8, 92, 35, 93, 84, 182, 80, 67, 65, 53,
+	51, 48, 44, 99, 212, 213, 191, 86, 197, 46,
+	195, 174, 198, 199, 200, 201, 202, 203, 109, 53,
+	66, 188, 79, 61, 98, 208, 206, 187, 35, 75,
+	159, 160, 11, 12, 194, 214, 231, 232, 218, 219,
+	167, 133, 13, 16, 117, 166, 119, 7, 18, 121,
+	14, 15, 88, 70, 8, 69, 100, 58, 23, 223,
+	227, 228, 224, 8, 33, 131, 11, 12, 129, 5,
+	233, 31, 30, 55, 21, 234, 13, 185, 2, 56,
+	90, 71, 24, 181, 14, 15, 151, 25, 26, 29,
+	47, 50, 27, 28, 211, 186, 32, 158, 45, 193,
+	226, 217, 132, 137, 165, 116, 97, 95, 49, 22,
+	38, 36, 190, 196, 107, 10, 9, 3, 1,
 }
 
 var yyPact = [...]int{
-	188, -1000, -1000, 22, 20, -1000, 196, 164, -1000, -1000,
-	214, 223, 216, 181, 173, -1000, 188, -1000, -1000, 199,
-	39, -1000, 95, 122, 211, 94, 220, 93, 92, 92,
-	-1000, 185, 19, 162, -1000, 38, 130, -1000, 16, 10,
-	-1000, 91, 127, 90, -1000, 160, 158, 204, 15, 2,
-	14, -1000, -1000, 199, 6, 54, -1000, 89, -32, 88,
-	13, 120, 11, -1000, 156, 63, 202, 85, 87, 85,
-	-1000, 100, -1000, 96, 130, -1000, -1000, -9, -26, 82,
-	-1000, 83, 62, -1000, 82, -28, -1000, -1000, -38, 150,
-	-1000, 100, 154, 160, -15, -1000, -1000, 78, 36, -1000,
-	68, -19, -1000, -1000, 171, 77, 170, 147, -29, -1000,
-	6, 130, -1000, -1000, 111, 117, -1000, 7, -1000, 7,
-	152, 145, 0, 124, -1000, -1000, -29, -29, -29, 9,
-	-1000, -1000, -1000, -1000, 1, 74, -1000, -1000, 210, -27,
-	190, -1000, 109, -1000, 35, -1000, 53, 35, 140, -29,
-	72, -29, -29, -29, -29, -29, -29, 56, -7, 26,
-	-16, 168, -31, -1000, -29, -1000, 73, -1000, -1000, 105,
-	7, -44, -1000, -2, 142, 144, 0, 34, -1000, 26,
-	26, -1000, -1000, -7, 8, -1000, -1000, -37, -1000, 0,
-	-47, -1000, -1000, -1000, 53, 130, 59, 72, 72, -1000,
-	-1000, -1000, -1000, -1000, 32, 137, -1000, 72, -1000, -1000,
-	-1000, 137, -1000,
+	188, -1000, -1000, 23, 22, 197, -1000, 214, 191, -1000,
+	-1000, 236, 246, 238, 208, 207, -1000, 188, -1000, -1000,
+	22, 222, 35, -1000, 107, 125, 237, 106, 243, 105,
+	104, 104, -1000, -1000, 212, 19, 189, -1000, 57, 143,
+	-1000, 17, 16, 34, -1000, 103, 138, 102, -1000, 186,
+	183, 226, 14, 29, 12, -1000, -1000, 222, 7, 64,
+	-1000, 101, -34, 82, 99, 11, 122, 10, -1000, 182,
+	76, 224, 96, 98, 96, -1000, 132, 187, 124, 143,
+	-1000, -1000, -1, 54, 26, 93, -1000, 94, 75, -1000,
+	93, -11, -1000, -1000, -12, 171, -1000, 132, 175, 7,
+	179, 186, -2, -1000, -1000, 65, 92, 53, -1000, 80,
+	-3, -1000, -1000, 203, 91, 200, 167, -31, -1000, 7,
+	233, 71, 143, -1000, -30, -1000, 123, 144, -1000, 6,
+	-1000, 6, 173, 165, -9, 128, -1000, -1000, -31, -31,
+	-31, 9, -1000, -1000, -1000, -1000, -28, 86, -1000, -1000,
+	230, -31, -1000, -33, -1000, 78, 219, -1000, 139, -1000,
+	-1000, 44, -1000, 56, 44, 157, -31, 82, -31, -31,
+	-31, -31, -31, -31, 62, 0, 33, -17, 197, -40,
+	-1000, -31, -9, -1000, -49, 84, 115, -1000, 117, 6,
+	-39, -1000, 1, 162, 164, -9, 41, -1000, 33, 33,
+	-1000, -1000, 0, 8, -1000, -1000, -50, -1000, -9, -1000,
+	-54, -1000, -1000, -1000, -1000, -1000, 56, 143, 67, 82,
+	82, -1000, -1000, -1000, -1000, -1000, 15, 158, -1000, 82,
+	-1000, -1000, -1000, 158, -1000,
 }
 
 var yyPgo = [...]int{
-	0, 254, 217, 119, 253, 118, 252, 251, 5, 250,
-	8, 14, 249, 7, 3, 248, 6, 115, 247, 246,
-	1, 245, 9, 13, 244, 10, 243, 11, 242, 4,
-	241, 240, 239, 238, 237, 2, 236, 235, 0, 233,
-	232, 231, 182,
+	0, 278, 238, 131, 277, 130, 276, 275, 7, 274,
+	8, 16, 273, 6, 5, 272, 2, 122, 271, 270,
+	0, 269, 9, 15, 268, 10, 267, 12, 266, 3,
+	265, 264, 263, 262, 261, 4, 260, 259, 1, 258,
+	257, 255, 254, 203,
 }
 
 var yyR1 = [...]int{
-	0, 1, 2, 2, 2, 42, 42, 4, 4, 5,
-	5, 3, 3, 6, 6, 6, 6, 6, 6, 24,
-	24, 39, 39, 7, 7, 13, 13, 14, 11, 11,
-	12, 12, 15, 15, 16, 16, 16, 16, 16, 16,
-	16, 16, 9, 9, 10, 40, 40, 41, 41, 41,
-	8, 21, 21, 18, 18, 19, 19, 17, 17, 17,
-	20, 20, 20, 22, 22, 22, 23, 23, 25, 25,
-	26, 26, 27, 27, 28, 30, 30, 33, 33, 31,
-	31, 34, 34, 37, 37, 36, 36, 38, 38, 38,
-	35, 35, 29, 29, 29, 29, 29, 29, 29, 29,
-	32, 32, 32, 32, 32, 32,
+	0, 1, 2, 2, 2, 2, 43, 43, 4, 4,
+	5, 5, 3, 3, 6, 6, 6, 6, 6, 6,
+	24, 24, 39, 39, 7, 7, 13, 13, 14, 11,
+	11, 12, 12, 15, 15, 16, 16, 16, 16, 16,
+	16, 16, 16, 9, 9, 10, 40, 40, 40, 41,
+	41, 41, 42, 42, 8, 21, 21, 18, 18, 19,
+	19, 17, 17, 17, 17, 17, 20, 20, 20, 22,
+	22, 22, 22, 23, 23, 25, 25, 26, 26, 27,
+	27, 28, 28, 30, 30, 33, 33, 31, 31, 34,
+	34, 37, 37, 36, 36, 38, 38, 38, 35, 35,
+	29, 29, 29, 29, 29, 29, 29, 29, 32, 32,
+	32, 32, 32, 32,
 }
 
 var yyR2 = [...]int{
-	0, 1, 2, 2, 3, 0, 1, 1, 4, 1,
-	1, 2, 3, 3, 3, 4, 11, 7, 6, 0,
-	3, 0, 3, 8, 8, 1, 3, 3, 1, 3,
-	1, 3, 1, 3, 1, 1, 1, 1, 3, 2,
-	1, 1, 1, 3, 4, 0, 1, 0, 1, 2,
-	12, 0, 1, 1, 1, 2, 4, 1, 3, 4,
-	1, 3, 5, 1, 5, 3, 1, 3, 0, 3,
-	0, 1, 1, 2, 5, 0, 2, 0, 3, 0,
-	2, 0, 2, 0, 3, 2, 4, 0, 1, 1,
-	0, 2, 1, 1, 1, 2, 2, 3, 3, 4,
-	3, 3, 3, 3, 3, 3,
+	0, 1, 2, 2, 3, 3, 0, 1, 1, 4,
+	1, 1, 2, 3, 3, 3, 4, 11, 7, 6,
+	0, 3, 0, 3, 8, 8, 1, 3, 3, 1,
+	3, 1, 3, 1, 3, 1, 1, 1, 1, 3,
+	2, 1, 1, 1, 3, 5, 0, 1, 1, 0,
+	1, 2, 0, 1, 12, 0, 1, 1, 1, 2,
+	4, 1, 3, 4, 6, 8, 1, 3, 5, 1,
+	4, 5, 3, 1, 3, 0, 3, 0, 1, 1,
+	2, 5, 4, 0, 2, 0, 3, 0, 2, 0,
+	2, 0, 3, 2, 4, 0, 1, 1, 0, 2,
+	1, 1, 1, 2, 2, 3, 3, 4, 3, 3,
+	3, 3, 3, 3,
 }
 
 var yyChk = [...]int{
-	-1000, -1, -2, -4, -8, -5, 19, 26, -6, -7,
-	4, 5, 14, 22, 23, -42, 66, -42, 66, 20,
-	-21, 27, 6, 11, 12, 6, 7, 11, 24, 24,
-	-2, -3, -5, -18, 63, -19, -17, -20, 58, 52,
-	52, -39, 43, 13, 52, -24, 8, 52, -23, 52,
-	-23, 21, -42, 66, 28, 60, -35, 40, 67, 65,
-	52, 41, 52, -25, 29, 30, 15, 67, 65, 67,
-	-3, -22, -23, 67, -17, 52, 68, -20, 52, 67,
-	44, 67, 30, 54, 16, -11, 52, 52, -11, -26,
-	-27, -28, 49, -23, -8, -35, 68, 65, -9, -10,
-	52, 52, 54, -10, 68, 60, 68, -30, 33, -27,
-	31, -25, 68, 52, 60, 53, 68, 25, 52, 25,
-	-33, 34, -29, -17, -16, -32, 41, 62, 67, 44,
-	54, 55, 56, 57, 52, 47, 48, 46, -22, -35,
-	17, -10, -40, 45, -13, -14, 67, -13, -31, 32,
-	35, 61, 62, 64, 63, 50, 51, 42, -29, -29,
-	-29, 67, 67, 52, 13, 68, 18, -41, 46, 41,
-	60, -15, -16, 52, -37, 37, -29, -12, -20, -29,
-	-29, -29, -29, -29, -29, 55, 68, -8, 68, -29,
-	52, 46, -14, 68, 60, -34, 36, 35, 60, 68,
-	68, -16, -35, 54, -36, -20, -20, 60, -38, 38,
-	39, -20, -38,
+	-1000, -1, -2, -4, -8, 41, -5, 19, 26, -6,
+	-7, 4, 5, 14, 22, 23, -43, 70, -43, 70,
+	-8, 20, -21, 27, 6, 11, 12, 6, 7, 11,
+	24, 24, -2, -43, -3, -5, -18, 67, -19, -17,
+	-20, 61, 62, 55, 55, -39, 44, 13, 55, -24,
+	8, 55, -23, 55, -23, 21, -43, 70, 28, 64,
+	-35, 40, 71, 71, 69, 55, 42, 55, -25, 29,
+	30, 15, 71, 69, 71, -3, -22, -23, 71, -17,
+	55, 72, -20, -20, 55, 71, 45, 71, 30, 57,
+	16, -11, 55, 55, -11, -26, -27, -28, 52, 31,
+	29, -23, -8, -35, 72, 64, 69, -9, -10, 55,
+	55, 57, -10, 72, 64, 72, -30, 33, -27, 31,
+	-22, 30, -25, 72, 58, 55, 64, 56, 72, 25,
+	55, 25, -33, 34, -29, -17, -16, -32, 42, 66,
+	71, 45, 57, 58, 59, 60, 55, 50, 51, 48,
+	-22, 13, 57, -35, 72, 64, 17, -10, -40, 46,
+	47, -13, -14, 71, -13, -31, 32, 35, 65, 66,
+	68, 67, 53, 54, 43, -29, -29, -29, 71, 71,
+	55, 13, -29, 72, 56, 18, -41, 48, 42, 64,
+	-15, -16, 55, -37, 37, -29, -12, -20, -29, -29,
+	-29, -29, -29, -29, 58, 72, -8, 72, -29, 72,
+	55, -42, 49, 48, -14, 72, 64, -34, 36, 35,
+	64, 72, 72, -16, -35, 57, -36, -20, -20, 64,
+	-38, 38, 39, -20, -38,
 }
 
 var yyDef = [...]int{
-	0, -2, 1, 5, 5, 7, 0, 51, 9, 10,
-	0, 0, 0, 0, 0, 2, 6, 3, 6, 0,
-	0, 52, 0, 21, 0, 0, 19, 0, 0, 0,
-	4, 0, 5, 0, 53, 54, 90, 57, 0, 60,
-	13, 0, 0, 0, 14, 68, 0, 0, 0, 66,
-	0, 8, 11, 6, 0, 0, 55, 0, 0, 0,
-	0, 0, 0, 15, 0, 0, 0, 0, 0, 0,
-	12, 70, 63, 0, 90, 91, 58, 0, 61, 0,
-	22, 0, 0, 20, 0, 0, 28, 67, 0, 75,
-	71, 72, 0, 68, 0, 56, 59, 0, 0, 42,
-	0, 0, 69, 18, 0, 0, 0, 77, 0, 73,
-	0, 90, 65, 62, 0, 45, 17, 0, 29, 0,
-	79, 0, 76, 92, 93, 94, 0, 0, 0, 0,
-	34, 35, 36, 37, 60, 0, 40, 41, 0, 0,
-	0, 43, 47, 46, 23, 25, 0, 24, 83, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 95, 96,
-	0, 0, 0, 39, 0, 64, 0, 44, 48, 0,
-	0, 0, 32, 0, 81, 0, 80, 78, 30, 100,
-	101, 102, 103, 104, 105, 98, 97, 0, 38, 74,
-	0, 49, 26, 27, 0, 90, 0, 0, 0, 99,
-	16, 33, 50, 82, 84, 87, 31, 0, 85, 88,
-	89, 87, 86,
+	0, -2, 1, 6, 6, 0, 8, 0, 55, 10,
+	11, 0, 0, 0, 0, 0, 2, 7, 3, 7,
+	6, 0, 0, 56, 0, 22, 0, 0, 20, 0,
+	0, 0, 5, 4, 0, 6, 0, 57, 58, 98,
+	61, 0, 0, 66, 14, 0, 0, 0, 15, 75,
+	0, 0, 0, 73, 0, 9, 12, 7, 0, 0,
+	59, 0, 0, 0, 0, 0, 0, 0, 16, 0,
+	0, 0, 0, 0, 0, 13, 77, 69, 0, 98,
+	99, 62, 0, 0, 67, 0, 23, 0, 0, 21,
+	0, 0, 29, 74, 0, 83, 78, 79, 0, 0,
+	0, 75, 0, 60, 63, 0, 0, 0, 43, 0,
+	0, 76, 19, 0, 0, 0, 85, 0, 80, 0,
+	0, 0, 98, 72, 0, 68, 0, 46, 18, 0,
+	30, 0, 87, 0, 84, 100, 101, 102, 0, 0,
+	0, 0, 35, 36, 37, 38, 66, 0, 41, 42,
+	0, 0, 70, 0, 64, 0, 0, 44, 49, 47,
+	48, 24, 26, 0, 25, 91, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 103, 104, 0, 0, 0,
+	40, 0, 82, 71, 0, 0, 52, 50, 0, 0,
+	0, 33, 0, 89, 0, 88, 86, 31, 108, 109,
+	110, 111, 112, 113, 106, 105, 0, 39, 81, 65,
+	0, 45, 53, 51, 27, 28, 0, 98, 0, 0,
+	0, 107, 17, 34, 54, 90, 92, 95, 32, 0,
+	93, 96, 97, 95, 94,
 }
 
 var yyTok1 = [...]int{
@@ -337,7 +356,7 @@ var yyTok1 = [...]int{
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	67, 68, 63, 61, 60, 62, 65, 64,
+	71, 72, 67, 65, 64, 66, 69, 68,
 }
 
 var yyTok2 = [...]int{
@@ -346,7 +365,8 @@ var yyTok2 = [...]int{
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
 	32, 33, 34, 35, 36, 37, 38, 39, 40, 41,
 	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
-	52, 53, 54, 55, 56, 57, 58, 59, 66,
+	52, 53, 54, 55, 56, 57, 58, 59, 60, 61,
+	62, 63, 70,
 }
 
 var yyTok3 = [...]int{
@@ -707,218 +727,238 @@ yydefault:
 	case 4:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.stmts = append([]SQLStmt{yyDollar[1].stmt}, yyDollar[3].stmts...)
+			yyVAL.stmts = []SQLStmt{&SelectStmt{ds: &ExplainStmt{stmt: (yyDollar[2].stmt).(*SelectStmt)}}}
 		}
 	case 5:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.stmts = append([]SQLStmt{yyDollar[1].stmt}, yyDollar[3].stmts...)
+		}
+	case 6:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 		}
-	case 7:
+	case 8:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
-	case 8:
+	case 9:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.stmt = &TxStmt{stmts: yyDollar[3].stmts}
 		}
-	case 11:
+	case 12:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmts = []SQLStmt{yyDollar[1].stmt}
 		}
-	case 12:
+	case 13:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmts = append([]SQLStmt{yyDollar[1].stmt}, yyDollar[3].stmts...)
 		}
-	case 13:
+	case 14:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &CreateDatabaseStmt{DB: yyDollar[3].id}
 		}
-	case 14:
+	case 15:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &UseDatabaseStmt{DB: yyDollar[3].id}
 		}
-	case 15:
+	case 16:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.stmt = &UseSnapshotStmt{sinceTx: yyDollar[3].number, asBefore: yyDollar[4].number}
 		}
-	case 16:
+	case 17:
 		yyDollar = yyS[yypt-11 : yypt+1]
 		{
 			yyVAL.stmt = &CreateTableStmt{ifNotExists: yyDollar[3].boolean, table: yyDollar[4].id, colsSpec: yyDollar[6].colsSpec, pk: yyDollar[10].id}
 		}
-	case 17:
+	case 18:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.stmt = &CreateIndexStmt{table: yyDollar[4].id, col: yyDollar[6].id}
 		}
-	case 18:
+	case 19:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = &AddColumnStmt{table: yyDollar[3].id, colSpec: yyDollar[6].colSpec}
 		}
-	case 19:
+	case 20:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.number = 0
 		}
-	case 20:
+	case 21:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.number = yyDollar[3].number
 		}
-	case 21:
+	case 22:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 22:
+	case 23:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 23:
+	case 24:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &UpsertIntoStmt{isInsert: true, tableRef: yyDollar[3].tableRef, cols: yyDollar[5].ids, rows: yyDollar[8].rows}
 		}
-	case 24:
+	case 25:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &UpsertIntoStmt{tableRef: yyDollar[3].tableRef, cols: yyDollar[5].ids, rows: yyDollar[8].rows}
 		}
-	case 25:
+	case 26:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.rows = []*RowSpec{yyDollar[1].row}
 		}
-	case 26:
+	case 27:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.rows = append(yyDollar[1].rows, yyDollar[3].row)
 		}
-	case 27:
+	case 28:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.row = &RowSpec{Values: yyDollar[2].values}
 		}
-	case 28:
+	case 29:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.ids = []string{yyDollar[1].id}
 		}
-	case 29:
+	case 30:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.ids = append(yyDollar[1].ids, yyDollar[3].id)
 		}
-	case 30:
+	case 31:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.cols = []*ColSelector{yyDollar[1].col}
 		}
-	case 31:
+	case 32:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.cols = append(yyDollar[1].cols, yyDollar[3].col)
 		}
-	case 32:
+	case 33:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.values = []ValueExp{yyDollar[1].value}
 		}
-	case 33:
+	case 34:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.values = append(yyDollar[1].values, yyDollar[3].value)
 		}
-	case 34:
+	case 35:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Number{val: yyDollar[1].number}
 		}
-	case 35:
+	case 36:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Varchar{val: yyDollar[1].str}
 		}
-	case 36:
+	case 37:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Bool{val: yyDollar[1].boolean}
 		}
-	case 37:
+	case 38:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Blob{val: yyDollar[1].blob}
 		}
-	case 38:
+	case 39:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.value = &SysFn{fn: yyDollar[1].id}
 		}
-	case 39:
+	case 40:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.value = &Param{id: yyDollar[2].id}
 		}
-	case 40:
+	case 41:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Param{id: fmt.Sprintf("param%d", yyDollar[1].pparam), pos: yyDollar[1].pparam}
 		}
-	case 41:
+	case 42:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &NullValue{t: AnyType}
 		}
-	case 42:
+	case 43:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.colsSpec = []*ColSpec{yyDollar[1].colSpec}
 		}
-	case 43:
+	case 44:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.colsSpec = append(yyDollar[1].colsSpec, yyDollar[3].colSpec)
 		}
-	case 44:
-		yyDollar = yyS[yypt-4 : yypt+1]
+	case 45:
+		yyDollar = yyS[yypt-5 : yypt+1]
 		{
-			yyVAL.colSpec = &ColSpec{colName: yyDollar[1].id, colType: yyDollar[2].sqlType, autoIncrement: yyDollar[3].boolean, notNull: yyDollar[4].boolean}
+			yyVAL.colSpec = &ColSpec{colName: yyDollar[1].id, colType: yyDollar[2].sqlType, autoIncrement: yyDollar[3].boolean, notNull: yyDollar[4].boolean, unique: yyDollar[5].boolean}
 		}
-	case 45:
+	case 46:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 46:
+	case 47:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 47:
+	case 48:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.boolean = true
+		}
+	case 49:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 48:
+	case 50:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 49:
+	case 51:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 50:
+	case 52:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.boolean = false
+		}
+	case 53:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.boolean = true
+		}
+	case 54:
 		yyDollar = yyS[yypt-12 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
@@ -934,281 +974,302 @@ yydefault:
 				as:        yyDollar[12].id,
 			}
 		}
-	case 51:
+	case 55:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.distinct = false
 		}
-	case 52:
+	case 56:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.distinct = true
 		}
-	case 53:
+	case 57:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sels = nil
 		}
-	case 54:
+	case 58:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sels = yyDollar[1].sels
 		}
-	case 55:
+	case 59:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyDollar[1].sel.setAlias(yyDollar[2].id)
 			yyVAL.sels = []Selector{yyDollar[1].sel}
 		}
-	case 56:
+	case 60:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyDollar[3].sel.setAlias(yyDollar[4].id)
 			yyVAL.sels = append(yyDollar[1].sels, yyDollar[3].sel)
 		}
-	case 57:
+	case 61:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sel = yyDollar[1].col
 		}
-	case 58:
+	case 62:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, col: "*"}
 		}
-	case 59:
+	case 63:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, db: yyDollar[3].col.db, table: yyDollar[3].col.table, col: yyDollar[3].col.col}
 		}
-	case 60:
+	case 64:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.sel = &JSONSelector{fn: yyDollar[1].jsonFn, sel: yyDollar[3].col, path: yyDollar[5].str}
+		}
+	case 65:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			yyVAL.sel = &JSONSelector{fn: yyDollar[1].jsonFn, sel: yyDollar[3].col, path: yyDollar[5].str, t: yyDollar[7].sqlType}
+		}
+	case 66:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.col = &ColSelector{col: yyDollar[1].id}
 		}
-	case 61:
+	case 67:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.col = &ColSelector{table: yyDollar[1].id, col: yyDollar[3].id}
 		}
-	case 62:
+	case 68:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.col = &ColSelector{db: yyDollar[1].id, table: yyDollar[3].id, col: yyDollar[5].id}
 		}
-	case 63:
+	case 69:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.ds = yyDollar[1].tableRef
 		}
-	case 64:
+	case 70:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyDollar[1].tableRef.asBefore = yyDollar[4].number
+			yyVAL.ds = yyDollar[1].tableRef
+		}
+	case 71:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyDollar[2].tableRef.asBefore = yyDollar[3].number
 			yyDollar[2].tableRef.as = yyDollar[4].id
 			yyVAL.ds = yyDollar[2].tableRef
 		}
-	case 65:
+	case 72:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.ds = yyDollar[2].stmt.(*SelectStmt)
 		}
-	case 66:
+	case 73:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.tableRef = &TableRef{table: yyDollar[1].id}
 		}
-	case 67:
+	case 74:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.tableRef = &TableRef{db: yyDollar[1].id, table: yyDollar[3].id}
 		}
-	case 68:
+	case 75:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.number = 0
 		}
-	case 69:
+	case 76:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.number = yyDollar[3].number
 		}
-	case 70:
+	case 77:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.joins = nil
 		}
-	case 71:
+	case 78:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.joins = yyDollar[1].joins
 		}
-	case 72:
+	case 79:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.joins = []*JoinSpec{yyDollar[1].join}
 		}
-	case 73:
+	case 80:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.joins = append([]*JoinSpec{yyDollar[1].join}, yyDollar[2].joins...)
 		}
-	case 74:
+	case 81:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.join = &JoinSpec{joinType: yyDollar[1].joinType, ds: yyDollar[3].ds, cond: yyDollar[5].boolExp}
 		}
-	case 75:
+	case 82:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.join = &JoinSpec{joinType: InnerJoin, ds: yyDollar[2].ds, cond: yyDollar[4].boolExp}
+		}
+	case 83:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolExp = nil
 		}
-	case 76:
+	case 84:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.boolExp = yyDollar[2].boolExp
 		}
-	case 77:
+	case 85:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.cols = nil
 		}
-	case 78:
+	case 86:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.cols = yyDollar[3].cols
 		}
-	case 79:
+	case 87:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolExp = nil
 		}
-	case 80:
+	case 88:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.boolExp = yyDollar[2].boolExp
 		}
-	case 81:
+	case 89:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.number = 0
 		}
-	case 82:
+	case 90:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.number = yyDollar[2].number
 		}
-	case 83:
+	case 91:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.ordcols = nil
 		}
-	case 84:
+	case 92:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.ordcols = yyDollar[3].ordcols
 		}
-	case 85:
+	case 93:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.ordcols = []*OrdCol{{sel: yyDollar[1].col, cmp: yyDollar[2].opt_ord}}
 		}
-	case 86:
+	case 94:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.ordcols = append(yyDollar[1].ordcols, &OrdCol{sel: yyDollar[3].col, cmp: yyDollar[4].opt_ord})
 		}
-	case 87:
+	case 95:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.opt_ord = GreaterOrEqualTo
 		}
-	case 88:
+	case 96:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.opt_ord = GreaterOrEqualTo
 		}
-	case 89:
+	case 97:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.opt_ord = LowerOrEqualTo
 		}
-	case 90:
+	case 98:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.id = ""
 		}
-	case 91:
+	case 99:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.id = yyDollar[2].id
 		}
-	case 92:
+	case 100:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.boolExp = yyDollar[1].sel
 		}
-	case 93:
+	case 101:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.boolExp = yyDollar[1].value
 		}
-	case 94:
+	case 102:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.boolExp = yyDollar[1].binExp
 		}
-	case 95:
+	case 103:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.boolExp = &NotBoolExp{exp: yyDollar[2].boolExp}
 		}
-	case 96:
+	case 104:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.boolExp = &NumExp{left: &Number{val: uint64(0)}, op: SUBSOP, right: yyDollar[2].boolExp}
 		}
-	case 97:
+	case 105:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.boolExp = yyDollar[2].boolExp
 		}
-	case 98:
+	case 106:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.boolExp = &LikeBoolExp{sel: yyDollar[1].sel, pattern: yyDollar[3].str}
 		}
-	case 99:
+	case 107:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.boolExp = &ExistsBoolExp{q: (yyDollar[3].stmt).(*SelectStmt)}
 		}
-	case 100:
+	case 108:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &NumExp{left: yyDollar[1].boolExp, op: ADDOP, right: yyDollar[3].boolExp}
 		}
-	case 101:
+	case 109:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &NumExp{left: yyDollar[1].boolExp, op: SUBSOP, right: yyDollar[3].boolExp}
 		}
-	case 102:
+	case 110:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &NumExp{left: yyDollar[1].boolExp, op: DIVOP, right: yyDollar[3].boolExp}
 		}
-	case 103:
+	case 111:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &NumExp{left: yyDollar[1].boolExp, op: MULTOP, right: yyDollar[3].boolExp}
 		}
-	case 104:
+	case 112:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &BinBoolExp{left: yyDollar[1].boolExp, op: yyDollar[2].logicOp, right: yyDollar[3].boolExp}
 		}
-	case 105:
+	case 113:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &CmpBoolExp{left: yyDollar[1].boolExp, op: yyDollar[2].cmpOp, right: yyDollar[3].boolExp}
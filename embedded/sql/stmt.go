@@ -19,6 +19,7 @@ package sql
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"regexp"
 	"strings"
@@ -30,7 +31,7 @@ import (
 const (
 	catalogDatabasePrefix = "CATALOG.DATABASE." // (key=CATALOG.DATABASE.{dbID}, value={dbNAME})
 	catalogTablePrefix    = "CATALOG.TABLE."    // (key=CATALOG.TABLE.{dbID}{tableID}{pkID}, value={tableNAME})
-	catalogColumnPrefix   = "CATALOG.COLUMN."   // (key=CATALOG.COLUMN.{dbID}{tableID}{colID}{colTYPE}, value={auto_incremental | nullable}{colNAME})
+	catalogColumnPrefix   = "CATALOG.COLUMN."   // (key=CATALOG.COLUMN.{dbID}{tableID}{colID}{colTYPE}, value={auto_incremental | nullable | unique}{colNAME})
 	catalogIndexPrefix    = "CATALOG.INDEX."    // (key=CATALOG.INDEX.{dbID}{tableID}{colID}, value={})
 	RowPrefix             = "ROW."              // (key=ROW.{dbID}{tableID}{colID}({valLen}{val})?{pkValLen}{pkVal}, value={})
 )
@@ -38,6 +39,7 @@ const (
 const (
 	nullableFlag      byte = 1 << iota
 	autoIncrementFlag byte = 1 << iota
+	uniqueFlag        byte = 1 << iota
 )
 
 type SQLValueType = string
@@ -48,6 +50,7 @@ const (
 	VarcharType   SQLValueType = "VARCHAR"
 	BLOBType      SQLValueType = "BLOB"
 	TimestampType SQLValueType = "TIMESTAMP"
+	JSONType      SQLValueType = "JSON"
 	AnyType       SQLValueType = "ANY"
 )
 
@@ -61,6 +64,13 @@ const (
 	AVG   AggregateFn = "AVG"
 )
 
+type JSONFn = string
+
+const (
+	JSONExtract JSONFn = "JSON_EXTRACT"
+	JSONValue   JSONFn = "JSON_VALUE"
+)
+
 type CmpOperator = int
 
 const (
@@ -104,14 +114,29 @@ type TxSummary struct {
 
 	updatedRows     int
 	lastInsertedPKs map[string]uint64
+
+	// unique index entries (without pk) of the rows written in the tx, mapped to the pk of the row
+	uniqueKeys map[string][]byte
 }
 
 func newTxSummary() *TxSummary {
 	return &TxSummary{
 		lastInsertedPKs: make(map[string]uint64),
+		uniqueKeys:      make(map[string][]byte),
 	}
 }
 
+func (s *TxSummary) addUniqueKey(key, pkEncVal []byte) error {
+	pk, exists := s.uniqueKeys[string(key)]
+	if exists && !bytes.Equal(pk, pkEncVal) {
+		return ErrUniqueConstraintViolation
+	}
+
+	s.uniqueKeys[string(key)] = pkEncVal
+
+	return nil
+}
+
 func (s *TxSummary) add(summary *TxSummary) error {
 	if summary == nil {
 		return ErrIllegalArguments
@@ -128,6 +153,13 @@ func (s *TxSummary) add(summary *TxSummary) error {
 		s.lastInsertedPKs[t] = pk
 	}
 
+	for k, pk := range summary.uniqueKeys {
+		err := s.addUniqueKey([]byte(k), pk)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -277,6 +309,9 @@ func (stmt *CreateTableStmt) compileUsing(e *Engine, implicitDB *Database, param
 		if col.notNull {
 			v[0] = v[0] | nullableFlag
 		}
+		if col.unique {
+			v[0] = v[0] | uniqueFlag
+		}
 		copy(v[1:], []byte(col.Name()))
 
 		ce := &store.KV{
@@ -284,6 +319,23 @@ func (stmt *CreateTableStmt) compileUsing(e *Engine, implicitDB *Database, param
 			Value: v,
 		}
 		summary.ces = append(summary.ces, ce)
+
+		// unique columns are enforced through an index, the pk is already unique
+		if !col.unique || col.id == table.pk.id {
+			continue
+		}
+
+		if col.colType == JSONType {
+			return nil, ErrLimitedJSON
+		}
+
+		table.indexes[col.id] = struct{}{}
+
+		ie := &store.KV{
+			Key:   e.mapKey(catalogIndexPrefix, EncodeID(implicitDB.id), EncodeID(table.id), EncodeID(colID)),
+			Value: []byte(table.name),
+		}
+		summary.ces = append(summary.ces, ie)
 	}
 
 	te := &store.KV{
@@ -300,6 +352,7 @@ type ColSpec struct {
 	colType       SQLValueType
 	autoIncrement bool
 	notNull       bool
+	unique        bool
 }
 
 type CreateIndexStmt struct {
@@ -333,6 +386,10 @@ func (stmt *CreateIndexStmt) compileUsing(e *Engine, implicitDB *Database, param
 		return nil, err
 	}
 
+	if col.colType == JSONType {
+		return nil, ErrLimitedJSON
+	}
+
 	_, exists := table.indexes[col.id]
 	if exists {
 		return nil, ErrIndexAlreadyExists
@@ -538,23 +595,32 @@ func (stmt *UpsertIntoStmt) compileUsing(e *Engine, implicitDB *Database, params
 		return nil, err
 	}
 
+	var snap *store.Snapshot
+
+	defer func() {
+		if snap != nil {
+			snap.Close()
+		}
+	}()
+
 	for _, row := range stmt.rows {
 		if len(row.Values) != len(stmt.cols) {
 			return nil, ErrInvalidNumberOfValues
 		}
 
 		cols := stmt.cols
+		values := row.Values
 
 		var pkVal ValueExp
 
-		// inject auto-incremental pk value
+		// inject auto-incremental pk value, leaving the statement untouched so it can be executed again
 		if stmt.isInsert && table.pk.autoIncrement {
 			table.maxPK++
 			e.catalog.mutated = true // TODO: implement transactional in-memory catalog
 
 			pkVal = &Number{val: table.maxPK}
-			cols = append(cols, table.pk.colName)
-			row.Values = append(row.Values, pkVal)
+			cols = append(cols[:len(cols):len(cols)], table.pk.colName)
+			values = append(values[:len(values):len(values)], pkVal)
 
 			summary.lastInsertedPKs[table.name] = table.maxPK
 		} else {
@@ -581,7 +647,7 @@ func (stmt *UpsertIntoStmt) compileUsing(e *Engine, implicitDB *Database, params
 			return nil, err
 		}
 
-		bs, err := row.bytes(e.catalog, table, cols, params)
+		bs, err := (&RowSpec{Values: values}).bytes(e.catalog, table, cols, params)
 		if err != nil {
 			return nil, err
 		}
@@ -613,7 +679,7 @@ func (stmt *UpsertIntoStmt) compileUsing(e *Engine, implicitDB *Database, params
 				return nil, ErrIndexedColumnCanNotBeNull
 			}
 
-			cVal := row.Values[colPos]
+			cVal := values[colPos]
 
 			val, err := cVal.substitute(params)
 			if err != nil {
@@ -640,6 +706,27 @@ func (stmt *UpsertIntoStmt) compileUsing(e *Engine, implicitDB *Database, params
 				return nil, err
 			}
 
+			if col.unique {
+				if snap == nil {
+					snap, err = e.latestDataSnapshot()
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				uniqueKey := e.mapKey(RowPrefix, EncodeID(table.db.id), EncodeID(table.id), EncodeID(colID), encVal)
+
+				err = e.checkUniqueness(snap, col, uniqueKey, encVal, pkEncVal)
+				if err != nil {
+					return nil, err
+				}
+
+				err = summary.addUniqueKey(uniqueKey, pkEncVal)
+				if err != nil {
+					return nil, err
+				}
+			}
+
 			ie := &store.KV{
 				Key:   e.mapKey(RowPrefix, EncodeID(table.db.id), EncodeID(table.id), EncodeID(colID), encVal, pkEncVal),
 				Value: nil,
@@ -791,7 +878,8 @@ func (v *Varchar) inferType(cols map[string]*ColDescriptor, params map[string]SQ
 }
 
 func (v *Varchar) requiresType(t SQLValueType, cols map[string]*ColDescriptor, params map[string]SQLValueType, implicitDB, implicitTable string) error {
-	if t != VarcharType {
+	// JSON documents are written as text
+	if t != VarcharType && t != JSONType {
 		return ErrInvalidTypes
 	}
 
@@ -939,6 +1027,57 @@ func (v *Blob) Compare(val TypedValue) (int, error) {
 	return bytes.Compare(v.val, rval), nil
 }
 
+type JSON struct {
+	val string
+}
+
+func (v *JSON) Type() SQLValueType {
+	return JSONType
+}
+
+func (v *JSON) inferType(cols map[string]*ColDescriptor, params map[string]SQLValueType, implicitDB, implicitTable string) (SQLValueType, error) {
+	return JSONType, nil
+}
+
+func (v *JSON) requiresType(t SQLValueType, cols map[string]*ColDescriptor, params map[string]SQLValueType, implicitDB, implicitTable string) error {
+	if t != JSONType {
+		return ErrInvalidTypes
+	}
+
+	return nil
+}
+
+func (v *JSON) jointColumnTo(col *Column, tableAlias string) (*ColSelector, error) {
+	return nil, ErrJointColumnNotFound
+}
+
+func (v *JSON) substitute(params map[string]interface{}) (ValueExp, error) {
+	return v, nil
+}
+
+func (v *JSON) reduce(catalog *Catalog, row *Row, implicitDB, implicitTable string) (TypedValue, error) {
+	return v, nil
+}
+
+func (v *JSON) Value() interface{} {
+	return v.val
+}
+
+func (v *JSON) Compare(val TypedValue) (int, error) {
+	_, isNull := val.(*NullValue)
+	if isNull {
+		return 1, nil
+	}
+
+	if val.Type() != JSONType {
+		return 0, ErrNotComparableValues
+	}
+
+	rval := val.Value().(string)
+
+	return bytes.Compare([]byte(v.val), []byte(rval)), nil
+}
+
 type SysFn struct {
 	fn string
 }
@@ -1063,6 +1202,7 @@ type DataSource interface {
 	inferParameters(e *Engine, implicitDB *Database, params map[string]SQLValueType) error
 	Resolve(e *Engine, implicitDB *Database, snap *store.Snapshot, params map[string]interface{}, ordCol *OrdCol) (RowReader, error)
 	Alias() string
+	explain(e *Engine, implicitDB *Database, snap *store.Snapshot, ordCol *OrdCol) ([]*planStep, error)
 }
 
 type SelectStmt struct {
@@ -1119,6 +1259,13 @@ func (stmt *SelectStmt) compileUsing(e *Engine, implicitDB *Database, params map
 		return nil, ErrHavingClauseRequiresGroupClause
 	}
 
+	// groups are returned in the order of the grouping column
+	if len(stmt.groupBy) > 0 && len(stmt.orderBy) > 0 {
+		if len(stmt.groupBy) > 1 || stmt.orderBy[0].sel.col != stmt.groupBy[0].col {
+			return nil, ErrLimitedGroupBy
+		}
+	}
+
 	if len(stmt.orderBy) > 1 {
 		return nil, ErrLimitedOrderBy
 	}
@@ -1152,23 +1299,65 @@ func (stmt *SelectStmt) compileUsing(e *Engine, implicitDB *Database, params map
 	return summary, nil
 }
 
-func (stmt *SelectStmt) Resolve(e *Engine, implicitDB *Database, snap *store.Snapshot, params map[string]interface{}, ordCol *OrdCol) (RowReader, error) {
-	var orderByCol *OrdCol
-
+func (stmt *SelectStmt) orderByCol(e *Engine, implicitDB *Database) *OrdCol {
 	if len(stmt.orderBy) > 0 {
-		orderByCol = stmt.orderBy[0]
+		return stmt.orderBy[0]
+	}
+
+	if stmt.groupedByIndex(e, implicitDB) {
+		// rows are grouped as they are read, thus they are read in the order of the grouping column
+		return &OrdCol{sel: stmt.groupBy[0], cmp: GreaterOrEqualTo}
 	}
 
-	rowReader, err := stmt.ds.Resolve(e, implicitDB, snap, params, orderByCol)
+	return nil
+}
+
+// groupedByIndex tells whether rows can be read through the index of the grouping column,
+// otherwise they are sorted by the grouping columns before being grouped
+func (stmt *SelectStmt) groupedByIndex(e *Engine, implicitDB *Database) bool {
+	if len(stmt.groupBy) != 1 {
+		return false
+	}
+
+	tableRef, ok := stmt.ds.(*TableRef)
+	if !ok {
+		return false
+	}
+
+	sel := stmt.groupBy[0]
+	if sel.table != "" && sel.table != tableRef.Alias() {
+		return false
+	}
+
+	table, err := tableRef.referencedTable(e, implicitDB)
+	if err != nil {
+		return false
+	}
+
+	col, err := table.GetColumnByName(sel.col)
+	if err != nil {
+		return false
+	}
+
+	_, indexed := table.indexes[col.id]
+
+	return table.pk.id == col.id || indexed
+}
+
+func (stmt *SelectStmt) Resolve(e *Engine, implicitDB *Database, snap *store.Snapshot, params map[string]interface{}, ordCol *OrdCol) (RowReader, error) {
+	rowReader, err := stmt.ds.Resolve(e, implicitDB, snap, params, stmt.orderByCol(e, implicitDB))
 	if err != nil {
 		return nil, err
 	}
 
 	if stmt.joins != nil {
-		rowReader, err = e.newJointRowReader(implicitDB, snap, params, rowReader, stmt.joins)
+		jointRowReader, err := e.newJointRowReader(implicitDB, snap, params, rowReader, stmt.joins)
 		if err != nil {
+			rowReader.Close()
 			return nil, err
 		}
+
+		rowReader = jointRowReader
 	}
 
 	if stmt.where != nil {
@@ -1192,6 +1381,13 @@ func (stmt *SelectStmt) Resolve(e *Engine, implicitDB *Database, snap *store.Sna
 			groupBy = stmt.groupBy
 		}
 
+		if len(groupBy) > 0 && len(stmt.orderBy) == 0 && !stmt.groupedByIndex(e, implicitDB) {
+			rowReader, err = e.newSortedRowReader(rowReader, groupBy)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		rowReader, err = e.newGroupedRowReader(rowReader, stmt.selectors, groupBy)
 		if err != nil {
 			return nil, err
@@ -1519,6 +1715,144 @@ func (sel *AggColSelector) reduce(catalog *Catalog, row *Row, implicitDB, implic
 	return v, nil
 }
 
+// JSONSelector extracts a field from the JSON documents stored in a column.
+// JSON_EXTRACT returns the field as a JSON document while JSON_VALUE returns its scalar value,
+// as VARCHAR unless another type is specified, or NULL when it can not be converted
+type JSONSelector struct {
+	fn   JSONFn
+	sel  *ColSelector
+	path string
+	t    SQLValueType
+	as   string
+}
+
+func (sel *JSONSelector) resolve(implicitDB, implicitTable string) (aggFn, db, table, col string) {
+	return sel.sel.resolve(implicitDB, implicitTable)
+}
+
+func (sel *JSONSelector) alias() string {
+	return sel.as
+}
+
+func (sel *JSONSelector) setAlias(alias string) {
+	sel.as = alias
+}
+
+func (sel *JSONSelector) valueType() (SQLValueType, error) {
+	switch sel.fn {
+	case JSONExtract:
+		{
+			if sel.t != "" && sel.t != JSONType {
+				return AnyType, ErrInvalidTypes
+			}
+
+			return JSONType, nil
+		}
+	case JSONValue:
+		{
+			switch sel.t {
+			case "":
+				return VarcharType, nil
+			case VarcharType, IntegerType, BooleanType:
+				return sel.t, nil
+			}
+
+			return AnyType, ErrInvalidTypes
+		}
+	}
+
+	return AnyType, ErrIllegalArguments
+}
+
+func (sel *JSONSelector) inferType(cols map[string]*ColDescriptor, params map[string]SQLValueType, implicitDB, implicitTable string) (SQLValueType, error) {
+	t, err := sel.sel.inferType(cols, params, implicitDB, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	if t != JSONType && t != VarcharType {
+		return AnyType, ErrInvalidTypes
+	}
+
+	return sel.valueType()
+}
+
+func (sel *JSONSelector) requiresType(t SQLValueType, cols map[string]*ColDescriptor, params map[string]SQLValueType, implicitDB, implicitTable string) error {
+	vt, err := sel.inferType(cols, params, implicitDB, implicitTable)
+	if err != nil {
+		return err
+	}
+
+	if vt != t {
+		return ErrInvalidTypes
+	}
+
+	return nil
+}
+
+func (sel *JSONSelector) jointColumnTo(col *Column, tableAlias string) (*ColSelector, error) {
+	return nil, ErrJointColumnNotFound
+}
+
+func (sel *JSONSelector) substitute(params map[string]interface{}) (ValueExp, error) {
+	return sel, nil
+}
+
+func (sel *JSONSelector) reduce(catalog *Catalog, row *Row, implicitDB, implicitTable string) (TypedValue, error) {
+	v, err := sel.sel.reduce(catalog, row, implicitDB, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return sel.extract(v)
+}
+
+// extract returns the field of the document, documents stored as VARCHAR are accepted as well
+func (sel *JSONSelector) extract(doc TypedValue) (TypedValue, error) {
+	t, err := sel.valueType()
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Type() != JSONType && doc.Type() != VarcharType {
+		return nil, ErrInvalidTypes
+	}
+
+	if doc.Value() == nil {
+		return &NullValue{t: t}, nil
+	}
+
+	path, err := parseJSONPath(sel.path)
+	if err != nil {
+		return nil, err
+	}
+
+	field, found, err := extractJSONField(doc.Value().(string), path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return &NullValue{t: t}, nil
+	}
+
+	if sel.fn == JSONExtract {
+		b, err := json.Marshal(field)
+		if err != nil {
+			return nil, err
+		}
+
+		return &JSON{val: string(b)}, nil
+	}
+
+	v := jsonScalarAs(field, t)
+	if v == nil {
+		return &NullValue{t: t}, nil
+	}
+
+	return v, nil
+}
+
 type NumExp struct {
 	op          NumOperator
 	left, right ValueExp
@@ -1571,10 +1905,7 @@ func (bexp *NumExp) substitute(params map[string]interface{}) (ValueExp, error)
 		return nil, err
 	}
 
-	bexp.left = rlexp
-	bexp.right = rrexp
-
-	return bexp, nil
+	return &NumExp{op: bexp.op, left: rlexp, right: rrexp}, nil
 }
 
 func (bexp *NumExp) reduce(catalog *Catalog, row *Row, implicitDB, implicitTable string) (TypedValue, error) {
@@ -1655,9 +1986,7 @@ func (bexp *NotBoolExp) substitute(params map[string]interface{}) (ValueExp, err
 		return nil, err
 	}
 
-	bexp.exp = rexp
-
-	return bexp, nil
+	return &NotBoolExp{exp: rexp}, nil
 }
 
 func (bexp *NotBoolExp) reduce(catalog *Catalog, row *Row, implicitDB, implicitTable string) (TypedValue, error) {
@@ -1700,15 +2029,19 @@ func (bexp *LikeBoolExp) substitute(params map[string]interface{}) (ValueExp, er
 }
 
 func (bexp *LikeBoolExp) reduce(catalog *Catalog, row *Row, implicitDB, implicitTable string) (TypedValue, error) {
-	v, ok := row.Values[EncodeSelector(bexp.sel.resolve(implicitDB, implicitTable))]
-	if !ok {
-		return nil, ErrColumnDoesNotExist
+	v, err := bexp.sel.reduce(catalog, row, implicitDB, implicitTable)
+	if err != nil {
+		return nil, err
 	}
 
 	if v.Type() != VarcharType {
 		return nil, ErrInvalidColumn
 	}
 
+	if v.Value() == nil {
+		return &Bool{val: false}, nil
+	}
+
 	matched, err := regexp.MatchString(bexp.pattern, v.Value().(string))
 	if err != nil {
 		return nil, err
@@ -1815,10 +2148,7 @@ func (bexp *CmpBoolExp) substitute(params map[string]interface{}) (ValueExp, err
 		return nil, err
 	}
 
-	bexp.left = rlexp
-	bexp.right = rrexp
-
-	return bexp, nil
+	return &CmpBoolExp{op: bexp.op, left: rlexp, right: rrexp}, nil
 }
 
 func (bexp *CmpBoolExp) reduce(catalog *Catalog, row *Row, implicitDB, implicitTable string) (TypedValue, error) {
@@ -1910,10 +2240,7 @@ func (bexp *BinBoolExp) substitute(params map[string]interface{}) (ValueExp, err
 		return nil, err
 	}
 
-	bexp.left = rlexp
-	bexp.right = rrexp
-
-	return bexp, nil
+	return &BinBoolExp{op: bexp.op, left: rlexp, right: rrexp}, nil
 }
 
 func (bexp *BinBoolExp) reduce(catalog *Catalog, row *Row, implicitDB, implicitTable string) (TypedValue, error) {
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+// valuesRowReader returns rows already known in advance, such as the steps of a query plan
+type valuesRowReader struct {
+	implicitDB    string
+	implicitTable string
+
+	colsByPos []*ColDescriptor
+	colsBySel map[string]*ColDescriptor
+
+	values [][]TypedValue
+	read   int
+}
+
+func newValuesRowReader(implicitDB, implicitTable string, cols []*ColDescriptor, values [][]TypedValue) (*valuesRowReader, error) {
+	colsBySel := make(map[string]*ColDescriptor, len(cols))
+
+	for _, col := range cols {
+		colsBySel[col.Selector()] = col
+	}
+
+	for _, vs := range values {
+		if len(vs) != len(cols) {
+			return nil, ErrInvalidNumberOfValues
+		}
+	}
+
+	return &valuesRowReader{
+		implicitDB:    implicitDB,
+		implicitTable: implicitTable,
+		colsByPos:     cols,
+		colsBySel:     colsBySel,
+		values:        values,
+	}, nil
+}
+
+func (vr *valuesRowReader) ImplicitDB() string {
+	return vr.implicitDB
+}
+
+func (vr *valuesRowReader) ImplicitTable() string {
+	return vr.implicitTable
+}
+
+func (vr *valuesRowReader) SetParameters(params map[string]interface{}) {
+}
+
+func (vr *valuesRowReader) Read() (*Row, error) {
+	if vr.read == len(vr.values) {
+		return nil, ErrNoMoreRows
+	}
+
+	row := &Row{Values: make(map[string]TypedValue, len(vr.colsByPos))}
+
+	for i, col := range vr.colsByPos {
+		row.Values[col.Selector()] = vr.values[vr.read][i]
+	}
+
+	vr.read++
+
+	return row, nil
+}
+
+func (vr *valuesRowReader) Close() error {
+	return nil
+}
+
+func (vr *valuesRowReader) Columns() ([]*ColDescriptor, error) {
+	return vr.colsByPos, nil
+}
+
+func (vr *valuesRowReader) InferParameters(params map[string]SQLValueType) error {
+	return nil
+}
+
+func (vr *valuesRowReader) colsBySelector() (map[string]*ColDescriptor, error) {
+	return vr.colsBySel, nil
+}
@@ -59,6 +59,7 @@ var ErrTxSizeGreaterThanMaxTxSize = errors.New("tx size greater than max tx size
 var ErrCorruptedAHtree = errors.New("appendable hash tree is corrupted")
 var ErrKeyNotFound = tbtree.ErrKeyNotFound
 var ErrKeyAlreadyExists = errors.New("key already exists")
+var ErrPreconditionFailed = errors.New("precondition failed")
 var ErrTxNotFound = errors.New("tx not found")
 var ErrNoMoreEntries = tbtree.ErrNoMoreEntries
 var ErrIllegalState = tbtree.ErrIllegalState
@@ -148,6 +149,8 @@ type ImmuStore struct {
 
 	mutex sync.Mutex
 
+	repairMutex sync.Mutex
+
 	compactionDisabled bool
 }
 
@@ -162,12 +165,15 @@ const (
 	NoConstraint KVConstraint = iota
 	MustExist    KVConstraint = iota + 1
 	MustNotExist KVConstraint = iota + 1
+	// MustMatchTx requires the current value of the key to be the one written at ExpectedTx
+	MustMatchTx KVConstraint = iota + 1
 )
 
 type KV struct {
 	Key        []byte
 	Value      []byte
 	Constraint KVConstraint
+	ExpectedTx uint64
 }
 
 func (kv *KV) Digest() [sha256.Size]byte {
@@ -903,6 +909,7 @@ func (s *ImmuStore) commitUsing(entries []*KV, md *TxMetadata, waitForIndexing b
 		txe.vLen = len(e.Value)
 		txe.hVal = sha256.Sum256(e.Value)
 		txe.constraint = e.Constraint
+		txe.expectedTx = e.ExpectedTx
 	}
 
 	err = tx.BuildHashTree()
@@ -1005,16 +1012,25 @@ func (s *ImmuStore) commit(tx *Tx, offsets []int64, ts int64, blTxID uint64) err
 				}
 			}
 
-			_, _, _, err := s.indexer.Get(txe.Key())
-			if err == nil && txe.constraint == MustNotExist {
-				return ErrKeyAlreadyExists
-			}
-			if err == ErrKeyNotFound && txe.constraint == MustExist {
-				return ErrKeyNotFound
-			}
-			if err != ErrKeyNotFound {
+			_, currTx, _, err := s.indexer.Get(txe.Key())
+			if err != nil && err != ErrKeyNotFound {
 				return err
 			}
+
+			switch txe.constraint {
+			case MustNotExist:
+				if err == nil {
+					return ErrKeyAlreadyExists
+				}
+			case MustExist:
+				if err == ErrKeyNotFound {
+					return ErrKeyNotFound
+				}
+			case MustMatchTx:
+				if err == ErrKeyNotFound || currTx != txe.expectedTx {
+					return ErrPreconditionFailed
+				}
+			}
 		}
 
 		// tx serialization using pre-allocated buffer
@@ -1184,6 +1200,7 @@ func (s *ImmuStore) commitWith(callback func(txID uint64, index KeyIndex) ([]*KV
 		txe.vLen = len(e.Value)
 		txe.hVal = sha256.Sum256(e.Value)
 		txe.constraint = e.Constraint
+		txe.expectedTx = e.ExpectedTx
 	}
 
 	err = tx.BuildHashTree()
@@ -1397,18 +1414,9 @@ func (s *ImmuStore) ExportTx(txID uint64, tx *Tx) ([]byte, error) {
 		return nil, err
 	}
 
-	mdBs := tx.Metadata().serialize()
-
 	var buf bytes.Buffer
 
-	var b [4]byte
-	binary.BigEndian.PutUint32(b[:], uint32(len(mdBs)))
-	_, err = buf.Write(b[:])
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = buf.Write(mdBs)
+	err = writeExportedTxMetadata(&buf, tx.Metadata())
 	if err != nil {
 		return nil, err
 	}
@@ -1421,30 +1429,86 @@ func (s *ImmuStore) ExportTx(txID uint64, tx *Tx) ([]byte, error) {
 			return nil, err
 		}
 
-		var lenBs [4]byte
-
-		// kLen
-		binary.BigEndian.PutUint32(lenBs[:], uint32(e.kLen))
-		_, err = buf.Write(lenBs[:])
+		err = writeExportedEntry(&buf, e.Key(), valBs[:e.vLen])
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		// vLen
-		binary.BigEndian.PutUint32(lenBs[:], uint32(e.vLen))
-		_, err = buf.Write(lenBs[:])
-		if err != nil {
-			return nil, err
-		}
+	return buf.Bytes(), nil
+}
 
-		// key
-		_, err = buf.Write(e.Key())
-		if err != nil {
-			return nil, err
+func writeExportedTxMetadata(buf *bytes.Buffer, md *TxMetadata) error {
+	mdBs := md.serialize()
+
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(len(mdBs)))
+	_, err := buf.Write(b[:])
+	if err != nil {
+		return err
+	}
+
+	_, err = buf.Write(mdBs)
+	return err
+}
+
+func writeExportedEntry(buf *bytes.Buffer, key, value []byte) error {
+	var lenBs [4]byte
+
+	// kLen
+	binary.BigEndian.PutUint32(lenBs[:], uint32(len(key)))
+	_, err := buf.Write(lenBs[:])
+	if err != nil {
+		return err
+	}
+
+	// vLen
+	binary.BigEndian.PutUint32(lenBs[:], uint32(len(value)))
+	_, err = buf.Write(lenBs[:])
+	if err != nil {
+		return err
+	}
+
+	// key
+	_, err = buf.Write(key)
+	if err != nil {
+		return err
+	}
+
+	// val
+	_, err = buf.Write(value)
+	return err
+}
+
+// FilterExportedTx returns the exported transaction holding only the entries accepted by keep.
+// The metadata is preserved but for the number of entries, so the result can not be replicated as a whole
+// but only applied partially
+func FilterExportedTx(exportedTx []byte, keep func(key []byte) bool) ([]byte, error) {
+	md, entries, err := parseExportedTx(exportedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []*KV
+
+	for _, e := range entries {
+		if keep(e.Key) {
+			kept = append(kept, e)
 		}
+	}
+
+	fmd := *md
+	fmd.NEntries = len(kept)
+
+	var buf bytes.Buffer
 
-		// val
-		_, err = buf.Write(valBs[:e.vLen])
+	err = writeExportedTxMetadata(&buf, &fmd)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range kept {
+		err = writeExportedEntry(&buf, e.Key, e.Value)
 		if err != nil {
 			return nil, err
 		}
@@ -1453,9 +1517,47 @@ func (s *ImmuStore) ExportTx(txID uint64, tx *Tx) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// ExportedTxEntries returns the metadata and the entries of an exported transaction
+func ExportedTxEntries(exportedTx []byte) (*TxMetadata, []*KV, error) {
+	return parseExportedTx(exportedTx)
+}
+
 func (s *ImmuStore) ReplicateTx(exportedTx []byte, waitForIndexing bool) (*TxMetadata, error) {
+	md, entries, err := parseExportedTx(exportedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commitUsing(entries, md, waitForIndexing)
+}
+
+// ExportedTxMetadata returns the metadata of an exported transaction along with the root of the
+// hash tree built out of its entries, which matches md.Eh only when the entries were not altered
+func ExportedTxMetadata(exportedTx []byte) (md *TxMetadata, eh [sha256.Size]byte, err error) {
+	md, entries, err := parseExportedTx(exportedTx)
+	if err != nil {
+		return nil, eh, err
+	}
+
+	txEntries := make([]*TxEntry, len(entries))
+
+	for i, e := range entries {
+		txEntries[i] = NewTxEntry(e.Key, len(e.Value), sha256.Sum256(e.Value), 0)
+	}
+
+	tx := NewTxWithEntries(txEntries)
+
+	err = tx.BuildHashTree()
+	if err != nil {
+		return nil, eh, err
+	}
+
+	return md, tx.Eh(), nil
+}
+
+func parseExportedTx(exportedTx []byte) (*TxMetadata, []*KV, error) {
 	if len(exportedTx) < 4 {
-		return nil, ErrIllegalArguments
+		return nil, nil, ErrIllegalArguments
 	}
 
 	i := 0
@@ -1464,13 +1566,13 @@ func (s *ImmuStore) ReplicateTx(exportedTx []byte, waitForIndexing bool) (*TxMet
 	i += 4
 
 	if len(exportedTx[i:]) < mdLen {
-		return nil, ErrIllegalArguments
+		return nil, nil, ErrIllegalArguments
 	}
 
 	md := &TxMetadata{}
 	err := md.readFrom(exportedTx[i : i+mdLen])
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	i += mdLen
 
@@ -1478,7 +1580,7 @@ func (s *ImmuStore) ReplicateTx(exportedTx []byte, waitForIndexing bool) (*TxMet
 
 	for ei := range entries {
 		if len(exportedTx[i:]) < 8 {
-			return nil, ErrIllegalArguments
+			return nil, nil, ErrIllegalArguments
 		}
 
 		kLen := int(binary.BigEndian.Uint32(exportedTx[i:]))
@@ -1488,7 +1590,7 @@ func (s *ImmuStore) ReplicateTx(exportedTx []byte, waitForIndexing bool) (*TxMet
 		i += 4
 
 		if len(exportedTx[i:]) < kLen+vLen {
-			return nil, ErrIllegalArguments
+			return nil, nil, ErrIllegalArguments
 		}
 
 		entries[ei] = &KV{
@@ -1500,10 +1602,10 @@ func (s *ImmuStore) ReplicateTx(exportedTx []byte, waitForIndexing bool) (*TxMet
 	}
 
 	if i != len(exportedTx) {
-		return nil, ErrIllegalArguments
+		return nil, nil, ErrIllegalArguments
 	}
 
-	return s.commitUsing(entries, md, waitForIndexing)
+	return md, entries, nil
 }
 
 func (s *ImmuStore) ReadTx(txID uint64, tx *Tx) error {
@@ -1569,11 +1671,17 @@ func (s *ImmuStore) ReadValueAt(b []byte, off int64, hvalue [sha256.Size]byte) (
 			return n, ErrAlreadyClosed
 		}
 		if err != nil {
+			if s.readRepairedValue(b, off, hvalue) {
+				return len(b), nil
+			}
 			return n, err
 		}
 	}
 
 	if hvalue != sha256.Sum256(b) {
+		if s.readRepairedValue(b, off, hvalue) {
+			return len(b), nil
+		}
 		return len(b), ErrCorruptedData
 	}
 
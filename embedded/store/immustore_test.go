@@ -784,6 +784,24 @@ func TestImmudbStoreKVConstraints(t *testing.T) {
 
 	_, err = immuStore.Commit([]*KV{{Key: []byte{1, 0, 0}, Value: []byte{0, 0, 1}, Constraint: MustNotExist}, {Key: []byte{1, 0, 0}, Value: []byte{0, 1, 1}, Constraint: MustNotExist}}, true)
 	require.Equal(t, ErrDuplicatedKey, err)
+
+	md, err := immuStore.Commit([]*KV{{Key: []byte{0, 0, 0}, Value: []byte{2, 2, 2}, Constraint: MustExist}}, true)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), md.ID)
+
+	_, err = immuStore.Commit([]*KV{{Key: []byte{0, 0, 0}, Value: []byte{3, 3, 3}, Constraint: MustMatchTx, ExpectedTx: 2}}, true)
+	require.ErrorIs(t, err, ErrPreconditionFailed)
+
+	_, err = immuStore.Commit([]*KV{{Key: []byte{2}, Value: []byte{3, 3, 3}, Constraint: MustMatchTx, ExpectedTx: 2}}, true)
+	require.ErrorIs(t, err, ErrPreconditionFailed)
+
+	md, err = immuStore.Commit([]*KV{{Key: []byte{0, 0, 0}, Value: []byte{3, 3, 3}, Constraint: MustMatchTx, ExpectedTx: 3}}, true)
+	require.NoError(t, err)
+
+	v, tx, _, err = immuStore.Get([]byte{0, 0, 0})
+	require.NoError(t, err)
+	require.Equal(t, []byte{3, 3, 3}, v)
+	require.Equal(t, md.ID, tx)
 }
 
 func TestImmudbStoreCommitWith(t *testing.T) {
@@ -1614,6 +1632,76 @@ func TestExportAndReplicateTx(t *testing.T) {
 
 	_, err = replicaStore.ReplicateTx(nil, false)
 	require.Equal(t, ErrIllegalArguments, err)
+
+	emd, eh, err := ExportedTxMetadata(etx)
+	require.NoError(t, err)
+	require.Equal(t, md.Alh(), emd.Alh())
+	require.Equal(t, md.Eh, eh)
+
+	// altered values are reflected in the rebuilt hash tree
+	etx[len(etx)-1] ^= 0xFF
+
+	_, eh, err = ExportedTxMetadata(etx)
+	require.NoError(t, err)
+	require.NotEqual(t, md.Eh, eh)
+
+	_, _, err = ExportedTxMetadata(etx[:len(etx)-1])
+	require.Equal(t, ErrIllegalArguments, err)
+}
+
+func TestFilterExportedTx(t *testing.T) {
+	immuStore, err := Open("data_filter_exported_tx", DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("data_filter_exported_tx")
+
+	md, err := immuStore.Commit([]*KV{
+		{Key: []byte("tenant1/key1"), Value: []byte("value1")},
+		{Key: []byte("tenant2/key1"), Value: []byte("value2")},
+		{Key: []byte("tenant1/key2"), Value: []byte("value3")},
+	}, false)
+	require.NoError(t, err)
+
+	etx, err := immuStore.ExportTx(md.ID, immuStore.NewTx())
+	require.NoError(t, err)
+
+	_, err = FilterExportedTx(nil, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	ftx, err := FilterExportedTx(etx, func(key []byte) bool { return bytes.HasPrefix(key, []byte("tenant1/")) })
+	require.NoError(t, err)
+
+	fmd, entries, err := ExportedTxEntries(ftx)
+	require.NoError(t, err)
+	require.Equal(t, md.ID, fmd.ID)
+	require.Equal(t, md.PrevAlh, fmd.PrevAlh)
+	require.Equal(t, 2, fmd.NEntries)
+	require.Len(t, entries, 2)
+	require.Equal(t, []byte("tenant1/key1"), entries[0].Key)
+	require.Equal(t, []byte("value1"), entries[0].Value)
+	require.Equal(t, []byte("tenant1/key2"), entries[1].Key)
+	require.Equal(t, []byte("value3"), entries[1].Value)
+
+	// a filtered transaction can not be replicated as a whole
+	replicaStore, err := Open("data_filter_exported_tx_replica", DefaultOptions())
+	require.NoError(t, err)
+	defer os.RemoveAll("data_filter_exported_tx_replica")
+
+	_, err = replicaStore.ReplicateTx(ftx, false)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	ftx, err = FilterExportedTx(etx, func(key []byte) bool { return false })
+	require.NoError(t, err)
+
+	fmd, entries, err = ExportedTxEntries(ftx)
+	require.NoError(t, err)
+	require.Equal(t, 0, fmd.NEntries)
+	require.Empty(t, entries)
+
+	err = immuStore.Close()
+	require.NoError(t, err)
+
+	err = replicaStore.Close()
+	require.NoError(t, err)
 }
 
 var errEmulatedAppendableError = errors.New("emulated appendable error")
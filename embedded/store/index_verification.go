@@ -0,0 +1,361 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/codenotary/immudb/embedded/tbtree"
+)
+
+var ErrIndexRebuildUnsupported = errors.New("index rebuild is unsupported on read-only stores or when remote storage is used")
+var ErrIndexVerificationCancelled = errors.New("index verification cancelled")
+
+// rebuildIndexDirname holds the index while it gets rebuilt, it replaces the current one once up to date
+const rebuildIndexDirname = "index.rebuild"
+
+// indexHistoryPageSize is the number of revisions of a key read at once while verifying the index
+const indexHistoryPageSize = 1000
+
+// IndexVerification is the outcome of the verification of the index against the commit log
+type IndexVerification struct {
+	// IndexedTx is the last transaction included in the verified index
+	IndexedTx uint64
+	KeyCount  uint64
+	// RevisionCount is the number of revisions held by the index, it must match EntriesCount
+	RevisionCount uint64
+	// EntriesCount is the number of entries committed up to IndexedTx
+	EntriesCount uint64
+	// DanglingKeys is the number of keys whose indexed revisions do not resolve to committed entries
+	DanglingKeys uint64
+	// Consistent is set when every indexed revision resolves to a committed entry and vice versa
+	Consistent bool
+}
+
+// VerifyIndex checks that every key in the index resolves to the entries committed in the transactions it references
+// and that every entry committed up to the last indexed transaction is held by the index.
+// Revisions are compared by count and by an order independent digest of their keys and transaction ids,
+// so the commit log is read sequentially. The verification can be interrupted through the cancellation channel
+func (s *ImmuStore) VerifyIndex(cancellation <-chan struct{}) (*IndexVerification, error) {
+	snap, err := s.SnapshotSince(s.IndexInfo())
+	if err != nil {
+		return nil, err
+	}
+	defer snap.Close()
+
+	verification := &IndexVerification{IndexedTx: snap.Ts()}
+
+	committedTxID, _, _ := s.commitState()
+	if verification.IndexedTx > committedTxID {
+		return verification, nil
+	}
+
+	r, err := snap.NewKeyReader(&KeyReaderSpec{})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	tx := s.NewTx()
+	txID := uint64(0)
+
+	var indexDigest uint64
+
+	for {
+		if isCancelled(cancellation) {
+			return nil, ErrIndexVerificationCancelled
+		}
+
+		key, valRef, ktxID, hc, err := r.Read()
+		if err == ErrNoMoreEntries {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		verification.KeyCount++
+		verification.RevisionCount += hc
+
+		dangling := ktxID == 0 || ktxID > verification.IndexedTx
+
+		// the latest revision must match the committed entry, the older ones are accounted by the digest
+		if !dangling && ktxID != txID {
+			err = s.ReadTx(ktxID, tx)
+			if err == ErrorCorruptedTxData {
+				dangling = true
+			} else if err != nil {
+				return nil, err
+			}
+
+			txID = ktxID
+		}
+
+		if !dangling {
+			i, err := tx.IndexOf(key)
+
+			dangling = err != nil ||
+				tx.entries[i].hVal != valRef.hVal ||
+				tx.entries[i].vOff != valRef.vOff ||
+				uint32(tx.entries[i].vLen) != valRef.valLen
+		}
+
+		if !dangling {
+			dangling, err = addHistoryDigest(snap, key, ktxID, hc, &indexDigest)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if dangling {
+			verification.DanglingKeys++
+			s.log.Warningf("Key %q of the index at '%s' does not resolve to committed entries", key, s.path)
+		}
+	}
+
+	var logDigest uint64
+
+	if verification.IndexedTx > 0 {
+		txReader, err := s.newTxReader(1, false, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		for {
+			if isCancelled(cancellation) {
+				return nil, ErrIndexVerificationCancelled
+			}
+
+			tx, err := txReader.Read()
+			if err == ErrNoMoreEntries {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if tx.ID > verification.IndexedTx {
+				break
+			}
+
+			for _, e := range tx.Entries() {
+				logDigest += revisionDigest(e.key(), tx.ID)
+			}
+
+			verification.EntriesCount += uint64(tx.nentries)
+		}
+	}
+
+	verification.Consistent = verification.DanglingKeys == 0 &&
+		verification.RevisionCount == verification.EntriesCount &&
+		indexDigest == logDigest
+
+	return verification, nil
+}
+
+// addHistoryDigest adds the revisions of the key to the digest, the key is reported as dangling
+// when its history does not start at the latest revision or is not strictly decreasing
+func addHistoryDigest(snap *Snapshot, key []byte, latestTxID, hc uint64, digest *uint64) (dangling bool, err error) {
+	prevTxID := latestTxID + 1
+
+	for offset := uint64(0); offset < hc; offset += indexHistoryPageSize {
+		tss, err := snap.History(key, offset, true, indexHistoryPageSize)
+		if err != nil {
+			return false, err
+		}
+
+		if len(tss) == 0 {
+			return true, nil
+		}
+
+		if offset == 0 && tss[0] != latestTxID {
+			return true, nil
+		}
+
+		for _, ts := range tss {
+			if ts == 0 || ts >= prevTxID {
+				return true, nil
+			}
+
+			*digest += revisionDigest(key, ts)
+			prevTxID = ts
+		}
+	}
+
+	return false, nil
+}
+
+func revisionDigest(key []byte, txID uint64) uint64 {
+	var b [txIDSize]byte
+	binary.BigEndian.PutUint64(b[:], txID)
+
+	h := sha256.Sum256(append(b[:], key...))
+
+	return binary.BigEndian.Uint64(h[:])
+}
+
+func isCancelled(cancellation <-chan struct{}) bool {
+	select {
+	case <-cancellation:
+		return true
+	default:
+		return false
+	}
+}
+
+// RebuildIndex builds a new index out of the commit log and replaces the current one with it.
+// The current index keeps serving reads until the new one has caught up
+func (s *ImmuStore) RebuildIndex() error {
+	if s.compactionDisabled || s.readOnly {
+		return ErrIndexRebuildUnsupported
+	}
+
+	return s.indexer.Rebuild()
+}
+
+func (idx *indexer) Rebuild() (err error) {
+	idx.compactionMutex.Lock()
+	defer idx.compactionMutex.Unlock()
+
+	idx.store.notify(Info, true, "Rebuilding index '%s'...", idx.store.path)
+
+	defer func() {
+		if err == nil {
+			idx.store.notify(Info, true, "Index '%s' successfully rebuilt", idx.store.path)
+		} else {
+			idx.store.notify(Info, true, "Rebuild of index '%s' returned: %v", idx.store.path, err)
+		}
+	}()
+
+	opts := idx.index.GetOptions()
+
+	rebuildPath := filepath.Join(idx.store.path, rebuildIndexDirname)
+
+	// leftovers of an interrupted rebuild are discarded
+	err = os.RemoveAll(rebuildPath)
+	if err != nil {
+		return err
+	}
+
+	index, err := tbtree.Open(rebuildPath, opts)
+	if err != nil {
+		return err
+	}
+
+	// the rebuild uses its own buffers, the indexing in progress is not affected
+	tx := idx.store.NewTx()
+
+	kvs := make([]*tbtree.KV, idx.store.maxTxEntries)
+	for i := range kvs {
+		kvs[i] = &tbtree.KV{}
+	}
+
+	err = idx.store.indexUpTo(index, tx, kvs)
+	if err != nil {
+		index.Close()
+		return err
+	}
+
+	err = index.Close()
+	if err != nil {
+		return err
+	}
+
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	if idx.closed {
+		return ErrAlreadyClosed
+	}
+
+	idx.stop()
+	defer idx.resume()
+
+	err = idx.index.Close()
+	if err != nil {
+		return err
+	}
+
+	// should the swap get interrupted, an empty index is rebuilt from scratch when the store is opened
+	err = os.RemoveAll(idx.path)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(rebuildPath, idx.path)
+	if err != nil {
+		return err
+	}
+
+	index, err = tbtree.Open(idx.path, opts)
+	if err != nil {
+		return err
+	}
+
+	idx.index = index
+
+	// transactions committed during the rebuild are indexed before the waitees get notified again
+	return idx.store.indexUpTo(index, tx, kvs)
+}
+
+// indexUpTo indexes into the given tree the transactions committed after its last indexed one
+func (s *ImmuStore) indexUpTo(index *tbtree.TBtree, tx *Tx, kvs []*tbtree.KV) error {
+	committedTxID, _, _ := s.commitState()
+
+	if index.Ts() >= committedTxID {
+		return nil
+	}
+
+	txReader, err := s.newTxReader(index.Ts()+1, false, tx)
+	if err != nil {
+		return err
+	}
+
+	for index.Ts() < committedTxID {
+		tx, err := txReader.Read()
+		if err != nil {
+			return err
+		}
+
+		err = index.BulkInsert(indexedKVs(tx, kvs))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexedKVs fills kvs with the keys of the transaction entries mapped to their value references
+func indexedKVs(tx *Tx, kvs []*tbtree.KV) []*tbtree.KV {
+	txEntries := tx.Entries()
+
+	for i, e := range txEntries {
+		var b [szSize + offsetSize + sha256.Size]byte
+		binary.BigEndian.PutUint32(b[:], uint32(e.vLen))
+		binary.BigEndian.PutUint64(b[szSize:], uint64(e.vOff))
+		copy(b[szSize+offsetSize:], e.hVal[:])
+
+		kvs[i].K = e.key()
+		kvs[i].V = b[:]
+	}
+
+	return kvs[:len(txEntries)]
+}
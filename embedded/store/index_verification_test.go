@@ -0,0 +1,121 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package store
+
+import (
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/tbtree"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyIndex(t *testing.T) {
+	defer os.RemoveAll("data_verify_index")
+
+	immuStore, err := Open("data_verify_index", DefaultOptions())
+	require.NoError(t, err)
+	defer immuStore.Close()
+
+	verification, err := immuStore.VerifyIndex(nil)
+	require.NoError(t, err)
+	require.True(t, verification.Consistent)
+	require.Zero(t, verification.IndexedTx)
+
+	for i := 0; i < 3; i++ {
+		_, err = immuStore.Commit([]*KV{
+			{Key: []byte("key1"), Value: []byte("value1")},
+			{Key: []byte("key2"), Value: []byte("value2")},
+		}, true)
+		require.NoError(t, err)
+	}
+
+	verification, err = immuStore.VerifyIndex(nil)
+	require.NoError(t, err)
+	require.True(t, verification.Consistent)
+	require.Equal(t, uint64(3), verification.IndexedTx)
+	require.Equal(t, uint64(2), verification.KeyCount)
+	require.Equal(t, uint64(6), verification.RevisionCount)
+	require.Equal(t, uint64(6), verification.EntriesCount)
+	require.Zero(t, verification.DanglingKeys)
+
+	cancellation := make(chan struct{})
+	close(cancellation)
+
+	_, err = immuStore.VerifyIndex(cancellation)
+	require.Equal(t, ErrIndexVerificationCancelled, err)
+
+	// the next tx gets indexed with a key it does not hold while regular indexing is paused
+	immuStore.indexer.Pause()
+
+	_, err = immuStore.Commit([]*KV{{Key: []byte("key3"), Value: []byte("value3")}}, false)
+	require.NoError(t, err)
+
+	tx := immuStore.NewTx()
+
+	err = immuStore.ReadTx(4, tx)
+	require.NoError(t, err)
+
+	kvs := indexedKVs(tx, []*tbtree.KV{{}})
+	kvs[0].K = []byte("key4")
+
+	err = immuStore.indexer.index.BulkInsert(kvs)
+	require.NoError(t, err)
+
+	immuStore.indexer.Resume()
+
+	verification, err = immuStore.VerifyIndex(nil)
+	require.NoError(t, err)
+	require.False(t, verification.Consistent)
+	require.Equal(t, uint64(4), verification.IndexedTx)
+	require.Equal(t, uint64(1), verification.DanglingKeys)
+
+	err = immuStore.RebuildIndex()
+	require.NoError(t, err)
+
+	verification, err = immuStore.VerifyIndex(nil)
+	require.NoError(t, err)
+	require.True(t, verification.Consistent)
+	require.Equal(t, uint64(4), verification.IndexedTx)
+	require.Equal(t, uint64(3), verification.KeyCount)
+
+	_, _, _, err = immuStore.Get([]byte("key4"))
+	require.Equal(t, ErrKeyNotFound, err)
+
+	val, txID, _, err := immuStore.Get([]byte("key3"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value3"), val)
+	require.Equal(t, uint64(4), txID)
+
+	_, err = immuStore.Commit([]*KV{{Key: []byte("key5"), Value: []byte("value5")}}, true)
+	require.NoError(t, err)
+
+	verification, err = immuStore.VerifyIndex(nil)
+	require.NoError(t, err)
+	require.True(t, verification.Consistent)
+	require.Equal(t, uint64(5), verification.IndexedTx)
+}
+
+func TestRebuildIndexUnsupported(t *testing.T) {
+	defer os.RemoveAll("data_rebuild_index")
+
+	immuStore, err := Open("data_rebuild_index", DefaultOptions().WithCompactionDisabled(true))
+	require.NoError(t, err)
+	defer immuStore.Close()
+
+	err = immuStore.RebuildIndex()
+	require.Equal(t, ErrIndexRebuildUnsupported, err)
+}
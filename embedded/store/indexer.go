@@ -16,8 +16,6 @@ limitations under the License.
 package store
 
 import (
-	"crypto/sha256"
-	"encoding/binary"
 	"sync"
 	"time"
 
@@ -34,6 +32,7 @@ type indexer struct {
 	index *tbtree.TBtree
 
 	cancellation chan struct{}
+	done         chan struct{}
 	wHub         *watchers.WatchersHub
 
 	state     int
@@ -214,6 +213,9 @@ func (idx *indexer) stop() {
 	idx.stateCond.L.Unlock()
 	idx.stateCond.Signal()
 
+	// the index must not be touched by the stopped goroutine once stop returns
+	<-idx.done
+
 	idx.store.notify(Info, true, "Indexing gracefully stopped at '%s'", idx.store.path)
 }
 
@@ -221,7 +223,8 @@ func (idx *indexer) resume() {
 	idx.stateCond.L.Lock()
 	idx.state = running
 	idx.cancellation = make(chan struct{})
-	go idx.doIndexing(idx.cancellation)
+	idx.done = make(chan struct{})
+	go idx.doIndexing(idx.cancellation, idx.done)
 	idx.stateCond.L.Unlock()
 
 	idx.store.notify(Info, true, "Indexing in progress at '%s'", idx.store.path)
@@ -268,7 +271,9 @@ func (idx *indexer) Pause() {
 	idx.stateCond.L.Unlock()
 }
 
-func (idx *indexer) doIndexing(cancellation <-chan struct{}) {
+func (idx *indexer) doIndexing(cancellation <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
 	for {
 		lastIndexedTx := idx.index.Ts()
 
@@ -282,7 +287,9 @@ func (idx *indexer) doIndexing(cancellation <-chan struct{}) {
 		}
 		if err != nil {
 			idx.store.notify(Error, true, "Indexing failed at '%s' due to error: %v", idx.store.path, err)
-			time.Sleep(60 * time.Second)
+			if !sleepOrCancel(60*time.Second, cancellation) {
+				return
+			}
 		}
 
 		committedTxID, _, _ := idx.store.commitState()
@@ -303,17 +310,34 @@ func (idx *indexer) doIndexing(cancellation <-chan struct{}) {
 		}
 		idx.stateCond.L.Unlock()
 
+		// the index may have been updated while indexing was paused
+		if idx.index.Ts() != lastIndexedTx {
+			continue
+		}
+
 		err = idx.indexSince(lastIndexedTx+1, 10)
 		if err == ErrAlreadyClosed || err == tbtree.ErrAlreadyClosed {
 			return
 		}
 		if err != nil {
 			idx.store.notify(Error, true, "Indexing failed at '%s' due to error: %v", idx.store.path, err)
-			time.Sleep(60 * time.Second)
+			if !sleepOrCancel(60*time.Second, cancellation) {
+				return
+			}
 		}
 	}
 }
 
+// sleepOrCancel waits for the given duration, returning false if cancellation was requested meanwhile
+func sleepOrCancel(d time.Duration, cancellation <-chan struct{}) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-cancellation:
+		return false
+	}
+}
+
 func (idx *indexer) indexSince(txID uint64, limit int) error {
 	txReader, err := idx.store.newTxReader(txID, false, idx.tx)
 	if err != nil {
@@ -329,19 +353,7 @@ func (idx *indexer) indexSince(txID uint64, limit int) error {
 			return err
 		}
 
-		txEntries := tx.Entries()
-
-		for i, e := range txEntries {
-			var b [szSize + offsetSize + sha256.Size]byte
-			binary.BigEndian.PutUint32(b[:], uint32(e.vLen))
-			binary.BigEndian.PutUint64(b[szSize:], uint64(e.vOff))
-			copy(b[szSize+offsetSize:], e.hVal[:])
-
-			idx.store._kvs[i].K = e.key()
-			idx.store._kvs[i].V = b[:]
-		}
-
-		err = idx.index.BulkInsert(idx.store._kvs[:len(txEntries)])
+		err = idx.index.BulkInsert(indexedKVs(tx, idx.store._kvs))
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package store
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var ErrTxMismatch = errors.New("exported tx does not match the local one")
+
+// repairsDir holds the copies of the values repaired after their value log got damaged,
+// one file per value named after its offset
+const repairsDir = "repairs"
+
+// RepairTx recovers the damaged values of a committed transaction out of its exported copy, e.g. taken from a replica.
+// Values are accepted only when they match the digests kept in the local transaction log, so the transaction
+// itself must be intact. Repaired values are written aside the value logs and used from then on by ReadValueAt.
+// The number of repaired entries is returned
+func (s *ImmuStore) RepairTx(exportedTx []byte) (int, error) {
+	md, entries, err := parseExportedTx(exportedTx)
+	if err != nil {
+		return 0, err
+	}
+
+	tx := s.NewTx()
+
+	err = s.ReadTx(md.ID, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	if tx.nentries != len(entries) {
+		return 0, ErrTxMismatch
+	}
+
+	s.repairMutex.Lock()
+	defer s.repairMutex.Unlock()
+
+	repaired := 0
+
+	for i, e := range tx.Entries() {
+		if !bytes.Equal(e.Key(), entries[i].Key) || e.hVal != sha256.Sum256(entries[i].Value) {
+			return repaired, ErrTxMismatch
+		}
+
+		val := make([]byte, e.vLen)
+
+		_, err = s.ReadValueAt(val, e.vOff, e.hVal)
+		if err == nil {
+			continue
+		}
+		if err == ErrAlreadyClosed {
+			return repaired, err
+		}
+
+		err = s.writeRepairedValue(e.vOff, entries[i].Value)
+		if err != nil {
+			return repaired, err
+		}
+
+		s.log.Infof("Value of entry %d of tx %d repaired at '%s'", i, tx.ID, s.path)
+
+		repaired++
+	}
+
+	return repaired, nil
+}
+
+func (s *ImmuStore) repairedValuePath(off int64) string {
+	return filepath.Join(s.path, repairsDir, fmt.Sprintf("%016x.val", off))
+}
+
+func (s *ImmuStore) writeRepairedValue(off int64, value []byte) error {
+	err := os.MkdirAll(filepath.Join(s.path, repairsDir), 0755)
+	if err != nil {
+		return err
+	}
+
+	path := s.repairedValuePath(off)
+
+	f, err := ioutil.TempFile(filepath.Dir(path), "repair")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(value)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// readRepairedValue fills b with the repaired copy of the value at the given offset, if there is one matching hvalue
+func (s *ImmuStore) readRepairedValue(b []byte, off int64, hvalue [sha256.Size]byte) bool {
+	val, err := ioutil.ReadFile(s.repairedValuePath(off))
+	if err != nil || len(val) != len(b) || hvalue != sha256.Sum256(val) {
+		return false
+	}
+
+	copy(b, val)
+
+	return true
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package store
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// tamperValueLog overwrites every occurrence of the value in the value logs of the store
+func tamperValueLog(t *testing.T, path string, value []byte) {
+	files, err := filepath.Glob(filepath.Join(path, "val_*", "*.val"))
+	require.NoError(t, err)
+
+	tampered := false
+
+	for _, f := range files {
+		content, err := ioutil.ReadFile(f)
+		require.NoError(t, err)
+
+		if !bytes.Contains(content, value) {
+			continue
+		}
+
+		err = ioutil.WriteFile(f, bytes.ReplaceAll(content, value, bytes.ToUpper(value)), 0644)
+		require.NoError(t, err)
+
+		tampered = true
+	}
+
+	require.True(t, tampered)
+}
+
+func TestRepairTx(t *testing.T) {
+	defer os.RemoveAll("data_repair")
+
+	immuStore, err := Open("data_repair", DefaultOptions())
+	require.NoError(t, err)
+
+	_, err = immuStore.Commit([]*KV{
+		{Key: []byte("key1"), Value: []byte("value1")},
+		{Key: []byte("key2"), Value: []byte("untouched")},
+	}, false)
+	require.NoError(t, err)
+
+	_, err = immuStore.Commit([]*KV{{Key: []byte("key3"), Value: []byte("value3")}}, false)
+	require.NoError(t, err)
+
+	tx := immuStore.NewTx()
+
+	// copies of the transactions as a replica would provide them
+	exportedTx1, err := immuStore.ExportTx(1, tx)
+	require.NoError(t, err)
+
+	exportedTx2, err := immuStore.ExportTx(2, tx)
+	require.NoError(t, err)
+
+	err = immuStore.Close()
+	require.NoError(t, err)
+
+	tamperValueLog(t, "data_repair", []byte("value1"))
+
+	immuStore, err = Open("data_repair", DefaultOptions())
+	require.NoError(t, err)
+
+	tx = immuStore.NewTx()
+
+	err = immuStore.ReadTx(1, tx)
+	require.NoError(t, err)
+
+	e := tx.Entries()[0]
+	val := make([]byte, e.VLen())
+
+	_, err = immuStore.ReadValueAt(val, e.VOff(), e.HVal())
+	require.Equal(t, ErrCorruptedData, err)
+
+	_, err = immuStore.RepairTx(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	// a different transaction is rejected
+	etx := append([]byte(nil), exportedTx2...)
+
+	// the id of the exported tx follows the length of its metadata
+	etx[4+7] = 1
+
+	_, err = immuStore.RepairTx(etx)
+	require.Equal(t, ErrTxMismatch, err)
+
+	etx = exportedTx1
+
+	// altered copies are rejected as well
+	altered := bytes.ReplaceAll(etx, []byte("value1"), []byte("valueX"))
+
+	_, err = immuStore.RepairTx(altered)
+	require.Equal(t, ErrTxMismatch, err)
+
+	repaired, err := immuStore.RepairTx(etx)
+	require.NoError(t, err)
+	require.Equal(t, 1, repaired)
+
+	_, err = immuStore.ReadValueAt(val, e.VOff(), e.HVal())
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), val)
+
+	// repairing again is a no-op
+	repaired, err = immuStore.RepairTx(etx)
+	require.NoError(t, err)
+	require.Zero(t, repaired)
+
+	err = immuStore.Close()
+	require.NoError(t, err)
+
+	// repairs survive restarts
+	immuStore, err = Open("data_repair", DefaultOptions())
+	require.NoError(t, err)
+	defer immuStore.Close()
+
+	_, err = immuStore.ReadValueAt(val, e.VOff(), e.HVal())
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), val)
+}
@@ -317,6 +317,7 @@ type TxEntry struct {
 	hVal       [sha256.Size]byte
 	vOff       int64
 	constraint KVConstraint
+	expectedTx uint64
 }
 
 func NewTxEntry(key []byte, vLen int, hVal [sha256.Size]byte, vOff int64) *TxEntry {
@@ -0,0 +1,182 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package anchor publishes database roots to external ledgers
+package anchor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrIllegalArguments = errors.New("illegal arguments")
+var ErrUnknownBackend = errors.New("unknown anchoring backend")
+var ErrPublishingRejected = errors.New("root publishing rejected")
+
+const (
+	// BackendWebhook posts the root as JSON to an HTTP endpoint
+	BackendWebhook = "webhook"
+	// BackendOpenTimestamps submits the root to an OpenTimestamps calendar, which commits it into the Bitcoin blockchain
+	BackendOpenTimestamps = "opentimestamps"
+)
+
+// DefaultOpenTimestampsCalendar is used when no calendar url is provided
+const DefaultOpenTimestampsCalendar = "https://a.pool.opentimestamps.org"
+
+const maxReceiptSize = 1 << 20
+
+// Root is the state of a database to be anchored
+type Root struct {
+	Database string
+	TxID     uint64
+	TxHash   []byte
+}
+
+// Receipt is the proof of publication returned by the ledger
+type Receipt struct {
+	Ledger      string
+	PublishedAt time.Time
+	Data        []byte
+}
+
+// Publisher publishes roots to an external ledger
+type Publisher interface {
+	Ledger() string
+	Publish(root *Root) (*Receipt, error)
+}
+
+// NewPublisher returns the publisher of the given backend
+func NewPublisher(backend, url string, timeout time.Duration) (Publisher, error) {
+	switch backend {
+	case BackendWebhook:
+		return NewWebhookPublisher(url, timeout)
+	case BackendOpenTimestamps:
+		if url == "" {
+			url = DefaultOpenTimestampsCalendar
+		}
+		return NewOpenTimestampsPublisher(url, timeout)
+	}
+
+	return nil, fmt.Errorf("%w: '%s'", ErrUnknownBackend, backend)
+}
+
+type webhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+type webhookPayload struct {
+	Database string `json:"database"`
+	TxID     uint64 `json:"txId"`
+	TxHash   string `json:"txHash"`
+}
+
+// NewWebhookPublisher returns a publisher posting roots as JSON to url.
+// The response body of the endpoint is kept as receipt
+func NewWebhookPublisher(url string, timeout time.Duration) (Publisher, error) {
+	if url == "" {
+		return nil, ErrIllegalArguments
+	}
+
+	return &webhookPublisher{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (p *webhookPublisher) Ledger() string {
+	return BackendWebhook
+}
+
+func (p *webhookPublisher) Publish(root *Root) (*Receipt, error) {
+	if root == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		Database: root.Database,
+		TxID:     root.TxID,
+		TxHash:   hex.EncodeToString(root.TxHash),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := post(p.client, p.url, "application/json", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Receipt{Ledger: p.Ledger(), PublishedAt: time.Now(), Data: data}, nil
+}
+
+type openTimestampsPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewOpenTimestampsPublisher returns a publisher submitting roots to the OpenTimestamps calendar at url.
+// The receipt is the pending timestamp returned by the calendar, which can be upgraded to a
+// Bitcoin attestation with the OpenTimestamps tools once the calendar commitment is confirmed
+func NewOpenTimestampsPublisher(url string, timeout time.Duration) (Publisher, error) {
+	if url == "" {
+		return nil, ErrIllegalArguments
+	}
+
+	return &openTimestampsPublisher{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (p *openTimestampsPublisher) Ledger() string {
+	return BackendOpenTimestamps
+}
+
+func (p *openTimestampsPublisher) Publish(root *Root) (*Receipt, error) {
+	if root == nil || len(root.TxHash) == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	data, err := post(p.client, p.url+"/digest", "application/x-www-form-urlencoded", root.TxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Receipt{Ledger: p.Ledger(), PublishedAt: time.Now(), Data: data}, nil
+}
+
+func post(client *http.Client, url, contentType string, body []byte) ([]byte, error) {
+	resp, err := client.Post(url, contentType, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: http status %d", ErrPublishingRejected, resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(io.LimitReader(resp.Body, maxReceiptSize))
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package anchor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPublisher(t *testing.T) {
+	_, err := NewPublisher("unknown", "http://localhost", time.Second)
+	require.ErrorIs(t, err, ErrUnknownBackend)
+
+	_, err = NewPublisher(BackendWebhook, "", time.Second)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = NewOpenTimestampsPublisher("", time.Second)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	p, err := NewPublisher(BackendOpenTimestamps, "", time.Second)
+	require.NoError(t, err)
+	require.Equal(t, BackendOpenTimestamps, p.Ledger())
+	require.Equal(t, DefaultOpenTimestampsCalendar, p.(*openTimestampsPublisher).url)
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	txHash := sha256.Sum256([]byte("root"))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var payload webhookPayload
+		err := json.NewDecoder(r.Body).Decode(&payload)
+		require.NoError(t, err)
+
+		if payload.Database != "defaultdb" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		require.Equal(t, uint64(3), payload.TxID)
+		require.Equal(t, hex.EncodeToString(txHash[:]), payload.TxHash)
+
+		w.Write([]byte("receipt-3"))
+	}))
+	defer srv.Close()
+
+	p, err := NewPublisher(BackendWebhook, srv.URL, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, BackendWebhook, p.Ledger())
+
+	_, err = p.Publish(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	receipt, err := p.Publish(&Root{Database: "defaultdb", TxID: 3, TxHash: txHash[:]})
+	require.NoError(t, err)
+	require.Equal(t, BackendWebhook, receipt.Ledger)
+	require.Equal(t, []byte("receipt-3"), receipt.Data)
+	require.False(t, receipt.PublishedAt.IsZero())
+
+	_, err = p.Publish(&Root{Database: "otherdb", TxID: 3, TxHash: txHash[:]})
+	require.ErrorIs(t, err, ErrPublishingRejected)
+
+	srv.Close()
+
+	_, err = p.Publish(&Root{Database: "defaultdb", TxID: 3, TxHash: txHash[:]})
+	require.Error(t, err)
+}
+
+func TestOpenTimestampsPublisher(t *testing.T) {
+	txHash := sha256.Sum256([]byte("root"))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/digest", r.URL.Path)
+
+		digest, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, txHash[:], digest)
+
+		w.Write(append([]byte{0xf0, 0x10}, digest...))
+	}))
+	defer srv.Close()
+
+	p, err := NewPublisher(BackendOpenTimestamps, srv.URL+"/", time.Second)
+	require.NoError(t, err)
+
+	_, err = p.Publish(&Root{Database: "defaultdb", TxID: 3})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	receipt, err := p.Publish(&Root{Database: "defaultdb", TxID: 3, TxHash: txHash[:]})
+	require.NoError(t, err)
+	require.Equal(t, BackendOpenTimestamps, receipt.Ledger)
+	require.Equal(t, append([]byte{0xf0, 0x10}, txHash[:]...), receipt.Data)
+}
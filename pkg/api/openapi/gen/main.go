@@ -0,0 +1,95 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// gen converts the swagger definition of the REST gateway into the OpenAPI v3 specification,
+// written both as a JSON file and as a Go source embedding it
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/codenotary/immudb/pkg/api/openapi"
+)
+
+func main() {
+	src := flag.String("src", "", "swagger 2.0 definition to convert")
+	server := flag.String("server", "", "URL the API is served under")
+	jsonDest := flag.String("json", "", "destination of the OpenAPI v3 specification")
+	goDest := flag.String("go", "", "destination of the Go source embedding the specification")
+	flag.Parse()
+
+	swagger, err := ioutil.ReadFile(*src)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	spec, err := openapi.FromSwagger(swagger, *server)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	spec = append(spec, '\n')
+
+	if *jsonDest != "" {
+		err = ioutil.WriteFile(*jsonDest, spec, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *goDest != "" {
+		err = ioutil.WriteFile(*goDest, goSource(spec), 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func goSource(spec []byte) []byte {
+	var b bytes.Buffer
+
+	b.WriteString("// Code generated by go run ./gen. DO NOT EDIT.\n\n")
+	b.WriteString("package openapi\n\n")
+	b.WriteString("const spec = \"\" +\n")
+
+	lines := strings.SplitAfter(string(spec), "\n")
+
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		b.WriteString("\t" + strconv.Quote(line))
+		if i < len(lines)-2 {
+			b.WriteString(" +")
+		}
+		b.WriteString("\n")
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(fmt.Errorf("formatting generated source: %w", err))
+	}
+
+	return src
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package openapi holds the OpenAPI v3 specification of the REST gateway,
+// generated from the swagger definition of the gRPC services
+package openapi
+
+//go:generate go run ./gen -src ../schema/schema.swagger.json -server /api -json openapi.json -go spec.go
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Version is the OpenAPI version of the generated specifications
+const Version = "3.0.3"
+
+var ErrUnsupportedSwaggerVersion = errors.New("unsupported swagger version")
+
+// document is the top level object of the specification, keeping the fields in the usual order
+type document struct {
+	OpenAPI      string        `json:"openapi"`
+	Info         interface{}   `json:"info,omitempty"`
+	Servers      []interface{} `json:"servers,omitempty"`
+	Tags         interface{}   `json:"tags,omitempty"`
+	Paths        interface{}   `json:"paths"`
+	Components   interface{}   `json:"components,omitempty"`
+	Security     interface{}   `json:"security,omitempty"`
+	ExternalDocs interface{}   `json:"externalDocs,omitempty"`
+}
+
+// Spec returns the OpenAPI v3 specification of the REST gateway
+func Spec() []byte {
+	return []byte(spec)
+}
+
+// FromSwagger converts a swagger 2.0 specification into an OpenAPI v3 one, the API being served under serverURL
+func FromSwagger(swagger []byte, serverURL string) ([]byte, error) {
+	var v2 map[string]interface{}
+
+	err := json.Unmarshal(swagger, &v2)
+	if err != nil {
+		return nil, err
+	}
+
+	if v2["swagger"] != "2.0" {
+		return nil, ErrUnsupportedSwaggerVersion
+	}
+
+	v3 := &document{
+		OpenAPI:      Version,
+		Info:         v2["info"],
+		Tags:         v2["tags"],
+		Security:     v2["security"],
+		ExternalDocs: v2["externalDocs"],
+	}
+
+	if basePath, ok := v2["basePath"].(string); ok && serverURL == "" {
+		serverURL = basePath
+	}
+	if serverURL != "" {
+		v3.Servers = []interface{}{map[string]interface{}{"url": serverURL}}
+	}
+
+	consumes := stringList(v2["consumes"], "application/json")
+	produces := stringList(v2["produces"], "application/json")
+
+	paths := make(map[string]interface{})
+
+	for path, item := range asMap(v2["paths"]) {
+		ops := make(map[string]interface{})
+
+		for method, op := range asMap(item) {
+			if method == "parameters" {
+				ops[method] = convertParameters(op)
+				continue
+			}
+
+			ops[method] = convertOperation(asMap(op), consumes, produces)
+		}
+
+		paths[path] = ops
+	}
+
+	v3.Paths = rewriteRefs(paths)
+
+	components := make(map[string]interface{})
+
+	if definitions, ok := v2["definitions"]; ok {
+		components["schemas"] = definitions
+	}
+
+	if secDefs, ok := v2["securityDefinitions"]; ok {
+		schemes := make(map[string]interface{})
+		for name, secDef := range asMap(secDefs) {
+			schemes[name] = convertSecurityScheme(asMap(secDef))
+		}
+		components["securitySchemes"] = schemes
+	}
+
+	if len(components) > 0 {
+		v3.Components = rewriteRefs(components)
+	}
+
+	return json.MarshalIndent(v3, "", "  ")
+}
+
+func convertOperation(op map[string]interface{}, consumes, produces []string) map[string]interface{} {
+	v3 := make(map[string]interface{})
+
+	for k, v := range op {
+		switch k {
+		case "consumes":
+			consumes = stringList(v, consumes...)
+		case "produces":
+			produces = stringList(v, produces...)
+		case "schemes":
+		case "parameters", "responses":
+		default:
+			v3[k] = v
+		}
+	}
+
+	var params []interface{}
+
+	for _, p := range asList(op["parameters"]) {
+		param := asMap(p)
+
+		if param["in"] == "body" {
+			body := map[string]interface{}{
+				"content": content(param["schema"], consumes),
+			}
+			if required, ok := param["required"]; ok {
+				body["required"] = required
+			}
+			if desc, ok := param["description"]; ok {
+				body["description"] = desc
+			}
+			v3["requestBody"] = body
+			continue
+		}
+
+		params = append(params, convertParameter(param))
+	}
+
+	if len(params) > 0 {
+		v3["parameters"] = params
+	}
+
+	responses := make(map[string]interface{})
+
+	for code, r := range asMap(op["responses"]) {
+		resp := make(map[string]interface{})
+
+		for k, v := range asMap(r) {
+			switch k {
+			case "schema":
+				resp["content"] = content(v, produces)
+			case "headers":
+				headers := make(map[string]interface{})
+				for name, h := range asMap(v) {
+					headers[name] = convertHeader(asMap(h))
+				}
+				resp["headers"] = headers
+			case "examples":
+			default:
+				resp[k] = v
+			}
+		}
+
+		responses[code] = resp
+	}
+
+	v3["responses"] = responses
+
+	return v3
+}
+
+func convertParameters(params interface{}) []interface{} {
+	var v3 []interface{}
+
+	for _, p := range asList(params) {
+		v3 = append(v3, convertParameter(asMap(p)))
+	}
+
+	return v3
+}
+
+// schemaFields are the parameter fields moved into the parameter schema
+var schemaFields = []string{
+	"type", "format", "items", "enum", "default", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+	"minLength", "maxLength", "pattern", "minItems", "maxItems", "uniqueItems", "multipleOf",
+}
+
+func convertParameter(param map[string]interface{}) map[string]interface{} {
+	v3 := make(map[string]interface{})
+	schema := make(map[string]interface{})
+
+	for k, v := range param {
+		v3[k] = v
+	}
+
+	for _, k := range schemaFields {
+		if v, ok := v3[k]; ok {
+			schema[k] = v
+			delete(v3, k)
+		}
+	}
+
+	if len(schema) > 0 {
+		v3["schema"] = schema
+	}
+
+	if format, ok := v3["collectionFormat"]; ok {
+		delete(v3, "collectionFormat")
+
+		switch format {
+		case "multi":
+			v3["style"] = "form"
+			v3["explode"] = true
+		case "ssv":
+			v3["style"] = "spaceDelimited"
+		case "pipes":
+			v3["style"] = "pipeDelimited"
+		default:
+			v3["explode"] = false
+		}
+	}
+
+	if v3["in"] == "formData" {
+		v3["in"] = "query"
+	}
+
+	return v3
+}
+
+func convertHeader(header map[string]interface{}) map[string]interface{} {
+	v3 := make(map[string]interface{})
+	schema := make(map[string]interface{})
+
+	for k, v := range header {
+		if k == "description" {
+			v3[k] = v
+			continue
+		}
+		schema[k] = v
+	}
+
+	v3["schema"] = schema
+
+	return v3
+}
+
+func convertSecurityScheme(secDef map[string]interface{}) map[string]interface{} {
+	if secDef["type"] == "basic" {
+		scheme := map[string]interface{}{
+			"type":   "http",
+			"scheme": "basic",
+		}
+		if desc, ok := secDef["description"]; ok {
+			scheme["description"] = desc
+		}
+		return scheme
+	}
+
+	return secDef
+}
+
+func content(schema interface{}, mediaTypes []string) map[string]interface{} {
+	c := make(map[string]interface{}, len(mediaTypes))
+
+	for _, mediaType := range mediaTypes {
+		c[mediaType] = map[string]interface{}{"schema": schema}
+	}
+
+	return c
+}
+
+// rewriteRefs points the references to the swagger definitions to the schema components
+func rewriteRefs(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if ref, ok := e.(string); ok && k == "$ref" {
+				v[k] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			v[k] = rewriteRefs(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = rewriteRefs(e)
+		}
+	}
+
+	return v
+}
+
+func stringList(v interface{}, defaults ...string) []string {
+	var l []string
+
+	for _, e := range asList(v) {
+		if s, ok := e.(string); ok {
+			l = append(l, s)
+		}
+	}
+
+	if len(l) == 0 {
+		l = append(l, defaults...)
+	}
+
+	sort.Strings(l)
+
+	return l
+}
+
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func asList(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSwagger = `{
+  "swagger": "2.0",
+  "info": {"title": "test", "version": "1"},
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "paths": {
+    "/db/set": {
+      "post": {
+        "operationId": "Set",
+        "parameters": [
+          {"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/SetRequest"}}
+        ],
+        "responses": {
+          "200": {"description": "ok", "schema": {"$ref": "#/definitions/TxMetadata"}}
+        }
+      }
+    },
+    "/db/get/{key}": {
+      "get": {
+        "operationId": "Get",
+        "parameters": [
+          {"name": "key", "in": "path", "required": true, "type": "string", "format": "byte"},
+          {"name": "ids", "in": "query", "required": false, "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"}
+        ],
+        "responses": {
+          "200": {"description": "ok", "schema": {"type": "array", "items": {"$ref": "#/definitions/Entry"}}}
+        },
+        "security": []
+      }
+    }
+  },
+  "definitions": {
+    "SetRequest": {"type": "object", "properties": {"KVs": {"type": "array", "items": {"$ref": "#/definitions/Entry"}}}},
+    "Entry": {"type": "object", "properties": {"key": {"type": "string", "format": "byte"}}},
+    "TxMetadata": {"type": "object"}
+  },
+  "securityDefinitions": {
+    "bearer": {"type": "apiKey", "name": "Authorization", "in": "header"},
+    "basic": {"type": "basic"}
+  },
+  "security": [{"bearer": []}]
+}`
+
+func TestFromSwagger(t *testing.T) {
+	b, err := FromSwagger([]byte(testSwagger), "/api")
+	require.NoError(t, err)
+
+	var spec map[string]interface{}
+	err = json.Unmarshal(b, &spec)
+	require.NoError(t, err)
+
+	require.Equal(t, Version, spec["openapi"])
+	require.Equal(t, []interface{}{map[string]interface{}{"url": "/api"}}, spec["servers"])
+	require.Equal(t, []interface{}{map[string]interface{}{"bearer": []interface{}{}}}, spec["security"])
+	require.NotContains(t, string(b), "#/definitions/")
+
+	paths := spec["paths"].(map[string]interface{})
+
+	set := paths["/db/set"].(map[string]interface{})["post"].(map[string]interface{})
+	require.NotContains(t, set, "parameters")
+	require.Equal(t, map[string]interface{}{
+		"required": true,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "#/components/schemas/SetRequest"},
+			},
+		},
+	}, set["requestBody"])
+	require.Equal(t, map[string]interface{}{
+		"200": map[string]interface{}{
+			"description": "ok",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{"$ref": "#/components/schemas/TxMetadata"},
+				},
+			},
+		},
+	}, set["responses"])
+
+	get := paths["/db/get/{key}"].(map[string]interface{})["get"].(map[string]interface{})
+	require.Equal(t, []interface{}{}, get["security"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"name":     "key",
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string", "format": "byte"},
+		},
+		map[string]interface{}{
+			"name":     "ids",
+			"in":       "query",
+			"required": false,
+			"style":    "form",
+			"explode":  true,
+			"schema":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		},
+	}, get["parameters"])
+
+	components := spec["components"].(map[string]interface{})
+	require.Len(t, components["schemas"], 3)
+	require.Equal(t, map[string]interface{}{
+		"bearer": map[string]interface{}{"type": "apiKey", "name": "Authorization", "in": "header"},
+		"basic":  map[string]interface{}{"type": "http", "scheme": "basic"},
+	}, components["securitySchemes"])
+
+	t.Run("invalid specifications should fail", func(t *testing.T) {
+		_, err := FromSwagger([]byte(`{"openapi": "3.0.0"}`), "")
+		require.ErrorIs(t, err, ErrUnsupportedSwaggerVersion)
+
+		_, err = FromSwagger([]byte(`{`), "")
+		require.Error(t, err)
+	})
+}
+
+func TestSpecIsUpToDate(t *testing.T) {
+	swagger, err := ioutil.ReadFile("../schema/schema.swagger.json")
+	require.NoError(t, err)
+
+	expected, err := FromSwagger(swagger, "/api")
+	require.NoError(t, err)
+
+	require.Equal(t, string(expected)+"\n", string(Spec()), "run go generate ./pkg/api/openapi")
+
+	file, err := ioutil.ReadFile("openapi.json")
+	require.NoError(t, err)
+	require.Equal(t, Spec(), file)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package schema
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+
+	"github.com/codenotary/immudb/pkg/signer"
+)
+
+// ToBytes returns the canonical serialization of the report, signature excluded
+func (r *ComplianceReportResponse) ToBytes() []byte {
+	b := make([]byte, 4+len(r.Db)+12*8+len(r.StateTxHash))
+	i := 0
+
+	binary.BigEndian.PutUint32(b[i:], uint32(len(r.Db)))
+	i += 4
+
+	copy(b[i:], []byte(r.Db))
+	i += len(r.Db)
+
+	for _, n := range []uint64{
+		uint64(r.SinceTime),
+		uint64(r.UntilTime),
+		uint64(r.GeneratedAt),
+		r.FirstTx,
+		r.LastTx,
+		r.TxCount,
+		r.EntriesCount,
+		r.PayloadSize,
+		r.VerifiedTxCount,
+		r.FailedVerifications,
+		r.RedactedEntries,
+	} {
+		binary.BigEndian.PutUint64(b[i:], n)
+		i += 8
+	}
+
+	binary.BigEndian.PutUint64(b[i:], r.StateTxId)
+	i += 8
+
+	copy(b[i:], r.StateTxHash)
+
+	return b
+}
+
+// CheckSignature
+func (r *ComplianceReportResponse) CheckSignature(key *ecdsa.PublicKey) (ok bool, err error) {
+	if r.Signature == nil {
+		return false, errors.New("no signature found")
+	}
+	return signer.Verify(r.ToBytes(), r.Signature.Signature, key)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package schema
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+)
+
+const (
+	stringComponent byte = iota + 1
+	uint64Component
+	timeComponent
+)
+
+// string components are terminated by 0x00 0x01, any 0x00 within them is escaped as 0x00 0xFF.
+// This way shorter strings sort before longer ones sharing the same prefix and the components following them are not mixed up
+const (
+	escapeByte      = 0x00
+	escapedByte     = 0xFF
+	terminationByte = 0x01
+)
+
+// KeyComponent is a typed part of a composite key
+type KeyComponent struct {
+	kind byte
+	s    string
+	n    uint64
+}
+
+// StringComponent returns a string key component
+func StringComponent(s string) KeyComponent {
+	return KeyComponent{kind: stringComponent, s: s}
+}
+
+// Uint64Component returns a numeric key component
+func Uint64Component(n uint64) KeyComponent {
+	return KeyComponent{kind: uint64Component, n: n}
+}
+
+// TimeComponent returns a time key component, with nanosecond precision
+func TimeComponent(t time.Time) KeyComponent {
+	return KeyComponent{kind: timeComponent, n: uint64(t.UnixNano()) ^ (1 << 63)}
+}
+
+// String returns the value of a string component
+func (c KeyComponent) String() string {
+	return c.s
+}
+
+// Uint64 returns the value of a numeric component
+func (c KeyComponent) Uint64() uint64 {
+	return c.n
+}
+
+// Time returns the value of a time component
+func (c KeyComponent) Time() time.Time {
+	return time.Unix(0, int64(c.n^(1<<63)))
+}
+
+// EncodeCompositeKey encodes the components into a key preserving their order, so keys sort as the tuple of their components does.
+// The encoding of the leading components is a prefix of the keys extending them, so it can be used as the prefix or the seek key of a scan
+func EncodeCompositeKey(components ...KeyComponent) []byte {
+	var b bytes.Buffer
+
+	for _, c := range components {
+		b.WriteByte(c.kind)
+
+		switch c.kind {
+		case stringComponent:
+			for i := 0; i < len(c.s); i++ {
+				b.WriteByte(c.s[i])
+
+				if c.s[i] == escapeByte {
+					b.WriteByte(escapedByte)
+				}
+			}
+
+			b.Write([]byte{escapeByte, terminationByte})
+		default:
+			var n [8]byte
+			binary.BigEndian.PutUint64(n[:], c.n)
+			b.Write(n[:])
+		}
+	}
+
+	return b.Bytes()
+}
+
+// DecodeCompositeKey returns the components of a key encoded with EncodeCompositeKey
+func DecodeCompositeKey(key []byte) ([]KeyComponent, error) {
+	var components []KeyComponent
+
+	for i := 0; i < len(key); {
+		c := KeyComponent{kind: key[i]}
+		i++
+
+		switch c.kind {
+		case stringComponent:
+			str, n, err := decodeStringComponent(key[i:])
+			if err != nil {
+				return nil, err
+			}
+
+			c.s = str
+			i += n
+		case uint64Component, timeComponent:
+			if i+8 > len(key) {
+				return nil, ErrInvalidCompositeKey
+			}
+
+			c.n = binary.BigEndian.Uint64(key[i:])
+			i += 8
+		default:
+			return nil, ErrInvalidCompositeKey
+		}
+
+		components = append(components, c)
+	}
+
+	return components, nil
+}
+
+// decodeStringComponent returns the string at the beginning of b and the length of its encoding
+func decodeStringComponent(b []byte) (string, int, error) {
+	var s []byte
+
+	for i := 0; i < len(b); i++ {
+		if b[i] != escapeByte {
+			s = append(s, b[i])
+			continue
+		}
+
+		if i+1 == len(b) {
+			break
+		}
+
+		i++
+
+		switch b[i] {
+		case terminationByte:
+			return string(s), i + 1, nil
+		case escapedByte:
+			s = append(s, escapeByte)
+		default:
+			return "", 0, ErrInvalidCompositeKey
+		}
+	}
+
+	return "", 0, ErrInvalidCompositeKey
+}
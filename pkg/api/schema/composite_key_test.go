@@ -0,0 +1,92 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package schema
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompositeKey(t *testing.T) {
+	t0 := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	key := EncodeCompositeKey(StringComponent("us\x00er"), Uint64Component(42), TimeComponent(t0))
+
+	components, err := DecodeCompositeKey(key)
+	require.NoError(t, err)
+	require.Len(t, components, 3)
+	require.Equal(t, "us\x00er", components[0].String())
+	require.Equal(t, uint64(42), components[1].Uint64())
+	require.True(t, t0.Equal(components[2].Time()))
+
+	prefix := EncodeCompositeKey(StringComponent("us\x00er"), Uint64Component(42))
+	require.True(t, bytes.HasPrefix(key, prefix))
+
+	components, err = DecodeCompositeKey(nil)
+	require.NoError(t, err)
+	require.Empty(t, components)
+
+	for _, invalid := range [][]byte{
+		{0},
+		{stringComponent, 'a'},
+		{stringComponent, 'a', escapeByte},
+		{stringComponent, 'a', escapeByte, 0x02},
+		{uint64Component, 0, 0, 0},
+		{timeComponent},
+	} {
+		_, err = DecodeCompositeKey(invalid)
+		require.Equal(t, ErrInvalidCompositeKey, err)
+	}
+}
+
+func TestCompositeKeyOrder(t *testing.T) {
+	t0 := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	// keys sorted as the tuple of their components
+	ordered := [][]KeyComponent{
+		{StringComponent("a")},
+		{StringComponent("a"), Uint64Component(1)},
+		{StringComponent("a"), Uint64Component(2)},
+		{StringComponent("a"), Uint64Component(10)},
+		{StringComponent("a"), Uint64Component(256)},
+		{StringComponent("a\x00")},
+		{StringComponent("a\x00b")},
+		{StringComponent("ab"), TimeComponent(time.Unix(-1, 0))},
+		{StringComponent("ab"), TimeComponent(time.Unix(0, 0))},
+		{StringComponent("ab"), TimeComponent(t0)},
+		{StringComponent("ab"), TimeComponent(t0.Add(time.Nanosecond))},
+		{StringComponent("ab"), TimeComponent(t0.Add(time.Hour)), StringComponent("x")},
+		{StringComponent("b")},
+	}
+
+	var keys [][]byte
+
+	for i := len(ordered) - 1; i >= 0; i-- {
+		keys = append(keys, EncodeCompositeKey(ordered[i]...))
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+
+	for i, components := range ordered {
+		require.Equal(t, EncodeCompositeKey(components...), keys[i])
+	}
+}
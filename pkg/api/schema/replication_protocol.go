@@ -0,0 +1,138 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"strconv"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ReplicationProtocolVersion is the latest version of the replication protocol spoken by this release
+	ReplicationProtocolVersion = 1
+	// MinReplicationProtocolVersion is the oldest version of the replication protocol this release can still speak
+	MinReplicationProtocolVersion = 1
+)
+
+// Metadata keys used by replication peers to advertise their versions when opening a replication stream
+const (
+	ReplicationProtocolVersionKey    = "x-immudb-replication-protocol"
+	MinReplicationProtocolVersionKey = "x-immudb-replication-protocol-min"
+	StoreFormatVersionKey            = "x-immudb-store-format"
+	// NegotiatedReplicationProtocolKey holds, in the header sent back to the peer, the protocol version in use
+	NegotiatedReplicationProtocolKey = "x-immudb-replication-protocol-negotiated"
+)
+
+// ReplicationVersions are the versions a replication peer is able to work with
+type ReplicationVersions struct {
+	Protocol    int
+	MinProtocol int
+	StoreFormat int
+}
+
+// LocalReplicationVersions returns the versions of this release
+func LocalReplicationVersions() ReplicationVersions {
+	return ReplicationVersions{
+		Protocol:    ReplicationProtocolVersion,
+		MinProtocol: MinReplicationProtocolVersion,
+		StoreFormat: store.Version,
+	}
+}
+
+// legacyReplicationVersions are assumed for peers not advertising their versions,
+// as they were released before the handshake was introduced
+var legacyReplicationVersions = ReplicationVersions{
+	Protocol:    1,
+	MinProtocol: 1,
+	StoreFormat: 1,
+}
+
+// MD returns the metadata advertising the versions
+func (v ReplicationVersions) MD() metadata.MD {
+	return metadata.Pairs(
+		ReplicationProtocolVersionKey, strconv.Itoa(v.Protocol),
+		MinReplicationProtocolVersionKey, strconv.Itoa(v.MinProtocol),
+		StoreFormatVersionKey, strconv.Itoa(v.StoreFormat),
+	)
+}
+
+// ReplicationVersionsFromMD reads the versions advertised by a peer, the ones of the releases predating
+// the handshake are returned when the peer advertised none
+func ReplicationVersionsFromMD(md metadata.MD) (ReplicationVersions, error) {
+	if len(md.Get(ReplicationProtocolVersionKey)) == 0 {
+		return legacyReplicationVersions, nil
+	}
+
+	var v ReplicationVersions
+
+	for _, f := range []struct {
+		key string
+		val *int
+	}{
+		{ReplicationProtocolVersionKey, &v.Protocol},
+		{MinReplicationProtocolVersionKey, &v.MinProtocol},
+		{StoreFormatVersionKey, &v.StoreFormat},
+	} {
+		vals := md.Get(f.key)
+		if len(vals) != 1 {
+			return v, status.Errorf(codes.InvalidArgument, "invalid replication handshake, missing %s", f.key)
+		}
+
+		n, err := strconv.Atoi(vals[0])
+		if err != nil || n <= 0 {
+			return v, status.Errorf(codes.InvalidArgument, "invalid replication handshake, malformed %s '%s'", f.key, vals[0])
+		}
+
+		*f.val = n
+	}
+
+	if v.MinProtocol > v.Protocol {
+		return v, status.Errorf(codes.InvalidArgument, "invalid replication handshake, protocol range %d-%d", v.MinProtocol, v.Protocol)
+	}
+
+	return v, nil
+}
+
+// NegotiateReplicationProtocol returns the latest protocol version spoken by both peers.
+// It fails when their protocol ranges do not overlap or when their store formats differ,
+// as the exported transactions are encoded in the store format
+func NegotiateReplicationProtocol(local, peer ReplicationVersions) (int, error) {
+	if local.StoreFormat != peer.StoreFormat {
+		return 0, status.Errorf(codes.FailedPrecondition,
+			"incompatible replication peer: store format %d differs from the local store format %d, "+
+				"both servers must run releases sharing the same store format",
+			peer.StoreFormat, local.StoreFormat)
+	}
+
+	version := local.Protocol
+	if peer.Protocol < version {
+		version = peer.Protocol
+	}
+
+	if version < local.MinProtocol || version < peer.MinProtocol {
+		return 0, status.Errorf(codes.FailedPrecondition,
+			"incompatible replication peer: it speaks replication protocol versions %d to %d while %d to %d are supported locally, "+
+				"upgrade the server running the oldest release",
+			peer.MinProtocol, peer.Protocol, local.MinProtocol, local.Protocol)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestReplicationVersionsFromMD(t *testing.T) {
+	local := LocalReplicationVersions()
+	require.Equal(t, ReplicationProtocolVersion, local.Protocol)
+	require.Equal(t, MinReplicationProtocolVersion, local.MinProtocol)
+	require.Equal(t, store.Version, local.StoreFormat)
+
+	v, err := ReplicationVersionsFromMD(local.MD())
+	require.NoError(t, err)
+	require.Equal(t, local, v)
+
+	t.Run("peers predating the handshake should be assumed to speak the first version", func(t *testing.T) {
+		v, err := ReplicationVersionsFromMD(metadata.Pairs("authorization", "token"))
+		require.NoError(t, err)
+		require.Equal(t, ReplicationVersions{Protocol: 1, MinProtocol: 1, StoreFormat: 1}, v)
+
+		v, err = ReplicationVersionsFromMD(nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, v.Protocol)
+	})
+
+	t.Run("malformed handshakes should be rejected", func(t *testing.T) {
+		for _, md := range []metadata.MD{
+			metadata.Pairs(ReplicationProtocolVersionKey, "1"),
+			metadata.Pairs(ReplicationProtocolVersionKey, "1", MinReplicationProtocolVersionKey, "1", StoreFormatVersionKey, "x"),
+			metadata.Pairs(ReplicationProtocolVersionKey, "0", MinReplicationProtocolVersionKey, "1", StoreFormatVersionKey, "1"),
+			metadata.Pairs(ReplicationProtocolVersionKey, "1", MinReplicationProtocolVersionKey, "2", StoreFormatVersionKey, "1"),
+		} {
+			_, err := ReplicationVersionsFromMD(md)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		}
+	})
+}
+
+func TestNegotiateReplicationProtocol(t *testing.T) {
+	local := ReplicationVersions{Protocol: 3, MinProtocol: 2, StoreFormat: 1}
+
+	version, err := NegotiateReplicationProtocol(local, local)
+	require.NoError(t, err)
+	require.Equal(t, 3, version)
+
+	version, err = NegotiateReplicationProtocol(local, ReplicationVersions{Protocol: 2, MinProtocol: 1, StoreFormat: 1})
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+
+	version, err = NegotiateReplicationProtocol(local, ReplicationVersions{Protocol: 5, MinProtocol: 3, StoreFormat: 1})
+	require.NoError(t, err)
+	require.Equal(t, 3, version)
+
+	_, err = NegotiateReplicationProtocol(local, ReplicationVersions{Protocol: 1, MinProtocol: 1, StoreFormat: 1})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.Contains(t, err.Error(), "replication protocol versions 1 to 1 while 2 to 3 are supported locally")
+
+	_, err = NegotiateReplicationProtocol(local, ReplicationVersions{Protocol: 5, MinProtocol: 4, StoreFormat: 1})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = NegotiateReplicationProtocol(local, ReplicationVersions{Protocol: 3, MinProtocol: 2, StoreFormat: 2})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.Contains(t, err.Error(), "store format 2 differs from the local store format 1")
+}
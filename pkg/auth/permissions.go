@@ -17,32 +17,51 @@ limitations under the License.
 package auth
 
 var maintenanceMethods = map[string]struct{}{
-	"Get":                 {},
-	"VerifiableGet":       {},
-	"StreamGet":           {},
-	"StreamVerifiableGet": {},
-	"GetAll":              {},
-	"ZScan":               {},
-	"StreamZScan":         {},
-	"VerifiableTxByID":    {},
-	"IScan":               {},
-	"Scan":                {},
-	"StreamScan":          {},
-	"History":             {},
-	"StreamHistory":       {},
-	"TxByID":              {},
-	"TxScan":              {},
-	"ExportTx":            {},
-	"ReplicateTx":         {},
-	"Count":               {},
-	"CountAll":            {},
-	"DatabaseList":        {},
-	"CurrentState":        {},
-	"UseSnapshot":         {},
-	"SQLQuery":            {},
-	"ListTables":          {},
-	"DescribeTable":       {},
-	"VerifiableSQLGet":    {},
+	"Get":                     {},
+	"VerifiableGet":           {},
+	"StreamGet":               {},
+	"StreamVerifiableGet":     {},
+	"GetAll":                  {},
+	"ZScan":                   {},
+	"StreamZScan":             {},
+	"VerifiableZScan":         {},
+	"ResolveReferenceHistory": {},
+	"MultiInclusion":          {},
+	"ReferencesTo":            {},
+	"ZCard":                   {},
+	"ZCount":                  {},
+	"VerifiableTxByID":        {},
+	"IScan":                   {},
+	"Scan":                    {},
+	"StreamScan":              {},
+	"Subscribe":               {},
+	"StreamTxs":               {},
+	"OpenSnapshot":            {},
+	"ReleaseSnapshot":         {},
+	"History":                 {},
+	"TimestampTokens":         {},
+	"StateHistory":            {},
+	"AnchorReceipts":          {},
+	"StreamHistory":           {},
+	"TxByID":                  {},
+	"TxScan":                  {},
+	"ExportTx":                {},
+	"ReplicateTx":             {},
+	"Count":                   {},
+	"CountAll":                {},
+	"DatabaseList":            {},
+	"CurrentState":            {},
+	"UseSnapshot":             {},
+	"SQLQuery":                {},
+	"ListTables":              {},
+	"DescribeTable":           {},
+	"SQLDump":                 {},
+	"VerifiableSQLGet":        {},
+	"ComplianceReport":        {},
+	"VerifyRange":             {},
+	"ConfirmReplication":      {},
+	"ReplicationStatus":       {},
+	"PrimaryEndpoint":         {},
 
 	// admin methods
 	"ListUsers":    {},
@@ -61,60 +80,110 @@ const (
 	PermissionNone = iota
 	PermissionR
 	PermissionRW
+	// PermissionReplication only allows a replica to fetch, verify and confirm the transactions of the database
+	PermissionReplication
 )
 
 var methodsPermissions = map[string][]uint32{
 	// readwrite methods
-	"Set":                    {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"VerifiableSet":          {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"StreamSet":              {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"StreamVerifiableSet":    {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"Get":                    {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"VerifiableGet":          {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"StreamGet":              {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"StreamVerifiableGet":    {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"GetAll":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"ExecAll":                {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"StreamExecAll":          {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"SetReference":           {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"VerifiableSetReference": {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"ZAdd":                   {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"VerifiableZAdd":         {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"ZScan":                  {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"StreamZScan":            {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"VerifiableTxByID":       {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"IScan":                  {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"Scan":                   {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"StreamScan":             {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"History":                {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"StreamHistory":          {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"TxByID":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"TxScan":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"ExportTx":               {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"ReplicateTx":            {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"Count":                  {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"CountAll":               {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"DatabaseList":           {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"CurrentState":           {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"SQLExec":                {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"UseSnapshot":            {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"SQLQuery":               {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"ListTables":             {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"DescribeTable":          {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"VerifiableSQLGet":       {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"Set":                     {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"SetConditional":          {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"Delete":                  {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"DeletePrefix":            {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"Append":                  {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"ExpirableSet":            {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"BeginTx":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"TxSet":                   {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"TxGet":                   {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"Commit":                  {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"Rollback":                {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"VerifiableSet":           {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"StreamSet":               {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"StreamVerifiableSet":     {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"Get":                     {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"VerifiableGet":           {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"StreamGet":               {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"StreamVerifiableGet":     {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"GetAll":                  {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"ExecAll":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"StreamExecAll":           {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"SetReference":            {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"VerifiableSetReference":  {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"ZAdd":                    {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"VerifiableZAdd":          {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"ZRem":                    {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"ZScan":                   {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"StreamZScan":             {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"VerifiableZScan":         {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"ResolveReferenceHistory": {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"MultiInclusion":          {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"ReferencesTo":            {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"ZCard":                   {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"ZCount":                  {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"VerifiableTxByID":        {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR, PermissionReplication},
+	"IScan":                   {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"Scan":                    {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"OpenSnapshot":            {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"ReleaseSnapshot":         {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"StreamScan":              {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"Subscribe":               {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"StreamTxs":               {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"History":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"TimestampTokens":         {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"StateHistory":            {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"AnchorReceipts":          {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"StreamHistory":           {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"TxByID":                  {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"TxScan":                  {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"ExportTx":                {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR, PermissionReplication},
+	"ExportSnapshot":          {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR, PermissionReplication},
+	"ReplicateTx":             {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"Count":                   {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"CountAll":                {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"DatabaseList":            {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"CurrentState":            {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR, PermissionReplication},
+	"SQLExec":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"UseSnapshot":             {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"SQLQuery":                {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"ListTables":              {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"DescribeTable":           {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"SQLDump":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"SQLLoad":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"VerifiableSQLGet":        {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"ComplianceReport":        {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"VerifyRange":             {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"ConfirmReplication":      {PermissionSysAdmin, PermissionAdmin, PermissionReplication},
+	"ReplicationStatus":       {PermissionSysAdmin, PermissionAdmin},
+	"ReplicationEvents":       {PermissionSysAdmin, PermissionAdmin},
+	"UpdateReplication":       {PermissionSysAdmin, PermissionAdmin},
+	"ReplicationAudit":        {PermissionSysAdmin, PermissionAdmin},
+	"SyncSnapshot":            {PermissionSysAdmin, PermissionAdmin},
+	"PrimaryEndpoint":         {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR, PermissionReplication},
 
 	// admin methods
-	"ListUsers":        {PermissionSysAdmin, PermissionAdmin},
-	"CreateUser":       {PermissionSysAdmin, PermissionAdmin},
-	"ChangePassword":   {PermissionSysAdmin, PermissionAdmin},
-	"SetPermission":    {PermissionSysAdmin, PermissionAdmin},
-	"DeactivateUser":   {PermissionSysAdmin, PermissionAdmin},
-	"SetActiveUser":    {PermissionSysAdmin, PermissionAdmin},
-	"UpdateAuthConfig": {PermissionSysAdmin},
-	"UpdateMTLSConfig": {PermissionSysAdmin},
-	"CreateDatabase":   {PermissionSysAdmin},
-	"Dump":             {PermissionSysAdmin, PermissionAdmin},
-	"CompactIndex":     {PermissionSysAdmin, PermissionAdmin},
+	"ListUsers":               {PermissionSysAdmin, PermissionAdmin},
+	"CreateUser":              {PermissionSysAdmin, PermissionAdmin},
+	"ChangePassword":          {PermissionSysAdmin, PermissionAdmin},
+	"SetPermission":           {PermissionSysAdmin, PermissionAdmin},
+	"DeactivateUser":          {PermissionSysAdmin, PermissionAdmin},
+	"SetActiveUser":           {PermissionSysAdmin, PermissionAdmin},
+	"UpdateAuthConfig":        {PermissionSysAdmin},
+	"UpdateMTLSConfig":        {PermissionSysAdmin},
+	"CreateDatabase":          {PermissionSysAdmin},
+	"DeleteDatabase":          {PermissionSysAdmin},
+	"CloneDatabase":           {PermissionSysAdmin},
+	"ExportDatabase":          {PermissionSysAdmin, PermissionAdmin},
+	"ImportDatabase":          {PermissionSysAdmin},
+	"Backup":                  {PermissionSysAdmin, PermissionAdmin},
+	"Restore":                 {PermissionSysAdmin},
+	"BackupStatus":            {PermissionSysAdmin},
+	"UpdateCorruptionChecker": {PermissionSysAdmin},
+	"CorruptionCheckStatus":   {PermissionSysAdmin},
+	"ClusterStatus":           {PermissionSysAdmin},
+	"Scrub":                   {PermissionSysAdmin},
+	"AcknowledgeCorruption":   {PermissionSysAdmin},
+	"Dump":                    {PermissionSysAdmin, PermissionAdmin},
+	"CompactIndex":            {PermissionSysAdmin, PermissionAdmin},
 }
 
 //HasPermissionForMethod checks if userPermission can access method name
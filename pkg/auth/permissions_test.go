@@ -37,4 +37,14 @@ func TestHasPermissionForMethod(t *testing.T) {
 	if HasPermissionForMethod(PermissionNone, "CountAll") {
 		t.Errorf("expected PermissionNone to be insufficient for CountAll")
 	}
+
+	if !HasPermissionForMethod(PermissionReplication, "ExportTx") {
+		t.Errorf("expected PermissionReplication to be sufficient for ExportTx")
+	}
+	if HasPermissionForMethod(PermissionReplication, "Get") {
+		t.Errorf("expected PermissionReplication to be insufficient for Get")
+	}
+	if HasPermissionForMethod(PermissionReplication, "ReplicateTx") {
+		t.Errorf("expected PermissionReplication to be insufficient for ReplicateTx")
+	}
 }
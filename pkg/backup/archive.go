@@ -0,0 +1,404 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// Archive layout:
+//
+//   magic | version (2 bytes) | header length (4 bytes) | json header
+//   tx records:  recordTx | tx id (8 bytes) | ts (8 bytes) | alh (32 bytes) | length (4 bytes) | exported tx
+//   trailer:     recordTrailer | sha256 of all preceding bytes (32 bytes) | length (4 bytes) | json trailer
+//
+// Exported transactions are the ones produced by the store for replication,
+// thus their consistency is checked by the store when they are replicated again.
+
+// Version is the current archive format version
+const Version = 1
+
+const (
+	recordTx      = byte(1)
+	recordTrailer = byte(2)
+)
+
+const maxHeaderLen = 1 << 20
+
+var magic = []byte("IMMUDBAR")
+
+var ErrInvalidArchive = errors.New("invalid archive")
+var ErrUnsupportedVersion = errors.New("unsupported archive version")
+var ErrCorruptedArchive = errors.New("corrupted archive")
+var ErrIllegalArguments = errors.New("illegal arguments")
+var ErrBrokenChain = errors.New("archive does not follow the previous one")
+
+// Header describes the content of an archive
+type Header struct {
+	Database      string          `json:"database"`
+	FromTx        uint64          `json:"fromTx"`
+	UntilTx       uint64          `json:"untilTx"`
+	PrevAlh       []byte          `json:"prevAlh,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	ImmudbVersion string          `json:"immudbVersion,omitempty"`
+	Settings      json.RawMessage `json:"settings,omitempty"`
+}
+
+// IsIncremental returns true when the archive doesn't start from the first transaction,
+// thus it can only be applied on top of the state recorded in PrevAlh
+func (hdr *Header) IsIncremental() bool {
+	return hdr.FromTx > 1
+}
+
+// Follows returns true when the archive starts right after the state recorded in the trailer of another one
+func (hdr *Header) Follows(prev *Trailer) bool {
+	return prev != nil &&
+		hdr.FromTx == prev.TxId+1 &&
+		bytes.Equal(hdr.PrevAlh, prev.TxHash)
+}
+
+// Trailer holds the database state after the last archived transaction
+type Trailer struct {
+	TxCount   uint64            `json:"txCount"`
+	TxId      uint64            `json:"txId"`
+	TxHash    []byte            `json:"txHash"`
+	Signature *schema.Signature `json:"signature,omitempty"`
+}
+
+// TxRecord is an exported transaction along with its accumulative linear hash
+type TxRecord struct {
+	TxID uint64
+	Ts   int64
+	Alh  [sha256.Size]byte
+	Tx   []byte
+}
+
+// Writer writes an archive
+type Writer struct {
+	w       io.Writer
+	digest  hash.Hash
+	hdr     *Header
+	txCount uint64
+	lastTx  uint64
+	lastAlh [sha256.Size]byte
+}
+
+// NewWriter writes the archive header
+func NewWriter(w io.Writer, hdr *Header) (*Writer, error) {
+	if w == nil || hdr == nil || hdr.FromTx == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	hdrBs, err := json.Marshal(hdr)
+	if err != nil {
+		return nil, err
+	}
+
+	aw := &Writer{
+		w:      w,
+		digest: sha256.New(),
+		hdr:    hdr,
+		lastTx: hdr.FromTx - 1,
+	}
+
+	copy(aw.lastAlh[:], hdr.PrevAlh)
+
+	var buf bytes.Buffer
+	buf.Write(magic)
+	binary.Write(&buf, binary.BigEndian, uint16(Version))
+	binary.Write(&buf, binary.BigEndian, uint32(len(hdrBs)))
+	buf.Write(hdrBs)
+
+	return aw, aw.write(buf.Bytes())
+}
+
+func (aw *Writer) write(b []byte) error {
+	_, err := aw.w.Write(b)
+	if err != nil {
+		return err
+	}
+	aw.digest.Write(b)
+	return nil
+}
+
+// WriteTx appends a transaction, transactions must be written in order
+func (aw *Writer) WriteTx(rec *TxRecord) error {
+	if rec == nil || rec.TxID != aw.lastTx+1 {
+		return ErrIllegalArguments
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(recordTx)
+	binary.Write(&buf, binary.BigEndian, rec.TxID)
+	binary.Write(&buf, binary.BigEndian, rec.Ts)
+	buf.Write(rec.Alh[:])
+	binary.Write(&buf, binary.BigEndian, uint32(len(rec.Tx)))
+
+	err := aw.write(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	err = aw.write(rec.Tx)
+	if err != nil {
+		return err
+	}
+
+	aw.txCount++
+	aw.lastTx = rec.TxID
+	aw.lastAlh = rec.Alh
+
+	return nil
+}
+
+// Trailer returns the trailer describing the transactions written so far
+func (aw *Writer) Trailer() *Trailer {
+	return &Trailer{
+		TxCount: aw.txCount,
+		TxId:    aw.lastTx,
+		TxHash:  aw.lastAlh[:],
+	}
+}
+
+// Close writes the trailer, it must include the state of the last written transaction
+func (aw *Writer) Close(trailer *Trailer) error {
+	if trailer == nil ||
+		trailer.TxCount != aw.txCount ||
+		trailer.TxId != aw.lastTx ||
+		!bytes.Equal(trailer.TxHash, aw.lastAlh[:]) {
+		return ErrIllegalArguments
+	}
+
+	trailerBs, err := json.Marshal(trailer)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(recordTrailer)
+
+	err = aw.write(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	buf.Reset()
+	buf.Write(aw.digest.Sum(nil))
+	binary.Write(&buf, binary.BigEndian, uint32(len(trailerBs)))
+	buf.Write(trailerBs)
+
+	_, err = aw.w.Write(buf.Bytes())
+	return err
+}
+
+// Reader reads an archive, checking its structure and checksum
+type Reader struct {
+	r       io.Reader
+	digest  hash.Hash
+	hdr     *Header
+	trailer *Trailer
+	txCount uint64
+	lastTx  uint64
+	lastAlh [sha256.Size]byte
+}
+
+// NewReader reads the archive header
+func NewReader(r io.Reader) (*Reader, error) {
+	if r == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	ar := &Reader{
+		r:      r,
+		digest: sha256.New(),
+	}
+
+	m := make([]byte, len(magic))
+
+	err := ar.read(m)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(m, magic) {
+		return nil, ErrInvalidArchive
+	}
+
+	var b [6]byte
+
+	err = ar.read(b[:])
+	if err != nil {
+		return nil, err
+	}
+
+	if binary.BigEndian.Uint16(b[:]) != Version {
+		return nil, ErrUnsupportedVersion
+	}
+
+	hdrLen := binary.BigEndian.Uint32(b[2:])
+	if hdrLen > maxHeaderLen {
+		return nil, ErrInvalidArchive
+	}
+
+	hdrBs := make([]byte, hdrLen)
+
+	err = ar.read(hdrBs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(hdrBs, &ar.hdr)
+	if err != nil || ar.hdr == nil || ar.hdr.FromTx == 0 {
+		return nil, ErrInvalidArchive
+	}
+
+	ar.lastTx = ar.hdr.FromTx - 1
+	copy(ar.lastAlh[:], ar.hdr.PrevAlh)
+
+	return ar, nil
+}
+
+func (ar *Reader) read(b []byte) error {
+	_, err := io.ReadFull(ar.r, b)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrCorruptedArchive
+	}
+	if err != nil {
+		return err
+	}
+	ar.digest.Write(b)
+	return nil
+}
+
+// Header returns the archive header
+func (ar *Reader) Header() *Header {
+	return ar.hdr
+}
+
+// Trailer returns the archive trailer, only available once all the transactions were read
+func (ar *Reader) Trailer() *Trailer {
+	return ar.trailer
+}
+
+// Next returns the next transaction, io.EOF is returned once the trailer was read and verified
+func (ar *Reader) Next() (*TxRecord, error) {
+	if ar.trailer != nil {
+		return nil, io.EOF
+	}
+
+	var recType [1]byte
+
+	err := ar.read(recType[:])
+	if err != nil {
+		return nil, err
+	}
+
+	switch recType[0] {
+	case recordTx:
+		return ar.readTx()
+	case recordTrailer:
+		return nil, ar.readTrailer()
+	}
+
+	return nil, ErrCorruptedArchive
+}
+
+func (ar *Reader) readTx() (*TxRecord, error) {
+	var b [8 + 8 + sha256.Size + 4]byte
+
+	err := ar.read(b[:])
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &TxRecord{
+		TxID: binary.BigEndian.Uint64(b[:]),
+		Ts:   int64(binary.BigEndian.Uint64(b[8:])),
+	}
+	copy(rec.Alh[:], b[16:])
+
+	if rec.TxID != ar.lastTx+1 || rec.TxID > ar.hdr.UntilTx {
+		return nil, ErrCorruptedArchive
+	}
+
+	rec.Tx = make([]byte, binary.BigEndian.Uint32(b[16+sha256.Size:]))
+
+	err = ar.read(rec.Tx)
+	if err != nil {
+		return nil, err
+	}
+
+	ar.txCount++
+	ar.lastTx = rec.TxID
+	ar.lastAlh = rec.Alh
+
+	return rec, nil
+}
+
+func (ar *Reader) readTrailer() error {
+	expectedDigest := ar.digest.Sum(nil)
+
+	var b [sha256.Size + 4]byte
+
+	_, err := io.ReadFull(ar.r, b[:])
+	if err != nil {
+		return ErrCorruptedArchive
+	}
+
+	if !bytes.Equal(b[:sha256.Size], expectedDigest) {
+		return ErrCorruptedArchive
+	}
+
+	trailerLen := binary.BigEndian.Uint32(b[sha256.Size:])
+	if trailerLen > maxHeaderLen {
+		return ErrCorruptedArchive
+	}
+
+	trailerBs := make([]byte, trailerLen)
+
+	_, err = io.ReadFull(ar.r, trailerBs)
+	if err != nil {
+		return ErrCorruptedArchive
+	}
+
+	var trailer *Trailer
+
+	err = json.Unmarshal(trailerBs, &trailer)
+	if err != nil || trailer == nil {
+		return ErrCorruptedArchive
+	}
+
+	if trailer.TxCount != ar.txCount ||
+		trailer.TxId != ar.lastTx ||
+		trailer.TxId != ar.hdr.UntilTx ||
+		!bytes.Equal(trailer.TxHash, ar.lastAlh[:]) {
+		return ErrCorruptedArchive
+	}
+
+	ar.trailer = trailer
+
+	return io.EOF
+}
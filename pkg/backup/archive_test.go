@@ -0,0 +1,203 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeArchive(t *testing.T, txCount int) []byte {
+	var buf bytes.Buffer
+
+	aw, err := NewWriter(&buf, &Header{
+		Database:  "db1",
+		FromTx:    1,
+		UntilTx:   uint64(txCount),
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	for i := 1; i <= txCount; i++ {
+		err = aw.WriteTx(&TxRecord{
+			TxID: uint64(i),
+			Ts:   time.Now().Unix(),
+			Alh:  sha256.Sum256([]byte{byte(i)}),
+			Tx:   bytes.Repeat([]byte{byte(i)}, i*10),
+		})
+		require.NoError(t, err)
+	}
+
+	err = aw.Close(aw.Trailer())
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+func TestArchive(t *testing.T) {
+	_, err := NewWriter(nil, &Header{FromTx: 1})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = NewWriter(&bytes.Buffer{}, &Header{})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	aw, err := NewWriter(&bytes.Buffer{}, &Header{FromTx: 1, UntilTx: 1})
+	require.NoError(t, err)
+
+	err = aw.WriteTx(&TxRecord{TxID: 2})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = aw.Close(&Trailer{TxCount: 1})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	archive := writeArchive(t, 5)
+
+	_, err = NewReader(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	ar, err := NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	require.Equal(t, "db1", ar.Header().Database)
+	require.Equal(t, uint64(5), ar.Header().UntilTx)
+	require.Nil(t, ar.Trailer())
+
+	for i := 1; i <= 5; i++ {
+		rec, err := ar.Next()
+		require.NoError(t, err)
+		require.Equal(t, uint64(i), rec.TxID)
+		require.Equal(t, sha256.Sum256([]byte{byte(i)}), rec.Alh)
+		require.Len(t, rec.Tx, i*10)
+	}
+
+	_, err = ar.Next()
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, uint64(5), ar.Trailer().TxCount)
+	require.Equal(t, uint64(5), ar.Trailer().TxId)
+
+	_, err = ar.Next()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestCorruptedArchive(t *testing.T) {
+	archive := writeArchive(t, 3)
+
+	readAll := func(archive []byte) error {
+		ar, err := NewReader(bytes.NewReader(archive))
+		if err != nil {
+			return err
+		}
+
+		for {
+			_, err = ar.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	require.NoError(t, readAll(archive))
+
+	_, err := NewReader(bytes.NewReader([]byte("NOTANARCHIVE")))
+	require.Equal(t, ErrInvalidArchive, err)
+
+	unsupported := append([]byte{}, archive...)
+	unsupported[len(magic)+1] = 2
+	_, err = NewReader(bytes.NewReader(unsupported))
+	require.Equal(t, ErrUnsupportedVersion, err)
+
+	// truncated archives
+	for _, l := range []int{4, 20, len(archive) / 2, len(archive) - 1} {
+		require.Equal(t, ErrCorruptedArchive, readAll(archive[:l]))
+	}
+
+	// any modified byte in the transactions is detected
+	tampered := append([]byte{}, archive...)
+	tampered[len(archive)/2] ^= 0xFF
+	require.Equal(t, ErrCorruptedArchive, readAll(tampered))
+}
+
+func TestIncrementalArchive(t *testing.T) {
+	prevAlh := sha256.Sum256([]byte{3})
+
+	var buf bytes.Buffer
+
+	aw, err := NewWriter(&buf, &Header{FromTx: 4, UntilTx: 5, PrevAlh: prevAlh[:]})
+	require.NoError(t, err)
+
+	err = aw.WriteTx(&TxRecord{TxID: 3})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	for i := 4; i <= 5; i++ {
+		err = aw.WriteTx(&TxRecord{TxID: uint64(i), Alh: sha256.Sum256([]byte{byte(i)})})
+		require.NoError(t, err)
+	}
+
+	err = aw.Close(aw.Trailer())
+	require.NoError(t, err)
+
+	ar, err := NewReader(&buf)
+	require.NoError(t, err)
+	require.True(t, ar.Header().IsIncremental())
+	require.Equal(t, prevAlh[:], ar.Header().PrevAlh)
+
+	rec, err := ar.Next()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), rec.TxID)
+
+	_, err = ar.Next()
+	require.NoError(t, err)
+
+	_, err = ar.Next()
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, uint64(2), ar.Trailer().TxCount)
+
+	// an incremental archive without new transactions holds the state it starts from
+	buf.Reset()
+
+	aw, err = NewWriter(&buf, &Header{FromTx: 6, UntilTx: 5, PrevAlh: prevAlh[:]})
+	require.NoError(t, err)
+
+	err = aw.Close(aw.Trailer())
+	require.NoError(t, err)
+
+	ar, err = NewReader(&buf)
+	require.NoError(t, err)
+
+	_, err = ar.Next()
+	require.Equal(t, io.EOF, err)
+	require.Zero(t, ar.Trailer().TxCount)
+	require.Equal(t, prevAlh[:], ar.Trailer().TxHash)
+}
+
+func TestArchiveChain(t *testing.T) {
+	alh := sha256.Sum256([]byte{3})
+
+	prev := &Trailer{TxId: 3, TxHash: alh[:]}
+
+	require.True(t, (&Header{FromTx: 4, PrevAlh: alh[:]}).Follows(prev))
+	require.False(t, (&Header{FromTx: 4, PrevAlh: alh[:]}).Follows(nil))
+	require.False(t, (&Header{FromTx: 5, PrevAlh: alh[:]}).Follows(prev))
+	require.False(t, (&Header{FromTx: 4, PrevAlh: make([]byte, sha256.Size)}).Follows(prev))
+}
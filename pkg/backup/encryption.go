@@ -0,0 +1,365 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// Encrypted archive layout:
+//
+//   encMagic | key id length (1 byte) | key id | nonce prefix (4 bytes)
+//   chunks:  length (4 bytes) | AES-GCM sealed chunk
+//
+// Each chunk is sealed using the nonce prefix followed by the chunk index, the index and whether it's
+// the last chunk are authenticated as additional data, so reordered or truncated archives are detected
+
+const encChunkSize = 64 * 1024
+
+const noncePrefixSize = 4
+
+var encMagic = []byte("IMMUDBEA")
+
+var ErrInvalidEncryptionKey = errors.New("invalid encryption key, a hex-encoded AES-128, AES-192 or AES-256 key is expected")
+var ErrEncryptionKeyNotFound = errors.New("archive is encrypted with an unknown key")
+
+// KeyProvider supplies the keys used to encrypt and decrypt archives
+type KeyProvider interface {
+	// EncryptionKey returns the key used to encrypt new archives along with its id, which is stored in the archive
+	EncryptionKey() (id string, key []byte, err error)
+	// DecryptionKey returns the key with the given id
+	DecryptionKey(id string) ([]byte, error)
+}
+
+type staticKeyProvider struct {
+	id  string
+	key []byte
+}
+
+// NewStaticKeyProvider returns a provider of a single key, identified by its fingerprint
+func NewStaticKeyProvider(key []byte) (KeyProvider, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	fingerprint := sha256.Sum256(key)
+
+	return &staticKeyProvider{
+		id:  hex.EncodeToString(fingerprint[:8]),
+		key: key,
+	}, nil
+}
+
+// NewFileKeyProvider returns a provider of the hex-encoded key stored in the given file
+func NewFileKeyProvider(path string) (KeyProvider, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	return NewStaticKeyProvider(key)
+}
+
+func (kp *staticKeyProvider) EncryptionKey() (string, []byte, error) {
+	return kp.id, kp.key, nil
+}
+
+func (kp *staticKeyProvider) DecryptionKey(id string) ([]byte, error) {
+	if id != kp.id {
+		return nil, fmt.Errorf("%w: '%s'", ErrEncryptionKeyNotFound, id)
+	}
+	return kp.key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, ErrInvalidEncryptionKey
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(aead cipher.AEAD, prefix []byte, idx uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint64(nonce[noncePrefixSize:], idx)
+	return nonce
+}
+
+func chunkAD(idx uint64, last bool) []byte {
+	var ad [9]byte
+	binary.BigEndian.PutUint64(ad[:], idx)
+	if last {
+		ad[8] = 1
+	}
+	return ad[:]
+}
+
+type encryptingWriter struct {
+	w           io.Writer
+	aead        cipher.AEAD
+	noncePrefix []byte
+	idx         uint64
+	buf         []byte
+}
+
+// NewEncryptingWriter encrypts everything written into it with the encryption key of the provider,
+// the archive is completed once the returned writer is closed
+func NewEncryptingWriter(w io.Writer, kp KeyProvider) (io.WriteCloser, error) {
+	if w == nil || kp == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	id, key, err := kp.EncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(id) > 255 {
+		return nil, ErrIllegalArguments
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ew := &encryptingWriter{
+		w:           w,
+		aead:        aead,
+		noncePrefix: make([]byte, noncePrefixSize),
+		buf:         make([]byte, 0, encChunkSize),
+	}
+
+	_, err = rand.Read(ew.noncePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var hdr bytes.Buffer
+	hdr.Write(encMagic)
+	hdr.WriteByte(byte(len(id)))
+	hdr.WriteString(id)
+	hdr.Write(ew.noncePrefix)
+
+	_, err = w.Write(hdr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return ew, nil
+}
+
+func (ew *encryptingWriter) Write(b []byte) (int, error) {
+	n := len(b)
+
+	for len(b) > 0 {
+		// a full chunk is only sealed once more data is written, the last one is sealed on close
+		if len(ew.buf) == encChunkSize {
+			err := ew.seal(false)
+			if err != nil {
+				return n - len(b), err
+			}
+		}
+
+		c := copy(ew.buf[len(ew.buf):encChunkSize], b)
+		ew.buf = ew.buf[:len(ew.buf)+c]
+		b = b[c:]
+	}
+
+	return n, nil
+}
+
+func (ew *encryptingWriter) seal(last bool) error {
+	sealed := ew.aead.Seal(nil, chunkNonce(ew.aead, ew.noncePrefix, ew.idx), ew.buf, chunkAD(ew.idx, last))
+
+	var lenBs [4]byte
+	binary.BigEndian.PutUint32(lenBs[:], uint32(len(sealed)))
+
+	_, err := ew.w.Write(lenBs[:])
+	if err != nil {
+		return err
+	}
+
+	_, err = ew.w.Write(sealed)
+	if err != nil {
+		return err
+	}
+
+	ew.idx++
+	ew.buf = ew.buf[:0]
+
+	return nil
+}
+
+// Close seals the last chunk, the underlying writer is not closed
+func (ew *encryptingWriter) Close() error {
+	return ew.seal(true)
+}
+
+type decryptingReader struct {
+	r           io.Reader
+	aead        cipher.AEAD
+	noncePrefix []byte
+	idx         uint64
+	buf         []byte
+	done        bool
+}
+
+// NewDecryptingReader decrypts an encrypted archive using the key it was encrypted with
+func NewDecryptingReader(r io.Reader, kp KeyProvider) (io.Reader, error) {
+	if r == nil || kp == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	m := make([]byte, len(encMagic)+1)
+
+	_, err := io.ReadFull(r, m)
+	if err != nil || !bytes.Equal(m[:len(encMagic)], encMagic) {
+		return nil, ErrInvalidArchive
+	}
+
+	id := make([]byte, m[len(encMagic)])
+	noncePrefix := make([]byte, noncePrefixSize)
+
+	_, err = io.ReadFull(r, id)
+	if err == nil {
+		_, err = io.ReadFull(r, noncePrefix)
+	}
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+
+	key, err := kp.DecryptionKey(string(id))
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptingReader{
+		r:           r,
+		aead:        aead,
+		noncePrefix: noncePrefix,
+	}, nil
+}
+
+func (dr *decryptingReader) Read(b []byte) (int, error) {
+	for len(dr.buf) == 0 {
+		if dr.done {
+			return 0, io.EOF
+		}
+
+		err := dr.open()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(b, dr.buf)
+	dr.buf = dr.buf[n:]
+
+	return n, nil
+}
+
+func (dr *decryptingReader) open() error {
+	var lenBs [4]byte
+
+	_, err := io.ReadFull(dr.r, lenBs[:])
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrCorruptedArchive
+	}
+	if err != nil {
+		return err
+	}
+
+	sealedLen := binary.BigEndian.Uint32(lenBs[:])
+	if sealedLen > uint32(encChunkSize+dr.aead.Overhead()) {
+		return ErrCorruptedArchive
+	}
+
+	sealed := make([]byte, sealedLen)
+
+	_, err = io.ReadFull(dr.r, sealed)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrCorruptedArchive
+	}
+	if err != nil {
+		return err
+	}
+
+	nonce := chunkNonce(dr.aead, dr.noncePrefix, dr.idx)
+
+	for _, last := range []bool{false, true} {
+		dr.buf, err = dr.aead.Open(nil, nonce, sealed, chunkAD(dr.idx, last))
+		if err == nil {
+			dr.done = last
+			dr.idx++
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: chunk %d can not be decrypted", ErrCorruptedArchive, dr.idx)
+}
+
+// OpenReader reads an archive, decrypting it when it was encrypted.
+// A key provider is only required for encrypted archives
+func OpenReader(r io.Reader, kp KeyProvider) (*Reader, error) {
+	if r == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	br := bufio.NewReader(r)
+
+	m, err := br.Peek(len(encMagic))
+	if err != nil || !bytes.Equal(m, encMagic) {
+		return NewReader(br)
+	}
+
+	if kp == nil {
+		return nil, ErrEncryptionKeyNotFound
+	}
+
+	dr, err := NewDecryptingReader(br, kp)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReader(dr)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyProvider(t *testing.T) {
+	_, err := NewStaticKeyProvider([]byte("short"))
+	require.Equal(t, ErrInvalidEncryptionKey, err)
+
+	kp, err := NewStaticKeyProvider(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	id, key, err := kp.EncryptionKey()
+	require.NoError(t, err)
+	require.Len(t, id, 16)
+
+	dkey, err := kp.DecryptionKey(id)
+	require.NoError(t, err)
+	require.Equal(t, key, dkey)
+
+	_, err = kp.DecryptionKey("unknown")
+	require.True(t, errors.Is(err, ErrEncryptionKeyNotFound))
+
+	dir, err := ioutil.TempDir("", "backup_keys")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = NewFileKeyProvider(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+
+	keyFile := filepath.Join(dir, "key")
+
+	err = ioutil.WriteFile(keyFile, []byte("not hex"), 0600)
+	require.NoError(t, err)
+
+	_, err = NewFileKeyProvider(keyFile)
+	require.Equal(t, ErrInvalidEncryptionKey, err)
+
+	err = ioutil.WriteFile(keyFile, []byte("0101010101010101010101010101010101010101010101010101010101010101\n"), 0600)
+	require.NoError(t, err)
+
+	fkp, err := NewFileKeyProvider(keyFile)
+	require.NoError(t, err)
+
+	fid, fkey, err := fkp.EncryptionKey()
+	require.NoError(t, err)
+	require.Equal(t, id, fid)
+	require.Equal(t, key, fkey)
+}
+
+func TestEncryptedArchive(t *testing.T) {
+	kp, err := NewStaticKeyProvider(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	// spans several chunks
+	archive := writeArchive(t, 400)
+	require.Greater(t, len(archive), 10*encChunkSize)
+
+	encrypt := func(b []byte) []byte {
+		var buf bytes.Buffer
+
+		ew, err := NewEncryptingWriter(&buf, kp)
+		require.NoError(t, err)
+
+		// odd sized writes
+		for len(b) > 0 {
+			n := 1000
+			if n > len(b) {
+				n = len(b)
+			}
+			_, err = ew.Write(b[:n])
+			require.NoError(t, err)
+			b = b[n:]
+		}
+
+		err = ew.Close()
+		require.NoError(t, err)
+
+		return buf.Bytes()
+	}
+
+	readAll := func(encrypted []byte, kp KeyProvider) error {
+		ar, err := OpenReader(bytes.NewReader(encrypted), kp)
+		if err != nil {
+			return err
+		}
+
+		for {
+			_, err = ar.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = NewEncryptingWriter(nil, kp)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	encrypted := encrypt(archive)
+	require.False(t, bytes.Contains(encrypted, magic))
+	require.NoError(t, readAll(encrypted, kp))
+
+	dr, err := NewDecryptingReader(bytes.NewReader(encrypted), kp)
+	require.NoError(t, err)
+
+	decrypted, err := ioutil.ReadAll(dr)
+	require.NoError(t, err)
+	require.Equal(t, archive, decrypted)
+
+	// plain archives don't require any key
+	require.NoError(t, readAll(archive, nil))
+	require.NoError(t, readAll(archive, kp))
+
+	require.Equal(t, ErrEncryptionKeyNotFound, readAll(encrypted, nil))
+
+	otherKp, err := NewStaticKeyProvider(bytes.Repeat([]byte{2}, 32))
+	require.NoError(t, err)
+	require.True(t, errors.Is(readAll(encrypted, otherKp), ErrEncryptionKeyNotFound))
+
+	// any modified byte is detected
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)/2] ^= 0xFF
+	require.True(t, errors.Is(readAll(tampered, kp), ErrCorruptedArchive))
+
+	// truncation at a chunk boundary is detected as well
+	firstChunkLen := len(encMagic) + 1 + 16 + noncePrefixSize + 4 + encChunkSize + 16
+	require.True(t, errors.Is(readAll(encrypted[:firstChunkLen], kp), ErrCorruptedArchive))
+
+	// empty content
+	dr, err = NewDecryptingReader(bytes.NewReader(encrypt(nil)), kp)
+	require.NoError(t, err)
+
+	decrypted, err = ioutil.ReadAll(dr)
+	require.NoError(t, err)
+	require.Empty(t, decrypted)
+}
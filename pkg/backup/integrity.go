@@ -0,0 +1,72 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// IntegrityError reports an archived transaction whose content doesn't match the hashes it's chained with
+type IntegrityError struct {
+	TxID     uint64
+	Hash     string
+	Expected []byte
+	Actual   []byte
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("%v: %s mismatch at tx %d, expected %x but got %x", ErrCorruptedArchive, e.Hash, e.TxID, e.Expected, e.Actual)
+}
+
+// Is makes integrity errors match ErrCorruptedArchive
+func (e *IntegrityError) Is(target error) bool {
+	return target == ErrCorruptedArchive
+}
+
+// Verify rebuilds the hash tree of the transaction entries and checks the resulting accumulative
+// linear hash against the one recorded in the archive, given the one of the previous transaction
+func (rec *TxRecord) Verify(prevAlh [sha256.Size]byte) error {
+	md, eh, err := store.ExportedTxMetadata(rec.Tx)
+	if err != nil {
+		return fmt.Errorf("%w: tx %d can not be decoded", ErrCorruptedArchive, rec.TxID)
+	}
+
+	if md.ID != rec.TxID {
+		return fmt.Errorf("%w: tx %d recorded as tx %d", ErrCorruptedArchive, md.ID, rec.TxID)
+	}
+
+	if md.Ts != rec.Ts {
+		return fmt.Errorf("%w: tx %d committed at %d but recorded at %d", ErrCorruptedArchive, rec.TxID, md.Ts, rec.Ts)
+	}
+
+	if eh != md.Eh {
+		return &IntegrityError{TxID: rec.TxID, Hash: "entries hash", Expected: md.Eh[:], Actual: eh[:]}
+	}
+
+	if md.PrevAlh != prevAlh {
+		return &IntegrityError{TxID: rec.TxID, Hash: "previous alh", Expected: prevAlh[:], Actual: md.PrevAlh[:]}
+	}
+
+	if alh := md.Alh(); alh != rec.Alh {
+		return &IntegrityError{TxID: rec.TxID, Hash: "alh", Expected: rec.Alh[:], Actual: alh[:]}
+	}
+
+	return nil
+}
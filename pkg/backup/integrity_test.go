@@ -0,0 +1,91 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxRecordVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup_integrity")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st, err := store.Open(dir, store.DefaultOptions())
+	require.NoError(t, err)
+	defer st.Close()
+
+	var recs []*TxRecord
+
+	for i := 0; i < 2; i++ {
+		md, err := st.Commit([]*store.KV{{Key: []byte("key"), Value: []byte{byte(i)}}}, false)
+		require.NoError(t, err)
+
+		etx, err := st.ExportTx(md.ID, st.NewTx())
+		require.NoError(t, err)
+
+		recs = append(recs, &TxRecord{TxID: md.ID, Ts: md.Ts, Alh: md.Alh(), Tx: etx})
+	}
+
+	require.NoError(t, recs[0].Verify(sha256.Sum256(nil)))
+	require.NoError(t, recs[1].Verify(recs[0].Alh))
+
+	var ierr *IntegrityError
+
+	err = recs[1].Verify(sha256.Sum256(nil))
+	require.True(t, errors.As(err, &ierr))
+	require.Equal(t, recs[1].TxID, ierr.TxID)
+	require.Equal(t, "previous alh", ierr.Hash)
+	require.True(t, errors.Is(err, ErrCorruptedArchive))
+
+	wrongAlh := *recs[1]
+	wrongAlh.Alh = recs[0].Alh
+
+	err = wrongAlh.Verify(recs[0].Alh)
+	require.True(t, errors.As(err, &ierr))
+	require.Equal(t, "alh", ierr.Hash)
+	require.Equal(t, recs[0].Alh[:], ierr.Expected)
+	require.Equal(t, recs[1].Alh[:], ierr.Actual)
+
+	tampered := *recs[1]
+	tampered.Tx = append([]byte{}, recs[1].Tx...)
+	tampered.Tx[len(tampered.Tx)-1] ^= 0xFF
+
+	err = tampered.Verify(recs[0].Alh)
+	require.True(t, errors.As(err, &ierr))
+	require.Equal(t, "entries hash", ierr.Hash)
+	require.Contains(t, err.Error(), "entries hash mismatch at tx 2")
+
+	wrongTs := *recs[1]
+	wrongTs.Ts++
+	require.True(t, errors.Is(wrongTs.Verify(recs[0].Alh), ErrCorruptedArchive))
+
+	wrongID := *recs[1]
+	wrongID.TxID = 3
+	require.True(t, errors.Is(wrongID.Verify(recs[0].Alh), ErrCorruptedArchive))
+
+	truncated := *recs[1]
+	truncated.Tx = recs[1].Tx[:10]
+	require.True(t, errors.Is(truncated.Verify(recs[0].Alh), ErrCorruptedArchive))
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"1-a * * * *",
+	} {
+		_, err := ParseSchedule(expr)
+		require.ErrorIs(t, err, ErrInvalidSchedule, expr)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	from := time.Date(2021, time.March, 10, 10, 30, 15, 0, time.UTC) // Wednesday
+
+	for _, c := range []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2021, time.March, 10, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2021, time.March, 10, 10, 45, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2021, time.March, 10, 10, 45, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2021, time.March, 11, 2, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2021, time.March, 11, 10, 30, 0, 0, time.UTC)},
+		{"0 3 * * 0", time.Date(2021, time.March, 14, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * 1-6", time.Date(2021, time.March, 11, 3, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * 0", time.Date(2021, time.March, 14, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+	} {
+		s, err := ParseSchedule(c.expr)
+		require.NoError(t, err)
+		require.Equal(t, c.expected, s.Next(from), c.expr)
+	}
+
+	s, err := ParseSchedule("0 0 31 2 *")
+	require.NoError(t, err)
+	require.True(t, s.Next(from).IsZero())
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/codenotary/immudb/embedded/remotestorage/s3"
+)
+
+// s3PartSize is the size of the parts in which backups are uploaded, it bounds the memory used by each upload
+const s3PartSize = 2 * s3.MinPartSize
+
+// Storage is where server-side backups are kept.
+// Backups are identified by slash-separated names relative to the storage root
+type Storage interface {
+	// String returns a human-readable representation of the storage
+	String() string
+
+	// Create starts writing a new backup, it's only available under its name once committed
+	Create(ctx context.Context, name string) (Upload, error)
+
+	// Open opens an existing backup
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+
+	// List returns the names of the backups directly inside dir, sorted
+	List(ctx context.Context, dir string) ([]string, error)
+
+	// Delete removes a backup
+	Delete(ctx context.Context, name string) error
+}
+
+// Upload is a backup being written
+type Upload interface {
+	io.Writer
+
+	// Commit makes the backup available under its name
+	Commit() error
+
+	// Abort discards the backup
+	Abort() error
+}
+
+// CleanName validates and returns the shortest form of a backup name,
+// names must be relative to the storage root and can't point outside of it
+func CleanName(name string) (string, error) {
+	name = filepath.ToSlash(name)
+
+	if strings.HasPrefix(name, "/") || filepath.IsAbs(name) {
+		return "", ErrIllegalArguments
+	}
+
+	name = path.Clean(name)
+
+	if name == "." || name == ".." || strings.HasPrefix(name, "../") {
+		return "", ErrIllegalArguments
+	}
+
+	return name, nil
+}
+
+type localStorage struct {
+	dir string
+}
+
+// NewLocalStorage returns a storage keeping backups inside a local directory
+func NewLocalStorage(dir string) Storage {
+	return &localStorage{dir: dir}
+}
+
+func (s *localStorage) String() string {
+	return s.dir
+}
+
+func (s *localStorage) path(name string) (string, error) {
+	name, err := CleanName(name)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(name)), nil
+}
+
+func (s *localStorage) Create(ctx context.Context, name string) (Upload, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	// partial backups are never left under the requested name
+	tmpPath := path + ".tmp"
+
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localUpload{
+		f:       f,
+		w:       bufio.NewWriter(f),
+		path:    path,
+		tmpPath: tmpPath,
+	}, nil
+}
+
+func (s *localStorage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (s *localStorage) List(ctx context.Context, dir string) ([]string, error) {
+	dir, err := CleanName(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(filepath.Join(s.dir, filepath.FromSlash(dir)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, f := range files {
+		// uploads in progress are not listed
+		if f.IsDir() || strings.HasSuffix(f.Name(), ".tmp") {
+			continue
+		}
+		names = append(names, dir+"/"+f.Name())
+	}
+
+	return names, nil
+}
+
+func (s *localStorage) Delete(ctx context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+type localUpload struct {
+	f       *os.File
+	w       *bufio.Writer
+	path    string
+	tmpPath string
+}
+
+func (u *localUpload) Write(p []byte) (int, error) {
+	return u.w.Write(p)
+}
+
+func (u *localUpload) Commit() error {
+	err := u.w.Flush()
+	if err == nil {
+		err = u.f.Sync()
+	}
+
+	closeErr := u.f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(u.tmpPath, u.path)
+	}
+	if err != nil {
+		os.Remove(u.tmpPath)
+	}
+
+	return err
+}
+
+func (u *localUpload) Abort() error {
+	u.f.Close()
+	return os.Remove(u.tmpPath)
+}
+
+type s3Storage struct {
+	s *s3.Storage
+}
+
+// OpenS3Storage returns a storage keeping backups in an s3-compatible bucket.
+// Backups are uploaded in parts as they are written, so no local copy of them is needed
+func OpenS3Storage(endpoint, accessKeyID, secretKey, bucket, prefix string) (Storage, error) {
+	s, err := s3.Open(endpoint, accessKeyID, secretKey, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Storage{s: s.(*s3.Storage)}, nil
+}
+
+func (s *s3Storage) String() string {
+	return s.s.String()
+}
+
+func (s *s3Storage) Create(ctx context.Context, name string) (Upload, error) {
+	name, err := CleanName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := s.s.NewWriter(ctx, name, s3PartSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Upload{w: w}, nil
+}
+
+func (s *s3Storage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	name, err := CleanName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.s.Get(ctx, name, 0, -1)
+}
+
+func (s *s3Storage) List(ctx context.Context, dir string) ([]string, error) {
+	dir, err := CleanName(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, _, err := s.s.ListEntries(ctx, dir+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, e := range entries {
+		names = append(names, dir+"/"+e.Name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (s *s3Storage) Delete(ctx context.Context, name string) error {
+	name, err := CleanName(name)
+	if err != nil {
+		return err
+	}
+
+	return s.s.Remove(ctx, name)
+}
+
+type s3Upload struct {
+	w *s3.Writer
+}
+
+func (u *s3Upload) Write(p []byte) (int, error) {
+	return u.w.Write(p)
+}
+
+func (u *s3Upload) Commit() error {
+	return u.w.Close()
+}
+
+func (u *s3Upload) Abort() error {
+	return u.w.Abort()
+}
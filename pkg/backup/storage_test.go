@@ -0,0 +1,113 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanName(t *testing.T) {
+	for _, name := range []string{"", ".", "..", "../db.bak", "/db.bak", "a/../../db.bak"} {
+		_, err := CleanName(name)
+		require.ErrorIs(t, err, ErrIllegalArguments, name)
+	}
+
+	name, err := CleanName("nightly/./db.bak")
+	require.NoError(t, err)
+	require.Equal(t, "nightly/db.bak", name)
+}
+
+func TestLocalStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backups")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := NewLocalStorage(dir)
+	require.Equal(t, dir, s.String())
+
+	_, err = s.Create(context.Background(), "../db.bak")
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = s.Open(context.Background(), "/db.bak")
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	upload, err := s.Create(context.Background(), "nightly/db.bak")
+	require.NoError(t, err)
+
+	_, err = upload.Write([]byte("backup"))
+	require.NoError(t, err)
+
+	_, err = s.Open(context.Background(), "nightly/db.bak")
+	require.True(t, os.IsNotExist(err))
+
+	err = upload.Commit()
+	require.NoError(t, err)
+
+	r, err := s.Open(context.Background(), "nightly/db.bak")
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, []byte("backup"), data)
+	require.NoError(t, r.Close())
+
+	upload, err = s.Create(context.Background(), "nightly/aborted.bak")
+	require.NoError(t, err)
+
+	_, err = upload.Write([]byte("partial"))
+	require.NoError(t, err)
+
+	err = upload.Abort()
+	require.NoError(t, err)
+
+	entries, err := ioutil.ReadDir(filepath.Join(dir, "nightly"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "db.bak", entries[0].Name())
+
+	upload, err = s.Create(context.Background(), "nightly/other.bak")
+	require.NoError(t, err)
+	require.NoError(t, upload.Commit())
+
+	// uploads in progress are not listed
+	_, err = s.Create(context.Background(), "nightly/pending.bak")
+	require.NoError(t, err)
+
+	names, err := s.List(context.Background(), "nightly")
+	require.NoError(t, err)
+	require.Equal(t, []string{"nightly/db.bak", "nightly/other.bak"}, names)
+
+	names, err = s.List(context.Background(), "missing")
+	require.NoError(t, err)
+	require.Empty(t, names)
+
+	_, err = s.List(context.Background(), "..")
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	err = s.Delete(context.Background(), "nightly/db.bak")
+	require.NoError(t, err)
+
+	names, err = s.List(context.Background(), "nightly")
+	require.NoError(t, err)
+	require.Equal(t, []string{"nightly/other.bak"}, names)
+}
@@ -50,9 +50,11 @@ type Auditor interface {
 }
 
 // AuditNotificationConfig holds the URL and credentials used to publish audit
-// result to ledger compliance.
+// result to ledger compliance. When AlertURL is set, the result is also published
+// there, but only when tampering is detected.
 type AuditNotificationConfig struct {
 	URL            string
+	AlertURL       string
 	Username       string
 	Password       string
 	RequestTimeout time.Duration
@@ -271,8 +273,10 @@ func (a *defaultAuditor) audit() error {
 	}
 
 	if err := a.verifyStateSignature(serverID, state); err != nil {
-		a.logger.Errorf("audit #%d aborted: %v", a.index, err)
-		withError = true
+		a.logger.Errorf("audit #%d detected possible tampering of db %s: %v", a.index, dbName, err)
+		checked = true
+		verified = false
+		a.notify(dbName, true, nil, state)
 		return noErr
 	}
 
@@ -289,10 +293,12 @@ func (a *defaultAuditor) audit() error {
 	if prevState != nil {
 		if isEmptyDB {
 			a.logger.Errorf(
-				"audit #%d aborted: database is empty on server %s @ %s, "+
+				"audit #%d detected possible tampering of db %s: database is empty on server %s @ %s, "+
 					"but locally a previous state exists with hash %x at id %d",
-				a.index, serverID, a.serverAddress, prevState.TxHash, prevState.TxId)
-			withError = true
+				a.index, dbName, serverID, a.serverAddress, prevState.TxHash, prevState.TxId)
+			checked = true
+			verified = false
+			a.notify(dbName, true, prevState, state)
 			return noErr
 		}
 
@@ -322,38 +328,7 @@ func (a *defaultAuditor) audit() error {
 			prevState.TxHash, prevState.TxId, state.TxHash, state.TxId)
 
 		checked = true
-		// publish audit notification
-		if len(a.notificationConfig.URL) > 0 {
-			err := a.publishAuditNotification(
-				dbName,
-				time.Now(),
-				!verified,
-				&State{
-					Tx:   prevState.TxId,
-					Hash: base64.StdEncoding.EncodeToString(prevState.TxHash),
-					Signature: Signature{
-						Signature: base64.StdEncoding.EncodeToString(prevState.GetSignature().GetSignature()),
-						PublicKey: base64.StdEncoding.EncodeToString(prevState.GetSignature().GetPublicKey()),
-					},
-				},
-				&State{
-					Tx:   state.TxId,
-					Hash: base64.StdEncoding.EncodeToString(state.TxHash),
-					Signature: Signature{
-						Signature: base64.StdEncoding.EncodeToString(state.GetSignature().GetSignature()),
-						PublicKey: base64.StdEncoding.EncodeToString(state.GetSignature().GetPublicKey()),
-					},
-				},
-			)
-			if err != nil {
-				a.logger.Errorf(
-					"error publishing audit notification for db %s: %v", dbName, err)
-			} else {
-				a.logger.Infof(
-					"audit notification for db %s has been published at %s",
-					dbName, a.notificationConfig.URL)
-			}
-		}
+		a.notify(dbName, !verified, prevState, state)
 	} else if isEmptyDB {
 		a.logger.Warningf("audit #%d canceled: database is empty on server %s @ %s",
 			a.index, serverID, a.serverAddress)
@@ -417,6 +392,47 @@ func (a *defaultAuditor) verifyStateSignature(
 	return nil
 }
 
+// notify publishes the audit result to the notification URL and, when tampering was detected, to the alert URL as well
+func (a *defaultAuditor) notify(db string, tampered bool, prevState, state *schema.ImmutableState) {
+	urls := []string{a.notificationConfig.URL}
+	if tampered {
+		urls = append(urls, a.notificationConfig.AlertURL)
+	}
+
+	runAt := time.Now()
+
+	for _, url := range urls {
+		if len(url) == 0 {
+			continue
+		}
+
+		err := a.publishAuditNotification(url, db, runAt, tampered, auditState(prevState), auditState(state))
+		if err != nil {
+			a.logger.Errorf(
+				"error publishing audit notification for db %s: %v", db, err)
+		} else {
+			a.logger.Infof(
+				"audit notification for db %s has been published at %s",
+				db, url)
+		}
+	}
+}
+
+func auditState(state *schema.ImmutableState) *State {
+	if state == nil {
+		return nil
+	}
+
+	return &State{
+		Tx:   state.TxId,
+		Hash: base64.StdEncoding.EncodeToString(state.TxHash),
+		Signature: Signature{
+			Signature: base64.StdEncoding.EncodeToString(state.GetSignature().GetSignature()),
+			PublicKey: base64.StdEncoding.EncodeToString(state.GetSignature().GetPublicKey()),
+		},
+	}
+}
+
 // Signature ...
 type Signature struct {
 	Signature string `json:"signature"`
@@ -442,6 +458,7 @@ type AuditNotificationRequest struct {
 }
 
 func (a *defaultAuditor) publishAuditNotification(
+	url string,
 	db string,
 	runAt time.Time,
 	tampered bool,
@@ -463,7 +480,7 @@ func (a *defaultAuditor) publishAuditNotification(
 		return err
 	}
 
-	req, err := http.NewRequest("POST", a.notificationConfig.URL, bytes.NewBuffer(reqBody))
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return err
 	}
@@ -485,7 +502,7 @@ func (a *defaultAuditor) publishAuditNotification(
 		return fmt.Errorf(
 			"POST %s request with payload %+v: "+
 				"got unexpected response status %s with response body %s",
-			a.notificationConfig.URL, payload,
+			url, payload,
 			resp.Status, respBody)
 	}
 
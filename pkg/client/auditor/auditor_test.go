@@ -19,6 +19,7 @@ package auditor
 import (
 	"context"
 	"crypto/ecdsa"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -642,6 +643,88 @@ func testDefaultAuditorRunOnDbWithInvalidSignature(t *testing.T, pk *ecdsa.Publi
 	require.Nil(t, err)
 }
 
+func TestDefaultAuditorAlertsOnTampering(t *testing.T) {
+	defer os.RemoveAll(dirname)
+
+	pk, err := signer.ParsePublicKeyFile("./../../../test/signer/ec3.pub")
+	require.NoError(t, err)
+
+	serviceClient := &clienttest.ImmuServiceClientMock{}
+
+	serviceClient.CurrentStateF = func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.ImmutableState, error) {
+		return &schema.ImmutableState{TxId: 1, TxHash: []byte{1}}, nil
+	}
+	serviceClient.LoginF = func(ctx context.Context, in *schema.LoginRequest, opts ...grpc.CallOption) (*schema.LoginResponse, error) {
+		return &schema.LoginResponse{Token: "token"}, nil
+	}
+	serviceClient.DatabaseListF = func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.DatabaseListResponse, error) {
+		return &schema.DatabaseListResponse{
+			Databases: []*schema.Database{{DatabaseName: "sysdb"}},
+		}, nil
+	}
+	serviceClient.UseDatabaseF = func(ctx context.Context, in *schema.Database, opts ...grpc.CallOption) (*schema.UseDatabaseReply, error) {
+		return &schema.UseDatabaseReply{Token: "sometoken"}, nil
+	}
+	serviceClient.LogoutF = func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+		return &empty.Empty{}, nil
+	}
+
+	var checked, verified bool
+
+	da, err := DefaultAuditor(
+		time.Duration(0),
+		fmt.Sprintf("%s:%d", "address", 0),
+		&[]grpc.DialOption{
+			grpc.WithInsecure(),
+		},
+		"immudb",
+		"immudb",
+		nil,
+		pk,
+		AuditNotificationConfig{
+			URL:      "http://notification-url.com",
+			AlertURL: "http://alert-url.com",
+		},
+		serviceClient,
+		state.NewUUIDProvider(serviceClient),
+		cache.NewHistoryFileCache(dirname),
+		func(_ string, _ string, c bool, _ bool, v bool, _ *schema.ImmutableState, _ *schema.ImmutableState) {
+			checked = c
+			verified = v
+		},
+		logger.NewSimpleLogger("test", os.Stdout),
+		nil)
+	require.NoError(t, err)
+
+	var published []string
+	var notification AuditNotificationRequest
+
+	da.(*defaultAuditor).notificationConfig.publishFunc = func(req *http.Request) (*http.Response, error) {
+		published = append(published, req.URL.String())
+
+		err := json.NewDecoder(req.Body).Decode(&notification)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+
+	// the state is not signed although a public key is configured
+	auditorDone := make(chan struct{}, 1)
+	err = da.Run(time.Duration(10), true, context.TODO().Done(), auditorDone)
+	require.NoError(t, err)
+
+	require.True(t, checked)
+	require.False(t, verified)
+	require.Equal(t, []string{"http://notification-url.com", "http://alert-url.com"}, published)
+	require.True(t, notification.Tampered)
+	require.Equal(t, "sysdb", notification.DB)
+	require.Nil(t, notification.PreviousState)
+	require.Equal(t, uint64(1), notification.CurrentState.Tx)
+}
+
 type PasswordReader struct {
 	Pass       []string
 	callNumber int
@@ -675,6 +758,7 @@ func TestPublishAuditNotification(t *testing.T) {
 
 	// test happy path
 	err = a.publishAuditNotification(
+		a.notificationConfig.URL,
 		"some-db",
 		runAt,
 		true,
@@ -692,6 +776,7 @@ func TestPublishAuditNotification(t *testing.T) {
 		}, nil
 	}
 	err = a.publishAuditNotification(
+		a.notificationConfig.URL,
 		"some-db2",
 		runAt,
 		false,
@@ -717,8 +802,8 @@ func TestPublishAuditNotification(t *testing.T) {
 
 	// test error creating request
 	a.notificationConfig.RequestTimeout = 1 * time.Second
-	a.notificationConfig.URL = string([]byte{0})
 	err = a.publishAuditNotification(
+		string([]byte{0}),
 		"some-db4",
 		runAt,
 		true,
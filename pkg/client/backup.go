@@ -0,0 +1,90 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/stream"
+	"github.com/golang/protobuf/ptypes/empty"
+)
+
+// Backup takes an online backup of a database. The archive is written into w,
+// unless a path is provided in the request, in which case it's written on the server side
+func (c *immuClient) Backup(ctx context.Context, req *schema.BackupRequest, w io.Writer) error {
+	start := time.Now()
+
+	if req == nil || (req.Path == "" && w == nil) {
+		return ErrIllegalArguments
+	}
+
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+
+	backupClient, err := c.ServiceClient.Backup(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if w == nil {
+		w = ioutil.Discard
+	}
+
+	_, err = io.Copy(w, stream.NewChunkReader(backupClient))
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Debugf("Backup finished in %s", time.Since(start))
+
+	return nil
+}
+
+// Restore creates a new database out of a chain of server-side backups, optionally up to a given transaction or timestamp
+func (c *immuClient) Restore(ctx context.Context, req *schema.RestoreRequest) (*schema.RestoreResponse, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	res, err := c.ServiceClient.Restore(ctx, req)
+
+	c.Logger.Debugf("Restore finished in %s", time.Since(start))
+
+	return res, err
+}
+
+// BackupStatus returns the status of the backups scheduled on the server
+func (c *immuClient) BackupStatus(ctx context.Context) (*schema.BackupStatusResponse, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	res, err := c.ServiceClient.BackupStatus(ctx, &empty.Empty{})
+
+	c.Logger.Debugf("BackupStatus finished in %s", time.Since(start))
+
+	return res, err
+}
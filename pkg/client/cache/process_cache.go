@@ -0,0 +1,86 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"sync"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+type processCache struct {
+	states map[string]map[string]*schema.ImmutableState
+	lock   sync.RWMutex
+
+	// held between Lock and Unlock, as the lock on the state file of the file cache
+	cacheLock sync.Mutex
+	locked    bool
+}
+
+// NewProcessCache returns a cache keeping the states in memory, so they can be shared only within the process.
+// Unlike the in-memory cache it can back the state service of a client, as the file cache does
+func NewProcessCache() Cache {
+	return &processCache{
+		states: map[string]map[string]*schema.ImmutableState{},
+	}
+}
+
+func (pc *processCache) Get(serverUUID, db string) (*schema.ImmutableState, error) {
+	pc.lock.RLock()
+	defer pc.lock.RUnlock()
+
+	state, ok := pc.states[serverUUID][db]
+	if !ok {
+		return nil, ErrPrevStateNotFound
+	}
+	return state, nil
+}
+
+func (pc *processCache) Set(serverUUID, db string, state *schema.ImmutableState) error {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if _, ok := pc.states[serverUUID]; !ok {
+		pc.states[serverUUID] = map[string]*schema.ImmutableState{}
+	}
+	pc.states[serverUUID][db] = state
+	return nil
+}
+
+func (pc *processCache) Lock(serverUUID string) error {
+	pc.cacheLock.Lock()
+
+	pc.lock.Lock()
+	pc.locked = true
+	pc.lock.Unlock()
+
+	return nil
+}
+
+func (pc *processCache) Unlock() error {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if !pc.locked {
+		return ErrCacheNotLocked
+	}
+
+	pc.locked = false
+	pc.cacheLock.Unlock()
+
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package client
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"crypto/sha256"
@@ -34,6 +35,7 @@ import (
 	"github.com/codenotary/immudb/pkg/stream"
 
 	"github.com/codenotary/immudb/pkg/signer"
+	"github.com/codenotary/immudb/pkg/tsa"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 
 	"github.com/codenotary/immudb/embedded/store"
@@ -88,23 +90,61 @@ type ImmuClient interface {
 	CreateDatabase(ctx context.Context, d *schema.DatabaseSettings) error
 	UseDatabase(ctx context.Context, d *schema.Database) (*schema.UseDatabaseReply, error)
 	UpdateDatabase(ctx context.Context, settings *schema.DatabaseSettings) error
+	UpdateDatabaseSettings(ctx context.Context, req *schema.UpdateDatabaseSettingsRequest) (*schema.UpdateDatabaseSettingsResponse, error)
+	DeleteDatabase(ctx context.Context, req *schema.DeleteDatabaseRequest) error
+	CloneDatabase(ctx context.Context, req *schema.CloneDatabaseRequest) (*schema.CloneDatabaseResponse, error)
+	ExportDatabase(ctx context.Context, req *schema.ExportDatabaseRequest, w io.Writer) error
+	ImportDatabase(ctx context.Context, r io.Reader) (*schema.ImportDatabaseResponse, error)
+	Backup(ctx context.Context, req *schema.BackupRequest, w io.Writer) error
+	Restore(ctx context.Context, req *schema.RestoreRequest) (*schema.RestoreResponse, error)
+	BackupStatus(ctx context.Context) (*schema.BackupStatusResponse, error)
+	UpdateCorruptionChecker(ctx context.Context, req *schema.UpdateCorruptionCheckerRequest) (*schema.CorruptionCheckerSettings, error)
+	CorruptionCheckStatus(ctx context.Context, req *schema.CorruptionCheckStatusRequest) (*schema.CorruptionCheckStatusResponse, error)
+	Scrub(ctx context.Context, req *schema.ScrubRequest) (*schema.ScrubResponse, error)
+	AcknowledgeCorruption(ctx context.Context, req *schema.AcknowledgeCorruptionRequest) error
 
 	SetActiveUser(ctx context.Context, u *schema.SetActiveUserRequest) error
 
 	CleanIndex(ctx context.Context, req *emptypb.Empty) error
 
 	CurrentState(ctx context.Context) (*schema.ImmutableState, error)
+	StateHistory(ctx context.Context, req *schema.StateHistoryRequest) (*schema.StateList, error)
+	TimestampTokens(ctx context.Context, req *schema.TimestampTokensRequest) (*schema.TimestampTokenList, error)
+	AnchorReceipts(ctx context.Context, req *schema.AnchorReceiptsRequest) (*schema.AnchorReceiptList, error)
+	ComplianceReport(ctx context.Context, req *schema.ComplianceReportRequest) (*schema.ComplianceReportResponse, error)
+	VerifyRange(ctx context.Context, req *schema.VerifyRangeRequest) (*schema.VerifyRangeResponse, error)
 
 	Set(ctx context.Context, key []byte, value []byte) (*schema.TxMetadata, error)
 	VerifiedSet(ctx context.Context, key []byte, value []byte) (*schema.TxMetadata, error)
+	SetNX(ctx context.Context, key []byte, value []byte) (*schema.TxMetadata, error)
+	SetWithAttributes(ctx context.Context, key []byte, value []byte, attributes map[string]string) (*schema.TxMetadata, error)
+	VerifiedSetNX(ctx context.Context, key []byte, value []byte) (*schema.TxMetadata, error)
+	VerifiedSetWithAttributes(ctx context.Context, key []byte, value []byte, attributes map[string]string) (*schema.TxMetadata, error)
+	SetConditional(ctx context.Context, req *schema.SetConditionalRequest) (*schema.TxMetadata, error)
+	Delete(ctx context.Context, req *schema.DeleteKeysRequest) (*schema.TxMetadata, error)
+	DeletePrefix(ctx context.Context, req *schema.DeletePrefixRequest) (*schema.TxMetadata, error)
+	Append(ctx context.Context, key []byte, value []byte) (*schema.AppendResponse, error)
+
+	BeginTx(ctx context.Context) (string, error)
+	TxSet(ctx context.Context, txID string, key []byte, value []byte) error
+	TxGet(ctx context.Context, txID string, key []byte) (*schema.Entry, error)
+	Commit(ctx context.Context, txID string) (*schema.TxMetadata, error)
+	Rollback(ctx context.Context, txID string) error
+	ExpirableSet(ctx context.Context, key []byte, value []byte, expiresAt time.Time) (*schema.TxMetadata, error)
 
 	Get(ctx context.Context, key []byte) (*schema.Entry, error)
 	GetSince(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error)
 	GetAt(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error)
+	GetAsOfTx(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error)
+	GetAsOfTime(ctx context.Context, key []byte, t time.Time) (*schema.Entry, error)
+	GetFromSnapshot(ctx context.Context, snapshot string, key []byte) (*schema.Entry, error)
 
 	VerifiedGet(ctx context.Context, key []byte) (*schema.Entry, error)
 	VerifiedGetSince(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error)
 	VerifiedGetAt(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error)
+	VerifiedGetAtCurrentRoot(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error)
+	VerifiedGetAsOfTx(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error)
+	VerifiedGetAsOfTime(ctx context.Context, key []byte, t time.Time) (*schema.Entry, error)
 
 	History(ctx context.Context, req *schema.HistoryRequest) (*schema.Entries, error)
 
@@ -114,8 +154,20 @@ type ImmuClient interface {
 	ZAddAt(ctx context.Context, set []byte, score float64, key []byte, atTx uint64) (*schema.TxMetadata, error)
 	VerifiedZAddAt(ctx context.Context, set []byte, score float64, key []byte, atTx uint64) (*schema.TxMetadata, error)
 
+	ZRem(ctx context.Context, set []byte, score float64, key []byte) (*schema.TxMetadata, error)
+	ZRemAt(ctx context.Context, set []byte, score float64, key []byte, atTx uint64) (*schema.TxMetadata, error)
+
 	Scan(ctx context.Context, req *schema.ScanRequest) (*schema.Entries, error)
 	ZScan(ctx context.Context, req *schema.ZScanRequest) (*schema.ZEntries, error)
+	VerifiedZScan(ctx context.Context, req *schema.ZScanRequest) (*schema.ZEntries, error)
+	ResolveReferenceHistory(ctx context.Context, req *schema.ReferenceHistoryRequest) ([]*schema.ReferenceRevision, error)
+	ReferencesTo(ctx context.Context, referencedKey []byte) (*schema.References, error)
+	MultiInclusion(ctx context.Context, req *schema.MultiInclusionRequest) ([]*schema.Entry, error)
+	ZCard(ctx context.Context, set []byte) (*schema.EntryCount, error)
+	ZCount(ctx context.Context, set []byte, minScore, maxScore *schema.Score) (*schema.EntryCount, error)
+
+	OpenSnapshot(ctx context.Context, name string, atTx uint64) (*schema.OpenSnapshotResponse, error)
+	ReleaseSnapshot(ctx context.Context, name string) error
 
 	TxByID(ctx context.Context, tx uint64) (*schema.Tx, error)
 	VerifiedTxByID(ctx context.Context, tx uint64) (*schema.Tx, error)
@@ -134,6 +186,7 @@ type ImmuClient interface {
 
 	SetReferenceAt(ctx context.Context, key []byte, referencedKey []byte, atTx uint64) (*schema.TxMetadata, error)
 	VerifiedSetReferenceAt(ctx context.Context, key []byte, referencedKey []byte, atTx uint64) (*schema.TxMetadata, error)
+	SetReferenceAtRevision(ctx context.Context, key []byte, referencedKey []byte, revision int64) (*schema.TxMetadata, error)
 
 	Dump(ctx context.Context, writer io.WriteSeeker) (int64, error)
 
@@ -146,14 +199,29 @@ type ImmuClient interface {
 	StreamHistory(ctx context.Context, req *schema.HistoryRequest) (*schema.Entries, error)
 	StreamExecAll(ctx context.Context, req *stream.ExecAllRequest) (*schema.TxMetadata, error)
 
+	Subscribe(ctx context.Context, req *schema.SubscribeRequest) (schema.ImmuService_SubscribeClient, error)
+	StreamTxs(ctx context.Context, req *schema.StreamTxsRequest) (schema.ImmuService_StreamTxsClient, error)
+
 	ExportTx(ctx context.Context, req *schema.TxRequest) (schema.ImmuService_ExportTxClient, error)
 	ReplicateTx(ctx context.Context) (schema.ImmuService_ReplicateTxClient, error)
+	ConfirmReplication(ctx context.Context, req *schema.ConfirmReplicationRequest) error
+	ReplicationStatus(ctx context.Context) (*schema.ReplicationStatusResponse, error)
+	PrimaryEndpoint(ctx context.Context) (*schema.PrimaryEndpointResponse, error)
+	ReplicationEvents(ctx context.Context, req *schema.ReplicationEventsRequest) (schema.ImmuService_ReplicationEventsClient, error)
+	UpdateReplication(ctx context.Context, req *schema.UpdateReplicationRequest) (*schema.UpdateReplicationResponse, error)
+	ReplicationAudit(ctx context.Context, req *schema.ReplicationAuditRequest) (*schema.ReplicationAuditList, error)
+	ExportSnapshot(ctx context.Context, req *schema.SnapshotRequest) (schema.ImmuService_ExportSnapshotClient, error)
+	SyncSnapshot(ctx context.Context, req *schema.SyncSnapshotRequest) (*schema.SyncSnapshotResponse, error)
+
+	ClusterStatus(ctx context.Context, req *schema.ClusterStatusRequest) (*schema.ClusterStatusResponse, error)
 
 	SQLExec(ctx context.Context, sql string, params map[string]interface{}) (*schema.SQLExecResult, error)
 	UseSnapshot(ctx context.Context, sinceTx, asBeforeTx uint64) error
 	SQLQuery(ctx context.Context, sql string, params map[string]interface{}, renewSnapshot bool) (*schema.SQLQueryResult, error)
 	ListTables(ctx context.Context) (*schema.SQLQueryResult, error)
 	DescribeTable(ctx context.Context, tableName string) (*schema.SQLQueryResult, error)
+	SQLDump(ctx context.Context, req *schema.SQLDumpRequest, w io.Writer) error
+	SQLLoad(ctx context.Context, r io.Reader) (*schema.SQLExecResult, error)
 
 	VerifyRow(ctx context.Context, row *schema.Row, table string, pkVal *schema.SQLValue) error
 }
@@ -170,6 +238,7 @@ type immuClient struct {
 	Tkns                 TokenService
 	serverSigningPubKey  *ecdsa.PublicKey
 	StreamServiceFactory stream.ServiceFactory
+	router               *endpointRouter
 	sync.RWMutex
 }
 
@@ -210,6 +279,16 @@ func NewImmuClient(options *Options) (c ImmuClient, err error) {
 		return nil, errors.New(stream.ErrChunkTooSmall).WithCode(errors.CodInvalidParameterValue)
 	}
 
+	if len(options.Endpoints) > 0 {
+		primary, err := primaryEndpoint(options.Endpoints)
+		if err != nil {
+			return nil, err
+		}
+
+		options.Address = primary.Address
+		options.Port = primary.Port
+	}
+
 	c.WithOptions(options)
 
 	var clientConn *grpc.ClientConn
@@ -226,20 +305,38 @@ func NewImmuClient(options *Options) (c ImmuClient, err error) {
 		return nil, err
 	}
 
-	if err = os.MkdirAll(options.Dir, os.ModePerm); err != nil {
-		return nil, logErr(l, "Unable to create program file folder: %s", err)
+	stateCache := options.StateCache
+
+	if stateCache == nil {
+		if err = os.MkdirAll(options.Dir, os.ModePerm); err != nil {
+			return nil, logErr(l, "Unable to create program file folder: %s", err)
+		}
+
+		stateCache = cache.NewFileCache(options.Dir)
 	}
 
 	stateProvider := state.NewStateProvider(serviceClient)
 	uuidProvider := state.NewUUIDProvider(serviceClient)
 
-	stateService, err := state.NewStateService(cache.NewFileCache(options.Dir), l, stateProvider, uuidProvider)
+	stateService, err := state.NewStateService(stateCache, l, stateProvider, uuidProvider)
 	if err != nil {
 		return nil, logErr(l, "Unable to create state service: %s", err)
 	}
 
 	c.WithStateService(stateService)
 
+	if len(options.Endpoints) > 0 {
+		ic := c.(*immuClient)
+
+		// the state and the server uuid are always those of the primary
+		ic.router, err = newEndpointRouter(clientConn, options.Endpoints, *options.DialOptions, ic.serverSigningPubKey == nil, l)
+		if err != nil {
+			return nil, logErr(l, "Unable to connect to the replicas: %s", err)
+		}
+
+		c.WithServiceClient(schema.NewImmuServiceClient(ic.router))
+	}
+
 	return c, nil
 }
 
@@ -341,6 +438,11 @@ func (c *immuClient) Disconnect() error {
 		return err
 	}
 
+	if c.router != nil {
+		c.router.close()
+		c.router = nil
+	}
+
 	c.ServiceClient = nil
 	c.clientConn = nil
 
@@ -518,6 +620,112 @@ func (c *immuClient) CurrentState(ctx context.Context) (*schema.ImmutableState,
 	return c.ServiceClient.CurrentState(ctx, &empty.Empty{})
 }
 
+// ComplianceReport returns the report of the current database for the requested period.
+// The signature of the report is checked when the server signing public key is provided
+func (c *immuClient) ComplianceReport(ctx context.Context, req *schema.ComplianceReportRequest) (*schema.ComplianceReportResponse, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	report, err := c.ServiceClient.ComplianceReport(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.serverSigningPubKey != nil {
+		ok, err := report.CheckSignature(c.serverSigningPubKey)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, store.ErrCorruptedData
+		}
+	}
+
+	return report, nil
+}
+
+// VerifyRange verifies on demand the requested transactions or key prefix of the current database
+func (c *immuClient) VerifyRange(ctx context.Context, req *schema.VerifyRangeRequest) (*schema.VerifyRangeResponse, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("VerifyRange finished in %s", time.Since(start))
+
+	return c.ServiceClient.VerifyRange(ctx, req)
+}
+
+// StateHistory pages through the past states of the current database.
+// Their signatures are checked when the server signing public key is provided
+func (c *immuClient) StateHistory(ctx context.Context, req *schema.StateHistoryRequest) (*schema.StateList, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	stateList, err := c.ServiceClient.StateHistory(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.serverSigningPubKey != nil {
+		for _, hs := range stateList.States {
+			state := &schema.ImmutableState{
+				Db:        stateList.Db,
+				TxId:      hs.TxId,
+				TxHash:    hs.TxHash,
+				Signature: hs.Signature,
+			}
+
+			ok, err := state.CheckSignature(c.serverSigningPubKey)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, store.ErrCorruptedData
+			}
+		}
+	}
+
+	return stateList, nil
+}
+
+// TimestampTokens returns the RFC 3161 time-stamp tokens of the roots of the current database.
+// Tokens not matching the root they were issued for are reported as corrupted data
+func (c *immuClient) TimestampTokens(ctx context.Context, req *schema.TimestampTokensRequest) (*schema.TimestampTokenList, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	list, err := c.ServiceClient.TimestampTokens(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range list.Tokens {
+		token, err := tsa.ParseToken(t.Token)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(token.HashedMessage, t.TxHash) {
+			return nil, store.ErrCorruptedData
+		}
+	}
+
+	return list, nil
+}
+
+// AnchorReceipts returns the receipts of the roots of the current database published to an external ledger
+func (c *immuClient) AnchorReceipts(ctx context.Context, req *schema.AnchorReceiptsRequest) (*schema.AnchorReceiptList, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	return c.ServiceClient.AnchorReceipts(ctx, req)
+}
+
 // Get ...
 func (c *immuClient) Get(ctx context.Context, key []byte) (*schema.Entry, error) {
 	if !c.IsConnected() {
@@ -549,6 +757,26 @@ func (c *immuClient) VerifiedGetSince(ctx context.Context, key []byte, tx uint64
 	})
 }
 
+// VerifiedGetAsOfTx returns the verified value the key had right after the transaction tx was committed
+func (c *immuClient) VerifiedGetAsOfTx(ctx context.Context, key []byte, tx uint64) (vi *schema.Entry, err error) {
+	start := time.Now()
+	defer c.Logger.Debugf("VerifiedGetAsOfTx finished in %s", time.Since(start))
+	return c.verifiedGet(ctx, &schema.KeyRequest{
+		Key:    key,
+		AsOfTx: tx,
+	})
+}
+
+// VerifiedGetAsOfTime returns the verified value the key had at the given time
+func (c *immuClient) VerifiedGetAsOfTime(ctx context.Context, key []byte, t time.Time) (vi *schema.Entry, err error) {
+	start := time.Now()
+	defer c.Logger.Debugf("VerifiedGetAsOfTime finished in %s", time.Since(start))
+	return c.verifiedGet(ctx, &schema.KeyRequest{
+		Key:      key,
+		AsOfTime: t.Unix(),
+	})
+}
+
 // VerifiedGetAt ...
 func (c *immuClient) VerifiedGetAt(ctx context.Context, key []byte, tx uint64) (vi *schema.Entry, err error) {
 	start := time.Now()
@@ -559,6 +787,23 @@ func (c *immuClient) VerifiedGetAt(ctx context.Context, key []byte, tx uint64) (
 	})
 }
 
+// VerifiedGetAtCurrentRoot returns the value the key was set to at transaction tx, proven against
+// the current root of the database instead of the transaction it was written in
+func (c *immuClient) VerifiedGetAtCurrentRoot(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error) {
+	if tx == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	entries, err := c.MultiInclusion(ctx, &schema.MultiInclusionRequest{
+		Keys: []*schema.InclusionKey{{Key: key, AtTx: tx}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries[0], nil
+}
+
 func (c *immuClient) verifiedGet(ctx context.Context, kReq *schema.KeyRequest) (vi *schema.Entry, err error) {
 	err = c.StateService.CacheLock()
 	if err != nil {
@@ -594,62 +839,454 @@ func (c *immuClient) verifiedGet(ctx context.Context, kReq *schema.KeyRequest) (
 	var sourceAlh, targetAlh [sha256.Size]byte
 
 	var vTx uint64
+	var vTxTs int64
 	var kv *store.KV
 
 	if vEntry.Entry.ReferencedBy == nil {
 		vTx = vEntry.Entry.Tx
-		kv = database.EncodeKV(kReq.Key, vEntry.Entry.Value)
+		kv = database.EncodeKVWithMetadata(kReq.Key, vEntry.Entry.Value, vEntry.Entry.Metadata)
 	} else {
 		vTx = vEntry.Entry.ReferencedBy.Tx
 		kv = database.EncodeReference(vEntry.Entry.ReferencedBy.Key, vEntry.Entry.Key, vEntry.Entry.ReferencedBy.AtTx)
 	}
 
+	// reads pinned to a transaction must be proven at that transaction and not at another revision of the key
+	if kReq.AtTx > 0 && vTx != kReq.AtTx {
+		return nil, store.ErrCorruptedData
+	}
+
 	if state.TxId <= vTx {
 		eh = schema.DigestFrom(vEntry.VerifiableTx.DualProof.TargetTxMetadata.EH)
 
-		sourceID = state.TxId
-		sourceAlh = schema.DigestFrom(state.TxHash)
-		targetID = vTx
-		targetAlh = dualProof.TargetTxMetadata.Alh()
-	} else {
-		eh = schema.DigestFrom(vEntry.VerifiableTx.DualProof.SourceTxMetadata.EH)
+		sourceID = state.TxId
+		sourceAlh = schema.DigestFrom(state.TxHash)
+		targetID = vTx
+		targetAlh = dualProof.TargetTxMetadata.Alh()
+		vTxTs = dualProof.TargetTxMetadata.Ts
+	} else {
+		eh = schema.DigestFrom(vEntry.VerifiableTx.DualProof.SourceTxMetadata.EH)
+
+		sourceID = vTx
+		sourceAlh = dualProof.SourceTxMetadata.Alh()
+		targetID = state.TxId
+		targetAlh = schema.DigestFrom(state.TxHash)
+		vTxTs = dualProof.SourceTxMetadata.Ts
+	}
+
+	// a revision written after the requested point in time can not be the one as of it
+	if (kReq.AsOfTx > 0 && vTx > kReq.AsOfTx) || (kReq.AsOfTime > 0 && vTxTs > kReq.AsOfTime) {
+		return nil, store.ErrCorruptedData
+	}
+
+	verifies := store.VerifyInclusion(
+		inclusionProof,
+		kv,
+		eh)
+	if !verifies {
+		return nil, store.ErrCorruptedData
+	}
+
+	if state.TxId > 0 {
+		verifies = store.VerifyDualProof(
+			dualProof,
+			sourceID,
+			targetID,
+			sourceAlh,
+			targetAlh,
+		)
+		if !verifies {
+			return nil, store.ErrCorruptedData
+		}
+	}
+
+	newState := &schema.ImmutableState{
+		Db:        c.currentDatabase(),
+		TxId:      targetID,
+		TxHash:    targetAlh[:],
+		Signature: vEntry.VerifiableTx.Signature,
+	}
+
+	if c.serverSigningPubKey != nil {
+		ok, err := newState.CheckSignature(c.serverSigningPubKey)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, store.ErrCorruptedData
+		}
+	}
+
+	err = c.StateService.SetState(c.Options.CurrentDatabase, newState)
+	if err != nil {
+		return nil, err
+	}
+
+	return vEntry.Entry, nil
+}
+
+// GetSince ...
+func (c *immuClient) GetSince(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("get finished in %s", time.Since(start))
+
+	return c.ServiceClient.Get(ctx, &schema.KeyRequest{Key: key, SinceTx: tx})
+}
+
+// GetAt ...
+func (c *immuClient) GetAt(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("get finished in %s", time.Since(start))
+
+	return c.ServiceClient.Get(ctx, &schema.KeyRequest{Key: key, AtTx: tx})
+}
+
+// GetAsOfTx returns the value the key had right after the transaction tx was committed
+func (c *immuClient) GetAsOfTx(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("get finished in %s", time.Since(start))
+
+	return c.ServiceClient.Get(ctx, &schema.KeyRequest{Key: key, AsOfTx: tx})
+}
+
+// GetAsOfTime returns the value the key had at the given time
+func (c *immuClient) GetAsOfTime(ctx context.Context, key []byte, t time.Time) (*schema.Entry, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("get finished in %s", time.Since(start))
+
+	return c.ServiceClient.Get(ctx, &schema.KeyRequest{Key: key, AsOfTime: t.Unix()})
+}
+
+// GetFromSnapshot returns the value the key had in a named snapshot
+func (c *immuClient) GetFromSnapshot(ctx context.Context, snapshot string, key []byte) (*schema.Entry, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("get finished in %s", time.Since(start))
+
+	return c.ServiceClient.Get(ctx, &schema.KeyRequest{Key: key, Snapshot: snapshot})
+}
+
+// Scan ...
+func (c *immuClient) Scan(ctx context.Context, req *schema.ScanRequest) (*schema.Entries, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	return c.ServiceClient.Scan(ctx, req)
+}
+
+// ZScan ...
+func (c *immuClient) ZScan(ctx context.Context, req *schema.ZScanRequest) (*schema.ZEntries, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	return c.ServiceClient.ZScan(ctx, req)
+}
+
+// VerifiedZScan scans a sorted set verifying every returned element, and the entry it refers to,
+// against the current state of the database, which is in turn verified against the local state
+func (c *immuClient) VerifiedZScan(ctx context.Context, req *schema.ZScanRequest) (*schema.ZEntries, error) {
+	err := c.StateService.CacheLock()
+	if err != nil {
+		return nil, err
+	}
+	defer c.StateService.CacheUnlock()
+
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("verified zscan finished in %s", time.Since(start))
+
+	state, err := c.StateService.GetState(ctx, c.Options.CurrentDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	vEntries, err := c.ServiceClient.VerifiableZScan(ctx, &schema.VerifiableZScanRequest{
+		ZScanRequest: req,
+		ProveSinceTx: state.TxId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rootState, err := c.verifyRoot(state, vEntries.VerifiableTx)
+	if err != nil {
+		return nil, err
+	}
+
+	rootID := rootState.TxId
+	rootAlh := schema.DigestFrom(rootState.TxHash)
+
+	list := &schema.ZEntries{NextCursor: vEntries.NextCursor}
+
+	for _, vze := range vEntries.Entries {
+		ze := vze.ZEntry
+		if ze == nil {
+			return nil, store.ErrCorruptedData
+		}
+
+		zkv := database.EncodeZAdd(ze.Set, ze.Score, database.EncodeKey(ze.Key), ze.AtTx)
+
+		if !verifyAgainstRoot(vze.InclusionProof, vze.DualProof, zkv, rootID, rootAlh) {
+			return nil, store.ErrCorruptedData
+		}
+
+		if ze.Entry != nil {
+			// the entry must be the one the element refers to
+			if !bytes.Equal(ze.Entry.Key, ze.Key) || (ze.AtTx > 0 && ze.Entry.Tx != ze.AtTx) {
+				return nil, store.ErrCorruptedData
+			}
+
+			kv := database.EncodeKVWithMetadata(ze.Entry.Key, ze.Entry.Value, ze.Entry.Metadata)
+
+			if vze.EntryDualProof == nil || vze.EntryDualProof.SourceTxMetadata == nil || vze.EntryDualProof.SourceTxMetadata.Id != ze.Entry.Tx {
+				return nil, store.ErrCorruptedData
+			}
+
+			if !verifyAgainstRoot(vze.EntryInclusionProof, vze.EntryDualProof, kv, rootID, rootAlh) {
+				return nil, store.ErrCorruptedData
+			}
+		}
+
+		list.Entries = append(list.Entries, ze)
+	}
+
+	err = c.StateService.SetState(c.Options.CurrentDatabase, rootState)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// ResolveReferenceHistory returns the targets a reference has pointed to, verifying every revision
+// against the current state of the database, which is in turn verified against the local state
+func (c *immuClient) ResolveReferenceHistory(ctx context.Context, req *schema.ReferenceHistoryRequest) ([]*schema.ReferenceRevision, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	err := c.StateService.CacheLock()
+	if err != nil {
+		return nil, err
+	}
+	defer c.StateService.CacheUnlock()
+
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("resolve reference history finished in %s", time.Since(start))
+
+	state, err := c.StateService.GetState(ctx, c.Options.CurrentDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := c.ServiceClient.ResolveReferenceHistory(ctx, &schema.ReferenceHistoryRequest{
+		Key:          req.Key,
+		Offset:       req.Offset,
+		Limit:        req.Limit,
+		Desc:         req.Desc,
+		SinceTx:      req.SinceTx,
+		ProveSinceTx: state.TxId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rootState, err := c.verifyRoot(state, history.VerifiableTx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rev := range history.Revisions {
+		if rev.DualProof == nil || rev.DualProof.SourceTxMetadata == nil || rev.DualProof.SourceTxMetadata.Id != rev.Tx {
+			return nil, store.ErrCorruptedData
+		}
+
+		kv := database.EncodeReference(req.Key, rev.ReferencedKey, rev.AtTx)
+
+		if !verifyAgainstRoot(rev.InclusionProof, rev.DualProof, kv, rootState.TxId, schema.DigestFrom(rootState.TxHash)) {
+			return nil, store.ErrCorruptedData
+		}
+	}
+
+	err = c.StateService.SetState(c.Options.CurrentDatabase, rootState)
+	if err != nil {
+		return nil, err
+	}
+
+	return history.Revisions, nil
+}
+
+// MultiInclusion returns the entries of a list of keys, verifying all of them against the current state of the database
+// with a single request. The state is in turn verified against the local state
+func (c *immuClient) MultiInclusion(ctx context.Context, req *schema.MultiInclusionRequest) ([]*schema.Entry, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	err := c.StateService.CacheLock()
+	if err != nil {
+		return nil, err
+	}
+	defer c.StateService.CacheUnlock()
+
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("multi inclusion finished in %s", time.Since(start))
+
+	state, err := c.StateService.GetState(ctx, c.Options.CurrentDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.ServiceClient.MultiInclusion(ctx, &schema.MultiInclusionRequest{
+		Keys:         req.Keys,
+		SinceTx:      req.SinceTx,
+		ProveSinceTx: state.TxId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rootState, err := c.verifyRoot(state, res.VerifiableTx)
+	if err != nil {
+		return nil, err
+	}
+
+	rootID := rootState.TxId
+	rootAlh := schema.DigestFrom(rootState.TxHash)
+
+	if len(res.Entries) != len(req.Keys) {
+		return nil, store.ErrCorruptedData
+	}
+
+	entries := make([]*schema.Entry, len(res.Entries))
+
+	for i, pe := range res.Entries {
+		e := pe.Entry
+		if e == nil || req.Keys[i] == nil {
+			return nil, store.ErrCorruptedData
+		}
+
+		// the key and transaction of the entry, or of the reference it was resolved through, must be the requested ones
+		keyTx := e.Tx
+		key := e.Key
+
+		if e.ReferencedBy != nil {
+			keyTx = e.ReferencedBy.Tx
+			key = e.ReferencedBy.Key
+
+			if e.ReferencedBy.AtTx > 0 && e.Tx != e.ReferencedBy.AtTx {
+				return nil, store.ErrCorruptedData
+			}
+
+			if pe.ReferenceDualProof == nil || pe.ReferenceDualProof.SourceTxMetadata == nil || pe.ReferenceDualProof.SourceTxMetadata.Id != e.ReferencedBy.Tx {
+				return nil, store.ErrCorruptedData
+			}
+
+			refKV := database.EncodeReference(e.ReferencedBy.Key, e.Key, e.ReferencedBy.AtTx)
+
+			if !verifyAgainstRoot(pe.ReferenceInclusionProof, pe.ReferenceDualProof, refKV, rootID, rootAlh) {
+				return nil, store.ErrCorruptedData
+			}
+		}
+
+		if !bytes.Equal(key, req.Keys[i].Key) || (req.Keys[i].AtTx > 0 && keyTx != req.Keys[i].AtTx) {
+			return nil, store.ErrCorruptedData
+		}
+
+		if pe.DualProof == nil || pe.DualProof.SourceTxMetadata == nil || pe.DualProof.SourceTxMetadata.Id != e.Tx {
+			return nil, store.ErrCorruptedData
+		}
+
+		kv := database.EncodeKVWithMetadata(e.Key, e.Value, e.Metadata)
+
+		if !verifyAgainstRoot(pe.InclusionProof, pe.DualProof, kv, rootID, rootAlh) {
+			return nil, store.ErrCorruptedData
+		}
+
+		entries[i] = e
+	}
+
+	err = c.StateService.SetState(c.Options.CurrentDatabase, rootState)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
 
-		sourceID = vTx
-		sourceAlh = dualProof.SourceTxMetadata.Alh()
-		targetID = state.TxId
-		targetAlh = schema.DigestFrom(state.TxHash)
+// ReferencesTo lists the references currently pointing at the key
+func (c *immuClient) ReferencesTo(ctx context.Context, referencedKey []byte) (*schema.References, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
 	}
 
-	verifies := store.VerifyInclusion(
-		inclusionProof,
-		kv,
-		eh)
-	if !verifies {
+	return c.ServiceClient.ReferencesTo(ctx, &schema.ReferencesToRequest{ReferencedKey: referencedKey})
+}
+
+// verifyRoot verifies the transaction used as root by the server is consistent with the local state,
+// returning the state it corresponds to
+func (c *immuClient) verifyRoot(state *schema.ImmutableState, rootTx *schema.VerifiableTx) (*schema.ImmutableState, error) {
+	if rootTx == nil || rootTx.DualProof == nil || rootTx.DualProof.SourceTxMetadata == nil || rootTx.DualProof.TargetTxMetadata == nil {
 		return nil, store.ErrCorruptedData
 	}
 
+	dualProof := schema.DualProofFrom(rootTx.DualProof)
+	rootID := dualProof.TargetTxMetadata.ID
+	rootAlh := dualProof.TargetTxMetadata.Alh()
+
 	if state.TxId > 0 {
-		verifies = store.VerifyDualProof(
+		verifies := store.VerifyDualProof(
 			dualProof,
-			sourceID,
-			targetID,
-			sourceAlh,
-			targetAlh,
+			state.TxId,
+			rootID,
+			schema.DigestFrom(state.TxHash),
+			rootAlh,
 		)
 		if !verifies {
 			return nil, store.ErrCorruptedData
 		}
 	}
 
-	newState := &schema.ImmutableState{
+	rootState := &schema.ImmutableState{
 		Db:        c.currentDatabase(),
-		TxId:      targetID,
-		TxHash:    targetAlh[:],
-		Signature: vEntry.VerifiableTx.Signature,
+		TxId:      rootID,
+		TxHash:    rootAlh[:],
+		Signature: rootTx.Signature,
 	}
 
 	if c.serverSigningPubKey != nil {
-		ok, err := newState.CheckSignature(c.serverSigningPubKey)
+		ok, err := rootState.CheckSignature(c.serverSigningPubKey)
 		if err != nil {
 			return nil, err
 		}
@@ -658,54 +1295,60 @@ func (c *immuClient) verifiedGet(ctx context.Context, kReq *schema.KeyRequest) (
 		}
 	}
 
-	err = c.StateService.SetState(c.Options.CurrentDatabase, newState)
-	if err != nil {
-		return nil, err
+	return rootState, nil
+}
+
+// verifyAgainstRoot verifies the kv is included in the source transaction of the dual proof, which must be consistent with the root
+func verifyAgainstRoot(inclusionProof *schema.InclusionProof, dualProof *schema.DualProof, kv *store.KV, rootID uint64, rootAlh [sha256.Size]byte) bool {
+	if inclusionProof == nil || dualProof == nil || dualProof.SourceTxMetadata == nil || dualProof.TargetTxMetadata == nil {
+		return false
 	}
 
-	return vEntry.Entry, nil
+	dp := schema.DualProofFrom(dualProof)
+
+	if !store.VerifyInclusion(schema.InclusionProofFrom(inclusionProof), kv, dp.SourceTxMetadata.Eh) {
+		return false
+	}
+
+	return store.VerifyDualProof(dp, dp.SourceTxMetadata.ID, rootID, dp.SourceTxMetadata.Alh(), rootAlh)
 }
 
-// GetSince ...
-func (c *immuClient) GetSince(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error) {
+// ZCard returns the number of elements in a sorted set
+func (c *immuClient) ZCard(ctx context.Context, set []byte) (*schema.EntryCount, error) {
 	if !c.IsConnected() {
 		return nil, errors.FromError(ErrNotConnected)
 	}
 
-	start := time.Now()
-	defer c.Logger.Debugf("get finished in %s", time.Since(start))
-
-	return c.ServiceClient.Get(ctx, &schema.KeyRequest{Key: key, SinceTx: tx})
+	return c.ServiceClient.ZCard(ctx, &schema.ZCardRequest{Set: set})
 }
 
-// GetAt ...
-func (c *immuClient) GetAt(ctx context.Context, key []byte, tx uint64) (*schema.Entry, error) {
+// ZCount returns the number of elements in a sorted set whose score is within the bounds, nil bounds are not applied
+func (c *immuClient) ZCount(ctx context.Context, set []byte, minScore, maxScore *schema.Score) (*schema.EntryCount, error) {
 	if !c.IsConnected() {
 		return nil, errors.FromError(ErrNotConnected)
 	}
 
-	start := time.Now()
-	defer c.Logger.Debugf("get finished in %s", time.Since(start))
-
-	return c.ServiceClient.Get(ctx, &schema.KeyRequest{Key: key, AtTx: tx})
+	return c.ServiceClient.ZCount(ctx, &schema.ZCountRequest{Set: set, MinScore: minScore, MaxScore: maxScore})
 }
 
-// Scan ...
-func (c *immuClient) Scan(ctx context.Context, req *schema.ScanRequest) (*schema.Entries, error) {
+// OpenSnapshot opens a named snapshot of the database at the given transaction, or the last committed one when it's zero.
+// Get and Scan requests referring to it see a consistent view of the database until it's released
+func (c *immuClient) OpenSnapshot(ctx context.Context, name string, atTx uint64) (*schema.OpenSnapshotResponse, error) {
 	if !c.IsConnected() {
 		return nil, errors.FromError(ErrNotConnected)
 	}
 
-	return c.ServiceClient.Scan(ctx, req)
+	return c.ServiceClient.OpenSnapshot(ctx, &schema.OpenSnapshotRequest{Name: name, AtTx: atTx})
 }
 
-// ZScan ...
-func (c *immuClient) ZScan(ctx context.Context, req *schema.ZScanRequest) (*schema.ZEntries, error) {
+// ReleaseSnapshot releases a named snapshot
+func (c *immuClient) ReleaseSnapshot(ctx context.Context, name string) error {
 	if !c.IsConnected() {
-		return nil, errors.FromError(ErrNotConnected)
+		return errors.FromError(ErrNotConnected)
 	}
 
-	return c.ServiceClient.ZScan(ctx, req)
+	_, err := c.ServiceClient.ReleaseSnapshot(ctx, &schema.ReleaseSnapshotRequest{Name: name})
+	return err
 }
 
 // Count ...
@@ -726,6 +1369,20 @@ func (c *immuClient) CountAll(ctx context.Context) (*schema.EntryCount, error) {
 
 // Set ...
 func (c *immuClient) Set(ctx context.Context, key []byte, value []byte) (*schema.TxMetadata, error) {
+	return c.set(ctx, &schema.KeyValue{Key: key, Value: value}, false)
+}
+
+// SetWithAttributes sets a value along with user attributes, which are returned with the entry and covered by its proofs
+func (c *immuClient) SetWithAttributes(ctx context.Context, key []byte, value []byte, attributes map[string]string) (*schema.TxMetadata, error) {
+	return c.set(ctx, &schema.KeyValue{Key: key, Value: value, Attributes: attributes}, false)
+}
+
+// SetNX sets a value only if the key has never been set before
+func (c *immuClient) SetNX(ctx context.Context, key []byte, value []byte) (*schema.TxMetadata, error) {
+	return c.set(ctx, &schema.KeyValue{Key: key, Value: value}, true)
+}
+
+func (c *immuClient) set(ctx context.Context, kv *schema.KeyValue, ifNotExists bool) (*schema.TxMetadata, error) {
 	if !c.IsConnected() {
 		return nil, errors.FromError(ErrNotConnected)
 	}
@@ -733,7 +1390,7 @@ func (c *immuClient) Set(ctx context.Context, key []byte, value []byte) (*schema
 	start := time.Now()
 	defer c.Logger.Debugf("set finished in %s", time.Since(start))
 
-	txmd, err := c.ServiceClient.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: key, Value: value}}})
+	txmd, err := c.ServiceClient.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{kv}, IfNotExists: ifNotExists})
 	if err != nil {
 		return nil, err
 	}
@@ -745,8 +1402,145 @@ func (c *immuClient) Set(ctx context.Context, key []byte, value []byte) (*schema
 	return txmd, nil
 }
 
+// SetConditional sets a value only if the key currently holds the expected value or tx
+func (c *immuClient) SetConditional(ctx context.Context, req *schema.SetConditionalRequest) (*schema.TxMetadata, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("setconditional finished in %s", time.Since(start))
+
+	return c.ServiceClient.SetConditional(ctx, req)
+}
+
+// Append atomically appends the value to the current one of the key, creating it when it doesn't exist
+func (c *immuClient) Append(ctx context.Context, key []byte, value []byte) (*schema.AppendResponse, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("append finished in %s", time.Since(start))
+
+	return c.ServiceClient.Append(ctx, &schema.AppendRequest{Key: key, Value: value})
+}
+
+// ExpirableSet sets a value which is no longer visible, although kept in the history, once expiresAt is reached
+func (c *immuClient) ExpirableSet(ctx context.Context, key []byte, value []byte, expiresAt time.Time) (*schema.TxMetadata, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("expirableset finished in %s", time.Since(start))
+
+	return c.ServiceClient.ExpirableSet(ctx, &schema.ExpirableSetRequest{
+		KVs:       []*schema.KeyValue{{Key: key, Value: value}},
+		ExpiresAt: expiresAt.Unix(),
+	})
+}
+
+// Delete logically deletes the keys, their previous revisions remain available in the history
+func (c *immuClient) Delete(ctx context.Context, req *schema.DeleteKeysRequest) (*schema.TxMetadata, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("delete finished in %s", time.Since(start))
+
+	return c.ServiceClient.Delete(ctx, req)
+}
+
+// DeletePrefix logically deletes all the keys under the prefix in a single transaction
+func (c *immuClient) DeletePrefix(ctx context.Context, req *schema.DeletePrefixRequest) (*schema.TxMetadata, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("delete prefix finished in %s", time.Since(start))
+
+	return c.ServiceClient.DeletePrefix(ctx, req)
+}
+
+// BeginTx starts an interactive transaction and returns its id
+func (c *immuClient) BeginTx(ctx context.Context) (string, error) {
+	if !c.IsConnected() {
+		return "", errors.FromError(ErrNotConnected)
+	}
+
+	res, err := c.ServiceClient.BeginTx(ctx, &empty.Empty{})
+	if err != nil {
+		return "", err
+	}
+
+	return res.TransactionId, nil
+}
+
+// TxSet stages a key-value pair in an interactive transaction
+func (c *immuClient) TxSet(ctx context.Context, txID string, key []byte, value []byte) error {
+	if !c.IsConnected() {
+		return errors.FromError(ErrNotConnected)
+	}
+
+	_, err := c.ServiceClient.TxSet(ctx, &schema.TxSetRequest{
+		TransactionId: txID,
+		KVs:           []*schema.KeyValue{{Key: key, Value: value}},
+	})
+
+	return err
+}
+
+// TxGet reads a key as seen by an interactive transaction, including its uncommitted writes
+func (c *immuClient) TxGet(ctx context.Context, txID string, key []byte) (*schema.Entry, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	return c.ServiceClient.TxGet(ctx, &schema.TxGetRequest{TransactionId: txID, Key: key})
+}
+
+// Commit atomically writes the entries of an interactive transaction
+func (c *immuClient) Commit(ctx context.Context, txID string) (*schema.TxMetadata, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("commit finished in %s", time.Since(start))
+
+	return c.ServiceClient.Commit(ctx, &schema.TransactionRequest{TransactionId: txID})
+}
+
+// Rollback discards an interactive transaction
+func (c *immuClient) Rollback(ctx context.Context, txID string) error {
+	if !c.IsConnected() {
+		return errors.FromError(ErrNotConnected)
+	}
+
+	_, err := c.ServiceClient.Rollback(ctx, &schema.TransactionRequest{TransactionId: txID})
+
+	return err
+}
+
 // VerifiedSet ...
 func (c *immuClient) VerifiedSet(ctx context.Context, key []byte, value []byte) (*schema.TxMetadata, error) {
+	return c.verifiedSet(ctx, &schema.KeyValue{Key: key, Value: value}, false)
+}
+
+// VerifiedSetWithAttributes sets a value along with user attributes, verifying the resulting tx
+func (c *immuClient) VerifiedSetWithAttributes(ctx context.Context, key []byte, value []byte, attributes map[string]string) (*schema.TxMetadata, error) {
+	return c.verifiedSet(ctx, &schema.KeyValue{Key: key, Value: value, Attributes: attributes}, false)
+}
+
+// VerifiedSetNX sets a value only if the key has never been set before, verifying the resulting tx
+func (c *immuClient) VerifiedSetNX(ctx context.Context, key []byte, value []byte) (*schema.TxMetadata, error) {
+	return c.verifiedSet(ctx, &schema.KeyValue{Key: key, Value: value}, true)
+}
+
+func (c *immuClient) verifiedSet(ctx context.Context, kv *schema.KeyValue, ifNotExists bool) (*schema.TxMetadata, error) {
 	err := c.StateService.CacheLock()
 	if err != nil {
 		return nil, err
@@ -766,7 +1560,7 @@ func (c *immuClient) VerifiedSet(ctx context.Context, key []byte, value []byte)
 	}
 
 	req := &schema.VerifiableSetRequest{
-		SetRequest:   &schema.SetRequest{KVs: []*schema.KeyValue{{Key: key, Value: value}}},
+		SetRequest:   &schema.SetRequest{KVs: []*schema.KeyValue{kv}, IfNotExists: ifNotExists},
 		ProveSinceTx: state.TxId,
 	}
 
@@ -787,12 +1581,17 @@ func (c *immuClient) VerifiedSet(ctx context.Context, key []byte, value []byte)
 
 	tx := schema.TxFrom(verifiableTx.Tx)
 
-	inclusionProof, err := tx.Proof(database.EncodeKey(key))
+	inclusionProof, err := tx.Proof(database.EncodeKey(kv.Key))
 	if err != nil {
 		return nil, err
 	}
 
-	verifies := store.VerifyInclusion(inclusionProof, database.EncodeKV(key, value), tx.Eh())
+	var md *schema.KVMetadata
+	if len(kv.Attributes) > 0 {
+		md = &schema.KVMetadata{Attributes: kv.Attributes}
+	}
+
+	verifies := store.VerifyInclusion(inclusionProof, database.EncodeKVWithMetadata(kv.Key, kv.Value, md), tx.Eh())
 	if !verifies {
 		return nil, store.ErrCorruptedData
 	}
@@ -1054,6 +1853,33 @@ func (c *immuClient) SetReferenceAt(ctx context.Context, key []byte, referencedK
 	return txmd, nil
 }
 
+// SetReferenceAtRevision sets a reference bound to a revision of the referenced key, numbered from 1 starting from the first one.
+// Negative revisions are counted backwards from the last one, so -1 binds the reference to the current value
+func (c *immuClient) SetReferenceAtRevision(ctx context.Context, key []byte, referencedKey []byte, revision int64) (*schema.TxMetadata, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("SetReferenceAtRevision finished in %s", time.Since(start))
+
+	txmd, err := c.ServiceClient.SetReference(ctx, &schema.ReferenceRequest{
+		Key:           key,
+		ReferencedKey: referencedKey,
+		AtRevision:    revision,
+		BoundRef:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if int(txmd.Nentries) != 1 {
+		return nil, store.ErrCorruptedData
+	}
+
+	return txmd, nil
+}
+
 // VerifiedSetReference ...
 func (c *immuClient) VerifiedSetReference(ctx context.Context, key []byte, referencedKey []byte) (*schema.TxMetadata, error) {
 	return c.VerifiedSetReferenceAt(ctx, key, referencedKey, 0)
@@ -1197,6 +2023,28 @@ func (c *immuClient) ZAddAt(ctx context.Context, set []byte, score float64, key
 	return txmd, nil
 }
 
+// ZRem removes from a sorted set the element added with the same score and key
+func (c *immuClient) ZRem(ctx context.Context, set []byte, score float64, key []byte) (*schema.TxMetadata, error) {
+	return c.ZRemAt(ctx, set, score, key, 0)
+}
+
+// ZRemAt removes from a sorted set the element added with the same score and key bound to the transaction atTx
+func (c *immuClient) ZRemAt(ctx context.Context, set []byte, score float64, key []byte, atTx uint64) (*schema.TxMetadata, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	start := time.Now()
+	defer c.Logger.Debugf("zrem finished in %s", time.Since(start))
+
+	return c.ServiceClient.ZRem(ctx, &schema.ZRemRequest{
+		Set:   set,
+		Score: score,
+		Key:   key,
+		AtTx:  atTx,
+	})
+}
+
 // ZAdd ...
 func (c *immuClient) VerifiedZAdd(ctx context.Context, set []byte, score float64, key []byte) (*schema.TxMetadata, error) {
 	return c.VerifiedZAddAt(ctx, set, score, key, 0)
@@ -1395,6 +2243,112 @@ func (c *immuClient) UpdateDatabase(ctx context.Context, settings *schema.Databa
 	return err
 }
 
+// UpdateDatabaseSettings changes only the provided settings of the database and returns the effective ones
+func (c *immuClient) UpdateDatabaseSettings(ctx context.Context, req *schema.UpdateDatabaseSettingsRequest) (*schema.UpdateDatabaseSettingsResponse, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	res, err := c.ServiceClient.UpdateDatabaseSettings(ctx, req)
+
+	c.Logger.Debugf("UpdateDatabaseSettings finished in %s", time.Since(start))
+
+	return res, err
+}
+
+// UpdateCorruptionChecker changes the interval and IO rate limit of the server corruption checker and returns the effective ones
+func (c *immuClient) UpdateCorruptionChecker(ctx context.Context, req *schema.UpdateCorruptionCheckerRequest) (*schema.CorruptionCheckerSettings, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	res, err := c.ServiceClient.UpdateCorruptionChecker(ctx, req)
+
+	c.Logger.Debugf("UpdateCorruptionChecker finished in %s", time.Since(start))
+
+	return res, err
+}
+
+// CorruptionCheckStatus returns the last completed and the running integrity scans of the databases on the server
+func (c *immuClient) CorruptionCheckStatus(ctx context.Context, req *schema.CorruptionCheckStatusRequest) (*schema.CorruptionCheckStatusResponse, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	res, err := c.ServiceClient.CorruptionCheckStatus(ctx, req)
+
+	c.Logger.Debugf("CorruptionCheckStatus finished in %s", time.Since(start))
+
+	return res, err
+}
+
+// Scrub verifies the database and recovers its damaged values out of the source in the request, or out of
+// the source of the database when it is a replica
+func (c *immuClient) Scrub(ctx context.Context, req *schema.ScrubRequest) (*schema.ScrubResponse, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	res, err := c.ServiceClient.Scrub(ctx, req)
+
+	c.Logger.Debugf("Scrub finished in %s", time.Since(start))
+
+	return res, err
+}
+
+// AcknowledgeCorruption lifts the quarantine of a database where corruption was detected, so it accepts writes again
+func (c *immuClient) AcknowledgeCorruption(ctx context.Context, req *schema.AcknowledgeCorruptionRequest) error {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+
+	_, err := c.ServiceClient.AcknowledgeCorruption(ctx, req)
+
+	c.Logger.Debugf("AcknowledgeCorruption finished in %s", time.Since(start))
+
+	return err
+}
+
+// DeleteDatabase unloads the database and removes or archives its data
+func (c *immuClient) DeleteDatabase(ctx context.Context, req *schema.DeleteDatabaseRequest) error {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+
+	_, err := c.ServiceClient.DeleteDatabase(ctx, req)
+
+	c.Logger.Debugf("DeleteDatabase finished in %s", time.Since(start))
+
+	return err
+}
+
+// CloneDatabase creates a new database holding a server-side copy of an existing one, optionally up to a given transaction
+func (c *immuClient) CloneDatabase(ctx context.Context, req *schema.CloneDatabaseRequest) (*schema.CloneDatabaseResponse, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	res, err := c.ServiceClient.CloneDatabase(ctx, req)
+
+	c.Logger.Debugf("CloneDatabase finished in %s", time.Since(start))
+
+	return res, err
+}
+
 // DEPREACATED: use CompactIndex
 func (c *immuClient) CleanIndex(ctx context.Context, req *empty.Empty) error {
 	return c.CompactIndex(ctx, req)
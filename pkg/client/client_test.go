@@ -16,9 +16,12 @@ limitations under the License.
 package client
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"path"
@@ -35,12 +38,15 @@ import (
 
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/client/cache"
 	"github.com/codenotary/immudb/pkg/logger"
 	"github.com/codenotary/immudb/pkg/server"
 	"github.com/stretchr/testify/require"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -472,6 +478,1400 @@ func TestReplica(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestImmuClient_SetNX(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.SetNX(ctx, []byte(`order-1`), []byte(`first`))
+	require.NoError(t, err)
+
+	_, err = client.SetNX(ctx, []byte(`order-1`), []byte(`second`))
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.VerifiedSetNX(ctx, []byte(`order-1`), []byte(`second`))
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.VerifiedSetNX(ctx, []byte(`order-2`), []byte(`first`))
+	require.NoError(t, err)
+
+	entry, err := client.Get(ctx, []byte(`order-1`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`first`), entry.Value)
+}
+
+func TestImmuClient_Delete(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	txmd, err := client.VerifiedSet(ctx, []byte(`session-1`), []byte(`active`))
+	require.NoError(t, err)
+
+	_, err = client.Delete(ctx, &schema.DeleteKeysRequest{Keys: [][]byte{[]byte(`session-1`)}})
+	require.NoError(t, err)
+
+	_, err = client.Get(ctx, []byte(`session-1`))
+	require.Error(t, err)
+
+	entry, err := client.VerifiedGetAt(ctx, []byte(`session-1`), txmd.Id)
+	require.NoError(t, err)
+	require.Equal(t, []byte(`active`), entry.Value)
+
+	history, err := client.History(ctx, &schema.HistoryRequest{Key: []byte(`session-1`)})
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 2)
+	require.True(t, history.Entries[1].Metadata.GetDeleted())
+}
+
+func TestImmuClient_ExpirableSet(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, err = client.ExpirableSet(ctx, []byte(`cache-1`), []byte(`value`), expiresAt)
+	require.NoError(t, err)
+
+	_, err = client.ExpirableSet(ctx, []byte(`cache-2`), []byte(`value`), time.Now().Add(-time.Second))
+	require.NoError(t, err)
+
+	entry, err := client.VerifiedGet(ctx, []byte(`cache-1`))
+	require.NoError(t, err)
+	require.Equal(t, expiresAt.Unix(), entry.Metadata.GetExpiresAt())
+
+	_, err = client.Get(ctx, []byte(`cache-2`))
+	require.Error(t, err)
+}
+
+func TestImmuClient_InteractiveTx(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.Set(ctx, []byte(`stock`), []byte(`10`))
+	require.NoError(t, err)
+
+	txID, err := client.BeginTx(ctx)
+	require.NoError(t, err)
+
+	entry, err := client.TxGet(ctx, txID, []byte(`stock`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`10`), entry.Value)
+
+	err = client.TxSet(ctx, txID, []byte(`stock`), []byte(`9`))
+	require.NoError(t, err)
+
+	err = client.TxSet(ctx, txID, []byte(`order-1`), []byte(`reserved`))
+	require.NoError(t, err)
+
+	entry, err = client.TxGet(ctx, txID, []byte(`stock`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`9`), entry.Value)
+
+	txmd, err := client.Commit(ctx, txID)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), txmd.Nentries)
+
+	txID, err = client.BeginTx(ctx)
+	require.NoError(t, err)
+
+	_, err = client.TxGet(ctx, txID, []byte(`stock`))
+	require.NoError(t, err)
+
+	err = client.TxSet(ctx, txID, []byte(`stock`), []byte(`8`))
+	require.NoError(t, err)
+
+	_, err = client.Set(ctx, []byte(`stock`), []byte(`0`))
+	require.NoError(t, err)
+
+	_, err = client.Commit(ctx, txID)
+	require.Equal(t, codes.Aborted, status.Code(err))
+
+	txID, err = client.BeginTx(ctx)
+	require.NoError(t, err)
+
+	err = client.Rollback(ctx, txID)
+	require.NoError(t, err)
+
+	_, err = client.Commit(ctx, txID)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestImmuClient_Append(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.Append(ctx, []byte(`events`), []byte(`created;`))
+	require.NoError(t, err)
+
+	res, err := client.Append(ctx, []byte(`events`), []byte(`paid;`))
+	require.NoError(t, err)
+	require.Equal(t, uint64(13), res.Length)
+
+	entry, err := client.VerifiedGet(ctx, []byte(`events`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`created;paid;`), entry.Value)
+	require.Equal(t, res.Tx.Id, entry.Tx)
+}
+
+func TestImmuClient_Subscribe(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.Subscribe(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	txMd, err := client.Set(ctx, []byte(`orders/1`), []byte(`v1`))
+	require.NoError(t, err)
+
+	_, err = client.Set(ctx, []byte(`users/1`), []byte(`v1`))
+	require.NoError(t, err)
+
+	_, err = client.Set(ctx, []byte(`orders/2`), []byte(`v2`))
+	require.NoError(t, err)
+
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sub, err := client.Subscribe(subCtx, &schema.SubscribeRequest{Prefix: []byte(`orders/`), SinceTx: txMd.Id, WithProof: true})
+	require.NoError(t, err)
+
+	for _, k := range []string{`orders/1`, `orders/2`} {
+		e, err := sub.Recv()
+		require.NoError(t, err)
+		require.Equal(t, []byte(k), e.Entry.Key)
+		require.NotNil(t, e.InclusionProof)
+		require.NotNil(t, e.VerifiableTx)
+	}
+
+	cancel()
+
+	_, err = sub.Recv()
+	require.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestImmuClient_StreamTxs(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.StreamTxs(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	txMd, err := client.Set(ctx, []byte(`key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	_, err = client.Set(ctx, []byte(`key2`), []byte(`val2`))
+	require.NoError(t, err)
+
+	stream, err := client.StreamTxs(ctx, &schema.StreamTxsRequest{SinceTx: txMd.Id, NoFollow: true})
+	require.NoError(t, err)
+
+	txe, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, txMd.Id, txe.Tx.Id)
+	require.Equal(t, []byte(`key1`), txe.Entries[0].Key)
+
+	txe, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`key2`), txe.Entries[0].Key)
+	require.Equal(t, txMd.Id+2, txe.Cursor)
+
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestImmuClient_DeletePrefix(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.Set(ctx, []byte(`tenant1/key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	_, err = client.Set(ctx, []byte(`tenant2/key1`), []byte(`val2`))
+	require.NoError(t, err)
+
+	_, err = client.DeletePrefix(ctx, &schema.DeletePrefixRequest{Prefix: []byte(`tenant1/`)})
+	require.NoError(t, err)
+
+	_, err = client.Get(ctx, []byte(`tenant1/key1`))
+	require.Error(t, err)
+
+	list, err := client.Scan(ctx, &schema.ScanRequest{Prefix: []byte(`tenant`)})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 1)
+	require.Equal(t, []byte(`tenant2/key1`), list.Entries[0].Key)
+}
+
+func TestImmuClient_ZRem(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	txmd, err := client.Set(ctx, []byte(`key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	_, err = client.ZAdd(ctx, []byte(`set1`), 1, []byte(`key1`))
+	require.NoError(t, err)
+
+	_, err = client.ZAddAt(ctx, []byte(`set1`), 2, []byte(`key1`), txmd.Id)
+	require.NoError(t, err)
+
+	_, err = client.ZRem(ctx, []byte(`set1`), 1, []byte(`key1`))
+	require.NoError(t, err)
+
+	_, err = client.ZRem(ctx, []byte(`set1`), 1, []byte(`key1`))
+	require.Error(t, err)
+
+	_, err = client.ZRemAt(ctx, []byte(`set1`), 2, []byte(`key1`), txmd.Id)
+	require.NoError(t, err)
+
+	list, err := client.ZScan(ctx, &schema.ZScanRequest{Set: []byte(`set1`)})
+	require.NoError(t, err)
+	require.Empty(t, list.Entries)
+}
+
+func TestImmuClient_ZCardAndZCount(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.Set(ctx, []byte(`key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	for i := 1; i <= 5; i++ {
+		_, err = client.ZAdd(ctx, []byte(`set1`), float64(i), []byte(`key1`))
+		require.NoError(t, err)
+	}
+
+	card, err := client.ZCard(ctx, []byte(`set1`))
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), card.Count)
+
+	count, err := client.ZCount(ctx, []byte(`set1`), &schema.Score{Score: 2, Exclusive: true}, &schema.Score{Score: 4})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count.Count)
+}
+
+func TestImmuClient_VerifiedZScan(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	txmd, err := client.Set(ctx, []byte(`key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	_, err = client.Set(ctx, []byte(`key2`), []byte(`val2`))
+	require.NoError(t, err)
+
+	_, err = client.ZAddAt(ctx, []byte(`set1`), 1, []byte(`key1`), txmd.Id)
+	require.NoError(t, err)
+
+	_, err = client.ZAdd(ctx, []byte(`set1`), 2, []byte(`key2`))
+	require.NoError(t, err)
+
+	list, err := client.VerifiedZScan(ctx, &schema.ZScanRequest{Set: []byte(`set1`)})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 2)
+	require.Equal(t, []byte(`val1`), list.Entries[0].Entry.Value)
+	require.Equal(t, []byte(`val2`), list.Entries[1].Entry.Value)
+
+	txmd, err = client.Set(ctx, []byte(`key1`), []byte(`val3`))
+	require.NoError(t, err)
+
+	// verified against the state kept by the previous call
+	list, err = client.VerifiedZScan(ctx, &schema.ZScanRequest{Set: []byte(`set1`), MinScore: &schema.Score{Score: 2}})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 1)
+	require.Equal(t, []byte(`val2`), list.Entries[0].Entry.Value)
+
+	state, err := client.CurrentState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, txmd.Id, state.TxId)
+}
+
+func TestImmuClient_ResolveReferenceHistory(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.ResolveReferenceHistory(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	txmd, err := client.Set(ctx, []byte(`key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	_, err = client.Set(ctx, []byte(`key2`), []byte(`val2`))
+	require.NoError(t, err)
+
+	_, err = client.SetReferenceAt(ctx, []byte(`tag`), []byte(`key1`), txmd.Id)
+	require.NoError(t, err)
+
+	// the local state is taken as the trusted one from now on
+	_, err = client.VerifiedGet(ctx, []byte(`tag`))
+	require.NoError(t, err)
+
+	_, err = client.SetReference(ctx, []byte(`tag`), []byte(`key2`))
+	require.NoError(t, err)
+
+	entry, err := client.VerifiedGet(ctx, []byte(`tag`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`val2`), entry.Value)
+
+	revisions, err := client.ResolveReferenceHistory(ctx, &schema.ReferenceHistoryRequest{Key: []byte(`tag`)})
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, []byte(`key1`), revisions[0].ReferencedKey)
+	require.Equal(t, txmd.Id, revisions[0].AtTx)
+	require.Equal(t, []byte(`key2`), revisions[1].ReferencedKey)
+	require.Equal(t, uint64(0), revisions[1].AtTx)
+}
+
+func TestImmuClient_ReferencesTo(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.Set(ctx, []byte(`key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	_, err = client.SetReference(ctx, []byte(`tag1`), []byte(`key1`))
+	require.NoError(t, err)
+
+	_, err = client.SetReference(ctx, []byte(`tag2`), []byte(`key1`))
+	require.NoError(t, err)
+
+	refs, err := client.ReferencesTo(ctx, []byte(`key1`))
+	require.NoError(t, err)
+	require.Len(t, refs.References, 2)
+	require.Equal(t, []byte(`tag1`), refs.References[0].Key)
+	require.Equal(t, []byte(`tag2`), refs.References[1].Key)
+
+	_, err = client.ReferencesTo(ctx, nil)
+	require.Error(t, err)
+
+	client.Disconnect()
+
+	_, err = client.ReferencesTo(ctx, []byte(`key1`))
+	require.EqualError(t, err, ErrNotConnected.Error())
+}
+
+func TestImmuClient_SetReferenceAtRevision(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	txmd, err := client.Set(ctx, []byte(`release`), []byte(`build1`))
+	require.NoError(t, err)
+
+	_, err = client.Set(ctx, []byte(`release`), []byte(`build2`))
+	require.NoError(t, err)
+
+	_, err = client.SetReferenceAtRevision(ctx, []byte(`v1`), []byte(`release`), 1)
+	require.NoError(t, err)
+
+	_, err = client.SetReferenceAtRevision(ctx, []byte(`v3`), []byte(`release`), 3)
+	require.Error(t, err)
+
+	entry, err := client.VerifiedGet(ctx, []byte(`v1`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`build1`), entry.Value)
+	require.Equal(t, txmd.Id, entry.Tx)
+
+	client.Disconnect()
+
+	_, err = client.SetReferenceAtRevision(ctx, []byte(`v1`), []byte(`release`), 1)
+	require.EqualError(t, err, ErrNotConnected.Error())
+}
+
+func TestImmuClient_WithStateCache(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+
+	bs.Start()
+	defer bs.Stop()
+
+	dir, err := ioutil.TempDir("", "client_state_cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	stateCache := cache.NewProcessCache()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().
+		WithDir(dir).
+		WithStateCache(stateCache).
+		WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).
+		WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	txmd, err := client.VerifiedSet(ctx, []byte(`key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	_, err = client.VerifiedGet(ctx, []byte(`key1`))
+	require.NoError(t, err)
+
+	// the trusted state is kept in the provided cache, no state file is written
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+
+	// the test server doesn't publish its uuid
+	serverUUID := ""
+
+	err = stateCache.Lock(serverUUID)
+	require.NoError(t, err)
+
+	st, err := stateCache.Get(serverUUID, client.GetOptions().CurrentDatabase)
+	require.NoError(t, err)
+	require.Equal(t, txmd.Id, st.TxId)
+
+	err = stateCache.Unlock()
+	require.NoError(t, err)
+}
+
+func TestImmuClient_MultiInclusion(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.MultiInclusion(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	txmd, err := client.Set(ctx, []byte(`key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	_, err = client.VerifiedSet(ctx, []byte(`key1`), []byte(`val2`))
+	require.NoError(t, err)
+
+	_, err = client.SetReference(ctx, []byte(`ref`), []byte(`key1`))
+	require.NoError(t, err)
+
+	entries, err := client.MultiInclusion(ctx, &schema.MultiInclusionRequest{
+		Keys: []*schema.InclusionKey{
+			{Key: []byte(`key1`)},
+			{Key: []byte(`key1`), AtTx: txmd.Id},
+			{Key: []byte(`ref`)},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, []byte(`val2`), entries[0].Value)
+	require.Equal(t, []byte(`val1`), entries[1].Value)
+	require.Equal(t, []byte(`val2`), entries[2].Value)
+	require.Equal(t, []byte(`ref`), entries[2].ReferencedBy.Key)
+
+	_, err = client.MultiInclusion(ctx, &schema.MultiInclusionRequest{Keys: []*schema.InclusionKey{{Key: []byte(`unknown`)}}})
+	require.Error(t, err)
+
+	client.Disconnect()
+
+	_, err = client.MultiInclusion(ctx, &schema.MultiInclusionRequest{Keys: []*schema.InclusionKey{{Key: []byte(`key1`)}}})
+	require.Error(t, err)
+}
+
+func TestImmuClient_TimestampTokens(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.Set(ctx, []byte(`key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	tokens, err := client.TimestampTokens(ctx, &schema.TimestampTokensRequest{})
+	require.NoError(t, err)
+	require.Empty(t, tokens.Tokens)
+
+	_, err = client.TimestampTokens(ctx, nil)
+	require.Error(t, err)
+
+	client.Disconnect()
+
+	_, err = client.TimestampTokens(ctx, &schema.TimestampTokensRequest{})
+	require.EqualError(t, err, ErrNotConnected.Error())
+}
+
+func TestImmuClient_AnchorReceipts(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	receipts, err := client.AnchorReceipts(ctx, &schema.AnchorReceiptsRequest{})
+	require.NoError(t, err)
+	require.Empty(t, receipts.Receipts)
+
+	client.Disconnect()
+
+	_, err = client.AnchorReceipts(ctx, &schema.AnchorReceiptsRequest{})
+	require.EqualError(t, err, ErrNotConnected.Error())
+}
+
+func TestImmuClient_VerifiedGetAtCurrentRoot(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.VerifiedGetAtCurrentRoot(ctx, []byte(`key1`), 0)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	txmd1, err := client.Set(ctx, []byte(`key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	_, err = client.Set(ctx, []byte(`key1`), []byte(`val2`))
+	require.NoError(t, err)
+
+	txmd3, err := client.Set(ctx, []byte(`key2`), []byte(`val3`))
+	require.NoError(t, err)
+
+	e, err := client.VerifiedGetAtCurrentRoot(ctx, []byte(`key1`), txmd1.Id)
+	require.NoError(t, err)
+	require.Equal(t, []byte(`val1`), e.Value)
+	require.Equal(t, txmd1.Id, e.Tx)
+
+	// the historical revision is proven against the last committed transaction
+	stateService := client.(*immuClient).StateService
+
+	err = stateService.CacheLock()
+	require.NoError(t, err)
+
+	state, err := stateService.GetState(ctx, "defaultdb")
+	require.NoError(t, err)
+	require.Equal(t, txmd3.Id, state.TxId)
+
+	err = stateService.CacheUnlock()
+	require.NoError(t, err)
+
+	e, err = client.VerifiedGetAt(ctx, []byte(`key1`), txmd1.Id)
+	require.NoError(t, err)
+	require.Equal(t, []byte(`val1`), e.Value)
+
+	_, err = client.VerifiedGetAtCurrentRoot(ctx, []byte(`key2`), txmd1.Id)
+	require.Error(t, err)
+
+	client.Disconnect()
+
+	_, err = client.VerifiedGetAtCurrentRoot(ctx, []byte(`key1`), txmd1.Id)
+	require.EqualError(t, err, ErrNotConnected.Error())
+}
+
+func TestImmuClient_StateHistory(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true).WithSigningKey("./../../test/signer/ec1.key")
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	opts := DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts)
+	client, err := NewImmuClient(opts.WithServerSigningPubKey("./../../test/signer/ec1.pub"))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.Set(ctx, []byte(`key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	_, err = client.Set(ctx, []byte(`key2`), []byte(`val2`))
+	require.NoError(t, err)
+
+	state, err := client.CurrentState(ctx)
+	require.NoError(t, err)
+
+	stateList, err := client.StateHistory(ctx, &schema.StateHistoryRequest{InitialTx: state.TxId, Limit: 2, Desc: true})
+	require.NoError(t, err)
+	require.Equal(t, "defaultdb", stateList.Db)
+	require.Len(t, stateList.States, 2)
+	require.Equal(t, state.TxId, stateList.States[0].TxId)
+	require.Equal(t, state.TxHash, stateList.States[0].TxHash)
+	require.NotNil(t, stateList.States[0].Signature)
+	require.Equal(t, state.TxId-1, stateList.States[1].TxId)
+
+	stateList, err = client.StateHistory(ctx, &schema.StateHistoryRequest{InitialTx: state.TxId + 1})
+	require.NoError(t, err)
+	require.Empty(t, stateList.States)
+
+	client.Disconnect()
+
+	_, err = client.StateHistory(ctx, &schema.StateHistoryRequest{InitialTx: 1})
+	require.EqualError(t, err, ErrNotConnected.Error())
+}
+
+func TestImmuClient_GetAsOf(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	txMd1, err := client.Set(ctx, []byte(`key1`), []byte(`val1`))
+	require.NoError(t, err)
+
+	txMd2, err := client.Set(ctx, []byte(`key2`), []byte(`val2`))
+	require.NoError(t, err)
+
+	_, err = client.Set(ctx, []byte(`key1`), []byte(`val3`))
+	require.NoError(t, err)
+
+	entry, err := client.GetAsOfTx(ctx, []byte(`key1`), txMd2.Id)
+	require.NoError(t, err)
+	require.Equal(t, []byte(`val1`), entry.Value)
+
+	entry, err = client.VerifiedGetAsOfTx(ctx, []byte(`key1`), txMd2.Id)
+	require.NoError(t, err)
+	require.Equal(t, []byte(`val1`), entry.Value)
+	require.Equal(t, txMd1.Id, entry.Tx)
+
+	entry, err = client.GetAsOfTime(ctx, []byte(`key1`), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`val3`), entry.Value)
+
+	entry, err = client.VerifiedGetAsOfTime(ctx, []byte(`key1`), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`val3`), entry.Value)
+}
+
+func TestImmuClient_SetWithAttributes(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	attrs := map[string]string{"author": "alice", "content-type": "text/plain"}
+
+	_, err = client.SetWithAttributes(ctx, []byte(`key1`), []byte(`val1`), attrs)
+	require.NoError(t, err)
+
+	entry, err := client.VerifiedGet(ctx, []byte(`key1`))
+	require.NoError(t, err)
+	require.Equal(t, attrs, entry.Metadata.Attributes)
+
+	_, err = client.VerifiedSetWithAttributes(ctx, []byte(`key2`), []byte(`val2`), attrs)
+	require.NoError(t, err)
+
+	entry, err = client.Get(ctx, []byte(`key2`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`val2`), entry.Value)
+	require.Equal(t, attrs, entry.Metadata.Attributes)
+}
+
+func TestImmuClient_UpdateDatabaseSettings(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	err = client.CreateDatabase(ctx, &schema.DatabaseSettings{DatabaseName: "db1"})
+	require.NoError(t, err)
+
+	res, err := client.UpdateDatabaseSettings(ctx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "db1",
+		Settings: &schema.DatabaseNullableSettings{
+			TxLogCacheSize: &schema.NullableUint32{Value: 100},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(100), res.Settings.TxLogCacheSize.Value)
+
+	client.Disconnect()
+
+	_, err = client.UpdateDatabaseSettings(ctx, &schema.UpdateDatabaseSettingsRequest{})
+	require.Equal(t, ErrNotConnected, err)
+}
+
+func TestImmuClient_UpdateCorruptionChecker(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	res, err := client.UpdateCorruptionChecker(ctx, &schema.UpdateCorruptionCheckerRequest{
+		IoRateLimit: &schema.NullableUint64{Value: 1024},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1024), res.IoRateLimit)
+
+	client.Disconnect()
+
+	_, err = client.UpdateCorruptionChecker(ctx, &schema.UpdateCorruptionCheckerRequest{})
+	require.Equal(t, ErrNotConnected, err)
+}
+
+func TestImmuClient_CorruptionCheckStatus(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	res, err := client.CorruptionCheckStatus(ctx, &schema.CorruptionCheckStatusRequest{Database: "defaultdb"})
+	require.NoError(t, err)
+	require.Len(t, res.Databases, 1)
+	require.Equal(t, "defaultdb", res.Databases[0].Database)
+
+	client.Disconnect()
+
+	_, err = client.CorruptionCheckStatus(ctx, &schema.CorruptionCheckStatusRequest{})
+	require.Equal(t, ErrNotConnected, err)
+}
+
+func TestImmuClient_Scrub(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	err = client.CreateDatabase(ctx, &schema.DatabaseSettings{DatabaseName: "db1"})
+	require.NoError(t, err)
+
+	_, err = client.Scrub(ctx, &schema.ScrubRequest{Database: "defaultdb"})
+	require.Error(t, err)
+
+	res, err := client.Scrub(ctx, &schema.ScrubRequest{Database: "defaultdb", SourceDatabase: "db1"})
+	require.NoError(t, err)
+	require.Equal(t, "defaultdb", res.Database)
+	require.Empty(t, res.UnrepairedTxs)
+
+	client.Disconnect()
+
+	_, err = client.Scrub(ctx, &schema.ScrubRequest{})
+	require.Equal(t, ErrNotConnected, err)
+}
+
+func TestImmuClient_DeleteDatabase(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	err = client.CreateDatabase(ctx, &schema.DatabaseSettings{DatabaseName: "db1"})
+	require.NoError(t, err)
+
+	err = client.DeleteDatabase(ctx, &schema.DeleteDatabaseRequest{DatabaseName: "db1"})
+	require.NoError(t, err)
+
+	dbs, err := client.DatabaseList(ctx)
+	require.NoError(t, err)
+	require.Len(t, dbs.Databases, 1)
+
+	err = client.DeleteDatabase(ctx, &schema.DeleteDatabaseRequest{DatabaseName: "db1"})
+	require.Error(t, err)
+
+	client.Disconnect()
+
+	err = client.DeleteDatabase(ctx, &schema.DeleteDatabaseRequest{DatabaseName: "db1"})
+	require.Equal(t, ErrNotConnected, err)
+}
+
+func TestImmuClient_CloneDatabase(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.Set(ctx, []byte("key"), []byte("value"))
+	require.NoError(t, err)
+
+	res, err := client.CloneDatabase(ctx, &schema.CloneDatabaseRequest{SrcDatabase: server.DefaultdbName, DstDatabase: "db1"})
+	require.NoError(t, err)
+	require.Equal(t, "db1", res.DatabaseName)
+
+	dbs, err := client.DatabaseList(ctx)
+	require.NoError(t, err)
+	require.Len(t, dbs.Databases, 2)
+
+	_, err = client.CloneDatabase(ctx, &schema.CloneDatabaseRequest{SrcDatabase: server.DefaultdbName, DstDatabase: "db1"})
+	require.Error(t, err)
+
+	client.Disconnect()
+
+	_, err = client.CloneDatabase(ctx, &schema.CloneDatabaseRequest{SrcDatabase: server.DefaultdbName, DstDatabase: "db2"})
+	require.Equal(t, ErrNotConnected, err)
+}
+
+func TestImmuClient_ExportImportDatabase(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.Set(ctx, []byte("key"), []byte("value"))
+	require.NoError(t, err)
+
+	_, err = client.CloneDatabase(ctx, &schema.CloneDatabaseRequest{SrcDatabase: server.DefaultdbName, DstDatabase: "db1"})
+	require.NoError(t, err)
+
+	err = client.ExportDatabase(ctx, nil, &bytes.Buffer{})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	var archive bytes.Buffer
+
+	err = client.ExportDatabase(ctx, &schema.ExportDatabaseRequest{DatabaseName: "db1"}, &archive)
+	require.NoError(t, err)
+
+	_, err = client.ImportDatabase(ctx, bytes.NewReader(archive.Bytes()))
+	require.Error(t, err)
+
+	err = client.DeleteDatabase(ctx, &schema.DeleteDatabaseRequest{DatabaseName: "db1"})
+	require.NoError(t, err)
+
+	_, err = client.ImportDatabase(ctx, bytes.NewReader(archive.Bytes()[:archive.Len()-1]))
+	require.Error(t, err)
+
+	res, err := client.ImportDatabase(ctx, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, "db1", res.DatabaseName)
+
+	dbs, err := client.DatabaseList(ctx)
+	require.NoError(t, err)
+	require.Len(t, dbs.Databases, 2)
+
+	client.Disconnect()
+
+	err = client.ExportDatabase(ctx, &schema.ExportDatabaseRequest{DatabaseName: "db1"}, &archive)
+	require.Equal(t, ErrNotConnected, err)
+
+	_, err = client.ImportDatabase(ctx, &archive)
+	require.Equal(t, ErrNotConnected, err)
+}
+
+func TestImmuClient_Snapshots(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	txMd, err := client.Set(ctx, []byte("key"), []byte("value1"))
+	require.NoError(t, err)
+
+	snap, err := client.OpenSnapshot(ctx, "dump", 0)
+	require.NoError(t, err)
+	require.Equal(t, txMd.Id, snap.TxId)
+
+	_, err = client.Set(ctx, []byte("key"), []byte("value2"))
+	require.NoError(t, err)
+
+	entry, err := client.GetFromSnapshot(ctx, "dump", []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), entry.Value)
+
+	entries, err := client.Scan(ctx, &schema.ScanRequest{Snapshot: "dump"})
+	require.NoError(t, err)
+	require.Len(t, entries.Entries, 1)
+	require.Equal(t, []byte("value1"), entries.Entries[0].Value)
+
+	err = client.ReleaseSnapshot(ctx, "dump")
+	require.NoError(t, err)
+
+	_, err = client.GetFromSnapshot(ctx, "dump", []byte("key"))
+	require.Error(t, err)
+
+	client.Disconnect()
+
+	_, err = client.OpenSnapshot(ctx, "dump", 0)
+	require.Error(t, err)
+
+	err = client.ReleaseSnapshot(ctx, "dump")
+	require.Error(t, err)
+
+	_, err = client.GetFromSnapshot(ctx, "dump", []byte("key"))
+	require.Error(t, err)
+}
+
+func TestImmuClient_Backup(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.Set(ctx, []byte("key"), []byte("value"))
+	require.NoError(t, err)
+
+	err = client.Backup(ctx, &schema.BackupRequest{DatabaseName: server.DefaultdbName}, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	var archive bytes.Buffer
+
+	err = client.Backup(ctx, &schema.BackupRequest{DatabaseName: server.DefaultdbName}, &archive)
+	require.NoError(t, err)
+	require.NotZero(t, archive.Len())
+
+	err = client.Backup(ctx, &schema.BackupRequest{DatabaseName: server.DefaultdbName, Path: "defaultdb.bak"}, nil)
+	require.NoError(t, err)
+
+	var incArchive bytes.Buffer
+
+	err = client.Backup(ctx, &schema.BackupRequest{DatabaseName: server.DefaultdbName, SinceTx: 1}, &incArchive)
+	require.NoError(t, err)
+	require.NotZero(t, incArchive.Len())
+
+	err = client.Backup(ctx, &schema.BackupRequest{DatabaseName: server.DefaultdbName, Path: "../defaultdb.bak"}, nil)
+	require.Error(t, err)
+
+	res, err := client.Restore(ctx, &schema.RestoreRequest{DatabaseName: "restored", Paths: []string{"defaultdb.bak"}})
+	require.NoError(t, err)
+	require.Equal(t, "restored", res.DatabaseName)
+
+	_, err = client.Restore(ctx, &schema.RestoreRequest{DatabaseName: "restored", Paths: []string{"defaultdb.bak"}})
+	require.Error(t, err)
+
+	status, err := client.BackupStatus(ctx)
+	require.NoError(t, err)
+	require.Empty(t, status.Schedules)
+
+	client.Disconnect()
+
+	err = client.Backup(ctx, &schema.BackupRequest{DatabaseName: server.DefaultdbName}, &archive)
+	require.Equal(t, ErrNotConnected, err)
+
+	_, err = client.Restore(ctx, &schema.RestoreRequest{DatabaseName: "restored2", Paths: []string{"defaultdb.bak"}})
+	require.Equal(t, ErrNotConnected, err)
+
+	_, err = client.BackupStatus(ctx)
+	require.Equal(t, ErrNotConnected, err)
+}
+
 func TestDatabasesSwitching(t *testing.T) {
 	options := server.DefaultOptions().WithAuth(true)
 	bs := servertest.NewBufconnServer(options)
@@ -1511,6 +2911,42 @@ func TestImmuClient_CurrentStateVerifiedSignature(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestImmuClient_ComplianceReportVerifiedSignature(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true).WithSigningKey("./../../test/signer/ec1.key")
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	err := bs.Start()
+	require.NoError(t, err)
+	defer bs.Stop()
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(DefaultHomedirServiceMock())
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts).WithServerSigningPubKey("./../../test/signer/ec1.pub"))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.Set(ctx, []byte("key1"), []byte("value1"))
+	require.NoError(t, err)
+
+	report, err := client.ComplianceReport(ctx, &schema.ComplianceReportRequest{})
+	require.NoError(t, err)
+	require.Equal(t, DefaultDB, report.Db)
+	require.NotZero(t, report.TxCount)
+	require.Equal(t, report.TxCount, report.VerifiedTxCount)
+
+	client.Disconnect()
+
+	_, err = client.ComplianceReport(ctx, &schema.ComplianceReportRequest{})
+	require.Error(t, err)
+}
+
 func TestImmuClient_VerifiedGetAt(t *testing.T) {
 	options := server.DefaultOptions().WithAuth(true).WithSigningKey("./../../test/signer/ec1.key")
 	bs := servertest.NewBufconnServer(options)
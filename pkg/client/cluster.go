@@ -0,0 +1,57 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"google.golang.org/grpc/status"
+)
+
+// ClusterStatus returns the role of the server in the Raft group of each database and the leader of the group
+func (c *immuClient) ClusterStatus(ctx context.Context, req *schema.ClusterStatusRequest) (*schema.ClusterStatusResponse, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	res, err := c.ServiceClient.ClusterStatus(ctx, req)
+
+	c.Logger.Debugf("ClusterStatus finished in %s", time.Since(start))
+
+	return res, err
+}
+
+// LeaderOf returns the leader a write has to be redirected to when it got rejected by a node of a cluster
+// not leading the database
+func LeaderOf(err error) (*schema.ClusterLeader, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil, false
+	}
+
+	for _, d := range st.Details() {
+		if leader, ok := d.(*schema.ClusterLeader); ok {
+			return leader, true
+		}
+	}
+
+	return nil, false
+}
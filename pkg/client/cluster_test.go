@@ -0,0 +1,47 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLeaderOf(t *testing.T) {
+	_, ok := LeaderOf(nil)
+	require.False(t, ok)
+
+	_, ok = LeaderOf(errors.New("not a status"))
+	require.False(t, ok)
+
+	_, ok = LeaderOf(status.Error(codes.FailedPrecondition, "no details"))
+	require.False(t, ok)
+
+	st, err := status.New(codes.FailedPrecondition, "not the leader").
+		WithDetails(&schema.ClusterLeader{Database: "defaultdb", NodeId: "node2", Address: "10.0.0.2:3322"})
+	require.NoError(t, err)
+
+	leader, ok := LeaderOf(st.Err())
+	require.True(t, ok)
+	require.Equal(t, "node2", leader.NodeId)
+	require.Equal(t, "10.0.0.2:3322", leader.Address)
+}
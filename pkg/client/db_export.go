@@ -0,0 +1,88 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/stream"
+)
+
+// ExportDatabase writes into w the archive of a database produced by the server
+func (c *immuClient) ExportDatabase(ctx context.Context, req *schema.ExportDatabaseRequest, w io.Writer) error {
+	start := time.Now()
+
+	if req == nil || w == nil {
+		return ErrIllegalArguments
+	}
+
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+
+	exportClient, err := c.ServiceClient.ExportDatabase(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, stream.NewChunkReader(exportClient))
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Debugf("ExportDatabase finished in %s", time.Since(start))
+
+	return nil
+}
+
+// ImportDatabase sends the archive read from r to the server, which creates a new database out of it
+func (c *immuClient) ImportDatabase(ctx context.Context, r io.Reader) (*schema.ImportDatabaseResponse, error) {
+	start := time.Now()
+
+	if r == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	importClient, err := c.ServiceClient.ImportDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cw := stream.NewChunkWriter(importClient, c.Options.StreamChunkSize)
+
+	_, err = io.Copy(cw, r)
+	if err == nil {
+		err = cw.Close()
+	}
+	// io.EOF is returned when the server already closed the stream, the actual error is returned by CloseAndRecv
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	res, err := importClient.CloseAndRecv()
+
+	c.Logger.Debugf("ImportDatabase finished in %s", time.Since(start))
+
+	return res, err
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Roles of the endpoints
+const (
+	EndpointPrimary = "primary"
+	EndpointReplica = "replica"
+)
+
+// replicaRetryDelay is how long a replica found unavailable is left out of the read routing
+const replicaRetryDelay = 10 * time.Second
+
+// Endpoint is a server the client connects to. Writes are sent to the primary, reads are spread across the replicas
+// holding a replica of the databases of the primary with the same names
+type Endpoint struct {
+	Address string
+	Port    int
+	Role    string
+}
+
+// Bind concatenates address and port
+func (e *Endpoint) Bind() string {
+	return e.Address + ":" + strconv.Itoa(e.Port)
+}
+
+// replicaReadMethods are the methods served by the replicas, the value tells if the response is verified by the client
+var replicaReadMethods = map[string]bool{
+	"Get":                     false,
+	"GetAll":                  false,
+	"Scan":                    false,
+	"ZScan":                   false,
+	"History":                 false,
+	"Count":                   false,
+	"CountAll":                false,
+	"TxByID":                  false,
+	"TxScan":                  false,
+	"ZCard":                   false,
+	"ZCount":                  false,
+	"ReferencesTo":            false,
+	"ResolveReferenceHistory": false,
+	"SQLQuery":                false,
+	"ListTables":              false,
+	"DescribeTable":           false,
+	"VerifiableGet":           true,
+	"VerifiableZScan":         true,
+	"VerifiableTxByID":        true,
+	"VerifiableSQLGet":        true,
+	"MultiInclusion":          true,
+}
+
+// primaryEndpoint checks the endpoints hold exactly one primary and returns it
+func primaryEndpoint(endpoints []*Endpoint) (*Endpoint, error) {
+	var primary *Endpoint
+
+	for _, e := range endpoints {
+		if e == nil || e.Address == "" || e.Port <= 0 {
+			return nil, ErrInvalidEndpoints
+		}
+
+		switch e.Role {
+		case EndpointPrimary:
+			if primary != nil {
+				return nil, ErrInvalidEndpoints
+			}
+			primary = e
+		case EndpointReplica:
+		default:
+			return nil, ErrInvalidEndpoints
+		}
+	}
+
+	if primary == nil {
+		return nil, ErrInvalidEndpoints
+	}
+
+	return primary, nil
+}
+
+// endpointRouter sends the reads to the replicas, in turn, and any other call to the primary.
+// Each replica has its own session, opened along with the one of the primary as tokens are issued per server.
+// A replica is skipped while unavailable and a read is retried on the next replica, then on the primary,
+// when the replica is behind the state trusted by the client.
+// As the replicas hold the same transactions, verified reads are proven against the state trusted for the primary
+type endpointRouter struct {
+	primary *grpc.ClientConn
+	// verifiedReads is false when the client verifies the signature of the primary, which replicas can not provide
+	verifiedReads bool
+	logger        logger.Logger
+
+	mutex    sync.Mutex
+	replicas []*replicaEndpoint
+	next     int
+	loggedIn bool
+}
+
+type replicaEndpoint struct {
+	endpoint  *Endpoint
+	conn      *grpc.ClientConn
+	token     string
+	downUntil time.Time
+}
+
+func newEndpointRouter(primary *grpc.ClientConn, endpoints []*Endpoint, dialOptions []grpc.DialOption, verifiedReads bool, log logger.Logger) (*endpointRouter, error) {
+	r := &endpointRouter{
+		primary:       primary,
+		verifiedReads: verifiedReads,
+		logger:        log,
+	}
+
+	for _, e := range endpoints {
+		if e.Role != EndpointReplica {
+			continue
+		}
+
+		conn, err := grpc.Dial(e.Bind(), dialOptions...)
+		if err != nil {
+			r.close()
+			return nil, err
+		}
+
+		r.replicas = append(r.replicas, &replicaEndpoint{endpoint: e, conn: conn})
+	}
+
+	return r, nil
+}
+
+func (r *endpointRouter) close() {
+	for _, re := range r.replicas {
+		re.conn.Close()
+	}
+}
+
+// Invoke implements grpc.ClientConnInterface
+func (r *endpointRouter) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	name := method[strings.LastIndex(method, "/")+1:]
+
+	switch name {
+	case "Login", "UseDatabase", "Logout":
+		err := r.primary.Invoke(ctx, method, args, reply, opts...)
+		if err == nil {
+			r.updateSessions(ctx, name, method, args)
+		}
+		return err
+	}
+
+	if r.routed(name, args) {
+		for _, re := range r.candidates() {
+			err := re.conn.Invoke(r.replicaContext(ctx, re), method, args, reply, opts...)
+			if err == nil || !r.failover(re, err) {
+				return err
+			}
+		}
+	}
+
+	return r.primary.Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream implements grpc.ClientConnInterface, streams are served by the primary
+func (r *endpointRouter) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return r.primary.NewStream(ctx, desc, method, opts...)
+}
+
+// routed tells if the call can be served by a replica, reads bound to a snapshot are served by the primary holding it
+func (r *endpointRouter) routed(name string, args interface{}) bool {
+	verified, ok := replicaReadMethods[name]
+	if !ok || (verified && !r.verifiedReads) {
+		return false
+	}
+
+	if req, ok := args.(interface{ GetKeyRequest() *schema.KeyRequest }); ok && req.GetKeyRequest().GetSnapshot() != "" {
+		return false
+	}
+
+	if req, ok := args.(interface{ GetSnapshot() string }); ok && req.GetSnapshot() != "" {
+		return false
+	}
+
+	return true
+}
+
+// candidates returns the replicas available for a read, starting from the next one in turn
+func (r *endpointRouter) candidates() []*replicaEndpoint {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+
+	var candidates []*replicaEndpoint
+
+	for i := range r.replicas {
+		re := r.replicas[(r.next+i)%len(r.replicas)]
+
+		if now.Before(re.downUntil) || (r.loggedIn && re.token == "") {
+			continue
+		}
+
+		candidates = append(candidates, re)
+	}
+
+	if len(r.replicas) > 0 {
+		r.next = (r.next + 1) % len(r.replicas)
+	}
+
+	return candidates
+}
+
+func (r *endpointRouter) replicaContext(ctx context.Context, re *replicaEndpoint) context.Context {
+	r.mutex.Lock()
+	token := re.token
+	r.mutex.Unlock()
+
+	if token == "" {
+		return ctx
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set("authorization", token)
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// failover tells if a read failed by a replica has to be retried elsewhere
+func (r *endpointRouter) failover(re *replicaEndpoint, err error) bool {
+	if status.Code(err) == codes.Unavailable {
+		r.mutex.Lock()
+		re.downUntil = time.Now().Add(replicaRetryDelay)
+		r.mutex.Unlock()
+
+		r.logger.Warningf("replica %s unavailable, reads are routed to the other endpoints: %v", re.endpoint.Bind(), err)
+
+		return true
+	}
+
+	// the replica did not catch up yet with the state trusted by the client
+	return errors.Is(err, ErrSrvIllegalState)
+}
+
+// updateSessions replays on the replicas the calls opening or closing a session on the primary
+func (r *endpointRouter) updateSessions(ctx context.Context, name string, method string, args interface{}) {
+	r.mutex.Lock()
+	r.loggedIn = name != "Logout"
+	r.mutex.Unlock()
+
+	for _, re := range r.replicas {
+		var token string
+		var err error
+
+		switch name {
+		case "Login":
+			res := &schema.LoginResponse{}
+			err = re.conn.Invoke(ctx, method, args, res)
+			token = res.Token
+		case "UseDatabase":
+			res := &schema.UseDatabaseReply{}
+			err = re.conn.Invoke(r.replicaContext(ctx, re), method, args, res)
+			token = res.Token
+		case "Logout":
+			err = re.conn.Invoke(r.replicaContext(ctx, re), method, args, new(empty.Empty))
+		}
+
+		if err != nil {
+			r.logger.Warningf("replica %s left out of the read routing, %s failed: %v", re.endpoint.Bind(), name, err)
+		}
+
+		r.mutex.Lock()
+		re.token = token
+		r.mutex.Unlock()
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestPrimaryEndpoint(t *testing.T) {
+	primary := &Endpoint{Address: "10.0.0.1", Port: 3322, Role: EndpointPrimary}
+	replica := &Endpoint{Address: "10.0.0.2", Port: 3322, Role: EndpointReplica}
+
+	e, err := primaryEndpoint([]*Endpoint{replica, primary})
+	require.NoError(t, err)
+	require.Equal(t, primary, e)
+	require.Equal(t, "10.0.0.1:3322", e.Bind())
+
+	for _, endpoints := range [][]*Endpoint{
+		{replica},
+		{primary, primary},
+		{primary, nil},
+		{primary, {Address: "10.0.0.3", Port: 3322}},
+		{primary, {Address: "10.0.0.3", Role: EndpointReplica}},
+	} {
+		_, err = primaryEndpoint(endpoints)
+		require.Equal(t, ErrInvalidEndpoints, err)
+	}
+
+	_, err = NewImmuClient(DefaultOptions().WithEndpoints([]*Endpoint{replica}))
+	require.Equal(t, ErrInvalidEndpoints, err)
+}
+
+func newEndpointTestClient(t *testing.T, dir string, dialer servertest.BuffDialer) (ImmuClient, context.Context) {
+	cli, err := NewImmuClient(DefaultOptions().
+		WithDir(dir).
+		WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(dialer), grpc.WithInsecure()}))
+	require.NoError(t, err)
+
+	lr, err := cli.Login(context.Background(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	return cli, ctx
+}
+
+func TestEndpointRouting(t *testing.T) {
+	dir, err := ioutil.TempDir("", "endpoint_routing")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	primaryOpts := server.DefaultOptions().WithDir(dir + "/primary").WithAuth(true)
+	primarySrv := servertest.NewBufconnServer(primaryOpts)
+	primarySrv.Start()
+	defer primarySrv.Stop()
+
+	replicaOpts := server.DefaultOptions().WithDir(dir + "/replica").WithAuth(true)
+	replicaSrv := servertest.NewBufconnServer(replicaOpts)
+	replicaSrv.Start()
+	defer replicaSrv.Stop()
+
+	primaryCli, primaryCtx := newEndpointTestClient(t, dir+"/primary_client", primarySrv.Dialer)
+	defer primaryCli.Disconnect()
+
+	replicaCli, replicaCtx := newEndpointTestClient(t, dir+"/replica_client", replicaSrv.Dialer)
+	defer replicaCli.Disconnect()
+
+	err = primaryCli.CreateDatabase(primaryCtx, &schema.DatabaseSettings{DatabaseName: "db1"})
+	require.NoError(t, err)
+
+	res, err := primaryCli.UseDatabase(primaryCtx, &schema.Database{DatabaseName: "db1"})
+	require.NoError(t, err)
+	primaryCtx = metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", res.Token))
+
+	err = replicaCli.CreateDatabase(replicaCtx, &schema.DatabaseSettings{DatabaseName: "db1", Replica: true})
+	require.NoError(t, err)
+
+	res, err = replicaCli.UseDatabase(replicaCtx, &schema.Database{DatabaseName: "db1"})
+	require.NoError(t, err)
+	replicaCtx = metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", res.Token))
+
+	replicated := uint64(0)
+
+	replicate := func() {
+		state, err := primaryCli.CurrentState(primaryCtx)
+		require.NoError(t, err)
+
+		for ; replicated < state.TxId; replicated++ {
+			exportTxStream, err := primaryCli.ExportTx(primaryCtx, &schema.TxRequest{Tx: replicated + 1})
+			require.NoError(t, err)
+
+			replicateTxStream, err := replicaCli.ReplicateTx(replicaCtx)
+			require.NoError(t, err)
+
+			for {
+				txChunk, err := exportTxStream.Recv()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+
+				err = replicateTxStream.Send(txChunk)
+				require.NoError(t, err)
+			}
+
+			_, err = replicateTxStream.CloseAndRecv()
+			require.NoError(t, err)
+		}
+	}
+
+	dialer := func(ctx context.Context, addr string) (net.Conn, error) {
+		if strings.HasPrefix(addr, "replica") {
+			return replicaSrv.Dialer(ctx, addr)
+		}
+		return primarySrv.Dialer(ctx, addr)
+	}
+
+	cli, err := NewImmuClient(DefaultOptions().
+		WithDir(dir + "/client").
+		WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(dialer), grpc.WithInsecure()}).
+		WithEndpoints([]*Endpoint{
+			{Address: "primary", Port: 3322, Role: EndpointPrimary},
+			{Address: "replica", Port: 3322, Role: EndpointReplica},
+		}))
+	require.NoError(t, err)
+	defer cli.Disconnect()
+
+	lr, err := cli.Login(context.Background(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	udr, err := cli.UseDatabase(ctx, &schema.Database{DatabaseName: "db1"})
+	require.NoError(t, err)
+
+	ctx = metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", udr.Token))
+
+	t.Run("writes are sent to the primary", func(t *testing.T) {
+		_, err := cli.Set(ctx, []byte("key1"), []byte("value1"))
+		require.NoError(t, err)
+
+		replicate()
+
+		_, err = cli.Set(ctx, []byte("key2"), []byte("value2"))
+		require.NoError(t, err)
+	})
+
+	t.Run("reads are served by the replica", func(t *testing.T) {
+		entry, err := cli.Get(ctx, []byte("key1"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("value1"), entry.Value)
+
+		// not replicated yet
+		_, err = cli.Get(ctx, []byte("key2"))
+		require.Error(t, err)
+	})
+
+	t.Run("verified reads fall back to the primary while the replica is behind the trusted state", func(t *testing.T) {
+		_, err := cli.VerifiedSet(ctx, []byte("key3"), []byte("value3"))
+		require.NoError(t, err)
+
+		entry, err := cli.VerifiedGet(ctx, []byte("key2"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("value2"), entry.Value)
+
+		replicate()
+
+		entry, err = cli.VerifiedGet(ctx, []byte("key3"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("value3"), entry.Value)
+	})
+
+	t.Run("reads fail over to the primary when the replica is unavailable", func(t *testing.T) {
+		_, err := cli.Set(ctx, []byte("key4"), []byte("value4"))
+		require.NoError(t, err)
+
+		replicaSrv.Stop()
+
+		entry, err := cli.Get(ctx, []byte("key4"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("value4"), entry.Value)
+	})
+}
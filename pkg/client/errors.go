@@ -30,6 +30,7 @@ var (
 	ErrNotConnected       = errors.New("not connected")
 	ErrHealthCheckFailed  = errors.New("health check failed")
 	ErrServerStateIsOlder = errors.New("server state is older than the client one")
+	ErrInvalidEndpoints   = errors.New("endpoints require an address, a port and a role, with exactly one primary")
 )
 
 // Server errors mapping
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"strconv"
 
+	"github.com/codenotary/immudb/pkg/client/cache"
 	"github.com/codenotary/immudb/pkg/stream"
 
 	c "github.com/codenotary/immudb/cmd/helper"
@@ -55,6 +56,9 @@ type Options struct {
 	LogFileName         string
 	ServerSigningPubKey string
 	StreamChunkSize     int
+	StateCache          cache.Cache
+	// Endpoints route the reads to replicas, when set the primary endpoint replaces Address and Port
+	Endpoints []*Endpoint
 }
 
 // DefaultOptions ...
@@ -207,6 +211,19 @@ func (o *Options) WithServerSigningPubKey(serverSigningPubKey string) *Options {
 	return o
 }
 
+// WithStateCache sets the cache where the trusted states are kept, by default they are stored in files under Dir.
+// cache.NewProcessCache keeps them in memory instead
+func (o *Options) WithStateCache(stateCache cache.Cache) *Options {
+	o.StateCache = stateCache
+	return o
+}
+
+// WithEndpoints sets the primary the client writes to and the replicas it reads from
+func (o *Options) WithEndpoints(endpoints []*Endpoint) *Options {
+	o.Endpoints = endpoints
+	return o
+}
+
 // WithStreamChunkSize set the chunk size
 func (o *Options) WithStreamChunkSize(streamChunkSize int) *Options {
 	o.StreamChunkSize = streamChunkSize
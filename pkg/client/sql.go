@@ -293,7 +293,7 @@ func typedValueToRowValue(tv sql.TypedValue) *schema.SQLValue {
 		{
 			return &schema.SQLValue{Value: &schema.SQLValue_N{N: tv.Value().(uint64)}}
 		}
-	case sql.VarcharType:
+	case sql.VarcharType, sql.JSONType:
 		{
 			return &schema.SQLValue{Value: &schema.SQLValue_S{S: tv.Value().(string)}}
 		}
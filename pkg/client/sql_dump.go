@@ -0,0 +1,88 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/stream"
+)
+
+// SQLDump writes into w the dump of the tables of the database in use, as a SQL script or as CSV records
+func (c *immuClient) SQLDump(ctx context.Context, req *schema.SQLDumpRequest, w io.Writer) error {
+	start := time.Now()
+
+	if req == nil || w == nil {
+		return ErrIllegalArguments
+	}
+
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+
+	dumpClient, err := c.ServiceClient.SQLDump(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, stream.NewChunkReader(dumpClient))
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Debugf("SQLDump finished in %s", time.Since(start))
+
+	return nil
+}
+
+// SQLLoad sends the dump read from r to the server, which executes it in the database in use
+func (c *immuClient) SQLLoad(ctx context.Context, r io.Reader) (*schema.SQLExecResult, error) {
+	start := time.Now()
+
+	if r == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	loadClient, err := c.ServiceClient.SQLLoad(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cw := stream.NewChunkWriter(loadClient, c.Options.StreamChunkSize)
+
+	_, err = io.Copy(cw, r)
+	if err == nil {
+		err = cw.Close()
+	}
+	// io.EOF is returned when the server already closed the stream, the actual error is returned by CloseAndRecv
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	res, err := loadClient.CloseAndRecv()
+
+	c.Logger.Debugf("SQLLoad finished in %s", time.Since(start))
+
+	return res, err
+}
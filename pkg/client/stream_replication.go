@@ -17,10 +17,20 @@ package client
 
 import (
 	"context"
+	"time"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/metadata"
 )
 
+// withReplicationVersions advertises the replication versions of this release when opening a replication stream,
+// the server refuses the stream if they are not compatible with its own
+func withReplicationVersions(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return metadata.NewOutgoingContext(ctx, metadata.Join(md, schema.LocalReplicationVersions().MD()))
+}
+
 func (c *immuClient) ExportTx(ctx context.Context, req *schema.TxRequest) (schema.ImmuService_ExportTxClient, error) {
 	if req == nil {
 		return nil, ErrIllegalArguments
@@ -30,7 +40,7 @@ func (c *immuClient) ExportTx(ctx context.Context, req *schema.TxRequest) (schem
 		return nil, ErrNotConnected
 	}
 
-	return c.ServiceClient.ExportTx(ctx, req)
+	return c.ServiceClient.ExportTx(withReplicationVersions(ctx), req)
 }
 
 func (c *immuClient) ReplicateTx(ctx context.Context) (schema.ImmuService_ReplicateTxClient, error) {
@@ -38,5 +48,115 @@ func (c *immuClient) ReplicateTx(ctx context.Context) (schema.ImmuService_Replic
 		return nil, ErrNotConnected
 	}
 
-	return c.ServiceClient.ReplicateTx(ctx)
+	return c.ServiceClient.ReplicateTx(withReplicationVersions(ctx))
+}
+
+func (c *immuClient) ConfirmReplication(ctx context.Context, req *schema.ConfirmReplicationRequest) error {
+	if req == nil {
+		return ErrIllegalArguments
+	}
+
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+
+	_, err := c.ServiceClient.ConfirmReplication(ctx, req)
+
+	return err
+}
+
+func (c *immuClient) ReplicationStatus(ctx context.Context) (*schema.ReplicationStatusResponse, error) {
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	return c.ServiceClient.ReplicationStatus(ctx, &empty.Empty{})
+}
+
+// PrimaryEndpoint returns the address of the server accepting the writes of the database in use,
+// which changes when a replica gets promoted after the failure of its primary
+func (c *immuClient) PrimaryEndpoint(ctx context.Context) (*schema.PrimaryEndpointResponse, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	res, err := c.ServiceClient.PrimaryEndpoint(ctx, &empty.Empty{})
+
+	c.Logger.Debugf("PrimaryEndpoint finished in %s", time.Since(start))
+
+	return res, err
+}
+
+// UpdateReplication switches a database between primary and replica, or changes the primary it replicates from
+func (c *immuClient) UpdateReplication(ctx context.Context, req *schema.UpdateReplicationRequest) (*schema.UpdateReplicationResponse, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	res, err := c.ServiceClient.UpdateReplication(ctx, req)
+
+	c.Logger.Debugf("UpdateReplication finished in %s", time.Since(start))
+
+	return res, err
+}
+
+// ReplicationAudit returns the replication audit trail of the database in use, or of the requested one
+func (c *immuClient) ReplicationAudit(ctx context.Context, req *schema.ReplicationAuditRequest) (*schema.ReplicationAuditList, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	res, err := c.ServiceClient.ReplicationAudit(ctx, req)
+
+	c.Logger.Debugf("ReplicationAudit finished in %s", time.Since(start))
+
+	return res, err
+}
+
+// ExportSnapshot opens a stream receiving the compressed archive of the transactions committed after sinceTx
+func (c *immuClient) ExportSnapshot(ctx context.Context, req *schema.SnapshotRequest) (schema.ImmuService_ExportSnapshotClient, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	return c.ServiceClient.ExportSnapshot(ctx, req)
+}
+
+// SyncSnapshot brings a replica in sync with its primary through a snapshot of the transactions it misses
+func (c *immuClient) SyncSnapshot(ctx context.Context, req *schema.SyncSnapshotRequest) (*schema.SyncSnapshotResponse, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	res, err := c.ServiceClient.SyncSnapshot(ctx, req)
+
+	c.Logger.Debugf("SyncSnapshot finished in %s", time.Since(start))
+
+	return res, err
+}
+
+// ReplicationEvents opens a stream receiving the replication events of the database in use, or of every database.
+// The stream ends when ctx is cancelled
+func (c *immuClient) ReplicationEvents(ctx context.Context, req *schema.ReplicationEventsRequest) (schema.ImmuService_ReplicationEventsClient, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	return c.ServiceClient.ReplicationEvents(ctx, req)
 }
@@ -21,6 +21,7 @@ import (
 	"context"
 	"io"
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
@@ -28,7 +29,9 @@ import (
 	"github.com/codenotary/immudb/pkg/server/servertest"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestImmuClient_ExportAndReplicateTx(t *testing.T) {
@@ -114,3 +117,65 @@ func TestImmuClient_ExportAndReplicateTx(t *testing.T) {
 	_, err = client.ReplicateTx(rctx)
 	require.Equal(t, ErrNotConnected, err)
 }
+
+func TestImmuClient_ReplicationHandshake(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true)
+	bs := servertest.NewBufconnServer(options)
+
+	defer os.RemoveAll(options.Dir)
+	defer os.Remove(".state-")
+
+	bs.Start()
+	defer bs.Stop()
+
+	client, err := NewImmuClient(DefaultOptions().WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}))
+	require.NoError(t, err)
+
+	lr, err := client.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = client.Set(ctx, []byte("key1"), []byte("value1"))
+	require.NoError(t, err)
+
+	exportTxStream, err := client.ExportTx(ctx, &schema.TxRequest{Tx: 1})
+	require.NoError(t, err)
+
+	header, err := exportTxStream.Header()
+	require.NoError(t, err)
+	require.Equal(t, []string{strconv.Itoa(schema.ReplicationProtocolVersion)}, header.Get(schema.NegotiatedReplicationProtocolKey))
+
+	peer, err := schema.ReplicationVersionsFromMD(header)
+	require.NoError(t, err)
+	require.Equal(t, schema.LocalReplicationVersions(), peer)
+
+	t.Run("streams opened by peers predating the handshake should be accepted", func(t *testing.T) {
+		exportTxStream, err := client.GetServiceClient().ExportTx(ctx, &schema.TxRequest{Tx: 1})
+		require.NoError(t, err)
+
+		_, err = exportTxStream.Recv()
+		require.NoError(t, err)
+	})
+
+	t.Run("streams opened by incompatible peers should be refused", func(t *testing.T) {
+		for _, v := range []schema.ReplicationVersions{
+			{Protocol: schema.ReplicationProtocolVersion + 2, MinProtocol: schema.ReplicationProtocolVersion + 1, StoreFormat: schema.LocalReplicationVersions().StoreFormat},
+			{Protocol: schema.ReplicationProtocolVersion, MinProtocol: schema.MinReplicationProtocolVersion, StoreFormat: schema.LocalReplicationVersions().StoreFormat + 1},
+		} {
+			ictx := metadata.NewOutgoingContext(context.Background(), metadata.Join(metadata.Pairs("authorization", lr.Token), v.MD()))
+
+			exportTxStream, err := client.GetServiceClient().ExportTx(ictx, &schema.TxRequest{Tx: 1})
+			require.NoError(t, err)
+
+			_, err = exportTxStream.Recv()
+			require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+			replicateTxStream, err := client.GetServiceClient().ReplicateTx(ictx)
+			require.NoError(t, err)
+
+			_, err = replicateTxStream.CloseAndRecv()
+			require.Equal(t, codes.FailedPrecondition, status.Code(err))
+		}
+	})
+}
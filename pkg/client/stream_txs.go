@@ -0,0 +1,37 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// StreamTxs opens a stream receiving the committed transactions from req.SinceTx on.
+// The cursor of the last received message can be used as SinceTx to resume the stream
+func (c *immuClient) StreamTxs(ctx context.Context, req *schema.StreamTxsRequest) (schema.ImmuService_StreamTxsClient, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	return c.ServiceClient.StreamTxs(ctx, req)
+}
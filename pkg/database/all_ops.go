@@ -25,7 +25,9 @@ import (
 // ExecAll like Set it permits many insertions at once.
 // The difference is that is possible to to specify a list of a mix of key value set and zAdd insertions.
 // If zAdd reference is not yet present on disk it's possible to add it as a regular key value and the reference is done onFly
-func (d *db) ExecAll(req *schema.ExecAllRequest) (*schema.TxMetadata, error) {
+func (d *db) ExecAll(req *schema.ExecAllRequest) (res *schema.TxMetadata, err error) {
+	defer func() { err = d.awaitReplicas(res.GetId(), err) }()
+
 	if req == nil {
 		return nil, store.ErrIllegalArguments
 	}
@@ -41,8 +43,20 @@ func (d *db) ExecAll(req *schema.ExecAllRequest) (*schema.TxMetadata, error) {
 		return nil, ErrIsReplica
 	}
 
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
 	lastTxID, _ := d.st.Alh()
-	err := d.st.WaitForIndexingUpto(lastTxID, nil)
+	err = d.st.WaitForIndexingUpto(lastTxID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -69,17 +83,27 @@ func (d *db) ExecAll(req *schema.ExecAllRequest) (*schema.TxMetadata, error) {
 					return nil, store.ErrIllegalArguments
 				}
 
-				kv = EncodeKV(x.Kv.Key, x.Kv.Value)
+				e, err := d.encodeKeyValue(x.Kv, false)
+				if err != nil {
+					return nil, err
+				}
+
+				kv = e
 
 			case *schema.Op_Ref:
 				if len(x.Ref.Key) == 0 || len(x.Ref.ReferencedKey) == 0 {
 					return nil, store.ErrIllegalArguments
 				}
 
-				if x.Ref.AtTx > 0 && !x.Ref.BoundRef {
+				if !validBinding(x.Ref) {
 					return nil, store.ErrIllegalArguments
 				}
 
+				atTx, err := d.referenceAtTx(x.Ref)
+				if err != nil {
+					return nil, err
+				}
+
 				// check key does not exists or it's already a reference
 				entry, err := d.getAt(EncodeKey(x.Ref.Key), 0, 0, index, d.tx1)
 				if err != nil && err != store.ErrKeyNotFound {
@@ -92,9 +116,9 @@ func (d *db) ExecAll(req *schema.ExecAllRequest) (*schema.TxMetadata, error) {
 				// reference arguments are converted in regular key value items and then atomically inserted
 				_, exists := kmap[sha256.Sum256(x.Ref.ReferencedKey)]
 
-				if !exists || x.Ref.AtTx > 0 {
+				if !exists || atTx > 0 {
 					// check referenced key exists and it's not a reference
-					refEntry, err := d.getAt(EncodeKey(x.Ref.ReferencedKey), x.Ref.AtTx, 0, index, d.tx1)
+					refEntry, err := d.getAt(EncodeKey(x.Ref.ReferencedKey), atTx, 0, index, d.tx1)
 					if err != nil {
 						return nil, err
 					}
@@ -103,10 +127,10 @@ func (d *db) ExecAll(req *schema.ExecAllRequest) (*schema.TxMetadata, error) {
 					}
 				}
 
-				if x.Ref.BoundRef && x.Ref.AtTx == 0 {
+				if x.Ref.BoundRef && atTx == 0 {
 					kv = EncodeReference(x.Ref.Key, x.Ref.ReferencedKey, txID)
 				} else {
-					kv = EncodeReference(x.Ref.Key, x.Ref.ReferencedKey, x.Ref.AtTx)
+					kv = EncodeReference(x.Ref.Key, x.Ref.ReferencedKey, atTx)
 				}
 
 			case *schema.Op_ZAdd:
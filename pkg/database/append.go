@@ -0,0 +1,140 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// maxAppendAttempts is the number of times an append is attempted when the key gets concurrently updated
+const maxAppendAttempts = 5
+
+// Append atomically appends the value to the current one of the key, which gets created when it doesn't exist.
+// Keys deleted or expired are considered as not existing
+func (d *db) Append(req *schema.AppendRequest) (res *schema.AppendResponse, err error) {
+	defer func() { err = d.awaitReplicas(res.GetTx().GetId(), err) }()
+
+	if req == nil || len(req.Key) == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.isReplica() {
+		return nil, ErrIsReplica
+	}
+
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
+	// appends are serialized, other writers are detected by the constraint checked at commit time
+	d.appendMutex.Lock()
+	defer d.appendMutex.Unlock()
+
+	for i := 0; i < maxAppendAttempts; i++ {
+		res, err := d.append(req)
+		if err == store.ErrPreconditionFailed || err == store.ErrKeyAlreadyExists {
+			// the key was updated meanwhile
+			continue
+		}
+
+		return res, err
+	}
+
+	return nil, ErrPreconditionFailed
+}
+
+func (d *db) append(req *schema.AppendRequest) (*schema.AppendResponse, error) {
+	lastTxID, _ := d.st.Alh()
+
+	err := d.st.WaitForIndexingUpto(lastTxID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	key := EncodeKey(req.Key)
+
+	val, ktx, _, err := d.st.Get(key)
+	if err != nil && err != store.ErrKeyNotFound {
+		return nil, err
+	}
+
+	var current []byte
+
+	if err == nil {
+		md, v, err := UnwrapMetadata(val)
+		if err != nil {
+			return nil, err
+		}
+
+		deleted, err := d.deletedByPrefix(key, ktx, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isHidden(md) && !deleted {
+			if v[0] != PlainValuePrefix {
+				// references can not be appended to
+				return nil, ErrIllegalArguments
+			}
+
+			current = TrimPrefix(v)
+		}
+	}
+
+	value := make([]byte, len(current)+len(req.Value))
+	copy(value, current)
+	copy(value[len(current):], req.Value)
+
+	e, err := d.encodeKV(req.Key, value, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if ktx == 0 {
+		e.Constraint = store.MustNotExist
+	} else {
+		e.Constraint = store.MustMatchTx
+		e.ExpectedTx = ktx
+	}
+
+	md, err := d.st.Commit([]*store.KV{e}, !req.NoWait)
+	if err != nil {
+		return nil, err
+	}
+
+	_, v, err := UnwrapMetadata(e.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.AppendResponse{
+		Tx:     schema.TxMetatadaTo(md),
+		Length: uint64(len(v) - 1),
+	}, nil
+}
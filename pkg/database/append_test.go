@@ -0,0 +1,88 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppend(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.Append(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.Append(&schema.AppendRequest{Value: []byte("event1;")})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	res, err := db.Append(&schema.AppendRequest{Key: []byte("log1"), Value: []byte("event1;")})
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), res.Length)
+
+	res, err = db.Append(&schema.AppendRequest{Key: []byte("log1"), Value: []byte("event2;")})
+	require.NoError(t, err)
+	require.Equal(t, uint64(14), res.Length)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("log1"), SinceTx: res.Tx.Id})
+	require.NoError(t, err)
+	require.Equal(t, []byte("event1;event2;"), entry.Value)
+	require.Equal(t, res.Tx.Id, entry.Tx)
+
+	_, err = db.Delete(&schema.DeleteKeysRequest{Keys: [][]byte{[]byte("log1")}})
+	require.NoError(t, err)
+
+	res, err = db.Append(&schema.AppendRequest{Key: []byte("log1"), Value: []byte("event3;")})
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), res.Length)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte("ref1"), ReferencedKey: []byte("log1")})
+	require.NoError(t, err)
+
+	_, err = db.Append(&schema.AppendRequest{Key: []byte("ref1"), Value: []byte("event4;")})
+	require.Equal(t, ErrIllegalArguments, err)
+}
+
+func TestConcurrentAppend(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 5; j++ {
+				_, err := db.Append(&schema.AppendRequest{Key: []byte("log1"), Value: []byte("x")})
+				require.NoError(t, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("log1")})
+	require.NoError(t, err)
+	require.Len(t, entry.Value, 20)
+}
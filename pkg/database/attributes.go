@@ -0,0 +1,66 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// MaxKVAttributes is the max number of user attributes of an entry
+const MaxKVAttributes = 16
+
+// MaxKVAttributesLen is the max length of the names and values of the user attributes of an entry
+const MaxKVAttributesLen = 1024
+
+// attributesMetadata validates the user attributes of an entry and wraps them into its metadata,
+// which is nil when there are no attributes
+func attributesMetadata(attrs map[string]string) (*schema.KVMetadata, error) {
+	if len(attrs) == 0 {
+		return nil, nil
+	}
+
+	if len(attrs) > MaxKVAttributes {
+		return nil, ErrInvalidKVAttributes
+	}
+
+	l := 0
+
+	for name, value := range attrs {
+		if len(name) == 0 {
+			return nil, ErrInvalidKVAttributes
+		}
+
+		l += len(name) + len(value)
+	}
+
+	if l > MaxKVAttributesLen {
+		return nil, ErrInvalidKVAttributes
+	}
+
+	return &schema.KVMetadata{Attributes: attrs}, nil
+}
+
+// encodeKeyValue encodes a key-value pair along with its user attributes
+func (d *db) encodeKeyValue(kv *schema.KeyValue, verifiable bool) (*store.KV, error) {
+	md, err := attributesMetadata(kv.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.encodeKVWithMetadata(kv.Key, kv.Value, verifiable, md)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributesMetadataEncoding(t *testing.T) {
+	md := &schema.KVMetadata{
+		ExpiresAt:  1700000000,
+		Attributes: map[string]string{"author": "alice", "content-type": "application/json", "empty": ""},
+	}
+
+	emd := EncodeMetadata(md)
+
+	// the encoding does not depend on the map iteration order
+	for i := 0; i < 10; i++ {
+		require.Equal(t, emd, EncodeMetadata(md))
+	}
+
+	decoded, err := DecodeMetadata(emd)
+	require.NoError(t, err)
+	require.Equal(t, md.ExpiresAt, decoded.ExpiresAt)
+	require.Equal(t, md.Attributes, decoded.Attributes)
+
+	_, err = DecodeMetadata([]byte{mdAttrAttributes, 0, 0, 0, 5, 0, 0, 0, 1, 'a'})
+	require.Equal(t, ErrInvalidMetadata, err)
+}
+
+func TestSetWithAttributes(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte("k1"), Value: []byte("v1"), Attributes: map[string]string{"": "v"}},
+	}})
+	require.Equal(t, ErrInvalidKVAttributes, err)
+
+	tooMany := make(map[string]string)
+	for i := 0; i <= MaxKVAttributes; i++ {
+		tooMany[fmt.Sprintf("attr%d", i)] = "v"
+	}
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k1"), Value: []byte("v1"), Attributes: tooMany}}})
+	require.Equal(t, ErrInvalidKVAttributes, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte("k1"), Value: []byte("v1"), Attributes: map[string]string{"note": strings.Repeat("x", MaxKVAttributesLen)}},
+	}})
+	require.Equal(t, ErrInvalidKVAttributes, err)
+
+	attrs := map[string]string{"author": "alice", "source": "billing"}
+
+	md, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k1"), Value: []byte("v1"), Attributes: attrs}}})
+	require.NoError(t, err)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("k1")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v1"), entry.Value)
+	require.Equal(t, attrs, entry.Metadata.Attributes)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k1"), Value: []byte("v2")}}})
+	require.NoError(t, err)
+
+	history, err := db.History(&schema.HistoryRequest{Key: []byte("k1")})
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 2)
+	require.Equal(t, attrs, history.Entries[0].Metadata.Attributes)
+	require.Nil(t, history.Entries[1].Metadata)
+
+	_, err = db.ExecAll(&schema.ExecAllRequest{Operations: []*schema.Op{
+		{Operation: &schema.Op_Kv{Kv: &schema.KeyValue{Key: []byte("k2"), Value: []byte("v3"), Attributes: attrs}}},
+	}})
+	require.NoError(t, err)
+
+	list, err := db.Scan(&schema.ScanRequest{Prefix: []byte("k")})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 2)
+	require.Nil(t, list.Entries[0].Metadata)
+	require.Equal(t, attrs, list.Entries[1].Metadata.Attributes)
+
+	// attributes are covered by the inclusion proof of the entry
+	vitem, err := db.VerifiableGet(&schema.VerifiableGetRequest{KeyRequest: &schema.KeyRequest{Key: []byte("k1"), AtTx: md.Id}})
+	require.NoError(t, err)
+
+	inclusionProof := schema.InclusionProofFrom(vitem.InclusionProof)
+	eh := schema.DualProofFrom(vitem.VerifiableTx.DualProof).TargetTxMetadata.Eh
+
+	verifies := store.VerifyInclusion(inclusionProof, EncodeKVWithMetadata(vitem.Entry.Key, vitem.Entry.Value, vitem.Entry.Metadata), eh)
+	require.True(t, verifies)
+
+	vitem.Entry.Metadata.Attributes["author"] = "mallory"
+
+	verifies = store.VerifyInclusion(inclusionProof, EncodeKVWithMetadata(vitem.Entry.Key, vitem.Entry.Value, vitem.Entry.Metadata), eh)
+	require.False(t, verifies)
+}
@@ -0,0 +1,424 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"crypto/sha256"
+	"sort"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// verifyRangeBatchSize is the number of transactions verified at once by VerifyRange, the database is not locked between batches
+const verifyRangeBatchSize = 100
+
+// ComplianceReport summarizes the transactions committed during the requested period (unix seconds, until excluded).
+// Every transaction in the period gets verified by checking its link to the previous one and the digests of its values
+func (d *db) ComplianceReport(req *schema.ComplianceReportRequest) (*schema.ComplianceReportResponse, error) {
+	if req == nil || req.SinceTime < 0 || req.UntilTime < 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	now := time.Now().Unix()
+
+	untilTime := req.UntilTime
+	if untilTime == 0 {
+		untilTime = now + 1
+	}
+
+	if req.SinceTime >= untilTime {
+		return nil, ErrIllegalArguments
+	}
+
+	lastTxID, lastTxAlh := d.st.Alh()
+
+	report := &schema.ComplianceReportResponse{
+		Db:          d.name,
+		SinceTime:   req.SinceTime,
+		UntilTime:   untilTime,
+		GeneratedAt: now,
+		StateTxId:   lastTxID,
+		StateTxHash: lastTxAlh[:],
+	}
+
+	tx := d.st.NewTx()
+
+	// transaction timestamps are non-decreasing, so the first tx of the period can be searched
+	var err error
+
+	firstTxID := uint64(sort.Search(int(lastTxID), func(i int) bool {
+		if err != nil {
+			return true
+		}
+
+		err = d.st.ReadTx(uint64(i+1), tx)
+
+		return err != nil || tx.Ts >= req.SinceTime
+	})) + 1
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the first tx is linked to the digest of an empty chain
+	prevAlh := sha256.Sum256(nil)
+
+	// linking can not be checked for the tx following a corrupted one
+	checkLink := true
+
+	if firstTxID > 1 && firstTxID <= lastTxID {
+		err = d.st.ReadTx(firstTxID-1, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		prevAlh = tx.Alh
+	}
+
+	for txID := firstTxID; txID <= lastTxID; txID++ {
+		err = d.st.ReadTx(txID, tx)
+		if err == store.ErrorCorruptedTxData {
+			report.TxCount++
+			report.FailedVerifications++
+			d.Logger.Errorf("compliance report: tx %d of database '%s' is corrupted", txID, d.name)
+			checkLink = false
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if tx.Ts >= untilTime {
+			break
+		}
+
+		if report.TxCount == 0 {
+			report.FirstTx = txID
+		}
+
+		report.LastTx = txID
+		report.TxCount++
+
+		valuesVerified, redactedEntries, err := d.verifyTxValues(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		report.EntriesCount += uint64(len(tx.Entries()))
+		report.PayloadSize += txPayloadSize(tx)
+		report.RedactedEntries += redactedEntries
+
+		verified := valuesVerified && (!checkLink || tx.PrevAlh == prevAlh)
+
+		if verified {
+			report.VerifiedTxCount++
+		} else {
+			report.FailedVerifications++
+			d.Logger.Errorf("compliance report: tx %d of database '%s' could not be verified", txID, d.name)
+		}
+
+		prevAlh = tx.Alh
+		checkLink = true
+	}
+
+	return report, nil
+}
+
+// TxsVerification is the outcome of the verification of a range of committed transactions
+type TxsVerification struct {
+	FirstTx             uint64
+	LastTx              uint64
+	TxCount             uint64
+	EntriesCount        uint64
+	FailedVerifications uint64
+	// FailedTxs holds the ids of the transactions that could not be verified
+	FailedTxs []uint64
+	// BytesRead accounts the keys and values read from disk during the verification
+	BytesRead uint64
+	// LastAlh is the accumulated linear hash of LastTx, left empty if it could not be read
+	LastAlh [sha256.Size]byte
+}
+
+// VerifyTxs verifies up to limit transactions committed from sinceTx onwards, the same way ComplianceReport does.
+// Long verifications can be split in several calls so the database is not kept locked for the whole run
+func (d *db) VerifyTxs(sinceTx uint64, limit int) (*TxsVerification, error) {
+	if sinceTx == 0 || limit <= 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	lastTxID, _ := d.st.Alh()
+
+	verification := &TxsVerification{}
+
+	if sinceTx > lastTxID {
+		return verification, nil
+	}
+
+	tx := d.st.NewTx()
+
+	// the first tx is linked to the digest of an empty chain
+	prevAlh := sha256.Sum256(nil)
+	checkLink := true
+
+	if sinceTx > 1 {
+		err := d.st.ReadTx(sinceTx-1, tx)
+		if err == store.ErrorCorruptedTxData {
+			checkLink = false
+		} else if err != nil {
+			return nil, err
+		}
+
+		prevAlh = tx.Alh
+	}
+
+	for txID := sinceTx; txID <= lastTxID && verification.TxCount < uint64(limit); txID++ {
+		if verification.TxCount == 0 {
+			verification.FirstTx = txID
+		}
+
+		verification.LastTx = txID
+		verification.TxCount++
+
+		err := d.st.ReadTx(txID, tx)
+		if err == store.ErrorCorruptedTxData {
+			verification.FailedVerifications++
+			verification.FailedTxs = append(verification.FailedTxs, txID)
+			d.Logger.Errorf("tx %d of database '%s' is corrupted", txID, d.name)
+			checkLink = false
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		valuesVerified, _, err := d.verifyTxValues(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		verification.EntriesCount += uint64(len(tx.Entries()))
+
+		for _, e := range tx.Entries() {
+			verification.BytesRead += uint64(len(e.Key()) + e.VLen())
+		}
+
+		if !valuesVerified || (checkLink && tx.PrevAlh != prevAlh) {
+			verification.FailedVerifications++
+			verification.FailedTxs = append(verification.FailedTxs, txID)
+			d.Logger.Errorf("tx %d of database '%s' could not be verified", txID, d.name)
+		}
+
+		prevAlh = tx.Alh
+		checkLink = true
+
+		verification.LastAlh = tx.Alh
+	}
+
+	return verification, nil
+}
+
+// verifyTxValues checks the values of the entries of the transaction against their digests,
+// the number of redacted entries found along is returned as well
+func (d *db) verifyTxValues(tx *store.Tx) (verified bool, redactedEntries uint64, err error) {
+	verified = true
+
+	for _, e := range tx.Entries() {
+		val := make([]byte, e.VLen())
+
+		_, err = d.st.ReadValueAt(val, e.VOff(), e.HVal())
+		if err == store.ErrCorruptedData {
+			verified = false
+			continue
+		}
+		if err != nil {
+			return false, 0, err
+		}
+
+		// only key-value entries may carry metadata
+		if e.Key()[0] != SetKeyPrefix {
+			continue
+		}
+
+		md, _, err := UnwrapMetadata(val)
+		if err != nil {
+			verified = false
+			continue
+		}
+
+		if len(md.GetOriginalDigest()) > 0 {
+			redactedEntries++
+		}
+	}
+
+	return verified, redactedEntries, nil
+}
+
+func txPayloadSize(tx *store.Tx) uint64 {
+	var size uint64
+	for _, e := range tx.Entries() {
+		size += uint64(e.VLen())
+	}
+	return size
+}
+
+// VerifyRange verifies on demand the transactions committed in the requested interval or, when a prefix is given,
+// only the ones holding revisions of the keys with that prefix
+func (d *db) VerifyRange(req *schema.VerifyRangeRequest) (*schema.VerifyRangeResponse, error) {
+	if req == nil || (req.UntilTx > 0 && req.SinceTx > req.UntilTx) {
+		return nil, ErrIllegalArguments
+	}
+
+	lastTxID, _ := d.st.Alh()
+
+	sinceTx := req.SinceTx
+	if sinceTx == 0 {
+		sinceTx = 1
+	}
+
+	untilTx := req.UntilTx
+	if untilTx == 0 || untilTx > lastTxID {
+		untilTx = lastTxID
+	}
+
+	res := &schema.VerifyRangeResponse{
+		Db:      d.name,
+		SinceTx: sinceTx,
+		UntilTx: untilTx,
+	}
+
+	add := func(verification *TxsVerification) {
+		res.TxCount += verification.TxCount
+		res.EntriesCount += verification.EntriesCount
+		res.FailedTxs = append(res.FailedTxs, verification.FailedTxs...)
+	}
+
+	if len(req.Prefix) == 0 {
+		for txID := sinceTx; txID <= untilTx; {
+			limit := verifyRangeBatchSize
+			if untilTx-txID < uint64(limit) {
+				limit = int(untilTx-txID) + 1
+			}
+
+			verification, err := d.VerifyTxs(txID, limit)
+			if err != nil {
+				return nil, err
+			}
+
+			if verification.TxCount == 0 {
+				break
+			}
+
+			add(verification)
+
+			txID = verification.LastTx + 1
+		}
+	} else if sinceTx <= untilTx {
+		txIDs, keyCount, err := d.txsHoldingPrefix(req.Prefix, sinceTx, untilTx)
+		if err != nil {
+			return nil, err
+		}
+
+		res.KeyCount = keyCount
+
+		for _, txID := range txIDs {
+			verification, err := d.VerifyTxs(txID, 1)
+			if err != nil {
+				return nil, err
+			}
+
+			add(verification)
+		}
+	}
+
+	res.Verified = len(res.FailedTxs) == 0
+
+	return res, nil
+}
+
+// txsHoldingPrefix returns the sorted ids of the transactions within the interval where the keys with the given prefix were written,
+// along with the number of such keys
+func (d *db) txsHoldingPrefix(prefix []byte, sinceTx, untilTx uint64) ([]uint64, uint64, error) {
+	err := d.st.WaitForIndexingUpto(untilTx, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	snap, err := d.st.SnapshotSince(untilTx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer snap.Close()
+
+	r, err := snap.NewKeyReader(&store.KeyReaderSpec{Prefix: EncodeKey(prefix)})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer r.Close()
+
+	txs := make(map[uint64]struct{})
+	keyCount := uint64(0)
+
+	for {
+		key, _, _, hc, err := r.Read()
+		if err == store.ErrNoMoreEntries {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		keyCount++
+
+		// revisions are read from the newest one, so the older ones are skipped once the interval is left behind
+		for offset := uint64(0); offset < hc; offset += MaxKeyScanLimit {
+			tss, err := snap.History(key, offset, true, MaxKeyScanLimit)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			for _, ts := range tss {
+				if ts >= sinceTx && ts <= untilTx {
+					txs[ts] = struct{}{}
+				}
+			}
+
+			if len(tss) == 0 || tss[len(tss)-1] < sinceTx {
+				break
+			}
+		}
+	}
+
+	txIDs := make([]uint64, 0, len(txs))
+	for txID := range txs {
+		txIDs = append(txIDs, txID)
+	}
+
+	sort.Slice(txIDs, func(i, j int) bool { return txIDs[i] < txIDs[j] })
+
+	return txIDs, keyCount, nil
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComplianceReport(t *testing.T) {
+	options := DefaultOption().
+		WithDbRootPath("data_" + strconv.FormatInt(time.Now().UnixNano(), 10)).
+		WithDbName("db").
+		WithValueTransformers([]string{"trimspace"}).
+		WithRetainOriginalDigest(true)
+
+	db, closer := makeDbWith(options)
+	defer closer()
+
+	_, err := db.ComplianceReport(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.ComplianceReport(&schema.ComplianceReportRequest{SinceTime: -1})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.ComplianceReport(&schema.ComplianceReportRequest{SinceTime: 10, UntilTime: 10})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	initialReport, err := db.ComplianceReport(&schema.ComplianceReportRequest{})
+	require.NoError(t, err)
+	require.Equal(t, initialReport.TxCount, initialReport.VerifiedTxCount)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte("key1"), Value: []byte("value1")},
+		{Key: []byte("key2"), Value: []byte(" value2 ")},
+	}})
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key3"), Value: []byte("value3")}}})
+	require.NoError(t, err)
+
+	report, err := db.ComplianceReport(&schema.ComplianceReportRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "db", report.Db)
+	require.Equal(t, uint64(1), report.FirstTx)
+	require.Equal(t, initialReport.StateTxId+2, report.LastTx)
+	require.Equal(t, initialReport.TxCount+2, report.TxCount)
+	require.Equal(t, report.TxCount, report.VerifiedTxCount)
+	require.Zero(t, report.FailedVerifications)
+	require.Equal(t, initialReport.EntriesCount+3, report.EntriesCount)
+	require.Equal(t, uint64(1), report.RedactedEntries)
+	require.Equal(t, report.LastTx, report.StateTxId)
+	require.NotEmpty(t, report.StateTxHash)
+
+	report, err = db.ComplianceReport(&schema.ComplianceReportRequest{UntilTime: 1})
+	require.NoError(t, err)
+	require.Zero(t, report.TxCount)
+
+	_, err = db.ComplianceReport(&schema.ComplianceReportRequest{SinceTime: time.Now().Unix() + 60})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	report, err = db.ComplianceReport(&schema.ComplianceReportRequest{
+		SinceTime: time.Now().Unix() + 60,
+		UntilTime: time.Now().Unix() + 120,
+	})
+	require.NoError(t, err)
+	require.Zero(t, report.TxCount)
+	require.Equal(t, initialReport.StateTxId+2, report.StateTxId)
+}
+
+func TestVerifyTxs(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.VerifyTxs(0, 1)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.VerifyTxs(1, 0)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+		require.NoError(t, err)
+	}
+
+	verification, err := db.VerifyTxs(1, 100)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), verification.FirstTx)
+	require.Equal(t, state.TxId+3, verification.LastTx)
+	require.Equal(t, state.TxId+3, verification.TxCount)
+	require.Zero(t, verification.FailedVerifications)
+	require.Empty(t, verification.FailedTxs)
+	require.GreaterOrEqual(t, verification.EntriesCount, uint64(3))
+	require.NotZero(t, verification.BytesRead)
+
+	lastState, err := db.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, lastState.TxHash, verification.LastAlh[:])
+
+	verification, err = db.VerifyTxs(state.TxId+1, 2)
+	require.NoError(t, err)
+	require.Equal(t, state.TxId+1, verification.FirstTx)
+	require.Equal(t, state.TxId+2, verification.LastTx)
+	require.Equal(t, uint64(2), verification.TxCount)
+	require.Zero(t, verification.FailedVerifications)
+
+	verification, err = db.VerifyTxs(state.TxId+4, 100)
+	require.NoError(t, err)
+	require.Zero(t, verification.TxCount)
+}
+
+func TestVerifyRange(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.VerifyRange(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.VerifyRange(&schema.VerifyRangeRequest{SinceTx: 2, UntilTx: 1})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+
+	for _, key := range []string{"a1", "b1", "a1", "a2"} {
+		_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte(key), Value: []byte("value")}}})
+		require.NoError(t, err)
+	}
+
+	res, err := db.VerifyRange(&schema.VerifyRangeRequest{})
+	require.NoError(t, err)
+	require.True(t, res.Verified)
+	require.Equal(t, uint64(1), res.SinceTx)
+	require.Equal(t, state.TxId+4, res.UntilTx)
+	require.Equal(t, state.TxId+4, res.TxCount)
+	require.Empty(t, res.FailedTxs)
+
+	res, err = db.VerifyRange(&schema.VerifyRangeRequest{SinceTx: state.TxId + 2, UntilTx: state.TxId + 3})
+	require.NoError(t, err)
+	require.True(t, res.Verified)
+	require.Equal(t, uint64(2), res.TxCount)
+	require.Equal(t, uint64(2), res.EntriesCount)
+
+	res, err = db.VerifyRange(&schema.VerifyRangeRequest{Prefix: []byte("a")})
+	require.NoError(t, err)
+	require.True(t, res.Verified)
+	require.Equal(t, uint64(2), res.KeyCount)
+	require.Equal(t, uint64(3), res.TxCount)
+
+	res, err = db.VerifyRange(&schema.VerifyRangeRequest{Prefix: []byte("a"), SinceTx: state.TxId + 2})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), res.KeyCount)
+	require.Equal(t, uint64(2), res.TxCount)
+
+	res, err = db.VerifyRange(&schema.VerifyRangeRequest{Prefix: []byte("z")})
+	require.NoError(t, err)
+	require.True(t, res.Verified)
+	require.Zero(t, res.KeyCount)
+	require.Zero(t, res.TxCount)
+}
+
+func TestVerifyRangeOnTamperedValues(t *testing.T) {
+	rootPath := "data_verify_range_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	defer os.RemoveAll(rootPath)
+
+	options := DefaultOption().WithDbRootPath(rootPath).WithDbName("db")
+
+	db, err := NewDb(options, nil, logger.NewSimpleLogger("immudb ", os.Stderr))
+	require.NoError(t, err)
+
+	txMetadata, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("a1"), Value: []byte("tampered-value")}}})
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("b1"), Value: []byte("value")}}})
+	require.NoError(t, err)
+
+	err = db.Close()
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(rootPath, "db", "val_*", "*.val"))
+	require.NoError(t, err)
+
+	for _, f := range files {
+		content, err := ioutil.ReadFile(f)
+		require.NoError(t, err)
+
+		err = ioutil.WriteFile(f, bytes.ReplaceAll(content, []byte("tampered-value"), []byte("TAMPERED-VALUE")), 0644)
+		require.NoError(t, err)
+	}
+
+	db, err = OpenDb(options, nil, logger.NewSimpleLogger("immudb ", os.Stderr))
+	require.NoError(t, err)
+	defer db.Close()
+
+	res, err := db.VerifyRange(&schema.VerifyRangeRequest{Prefix: []byte("b")})
+	require.NoError(t, err)
+	require.True(t, res.Verified)
+
+	res, err = db.VerifyRange(&schema.VerifyRangeRequest{Prefix: []byte("a")})
+	require.NoError(t, err)
+	require.False(t, res.Verified)
+	require.Equal(t, []uint64{txMetadata.Id}, res.FailedTxs)
+
+	res, err = db.VerifyRange(&schema.VerifyRangeRequest{SinceTx: txMetadata.Id + 1})
+	require.NoError(t, err)
+	require.True(t, res.Verified)
+}
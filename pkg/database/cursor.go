@@ -0,0 +1,62 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"encoding/binary"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+const cursorVersion = 1
+
+// scanCursor is the position where a scan stopped. The keys are read as they were at atTx,
+// so iterating through the pages is not affected by concurrent writes
+type scanCursor struct {
+	atTx    uint64
+	lastKey []byte
+}
+
+func encodeCursor(atTx uint64, lastKey []byte) []byte {
+	b := make([]byte, 1+8+len(lastKey))
+	b[0] = cursorVersion
+	binary.BigEndian.PutUint64(b[1:], atTx)
+	copy(b[1+8:], lastKey)
+
+	return b
+}
+
+func decodeCursor(b []byte) (*scanCursor, error) {
+	if len(b) < 1+8+1 || b[0] != cursorVersion {
+		return nil, ErrInvalidCursor
+	}
+
+	return &scanCursor{
+		atTx:    binary.BigEndian.Uint64(b[1:]),
+		lastKey: b[1+8:],
+	}, nil
+}
+
+// readKey reads the next key, as it was at atTx when it's not zero
+func readKey(r *store.KeyReader, atTx uint64) (key []byte, val *store.ValueRef, tx uint64, err error) {
+	if atTx == 0 {
+		key, val, tx, _, err = r.Read()
+		return key, val, tx, err
+	}
+
+	return r.ReadAsBefore(atTx + 1)
+}
@@ -20,10 +20,12 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
 
+	"github.com/codenotary/immudb/embedded/cache"
 	"github.com/codenotary/immudb/embedded/sql"
 	"github.com/codenotary/immudb/embedded/store"
 
@@ -42,12 +44,20 @@ var ErrIllegalArguments = store.ErrIllegalArguments
 var ErrIllegalState = store.ErrIllegalState
 var ErrIsReplica = errors.New("database is read-only because it's a replica")
 var ErrNotReplica = errors.New("database is NOT a replica")
+var ErrTxNotRepaired = errors.New("tx could not be repaired")
 
 type DB interface {
 	CurrentState() (*schema.ImmutableState, error)
 	WaitForTx(txID uint64, cancellation <-chan struct{}) error
 	WaitForIndexingUpto(txID uint64, cancellation <-chan struct{}) error
 	Set(req *schema.SetRequest) (*schema.TxMetadata, error)
+	SetConditional(req *schema.SetConditionalRequest) (*schema.TxMetadata, error)
+	Delete(req *schema.DeleteKeysRequest) (*schema.TxMetadata, error)
+	DeletePrefix(req *schema.DeletePrefixRequest) (*schema.TxMetadata, error)
+	Append(req *schema.AppendRequest) (*schema.AppendResponse, error)
+	ExpirableSet(req *schema.ExpirableSetRequest) (*schema.TxMetadata, error)
+	SweepExpiredKeys(limit int) (int, error)
+	NewOngoingTx() (*OngoingTx, error)
 	Get(req *schema.KeyRequest) (*schema.Entry, error)
 	VerifiableSet(req *schema.VerifiableSetRequest) (*schema.VerifiableTx, error)
 	VerifiableGet(req *schema.VerifiableGetRequest) (*schema.VerifiableEntry, error)
@@ -59,20 +69,45 @@ type DB interface {
 	TxByID(req *schema.TxRequest) (*schema.Tx, error)
 	ExportTxByID(req *schema.TxRequest) ([]byte, error)
 	ReplicateTx(exportedTx []byte) (*schema.TxMetadata, error)
+	Subscribe(req *schema.SubscribeRequest, cancellation <-chan struct{}, send func(*schema.VerifiableEntry) error) error
+	StreamTxs(req *schema.StreamTxsRequest, cancellation <-chan struct{}, send func(*schema.TxEntries) error) error
 	VerifiableTxByID(req *schema.VerifiableTxRequest) (*schema.VerifiableTx, error)
 	TxScan(req *schema.TxScanRequest) (*schema.TxList, error)
+	StateHistory(req *schema.StateHistoryRequest) (*schema.StateList, error)
 	History(req *schema.HistoryRequest) (*schema.Entries, error)
 	SetReference(req *schema.ReferenceRequest) (*schema.TxMetadata, error)
 	VerifiableSetReference(req *schema.VerifiableReferenceRequest) (*schema.VerifiableTx, error)
+	ResolveReferenceHistory(req *schema.ReferenceHistoryRequest) (*schema.VerifiableReferenceHistory, error)
+	MultiInclusion(req *schema.MultiInclusionRequest) (*schema.MultiInclusionResponse, error)
+	ReferencesTo(req *schema.ReferencesToRequest) (*schema.References, error)
 	ZAdd(req *schema.ZAddRequest) (*schema.TxMetadata, error)
+	ZRem(req *schema.ZRemRequest) (*schema.TxMetadata, error)
 	ZScan(req *schema.ZScanRequest) (*schema.ZEntries, error)
+	VerifiableZScan(req *schema.VerifiableZScanRequest) (*schema.VerifiableZEntries, error)
+	ZCard(req *schema.ZCardRequest) (*schema.EntryCount, error)
+	ZCount(req *schema.ZCountRequest) (*schema.EntryCount, error)
 	VerifiableZAdd(req *schema.VerifiableZAddRequest) (*schema.VerifiableTx, error)
 	Scan(req *schema.ScanRequest) (*schema.Entries, error)
+	OpenSnapshot(req *schema.OpenSnapshotRequest) (*schema.OpenSnapshotResponse, error)
+	ReleaseSnapshot(req *schema.ReleaseSnapshotRequest) error
 	Close() error
 	GetOptions() *DbOptions
 	UpdateReplicationOptions(replicationOpts *ReplicationOptions)
+	SwitchReplication(replicationOpts *ReplicationOptions, minTxID uint64) (uint64, error)
+	UpdateCorruptionChecker(enabled bool)
+	UpdateMaxDiskSize(maxDiskSize int64)
+	UpdateReadOnly(readOnly bool)
+	UpdateParanoidReads(paranoidReads bool)
+	Quarantine(quarantined bool)
+	IsQuarantined() bool
 	IsReplica() bool
+	ConfirmReplication(replicaID string, txID uint64, txHash []byte) error
+	ReplicationProgress() []*ReplicaProgress
+	ReportDownstreamReplicas(replicaID string, downstream []*ReplicaProgress)
+	ReplicatedTxID() (uint64, error)
 	CompactIndex() error
+	VerifyIndex(cancellation <-chan struct{}) (*store.IndexVerification, error)
+	RebuildIndex() error
 	VerifiableSQLGet(req *schema.VerifiableSQLGetRequest) (*schema.VerifiableSQLEntry, error)
 	SQLExec(req *schema.SQLExecRequest) (*schema.SQLExecResult, error)
 	SQLExecPrepared(stmts []sql.SQLStmt, namedParams []*schema.NamedParam, waitForIndexing bool) (*schema.SQLExecResult, error)
@@ -84,7 +119,13 @@ type DB interface {
 	SQLQueryRowReader(stmt *sql.SelectStmt, renewSnapshot bool) (sql.RowReader, error)
 	ListTables() (*schema.SQLQueryResult, error)
 	DescribeTable(table string) (*schema.SQLQueryResult, error)
+	SQLDump(req *schema.SQLDumpRequest, w io.Writer) error
+	SQLLoad(r io.Reader) (*schema.SQLExecResult, error)
 	GetName() string
+	ComplianceReport(req *schema.ComplianceReportRequest) (*schema.ComplianceReportResponse, error)
+	VerifyTxs(sinceTx uint64, limit int) (*TxsVerification, error)
+	VerifyRange(req *schema.VerifyRangeRequest) (*schema.VerifyRangeResponse, error)
+	Scrub(source TxSource) (*ScrubReport, error)
 }
 
 //IDB database instance
@@ -95,12 +136,30 @@ type db struct {
 	sqlInitCancel chan (struct{})
 	sqlInit       sync.WaitGroup
 
+	// sqlStmts keeps the statements of the most frequent SQL requests already parsed
+	sqlStmts *cache.LRUCache
+
 	tx1, tx2 *store.Tx
 	mutex    sync.RWMutex
 
 	Logger  logger.Logger
 	options *DbOptions
 
+	quota quota
+
+	snapshots      map[string]*snapshotHandle
+	snapshotsMutex sync.Mutex
+
+	appendMutex sync.Mutex
+
+	prefixTombstones prefixTombstones
+	referrers        referrers
+
+	// quarantined databases reject writes until the detected corruption is acknowledged
+	quarantined bool
+
+	replicas replicaTracker
+
 	name string
 }
 
@@ -114,6 +173,14 @@ func OpenDb(op *DbOptions, systemDB DB, log logger.Logger) (DB, error) {
 		name:    op.dbName,
 	}
 
+	if err := ValidateValueTransformers(op.valueTransformers); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateValueCompression(op.valueCompression); err != nil {
+		return nil, err
+	}
+
 	dbDir := dbi.path()
 
 	_, dbErr := os.Stat(dbDir)
@@ -121,7 +188,7 @@ func OpenDb(op *DbOptions, systemDB DB, log logger.Logger) (DB, error) {
 		return nil, fmt.Errorf("Missing database directories")
 	}
 
-	dbi.st, err = store.Open(dbDir, op.GetStoreOptions().WithLog(log))
+	dbi.st, err = store.Open(dbDir, withValueCompression(op.GetStoreOptions(), op.valueCompression).WithLog(log))
 	if err != nil {
 		return nil, logErr(dbi.Logger, "Unable to open store: %s", err)
 	}
@@ -134,6 +201,11 @@ func OpenDb(op *DbOptions, systemDB DB, log logger.Logger) (DB, error) {
 		return nil, err
 	}
 
+	dbi.sqlStmts, err = newSQLStmtCache(op.sqlStmtCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
 	if op.replicationOpts.Replica {
 		dbi.Logger.Infof("Database '%s' successfully opened (replica = %v)", op.dbName, op.replicationOpts.Replica)
 		return dbi, nil
@@ -245,6 +317,14 @@ func NewDb(op *DbOptions, systemDB DB, log logger.Logger) (DB, error) {
 		name:    op.dbName,
 	}
 
+	if err := ValidateValueTransformers(op.valueTransformers); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateValueCompression(op.valueCompression); err != nil {
+		return nil, err
+	}
+
 	dbDir := filepath.Join(op.GetDbRootPath(), op.GetDbName())
 
 	if _, dbErr := os.Stat(dbDir); dbErr == nil {
@@ -255,7 +335,7 @@ func NewDb(op *DbOptions, systemDB DB, log logger.Logger) (DB, error) {
 		return nil, logErr(dbi.Logger, "Unable to create data folder: %s", err)
 	}
 
-	dbi.st, err = store.Open(dbDir, op.GetStoreOptions().WithLog(log))
+	dbi.st, err = store.Open(dbDir, withValueCompression(op.GetStoreOptions(), op.valueCompression).WithLog(log))
 	if err != nil {
 		return nil, logErr(dbi.Logger, "Unable to open store: %s", err)
 	}
@@ -268,6 +348,11 @@ func NewDb(op *DbOptions, systemDB DB, log logger.Logger) (DB, error) {
 		return nil, logErr(dbi.Logger, "Unable to open store: %s", err)
 	}
 
+	dbi.sqlStmts, err = newSQLStmtCache(op.sqlStmtCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
 	if !op.replicationOpts.Replica {
 		_, err = dbi.sqlEngine.ExecPreparedStmts([]sql.SQLStmt{&sql.CreateDatabaseStmt{DB: dbInstanceName}}, nil, true)
 		if err != nil {
@@ -309,8 +394,34 @@ func (d *db) CompactIndex() error {
 	return d.sqlEngine.RenewSnapshot()
 }
 
+// VerifyIndex checks the index against the commit log, it can be interrupted through the cancellation channel
+func (d *db) VerifyIndex(cancellation <-chan struct{}) (*store.IndexVerification, error) {
+	return d.st.VerifyIndex(cancellation)
+}
+
+// RebuildIndex replaces the index with a new one built out of the commit log
+func (d *db) RebuildIndex() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	err := d.sqlEngine.CloseSnapshot()
+	if err != nil {
+		return err
+	}
+
+	err = d.st.RebuildIndex()
+	if err != nil {
+		d.sqlEngine.RenewSnapshot()
+		return err
+	}
+
+	return d.sqlEngine.RenewSnapshot()
+}
+
 // Set ...
-func (d *db) Set(req *schema.SetRequest) (*schema.TxMetadata, error) {
+func (d *db) Set(req *schema.SetRequest) (res *schema.TxMetadata, err error) {
+	defer func() { err = d.awaitReplicas(res.GetId(), err) }()
+
 	d.mutex.RLock()
 	defer d.mutex.RUnlock()
 
@@ -318,10 +429,22 @@ func (d *db) Set(req *schema.SetRequest) (*schema.TxMetadata, error) {
 		return nil, ErrIsReplica
 	}
 
-	return d.set(req)
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
+	return d.set(req, false)
 }
 
-func (d *db) set(req *schema.SetRequest) (*schema.TxMetadata, error) {
+func (d *db) set(req *schema.SetRequest, verifiable bool) (*schema.TxMetadata, error) {
 	if req == nil {
 		return nil, ErrIllegalArguments
 	}
@@ -333,10 +456,22 @@ func (d *db) set(req *schema.SetRequest) (*schema.TxMetadata, error) {
 			return nil, ErrIllegalArguments
 		}
 
-		entries[i] = EncodeKV(kv.Key, kv.Value)
+		e, err := d.encodeKeyValue(kv, verifiable)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.IfNotExists {
+			e.Constraint = store.MustNotExist
+		}
+
+		entries[i] = e
 	}
 
 	txMetatadata, err := d.st.Commit(entries, !req.NoWait)
+	if err == store.ErrKeyAlreadyExists {
+		return nil, ErrKeyAlreadyExists
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -354,6 +489,26 @@ func (d *db) Get(req *schema.KeyRequest) (*schema.Entry, error) {
 		return nil, ErrIllegalArguments
 	}
 
+	if req.AsOfTx > 0 || req.AsOfTime > 0 {
+		return d.getAsOf(req)
+	}
+
+	if req.Snapshot != "" {
+		if req.AtTx > 0 || req.SinceTx > 0 {
+			return nil, ErrIllegalArguments
+		}
+
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+
+		index, err := d.pinnedIndex(req.Snapshot)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.getAt(EncodeKey(req.Key), 0, 0, index, d.tx1)
+	}
+
 	err := d.WaitForIndexingUpto(req.SinceTx, nil)
 	if err != nil {
 		return nil, err
@@ -369,7 +524,38 @@ func (d *db) get(key []byte, index store.KeyIndex, tx *store.Tx) (*schema.Entry,
 	return d.getAt(key, 0, 0, index, tx)
 }
 
-func (d *db) getAt(key []byte, atTx uint64, resolved int, index store.KeyIndex, tx *store.Tx) (entry *schema.Entry, err error) {
+// getAt resolves the entry of the key, logically deleted or expired keys are reported as not found
+func (d *db) getAt(key []byte, atTx uint64, resolved int, index store.KeyIndex, tx *store.Tx) (*schema.Entry, error) {
+	entry, err := d.getEntryAt(key, atTx, resolved, index, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if isHidden(entry.Metadata) {
+		return nil, store.ErrKeyNotFound
+	}
+
+	if atTx == 0 {
+		var viewTx uint64
+		if p, ok := index.(*pinnedIndex); ok {
+			viewTx = p.txID
+		}
+
+		deleted, err := d.deletedByPrefix(key, keyTxOf(entry), viewTx)
+		if err != nil {
+			return nil, err
+		}
+
+		if deleted {
+			return nil, store.ErrKeyNotFound
+		}
+	}
+
+	return entry, nil
+}
+
+// getEntryAt resolves the entry of the key, including tombstones
+func (d *db) getEntryAt(key []byte, atTx uint64, resolved int, index store.KeyIndex, tx *store.Tx) (entry *schema.Entry, err error) {
 	var ktx uint64
 	var val []byte
 
@@ -386,6 +572,18 @@ func (d *db) getAt(key []byte, atTx uint64, resolved int, index store.KeyIndex,
 		ktx = atTx
 	}
 
+	if d.options.paranoidReads {
+		err = d.verifyEntryRead(key, val, ktx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	md, val, err := UnwrapMetadata(val)
+	if err != nil {
+		return nil, err
+	}
+
 	//Reference lookup
 	if val[0] == ReferenceValuePrefix {
 		if resolved == MaxKeyResolutionLimit {
@@ -396,7 +594,7 @@ func (d *db) getAt(key []byte, atTx uint64, resolved int, index store.KeyIndex,
 		refKey := make([]byte, len(val)-1-8)
 		copy(refKey, val[1+8:])
 
-		entry, err := d.getAt(refKey, atTx, resolved+1, index, tx)
+		entry, err := d.getEntryAt(refKey, atTx, resolved+1, index, tx)
 		if err != nil {
 			return nil, err
 		}
@@ -410,7 +608,7 @@ func (d *db) getAt(key []byte, atTx uint64, resolved int, index store.KeyIndex,
 		return entry, nil
 	}
 
-	return &schema.Entry{Key: TrimPrefix(key), Value: TrimPrefix(val), Tx: ktx}, err
+	return &schema.Entry{Key: TrimPrefix(key), Value: TrimPrefix(val), Tx: ktx, Metadata: md}, err
 }
 
 func (d *db) readValue(key []byte, atTx uint64, tx *store.Tx) ([]byte, error) {
@@ -445,6 +643,29 @@ func (d *db) WaitForIndexingUpto(txID uint64, cancellation <-chan struct{}) erro
 	return d.st.WaitForIndexingUpto(txID, cancellation)
 }
 
+func (d *db) verifiableSet(req *schema.SetRequest) (*schema.TxMetadata, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.isReplica() {
+		return nil, ErrIsReplica
+	}
+
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
+	return d.set(req, true)
+}
+
 //VerifiableSet ...
 func (d *db) VerifiableSet(req *schema.VerifiableSetRequest) (*schema.VerifiableTx, error) {
 	if req == nil {
@@ -456,7 +677,12 @@ func (d *db) VerifiableSet(req *schema.VerifiableSetRequest) (*schema.Verifiable
 		return nil, ErrIllegalState
 	}
 
-	txMetatadata, err := d.Set(req.SetRequest)
+	txMetatadata, err := d.verifiableSet(req.SetRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.waitForReplicas(txMetatadata.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -647,14 +873,49 @@ func (d *db) TxByID(req *schema.TxRequest) (*schema.Tx, error) {
 }
 
 func (d *db) ExportTxByID(req *schema.TxRequest) ([]byte, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if req.ReplicaId == "" {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+
+		return d.st.ExportTx(req.Tx, d.tx1)
+	}
+
+	// a diverged replica would keep on failing to confirm what it gets
+	if d.replicas.divergenceOf(req.ReplicaId) != nil {
+		return nil, ErrReplicationStopped
+	}
+
+	rOpts := d.options.GetReplicationOptions()
+
+	// each replica is throttled on its own, a fast replica is not slowed down by the others
+	// unless the rates of the database are reached
+	exportedAt := d.replicas.throttle(req.ReplicaId, rOpts)
+
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
-	if req == nil {
-		return nil, ErrIllegalArguments
+	etx, err := d.st.ExportTx(req.Tx, d.tx1)
+	if err != nil {
+		return nil, err
 	}
 
-	return d.st.ExportTx(req.Tx, d.tx1)
+	d.replicas.exported(req.ReplicaId, req.Tx)
+
+	prefixes, filtered := rOpts.ReplicaFilters[req.ReplicaId]
+	if filtered {
+		etx, err = store.FilterExportedTx(etx, replicaKeyFilter(prefixes))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	d.replicas.transferred(req.ReplicaId, exportedAt, len(etx), rOpts)
+
+	return etx, nil
 }
 
 func (d *db) ReplicateTx(exportedTx []byte) (*schema.TxMetadata, error) {
@@ -665,11 +926,34 @@ func (d *db) ReplicateTx(exportedTx []byte) (*schema.TxMetadata, error) {
 		return nil, ErrNotReplica
 	}
 
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
+	if d.options.GetReplicationOptions().Partial {
+		return d.replicatePartially(exportedTx)
+	}
+
 	md, err := d.st.ReplicateTx(exportedTx, false)
 	if err != nil {
 		return nil, err
 	}
 
+	_, entries, err := store.ExportedTxEntries(exportedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	d.recordPrefixTombstones(entries, md.ID)
+
 	return schema.TxMetatadaTo(md), nil
 }
 
@@ -770,6 +1054,54 @@ func (d *db) TxScan(req *schema.TxScanRequest) (*schema.TxList, error) {
 	return txList, nil
 }
 
+// StateHistory returns the state of the database right after each of the requested transactions were committed
+func (d *db) StateHistory(req *schema.StateHistoryRequest) (*schema.StateList, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if req.Limit > MaxKeyScanLimit {
+		return nil, ErrMaxKeyScanLimitExceeded
+	}
+
+	limit := int(req.Limit)
+
+	if req.Limit == 0 {
+		limit = MaxKeyScanLimit
+	}
+
+	txReader, err := d.st.NewTxReader(req.InitialTx, req.Desc, d.tx1)
+	if err != nil {
+		return nil, err
+	}
+
+	stateList := &schema.StateList{Db: d.name}
+
+	for i := 0; i < limit; i++ {
+		tx, err := txReader.Read()
+		if err == store.ErrNoMoreEntries {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// tx is reused by the reader, so its digest is copied
+		alh := tx.Alh
+
+		stateList.States = append(stateList.States, &schema.HistoricalState{
+			TxId:   tx.ID,
+			TxHash: alh[:],
+			Ts:     tx.Ts,
+		})
+	}
+
+	return stateList, nil
+}
+
 //History ...
 func (d *db) History(req *schema.HistoryRequest) (*schema.Entries, error) {
 	d.mutex.Lock()
@@ -816,7 +1148,12 @@ func (d *db) History(req *schema.HistoryRequest) (*schema.Entries, error) {
 			return nil, err
 		}
 
-		list.Entries[i] = &schema.Entry{Key: req.Key, Value: TrimPrefix(val), Tx: tx}
+		md, val, err := UnwrapMetadata(val)
+		if err != nil {
+			return nil, err
+		}
+
+		list.Entries[i] = &schema.Entry{Key: req.Key, Value: TrimPrefix(val), Tx: tx, Metadata: md}
 	}
 
 	return list, nil
@@ -833,6 +1170,8 @@ func (d *db) Close() error {
 
 	d.sqlInit.Wait() // Wait for SQL Engine initialization to conclude
 
+	d.releaseSnapshots()
+
 	err := d.sqlEngine.Close()
 	if err != nil {
 		return err
@@ -852,8 +1191,8 @@ func (d *db) GetOptions() *DbOptions {
 }
 
 func (d *db) UpdateReplicationOptions(replicationOpts *ReplicationOptions) {
-	d.mutex.RLock()
-	defer d.mutex.RUnlock()
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 
 	if replicationOpts.Replica {
 		d.Logger.Warningf("Replication is a work-in-progress feature. Not ready for production use")
@@ -862,6 +1201,63 @@ func (d *db) UpdateReplicationOptions(replicationOpts *ReplicationOptions) {
 	d.options.WithReplicationOptions(replicationOpts)
 }
 
+// SwitchReplication atomically applies the replication options fencing ongoing writes. A replica is only promoted
+// if it already holds transaction minTxID. It returns the last committed transaction at the time of the switch
+func (d *db) SwitchReplication(replicationOpts *ReplicationOptions, minTxID uint64) (uint64, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	txID, _ := d.st.Alh()
+
+	if d.isReplica() && !replicationOpts.Replica && txID < minTxID {
+		return txID, ErrReplicaBehind
+	}
+
+	d.options.WithReplicationOptions(replicationOpts)
+
+	return txID, nil
+}
+
+// UpdateCorruptionChecker enables or disables the corruption checker for this database
+func (d *db) UpdateCorruptionChecker(enabled bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.options.WithCorruptionChecker(enabled)
+}
+
+// UpdateReadOnly sets if mutating operations are rejected for this database
+func (d *db) UpdateReadOnly(readOnly bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.options.WithReadOnly(readOnly)
+}
+
+// UpdateParanoidReads sets if read entries are verified against the database state for this database
+func (d *db) UpdateParanoidReads(paranoidReads bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.options.WithParanoidReads(paranoidReads)
+}
+
+// Quarantine sets if writes are rejected for this database because corruption was detected in it
+func (d *db) Quarantine(quarantined bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.quarantined = quarantined
+}
+
+// IsQuarantined returns if writes are rejected because corruption was detected in the database
+func (d *db) IsQuarantined() bool {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	return d.quarantined
+}
+
 func (d *db) IsReplica() bool {
 	d.mutex.RLock()
 	defer d.mutex.RUnlock()
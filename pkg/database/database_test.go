@@ -436,6 +436,43 @@ func TestSetGetAll(t *testing.T) {
 	}
 }
 
+func TestSetIfNotExists(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("id1"), Value: []byte("v1")}}, IfNotExists: true})
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("id1"), Value: []byte("v2")}}, IfNotExists: true})
+	require.Equal(t, ErrKeyAlreadyExists, err)
+
+	_, err = db.Set(&schema.SetRequest{
+		KVs: []*schema.KeyValue{
+			{Key: []byte("id2"), Value: []byte("v1")},
+			{Key: []byte("id1"), Value: []byte("v2")},
+		},
+		IfNotExists: true,
+	})
+	require.Equal(t, ErrKeyAlreadyExists, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("id2")})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	_, err = db.VerifiableSet(&schema.VerifiableSetRequest{
+		SetRequest: &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("id1"), Value: []byte("v2")}}, IfNotExists: true},
+	})
+	require.Equal(t, ErrKeyAlreadyExists, err)
+
+	_, err = db.VerifiableSet(&schema.VerifiableSetRequest{
+		SetRequest: &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("id2"), Value: []byte("v1")}}, IfNotExists: true},
+	})
+	require.NoError(t, err)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("id1")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v1"), entry.Value)
+}
+
 func TestTxByID(t *testing.T) {
 	db, closer := makeDb()
 	defer closer()
@@ -506,6 +543,50 @@ func TestTxScan(t *testing.T) {
 	}
 }
 
+func TestStateHistory(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	for _, val := range kvs {
+		_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: val.Key, Value: val.Value}}})
+		require.NoError(t, err)
+	}
+
+	_, err := db.StateHistory(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.StateHistory(&schema.StateHistoryRequest{
+		InitialTx: 1,
+		Limit:     MaxKeyScanLimit + 1,
+	})
+	require.Equal(t, ErrMaxKeyScanLimitExceeded, err)
+
+	stateList, err := db.StateHistory(&schema.StateHistoryRequest{InitialTx: 1})
+	require.NoError(t, err)
+	require.Equal(t, "db", stateList.Db)
+	require.Len(t, stateList.States, len(kvs)+1)
+
+	for i, hs := range stateList.States {
+		tx, err := db.TxByID(&schema.TxRequest{Tx: hs.TxId})
+		require.NoError(t, err)
+		require.Equal(t, uint64(i+1), hs.TxId)
+		require.Equal(t, tx.Metadata.Ts, hs.Ts)
+
+		alh := schema.TxMetadataFrom(tx.Metadata).Alh()
+		require.Equal(t, alh[:], hs.TxHash)
+	}
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+
+	stateList, err = db.StateHistory(&schema.StateHistoryRequest{InitialTx: state.TxId, Limit: 2, Desc: true})
+	require.NoError(t, err)
+	require.Len(t, stateList.States, 2)
+	require.Equal(t, state.TxId, stateList.States[0].TxId)
+	require.Equal(t, state.TxHash, stateList.States[0].TxHash)
+	require.Equal(t, state.TxId-1, stateList.States[1].TxId)
+}
+
 func TestHistory(t *testing.T) {
 	db, closer := makeDb()
 	defer closer()
@@ -925,3 +1006,126 @@ func TestDb_SetBatchAtomicOperations(t *testing.T) {
 	require.NoError(t, err)
 }
 */
+
+func TestReadOnlyDatabase(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	kv := &schema.KeyValue{Key: []byte("key"), Value: []byte("value")}
+
+	txMetadata, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{kv}})
+	require.NoError(t, err)
+
+	db.UpdateReadOnly(true)
+	require.True(t, db.GetOptions().GetReadOnly())
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{kv}})
+	require.Equal(t, ErrIsReadOnly, err)
+
+	_, err = db.VerifiableSet(&schema.VerifiableSetRequest{SetRequest: &schema.SetRequest{KVs: []*schema.KeyValue{kv}}})
+	require.Equal(t, ErrIsReadOnly, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte("ref"), ReferencedKey: []byte("key")})
+	require.Equal(t, ErrIsReadOnly, err)
+
+	_, err = db.ZAdd(&schema.ZAddRequest{Set: []byte("set"), Score: 1, Key: []byte("key")})
+	require.Equal(t, ErrIsReadOnly, err)
+
+	_, err = db.ExecAll(&schema.ExecAllRequest{Operations: []*schema.Op{{Operation: &schema.Op_Kv{Kv: kv}}}})
+	require.Equal(t, ErrIsReadOnly, err)
+
+	_, err = db.SQLExec(&schema.SQLExecRequest{Sql: "CREATE TABLE mytable(id INTEGER, PRIMARY KEY id)"})
+	require.Equal(t, ErrIsReadOnly, err)
+
+	// reads and proofs are still served
+	_, err = db.Get(&schema.KeyRequest{Key: kv.Key})
+	require.NoError(t, err)
+
+	_, err = db.VerifiableGet(&schema.VerifiableGetRequest{KeyRequest: &schema.KeyRequest{Key: kv.Key}})
+	require.NoError(t, err)
+
+	_, err = db.VerifiableTxByID(&schema.VerifiableTxRequest{Tx: txMetadata.Id})
+	require.NoError(t, err)
+
+	db.UpdateReadOnly(false)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{kv}})
+	require.NoError(t, err)
+}
+
+func TestRebuildIndex(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+	require.NoError(t, err)
+
+	_, err = db.SQLExec(&schema.SQLExecRequest{Sql: "CREATE TABLE mytable(id INTEGER, PRIMARY KEY id)"})
+	require.NoError(t, err)
+
+	_, err = db.SQLExec(&schema.SQLExecRequest{Sql: "INSERT INTO mytable(id) VALUES (1)"})
+	require.NoError(t, err)
+
+	verification, err := db.VerifyIndex(nil)
+	require.NoError(t, err)
+	require.True(t, verification.Consistent)
+
+	err = db.RebuildIndex()
+	require.NoError(t, err)
+
+	verification, err = db.VerifyIndex(nil)
+	require.NoError(t, err)
+	require.True(t, verification.Consistent)
+
+	item, err := db.Get(&schema.KeyRequest{Key: []byte("key")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), item.Value)
+
+	res, err := db.SQLQuery(&schema.SQLQueryRequest{Sql: "SELECT id FROM mytable"})
+	require.NoError(t, err)
+	require.Len(t, res.Rows, 1)
+}
+
+func TestQuarantinedDatabase(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	kv := &schema.KeyValue{Key: []byte("key"), Value: []byte("value")}
+
+	_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{kv}})
+	require.NoError(t, err)
+
+	db.Quarantine(true)
+	require.True(t, db.IsQuarantined())
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{kv}})
+	require.Equal(t, ErrQuarantined, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte("ref"), ReferencedKey: []byte("key")})
+	require.Equal(t, ErrQuarantined, err)
+
+	_, err = db.ZAdd(&schema.ZAddRequest{Set: []byte("set"), Score: 1, Key: []byte("key")})
+	require.Equal(t, ErrQuarantined, err)
+
+	_, err = db.ExecAll(&schema.ExecAllRequest{Operations: []*schema.Op{{Operation: &schema.Op_Kv{Kv: kv}}}})
+	require.Equal(t, ErrQuarantined, err)
+
+	_, err = db.SQLExec(&schema.SQLExecRequest{Sql: "CREATE TABLE mytable(id INTEGER, PRIMARY KEY id)"})
+	require.Equal(t, ErrQuarantined, err)
+
+	// quarantine takes precedence over the read-only mode
+	db.UpdateReadOnly(true)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{kv}})
+	require.Equal(t, ErrQuarantined, err)
+
+	db.UpdateReadOnly(false)
+
+	_, err = db.Get(&schema.KeyRequest{Key: kv.Key})
+	require.NoError(t, err)
+
+	db.Quarantine(false)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{kv}})
+	require.NoError(t, err)
+}
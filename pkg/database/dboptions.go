@@ -16,7 +16,11 @@ limitations under the License.
 
 package database
 
-import "github.com/codenotary/immudb/embedded/store"
+import (
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
 
 //DbOptions database instance options
 type DbOptions struct {
@@ -27,6 +31,20 @@ type DbOptions struct {
 	corruptionChecker bool
 
 	replicationOpts *ReplicationOptions
+
+	valueTransformers    []string
+	retainOriginalDigest bool
+
+	valueCompression string
+
+	maxDiskSize           int64
+	quotaWarningThreshold int
+
+	readOnly bool
+
+	paranoidReads bool
+
+	sqlStmtCacheSize int
 }
 
 type ReplicationOptions struct {
@@ -36,8 +54,46 @@ type ReplicationOptions struct {
 	SrcPort     int
 	FollowerUsr string
 	FollowerPwd string
+	// SyncAcks is the number of replicas which must confirm a transaction before its commit is acknowledged,
+	// zero means asynchronous replication. Replicas restricted through ReplicaFilters are not counted
+	SyncAcks int
+	// SyncTimeout is the maximum time a commit waits for the replicas, DefaultSyncTimeout is used when unset
+	SyncTimeout time.Duration
+	// SyncFallback is the behaviour once SyncTimeout expires, either SyncFallbackAsync or SyncFallbackFail
+	SyncFallback string
+	// MaxReplicaTxRate is the maximum number of transactions per second exported to each replica, zero means unlimited
+	MaxReplicaTxRate int
+	// MaxReplicaByteRate is the maximum number of bytes per second exported to each replica, zero means unlimited
+	MaxReplicaByteRate int
+	// MaxReplicationTxRate is the maximum number of transactions per second exported to all the replicas together,
+	// zero means unlimited
+	MaxReplicationTxRate int
+	// MaxReplicationByteRate is the maximum number of bytes per second exported to all the replicas together,
+	// zero means unlimited
+	MaxReplicationByteRate int
+	// ReplicaFilters restricts the entries exported to the given replicas to the keys under the configured prefixes
+	ReplicaFilters map[string][][]byte
+	// Partial replicas apply the entries shipped by a filtered source as their own transactions
+	Partial bool
+	// TLSCert and TLSKey are the files of the client certificate presented to the source database, enabling mutual TLS
+	TLSCert string
+	TLSKey  string
+	// TLSCA is the file of the certificate authority the certificate of the source is verified against, enabling TLS
+	TLSCA string
+	// TLSServerName is the name verified in the certificate of the source, its address is used when unset
+	TLSServerName string
 }
 
+const (
+	// SyncFallbackAsync acknowledges the commit anyway once the replicas timed out, as asynchronous replication would
+	SyncFallbackAsync = "async"
+	// SyncFallbackFail reports the commit as failed once the replicas timed out, the transaction stays committed locally
+	SyncFallbackFail = "fail"
+)
+
+// DefaultSyncTimeout is the maximum time a commit waits for the replicas under synchronous replication
+const DefaultSyncTimeout = 5 * time.Second
+
 // DefaultOption Initialise Db Optionts to default values
 func DefaultOption() *DbOptions {
 	return &DbOptions{
@@ -45,6 +101,9 @@ func DefaultOption() *DbOptions {
 		dbName:          "db_name",
 		storeOpts:       store.DefaultOptions(),
 		replicationOpts: &ReplicationOptions{},
+
+		quotaWarningThreshold: DefaultQuotaWarningThreshold,
+		sqlStmtCacheSize:      DefaultSQLStmtCacheSize,
 	}
 }
 
@@ -81,6 +140,95 @@ func (o *DbOptions) GetCorruptionChecker() bool {
 	return o.corruptionChecker
 }
 
+// WithValueTransformers sets the ordered list of registered transformers applied to values before commit
+func (o *DbOptions) WithValueTransformers(valueTransformers []string) *DbOptions {
+	o.valueTransformers = valueTransformers
+	return o
+}
+
+// GetValueTransformers returns the ordered list of transformers applied to values before commit
+func (o *DbOptions) GetValueTransformers() []string {
+	return o.valueTransformers
+}
+
+// WithRetainOriginalDigest sets if the digest of values modified by the transformers is kept in the entry metadata
+func (o *DbOptions) WithRetainOriginalDigest(retainOriginalDigest bool) *DbOptions {
+	o.retainOriginalDigest = retainOriginalDigest
+	return o
+}
+
+// GetRetainOriginalDigest returns if the digest of values modified by the transformers is kept in the entry metadata
+func (o *DbOptions) GetRetainOriginalDigest() bool {
+	return o.retainOriginalDigest
+}
+
+// WithValueCompression sets the algorithm (zstd or snappy) used to compress values on disk, empty means no compression.
+// It only applies when the database gets created
+func (o *DbOptions) WithValueCompression(valueCompression string) *DbOptions {
+	o.valueCompression = valueCompression
+	return o
+}
+
+// GetValueCompression returns the algorithm used to compress values on disk
+func (o *DbOptions) GetValueCompression() string {
+	return o.valueCompression
+}
+
+// WithMaxDiskSize sets the maximum on-disk size in bytes, writes are rejected once reached. Zero means no limit
+func (o *DbOptions) WithMaxDiskSize(maxDiskSize int64) *DbOptions {
+	o.maxDiskSize = maxDiskSize
+	return o
+}
+
+// GetMaxDiskSize returns the maximum on-disk size in bytes
+func (o *DbOptions) GetMaxDiskSize() int64 {
+	return o.maxDiskSize
+}
+
+// WithQuotaWarningThreshold sets the percentage of the maximum on-disk size at which a warning is logged
+func (o *DbOptions) WithQuotaWarningThreshold(quotaWarningThreshold int) *DbOptions {
+	o.quotaWarningThreshold = quotaWarningThreshold
+	return o
+}
+
+// GetQuotaWarningThreshold returns the percentage of the maximum on-disk size at which a warning is logged
+func (o *DbOptions) GetQuotaWarningThreshold() int {
+	return o.quotaWarningThreshold
+}
+
+// WithReadOnly sets if mutating operations are rejected while reads and proofs are still served
+func (o *DbOptions) WithReadOnly(readOnly bool) *DbOptions {
+	o.readOnly = readOnly
+	return o
+}
+
+// GetReadOnly returns if mutating operations are rejected
+func (o *DbOptions) GetReadOnly() bool {
+	return o.readOnly
+}
+
+// WithParanoidReads sets if every entry returned by Get and Scan is verified against the database state before being served
+func (o *DbOptions) WithParanoidReads(paranoidReads bool) *DbOptions {
+	o.paranoidReads = paranoidReads
+	return o
+}
+
+// GetParanoidReads returns if read entries are verified against the database state
+func (o *DbOptions) GetParanoidReads() bool {
+	return o.paranoidReads
+}
+
+// WithSQLStmtCacheSize sets the number of parsed SQL statements kept for reuse, zero disables the cache
+func (o *DbOptions) WithSQLStmtCacheSize(sqlStmtCacheSize int) *DbOptions {
+	o.sqlStmtCacheSize = sqlStmtCacheSize
+	return o
+}
+
+// GetSQLStmtCacheSize returns the number of parsed SQL statements kept for reuse
+func (o *DbOptions) GetSQLStmtCacheSize() int {
+	return o.sqlStmtCacheSize
+}
+
 // WithStoreOptions sets backing store options
 func (o *DbOptions) WithStoreOptions(storeOpts *store.Options) *DbOptions {
 	o.storeOpts = storeOpts
@@ -109,6 +257,12 @@ func (o *ReplicationOptions) AsReplica(replica bool) *ReplicationOptions {
 	return o
 }
 
+// AsPartial sets if the database is a replica holding only the keys its source filters for it
+func (o *ReplicationOptions) AsPartial(partial bool) *ReplicationOptions {
+	o.Partial = partial
+	return o
+}
+
 // WithSrcDatabase sets the source database name
 func (o *ReplicationOptions) WithSrcDatabase(srcDatabase string) *ReplicationOptions {
 	o.SrcDatabase = srcDatabase
@@ -138,3 +292,75 @@ func (o *ReplicationOptions) WithFollowerPwd(followerPwd string) *ReplicationOpt
 	o.FollowerPwd = followerPwd
 	return o
 }
+
+// WithSyncAcks sets the number of replicas which must confirm a transaction before its commit is acknowledged
+func (o *ReplicationOptions) WithSyncAcks(syncAcks int) *ReplicationOptions {
+	o.SyncAcks = syncAcks
+	return o
+}
+
+// WithSyncTimeout sets the maximum time a commit waits for the replicas to confirm it
+func (o *ReplicationOptions) WithSyncTimeout(syncTimeout time.Duration) *ReplicationOptions {
+	o.SyncTimeout = syncTimeout
+	return o
+}
+
+// WithSyncFallback sets the behaviour once the replicas timed out, either SyncFallbackAsync or SyncFallbackFail
+func (o *ReplicationOptions) WithSyncFallback(syncFallback string) *ReplicationOptions {
+	o.SyncFallback = syncFallback
+	return o
+}
+
+// WithMaxReplicaTxRate sets the maximum number of transactions per second exported to each replica
+func (o *ReplicationOptions) WithMaxReplicaTxRate(maxReplicaTxRate int) *ReplicationOptions {
+	o.MaxReplicaTxRate = maxReplicaTxRate
+	return o
+}
+
+// WithMaxReplicaByteRate sets the maximum number of bytes per second exported to each replica
+func (o *ReplicationOptions) WithMaxReplicaByteRate(maxReplicaByteRate int) *ReplicationOptions {
+	o.MaxReplicaByteRate = maxReplicaByteRate
+	return o
+}
+
+// WithMaxReplicationTxRate sets the maximum number of transactions per second exported to all the replicas together
+func (o *ReplicationOptions) WithMaxReplicationTxRate(maxReplicationTxRate int) *ReplicationOptions {
+	o.MaxReplicationTxRate = maxReplicationTxRate
+	return o
+}
+
+// WithMaxReplicationByteRate sets the maximum number of bytes per second exported to all the replicas together
+func (o *ReplicationOptions) WithMaxReplicationByteRate(maxReplicationByteRate int) *ReplicationOptions {
+	o.MaxReplicationByteRate = maxReplicationByteRate
+	return o
+}
+
+// WithReplicaFilters sets the key prefixes exported to each filtered replica
+func (o *ReplicationOptions) WithReplicaFilters(replicaFilters map[string][][]byte) *ReplicationOptions {
+	o.ReplicaFilters = replicaFilters
+	return o
+}
+
+// WithTLSCert sets the file of the client certificate presented to the source database
+func (o *ReplicationOptions) WithTLSCert(tlsCert string) *ReplicationOptions {
+	o.TLSCert = tlsCert
+	return o
+}
+
+// WithTLSKey sets the file of the private key of the client certificate presented to the source database
+func (o *ReplicationOptions) WithTLSKey(tlsKey string) *ReplicationOptions {
+	o.TLSKey = tlsKey
+	return o
+}
+
+// WithTLSCA sets the file of the certificate authority the certificate of the source database is verified against
+func (o *ReplicationOptions) WithTLSCA(tlsCA string) *ReplicationOptions {
+	o.TLSCA = tlsCA
+	return o
+}
+
+// WithTLSServerName sets the name verified in the certificate of the source database
+func (o *ReplicationOptions) WithTLSServerName(tlsServerName string) *ReplicationOptions {
+	o.TLSServerName = tlsServerName
+	return o
+}
@@ -34,6 +34,8 @@ func TestDefaultOptions(t *testing.T) {
 	if op.GetCorruptionChecker() {
 		t.Errorf("default corruption checker not what expected")
 	}
+	require.Equal(t, int64(0), op.GetMaxDiskSize())
+	require.Equal(t, DefaultQuotaWarningThreshold, op.GetQuotaWarningThreshold())
 
 	DbName := "Charles_Aznavour"
 	rootpath := "rootpath"
@@ -52,7 +54,10 @@ func TestDefaultOptions(t *testing.T) {
 		WithDbRootPath(rootpath).
 		WithCorruptionChecker(true).
 		WithStoreOptions(storeOpts).
-		WithReplicationOptions(replicaOpts)
+		WithReplicationOptions(replicaOpts).
+		WithMaxDiskSize(1024).
+		WithQuotaWarningThreshold(80).
+		WithReadOnly(true)
 
 	if op.GetDbName() != DbName {
 		t.Errorf("db name not set correctly , expected %s got %s", DbName, op.GetDbName())
@@ -67,4 +72,7 @@ func TestDefaultOptions(t *testing.T) {
 
 	require.Equal(t, storeOpts, op.storeOpts)
 	require.Equal(t, replicaOpts, op.GetReplicationOptions())
+	require.Equal(t, int64(1024), op.GetMaxDiskSize())
+	require.Equal(t, 80, op.GetQuotaWarningThreshold())
+	require.True(t, op.GetReadOnly())
 }
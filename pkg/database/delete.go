@@ -0,0 +1,118 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// Delete logically deletes the keys by appending a tombstone for each of them.
+// Deleted keys are no longer returned by Get or Scan, but their previous revisions remain in the history
+func (d *db) Delete(req *schema.DeleteKeysRequest) (res *schema.TxMetadata, err error) {
+	defer func() { err = d.awaitReplicas(res.GetId(), err) }()
+
+	if req == nil || len(req.Keys) == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	for _, k := range req.Keys {
+		if len(k) == 0 {
+			return nil, ErrIllegalArguments
+		}
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.isReplica() {
+		return nil, ErrIsReplica
+	}
+
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
+	lastTxID, _ := d.st.Alh()
+
+	err = d.st.WaitForIndexingUpto(lastTxID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*store.KV, len(req.Keys))
+
+	for i, k := range req.Keys {
+		txID, err := d.liveTxOf(EncodeKey(k))
+		if err != nil {
+			return nil, err
+		}
+
+		// the key must not be updated or deleted by someone else before the tombstone gets committed
+		e := EncodeTombstone(k)
+		e.Constraint = store.MustMatchTx
+		e.ExpectedTx = txID
+
+		entries[i] = e
+	}
+
+	md, err := d.st.Commit(entries, !req.NoWait)
+	if err == store.ErrPreconditionFailed {
+		return nil, ErrPreconditionFailed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.TxMetatadaTo(md), nil
+}
+
+// liveTxOf returns the transaction which wrote the current value of a key, provided it's neither deleted nor expired
+func (d *db) liveTxOf(key []byte) (uint64, error) {
+	val, txID, _, err := d.st.Get(key)
+	if err != nil {
+		return 0, err
+	}
+
+	md, _, err := UnwrapMetadata(val)
+	if err != nil {
+		return 0, err
+	}
+
+	if isHidden(md) {
+		return 0, store.ErrKeyNotFound
+	}
+
+	deleted, err := d.deletedByPrefix(key, txID, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	if deleted {
+		return 0, store.ErrKeyNotFound
+	}
+
+	return txID, nil
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// prefixTombstones keeps the transactions where each prefix was deleted, in a trie walked along the read keys.
+// It's lazily loaded from the store and then updated as tombstones are committed or replicated
+type prefixTombstones struct {
+	mutex  sync.RWMutex
+	loaded bool
+	root   tombstoneNode
+}
+
+// tombstoneNode is the node of the deleted prefix spelled by the path from the root of the trie
+type tombstoneNode struct {
+	children map[byte]*tombstoneNode
+	// txs are the transactions the prefix was deleted at, in ascending order
+	txs []uint64
+}
+
+// add records the tombstone of the prefix committed at txID, it must be called holding the mutex
+func (pt *prefixTombstones) add(prefix []byte, txID uint64) {
+	n := &pt.root
+
+	for _, b := range prefix {
+		child, ok := n.children[b]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[byte]*tombstoneNode)
+			}
+
+			child = &tombstoneNode{}
+			n.children[b] = child
+		}
+
+		n = child
+	}
+
+	i := sort.Search(len(n.txs), func(i int) bool { return n.txs[i] >= txID })
+	if i < len(n.txs) && n.txs[i] == txID {
+		return
+	}
+
+	n.txs = append(n.txs, 0)
+	copy(n.txs[i+1:], n.txs[i:])
+	n.txs[i] = txID
+}
+
+// addEntries records the prefix tombstones among the entries committed at txID, it must be called holding the mutex.
+// Nothing is recorded until tombstones are loaded, as loading reads them from the store
+func (pt *prefixTombstones) addEntries(entries []*store.KV, txID uint64) {
+	if !pt.loaded {
+		return
+	}
+
+	for _, e := range entries {
+		if len(e.Key) > 0 && e.Key[0] == PrefixTombstoneKeyPrefix {
+			pt.add(e.Key[1:], txID)
+		}
+	}
+}
+
+// covers returns true when any prefix of the key was deleted after ktx and, unless atTx is zero, up to atTx.
+// It must be called holding the mutex
+func (pt *prefixTombstones) covers(key []byte, ktx, atTx uint64) bool {
+	n := &pt.root
+
+	for i := 0; ; i++ {
+		j := sort.Search(len(n.txs), func(j int) bool { return n.txs[j] > ktx })
+		if j < len(n.txs) && (atTx == 0 || n.txs[j] <= atTx) {
+			return true
+		}
+
+		if i == len(key) {
+			return false
+		}
+
+		child, ok := n.children[key[i]]
+		if !ok {
+			return false
+		}
+
+		n = child
+	}
+}
+
+// DeletePrefix logically deletes all the keys under the prefix with a single prefix tombstone.
+// Keys written before the tombstone are no longer returned by Get or Scan, but remain in the history
+func (d *db) DeletePrefix(req *schema.DeletePrefixRequest) (res *schema.TxMetadata, err error) {
+	defer func() { err = d.awaitReplicas(res.GetId(), err) }()
+
+	if req == nil || len(req.Prefix) == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.isReplica() {
+		return nil, ErrIsReplica
+	}
+
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
+	entries := []*store.KV{
+		{
+			Key:   WrapWithPrefix(req.Prefix, PrefixTombstoneKeyPrefix),
+			Value: []byte{PlainValuePrefix},
+		},
+	}
+
+	// the tombstone is recorded before readers can see the transaction
+	d.prefixTombstones.mutex.Lock()
+
+	md, err := d.st.Commit(entries, false)
+	if err == nil {
+		d.prefixTombstones.addEntries(entries, md.ID)
+	}
+
+	d.prefixTombstones.mutex.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.NoWait {
+		err = d.st.WaitForIndexingUpto(md.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return schema.TxMetatadaTo(md), nil
+}
+
+// deletedByPrefix returns true when the encoded key, written at ktx, is covered by a prefix tombstone
+// committed after it. Only tombstones up to atTx are considered, unless atTx is zero
+func (d *db) deletedByPrefix(key []byte, ktx, atTx uint64) (bool, error) {
+	err := d.loadPrefixTombstones()
+	if err != nil {
+		return false, err
+	}
+
+	pt := &d.prefixTombstones
+
+	pt.mutex.RLock()
+	defer pt.mutex.RUnlock()
+
+	return pt.covers(key[1:], ktx, atTx), nil
+}
+
+// recordPrefixTombstones records the prefix tombstones among the entries of a transaction committed at txID
+// other than through DeletePrefix, as the replicated ones
+func (d *db) recordPrefixTombstones(entries []*store.KV, txID uint64) {
+	pt := &d.prefixTombstones
+
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	pt.addEntries(entries, txID)
+}
+
+// loadPrefixTombstones reads the prefix tombstones from the index, and from the transactions not indexed yet
+func (d *db) loadPrefixTombstones() error {
+	pt := &d.prefixTombstones
+
+	pt.mutex.RLock()
+	loaded := pt.loaded
+	pt.mutex.RUnlock()
+
+	if loaded {
+		return nil
+	}
+
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	if pt.loaded {
+		return nil
+	}
+
+	snap, err := d.st.Snapshot()
+	if err != nil {
+		return err
+	}
+	defer snap.Close()
+
+	r, err := snap.NewKeyReader(&store.KeyReaderSpec{Prefix: []byte{PrefixTombstoneKeyPrefix}})
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for {
+		key, _, _, _, err := r.Read()
+		if err == store.ErrNoMoreEntries {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		// all the tombstones of the prefix are needed to resolve reads at previous transactions
+		txs, err := d.st.History(key, 0, false, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+
+		for _, ptx := range txs {
+			// later transactions are read below
+			if ptx <= snap.Ts() {
+				pt.add(key[1:], ptx)
+			}
+		}
+	}
+
+	lastTxID, _ := d.st.Alh()
+
+	tx := d.st.NewTx()
+
+	for txID := snap.Ts() + 1; txID <= lastTxID; txID++ {
+		err := d.st.ReadTx(txID, tx)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range tx.Entries() {
+			if e.Key()[0] == PrefixTombstoneKeyPrefix {
+				pt.add(e.Key()[1:], txID)
+			}
+		}
+	}
+
+	pt.loaded = true
+
+	return nil
+}
+
+// keyTxOf returns the transaction where the key of the entry was written, the reference one for resolved entries
+func keyTxOf(e *schema.Entry) uint64 {
+	if e.ReferencedBy != nil {
+		return e.ReferencedBy.Tx
+	}
+
+	return e.Tx
+}
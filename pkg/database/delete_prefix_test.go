@@ -0,0 +1,204 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletePrefix(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.DeletePrefix(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.DeletePrefix(&schema.DeletePrefixRequest{})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	md, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte("tenant1/k1"), Value: []byte("v1")},
+		{Key: []byte("tenant1/k2"), Value: []byte("v2")},
+		{Key: []byte("tenant2/k1"), Value: []byte("v3")},
+	}})
+	require.NoError(t, err)
+
+	_, err = db.OpenSnapshot(&schema.OpenSnapshotRequest{Name: "before"})
+	require.NoError(t, err)
+
+	dmd, err := db.DeletePrefix(&schema.DeletePrefixRequest{Prefix: []byte("tenant1/")})
+	require.NoError(t, err)
+	require.Equal(t, md.Id+1, dmd.Id)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("tenant1/k1")})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("tenant2/k1")})
+	require.NoError(t, err)
+
+	// previous revisions remain available
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("tenant1/k1"), AtTx: md.Id})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v1"), entry.Value)
+
+	list, err := db.Scan(&schema.ScanRequest{Prefix: []byte("tenant")})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 1)
+	require.Equal(t, []byte("tenant2/k1"), list.Entries[0].Key)
+
+	list, err = db.Scan(&schema.ScanRequest{Prefix: []byte("tenant"), IncludeDeleted: true})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 3)
+
+	list, err = db.Scan(&schema.ScanRequest{Prefix: []byte("tenant"), Snapshot: "before"})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 3)
+
+	_, err = db.Delete(&schema.DeleteKeysRequest{Keys: [][]byte{[]byte("tenant1/k2")}})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	// keys written after the prefix tombstone are visible
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("tenant1/k1"), Value: []byte("v4")}}})
+	require.NoError(t, err)
+
+	entry, err = db.Get(&schema.KeyRequest{Key: []byte("tenant1/k1")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v4"), entry.Value)
+
+	var replayed []*schema.TxEntries
+
+	err = db.StreamTxs(&schema.StreamTxsRequest{SinceTx: dmd.Id, NoFollow: true}, nil, func(txe *schema.TxEntries) error {
+		replayed = append(replayed, txe)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("tenant1/")}, replayed[0].DeletedPrefixes)
+	require.Empty(t, replayed[0].Entries)
+}
+
+func TestDeletePrefixAfterReopening(t *testing.T) {
+	options := DefaultOption().WithDbRootPath("data_delete_prefix").WithDbName("db")
+	defer os.RemoveAll(options.GetDbRootPath())
+
+	db, err := NewDb(options, nil, logger.NewSimpleLogger("immudb ", os.Stderr))
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte("tenant1/k1"), Value: []byte("v1")},
+		{Key: []byte("tenant2/k1"), Value: []byte("v2")},
+	}})
+	require.NoError(t, err)
+
+	_, err = db.DeletePrefix(&schema.DeletePrefixRequest{Prefix: []byte("tenant1/")})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Close())
+
+	db, err = OpenDb(options, nil, logger.NewSimpleLogger("immudb ", os.Stderr))
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("tenant1/k1"), SinceTx: 2})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("tenant2/k1")})
+	require.NoError(t, err)
+}
+
+func TestDeletePrefixReplicated(t *testing.T) {
+	primary, closePrimary := makeDb()
+	defer closePrimary()
+
+	replicaOpts := DefaultOption().
+		WithDbRootPath("data_" + strconv.FormatInt(time.Now().UnixNano(), 10)).
+		WithDbName("replica").
+		WithReplicationOptions((&ReplicationOptions{}).AsReplica(true))
+
+	replica, closeReplica := makeDbWith(replicaOpts)
+	defer closeReplica()
+
+	replicatedTxID := uint64(0)
+
+	replicate := func() {
+		state, err := primary.CurrentState()
+		require.NoError(t, err)
+
+		for ; replicatedTxID < state.TxId; replicatedTxID++ {
+			etx, err := primary.ExportTxByID(&schema.TxRequest{Tx: replicatedTxID + 1})
+			require.NoError(t, err)
+
+			// the replica may not have linked the previous transactions yet
+			require.Eventually(t, func() bool {
+				_, err = replica.ReplicateTx(etx)
+				return err == nil
+			}, 5*time.Second, time.Millisecond)
+		}
+	}
+
+	_, err := primary.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("tenant1/k1"), Value: []byte("v1")}}})
+	require.NoError(t, err)
+
+	replicate()
+
+	// tombstones get loaded by the first read
+	_, err = replica.Get(&schema.KeyRequest{Key: []byte("tenant1/k1"), SinceTx: replicatedTxID})
+	require.NoError(t, err)
+
+	_, err = primary.DeletePrefix(&schema.DeletePrefixRequest{Prefix: []byte("tenant1/")})
+	require.NoError(t, err)
+
+	replicate()
+
+	_, err = replica.Get(&schema.KeyRequest{Key: []byte("tenant1/k1"), SinceTx: replicatedTxID})
+	require.Equal(t, store.ErrKeyNotFound, err)
+}
+
+func TestPrefixTombstones(t *testing.T) {
+	var pt prefixTombstones
+
+	pt.addEntries([]*store.KV{{Key: WrapWithPrefix([]byte("a/"), PrefixTombstoneKeyPrefix)}}, 1)
+	require.False(t, pt.covers([]byte("a/b"), 0, 0))
+
+	pt.loaded = true
+
+	pt.addEntries([]*store.KV{
+		{Key: WrapWithPrefix([]byte("a/"), PrefixTombstoneKeyPrefix)},
+		{Key: WrapWithPrefix([]byte("k"), SetKeyPrefix)},
+	}, 5)
+	pt.add([]byte("a/b/"), 10)
+	pt.add([]byte("a/"), 3)
+	pt.add([]byte("a/"), 5)
+
+	require.Equal(t, []uint64{3, 5}, pt.root.children['a'].children['/'].txs)
+
+	require.True(t, pt.covers([]byte("a/x"), 4, 0))
+	require.False(t, pt.covers([]byte("a/x"), 5, 0))
+	require.False(t, pt.covers([]byte("a/x"), 3, 4))
+	require.True(t, pt.covers([]byte("a/x"), 3, 5))
+	require.True(t, pt.covers([]byte("a/b/c"), 5, 0))
+	require.False(t, pt.covers([]byte("a/b/c"), 5, 9))
+	require.False(t, pt.covers([]byte("a"), 0, 0))
+	require.False(t, pt.covers([]byte("b/a/"), 0, 0))
+}
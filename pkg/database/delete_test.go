@@ -0,0 +1,117 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelete(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.Delete(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.Delete(&schema.DeleteKeysRequest{Keys: [][]byte{nil}})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.Delete(&schema.DeleteKeysRequest{Keys: [][]byte{[]byte("key1")}})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	md1, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte("key1"), Value: []byte("value1")},
+		{Key: []byte("key2"), Value: []byte("value2")},
+	}})
+	require.NoError(t, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte("ref1"), ReferencedKey: []byte("key1")})
+	require.NoError(t, err)
+
+	mdDel, err := db.Delete(&schema.DeleteKeysRequest{Keys: [][]byte{[]byte("key1")}})
+	require.NoError(t, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("key1"), SinceTx: mdDel.Id})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("ref1")})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("key1"), AtTx: mdDel.Id})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("key1"), AtTx: md1.Id})
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), entry.Value)
+
+	_, err = db.VerifiableGet(&schema.VerifiableGetRequest{
+		KeyRequest:   &schema.KeyRequest{Key: []byte("key1"), AtTx: md1.Id},
+		ProveSinceTx: mdDel.Id,
+	})
+	require.NoError(t, err)
+
+	_, err = db.Delete(&schema.DeleteKeysRequest{Keys: [][]byte{[]byte("key1")}})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	history, err := db.History(&schema.HistoryRequest{Key: []byte("key1")})
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 2)
+	require.Equal(t, []byte("value1"), history.Entries[0].Value)
+	require.False(t, history.Entries[0].Metadata.GetDeleted())
+	require.Empty(t, history.Entries[1].Value)
+	require.True(t, history.Entries[1].Metadata.GetDeleted())
+	require.Equal(t, mdDel.Id, history.Entries[1].Tx)
+
+	scan, err := db.Scan(&schema.ScanRequest{Prefix: []byte("key")})
+	require.NoError(t, err)
+	require.Len(t, scan.Entries, 1)
+	require.Equal(t, []byte("key2"), scan.Entries[0].Key)
+
+	scan, err = db.Scan(&schema.ScanRequest{Prefix: []byte("key"), IncludeDeleted: true})
+	require.NoError(t, err)
+	require.Len(t, scan.Entries, 2)
+	require.Equal(t, []byte("key1"), scan.Entries[0].Key)
+	require.True(t, scan.Entries[0].Metadata.GetDeleted())
+
+	scan, err = db.Scan(&schema.ScanRequest{Prefix: []byte("ref")})
+	require.NoError(t, err)
+	require.Empty(t, scan.Entries)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value3")}}})
+	require.NoError(t, err)
+
+	entry, err = db.Get(&schema.KeyRequest{Key: []byte("key1")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("value3"), entry.Value)
+	require.Nil(t, entry.Metadata)
+}
+
+func TestTombstoneMetadataEncoding(t *testing.T) {
+	kv := EncodeTombstone([]byte("key1"))
+
+	md, val, err := UnwrapMetadata(kv.Value)
+	require.NoError(t, err)
+	require.True(t, md.Deleted)
+	require.Equal(t, []byte{PlainValuePrefix}, val)
+
+	_, err = DecodeMetadata([]byte{mdAttrDeleted, 0, 0, 0, 1, 1})
+	require.Equal(t, ErrInvalidMetadata, err)
+}
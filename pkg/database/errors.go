@@ -22,10 +22,29 @@ import (
 )
 
 var (
-	ErrIndexKeyMismatch      = status.New(codes.InvalidArgument, "mismatch between provided index and key").Err()
-	ErrNoReferenceProvided   = status.New(codes.InvalidArgument, "provided argument is not a reference").Err()
-	ErrReferenceKeyMissing   = status.New(codes.InvalidArgument, "reference key not provided").Err()
-	ErrZAddIndexMissing      = status.New(codes.InvalidArgument, "zAdd index not provided").Err()
-	ErrReferenceIndexMissing = status.New(codes.InvalidArgument, "reference index not provided").Err()
-	ErrDatabaseNotExists     = status.New(codes.NotFound, "database does not exist").Err()
+	ErrIndexKeyMismatch       = status.New(codes.InvalidArgument, "mismatch between provided index and key").Err()
+	ErrNoReferenceProvided    = status.New(codes.InvalidArgument, "provided argument is not a reference").Err()
+	ErrReferenceKeyMissing    = status.New(codes.InvalidArgument, "reference key not provided").Err()
+	ErrZAddIndexMissing       = status.New(codes.InvalidArgument, "zAdd index not provided").Err()
+	ErrReferenceIndexMissing  = status.New(codes.InvalidArgument, "reference index not provided").Err()
+	ErrDatabaseNotExists      = status.New(codes.NotFound, "database does not exist").Err()
+	ErrQuotaExceeded          = status.New(codes.ResourceExhausted, "database storage quota exceeded").Err()
+	ErrIsReadOnly             = status.New(codes.FailedPrecondition, "database is read-only").Err()
+	ErrPreconditionFailed     = status.New(codes.FailedPrecondition, "precondition failed: key does not hold the expected value").Err()
+	ErrKeyAlreadyExists       = status.New(codes.AlreadyExists, "key already exists").Err()
+	ErrTxClosed               = status.New(codes.FailedPrecondition, "transaction already committed or rolled back").Err()
+	ErrTxReadConflict         = status.New(codes.Aborted, "transaction aborted: keys read by the transaction were modified").Err()
+	ErrInvalidCursor          = status.New(codes.InvalidArgument, "invalid scan cursor").Err()
+	ErrInvalidKeyPattern      = status.New(codes.InvalidArgument, "invalid key pattern").Err()
+	ErrInvalidValueFilter     = status.New(codes.InvalidArgument, "invalid value filter").Err()
+	ErrInvalidKVAttributes    = status.New(codes.InvalidArgument, "invalid key-value attributes").Err()
+	ErrSnapshotNotFound       = status.New(codes.NotFound, "snapshot not found").Err()
+	ErrSnapshotAlreadyExists  = status.New(codes.AlreadyExists, "snapshot already exists").Err()
+	ErrMaxSnapshotsExceeded   = status.New(codes.ResourceExhausted, "max number of open snapshots exceeded").Err()
+	ErrCorruptedRead          = status.New(codes.DataLoss, "read entry could not be verified against the database state").Err()
+	ErrQuarantined            = status.New(codes.DataLoss, "database is quarantined because corruption was detected, writes are rejected until it is acknowledged").Err()
+	ErrSyncReplicationTimeout = status.New(codes.DeadlineExceeded, "transaction committed but not confirmed by enough replicas in time").Err()
+	ErrReplicaDiverged        = status.New(codes.DataLoss, "confirmed transaction does not match the local one").Err()
+	ErrReplicationStopped     = status.New(codes.FailedPrecondition, "replication stopped, the replica diverges from the database").Err()
+	ErrReplicaBehind          = status.New(codes.FailedPrecondition, "the replica has not replicated the required transactions yet").Err()
 )
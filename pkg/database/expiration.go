@@ -0,0 +1,221 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// ExpirableSet sets the key-value pairs along with an expiration, expressed as unix seconds.
+// Once expired, the keys are no longer returned by Get or Scan but their revisions remain in the history
+func (d *db) ExpirableSet(req *schema.ExpirableSetRequest) (res *schema.TxMetadata, err error) {
+	defer func() { err = d.awaitReplicas(res.GetId(), err) }()
+
+	if req == nil || len(req.KVs) == 0 || req.ExpiresAt <= 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.isReplica() {
+		return nil, ErrIsReplica
+	}
+
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*store.KV, len(req.KVs))
+
+	for i, kv := range req.KVs {
+		if len(kv.Key) == 0 {
+			return nil, ErrIllegalArguments
+		}
+
+		md, err := attributesMetadata(kv.Attributes)
+		if err != nil {
+			return nil, err
+		}
+
+		if md == nil {
+			md = &schema.KVMetadata{}
+		}
+		md.ExpiresAt = req.ExpiresAt
+
+		e, err := d.encodeKVWithMetadata(kv.Key, kv.Value, false, md)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[i] = e
+	}
+
+	md, err := d.st.Commit(entries, !req.NoWait)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.TxMetatadaTo(md), nil
+}
+
+// SweepExpiredKeys appends a tombstone for up to limit expired keys, so they are no longer
+// part of the current state of the database. It returns the number of keys deleted
+func (d *db) SweepExpiredKeys(limit int) (int, error) {
+	if limit <= 0 {
+		return 0, ErrIllegalArguments
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.isReplica() || d.options.readOnly || d.quarantined {
+		return 0, nil
+	}
+
+	lastTxID, _ := d.st.Alh()
+
+	err := d.st.WaitForIndexingUpto(lastTxID, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	entries, err := d.expiredEntries(lastTxID, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.commitTombstones(entries)
+}
+
+// commitTombstones appends the tombstones of the expired keys. The keys updated since they were found expired are
+// left to the next sweep, the tombstones of the other ones are committed anyway. It returns the number of keys deleted
+func (d *db) commitTombstones(entries []*store.KV) (int, error) {
+	for len(entries) > 0 {
+		_, err := d.st.Commit(entries, true)
+		if err == nil {
+			break
+		}
+		if err != store.ErrPreconditionFailed {
+			return 0, err
+		}
+
+		entries, err = d.unchangedEntries(entries)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(entries), nil
+}
+
+// unchangedEntries filters out the tombstones of the keys updated after the transaction they are expected at
+func (d *db) unchangedEntries(entries []*store.KV) ([]*store.KV, error) {
+	lastTxID, _ := d.st.Alh()
+
+	err := d.st.WaitForIndexingUpto(lastTxID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var unchanged []*store.KV
+
+	for _, e := range entries {
+		_, tx, _, err := d.st.Get(e.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if tx == e.ExpectedTx {
+			unchanged = append(unchanged, e)
+		}
+	}
+
+	return unchanged, nil
+}
+
+// expiredEntries returns the tombstones of up to limit keys which are expired at txID
+func (d *db) expiredEntries(txID uint64, limit int) ([]*store.KV, error) {
+	snap, err := d.st.SnapshotSince(txID)
+	if err != nil {
+		return nil, err
+	}
+	defer snap.Close()
+
+	r, err := snap.NewKeyReader(&store.KeyReaderSpec{Prefix: []byte{SetKeyPrefix}})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	now := time.Now()
+
+	var entries []*store.KV
+
+	for len(entries) < limit {
+		key, valRef, ktx, _, err := r.Read()
+		if err == store.ErrNoMoreEntries {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := valRef.Resolve()
+		if err != nil {
+			return nil, err
+		}
+
+		md, _, err := UnwrapMetadata(val)
+		if err != nil {
+			return nil, err
+		}
+
+		if md.GetDeleted() || !isExpired(md, now) {
+			continue
+		}
+
+		e := EncodeTombstone(TrimPrefix(key))
+		e.Constraint = store.MustMatchTx
+		e.ExpectedTx = ktx
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// isExpired returns true when the entry has an expiration which is already due
+func isExpired(md *schema.KVMetadata, now time.Time) bool {
+	return md.GetExpiresAt() > 0 && now.Unix() >= md.ExpiresAt
+}
+
+// isHidden returns true when the entry is not part of the current state, either deleted or expired
+func isHidden(md *schema.KVMetadata) bool {
+	return md.GetDeleted() || isExpired(md, time.Now())
+}
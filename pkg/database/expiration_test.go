@@ -0,0 +1,181 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpirableSet(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.ExpirableSet(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.ExpirableSet(&schema.ExpirableSetRequest{KVs: []*schema.KeyValue{{Key: []byte("session1"), Value: []byte("v")}}})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.ExpirableSet(&schema.ExpirableSetRequest{KVs: []*schema.KeyValue{{Value: []byte("v")}}, ExpiresAt: 1})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	expiresAt := time.Now().Add(time.Hour).Unix()
+
+	_, err = db.ExpirableSet(&schema.ExpirableSetRequest{
+		KVs:       []*schema.KeyValue{{Key: []byte("session1"), Value: []byte("active")}},
+		ExpiresAt: expiresAt,
+	})
+	require.NoError(t, err)
+
+	md, err := db.ExpirableSet(&schema.ExpirableSetRequest{
+		KVs:       []*schema.KeyValue{{Key: []byte("session2"), Value: []byte("active")}},
+		ExpiresAt: time.Now().Add(-time.Second).Unix(),
+	})
+	require.NoError(t, err)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("session1"), SinceTx: md.Id})
+	require.NoError(t, err)
+	require.Equal(t, []byte("active"), entry.Value)
+	require.Equal(t, expiresAt, entry.Metadata.ExpiresAt)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("session2")})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	history, err := db.History(&schema.HistoryRequest{Key: []byte("session2")})
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 1)
+	require.Equal(t, []byte("active"), history.Entries[0].Value)
+
+	scan, err := db.Scan(&schema.ScanRequest{Prefix: []byte("session")})
+	require.NoError(t, err)
+	require.Len(t, scan.Entries, 1)
+	require.Equal(t, []byte("session1"), scan.Entries[0].Key)
+
+	scan, err = db.Scan(&schema.ScanRequest{Prefix: []byte("session"), IncludeDeleted: true})
+	require.NoError(t, err)
+	require.Len(t, scan.Entries, 2)
+
+	_, err = db.Delete(&schema.DeleteKeysRequest{Keys: [][]byte{[]byte("session2")}})
+	require.Equal(t, store.ErrKeyNotFound, err)
+}
+
+func TestSweepExpiredKeys(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.SweepExpiredKeys(0)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	n, err := db.SweepExpiredKeys(10)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	past := time.Now().Add(-time.Second).Unix()
+
+	_, err = db.ExpirableSet(&schema.ExpirableSetRequest{
+		KVs: []*schema.KeyValue{
+			{Key: []byte("session1"), Value: []byte("v1")},
+			{Key: []byte("session2"), Value: []byte("v2")},
+			{Key: []byte("session3"), Value: []byte("v3")},
+		},
+		ExpiresAt: past,
+	})
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("session4"), Value: []byte("v4")}}})
+	require.NoError(t, err)
+
+	n, err = db.SweepExpiredKeys(2)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	n, err = db.SweepExpiredKeys(10)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	n, err = db.SweepExpiredKeys(10)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	history, err := db.History(&schema.HistoryRequest{Key: []byte("session1")})
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 2)
+	require.Equal(t, past, history.Entries[0].Metadata.ExpiresAt)
+	require.True(t, history.Entries[1].Metadata.Deleted)
+
+	scan, err := db.Scan(&schema.ScanRequest{Prefix: []byte("session")})
+	require.NoError(t, err)
+	require.Len(t, scan.Entries, 1)
+	require.Equal(t, []byte("session4"), scan.Entries[0].Key)
+}
+
+func TestSweepExpiredKeysUpdatedMeanwhile(t *testing.T) {
+	d, closer := makeDb()
+	defer closer()
+
+	db := d.(*db)
+
+	past := time.Now().Add(-time.Second).Unix()
+
+	hdr, err := db.ExpirableSet(&schema.ExpirableSetRequest{
+		KVs: []*schema.KeyValue{
+			{Key: []byte("session1"), Value: []byte("v1")},
+			{Key: []byte("session2"), Value: []byte("v2")},
+			{Key: []byte("session3"), Value: []byte("v3")},
+		},
+		ExpiresAt: past,
+	})
+	require.NoError(t, err)
+
+	entries, err := db.expiredEntries(hdr.Id, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	// session2 gets renewed once found expired, its tombstone no longer applies
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("session2"), Value: []byte("v2")}}})
+	require.NoError(t, err)
+
+	n, err := db.commitTombstones(entries)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	scan, err := db.Scan(&schema.ScanRequest{Prefix: []byte("session")})
+	require.NoError(t, err)
+	require.Len(t, scan.Entries, 1)
+	require.Equal(t, []byte("session2"), scan.Entries[0].Key)
+
+	n, err = db.SweepExpiredKeys(10)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestExpirationMetadataEncoding(t *testing.T) {
+	md := &schema.KVMetadata{OriginalDigest: []byte{1, 2}, ExpiresAt: 1700000000}
+
+	decoded, err := DecodeMetadata(EncodeMetadata(md))
+	require.NoError(t, err)
+	require.Equal(t, md.OriginalDigest, decoded.OriginalDigest)
+	require.Equal(t, md.ExpiresAt, decoded.ExpiresAt)
+
+	_, err = DecodeMetadata([]byte{mdAttrExpiresAt, 0, 0, 0, 1, 1})
+	require.Equal(t, ErrInvalidMetadata, err)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"path"
+)
+
+// validateKeyPattern checks the syntax of a key pattern, an empty pattern matches every key
+func validateKeyPattern(pattern string) error {
+	if pattern == "" {
+		return nil
+	}
+
+	_, err := path.Match(pattern, "")
+	if err != nil {
+		return ErrInvalidKeyPattern
+	}
+
+	return nil
+}
+
+// matchesKeyPattern reports whether the encoded key matches the glob pattern.
+// Patterns follow path.Match syntax, so '*' does not match the '/' separator
+func matchesKeyPattern(pattern string, encKey []byte) bool {
+	if pattern == "" {
+		return true
+	}
+
+	matched, _ := path.Match(pattern, string(encKey[1:]))
+	return matched
+}
@@ -17,23 +17,43 @@ limitations under the License.
 package database
 
 import (
+	"bytes"
 	"encoding/binary"
+	"errors"
 	"math"
+	"sort"
 
 	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
 )
 
+var ErrInvalidMetadata = errors.New("invalid entry metadata")
+
 const (
 	SetKeyPrefix byte = iota
 	SortedSetKeyPrefix
 	SQLPrefix
+	PrefixTombstoneKeyPrefix
+	ReplicationKeyPrefix
 )
 
 const (
 	PlainValuePrefix = iota
 	ReferenceValuePrefix
+	MetadataValuePrefix
 )
 
+// metadata attributes are encoded sorted by code so the encoding is deterministic
+const (
+	mdAttrOriginalDigest byte = iota
+	mdAttrDeleted
+	mdAttrExpiresAt
+	mdAttrAttributes
+)
+
+const mdLenLen = 4
+const mdAttrLenLen = 4
+
 //WrapWithPrefix ...
 func WrapWithPrefix(b []byte, prefix byte) []byte {
 	wb := make([]byte, 1+len(b))
@@ -57,6 +77,21 @@ func EncodeKV(key []byte, value []byte) *store.KV {
 	}
 }
 
+// EncodeKVWithMetadata encodes a key-value pair, prepending the metadata to the value when present.
+// The resulting entry is the one to be used when verifying inclusion of entries written with metadata
+func EncodeKVWithMetadata(key []byte, value []byte, md *schema.KVMetadata) *store.KV {
+	return &store.KV{
+		Key:   WrapWithPrefix(key, SetKeyPrefix),
+		Value: WrapWithMetadata(WrapWithPrefix(value, PlainValuePrefix), md),
+	}
+}
+
+// EncodeTombstone encodes the entry which logically deletes a key.
+// Tombstones hold an empty value and are marked as deleted in their metadata
+func EncodeTombstone(key []byte) *store.KV {
+	return EncodeKVWithMetadata(key, nil, &schema.KVMetadata{Deleted: true})
+}
+
 func EncodeReference(key, referencedKey []byte, atTx uint64) *store.KV {
 	return &store.KV{
 		Key:   WrapWithPrefix(key, SetKeyPrefix),
@@ -81,6 +116,15 @@ func EncodeZAdd(set []byte, score float64, key []byte, atTx uint64) *store.KV {
 	}
 }
 
+// EncodeZRem encodes the tombstone which logically removes an element from a sorted set.
+// It's written under the same key as the element, so the addition and the removal share the same history
+func EncodeZRem(set []byte, score float64, key []byte, atTx uint64) *store.KV {
+	return &store.KV{
+		Key:   WrapZAddReferenceAt(set, score, key, atTx),
+		Value: WrapWithMetadata([]byte{PlainValuePrefix}, &schema.KVMetadata{Deleted: true}),
+	}
+}
+
 func WrapZAddReferenceAt(set []byte, score float64, key []byte, atTx uint64) []byte {
 	zKey := make([]byte, 1+setLenLen+len(set)+scoreLen+keyLenLen+len(key)+txIDLen)
 	zi := 0
@@ -101,3 +145,182 @@ func WrapZAddReferenceAt(set []byte, score float64, key []byte, atTx uint64) []b
 
 	return zKey
 }
+
+// WrapWithMetadata prepends the encoded metadata to an already prefixed value.
+// Values without metadata are returned untouched, so their encoding is the same as before
+func WrapWithMetadata(val []byte, md *schema.KVMetadata) []byte {
+	emd := EncodeMetadata(md)
+	if len(emd) == 0 {
+		return val
+	}
+
+	wv := make([]byte, 1+mdLenLen+len(emd)+len(val))
+	wv[0] = MetadataValuePrefix
+	binary.BigEndian.PutUint32(wv[1:], uint32(len(emd)))
+	copy(wv[1+mdLenLen:], emd)
+	copy(wv[1+mdLenLen+len(emd):], val)
+
+	return wv
+}
+
+// UnwrapMetadata splits a stored value into its metadata and the prefixed value it wraps.
+// Values written without metadata are returned as they are along with a nil metadata
+func UnwrapMetadata(val []byte) (*schema.KVMetadata, []byte, error) {
+	if len(val) == 0 || val[0] != MetadataValuePrefix {
+		return nil, val, nil
+	}
+
+	if len(val) < 1+mdLenLen {
+		return nil, nil, ErrInvalidMetadata
+	}
+
+	mdLen := int(binary.BigEndian.Uint32(val[1:]))
+	if len(val) < 1+mdLenLen+mdLen+1 {
+		return nil, nil, ErrInvalidMetadata
+	}
+
+	md, err := DecodeMetadata(val[1+mdLenLen : 1+mdLenLen+mdLen])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return md, val[1+mdLenLen+mdLen:], nil
+}
+
+// EncodeMetadata returns the deterministic binary encoding of entry metadata
+func EncodeMetadata(md *schema.KVMetadata) []byte {
+	if md == nil {
+		return nil
+	}
+
+	var b bytes.Buffer
+
+	if len(md.OriginalDigest) > 0 {
+		writeMetadataAttr(&b, mdAttrOriginalDigest, md.OriginalDigest)
+	}
+
+	if md.Deleted {
+		writeMetadataAttr(&b, mdAttrDeleted, nil)
+	}
+
+	if md.ExpiresAt != 0 {
+		var eb [8]byte
+		binary.BigEndian.PutUint64(eb[:], uint64(md.ExpiresAt))
+		writeMetadataAttr(&b, mdAttrExpiresAt, eb[:])
+	}
+
+	if len(md.Attributes) > 0 {
+		writeMetadataAttr(&b, mdAttrAttributes, encodeAttributes(md.Attributes))
+	}
+
+	return b.Bytes()
+}
+
+// encodeAttributes encodes the user attributes sorted by name, as length-prefixed name and value pairs
+func encodeAttributes(attrs map[string]string) []byte {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	var lb [mdAttrLenLen]byte
+
+	for _, name := range names {
+		binary.BigEndian.PutUint32(lb[:], uint32(len(name)))
+		b.Write(lb[:])
+		b.WriteString(name)
+
+		binary.BigEndian.PutUint32(lb[:], uint32(len(attrs[name])))
+		b.Write(lb[:])
+		b.WriteString(attrs[name])
+	}
+
+	return b.Bytes()
+}
+
+func decodeAttributes(b []byte) (map[string]string, error) {
+	attrs := make(map[string]string)
+
+	for i := 0; i < len(b); {
+		var pair [2]string
+
+		for j := range pair {
+			if len(b)-i < mdAttrLenLen {
+				return nil, ErrInvalidMetadata
+			}
+
+			l := int(binary.BigEndian.Uint32(b[i:]))
+			i += mdAttrLenLen
+
+			if len(b)-i < l {
+				return nil, ErrInvalidMetadata
+			}
+
+			pair[j] = string(b[i : i+l])
+			i += l
+		}
+
+		attrs[pair[0]] = pair[1]
+	}
+
+	return attrs, nil
+}
+
+func writeMetadataAttr(b *bytes.Buffer, code byte, v []byte) {
+	var lb [mdAttrLenLen]byte
+	binary.BigEndian.PutUint32(lb[:], uint32(len(v)))
+
+	b.WriteByte(code)
+	b.Write(lb[:])
+	b.Write(v)
+}
+
+// DecodeMetadata ...
+func DecodeMetadata(b []byte) (*schema.KVMetadata, error) {
+	md := &schema.KVMetadata{}
+
+	for i := 0; i < len(b); {
+		if len(b)-i < 1+mdAttrLenLen {
+			return nil, ErrInvalidMetadata
+		}
+
+		code := b[i]
+		l := int(binary.BigEndian.Uint32(b[i+1:]))
+		i += 1 + mdAttrLenLen
+
+		if len(b)-i < l {
+			return nil, ErrInvalidMetadata
+		}
+
+		v := make([]byte, l)
+		copy(v, b[i:i+l])
+		i += l
+
+		switch code {
+		case mdAttrOriginalDigest:
+			md.OriginalDigest = v
+		case mdAttrDeleted:
+			if l != 0 {
+				return nil, ErrInvalidMetadata
+			}
+			md.Deleted = true
+		case mdAttrExpiresAt:
+			if l != 8 {
+				return nil, ErrInvalidMetadata
+			}
+			md.ExpiresAt = int64(binary.BigEndian.Uint64(v))
+		case mdAttrAttributes:
+			attrs, err := decodeAttributes(v)
+			if err != nil {
+				return nil, err
+			}
+			md.Attributes = attrs
+		default:
+			return nil, ErrInvalidMetadata
+		}
+	}
+
+	return md, nil
+}
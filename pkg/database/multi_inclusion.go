@@ -0,0 +1,95 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// MultiInclusion proves the entries of a list of keys, each one at the requested transaction or the current one,
+// against the last committed transaction, which in turn is proven against req.ProveSinceTx.
+// Entries resolved through a reference are proven along with the reference
+func (d *db) MultiInclusion(req *schema.MultiInclusionRequest) (*schema.MultiInclusionResponse, error) {
+	if req == nil || len(req.Keys) == 0 {
+		return nil, store.ErrIllegalArguments
+	}
+
+	if len(req.Keys) > MaxKeyScanLimit {
+		return nil, ErrMaxKeyScanLimitExceeded
+	}
+
+	lastTxID, _ := d.st.Alh()
+	if lastTxID < req.ProveSinceTx {
+		return nil, ErrIllegalState
+	}
+
+	err := d.WaitForIndexingUpto(req.SinceTx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*schema.Entry, len(req.Keys))
+
+	for i, k := range req.Keys {
+		if k == nil {
+			return nil, store.ErrIllegalArguments
+		}
+
+		entries[i], err = d.Get(&schema.KeyRequest{Key: k.Key, AtTx: k.AtTx})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// every entry was written at or before the root
+	rootTx, verifiableRootTx, err := d.provenRoot(req.ProveSinceTx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := d.st.NewTx()
+
+	provenEntries := make([]*schema.ProvenEntry, len(entries))
+
+	for i, e := range entries {
+		inclusionProof, dualProof, err := d.proveAgainst(e.Tx, EncodeKey(e.Key), tx, rootTx)
+		if err != nil {
+			return nil, err
+		}
+
+		provenEntries[i] = &schema.ProvenEntry{
+			Entry:          e,
+			InclusionProof: inclusionProof,
+			DualProof:      dualProof,
+		}
+
+		if e.ReferencedBy == nil {
+			continue
+		}
+
+		provenEntries[i].ReferenceInclusionProof, provenEntries[i].ReferenceDualProof, err = d.proveAgainst(e.ReferencedBy.Tx, EncodeKey(e.ReferencedBy.Key), tx, rootTx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &schema.MultiInclusionResponse{
+		Entries:      provenEntries,
+		VerifiableTx: verifiableRootTx,
+	}, nil
+}
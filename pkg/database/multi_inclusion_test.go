@@ -0,0 +1,95 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiInclusion(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	md1, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte(`key1`), Value: []byte(`value1`)},
+		{Key: []byte(`key2`), Value: []byte(`value2`)},
+	}})
+	require.NoError(t, err)
+
+	md2, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte(`key1`), Value: []byte(`value1b`)}}})
+	require.NoError(t, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`ref`), ReferencedKey: []byte(`key2`)})
+	require.NoError(t, err)
+
+	_, err = db.MultiInclusion(nil)
+	require.Equal(t, store.ErrIllegalArguments, err)
+
+	_, err = db.MultiInclusion(&schema.MultiInclusionRequest{Keys: []*schema.InclusionKey{nil}})
+	require.Equal(t, store.ErrIllegalArguments, err)
+
+	_, err = db.MultiInclusion(&schema.MultiInclusionRequest{Keys: make([]*schema.InclusionKey, MaxKeyScanLimit+1)})
+	require.Equal(t, ErrMaxKeyScanLimitExceeded, err)
+
+	_, err = db.MultiInclusion(&schema.MultiInclusionRequest{Keys: []*schema.InclusionKey{{Key: []byte(`key1`)}}, ProveSinceTx: 100})
+	require.Equal(t, ErrIllegalState, err)
+
+	_, err = db.MultiInclusion(&schema.MultiInclusionRequest{Keys: []*schema.InclusionKey{{Key: []byte(`unknown`)}}})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	res, err := db.MultiInclusion(&schema.MultiInclusionRequest{
+		Keys: []*schema.InclusionKey{
+			{Key: []byte(`key1`)},
+			{Key: []byte(`key1`), AtTx: md1.Id},
+			{Key: []byte(`ref`)},
+		},
+		ProveSinceTx: md2.Id,
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Entries, 3)
+
+	rootDualProof := schema.DualProofFrom(res.VerifiableTx.DualProof)
+	rootID := rootDualProof.TargetTxMetadata.ID
+	rootAlh := rootDualProof.TargetTxMetadata.Alh()
+
+	require.Equal(t, md2.Id, rootDualProof.SourceTxMetadata.ID)
+	require.True(t, store.VerifyDualProof(rootDualProof, md2.Id, rootID, rootDualProof.SourceTxMetadata.Alh(), rootAlh))
+
+	verify := func(inclusionProof *schema.InclusionProof, dualProof *schema.DualProof, kv *store.KV) {
+		dp := schema.DualProofFrom(dualProof)
+
+		require.True(t, store.VerifyInclusion(schema.InclusionProofFrom(inclusionProof), kv, dp.SourceTxMetadata.Eh))
+		require.True(t, store.VerifyDualProof(dp, dp.SourceTxMetadata.ID, rootID, dp.SourceTxMetadata.Alh(), rootAlh))
+	}
+
+	for _, pe := range res.Entries {
+		verify(pe.InclusionProof, pe.DualProof, EncodeKVWithMetadata(pe.Entry.Key, pe.Entry.Value, pe.Entry.Metadata))
+	}
+
+	require.Equal(t, []byte(`value1b`), res.Entries[0].Entry.Value)
+	require.Equal(t, []byte(`value1`), res.Entries[1].Entry.Value)
+	require.Nil(t, res.Entries[1].ReferenceInclusionProof)
+
+	ref := res.Entries[2]
+	require.Equal(t, []byte(`value2`), ref.Entry.Value)
+	require.Equal(t, []byte(`ref`), ref.Entry.ReferencedBy.Key)
+	verify(ref.ReferenceInclusionProof, ref.ReferenceDualProof, EncodeReference(ref.Entry.ReferencedBy.Key, ref.Entry.Key, ref.Entry.ReferencedBy.AtTx))
+}
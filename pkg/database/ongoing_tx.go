@@ -0,0 +1,236 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"sync"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// OngoingTx is an interactive transaction. Writes are kept aside and are visible only to the
+// transaction itself until it gets committed, all at once.
+// Reads see the database as it was when the transaction started, later commits are not visible to it.
+// Conflicts are detected optimistically: keys read and then written by the transaction must not be
+// updated by someone else since it started, otherwise ErrTxReadConflict is returned
+type OngoingTx struct {
+	db *db
+
+	// snapshotTxID is the last tx committed when the transaction started, reads are made as of it
+	snapshotTxID uint64
+
+	entries      []*store.KV
+	entriesByKey map[string]int
+
+	// txs which wrote the values read by the transaction, zero for keys which didn't exist
+	readTxs map[string]uint64
+
+	closed bool
+	mutex  sync.Mutex
+}
+
+// NewOngoingTx starts an interactive transaction
+func (d *db) NewOngoingTx() (*OngoingTx, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.isReplica() {
+		return nil, ErrIsReplica
+	}
+
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	snapshotTxID, _ := d.st.Alh()
+
+	return &OngoingTx{
+		db:           d,
+		snapshotTxID: snapshotTxID,
+		entriesByKey: make(map[string]int),
+		readTxs:      make(map[string]uint64),
+	}, nil
+}
+
+// Set stages the key-value pairs, later writes of the same key replace the previous ones
+func (tx *OngoingTx) Set(kvs []*schema.KeyValue) error {
+	if len(kvs) == 0 {
+		return ErrIllegalArguments
+	}
+
+	tx.mutex.Lock()
+	defer tx.mutex.Unlock()
+
+	if tx.closed {
+		return ErrTxClosed
+	}
+
+	entries := make([]*store.KV, len(kvs))
+
+	tx.db.mutex.RLock()
+	for i, kv := range kvs {
+		if len(kv.Key) == 0 {
+			tx.db.mutex.RUnlock()
+			return ErrIllegalArguments
+		}
+
+		e, err := tx.db.encodeKeyValue(kv, false)
+		if err != nil {
+			tx.db.mutex.RUnlock()
+			return err
+		}
+
+		entries[i] = e
+	}
+	tx.db.mutex.RUnlock()
+
+	for _, e := range entries {
+		i, ok := tx.entriesByKey[string(e.Key)]
+		if ok {
+			tx.entries[i] = e
+			continue
+		}
+
+		tx.entriesByKey[string(e.Key)] = len(tx.entries)
+		tx.entries = append(tx.entries, e)
+	}
+
+	return nil
+}
+
+// Get returns the value of the key as of the snapshot of the transaction, or its own uncommitted write.
+// Uncommitted values are returned with a zero tx
+func (tx *OngoingTx) Get(key []byte) (*schema.Entry, error) {
+	if len(key) == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	tx.mutex.Lock()
+	defer tx.mutex.Unlock()
+
+	if tx.closed {
+		return nil, ErrTxClosed
+	}
+
+	ekey := EncodeKey(key)
+
+	if i, ok := tx.entriesByKey[string(ekey)]; ok {
+		md, val, err := UnwrapMetadata(tx.entries[i].Value)
+		if err != nil {
+			return nil, err
+		}
+
+		return &schema.Entry{Key: key, Value: TrimPrefix(val), Metadata: md}, nil
+	}
+
+	// the key is checked not to be updated since the snapshot on commit, when written by the transaction
+	ktx, err := tx.db.keyTxAsOf(ekey, tx.snapshotTxID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.readTxs[string(ekey)] = ktx
+
+	if ktx == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return tx.db.Get(&schema.KeyRequest{Key: key, AsOfTx: tx.snapshotTxID})
+}
+
+// Commit atomically writes all the staged entries and closes the transaction
+func (tx *OngoingTx) Commit() (res *schema.TxMetadata, err error) {
+	defer func() { err = tx.db.awaitReplicas(res.GetId(), err) }()
+
+	tx.mutex.Lock()
+	defer tx.mutex.Unlock()
+
+	if tx.closed {
+		return nil, ErrTxClosed
+	}
+
+	tx.closed = true
+
+	if len(tx.entries) == 0 {
+		return nil, store.ErrorNoEntriesProvided
+	}
+
+	for _, e := range tx.entries {
+		ktx, ok := tx.readTxs[string(e.Key)]
+		if !ok {
+			continue
+		}
+
+		if ktx == 0 {
+			e.Constraint = store.MustNotExist
+		} else {
+			e.Constraint = store.MustMatchTx
+			e.ExpectedTx = ktx
+		}
+	}
+
+	d := tx.db
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.isReplica() {
+		return nil, ErrIsReplica
+	}
+
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
+	md, err := d.st.Commit(tx.entries, true)
+	if err == store.ErrPreconditionFailed || err == store.ErrKeyAlreadyExists {
+		return nil, ErrTxReadConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.TxMetatadaTo(md), nil
+}
+
+// Rollback discards the staged entries and closes the transaction
+func (tx *OngoingTx) Rollback() error {
+	tx.mutex.Lock()
+	defer tx.mutex.Unlock()
+
+	if tx.closed {
+		return ErrTxClosed
+	}
+
+	tx.closed = true
+	tx.entries = nil
+
+	return nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOngoingTx(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("balance1"), Value: []byte("100")}}})
+	require.NoError(t, err)
+
+	tx, err := db.NewOngoingTx()
+	require.NoError(t, err)
+
+	err = tx.Set(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = tx.Set([]*schema.KeyValue{{Value: []byte("v")}})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = tx.Get(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	entry, err := tx.Get([]byte("balance1"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("100"), entry.Value)
+
+	err = tx.Set([]*schema.KeyValue{
+		{Key: []byte("balance1"), Value: []byte("50")},
+		{Key: []byte("balance2"), Value: []byte("40")},
+	})
+	require.NoError(t, err)
+
+	err = tx.Set([]*schema.KeyValue{{Key: []byte("balance2"), Value: []byte("50")}})
+	require.NoError(t, err)
+
+	entry, err = tx.Get([]byte("balance2"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("50"), entry.Value)
+	require.Zero(t, entry.Tx)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("balance2")})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	md, err := tx.Commit()
+	require.NoError(t, err)
+	require.Equal(t, int32(2), md.Nentries)
+
+	_, err = tx.Commit()
+	require.Equal(t, ErrTxClosed, err)
+
+	err = tx.Rollback()
+	require.Equal(t, ErrTxClosed, err)
+
+	entry, err = db.Get(&schema.KeyRequest{Key: []byte("balance2"), SinceTx: md.Id})
+	require.NoError(t, err)
+	require.Equal(t, []byte("50"), entry.Value)
+	require.Equal(t, md.Id, entry.Tx)
+}
+
+func TestOngoingTxConflict(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("counter"), Value: []byte("1")}}})
+	require.NoError(t, err)
+
+	tx1, err := db.NewOngoingTx()
+	require.NoError(t, err)
+
+	tx2, err := db.NewOngoingTx()
+	require.NoError(t, err)
+
+	_, err = tx1.Get([]byte("counter"))
+	require.NoError(t, err)
+
+	_, err = tx2.Get([]byte("counter"))
+	require.NoError(t, err)
+
+	_, err = tx1.Get([]byte("missing"))
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	err = tx1.Set([]*schema.KeyValue{{Key: []byte("counter"), Value: []byte("2")}})
+	require.NoError(t, err)
+
+	err = tx2.Set([]*schema.KeyValue{{Key: []byte("counter"), Value: []byte("3")}})
+	require.NoError(t, err)
+
+	_, err = tx1.Commit()
+	require.NoError(t, err)
+
+	_, err = tx2.Commit()
+	require.Equal(t, ErrTxReadConflict, err)
+
+	tx3, err := db.NewOngoingTx()
+	require.NoError(t, err)
+
+	_, err = tx3.Get([]byte("missing"))
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	err = tx3.Set([]*schema.KeyValue{{Key: []byte("missing"), Value: []byte("1")}})
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("missing"), Value: []byte("0")}}})
+	require.NoError(t, err)
+
+	_, err = tx3.Commit()
+	require.Equal(t, ErrTxReadConflict, err)
+
+	tx4, err := db.NewOngoingTx()
+	require.NoError(t, err)
+
+	_, err = tx4.Commit()
+	require.Equal(t, store.ErrorNoEntriesProvided, err)
+
+	tx5, err := db.NewOngoingTx()
+	require.NoError(t, err)
+
+	err = tx5.Set([]*schema.KeyValue{{Key: []byte("counter"), Value: []byte("5")}})
+	require.NoError(t, err)
+
+	err = tx5.Rollback()
+	require.NoError(t, err)
+
+	err = tx5.Set([]*schema.KeyValue{{Key: []byte("counter"), Value: []byte("5")}})
+	require.Equal(t, ErrTxClosed, err)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("counter")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("2"), entry.Value)
+}
+
+func TestOngoingTxSnapshotReads(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("balance"), Value: []byte("1")}}})
+	require.NoError(t, err)
+
+	tx, err := db.NewOngoingTx()
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte("balance"), Value: []byte("2")},
+		{Key: []byte("created"), Value: []byte("1")},
+	}})
+	require.NoError(t, err)
+
+	// commits made after the transaction started are not visible to it
+	entry, err := tx.Get([]byte("balance"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), entry.Value)
+
+	_, err = tx.Get([]byte("created"))
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	// and keys updated since then can not be overwritten
+	err = tx.Set([]*schema.KeyValue{{Key: []byte("balance"), Value: []byte("3")}})
+	require.NoError(t, err)
+
+	_, err = tx.Commit()
+	require.Equal(t, ErrTxReadConflict, err)
+
+	entry, err = db.Get(&schema.KeyRequest{Key: []byte("balance")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("2"), entry.Value)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// verifyEntryRead checks the raw value read for the key was committed in the given transaction and that the transaction
+// is consistent with the current state of the database, i.e. the proofs a client gets through VerifiableGet are checked on the server
+func (d *db) verifyEntryRead(key, val []byte, txID uint64) error {
+	tx := d.st.NewTx()
+
+	err := d.st.ReadTx(txID, tx)
+	if err == store.ErrorCorruptedTxData {
+		d.Logger.Errorf("Paranoid read of database '%s' failed: tx %d is corrupted", d.name, txID)
+		return ErrCorruptedRead
+	}
+	if err != nil {
+		return err
+	}
+
+	inclusionProof, err := tx.Proof(key)
+	if err != nil || !store.VerifyInclusion(inclusionProof, &store.KV{Key: key, Value: val}, tx.Eh()) {
+		d.Logger.Errorf("Paranoid read of database '%s' failed: entry read does not match tx %d", d.name, txID)
+		return ErrCorruptedRead
+	}
+
+	lastTxID, lastTxAlh := d.st.Alh()
+
+	lastTx := tx
+
+	if lastTxID != txID {
+		lastTx = d.st.NewTx()
+
+		err = d.st.ReadTx(lastTxID, lastTx)
+		if err != nil {
+			return err
+		}
+	}
+
+	dualProof, err := d.st.DualProof(tx, lastTx)
+	if err != nil {
+		return err
+	}
+
+	if !store.VerifyDualProof(dualProof, txID, lastTxID, tx.Alh, lastTxAlh) {
+		d.Logger.Errorf("Paranoid read of database '%s' failed: tx %d is not consistent with tx %d", d.name, txID, lastTxID)
+		return ErrCorruptedRead
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParanoidReads(t *testing.T) {
+	idb, closer := makeDb()
+	defer closer()
+
+	db := idb.(*db)
+
+	require.False(t, db.GetOptions().GetParanoidReads())
+
+	db.UpdateParanoidReads(true)
+	require.True(t, db.GetOptions().GetParanoidReads())
+
+	md, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte("ref1"), ReferencedKey: []byte("key1")})
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.NoError(t, err)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("key1")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), entry.Value)
+
+	entry, err = db.Get(&schema.KeyRequest{Key: []byte("key1"), AtTx: md.Id})
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), entry.Value)
+
+	entry, err = db.Get(&schema.KeyRequest{Key: []byte("ref1")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), entry.Value)
+
+	entries, err := db.Scan(&schema.ScanRequest{Prefix: []byte("key")})
+	require.NoError(t, err)
+	require.Len(t, entries.Entries, 2)
+
+	// values not matching the committed entries are rejected
+	err = db.verifyEntryRead(EncodeKey([]byte("key1")), WrapWithPrefix([]byte("value1"), PlainValuePrefix), md.Id)
+	require.NoError(t, err)
+
+	err = db.verifyEntryRead(EncodeKey([]byte("key1")), WrapWithPrefix([]byte("tampered"), PlainValuePrefix), md.Id)
+	require.Equal(t, ErrCorruptedRead, err)
+
+	err = db.verifyEntryRead(EncodeKey([]byte("key2")), WrapWithPrefix([]byte("value2"), PlainValuePrefix), md.Id)
+	require.Equal(t, ErrCorruptedRead, err)
+}
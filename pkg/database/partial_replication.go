@@ -0,0 +1,140 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// replicationProgressKey holds, in partial replicas, the id of the last source transaction replicated
+var replicationProgressKey = []byte{ReplicationKeyPrefix}
+
+// replicaKeyFilter accepts the entries of the keys under any of the prefixes. Entries not bound to a key,
+// as SQL rows, are never shipped to filtered replicas
+func replicaKeyFilter(prefixes [][]byte) func(key []byte) bool {
+	hasPrefix := func(key []byte) bool {
+		for _, p := range prefixes {
+			if bytes.HasPrefix(key, p) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(key []byte) bool {
+		if len(key) == 0 {
+			return false
+		}
+
+		switch key[0] {
+		case SetKeyPrefix:
+			return hasPrefix(key[1:])
+		case SortedSetKeyPrefix:
+			// zKey = [1+setLenLen+len(set)+scoreLen+keyLenLen+1+len(key)+txIDLen]
+			if len(key) < 1+setLenLen {
+				return false
+			}
+			keyOff := 1 + setLenLen + int(binary.BigEndian.Uint64(key[1:])) + scoreLen + keyLenLen + 1
+			if keyOff > len(key)-txIDLen {
+				return false
+			}
+			return hasPrefix(key[keyOff : len(key)-txIDLen])
+		case PrefixTombstoneKeyPrefix:
+			// tombstones of broader prefixes remove the filtered keys as well
+			for _, p := range prefixes {
+				if bytes.HasPrefix(key[1:], p) || bytes.HasPrefix(p, key[1:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		return false
+	}
+}
+
+// replicatePartially commits the entries of a filtered transaction as a new local transaction,
+// along with the id of the source transaction so replication resumes from it
+func (d *db) replicatePartially(exportedTx []byte) (*schema.TxMetadata, error) {
+	md, entries, err := store.ExportedTxEntries(exportedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	replicatedTxID, err := d.replicatedTxID()
+	if err != nil {
+		return nil, err
+	}
+
+	if md.ID != replicatedTxID+1 {
+		return nil, ErrIllegalArguments
+	}
+
+	var srcTxID [txIDLen]byte
+	binary.BigEndian.PutUint64(srcTxID[:], md.ID)
+
+	entries = append(entries, &store.KV{Key: replicationProgressKey, Value: srcTxID[:]})
+
+	txMetadata, err := d.st.Commit(entries, true)
+	if err != nil {
+		return nil, err
+	}
+
+	d.recordPrefixTombstones(entries, txMetadata.ID)
+
+	return schema.TxMetatadaTo(txMetadata), nil
+}
+
+// ReplicatedTxID returns the id of the last transaction replicated from the source database,
+// which for partial replicas differs from the id of their own last transaction
+func (d *db) ReplicatedTxID() (uint64, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	return d.replicatedTxID()
+}
+
+func (d *db) replicatedTxID() (uint64, error) {
+	lastTxID, _ := d.st.Alh()
+
+	if !d.options.GetReplicationOptions().Partial {
+		return lastTxID, nil
+	}
+
+	err := d.st.WaitForIndexingUpto(lastTxID, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	v, _, _, err := d.st.Get(replicationProgressKey)
+	if err == store.ErrKeyNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if len(v) != txIDLen {
+		return 0, ErrIllegalState
+	}
+
+	return binary.BigEndian.Uint64(v), nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicaKeyFilter(t *testing.T) {
+	keep := replicaKeyFilter([][]byte{[]byte("tenant1/")})
+
+	require.False(t, keep(nil))
+	require.True(t, keep(EncodeKey([]byte("tenant1/key"))))
+	require.False(t, keep(EncodeKey([]byte("tenant2/key"))))
+	require.True(t, keep(EncodeZAdd([]byte("set"), 1, EncodeKey([]byte("tenant1/key")), 0).Key))
+	require.False(t, keep(EncodeZAdd([]byte("tenant1/"), 1, EncodeKey([]byte("tenant2/key")), 0).Key))
+	require.False(t, keep([]byte{SortedSetKeyPrefix, 0}))
+	require.True(t, keep(WrapWithPrefix([]byte("tenant1/sub"), PrefixTombstoneKeyPrefix)))
+	require.True(t, keep(WrapWithPrefix([]byte("tenant"), PrefixTombstoneKeyPrefix)))
+	require.False(t, keep(WrapWithPrefix([]byte("tenant2/"), PrefixTombstoneKeyPrefix)))
+	require.False(t, keep([]byte{SQLPrefix, 1, 2, 3}))
+}
+
+func TestPartialReplication(t *testing.T) {
+	primary, closePrimary := makeDb()
+	defer closePrimary()
+
+	primary.GetOptions().GetReplicationOptions().WithReplicaFilters(map[string][][]byte{
+		"edge1": {[]byte("tenant1/")},
+	})
+
+	replicaPath := "data_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	replicaOpts := DefaultOption().
+		WithDbRootPath(replicaPath).
+		WithDbName("replica").
+		WithReplicationOptions((&ReplicationOptions{}).AsReplica(true).AsPartial(true))
+
+	replica, closeReplica := makeDbWith(replicaOpts)
+	defer closeReplica()
+
+	_, err := primary.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte("tenant1/key1"), Value: []byte("value1")},
+		{Key: []byte("tenant2/key1"), Value: []byte("value2")},
+	}})
+	require.NoError(t, err)
+
+	_, err = primary.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("tenant2/key2"), Value: []byte("value3")}}})
+	require.NoError(t, err)
+
+	_, err = primary.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("tenant1/key2"), Value: []byte("value4")}}})
+	require.NoError(t, err)
+
+	state, err := primary.CurrentState()
+	require.NoError(t, err)
+
+	replicatedTxID, err := replica.ReplicatedTxID()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), replicatedTxID)
+
+	// transactions must be replicated in order
+	etx, err := primary.ExportTxByID(&schema.TxRequest{Tx: 2, ReplicaId: "edge1"})
+	require.NoError(t, err)
+
+	_, err = replica.ReplicateTx(etx)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	for txID := uint64(1); txID <= state.TxId; txID++ {
+		etx, err := primary.ExportTxByID(&schema.TxRequest{Tx: txID, ReplicaId: "edge1"})
+		require.NoError(t, err)
+
+		_, err = replica.ReplicateTx(etx)
+		require.NoError(t, err)
+	}
+
+	replicatedTxID, err = replica.ReplicatedTxID()
+	require.NoError(t, err)
+	require.Equal(t, state.TxId, replicatedTxID)
+
+	e, err := replica.Get(&schema.KeyRequest{Key: []byte("tenant1/key1")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), e.Value)
+
+	e, err = replica.Get(&schema.KeyRequest{Key: []byte("tenant1/key2")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("value4"), e.Value)
+
+	for _, key := range []string{"tenant2/key1", "tenant2/key2"} {
+		_, err = replica.Get(&schema.KeyRequest{Key: []byte(key)})
+		require.Error(t, err)
+	}
+
+	// replicas without filters get whole transactions
+	etx, err = primary.ExportTxByID(&schema.TxRequest{Tx: state.TxId - 2, ReplicaId: "replica2"})
+	require.NoError(t, err)
+
+	_, entries, err := store.ExportedTxEntries(etx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	progress := primary.ReplicationProgress()
+	require.Len(t, progress, 2)
+	require.Equal(t, "edge1", progress[0].ReplicaID)
+	require.Equal(t, [][]byte{[]byte("tenant1/")}, progress[0].KeyPrefixes)
+	require.Equal(t, "replica2", progress[1].ReplicaID)
+	require.Empty(t, progress[1].KeyPrefixes)
+}
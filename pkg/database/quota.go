@@ -0,0 +1,104 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultQuotaWarningThreshold is the percentage of the quota at which a warning gets logged
+const DefaultQuotaWarningThreshold = 90
+
+// quotaCheckInterval is the minimum time between two computations of the database size
+var quotaCheckInterval = 1 * time.Second
+
+// quota keeps track of the on-disk size of a database.
+// The size is computed at most once per quotaCheckInterval, thus the quota is a soft limit
+type quota struct {
+	mutex sync.Mutex
+
+	size      int64
+	checkedAt time.Time
+	warned    bool
+}
+
+// checkQuota returns ErrQuotaExceeded when the database reached its maximum on-disk size
+func (d *db) checkQuota() error {
+	maxDiskSize := d.options.GetMaxDiskSize()
+	if maxDiskSize <= 0 {
+		return nil
+	}
+
+	d.quota.mutex.Lock()
+	defer d.quota.mutex.Unlock()
+
+	if time.Since(d.quota.checkedAt) >= quotaCheckInterval {
+		size, err := diskUsage(d.path())
+		if err != nil {
+			return err
+		}
+
+		d.quota.size = size
+		d.quota.checkedAt = time.Now()
+	}
+
+	if d.quota.size >= maxDiskSize {
+		return ErrQuotaExceeded
+	}
+
+	warningThld := maxDiskSize / 100 * int64(d.options.GetQuotaWarningThreshold())
+
+	if d.quota.size < warningThld {
+		d.quota.warned = false
+	} else if !d.quota.warned {
+		d.Logger.Warningf("Database '%s' is using %d bytes out of a quota of %d bytes", d.name, d.quota.size, maxDiskSize)
+		d.quota.warned = true
+	}
+
+	return nil
+}
+
+// UpdateMaxDiskSize changes the maximum on-disk size of the database, zero means no limit
+func (d *db) UpdateMaxDiskSize(maxDiskSize int64) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.options.WithMaxDiskSize(maxDiskSize)
+
+	d.quota.mutex.Lock()
+	d.quota.checkedAt = time.Time{}
+	d.quota.mutex.Unlock()
+}
+
+func diskUsage(dir string) (int64, error) {
+	var size int64
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+
+	return size, err
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseQuota(t *testing.T) {
+	d, closer := makeDb()
+	defer closer()
+
+	db := d.(*db)
+
+	kv := &schema.KeyValue{Key: []byte("key"), Value: []byte("value")}
+
+	_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{kv}})
+	require.NoError(t, err)
+
+	db.UpdateMaxDiskSize(1)
+	require.Equal(t, int64(1), db.GetOptions().GetMaxDiskSize())
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{kv}})
+	require.Equal(t, ErrQuotaExceeded, err)
+
+	_, err = db.VerifiableSet(&schema.VerifiableSetRequest{SetRequest: &schema.SetRequest{KVs: []*schema.KeyValue{kv}}})
+	require.Equal(t, ErrQuotaExceeded, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte("ref"), ReferencedKey: []byte("key")})
+	require.Equal(t, ErrQuotaExceeded, err)
+
+	_, err = db.ZAdd(&schema.ZAddRequest{Set: []byte("set"), Score: 1, Key: []byte("key")})
+	require.Equal(t, ErrQuotaExceeded, err)
+
+	_, err = db.ExecAll(&schema.ExecAllRequest{Operations: []*schema.Op{{Operation: &schema.Op_Kv{Kv: kv}}}})
+	require.Equal(t, ErrQuotaExceeded, err)
+
+	_, err = db.SQLExec(&schema.SQLExecRequest{Sql: "CREATE TABLE mytable(id INTEGER, PRIMARY KEY id)"})
+	require.Equal(t, ErrQuotaExceeded, err)
+
+	// reads are still served
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("key")})
+	require.NoError(t, err)
+
+	size, err := diskUsage(db.path())
+	require.NoError(t, err)
+
+	// usage above the warning threshold
+	db.UpdateMaxDiskSize(size + size/20)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{kv}})
+	require.NoError(t, err)
+	require.True(t, db.quota.warned)
+
+	db.UpdateMaxDiskSize(0)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{kv}})
+	require.NoError(t, err)
+}
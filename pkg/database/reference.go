@@ -17,6 +17,7 @@ limitations under the License.
 package database
 
 import (
+	"encoding/binary"
 	"errors"
 
 	"github.com/codenotary/immudb/embedded/store"
@@ -25,14 +26,17 @@ import (
 
 var ErrReferencedKeyCannotBeAReference = errors.New("referenced key cannot be a reference")
 var ErrFinalKeyCannotBeConvertedIntoReference = errors.New("final key cannot be converted into a reference")
+var ErrKeyRevisionNotFound = errors.New("key revision not found")
 
 //Reference ...
-func (d *db) SetReference(req *schema.ReferenceRequest) (*schema.TxMetadata, error) {
+func (d *db) SetReference(req *schema.ReferenceRequest) (res *schema.TxMetadata, err error) {
+	defer func() { err = d.awaitReplicas(res.GetId(), err) }()
+
 	if req == nil || len(req.Key) == 0 || len(req.ReferencedKey) == 0 {
 		return nil, store.ErrIllegalArguments
 	}
 
-	if (req.AtTx == 0 && req.BoundRef) || (req.AtTx > 0 && !req.BoundRef) {
+	if !validBinding(req) || (req.BoundRef && req.AtTx == 0 && req.AtRevision == 0) {
 		return nil, store.ErrIllegalArguments
 	}
 
@@ -43,14 +47,31 @@ func (d *db) SetReference(req *schema.ReferenceRequest) (*schema.TxMetadata, err
 		return nil, ErrIsReplica
 	}
 
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
 	lastTxID, _ := d.st.Alh()
-	err := d.st.WaitForIndexingUpto(lastTxID, nil)
+	err = d.st.WaitForIndexingUpto(lastTxID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	atTx, err := d.referenceAtTx(req)
 	if err != nil {
 		return nil, err
 	}
 
-	// check key does not exists or it's already a reference
-	entry, err := d.getAt(EncodeKey(req.Key), req.AtTx, 0, d.st, d.tx1)
+	// check key does not exists or it's already a reference, so it can be pointed to a new target
+	entry, err := d.getAt(EncodeKey(req.Key), 0, 0, d.st, d.tx1)
 	if err != nil && err != store.ErrKeyNotFound {
 		return nil, err
 	}
@@ -59,7 +80,7 @@ func (d *db) SetReference(req *schema.ReferenceRequest) (*schema.TxMetadata, err
 	}
 
 	// check referenced key exists and it's not a reference
-	refEntry, err := d.getAt(EncodeKey(req.ReferencedKey), req.AtTx, 0, d.st, d.tx1)
+	refEntry, err := d.getAt(EncodeKey(req.ReferencedKey), atTx, 0, d.st, d.tx1)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +88,7 @@ func (d *db) SetReference(req *schema.ReferenceRequest) (*schema.TxMetadata, err
 		return nil, ErrReferencedKeyCannotBeAReference
 	}
 
-	meta, err := d.st.Commit([]*store.KV{EncodeReference(req.Key, req.ReferencedKey, req.AtTx)}, !req.NoWait)
+	meta, err := d.st.Commit([]*store.KV{EncodeReference(req.Key, req.ReferencedKey, atTx)}, !req.NoWait)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +96,40 @@ func (d *db) SetReference(req *schema.ReferenceRequest) (*schema.TxMetadata, err
 	return schema.TxMetatadaTo(meta), err
 }
 
+// validBinding checks the reference is bound either at a transaction or at a revision, and only when it's a bound one
+func validBinding(req *schema.ReferenceRequest) bool {
+	if req.AtTx > 0 && req.AtRevision != 0 {
+		return false
+	}
+
+	return req.BoundRef || (req.AtTx == 0 && req.AtRevision == 0)
+}
+
+// referenceAtTx returns the transaction the reference is bound at. Revisions are numbered from 1 starting from the
+// first one of the referenced key, while negative revisions are counted backwards from the last one, being -1 the current one
+func (d *db) referenceAtTx(req *schema.ReferenceRequest) (uint64, error) {
+	if req.AtRevision == 0 {
+		return req.AtTx, nil
+	}
+
+	offset := req.AtRevision - 1
+	desc := req.AtRevision < 0
+
+	if desc {
+		offset = -req.AtRevision - 1
+	}
+
+	txs, err := d.st.History(EncodeKey(req.ReferencedKey), uint64(offset), desc, 1)
+	if err == store.ErrKeyNotFound || err == store.ErrNoMoreEntries || err == store.ErrOffsetOutOfRange || (err == nil && len(txs) == 0) {
+		return 0, ErrKeyRevisionNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return txs[0], nil
+}
+
 //SafeReference ...
 func (d *db) VerifiableSetReference(req *schema.VerifiableReferenceRequest) (*schema.VerifiableTx, error) {
 	if req == nil {
@@ -124,3 +179,82 @@ func (d *db) VerifiableSetReference(req *schema.VerifiableReferenceRequest) (*sc
 		DualProof: schema.DualProofTo(dualProof),
 	}, nil
 }
+
+// ResolveReferenceHistory lists the targets a reference has pointed to, each one proven against the last committed
+// transaction, which in turn is proven against req.ProveSinceTx. Revisions which are not references, like tombstones, are not listed,
+// although they are taken into account by the offset and limit
+func (d *db) ResolveReferenceHistory(req *schema.ReferenceHistoryRequest) (*schema.VerifiableReferenceHistory, error) {
+	if req == nil || len(req.Key) == 0 {
+		return nil, store.ErrIllegalArguments
+	}
+
+	if req.Limit > MaxKeyScanLimit {
+		return nil, ErrMaxKeyScanLimitExceeded
+	}
+
+	lastTxID, _ := d.st.Alh()
+	if lastTxID < req.ProveSinceTx {
+		return nil, ErrIllegalState
+	}
+
+	err := d.WaitForIndexingUpto(req.SinceTx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+
+	if req.Limit == 0 {
+		limit = MaxKeyScanLimit
+	}
+
+	key := EncodeKey(req.Key)
+
+	txs, err := d.st.History(key, req.Offset, req.Desc, limit)
+	if err != nil && err != store.ErrOffsetOutOfRange {
+		return nil, err
+	}
+
+	rootTx, verifiableRootTx, err := d.provenRoot(req.ProveSinceTx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := d.st.NewTx()
+
+	var revisions []*schema.ReferenceRevision
+
+	for _, txID := range txs {
+		err = d.st.ReadTx(txID, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := d.st.ReadValue(tx, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if val[0] != ReferenceValuePrefix {
+			continue
+		}
+
+		inclusionProof, dualProof, err := d.proveAgainst(txID, key, tx, rootTx)
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, &schema.ReferenceRevision{
+			Tx:             txID,
+			ReferencedKey:  TrimPrefix(val[1+8:]),
+			AtTx:           binary.BigEndian.Uint64(val[1:]),
+			InclusionProof: inclusionProof,
+			DualProof:      dualProof,
+		})
+	}
+
+	return &schema.VerifiableReferenceHistory{
+		Revisions:    revisions,
+		VerifiableTx: verifiableRootTx,
+	}, nil
+}
@@ -18,6 +18,7 @@ package database
 
 import (
 	"crypto/sha256"
+	"fmt"
 	"strconv"
 	"testing"
 
@@ -379,3 +380,202 @@ func TestStoreVerifiableReference(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, []byte(`firstValue`), firstItemRet.Value, "Should have referenced item value")
 }
+
+func TestStoreReferenceRepointingAndHistory(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	md1, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte(`release-1.0`), Value: []byte(`artifact1`)}}})
+	require.NoError(t, err)
+
+	md2, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte(`release-1.1`), Value: []byte(`artifact2`)}}})
+	require.NoError(t, err)
+
+	// a final key can not be converted into a reference, even when the reference is bound
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`release-1.1`), ReferencedKey: []byte(`release-1.0`), AtTx: md1.Id, BoundRef: true})
+	require.Equal(t, ErrFinalKeyCannotBeConvertedIntoReference, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`stable`), ReferencedKey: []byte(`release-1.0`), AtTx: md1.Id, BoundRef: true})
+	require.NoError(t, err)
+
+	_, err = db.Delete(&schema.DeleteKeysRequest{Keys: [][]byte{[]byte(`stable`)}})
+	require.NoError(t, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`stable`), ReferencedKey: []byte(`release-1.1`), AtTx: md2.Id, BoundRef: true})
+	require.NoError(t, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`stable`), ReferencedKey: []byte(`release-1.0`)})
+	require.NoError(t, err)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte(`stable`)})
+	require.NoError(t, err)
+	require.Equal(t, []byte(`artifact1`), entry.Value)
+
+	_, err = db.ResolveReferenceHistory(nil)
+	require.Equal(t, store.ErrIllegalArguments, err)
+
+	_, err = db.ResolveReferenceHistory(&schema.ReferenceHistoryRequest{Key: []byte(`stable`), ProveSinceTx: 100})
+	require.Equal(t, ErrIllegalState, err)
+
+	proveSinceTx := md2.Id
+
+	history, err := db.ResolveReferenceHistory(&schema.ReferenceHistoryRequest{Key: []byte(`stable`), ProveSinceTx: proveSinceTx})
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 3)
+
+	rootDualProof := schema.DualProofFrom(history.VerifiableTx.DualProof)
+	rootID := rootDualProof.TargetTxMetadata.ID
+	rootAlh := rootDualProof.TargetTxMetadata.Alh()
+
+	require.True(t, store.VerifyDualProof(rootDualProof, proveSinceTx, rootID, rootDualProof.SourceTxMetadata.Alh(), rootAlh))
+
+	targets := []struct {
+		key  string
+		atTx uint64
+	}{
+		{`release-1.0`, md1.Id},
+		{`release-1.1`, md2.Id},
+		{`release-1.0`, 0},
+	}
+
+	for i, rev := range history.Revisions {
+		require.Equal(t, []byte(targets[i].key), rev.ReferencedKey)
+		require.Equal(t, targets[i].atTx, rev.AtTx)
+
+		dp := schema.DualProofFrom(rev.DualProof)
+		require.Equal(t, rev.Tx, dp.SourceTxMetadata.ID)
+
+		kv := EncodeReference([]byte(`stable`), rev.ReferencedKey, rev.AtTx)
+
+		require.True(t, store.VerifyInclusion(schema.InclusionProofFrom(rev.InclusionProof), kv, dp.SourceTxMetadata.Eh))
+		require.True(t, store.VerifyDualProof(dp, rev.Tx, rootID, dp.SourceTxMetadata.Alh(), rootAlh))
+	}
+
+	history, err = db.ResolveReferenceHistory(&schema.ReferenceHistoryRequest{Key: []byte(`stable`), Desc: true, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 1)
+	require.Equal(t, uint64(0), history.Revisions[0].AtTx)
+}
+
+func TestStoreReferencesTo(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	md, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte(`artifact`), Value: []byte(`v1`)},
+		{Key: []byte(`other`), Value: []byte(`v1`)},
+	}})
+	require.NoError(t, err)
+
+	// references set before the first lookup are loaded from the index
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`tags/latest`), ReferencedKey: []byte(`artifact`)})
+	require.NoError(t, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`tags/stable`), ReferencedKey: []byte(`artifact`), AtTx: md.Id, BoundRef: true})
+	require.NoError(t, err)
+
+	_, err = db.ReferencesTo(nil)
+	require.Equal(t, store.ErrIllegalArguments, err)
+
+	_, err = db.ReferencesTo(&schema.ReferencesToRequest{ReferencedKey: []byte(`artifact`), Limit: MaxKeyScanLimit + 1})
+	require.Equal(t, ErrMaxKeyScanLimitExceeded, err)
+
+	refs, err := db.ReferencesTo(&schema.ReferencesToRequest{ReferencedKey: []byte(`artifact`)})
+	require.NoError(t, err)
+	require.Len(t, refs.References, 2)
+	require.Equal(t, []byte(`tags/latest`), refs.References[0].Key)
+	require.Equal(t, uint64(0), refs.References[0].AtTx)
+	require.Equal(t, []byte(`tags/stable`), refs.References[1].Key)
+	require.Equal(t, md.Id, refs.References[1].AtTx)
+
+	// later changes are read from the committed transactions
+	_, err = db.ExecAll(&schema.ExecAllRequest{Operations: []*schema.Op{
+		{Operation: &schema.Op_Ref{Ref: &schema.ReferenceRequest{Key: []byte(`alias`), ReferencedKey: []byte(`artifact`)}}},
+	}})
+	require.NoError(t, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`tags/latest`), ReferencedKey: []byte(`other`)})
+	require.NoError(t, err)
+
+	_, err = db.Delete(&schema.DeleteKeysRequest{Keys: [][]byte{[]byte(`alias`)}})
+	require.NoError(t, err)
+
+	refs, err = db.ReferencesTo(&schema.ReferencesToRequest{ReferencedKey: []byte(`artifact`)})
+	require.NoError(t, err)
+	require.Len(t, refs.References, 1)
+	require.Equal(t, []byte(`tags/stable`), refs.References[0].Key)
+
+	refs, err = db.ReferencesTo(&schema.ReferencesToRequest{ReferencedKey: []byte(`other`)})
+	require.NoError(t, err)
+	require.Len(t, refs.References, 1)
+	require.Equal(t, []byte(`tags/latest`), refs.References[0].Key)
+
+	_, err = db.DeletePrefix(&schema.DeletePrefixRequest{Prefix: []byte(`tags/`)})
+	require.NoError(t, err)
+
+	refs, err = db.ReferencesTo(&schema.ReferencesToRequest{ReferencedKey: []byte(`artifact`)})
+	require.NoError(t, err)
+	require.Empty(t, refs.References)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`tags/stable`), ReferencedKey: []byte(`artifact`)})
+	require.NoError(t, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`tags/beta`), ReferencedKey: []byte(`artifact`)})
+	require.NoError(t, err)
+
+	refs, err = db.ReferencesTo(&schema.ReferencesToRequest{ReferencedKey: []byte(`artifact`), Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, refs.References, 1)
+	require.Equal(t, []byte(`tags/beta`), refs.References[0].Key)
+}
+
+func TestStoreReferenceAtRevision(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	var txs []uint64
+
+	for i := 1; i <= 3; i++ {
+		md, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte(`release`), Value: []byte(fmt.Sprintf("build%d", i))}}})
+		require.NoError(t, err)
+
+		txs = append(txs, md.Id)
+	}
+
+	_, err := db.SetReference(&schema.ReferenceRequest{Key: []byte(`v2`), ReferencedKey: []byte(`release`), AtRevision: 2})
+	require.Equal(t, store.ErrIllegalArguments, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`v2`), ReferencedKey: []byte(`release`), AtRevision: 2, AtTx: txs[1], BoundRef: true})
+	require.Equal(t, store.ErrIllegalArguments, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`v4`), ReferencedKey: []byte(`release`), AtRevision: 4, BoundRef: true})
+	require.Equal(t, ErrKeyRevisionNotFound, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`v0`), ReferencedKey: []byte(`release`), AtRevision: -4, BoundRef: true})
+	require.Equal(t, ErrKeyRevisionNotFound, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`missing`), ReferencedKey: []byte(`unknown`), AtRevision: 1, BoundRef: true})
+	require.Equal(t, ErrKeyRevisionNotFound, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`v2`), ReferencedKey: []byte(`release`), AtRevision: 2, BoundRef: true})
+	require.NoError(t, err)
+
+	_, err = db.SetReference(&schema.ReferenceRequest{Key: []byte(`current`), ReferencedKey: []byte(`release`), AtRevision: -1, BoundRef: true})
+	require.NoError(t, err)
+
+	_, err = db.ExecAll(&schema.ExecAllRequest{Operations: []*schema.Op{
+		{Operation: &schema.Op_Ref{Ref: &schema.ReferenceRequest{Key: []byte(`v1`), ReferencedKey: []byte(`release`), AtRevision: 1, BoundRef: true}}},
+	}})
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte(`release`), Value: []byte(`build4`)}}})
+	require.NoError(t, err)
+
+	for ref, i := range map[string]int{"v1": 0, "v2": 1, "current": 2} {
+		entry, err := db.VerifiableGet(&schema.VerifiableGetRequest{KeyRequest: &schema.KeyRequest{Key: []byte(ref)}})
+		require.NoError(t, err)
+		require.Equal(t, []byte(fmt.Sprintf("build%d", i+1)), entry.Entry.Value)
+		require.Equal(t, txs[i], entry.Entry.Tx)
+		require.Equal(t, txs[i], entry.Entry.ReferencedBy.AtTx)
+	}
+}
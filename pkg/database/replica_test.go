@@ -135,3 +135,37 @@ func TestSwitchToReplica(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestSwitchReplication(t *testing.T) {
+	rootPath := "data_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	options := DefaultOption().WithDbRootPath(rootPath).WithDbName("db").WithReplicationOptions(&ReplicationOptions{})
+
+	db, closer := makeDbWith(options)
+	defer closer()
+
+	md, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	txID, err := db.SwitchReplication((&ReplicationOptions{}).AsReplica(true), 0)
+	require.NoError(t, err)
+	require.Equal(t, md.Id, txID)
+	require.True(t, db.IsReplica())
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.Equal(t, ErrIsReplica, err)
+
+	// the replica does not hold the next tx yet
+	txID, err = db.SwitchReplication(&ReplicationOptions{}, md.Id+1)
+	require.Equal(t, ErrReplicaBehind, err)
+	require.Equal(t, md.Id, txID)
+	require.True(t, db.IsReplica())
+
+	txID, err = db.SwitchReplication(&ReplicationOptions{}, md.Id)
+	require.NoError(t, err)
+	require.Equal(t, md.Id, txID)
+	require.False(t, db.IsReplica())
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.NoError(t, err)
+}
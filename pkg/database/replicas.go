@@ -0,0 +1,322 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ReplicaProgress holds the replication progress of a replica of the database
+type ReplicaProgress struct {
+	ReplicaID     string
+	ExportedTxID  uint64
+	ConfirmedTxID uint64
+	LastSeenAt    time.Time
+	// KeyPrefixes are the prefixes of the keys exported to the replica, all entries are exported when empty
+	KeyPrefixes [][]byte
+	// Divergence is set once the replica confirmed a transaction with a different hash,
+	// no transaction is exported to it until it confirms a matching one
+	Divergence *ReplicaDivergence
+	// Downstream is the progress of the replicas fed by the replica, as last reported by it
+	Downstream []*ReplicaProgress
+}
+
+// MaxTrackedReplicas is the number of replicas whose progress is kept, the least recently seen one
+// is forgotten to make room for a new one
+const MaxTrackedReplicas = 256
+
+// MaxDownstreamDepth is the number of levels of downstream replicas kept below each replica
+const MaxDownstreamDepth = 8
+
+// ReplicaDivergence describes a transaction confirmed by a replica with a hash other than the local one
+type ReplicaDivergence struct {
+	TxID          uint64
+	ReplicaTxHash []byte
+	TxHash        []byte
+	DetectedAt    time.Time
+}
+
+type replicaState struct {
+	exportedTxID  uint64
+	confirmedTxID uint64
+	lastSeenAt    time.Time
+	txLimiter     rateLimiter
+	byteLimiter   rateLimiter
+	divergence    *ReplicaDivergence
+	downstream    []*ReplicaProgress
+}
+
+// rateLimiter spaces out exports so that their cost, in transactions or bytes, does not exceed a rate per second
+type rateLimiter struct {
+	// next is the earliest time the next export may happen
+	next time.Time
+}
+
+// wait returns how long the next export has to wait
+func (l *rateLimiter) wait(now time.Time) time.Duration {
+	if l.next.Before(now) {
+		return 0
+	}
+
+	return l.next.Sub(now)
+}
+
+// charge delays the exports following the one made at the given time by its cost
+func (l *rateLimiter) charge(at time.Time, cost, rate int) {
+	if rate <= 0 {
+		return
+	}
+
+	if l.next.Before(at) {
+		l.next = at
+	}
+
+	l.next = l.next.Add(time.Duration(cost) * time.Second / time.Duration(rate))
+}
+
+// replicaTracker tracks the progress of each replica of the database independently
+type replicaTracker struct {
+	mutex    sync.Mutex
+	replicas map[string]*replicaState
+	// changed gets closed and renewed on every confirmation
+	changed chan struct{}
+	// txLimiter and byteLimiter throttle the exports to all the replicas together
+	txLimiter   rateLimiter
+	byteLimiter rateLimiter
+}
+
+// stateOf must be called holding the tracker mutex
+func (rt *replicaTracker) stateOf(replicaID string) *replicaState {
+	if rt.replicas == nil {
+		rt.replicas = make(map[string]*replicaState)
+	}
+
+	rs, ok := rt.replicas[replicaID]
+	if !ok {
+		if len(rt.replicas) >= MaxTrackedReplicas {
+			rt.evictLeastRecentlySeen()
+		}
+
+		rs = &replicaState{}
+		rt.replicas[replicaID] = rs
+	}
+
+	rs.lastSeenAt = time.Now()
+
+	return rs
+}
+
+// evictLeastRecentlySeen must be called holding the tracker mutex
+func (rt *replicaTracker) evictLeastRecentlySeen() {
+	var staleID string
+	var stale *replicaState
+
+	for id, rs := range rt.replicas {
+		if stale == nil || rs.lastSeenAt.Before(stale.lastSeenAt) {
+			staleID = id
+			stale = rs
+		}
+	}
+
+	delete(rt.replicas, staleID)
+}
+
+func (rt *replicaTracker) exported(replicaID string, txID uint64) {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	rs := rt.stateOf(replicaID)
+
+	if rs.exportedTxID < txID {
+		rs.exportedTxID = txID
+	}
+}
+
+func (rt *replicaTracker) confirm(replicaID string, txID uint64) {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	rs := rt.stateOf(replicaID)
+
+	rs.divergence = nil
+
+	if rs.confirmedTxID >= txID {
+		return
+	}
+
+	rs.confirmedTxID = txID
+
+	if rs.exportedTxID < txID {
+		rs.exportedTxID = txID
+	}
+
+	if rt.changed != nil {
+		close(rt.changed)
+		rt.changed = nil
+	}
+}
+
+func (rt *replicaTracker) diverged(replicaID string, divergence *ReplicaDivergence) {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	rt.stateOf(replicaID).divergence = divergence
+}
+
+func (rt *replicaTracker) divergenceOf(replicaID string) *ReplicaDivergence {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	rs, ok := rt.replicas[replicaID]
+	if !ok {
+		return nil
+	}
+
+	return rs.divergence
+}
+
+func (rt *replicaTracker) reportDownstream(replicaID string, downstream []*ReplicaProgress) {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	rt.stateOf(replicaID).downstream = truncateDownstream(downstream, MaxDownstreamDepth)
+}
+
+// truncateDownstream drops the replicas deeper than the given depth, so a misbehaving replica can not grow the tree at will
+func truncateDownstream(downstream []*ReplicaProgress, depth int) []*ReplicaProgress {
+	if depth <= 0 || len(downstream) == 0 {
+		return nil
+	}
+
+	truncated := make([]*ReplicaProgress, len(downstream))
+
+	for i, p := range downstream {
+		cp := *p
+		cp.Downstream = truncateDownstream(p.Downstream, depth-1)
+		truncated[i] = &cp
+	}
+
+	return truncated
+}
+
+// confirmedBy returns the number of full replicas which confirmed the transaction, along with a channel closed on the next
+// confirmation. Filtered replicas are left out, as they only hold part of the entries of the transaction
+func (rt *replicaTracker) confirmedBy(txID uint64, filters map[string][][]byte) (int, <-chan struct{}) {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	n := 0
+	for id, rs := range rt.replicas {
+		if _, filtered := filters[id]; filtered {
+			continue
+		}
+
+		if rs.confirmedTxID >= txID {
+			n++
+		}
+	}
+
+	if rt.changed == nil {
+		rt.changed = make(chan struct{})
+	}
+
+	return n, rt.changed
+}
+
+// throttle blocks until the replica can be sent another transaction without exceeding the rates of the replica
+// and of the database. The transaction is accounted right away, so that concurrent exports get spaced out,
+// while its size is only known once exported and delays the following exports. The returned time is the one
+// of the export, to be passed to transferred
+func (rt *replicaTracker) throttle(replicaID string, opts *ReplicationOptions) time.Time {
+	rt.mutex.Lock()
+
+	rs := rt.stateOf(replicaID)
+
+	now := time.Now()
+
+	delay := rs.txLimiter.wait(now)
+	for _, d := range []time.Duration{rs.byteLimiter.wait(now), rt.txLimiter.wait(now), rt.byteLimiter.wait(now)} {
+		if d > delay {
+			delay = d
+		}
+	}
+
+	at := now.Add(delay)
+
+	rs.txLimiter.charge(at, 1, opts.MaxReplicaTxRate)
+	rt.txLimiter.charge(at, 1, opts.MaxReplicationTxRate)
+
+	rt.mutex.Unlock()
+
+	time.Sleep(delay)
+
+	return at
+}
+
+// transferred accounts the size of a transaction exported to the replica at the given time
+func (rt *replicaTracker) transferred(replicaID string, at time.Time, size int, opts *ReplicationOptions) {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	rs := rt.stateOf(replicaID)
+
+	rs.byteLimiter.charge(at, size, opts.MaxReplicaByteRate)
+	rt.byteLimiter.charge(at, size, opts.MaxReplicationByteRate)
+}
+
+// progress returns the progress of the replicas seen so far and of the filtered ones, even if not seen yet
+func (rt *replicaTracker) progress(filters map[string][][]byte) []*ReplicaProgress {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	progress := make([]*ReplicaProgress, 0, len(rt.replicas))
+
+	for id, rs := range rt.replicas {
+		progress = append(progress, &ReplicaProgress{
+			ReplicaID:     id,
+			ExportedTxID:  rs.exportedTxID,
+			ConfirmedTxID: rs.confirmedTxID,
+			LastSeenAt:    rs.lastSeenAt,
+			KeyPrefixes:   filters[id],
+			Divergence:    rs.divergence,
+			Downstream:    rs.downstream,
+		})
+	}
+
+	for id, prefixes := range filters {
+		if _, seen := rt.replicas[id]; !seen {
+			progress = append(progress, &ReplicaProgress{ReplicaID: id, KeyPrefixes: prefixes})
+		}
+	}
+
+	sort.Slice(progress, func(i, j int) bool { return progress[i].ReplicaID < progress[j].ReplicaID })
+
+	return progress
+}
+
+// ReplicationProgress returns the progress of the replicas which identified themselves when fetching transactions
+func (d *db) ReplicationProgress() []*ReplicaProgress {
+	return d.replicas.progress(d.options.GetReplicationOptions().ReplicaFilters)
+}
+
+// ReportDownstreamReplicas records the progress of the replicas fed by the given replica,
+// so that the progress of a whole replication tree can be followed from its root
+func (d *db) ReportDownstreamReplicas(replicaID string, downstream []*ReplicaProgress) {
+	d.replicas.reportDownstream(replicaID, downstream)
+}
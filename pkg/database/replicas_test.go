@@ -0,0 +1,218 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicationProgress(t *testing.T) {
+	rootPath := "data_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	options := DefaultOption().
+		WithDbRootPath(rootPath).
+		WithDbName("db").
+		WithReplicationOptions((&ReplicationOptions{}).WithMaxReplicaTxRate(10))
+
+	db, closer := makeDbWith(options)
+	defer closer()
+
+	require.Empty(t, db.ReplicationProgress())
+
+	var states []*schema.ImmutableState
+
+	for i := 0; i < 3; i++ {
+		_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+		require.NoError(t, err)
+
+		state, err := db.CurrentState()
+		require.NoError(t, err)
+
+		states = append(states, state)
+	}
+
+	state := states[2]
+
+	_, err := db.ExportTxByID(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	// exports without a replica id are neither tracked nor throttled
+	_, err = db.ExportTxByID(&schema.TxRequest{Tx: state.TxId})
+	require.NoError(t, err)
+	require.Empty(t, db.ReplicationProgress())
+
+	start := time.Now()
+
+	for tx := state.TxId - 2; tx <= state.TxId; tx++ {
+		_, err = db.ExportTxByID(&schema.TxRequest{Tx: tx, ReplicaId: "replica1"})
+		require.NoError(t, err)
+	}
+
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(200*time.Millisecond))
+
+	// each replica is throttled independently
+	start = time.Now()
+
+	_, err = db.ExportTxByID(&schema.TxRequest{Tx: state.TxId - 2, ReplicaId: "replica2"})
+	require.NoError(t, err)
+
+	require.Less(t, int64(time.Since(start)), int64(100*time.Millisecond))
+
+	err = db.ConfirmReplication("replica2", state.TxId-2, states[1].TxHash)
+	require.Equal(t, ErrReplicaDiverged, err)
+
+	// replication to a diverged replica is stopped
+	_, err = db.ExportTxByID(&schema.TxRequest{Tx: state.TxId - 1, ReplicaId: "replica2"})
+	require.Equal(t, ErrReplicationStopped, err)
+
+	progress := db.ReplicationProgress()
+	require.Len(t, progress, 2)
+	require.Nil(t, progress[0].Divergence)
+	require.NotNil(t, progress[1].Divergence)
+	require.Equal(t, state.TxId-2, progress[1].Divergence.TxID)
+	require.Equal(t, states[1].TxHash, progress[1].Divergence.ReplicaTxHash)
+	require.Equal(t, states[0].TxHash, progress[1].Divergence.TxHash)
+
+	// and resumed once it confirms a matching transaction
+	err = db.ConfirmReplication("replica2", state.TxId-2, states[0].TxHash)
+	require.NoError(t, err)
+
+	progress = db.ReplicationProgress()
+	require.Len(t, progress, 2)
+	require.Nil(t, progress[1].Divergence)
+
+	require.Equal(t, "replica1", progress[0].ReplicaID)
+	require.Equal(t, state.TxId, progress[0].ExportedTxID)
+	require.Equal(t, uint64(0), progress[0].ConfirmedTxID)
+
+	require.Equal(t, "replica2", progress[1].ReplicaID)
+	require.Equal(t, state.TxId-2, progress[1].ExportedTxID)
+	require.Equal(t, state.TxId-2, progress[1].ConfirmedTxID)
+	require.False(t, progress[1].LastSeenAt.IsZero())
+}
+
+func TestReplicationBandwidthThrottling(t *testing.T) {
+	rootPath := "data_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	options := DefaultOption().
+		WithDbRootPath(rootPath).
+		WithDbName("db")
+
+	db, closer := makeDbWith(options)
+	defer closer()
+
+	md, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+	require.NoError(t, err)
+
+	etx, err := db.ExportTxByID(&schema.TxRequest{Tx: md.Id})
+	require.NoError(t, err)
+
+	// each export delays the following ones to the same replica by 100ms
+	db.UpdateReplicationOptions((&ReplicationOptions{}).WithMaxReplicaByteRate(len(etx) * 10))
+
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		_, err = db.ExportTxByID(&schema.TxRequest{Tx: md.Id, ReplicaId: "replica1"})
+		require.NoError(t, err)
+	}
+
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(200*time.Millisecond))
+
+	start = time.Now()
+
+	_, err = db.ExportTxByID(&schema.TxRequest{Tx: md.Id, ReplicaId: "replica2"})
+	require.NoError(t, err)
+
+	require.Less(t, int64(time.Since(start)), int64(100*time.Millisecond))
+
+	// the rates of the database apply to all the replicas together
+	for _, opts := range []*ReplicationOptions{
+		(&ReplicationOptions{}).WithMaxReplicationTxRate(10),
+		(&ReplicationOptions{}).WithMaxReplicationByteRate(len(etx) * 10),
+	} {
+		db.UpdateReplicationOptions(opts)
+
+		time.Sleep(100 * time.Millisecond)
+
+		start = time.Now()
+
+		for _, replicaID := range []string{"replica1", "replica2", "replica3"} {
+			_, err = db.ExportTxByID(&schema.TxRequest{Tx: md.Id, ReplicaId: replicaID})
+			require.NoError(t, err)
+		}
+
+		require.GreaterOrEqual(t, int64(time.Since(start)), int64(200*time.Millisecond))
+	}
+}
+
+func TestReportDownstreamReplicas(t *testing.T) {
+	rootPath := "data_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	db, closer := makeDbWith(DefaultOption().WithDbRootPath(rootPath).WithDbName("db"))
+	defer closer()
+
+	// a replication tree deeper than kept
+	var downstream []*ReplicaProgress
+	for i := MaxDownstreamDepth + 2; i > 0; i-- {
+		downstream = []*ReplicaProgress{{ReplicaID: "replica" + strconv.Itoa(i), Downstream: downstream}}
+	}
+
+	db.ReportDownstreamReplicas("replica0", downstream)
+
+	progress := db.ReplicationProgress()
+	require.Len(t, progress, 1)
+	require.Equal(t, "replica0", progress[0].ReplicaID)
+
+	depth := 0
+	for p := progress[0].Downstream; len(p) > 0; p = p[0].Downstream {
+		depth++
+		require.Equal(t, "replica"+strconv.Itoa(depth), p[0].ReplicaID)
+	}
+	require.Equal(t, MaxDownstreamDepth, depth)
+
+	// the reported tree is left untouched
+	require.Len(t, downstream[0].Downstream[0].Downstream, 1)
+}
+
+func TestReplicaTrackerBounded(t *testing.T) {
+	var rt replicaTracker
+
+	seenAt := time.Now()
+
+	for i := 0; i < MaxTrackedReplicas; i++ {
+		rt.confirm("replica"+strconv.Itoa(i), 1)
+		rt.replicas["replica"+strconv.Itoa(i)].lastSeenAt = seenAt.Add(time.Duration(i) * time.Second)
+	}
+
+	// the first replica is seen again, the second one becomes the least recently seen
+	rt.replicas["replica0"].lastSeenAt = seenAt.Add(time.Hour)
+
+	rt.exported("unknown", 1)
+	require.Len(t, rt.replicas, MaxTrackedReplicas)
+	require.Contains(t, rt.replicas, "unknown")
+	require.Contains(t, rt.replicas, "replica0")
+	require.NotContains(t, rt.replicas, "replica1")
+
+	n, _ := rt.confirmedBy(1, nil)
+	require.Equal(t, MaxTrackedReplicas-1, n)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"sync"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// referrers keeps, for each key, the references currently pointing at it.
+// It's lazily loaded from the index and then kept up to date by reading the transactions
+// committed since, so references don't need any additional entry to be written
+type referrers struct {
+	mutex       sync.Mutex
+	loaded      bool
+	scannedUpTo uint64
+	tx          *store.Tx
+	refs        map[string]*schema.Reference
+	byKey       map[string]map[string]struct{}
+}
+
+// ReferencesTo lists the references currently pointing at req.ReferencedKey, sorted by reference key
+func (d *db) ReferencesTo(req *schema.ReferencesToRequest) (*schema.References, error) {
+	if req == nil || len(req.ReferencedKey) == 0 {
+		return nil, store.ErrIllegalArguments
+	}
+
+	if req.Limit > MaxKeyScanLimit {
+		return nil, ErrMaxKeyScanLimitExceeded
+	}
+
+	err := d.WaitForIndexingUpto(req.SinceTx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+
+	if req.Limit == 0 {
+		limit = MaxKeyScanLimit
+	}
+
+	refs, err := d.referencesTo(EncodeKey(req.ReferencedKey))
+	if err != nil {
+		return nil, err
+	}
+
+	list := &schema.References{}
+
+	for _, ref := range refs {
+		if len(list.References) == limit {
+			break
+		}
+
+		deleted, err := d.deletedByPrefix(EncodeKey(ref.Key), ref.Tx, 0)
+		if err != nil {
+			return nil, err
+		}
+		if deleted {
+			continue
+		}
+
+		list.References = append(list.References, ref)
+	}
+
+	return list, nil
+}
+
+func (d *db) referencesTo(referencedKey []byte) ([]*schema.Reference, error) {
+	rs := &d.referrers
+
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	err := d.refreshReferrers()
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []*schema.Reference
+
+	for key := range rs.byKey[string(referencedKey)] {
+		ref := rs.refs[key]
+
+		refs = append(refs, &schema.Reference{
+			Tx:   ref.Tx,
+			Key:  TrimPrefix([]byte(key)),
+			AtTx: ref.AtTx,
+		})
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return bytes.Compare(refs[i].Key, refs[j].Key) < 0
+	})
+
+	return refs, nil
+}
+
+// refreshReferrers must be called holding referrers.mutex
+func (d *db) refreshReferrers() error {
+	rs := &d.referrers
+
+	if !rs.loaded {
+		rs.tx = d.st.NewTx()
+		rs.refs = make(map[string]*schema.Reference)
+		rs.byKey = make(map[string]map[string]struct{})
+
+		snap, err := d.st.Snapshot()
+		if err != nil {
+			return err
+		}
+		defer snap.Close()
+
+		r, err := snap.NewKeyReader(&store.KeyReaderSpec{Prefix: []byte{SetKeyPrefix}})
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		for {
+			key, valRef, ktx, _, err := r.Read()
+			if err == store.ErrNoMoreEntries {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			val, err := valRef.Resolve()
+			if err != nil {
+				return err
+			}
+
+			rs.update(key, val, ktx)
+		}
+
+		rs.scannedUpTo = snap.Ts()
+		rs.loaded = true
+	}
+
+	lastTxID, _ := d.st.Alh()
+
+	for txID := rs.scannedUpTo + 1; txID <= lastTxID; txID++ {
+		err := d.st.ReadTx(txID, rs.tx)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range rs.tx.Entries() {
+			if e.Key()[0] != SetKeyPrefix {
+				continue
+			}
+
+			val, err := d.st.ReadValue(rs.tx, e.Key())
+			if err != nil {
+				return err
+			}
+
+			rs.update(e.Key(), val, txID)
+		}
+
+		rs.scannedUpTo = txID
+	}
+
+	return nil
+}
+
+// update records the value written for the encoded key at tx, either a reference or anything
+// else overwriting a previous one
+func (rs *referrers) update(key, val []byte, tx uint64) {
+	k := string(key)
+
+	if prev, ok := rs.refs[k]; ok {
+		referencedKey := string(EncodeKey(prev.Key))
+
+		delete(rs.byKey[referencedKey], k)
+		if len(rs.byKey[referencedKey]) == 0 {
+			delete(rs.byKey, referencedKey)
+		}
+
+		delete(rs.refs, k)
+	}
+
+	if len(val) == 0 || val[0] != ReferenceValuePrefix {
+		return
+	}
+
+	referencedKey := string(val[1+8:])
+
+	rs.refs[k] = &schema.Reference{
+		Tx:   tx,
+		Key:  TrimPrefix(val[1+8:]),
+		AtTx: binary.BigEndian.Uint64(val[1:]),
+	}
+
+	if rs.byKey[referencedKey] == nil {
+		rs.byKey[referencedKey] = make(map[string]struct{})
+	}
+	rs.byKey[referencedKey][k] = struct{}{}
+}
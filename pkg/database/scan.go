@@ -33,8 +33,46 @@ func (d *db) Scan(req *schema.ScanRequest) (*schema.Entries, error) {
 		return nil, ErrMaxKeyScanLimitExceeded
 	}
 
+	limit := req.Limit
+
+	if req.Limit == 0 {
+		limit = MaxKeyScanLimit
+	}
+
+	err := validateKeyPattern(req.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := newValueFilter(req.ValueFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor *scanCursor
+
+	if len(req.Cursor) > 0 {
+		c, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = c
+	}
+
+	if req.Snapshot != "" {
+		if req.SinceTx > 0 {
+			return nil, store.ErrIllegalArguments
+		}
+
+		return d.scanSnapshot(req, limit, cursor, filter)
+	}
+
 	waitUntilTx := req.SinceTx
 
+	if cursor != nil {
+		waitUntilTx = cursor.atTx
+	}
+
 	if waitUntilTx == 0 {
 		waitUntilTx, _ = d.st.Alh()
 	}
@@ -46,12 +84,6 @@ func (d *db) Scan(req *schema.ScanRequest) (*schema.Entries, error) {
 		}
 	}
 
-	limit := req.Limit
-
-	if req.Limit == 0 {
-		limit = MaxKeyScanLimit
-	}
-
 	var entries []*schema.Entry
 	i := uint64(0)
 
@@ -67,6 +99,14 @@ func (d *db) Scan(req *schema.ScanRequest) (*schema.Entries, error) {
 		seekKey = EncodeKey(req.SeekKey)
 	}
 
+	// keys are read as of the snapshot of the first page, when continuing from a cursor
+	var readAtTx uint64
+
+	if cursor != nil {
+		seekKey = cursor.lastKey
+		readAtTx = cursor.atTx
+	}
+
 	r, err := snap.NewKeyReader(
 		&store.KeyReaderSpec{
 			SeekKey:   seekKey,
@@ -78,8 +118,10 @@ func (d *db) Scan(req *schema.ScanRequest) (*schema.Entries, error) {
 	}
 	defer r.Close()
 
+	var nextCursor []byte
+
 	for {
-		key, _, tx, _, err := r.Read()
+		key, _, tx, err := readKey(r, readAtTx)
 		if err == store.ErrNoMoreEntries {
 			break
 		}
@@ -87,18 +129,48 @@ func (d *db) Scan(req *schema.ScanRequest) (*schema.Entries, error) {
 			return nil, err
 		}
 
-		e, err := d.getAt(key, tx, 0, snap, d.tx1)
+		if !matchesKeyPattern(req.Pattern, key) {
+			continue
+		}
+
+		e, err := d.getEntryAt(key, tx, 0, snap, d.tx1)
 		if err != nil {
 			return nil, err
 		}
 
+		if isHidden(e.Metadata) && !req.IncludeDeleted {
+			continue
+		}
+
+		if !req.IncludeDeleted {
+			deleted, err := d.deletedByPrefix(key, tx, readAtTx)
+			if err != nil {
+				return nil, err
+			}
+
+			if deleted {
+				continue
+			}
+		}
+
+		if !filter.matches(e.Value) {
+			continue
+		}
+
 		entries = append(entries, e)
 		if i++; i == limit {
+			atTx := readAtTx
+			if atTx == 0 {
+				atTx = snap.Ts()
+			}
+
+			nextCursor = encodeCursor(atTx, key)
 			break
 		}
 	}
 
 	return &schema.Entries{
-		Entries: entries,
+		Entries:    entries,
+		NextCursor: nextCursor,
 	}, nil
 }
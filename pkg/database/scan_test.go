@@ -180,3 +180,157 @@ func TestStoreScanDesc(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, list.Entries, 3)
 }
+
+func TestStoreScanCursor(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.Scan(&schema.ScanRequest{Cursor: []byte{0}})
+	require.Equal(t, ErrInvalidCursor, err)
+
+	for _, k := range []string{"k1", "k2", "k3", "k4", "k5"} {
+		_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte(k), Value: []byte("v1")}}})
+		require.NoError(t, err)
+	}
+
+	page, err := db.Scan(&schema.ScanRequest{Prefix: []byte("k"), Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	require.Equal(t, []byte("k2"), page.Entries[1].Key)
+	require.NotEmpty(t, page.NextCursor)
+
+	// writes done while paginating are not visible
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte("k0"), Value: []byte("v1")},
+		{Key: []byte("k3a"), Value: []byte("v1")},
+		{Key: []byte("k4"), Value: []byte("v2")},
+	}})
+	require.NoError(t, err)
+
+	page, err = db.Scan(&schema.ScanRequest{Prefix: []byte("k"), Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	require.Equal(t, []byte("k3"), page.Entries[0].Key)
+	require.Equal(t, []byte("k4"), page.Entries[1].Key)
+	require.Equal(t, []byte("v1"), page.Entries[1].Value)
+
+	page, err = db.Scan(&schema.ScanRequest{Prefix: []byte("k"), Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	require.Equal(t, []byte("k5"), page.Entries[0].Key)
+	require.Empty(t, page.NextCursor)
+
+	page, err = db.Scan(&schema.ScanRequest{Prefix: []byte("k"), Limit: 3, Desc: true})
+	require.NoError(t, err)
+	require.Equal(t, []byte("k3a"), page.Entries[2].Key)
+
+	page, err = db.Scan(&schema.ScanRequest{Prefix: []byte("k"), Limit: 10, Desc: true, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 4)
+	require.Equal(t, []byte("k0"), page.Entries[3].Key)
+	require.Empty(t, page.NextCursor)
+
+	_, err = db.OpenSnapshot(&schema.OpenSnapshotRequest{Name: "snap1"})
+	require.NoError(t, err)
+
+	page, err = db.Scan(&schema.ScanRequest{Prefix: []byte("k"), Limit: 4, Snapshot: "snap1"})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 4)
+
+	page, err = db.Scan(&schema.ScanRequest{Prefix: []byte("k"), Limit: 4, Snapshot: "snap1", Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 3)
+	require.Equal(t, []byte("k5"), page.Entries[2].Key)
+	require.Empty(t, page.NextCursor)
+}
+
+func TestStoreScanPattern(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.Scan(&schema.ScanRequest{Pattern: "invoices/["})
+	require.Equal(t, ErrInvalidKeyPattern, err)
+
+	for _, k := range []string{
+		"invoices/acme/2023-01",
+		"invoices/acme/2023-02",
+		"invoices/acme/2022-12",
+		"invoices/globex/2023-03",
+		"invoices/globex/drafts/2023-04",
+	} {
+		_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte(k), Value: []byte("v1")}}})
+		require.NoError(t, err)
+	}
+
+	list, err := db.Scan(&schema.ScanRequest{Prefix: []byte("invoices/"), Pattern: "invoices/*/2023-??"})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 3)
+	require.Equal(t, []byte("invoices/acme/2023-01"), list.Entries[0].Key)
+	require.Equal(t, []byte("invoices/globex/2023-03"), list.Entries[2].Key)
+
+	page, err := db.Scan(&schema.ScanRequest{Pattern: "invoices/*/2023-??", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = db.Scan(&schema.ScanRequest{Pattern: "invoices/*/2023-??", Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	require.Equal(t, []byte("invoices/globex/2023-03"), page.Entries[0].Key)
+
+	_, err = db.OpenSnapshot(&schema.OpenSnapshotRequest{Name: "snap1"})
+	require.NoError(t, err)
+
+	list, err = db.Scan(&schema.ScanRequest{Pattern: "invoices/acme/*", Snapshot: "snap1"})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 3)
+}
+
+func TestStoreScanValueFilter(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.Scan(&schema.ScanRequest{ValueFilter: &schema.ValueFilter{MinSize: 10, MaxSize: 5}})
+	require.Equal(t, ErrInvalidValueFilter, err)
+
+	_, err = db.Scan(&schema.ScanRequest{ValueFilter: &schema.ValueFilter{JsonField: "status", JsonValue: "paid"}})
+	require.Equal(t, ErrInvalidValueFilter, err)
+
+	kvs := []*schema.KeyValue{
+		{Key: []byte("doc1"), Value: []byte(`{"status":"paid","customer":{"id":1}}`)},
+		{Key: []byte("doc2"), Value: []byte(`{"status":"open","customer":{"id":2}}`)},
+		{Key: []byte("doc3"), Value: []byte(`{"status":"paid","customer":{"id":2}}`)},
+		{Key: []byte("doc4"), Value: []byte("not json")},
+	}
+
+	_, err = db.Set(&schema.SetRequest{KVs: kvs})
+	require.NoError(t, err)
+
+	list, err := db.Scan(&schema.ScanRequest{Prefix: []byte("doc"), ValueFilter: &schema.ValueFilter{Prefix: []byte("not")}})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 1)
+	require.Equal(t, []byte("doc4"), list.Entries[0].Key)
+
+	list, err = db.Scan(&schema.ScanRequest{Prefix: []byte("doc"), ValueFilter: &schema.ValueFilter{MinSize: 1, MaxSize: 10}})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 1)
+
+	list, err = db.Scan(&schema.ScanRequest{Prefix: []byte("doc"), ValueFilter: &schema.ValueFilter{JsonField: "status", JsonValue: `"paid"`}})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 2)
+	require.Equal(t, []byte("doc1"), list.Entries[0].Key)
+	require.Equal(t, []byte("doc3"), list.Entries[1].Key)
+
+	list, err = db.Scan(&schema.ScanRequest{Prefix: []byte("doc"), ValueFilter: &schema.ValueFilter{JsonField: "customer.id", JsonValue: "2"}})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 2)
+	require.Equal(t, []byte("doc2"), list.Entries[0].Key)
+
+	_, err = db.OpenSnapshot(&schema.OpenSnapshotRequest{Name: "snap1"})
+	require.NoError(t, err)
+
+	list, err = db.Scan(&schema.ScanRequest{Snapshot: "snap1", ValueFilter: &schema.ValueFilter{JsonField: "customer.id", JsonValue: "1"}})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 1)
+	require.Equal(t, []byte("doc1"), list.Entries[0].Key)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+// scrubBatchSize is the number of transactions verified at once while scrubbing
+const scrubBatchSize = 100
+
+// TxSource provides exported copies of the transactions of a database, e.g. out of one of its replicas
+type TxSource interface {
+	ExportTx(txID uint64) ([]byte, error)
+}
+
+// TxSourceFunc adapts a function to a TxSource
+type TxSourceFunc func(txID uint64) ([]byte, error)
+
+// ExportTx calls f(txID)
+func (f TxSourceFunc) ExportTx(txID uint64) ([]byte, error) {
+	return f(txID)
+}
+
+// ScrubReport summarizes the outcome of a scrub
+type ScrubReport struct {
+	TxCount         uint64
+	RepairedTxs     []uint64
+	RepairedEntries uint64
+	// UnrepairedTxs holds the damaged transactions that could not be recovered from the source
+	UnrepairedTxs []uint64
+}
+
+// Scrub verifies every committed transaction and recovers the damaged values out of the copies provided by the source.
+// Only values matching the digests of the intact local transactions are accepted, damaged transactions are left as they
+// are otherwise
+func (d *db) Scrub(source TxSource) (*ScrubReport, error) {
+	if source == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	report := &ScrubReport{}
+
+	for sinceTx := uint64(1); ; {
+		verification, err := d.VerifyTxs(sinceTx, scrubBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if verification.TxCount == 0 {
+			return report, nil
+		}
+
+		report.TxCount += verification.TxCount
+
+		for _, txID := range verification.FailedTxs {
+			repaired, err := d.repairTx(source, txID)
+			if err != nil {
+				d.Logger.Errorf("Unable to repair tx %d of database '%s': %v", txID, d.name, err)
+				report.UnrepairedTxs = append(report.UnrepairedTxs, txID)
+				continue
+			}
+
+			d.Logger.Infof("Tx %d of database '%s' repaired, %d entries recovered", txID, d.name, repaired)
+
+			report.RepairedTxs = append(report.RepairedTxs, txID)
+			report.RepairedEntries += uint64(repaired)
+		}
+
+		sinceTx = verification.LastTx + 1
+	}
+}
+
+func (d *db) repairTx(source TxSource, txID uint64) (int, error) {
+	exportedTx, err := source.ExportTx(txID)
+	if err != nil {
+		return 0, err
+	}
+
+	repaired, err := d.st.RepairTx(exportedTx)
+	if err != nil {
+		return 0, err
+	}
+
+	verification, err := d.VerifyTxs(txID, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	if verification.FailedVerifications > 0 {
+		return 0, ErrTxNotRepaired
+	}
+
+	return repaired, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrub(t *testing.T) {
+	rootPath := "data_scrub_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	defer os.RemoveAll(rootPath)
+
+	options := DefaultOption().WithDbRootPath(rootPath).WithDbName("db")
+
+	db, err := NewDb(options, nil, logger.NewSimpleLogger("immudb ", os.Stderr))
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("scrubbed-value")}}})
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.NoError(t, err)
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+
+	// copies of the transactions as a replica would provide them
+	exportedTxs := make(map[uint64][]byte)
+
+	for txID := uint64(1); txID <= state.TxId; txID++ {
+		exportedTxs[txID], err = db.ExportTxByID(&schema.TxRequest{Tx: txID})
+		require.NoError(t, err)
+	}
+
+	err = db.Close()
+	require.NoError(t, err)
+
+	// damage the value log of the database
+	files, err := filepath.Glob(filepath.Join(rootPath, "db", "val_*", "*.val"))
+	require.NoError(t, err)
+
+	for _, f := range files {
+		content, err := ioutil.ReadFile(f)
+		require.NoError(t, err)
+
+		err = ioutil.WriteFile(f, bytes.ReplaceAll(content, []byte("scrubbed-value"), []byte("SCRUBBED-VALUE")), 0644)
+		require.NoError(t, err)
+	}
+
+	db, err = OpenDb(options, nil, logger.NewSimpleLogger("immudb ", os.Stderr))
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Scrub(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	failingSource := TxSourceFunc(func(txID uint64) ([]byte, error) {
+		return nil, errors.New("source unavailable")
+	})
+
+	report, err := db.Scrub(failingSource)
+	require.NoError(t, err)
+	require.Equal(t, state.TxId, report.TxCount)
+	require.Len(t, report.UnrepairedTxs, 1)
+	require.Empty(t, report.RepairedTxs)
+
+	damagedTx := report.UnrepairedTxs[0]
+
+	source := TxSourceFunc(func(txID uint64) ([]byte, error) {
+		return exportedTxs[txID], nil
+	})
+
+	report, err = db.Scrub(source)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{damagedTx}, report.RepairedTxs)
+	require.Equal(t, uint64(1), report.RepairedEntries)
+	require.Empty(t, report.UnrepairedTxs)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("key1")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("scrubbed-value"), entry.Value)
+
+	verification, err := db.VerifyTxs(1, int(state.TxId))
+	require.NoError(t, err)
+	require.Zero(t, verification.FailedVerifications)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// SetConditional writes a key only if it currently holds the expected value, or the value written at the
+// expected transaction. An expected transaction equal to zero requires the key to not exist yet.
+// The check is done by the store while committing, so concurrent writers can't lose updates.
+// ErrPreconditionFailed is returned when the key doesn't match the expectation
+func (d *db) SetConditional(req *schema.SetConditionalRequest) (res *schema.TxMetadata, err error) {
+	defer func() { err = d.awaitReplicas(res.GetId(), err) }()
+
+	if req == nil || req.Kv == nil || len(req.Kv.Key) == 0 || req.Expected == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.isReplica() {
+		return nil, ErrIsReplica
+	}
+
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
+	e, err := d.encodeKeyValue(req.Kv, false)
+	if err != nil {
+		return nil, err
+	}
+
+	switch expected := req.Expected.(type) {
+	case *schema.SetConditionalRequest_ExpectedTx:
+		if expected.ExpectedTx == 0 {
+			e.Constraint = store.MustNotExist
+		} else {
+			e.Constraint = store.MustMatchTx
+			e.ExpectedTx = expected.ExpectedTx
+		}
+	case *schema.SetConditionalRequest_ExpectedValue:
+		txID, err := d.currentTxOf(e.Key, expected.ExpectedValue)
+		if err != nil {
+			return nil, err
+		}
+
+		// the value must still be the same one when the write gets committed
+		e.Constraint = store.MustMatchTx
+		e.ExpectedTx = txID
+	}
+
+	md, err := d.st.Commit([]*store.KV{e}, !req.NoWait)
+	if err == store.ErrPreconditionFailed || err == store.ErrKeyAlreadyExists {
+		return nil, ErrPreconditionFailed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.TxMetatadaTo(md), nil
+}
+
+// currentTxOf returns the transaction which wrote the current value of the key, provided it's the expected one
+func (d *db) currentTxOf(key []byte, expectedValue []byte) (uint64, error) {
+	lastTxID, _ := d.st.Alh()
+
+	err := d.st.WaitForIndexingUpto(lastTxID, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	val, txID, _, err := d.st.Get(key)
+	if err == store.ErrKeyNotFound {
+		return 0, ErrPreconditionFailed
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	md, val, err := UnwrapMetadata(val)
+	if err != nil {
+		return 0, err
+	}
+
+	if isHidden(md) || len(val) == 0 || val[0] != PlainValuePrefix || !bytes.Equal(TrimPrefix(val), expectedValue) {
+		return 0, ErrPreconditionFailed
+	}
+
+	deleted, err := d.deletedByPrefix(key, txID, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	if deleted {
+		return 0, ErrPreconditionFailed
+	}
+
+	return txID, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetConditional(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.SetConditional(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.SetConditional(&schema.SetConditionalRequest{Kv: &schema.KeyValue{Key: []byte("key1"), Value: []byte("v1")}})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.SetConditional(&schema.SetConditionalRequest{
+		Kv:       &schema.KeyValue{Key: []byte("key1"), Value: []byte("v1")},
+		Expected: &schema.SetConditionalRequest_ExpectedValue{ExpectedValue: []byte("v0")},
+	})
+	require.Equal(t, ErrPreconditionFailed, err)
+
+	md1, err := db.SetConditional(&schema.SetConditionalRequest{
+		Kv:       &schema.KeyValue{Key: []byte("key1"), Value: []byte("v1")},
+		Expected: &schema.SetConditionalRequest_ExpectedTx{ExpectedTx: 0},
+	})
+	require.NoError(t, err)
+
+	_, err = db.SetConditional(&schema.SetConditionalRequest{
+		Kv:       &schema.KeyValue{Key: []byte("key1"), Value: []byte("v1")},
+		Expected: &schema.SetConditionalRequest_ExpectedTx{ExpectedTx: 0},
+	})
+	require.Equal(t, ErrPreconditionFailed, err)
+
+	_, err = db.SetConditional(&schema.SetConditionalRequest{
+		Kv:       &schema.KeyValue{Key: []byte("key1"), Value: []byte("v2")},
+		Expected: &schema.SetConditionalRequest_ExpectedTx{ExpectedTx: md1.Id + 1},
+	})
+	require.Equal(t, ErrPreconditionFailed, err)
+
+	md2, err := db.SetConditional(&schema.SetConditionalRequest{
+		Kv:       &schema.KeyValue{Key: []byte("key1"), Value: []byte("v2")},
+		Expected: &schema.SetConditionalRequest_ExpectedTx{ExpectedTx: md1.Id},
+	})
+	require.NoError(t, err)
+
+	_, err = db.SetConditional(&schema.SetConditionalRequest{
+		Kv:       &schema.KeyValue{Key: []byte("key1"), Value: []byte("v3")},
+		Expected: &schema.SetConditionalRequest_ExpectedValue{ExpectedValue: []byte("v1")},
+	})
+	require.Equal(t, ErrPreconditionFailed, err)
+
+	md3, err := db.SetConditional(&schema.SetConditionalRequest{
+		Kv:       &schema.KeyValue{Key: []byte("key1"), Value: []byte("v3")},
+		Expected: &schema.SetConditionalRequest_ExpectedValue{ExpectedValue: []byte("v2")},
+	})
+	require.NoError(t, err)
+	require.Greater(t, md3.Id, md2.Id)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("key1")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v3"), entry.Value)
+	require.Equal(t, md3.Id, entry.Tx)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// MaxSnapshots is the maximum number of named snapshots simultaneously open on a database,
+// each of them keeps the index pinned until it's released
+const MaxSnapshots = 16
+
+// snapshotHistoryPageSize is the number of key versions read at once while looking for the one visible in a snapshot
+const snapshotHistoryPageSize = 100
+
+type snapshotHandle struct {
+	snap     *store.Snapshot
+	txID     uint64
+	openedAt time.Time
+}
+
+// pinnedIndex resolves keys as they were at a given transaction, even if the underlying
+// index snapshot includes newer ones
+type pinnedIndex struct {
+	d    *db
+	snap *store.Snapshot
+	txID uint64
+}
+
+func (p *pinnedIndex) Get(key []byte) (val []byte, tx uint64, hc uint64, err error) {
+	val, tx, hc, err = p.snap.Get(key)
+	if err != nil || tx <= p.txID {
+		return val, tx, hc, err
+	}
+
+	for offset := uint64(0); offset < hc; offset += snapshotHistoryPageSize {
+		tss, err := p.snap.History(key, offset, true, snapshotHistoryPageSize)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+
+		for i, ts := range tss {
+			if ts > p.txID {
+				continue
+			}
+
+			val, err = p.d.readValue(key, ts, p.d.tx2)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+
+			return val, ts, hc - offset - uint64(i), nil
+		}
+	}
+
+	return nil, 0, 0, store.ErrKeyNotFound
+}
+
+// OpenSnapshot opens a named read snapshot of the database at the given transaction, or the last committed one.
+// Get and Scan requests referring to the snapshot see the database as it was at that transaction until it's released
+func (d *db) OpenSnapshot(req *schema.OpenSnapshotRequest) (*schema.OpenSnapshotResponse, error) {
+	if req == nil || req.Name == "" {
+		return nil, ErrIllegalArguments
+	}
+
+	txID := req.AtTx
+
+	if txID == 0 {
+		txID, _ = d.st.Alh()
+	}
+
+	lastTxID, _ := d.st.Alh()
+	if txID > lastTxID {
+		return nil, ErrIllegalArguments
+	}
+
+	err := d.st.WaitForIndexingUpto(txID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	d.snapshotsMutex.Lock()
+	defer d.snapshotsMutex.Unlock()
+
+	if _, ok := d.snapshots[req.Name]; ok {
+		return nil, ErrSnapshotAlreadyExists
+	}
+
+	if len(d.snapshots) >= MaxSnapshots {
+		return nil, ErrMaxSnapshotsExceeded
+	}
+
+	snap, err := d.st.SnapshotSince(txID)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.snapshots == nil {
+		d.snapshots = make(map[string]*snapshotHandle)
+	}
+
+	d.snapshots[req.Name] = &snapshotHandle{
+		snap:     snap,
+		txID:     txID,
+		openedAt: time.Now(),
+	}
+
+	return &schema.OpenSnapshotResponse{Name: req.Name, TxId: txID}, nil
+}
+
+// ReleaseSnapshot closes a named snapshot
+func (d *db) ReleaseSnapshot(req *schema.ReleaseSnapshotRequest) error {
+	if req == nil {
+		return ErrIllegalArguments
+	}
+
+	d.snapshotsMutex.Lock()
+	defer d.snapshotsMutex.Unlock()
+
+	h, ok := d.snapshots[req.Name]
+	if !ok {
+		return ErrSnapshotNotFound
+	}
+
+	delete(d.snapshots, req.Name)
+
+	return h.snap.Close()
+}
+
+func (d *db) releaseSnapshots() {
+	d.snapshotsMutex.Lock()
+	defer d.snapshotsMutex.Unlock()
+
+	for name, h := range d.snapshots {
+		h.snap.Close()
+		delete(d.snapshots, name)
+	}
+}
+
+// pinnedIndex returns the index of a named snapshot, the caller must hold d.mutex
+func (d *db) pinnedIndex(name string) (*pinnedIndex, error) {
+	d.snapshotsMutex.Lock()
+	defer d.snapshotsMutex.Unlock()
+
+	h, ok := d.snapshots[name]
+	if !ok {
+		return nil, ErrSnapshotNotFound
+	}
+
+	return &pinnedIndex{d: d, snap: h.snap, txID: h.txID}, nil
+}
+
+// scanSnapshot scans a named snapshot, the caller must hold d.mutex
+func (d *db) scanSnapshot(req *schema.ScanRequest, limit uint64, cursor *scanCursor, filter *valueFilter) (*schema.Entries, error) {
+	index, err := d.pinnedIndex(req.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	seekKey := req.SeekKey
+
+	if len(seekKey) > 0 {
+		seekKey = EncodeKey(req.SeekKey)
+	}
+
+	if cursor != nil {
+		seekKey = cursor.lastKey
+	}
+
+	r, err := index.snap.NewKeyReader(
+		&store.KeyReaderSpec{
+			SeekKey:   seekKey,
+			Prefix:    EncodeKey(req.Prefix),
+			DescOrder: req.Desc,
+		})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var entries []*schema.Entry
+	var nextCursor []byte
+
+	for uint64(len(entries)) < limit {
+		key, _, _, _, err := r.Read()
+		if err == store.ErrNoMoreEntries {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !matchesKeyPattern(req.Pattern, key) {
+			continue
+		}
+
+		e, err := d.getEntryAt(key, 0, 0, index, d.tx1)
+		if err == store.ErrKeyNotFound {
+			// the key was created after the snapshot transaction
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if isHidden(e.Metadata) && !req.IncludeDeleted {
+			continue
+		}
+
+		if !req.IncludeDeleted {
+			deleted, err := d.deletedByPrefix(key, keyTxOf(e), index.txID)
+			if err != nil {
+				return nil, err
+			}
+
+			if deleted {
+				continue
+			}
+		}
+
+		if !filter.matches(e.Value) {
+			continue
+		}
+
+		entries = append(entries, e)
+
+		if uint64(len(entries)) == limit {
+			nextCursor = encodeCursor(index.txID, key)
+		}
+	}
+
+	return &schema.Entries{
+		Entries:    entries,
+		NextCursor: nextCursor,
+	}, nil
+}
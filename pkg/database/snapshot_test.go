@@ -0,0 +1,137 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshots(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.OpenSnapshot(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.OpenSnapshot(&schema.OpenSnapshotRequest{})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	set := func(key, value string) *schema.TxMetadata {
+		md, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte(key), Value: []byte(value)}}})
+		require.NoError(t, err)
+		return md
+	}
+
+	set("a", "a1")
+	set("b", "b1")
+	md := set("a", "a2")
+
+	snap, err := db.OpenSnapshot(&schema.OpenSnapshotRequest{Name: "dump"})
+	require.NoError(t, err)
+	require.Equal(t, md.Id, snap.TxId)
+
+	_, err = db.OpenSnapshot(&schema.OpenSnapshotRequest{Name: "dump"})
+	require.Equal(t, ErrSnapshotAlreadyExists, err)
+
+	_, err = db.OpenSnapshot(&schema.OpenSnapshotRequest{Name: "future", AtTx: md.Id + 1})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	// snapshots at a previous transaction
+	old, err := db.OpenSnapshot(&schema.OpenSnapshotRequest{Name: "old", AtTx: md.Id - 1})
+	require.NoError(t, err)
+	require.Equal(t, md.Id-1, old.TxId)
+
+	// changes made after the snapshot are not visible
+	set("a", "a3")
+	set("c", "c1")
+	set("b", "b2")
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("a"), Snapshot: "dump"})
+	require.NoError(t, err)
+	require.Equal(t, []byte("a2"), entry.Value)
+	require.Equal(t, md.Id, entry.Tx)
+
+	entry, err = db.Get(&schema.KeyRequest{Key: []byte("a"), Snapshot: "old"})
+	require.NoError(t, err)
+	require.Equal(t, []byte("a1"), entry.Value)
+
+	// older versions are found across several pages of history
+	for i := 0; i < 2*snapshotHistoryPageSize; i++ {
+		set("a", "a4")
+	}
+
+	entry, err = db.Get(&schema.KeyRequest{Key: []byte("a"), Snapshot: "old"})
+	require.NoError(t, err)
+	require.Equal(t, []byte("a1"), entry.Value)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("c"), Snapshot: "dump"})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("a"), Snapshot: "dump", SinceTx: 1})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("a"), Snapshot: "missing"})
+	require.Equal(t, ErrSnapshotNotFound, err)
+
+	entries, err := db.Scan(&schema.ScanRequest{Snapshot: "dump"})
+	require.NoError(t, err)
+	require.Len(t, entries.Entries, 2)
+	require.Equal(t, []byte("a"), entries.Entries[0].Key)
+	require.Equal(t, []byte("a2"), entries.Entries[0].Value)
+	require.Equal(t, []byte("b"), entries.Entries[1].Key)
+	require.Equal(t, []byte("b1"), entries.Entries[1].Value)
+
+	entries, err = db.Scan(&schema.ScanRequest{Snapshot: "dump", Desc: true, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, entries.Entries, 1)
+	require.Equal(t, []byte("b"), entries.Entries[0].Key)
+
+	_, err = db.Scan(&schema.ScanRequest{Snapshot: "dump", SinceTx: 1})
+	require.Equal(t, store.ErrIllegalArguments, err)
+
+	_, err = db.Scan(&schema.ScanRequest{Snapshot: "missing"})
+	require.Equal(t, ErrSnapshotNotFound, err)
+
+	entries, err = db.Scan(&schema.ScanRequest{})
+	require.NoError(t, err)
+	require.Len(t, entries.Entries, 3)
+
+	err = db.ReleaseSnapshot(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = db.ReleaseSnapshot(&schema.ReleaseSnapshotRequest{Name: "dump"})
+	require.NoError(t, err)
+
+	err = db.ReleaseSnapshot(&schema.ReleaseSnapshotRequest{Name: "dump"})
+	require.Equal(t, ErrSnapshotNotFound, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("a"), Snapshot: "dump"})
+	require.Equal(t, ErrSnapshotNotFound, err)
+
+	for i := 1; i < MaxSnapshots; i++ {
+		_, err = db.OpenSnapshot(&schema.OpenSnapshotRequest{Name: fmt.Sprintf("snap%d", i)})
+		require.NoError(t, err)
+	}
+
+	_, err = db.OpenSnapshot(&schema.OpenSnapshotRequest{Name: "exceeding"})
+	require.Equal(t, ErrMaxSnapshotsExceeded, err)
+}
@@ -16,6 +16,7 @@ limitations under the License.
 package database
 
 import (
+	"bytes"
 	"encoding/binary"
 	"math"
 
@@ -32,7 +33,9 @@ const txIDLen = 8
 // As a parameter of ZAddOptions is possible to provide the associated index of the provided key. In this way, when resolving reference, the specified version of the key will be returned.
 // If the index is not provided the resolution will use only the key and last version of the item will be returned
 // If ZAddOptions.index is provided key is optional
-func (d *db) ZAdd(req *schema.ZAddRequest) (*schema.TxMetadata, error) {
+func (d *db) ZAdd(req *schema.ZAddRequest) (res *schema.TxMetadata, err error) {
+	defer func() { err = d.awaitReplicas(res.GetId(), err) }()
+
 	if req == nil || len(req.Set) == 0 || len(req.Key) == 0 {
 		return nil, store.ErrIllegalArguments
 	}
@@ -48,8 +51,20 @@ func (d *db) ZAdd(req *schema.ZAddRequest) (*schema.TxMetadata, error) {
 		return nil, ErrIsReplica
 	}
 
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
 	lastTxID, _ := d.st.Alh()
-	err := d.st.WaitForIndexingUpto(lastTxID, nil)
+	err = d.st.WaitForIndexingUpto(lastTxID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -70,17 +85,99 @@ func (d *db) ZAdd(req *schema.ZAddRequest) (*schema.TxMetadata, error) {
 	return schema.TxMetatadaTo(meta), err
 }
 
+// ZRem logically removes an element from a sorted set by appending a tombstone for it.
+// The element is identified by the same set, score, key and atTx it was added with.
+// Removed elements are skipped by ZScan, but both the addition and the removal remain in the history
+func (d *db) ZRem(req *schema.ZRemRequest) (res *schema.TxMetadata, err error) {
+	defer func() { err = d.awaitReplicas(res.GetId(), err) }()
+
+	if req == nil || len(req.Set) == 0 || len(req.Key) == 0 {
+		return nil, store.ErrIllegalArguments
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.isReplica() {
+		return nil, ErrIsReplica
+	}
+
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
+	lastTxID, _ := d.st.Alh()
+	err = d.st.WaitForIndexingUpto(lastTxID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	key := EncodeKey(req.Key)
+
+	val, txID, _, err := d.st.Get(WrapZAddReferenceAt(req.Set, req.Score, key, req.AtTx))
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := isZRemoved(val)
+	if err != nil {
+		return nil, err
+	}
+	if removed {
+		return nil, store.ErrKeyNotFound
+	}
+
+	// the element must not be removed or added again by someone else before the tombstone gets committed
+	e := EncodeZRem(req.Set, req.Score, key, req.AtTx)
+	e.Constraint = store.MustMatchTx
+	e.ExpectedTx = txID
+
+	meta, err := d.st.Commit([]*store.KV{e}, !req.NoWait)
+	if err == store.ErrPreconditionFailed {
+		return nil, ErrPreconditionFailed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.TxMetatadaTo(meta), nil
+}
+
+// isZRemoved returns if the value of a sorted set element is a ZRem tombstone
+func isZRemoved(val []byte) (bool, error) {
+	md, _, err := UnwrapMetadata(val)
+	if err != nil {
+		return false, err
+	}
+
+	return md.GetDeleted(), nil
+}
+
 // ZScan ...
 func (d *db) ZScan(req *schema.ZScanRequest) (*schema.ZEntries, error) {
+	list, _, err := d.zScan(req)
+	return list, err
+}
+
+// zScan returns the elements of a sorted set along with the transactions which added them
+func (d *db) zScan(req *schema.ZScanRequest) (*schema.ZEntries, []uint64, error) {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
 	if req == nil || len(req.Set) == 0 {
-		return nil, store.ErrIllegalArguments
+		return nil, nil, store.ErrIllegalArguments
 	}
 
 	if req.Limit > MaxKeyScanLimit {
-		return nil, ErrMaxKeyScanLimitExceeded
+		return nil, nil, ErrMaxKeyScanLimitExceeded
 	}
 
 	limit := req.Limit
@@ -89,10 +186,7 @@ func (d *db) ZScan(req *schema.ZScanRequest) (*schema.ZEntries, error) {
 		limit = MaxKeyScanLimit
 	}
 
-	prefix := make([]byte, 1+setLenLen+len(req.Set))
-	prefix[0] = SortedSetKeyPrefix
-	binary.BigEndian.PutUint64(prefix[1:], uint64(len(req.Set)))
-	copy(prefix[1+setLenLen:], req.Set)
+	prefix := sortedSetPrefix(req.Set)
 
 	var seekKey []byte
 
@@ -103,9 +197,11 @@ func (d *db) ZScan(req *schema.ZScanRequest) (*schema.ZEntries, error) {
 		if req.MinScore != nil && !req.Desc {
 			binary.BigEndian.PutUint64(seekKey[len(prefix):], math.Float64bits(req.MinScore.Score))
 		}
-		// here we compose the offset if Max score filter is provided only if is reversed order
+		// here we compose the offset if Max score filter is provided only if is reversed order.
+		// The offset is padded so it sorts after the elements scored exactly as the max score
 		if req.MaxScore != nil && req.Desc {
 			binary.BigEndian.PutUint64(seekKey[len(prefix):], math.Float64bits(req.MaxScore.Score))
+			seekKey = append(seekKey, bytes.Repeat([]byte{0xFF}, keyLenLen)...)
 		}
 	} else {
 		seekKey = make([]byte, len(prefix)+scoreLen+keyLenLen+1+len(req.SeekKey)+txIDLen)
@@ -116,8 +212,25 @@ func (d *db) ZScan(req *schema.ZScanRequest) (*schema.ZEntries, error) {
 		binary.BigEndian.PutUint64(seekKey[len(prefix)+scoreLen+keyLenLen+1+len(req.SeekKey):], req.SeekAtTx)
 	}
 
+	// keys are read as of the snapshot of the first page, when continuing from a cursor
+	var readAtTx uint64
+
+	if len(req.Cursor) > 0 {
+		cursor, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		seekKey = cursor.lastKey
+		readAtTx = cursor.atTx
+	}
+
 	waitUntilTx := req.SinceTx
 
+	if readAtTx > 0 {
+		waitUntilTx = readAtTx
+	}
+
 	if waitUntilTx == 0 {
 		waitUntilTx, _ = d.st.Alh()
 	}
@@ -125,13 +238,13 @@ func (d *db) ZScan(req *schema.ZScanRequest) (*schema.ZEntries, error) {
 	if !req.NoWait {
 		err := d.st.WaitForIndexingUpto(waitUntilTx, nil)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	snap, err := d.st.SnapshotSince(waitUntilTx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer snap.Close()
 
@@ -139,24 +252,39 @@ func (d *db) ZScan(req *schema.ZScanRequest) (*schema.ZEntries, error) {
 		&store.KeyReaderSpec{
 			SeekKey:       seekKey,
 			Prefix:        prefix,
-			InclusiveSeek: req.InclusiveSeek,
+			InclusiveSeek: req.InclusiveSeek && readAtTx == 0,
 			DescOrder:     req.Desc,
 		})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer r.Close()
 
 	var entries []*schema.ZEntry
+	var zTxs []uint64
+	var nextCursor []byte
 	i := uint64(0)
 
 	for {
-		zKey, _, _, _, err := r.Read()
+		zKey, zVal, zTx, err := readKey(r, readAtTx)
 		if err == store.ErrNoMoreEntries {
 			break
 		}
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+
+		val, err := zVal.Resolve()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		removed, err := isZRemoved(val)
+		if err != nil {
+			return nil, nil, err
+		}
+		if removed {
+			continue
 		}
 
 		// zKey = [1+setLenLen+len(req.Set)+scoreLen+keyLenLen+1+len(req.Key)+txIDLen]
@@ -164,11 +292,19 @@ func (d *db) ZScan(req *schema.ZScanRequest) (*schema.ZEntries, error) {
 		scoreB := binary.BigEndian.Uint64(zKey[scoreOff:])
 		score := math.Float64frombits(scoreB)
 
-		// Guard to ensure that score match the filter range if filter is provided
-		if req.MinScore != nil && score < req.MinScore.Score {
+		// Guard to ensure that score match the filter range if filter is provided.
+		// Once the far bound is crossed no further entry can be in range, provided scores are non-negative
+		// as their binary representation only follows their natural order among non-negative values
+		if belowMinScore(req.MinScore, score) {
+			if req.Desc && req.MaxScore != nil && !math.Signbit(score) {
+				break
+			}
 			continue
 		}
-		if req.MaxScore != nil && score > req.MaxScore.Score {
+		if aboveMaxScore(req.MaxScore, score) {
+			if !req.Desc && req.MinScore != nil && !math.Signbit(score) {
+				break
+			}
 			continue
 		}
 
@@ -189,16 +325,141 @@ func (d *db) ZScan(req *schema.ZScanRequest) (*schema.ZEntries, error) {
 		}
 
 		entries = append(entries, zentry)
+		zTxs = append(zTxs, zTx)
 		if i++; i == limit {
+			atTx := readAtTx
+			if atTx == 0 {
+				atTx = snap.Ts()
+			}
+
+			nextCursor = encodeCursor(atTx, zKey)
 			break
 		}
 	}
 
 	list := &schema.ZEntries{
-		Entries: entries,
+		Entries:    entries,
+		NextCursor: nextCursor,
+	}
+
+	return list, zTxs, nil
+}
+
+// ZCard returns the number of elements in a sorted set, removed elements are not counted
+func (d *db) ZCard(req *schema.ZCardRequest) (*schema.EntryCount, error) {
+	if req == nil {
+		return nil, store.ErrIllegalArguments
+	}
+
+	return d.zCount(req.Set, nil, nil, req.SinceTx, req.NoWait)
+}
+
+// ZCount returns the number of elements in a sorted set whose score is within the provided bounds
+func (d *db) ZCount(req *schema.ZCountRequest) (*schema.EntryCount, error) {
+	if req == nil {
+		return nil, store.ErrIllegalArguments
+	}
+
+	return d.zCount(req.Set, req.MinScore, req.MaxScore, req.SinceTx, req.NoWait)
+}
+
+func (d *db) zCount(set []byte, minScore, maxScore *schema.Score, sinceTx uint64, noWait bool) (*schema.EntryCount, error) {
+	if len(set) == 0 {
+		return nil, store.ErrIllegalArguments
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	prefix := sortedSetPrefix(set)
+
+	seekKey := make([]byte, len(prefix)+scoreLen)
+	copy(seekKey, prefix)
+	if minScore != nil {
+		binary.BigEndian.PutUint64(seekKey[len(prefix):], math.Float64bits(minScore.Score))
+	}
+
+	waitUntilTx := sinceTx
+	if waitUntilTx == 0 {
+		waitUntilTx, _ = d.st.Alh()
+	}
+
+	if !noWait {
+		err := d.st.WaitForIndexingUpto(waitUntilTx, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	snap, err := d.st.SnapshotSince(waitUntilTx)
+	if err != nil {
+		return nil, err
+	}
+	defer snap.Close()
+
+	r, err := snap.NewKeyReader(&store.KeyReaderSpec{SeekKey: seekKey, Prefix: prefix})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var count uint64
+
+	for {
+		zKey, zVal, _, err := readKey(r, 0)
+		if err == store.ErrNoMoreEntries {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := zVal.Resolve()
+		if err != nil {
+			return nil, err
+		}
+
+		removed, err := isZRemoved(val)
+		if err != nil {
+			return nil, err
+		}
+		if removed {
+			continue
+		}
+
+		score := math.Float64frombits(binary.BigEndian.Uint64(zKey[len(prefix):]))
+
+		if belowMinScore(minScore, score) {
+			continue
+		}
+		if aboveMaxScore(maxScore, score) {
+			if minScore != nil && !math.Signbit(score) {
+				break
+			}
+			continue
+		}
+
+		count++
 	}
 
-	return list, nil
+	return &schema.EntryCount{Count: count}, nil
+}
+
+func sortedSetPrefix(set []byte) []byte {
+	prefix := make([]byte, 1+setLenLen+len(set))
+	prefix[0] = SortedSetKeyPrefix
+	binary.BigEndian.PutUint64(prefix[1:], uint64(len(set)))
+	copy(prefix[1+setLenLen:], set)
+
+	return prefix
+}
+
+func belowMinScore(min *schema.Score, score float64) bool {
+	return min != nil && (score < min.Score || (min.Exclusive && score == min.Score))
+}
+
+func aboveMaxScore(max *schema.Score, score float64) bool {
+	return max != nil && (score > max.Score || (max.Exclusive && score == max.Score))
 }
 
 //VerifiableZAdd ...
@@ -250,3 +511,113 @@ func (d *db) VerifiableZAdd(req *schema.VerifiableZAddRequest) (*schema.Verifiab
 		DualProof: schema.DualProofTo(dualProof),
 	}, nil
 }
+
+// VerifiableZScan scans a sorted set as ZScan does, proving every returned element and the entry it refers to
+// against the last committed transaction, which in turn is proven against req.ProveSinceTx
+func (d *db) VerifiableZScan(req *schema.VerifiableZScanRequest) (*schema.VerifiableZEntries, error) {
+	if req == nil {
+		return nil, store.ErrIllegalArguments
+	}
+
+	lastTxID, _ := d.st.Alh()
+	if lastTxID < req.ProveSinceTx {
+		return nil, ErrIllegalState
+	}
+
+	list, zTxs, err := d.zScan(req.ZScanRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	// every element was added and every entry was written at or before the root
+	rootTx, verifiableRootTx, err := d.provenRoot(req.ProveSinceTx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := d.st.NewTx()
+
+	entries := make([]*schema.VerifiableZEntry, len(list.Entries))
+
+	for i, ze := range list.Entries {
+		inclusionProof, dualProof, err := d.proveAgainst(zTxs[i], WrapZAddReferenceAt(ze.Set, ze.Score, EncodeKey(ze.Key), ze.AtTx), tx, rootTx)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[i] = &schema.VerifiableZEntry{
+			ZEntry:         ze,
+			InclusionProof: inclusionProof,
+			DualProof:      dualProof,
+		}
+
+		if ze.Entry == nil {
+			continue
+		}
+
+		entries[i].EntryInclusionProof, entries[i].EntryDualProof, err = d.proveAgainst(ze.Entry.Tx, EncodeKey(ze.Entry.Key), tx, rootTx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &schema.VerifiableZEntries{
+		Entries:      entries,
+		VerifiableTx: verifiableRootTx,
+		NextCursor:   list.NextCursor,
+	}, nil
+}
+
+// provenRoot reads the last committed transaction, which is used as root to prove a set of entries,
+// along with the dual proof between proveSinceTx and it
+func (d *db) provenRoot(proveSinceTx uint64) (*store.Tx, *schema.VerifiableTx, error) {
+	rootTx := d.st.NewTx()
+
+	rootTxID, _ := d.st.Alh()
+
+	err := d.st.ReadTx(rootTxID, rootTx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sourceTx := rootTx
+
+	if proveSinceTx > 0 {
+		sourceTx = d.st.NewTx()
+
+		err = d.st.ReadTx(proveSinceTx, sourceTx)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	dualProof, err := d.st.DualProof(sourceTx, rootTx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rootTx, &schema.VerifiableTx{
+		Tx:        schema.TxTo(rootTx),
+		DualProof: schema.DualProofTo(dualProof),
+	}, nil
+}
+
+// proveAgainst returns the inclusion proof of the key in the transaction txID and the dual proof between it and rootTx
+func (d *db) proveAgainst(txID uint64, key []byte, tx, rootTx *store.Tx) (*schema.InclusionProof, *schema.DualProof, error) {
+	err := d.st.ReadTx(txID, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inclusionProof, err := tx.Proof(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dualProof, err := d.st.DualProof(tx, rootTx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return schema.InclusionProofTo(inclusionProof), schema.DualProofTo(dualProof), nil
+}
@@ -717,3 +717,281 @@ func TestStoreVerifiableZAdd(t *testing.T) {
 	require.Equal(t, req.Key, itemList1.Entries[0].Entry.Key)
 	require.Equal(t, req.Score, itemList1.Entries[0].Score)
 }
+
+func TestStore_ZScanCursor(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.ZScan(&schema.ZScanRequest{Set: []byte("ranking"), Cursor: []byte{1, 2}})
+	require.Equal(t, ErrInvalidCursor, err)
+
+	for i, k := range []string{"alice", "bob", "carol"} {
+		_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte(k), Value: []byte("player")}}})
+		require.NoError(t, err)
+
+		_, err = db.ZAdd(&schema.ZAddRequest{Set: []byte("ranking"), Score: float64(i), Key: []byte(k)})
+		require.NoError(t, err)
+	}
+
+	page, err := db.ZScan(&schema.ZScanRequest{Set: []byte("ranking"), Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	require.NotEmpty(t, page.NextCursor)
+
+	_, err = db.ZAdd(&schema.ZAddRequest{Set: []byte("ranking"), Score: 1.5, Key: []byte("alice")})
+	require.NoError(t, err)
+
+	page, err = db.ZScan(&schema.ZScanRequest{Set: []byte("ranking"), Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	require.Equal(t, []byte("carol"), page.Entries[0].Key)
+	require.Empty(t, page.NextCursor)
+}
+
+func TestStore_ZRem(t *testing.T) {
+	d, closer := makeDb()
+	defer closer()
+
+	for _, k := range []string{"alice", "bob"} {
+		_, err := d.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte(k), Value: []byte(k)}}})
+		require.NoError(t, err)
+	}
+
+	_, err := d.ZAdd(&schema.ZAddRequest{Set: []byte("players"), Score: 1, Key: []byte("alice")})
+	require.NoError(t, err)
+
+	_, err = d.ZAdd(&schema.ZAddRequest{Set: []byte("players"), Score: 2, Key: []byte("bob")})
+	require.NoError(t, err)
+
+	_, err = d.ZRem(nil)
+	require.Equal(t, store.ErrIllegalArguments, err)
+
+	_, err = d.ZRem(&schema.ZRemRequest{Set: []byte("players"), Score: 1})
+	require.Equal(t, store.ErrIllegalArguments, err)
+
+	_, err = d.ZRem(&schema.ZRemRequest{Set: []byte("players"), Score: 3, Key: []byte("alice")})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	md, err := d.ZRem(&schema.ZRemRequest{Set: []byte("players"), Score: 1, Key: []byte("alice")})
+	require.NoError(t, err)
+
+	_, err = d.ZRem(&schema.ZRemRequest{Set: []byte("players"), Score: 1, Key: []byte("alice")})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	entries, err := d.ZScan(&schema.ZScanRequest{Set: []byte("players"), SinceTx: md.Id})
+	require.NoError(t, err)
+	require.Len(t, entries.Entries, 1)
+	require.Equal(t, []byte("bob"), entries.Entries[0].Key)
+
+	// the removed key itself is not affected
+	entry, err := d.Get(&schema.KeyRequest{Key: []byte("alice")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("alice"), entry.Value)
+
+	// both the addition and the removal are kept
+	txs, err := d.(*db).st.History(WrapZAddReferenceAt([]byte("players"), 1, EncodeKey([]byte("alice")), 0), 0, false, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	require.Equal(t, md.Id, txs[1])
+
+	md, err = d.ZAdd(&schema.ZAddRequest{Set: []byte("players"), Score: 1, Key: []byte("alice")})
+	require.NoError(t, err)
+
+	entries, err = d.ZScan(&schema.ZScanRequest{Set: []byte("players"), SinceTx: md.Id})
+	require.NoError(t, err)
+	require.Len(t, entries.Entries, 2)
+	require.Equal(t, []byte("alice"), entries.Entries[0].Key)
+}
+
+func TestStore_ZScanScoreRange(t *testing.T) {
+	d, closer := makeDb()
+	defer closer()
+
+	_, err := d.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("event"), Value: []byte("event")}}})
+	require.NoError(t, err)
+
+	for i := 1; i <= 10; i++ {
+		_, err = d.ZAdd(&schema.ZAddRequest{Set: []byte("timeline"), Score: float64(i), Key: []byte("event")})
+		require.NoError(t, err)
+	}
+
+	scores := func(entries *schema.ZEntries) []float64 {
+		var s []float64
+		for _, e := range entries.Entries {
+			s = append(s, e.Score)
+		}
+		return s
+	}
+
+	entries, err := d.ZScan(&schema.ZScanRequest{
+		Set:      []byte("timeline"),
+		MinScore: &schema.Score{Score: 3, Exclusive: true},
+		MaxScore: &schema.Score{Score: 7},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float64{4, 5, 6, 7}, scores(entries))
+
+	entries, err = d.ZScan(&schema.ZScanRequest{
+		Set:      []byte("timeline"),
+		MinScore: &schema.Score{Score: 3},
+		MaxScore: &schema.Score{Score: 7, Exclusive: true},
+		Desc:     true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float64{6, 5, 4, 3}, scores(entries))
+
+	entries, err = d.ZScan(&schema.ZScanRequest{
+		Set:      []byte("timeline"),
+		MinScore: &schema.Score{Score: 3},
+		MaxScore: &schema.Score{Score: 7},
+		Desc:     true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float64{7, 6, 5, 4, 3}, scores(entries))
+
+	entries, err = d.ZScan(&schema.ZScanRequest{
+		Set:      []byte("timeline"),
+		MinScore: &schema.Score{Score: 3, Exclusive: true},
+		MaxScore: &schema.Score{Score: 7, Exclusive: true},
+		Limit:    2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float64{4, 5}, scores(entries))
+	require.NotEmpty(t, entries.NextCursor)
+
+	entries, err = d.ZScan(&schema.ZScanRequest{
+		Set:      []byte("timeline"),
+		MinScore: &schema.Score{Score: 3, Exclusive: true},
+		MaxScore: &schema.Score{Score: 7, Exclusive: true},
+		Limit:    2,
+		Cursor:   entries.NextCursor,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float64{6}, scores(entries))
+	require.Empty(t, entries.NextCursor)
+
+	entries, err = d.ZScan(&schema.ZScanRequest{
+		Set:      []byte("timeline"),
+		MinScore: &schema.Score{Score: 5, Exclusive: true},
+		MaxScore: &schema.Score{Score: 5},
+	})
+	require.NoError(t, err)
+	require.Empty(t, entries.Entries)
+}
+
+func TestStore_ZCardAndZCount(t *testing.T) {
+	d, closer := makeDb()
+	defer closer()
+
+	_, err := d.ZCard(nil)
+	require.Equal(t, store.ErrIllegalArguments, err)
+
+	_, err = d.ZCount(&schema.ZCountRequest{})
+	require.Equal(t, store.ErrIllegalArguments, err)
+
+	card, err := d.ZCard(&schema.ZCardRequest{Set: []byte("timeline")})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), card.Count)
+
+	_, err = d.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("event"), Value: []byte("event")}}})
+	require.NoError(t, err)
+
+	for i := 1; i <= 10; i++ {
+		_, err = d.ZAdd(&schema.ZAddRequest{Set: []byte("timeline"), Score: float64(i), Key: []byte("event")})
+		require.NoError(t, err)
+	}
+
+	_, err = d.ZAdd(&schema.ZAddRequest{Set: []byte("other"), Score: 1, Key: []byte("event")})
+	require.NoError(t, err)
+
+	md, err := d.ZRem(&schema.ZRemRequest{Set: []byte("timeline"), Score: 5, Key: []byte("event")})
+	require.NoError(t, err)
+
+	card, err = d.ZCard(&schema.ZCardRequest{Set: []byte("timeline"), SinceTx: md.Id})
+	require.NoError(t, err)
+	require.Equal(t, uint64(9), card.Count)
+
+	count, err := d.ZCount(&schema.ZCountRequest{
+		Set:      []byte("timeline"),
+		MinScore: &schema.Score{Score: 3},
+		MaxScore: &schema.Score{Score: 7},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), count.Count)
+
+	count, err = d.ZCount(&schema.ZCountRequest{
+		Set:      []byte("timeline"),
+		MinScore: &schema.Score{Score: 3, Exclusive: true},
+		MaxScore: &schema.Score{Score: 7, Exclusive: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count.Count)
+
+	count, err = d.ZCount(&schema.ZCountRequest{Set: []byte("timeline"), MinScore: &schema.Score{Score: 8}})
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), count.Count)
+
+	count, err = d.ZCount(&schema.ZCountRequest{Set: []byte("timeline"), MaxScore: &schema.Score{Score: 2}})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count.Count)
+}
+
+func TestStoreVerifiableZScan(t *testing.T) {
+	d, closer := makeDb()
+	defer closer()
+
+	_, err := d.VerifiableZScan(nil)
+	require.Equal(t, store.ErrIllegalArguments, err)
+
+	_, err = d.VerifiableZScan(&schema.VerifiableZScanRequest{ZScanRequest: &schema.ZScanRequest{Set: []byte("players")}, ProveSinceTx: 100})
+	require.Equal(t, ErrIllegalState, err)
+
+	md, err := d.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("alice"), Value: []byte("alice")}}})
+	require.NoError(t, err)
+
+	_, err = d.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("bob"), Value: []byte("bob")}}})
+	require.NoError(t, err)
+
+	_, err = d.ZAdd(&schema.ZAddRequest{Set: []byte("players"), Score: 1, Key: []byte("alice"), AtTx: md.Id, BoundRef: true})
+	require.NoError(t, err)
+
+	_, err = d.ZAdd(&schema.ZAddRequest{Set: []byte("players"), Score: 2, Key: []byte("bob")})
+	require.NoError(t, err)
+
+	_, err = d.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("alice"), Value: []byte("alice2")}}})
+	require.NoError(t, err)
+
+	proveSinceTx := md.Id
+
+	vEntries, err := d.VerifiableZScan(&schema.VerifiableZScanRequest{
+		ZScanRequest: &schema.ZScanRequest{Set: []byte("players")},
+		ProveSinceTx: proveSinceTx,
+	})
+	require.NoError(t, err)
+	require.Len(t, vEntries.Entries, 2)
+
+	rootDualProof := schema.DualProofFrom(vEntries.VerifiableTx.DualProof)
+	rootID := rootDualProof.TargetTxMetadata.ID
+	rootAlh := rootDualProof.TargetTxMetadata.Alh()
+
+	require.Equal(t, proveSinceTx, rootDualProof.SourceTxMetadata.ID)
+	require.True(t, store.VerifyDualProof(rootDualProof, proveSinceTx, rootID, rootDualProof.SourceTxMetadata.Alh(), rootAlh))
+
+	verify := func(inclusionProof *schema.InclusionProof, dualProof *schema.DualProof, kv *store.KV) {
+		dp := schema.DualProofFrom(dualProof)
+
+		require.True(t, store.VerifyInclusion(schema.InclusionProofFrom(inclusionProof), kv, dp.SourceTxMetadata.Eh))
+		require.True(t, store.VerifyDualProof(dp, dp.SourceTxMetadata.ID, rootID, dp.SourceTxMetadata.Alh(), rootAlh))
+	}
+
+	for _, vze := range vEntries.Entries {
+		ze := vze.ZEntry
+
+		verify(vze.InclusionProof, vze.DualProof, EncodeZAdd(ze.Set, ze.Score, EncodeKey(ze.Key), ze.AtTx))
+		verify(vze.EntryInclusionProof, vze.EntryDualProof, EncodeKVWithMetadata(ze.Entry.Key, ze.Entry.Value, ze.Entry.Metadata))
+	}
+
+	// the bound element refers to the revision it was added with
+	require.Equal(t, []byte("alice"), vEntries.Entries[0].ZEntry.Entry.Value)
+	require.Equal(t, []byte("bob"), vEntries.Entries[1].ZEntry.Entry.Value)
+}
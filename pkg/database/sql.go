@@ -19,6 +19,7 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/codenotary/immudb/embedded/cache"
 	"github.com/codenotary/immudb/embedded/sql"
 	"github.com/codenotary/immudb/embedded/store"
 	"github.com/codenotary/immudb/pkg/api/schema"
@@ -26,6 +27,39 @@ import (
 
 var ErrSQLNotReady = errors.New("SQL catalog not yet replicated")
 
+// DefaultSQLStmtCacheSize is the number of parsed SQL statements kept for reuse by each database
+const DefaultSQLStmtCacheSize = 1000
+
+func newSQLStmtCache(size int) (*cache.LRUCache, error) {
+	if size <= 0 {
+		return nil, nil
+	}
+
+	return cache.NewLRUCache(size)
+}
+
+// parseSQL returns the statements of the SQL request, reusing the ones already parsed for the same request.
+// Parameters are only bound at execution time, so cached statements can be executed with any parameter values
+func (d *db) parseSQL(sqlStmt string) ([]sql.SQLStmt, error) {
+	if d.sqlStmts != nil {
+		stmts, err := d.sqlStmts.Get(sqlStmt)
+		if err == nil {
+			return stmts.([]sql.SQLStmt), nil
+		}
+	}
+
+	stmts, err := sql.Parse(strings.NewReader(sqlStmt))
+	if err != nil {
+		return nil, err
+	}
+
+	if d.sqlStmts != nil {
+		d.sqlStmts.Put(sqlStmt, stmts)
+	}
+
+	return stmts, nil
+}
+
 func (d *db) VerifiableSQLGet(req *schema.VerifiableSQLGetRequest) (*schema.VerifiableSQLEntry, error) {
 	if req == nil {
 		return nil, ErrIllegalArguments
@@ -249,28 +283,40 @@ func (d *db) SQLExec(req *schema.SQLExecRequest) (*schema.SQLExecResult, error)
 		return nil, ErrIllegalArguments
 	}
 
-	stmts, err := sql.Parse(strings.NewReader(req.Sql))
+	stmts, err := d.parseSQL(req.Sql)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExecStmts(stmts)
 	if err != nil {
 		return nil, err
 	}
 
+	return d.SQLExecPrepared(stmts, req.Params, !req.NoWait)
+}
+
+// checkExecStmts rejects the statements which are served by their own operations
+func checkExecStmts(stmts []sql.SQLStmt) error {
 	for _, stmt := range stmts {
 		switch stmt.(type) {
 		case *sql.UseDatabaseStmt:
 			{
-				return nil, errors.New("SQL statement not supported. Please use `UseDatabase` operation instead")
+				return errors.New("SQL statement not supported. Please use `UseDatabase` operation instead")
 			}
 		case *sql.CreateDatabaseStmt:
 			{
-				return nil, errors.New("SQL statement not supported. Please use `CreateDatabase` operation instead")
+				return errors.New("SQL statement not supported. Please use `CreateDatabase` operation instead")
 			}
 		}
 	}
 
-	return d.SQLExecPrepared(stmts, req.Params, !req.NoWait)
+	return nil
 }
 
-func (d *db) SQLExecPrepared(stmts []sql.SQLStmt, namedParams []*schema.NamedParam, waitForIndexing bool) (*schema.SQLExecResult, error) {
+func (d *db) SQLExecPrepared(stmts []sql.SQLStmt, namedParams []*schema.NamedParam, waitForIndexing bool) (res *schema.SQLExecResult, err error) {
+	defer func() { err = d.awaitReplicas(lastTxOf(res), err) }()
+
 	if len(stmts) == 0 {
 		return nil, ErrIllegalArguments
 	}
@@ -282,13 +328,25 @@ func (d *db) SQLExecPrepared(stmts []sql.SQLStmt, namedParams []*schema.NamedPar
 		return nil, ErrIsReplica
 	}
 
+	if d.quarantined {
+		return nil, ErrQuarantined
+	}
+
+	if d.options.readOnly {
+		return nil, ErrIsReadOnly
+	}
+
+	if err := d.checkQuota(); err != nil {
+		return nil, err
+	}
+
 	params := make(map[string]interface{})
 
 	for _, p := range namedParams {
 		params[p.Name] = schema.RawValue(p.Value)
 	}
 
-	err := d.sqlEngine.EnsureCatalogReady(nil)
+	err = d.sqlEngine.EnsureCatalogReady(nil)
 	if err != nil {
 		return nil, err
 	}
@@ -298,7 +356,7 @@ func (d *db) SQLExecPrepared(stmts []sql.SQLStmt, namedParams []*schema.NamedPar
 		return nil, err
 	}
 
-	res := &schema.SQLExecResult{
+	res = &schema.SQLExecResult{
 		Ctxs:            make([]*schema.TxMetadata, len(summary.DDTxs)),
 		Dtxs:            make([]*schema.TxMetadata, len(summary.DMTxs)),
 		UpdatedRows:     uint32(summary.UpdatedRows),
@@ -343,7 +401,7 @@ func (d *db) SQLQuery(req *schema.SQLQueryRequest) (*schema.SQLQueryResult, erro
 		return nil, ErrIllegalArguments
 	}
 
-	stmts, err := sql.Parse(strings.NewReader(req.Sql))
+	stmts, err := d.parseSQL(req.Sql)
 	if err != nil {
 		return nil, err
 	}
@@ -417,8 +475,8 @@ func (d *db) SQLQueryPrepared(stmt *sql.SelectStmt, namedParams []*schema.NamedP
 
 			v := row.Values[c.Selector()]
 
-			_, isNull := v.(*sql.NullValue)
-			if isNull {
+			// NULL values may also be wrapped, as MIN or MAX over NULL values
+			if v.Value() == nil {
 				rrow.Values[i] = &schema.SQLValue{Value: &schema.SQLValue_Null{}}
 			} else {
 				rrow.Values[i] = typedValueToRowValue(v)
@@ -481,7 +539,7 @@ func typedValueToRowValue(tv sql.TypedValue) *schema.SQLValue {
 		{
 			return &schema.SQLValue{Value: &schema.SQLValue_N{N: tv.Value().(uint64)}}
 		}
-	case sql.VarcharType:
+	case sql.VarcharType, sql.JSONType:
 		{
 			return &schema.SQLValue{Value: &schema.SQLValue_S{S: tv.Value().(string)}}
 		}
@@ -0,0 +1,685 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/codenotary/immudb/embedded/sql"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// Formats of a SQL dump: a SQL script holding the schema and the rows of the dumped tables,
+// or the rows of a single table as CSV records
+const (
+	SQLDumpFormatSQL = "sql"
+	SQLDumpFormatCSV = "csv"
+)
+
+// sqlDumpBatchEntries bounds the entries written by each statement of a dump or a load,
+// every statement is committed in its own transaction
+const sqlDumpBatchEntries = 256
+
+// csvNullValue represents a NULL value in CSV records
+const csvNullValue = `\N`
+
+// csvComment starts the lines holding the metadata of a CSV dump
+const csvComment = '#'
+
+var ErrUnsupportedDumpFormat = errors.New("unsupported dump format")
+var ErrInvalidDump = errors.New("invalid dump")
+var ErrAutoIncrementGap = errors.New("auto-incremental primary key values are not contiguous, the table can not be dumped")
+
+// SQLDump writes the tables of the database, or only the requested ones, as they were at the requested tx or
+// at the current state of the database. Rows are read as before the following tx, thus the dump is consistent even
+// if new transactions get committed meanwhile. The database, the tx and its hash are recorded at the beginning of the dump.
+// SQL dumps can be loaded into any database, CSV dumps can be loaded into an existing table
+func (d *db) SQLDump(req *schema.SQLDumpRequest, w io.Writer) error {
+	if req == nil || w == nil {
+		return ErrIllegalArguments
+	}
+
+	format := req.Format
+	if format == "" {
+		format = SQLDumpFormatSQL
+	}
+
+	if format != SQLDumpFormatSQL && format != SQLDumpFormatCSV {
+		return ErrUnsupportedDumpFormat
+	}
+
+	if format == SQLDumpFormatCSV && len(req.Tables) != 1 {
+		return ErrIllegalArguments
+	}
+
+	state, err := d.CurrentState()
+	if err != nil {
+		return err
+	}
+
+	if req.AtTx > state.TxId {
+		return ErrIllegalArguments
+	}
+
+	if req.AtTx > 0 && req.AtTx < state.TxId {
+		tx, err := d.TxByID(&schema.TxRequest{Tx: req.AtTx})
+		if err != nil {
+			return err
+		}
+
+		alh := schema.TxMetadataFrom(tx.Metadata).Alh()
+		state = &schema.ImmutableState{TxId: req.AtTx, TxHash: alh[:]}
+	}
+
+	err = d.WaitForIndexingUpto(state.TxId, nil)
+	if err != nil {
+		return err
+	}
+
+	tables, err := d.catalogTables(req.Tables)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+
+	if format == SQLDumpFormatCSV {
+		err = d.dumpTableAsCSV(tables[0], state, bw)
+	} else {
+		err = d.dumpTablesAsSQL(tables, state, bw)
+	}
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// catalogTables returns the requested tables, or all of them in creation order
+func (d *db) catalogTables(names []string) ([]*sql.Table, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.isReplica() {
+		err := d.reloadSQLCatalog()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := d.sqlEngine.EnsureCatalogReady(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := d.sqlEngine.GetDatabaseByName(dbInstanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		tables := db.GetTables()
+		sort.Slice(tables, func(i, j int) bool { return tables[i].ID() < tables[j].ID() })
+		return tables, nil
+	}
+
+	tables := make([]*sql.Table, len(names))
+
+	for i, name := range names {
+		tables[i], err = db.GetTableByName(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tables, nil
+}
+
+// tableCols returns the columns of a table in creation order
+func tableCols(table *sql.Table) ([]*sql.Column, error) {
+	cols := make([]*sql.Column, len(table.ColsByID()))
+
+	for i := range cols {
+		col, err := table.GetColumnByID(uint64(i + 1))
+		if err != nil {
+			return nil, err
+		}
+		cols[i] = col
+	}
+
+	return cols, nil
+}
+
+// batchSize returns how many rows of a table can be written by a single statement
+func batchSize(table *sql.Table) (int, error) {
+	entriesPerRow := 1
+
+	for _, col := range table.ColsByID() {
+		indexed, err := table.IsIndexed(col.Name())
+		if err != nil {
+			return 0, err
+		}
+		if indexed {
+			entriesPerRow++
+		}
+	}
+
+	if entriesPerRow > sqlDumpBatchEntries {
+		return 1, nil
+	}
+
+	return sqlDumpBatchEntries / entriesPerRow, nil
+}
+
+func (d *db) dumpTablesAsSQL(tables []*sql.Table, state *schema.ImmutableState, w *bufio.Writer) error {
+	fmt.Fprintf(w, "/*\n * immudb SQL dump\n * database: %s\n * tx: %d\n * tx hash: %x\n */\n", d.GetName(), state.TxId, state.TxHash)
+
+	for _, table := range tables {
+		cols, err := tableCols(table)
+		if err != nil {
+			return err
+		}
+
+		colSpecs := make([]string, len(cols))
+
+		for i, col := range cols {
+			colSpecs[i] = fmt.Sprintf("%s %s", col.Name(), col.Type())
+
+			if col.IsAutoIncremental() {
+				colSpecs[i] += " AUTO_INCREMENT"
+			}
+
+			if !col.IsNullable() {
+				colSpecs[i] += " NOT NULL"
+			}
+
+			if col.IsUnique() && col.ID() != table.PrimaryKey().ID() {
+				colSpecs[i] += " UNIQUE"
+			}
+		}
+
+		fmt.Fprintf(w, "\nCREATE TABLE %s (%s, PRIMARY KEY %s);\n", table.Name(), strings.Join(colSpecs, ", "), table.PrimaryKey().Name())
+
+		for _, col := range cols {
+			indexed, err := table.IsIndexed(col.Name())
+			if err != nil {
+				return err
+			}
+
+			// unique columns are indexed when the table is created
+			if indexed && !col.IsUnique() {
+				fmt.Fprintf(w, "CREATE INDEX ON %s(%s);\n", table.Name(), col.Name())
+			}
+		}
+
+		err = d.dumpRowsAsSQL(table, cols, state.TxId, w)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dumpRowsAsSQL writes the rows of a table as upserts of several rows each.
+// Values of auto-incremental primary keys can not be set explicitly, thus the rows of such tables are inserted
+// in primary key order so that each row gets the same key it had
+func (d *db) dumpRowsAsSQL(table *sql.Table, cols []*sql.Column, atTx uint64, w *bufio.Writer) error {
+	autoIncrement := table.PrimaryKey().IsAutoIncremental()
+
+	stmt := "UPSERT"
+	if autoIncrement {
+		stmt = "INSERT"
+	}
+
+	var dumpedCols []*sql.Column
+	var colNames []string
+
+	for _, col := range cols {
+		if autoIncrement && col.Name() == table.PrimaryKey().Name() {
+			continue
+		}
+
+		dumpedCols = append(dumpedCols, col)
+		colNames = append(colNames, col.Name())
+	}
+
+	batch, err := batchSize(table)
+	if err != nil {
+		return err
+	}
+
+	n := 0
+
+	err = d.readTableRows(table, atTx, func(values map[string]sql.TypedValue) error {
+		if autoIncrement && values[table.PrimaryKey().Name()].Value() != uint64(n+1) {
+			return ErrAutoIncrementGap
+		}
+
+		if n%batch == 0 {
+			if n > 0 {
+				w.WriteString(";\n")
+			}
+
+			fmt.Fprintf(w, "%s INTO %s(%s) VALUES\n", stmt, table.Name(), strings.Join(colNames, ", "))
+		} else {
+			w.WriteString(",\n")
+		}
+
+		literals := make([]string, len(dumpedCols))
+
+		for i, col := range dumpedCols {
+			lit, err := sqlLiteral(values[col.Name()])
+			if err != nil {
+				return err
+			}
+			literals[i] = lit
+		}
+
+		fmt.Fprintf(w, "(%s)", strings.Join(literals, ", "))
+
+		n++
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if n > 0 {
+		w.WriteString(";\n")
+	}
+
+	return nil
+}
+
+func (d *db) dumpTableAsCSV(table *sql.Table, state *schema.ImmutableState, w *bufio.Writer) error {
+	fmt.Fprintf(w, "%c database: %s\n", csvComment, d.GetName())
+	fmt.Fprintf(w, "%c table: %s\n", csvComment, table.Name())
+	fmt.Fprintf(w, "%c tx: %d\n", csvComment, state.TxId)
+	fmt.Fprintf(w, "%c tx hash: %x\n", csvComment, state.TxHash)
+
+	cols, err := tableCols(table)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+
+	record := make([]string, len(cols))
+
+	for i, col := range cols {
+		record[i] = col.Name()
+	}
+
+	err = cw.Write(record)
+	if err != nil {
+		return err
+	}
+
+	err = d.readTableRows(table, state.TxId, func(values map[string]sql.TypedValue) error {
+		for i, col := range cols {
+			field, err := csvField(values[col.Name()])
+			if err != nil {
+				return err
+			}
+			record[i] = field
+		}
+
+		return cw.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// readTableRows calls fn with the values, by column name, of each row of the table as it was at the given tx,
+// in primary key order
+func (d *db) readTableRows(table *sql.Table, atTx uint64, fn func(values map[string]sql.TypedValue) error) error {
+	stmts, err := sql.ParseString(fmt.Sprintf("SELECT * FROM %s BEFORE TX %d", table.Name(), atTx+1))
+	if err != nil {
+		return err
+	}
+
+	r, err := d.SQLQueryRowReader(stmts[0].(*sql.SelectStmt), true)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	colDescriptors, err := r.Columns()
+	if err != nil {
+		return err
+	}
+
+	for {
+		row, err := r.Read()
+		if err == sql.ErrNoMoreRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		values := make(map[string]sql.TypedValue, len(colDescriptors))
+
+		for _, c := range colDescriptors {
+			values[c.Column] = row.Values[c.Selector()]
+		}
+
+		err = fn(values)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func sqlLiteral(v sql.TypedValue) (string, error) {
+	if v == nil || v.Value() == nil {
+		return "NULL", nil
+	}
+
+	switch v.Type() {
+	case sql.IntegerType:
+		{
+			return strconv.FormatUint(v.Value().(uint64), 10), nil
+		}
+	case sql.BooleanType:
+		{
+			if v.Value().(bool) {
+				return "TRUE", nil
+			}
+			return "FALSE", nil
+		}
+	case sql.VarcharType, sql.JSONType:
+		{
+			return "'" + strings.ReplaceAll(v.Value().(string), "'", "''") + "'", nil
+		}
+	case sql.BLOBType:
+		{
+			return "x'" + hex.EncodeToString(v.Value().([]byte)) + "'", nil
+		}
+	}
+
+	return "", sql.ErrInvalidTypes
+}
+
+func csvField(v sql.TypedValue) (string, error) {
+	if v == nil || v.Value() == nil {
+		return csvNullValue, nil
+	}
+
+	switch v.Type() {
+	case sql.IntegerType:
+		{
+			return strconv.FormatUint(v.Value().(uint64), 10), nil
+		}
+	case sql.BooleanType:
+		{
+			return strconv.FormatBool(v.Value().(bool)), nil
+		}
+	case sql.VarcharType, sql.JSONType:
+		{
+			return v.Value().(string), nil
+		}
+	case sql.BLOBType:
+		{
+			return hex.EncodeToString(v.Value().([]byte)), nil
+		}
+	}
+
+	return "", sql.ErrInvalidTypes
+}
+
+func csvValue(field string, t sql.SQLValueType) (*schema.SQLValue, error) {
+	if field == csvNullValue {
+		return &schema.SQLValue{Value: &schema.SQLValue_Null{}}, nil
+	}
+
+	switch t {
+	case sql.IntegerType:
+		{
+			n, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil, ErrInvalidDump
+			}
+			return &schema.SQLValue{Value: &schema.SQLValue_N{N: n}}, nil
+		}
+	case sql.BooleanType:
+		{
+			b, err := strconv.ParseBool(field)
+			if err != nil {
+				return nil, ErrInvalidDump
+			}
+			return &schema.SQLValue{Value: &schema.SQLValue_B{B: b}}, nil
+		}
+	case sql.VarcharType, sql.JSONType:
+		{
+			return &schema.SQLValue{Value: &schema.SQLValue_S{S: field}}, nil
+		}
+	case sql.BLOBType:
+		{
+			bs, err := hex.DecodeString(field)
+			if err != nil {
+				return nil, ErrInvalidDump
+			}
+			return &schema.SQLValue{Value: &schema.SQLValue_Bs{Bs: bs}}, nil
+		}
+	}
+
+	return nil, sql.ErrInvalidTypes
+}
+
+// SQLLoad executes a dump produced by SQLDump. SQL dumps are executed as they are,
+// while the rows of CSV dumps are upserted in batches into the table recorded in the dump.
+// Tables with an auto-incremental primary key get the rows inserted in the order they appear,
+// each row getting a new key
+func (d *db) SQLLoad(r io.Reader) (*schema.SQLExecResult, error) {
+	if r == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	br := bufio.NewReader(r)
+
+	lead, err := br.Peek(1)
+	if err == io.EOF {
+		return nil, ErrInvalidDump
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if lead[0] == csvComment {
+		return d.loadCSV(br)
+	}
+
+	stmts, err := sql.Parse(br)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExecStmts(stmts)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.SQLExecPrepared(stmts, nil, true)
+}
+
+func (d *db) loadCSV(br *bufio.Reader) (*schema.SQLExecResult, error) {
+	var tableName string
+
+	for {
+		lead, err := br.Peek(1)
+		if err != nil || lead[0] != csvComment {
+			break
+		}
+
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, ErrInvalidDump
+		}
+
+		entry := strings.SplitN(strings.TrimSpace(line[1:]), ":", 2)
+		if len(entry) == 2 && strings.TrimSpace(entry[0]) == "table" {
+			tableName = strings.TrimSpace(entry[1])
+		}
+	}
+
+	if tableName == "" {
+		return nil, ErrInvalidDump
+	}
+
+	tables, err := d.catalogTables([]string{tableName})
+	if err != nil {
+		return nil, err
+	}
+
+	table := tables[0]
+
+	cr := csv.NewReader(br)
+	cr.Comment = csvComment
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, ErrInvalidDump
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	autoIncrement := table.PrimaryKey().IsAutoIncremental()
+
+	stmt := "UPSERT"
+	if autoIncrement {
+		stmt = "INSERT"
+	}
+
+	var colPos []int
+	var colTypes []sql.SQLValueType
+	var colNames []string
+
+	for i, name := range header {
+		if autoIncrement && name == table.PrimaryKey().Name() {
+			continue
+		}
+
+		col, err := table.GetColumnByName(name)
+		if err != nil {
+			return nil, err
+		}
+
+		colPos = append(colPos, i)
+		colTypes = append(colTypes, col.Type())
+		colNames = append(colNames, name)
+	}
+
+	batch, err := batchSize(table)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &schema.SQLExecResult{LastInsertedPKs: make(map[string]*schema.SQLValue)}
+
+	var rows []string
+	var params []*schema.NamedParam
+
+	flush := func() error {
+		if len(rows) == 0 {
+			return nil
+		}
+
+		stmts, err := sql.ParseString(fmt.Sprintf("%s INTO %s(%s) VALUES %s", stmt, table.Name(), strings.Join(colNames, ", "), strings.Join(rows, ", ")))
+		if err != nil {
+			return err
+		}
+
+		bres, err := d.SQLExecPrepared(stmts, params, true)
+		if err != nil {
+			return err
+		}
+
+		res.Ctxs = append(res.Ctxs, bres.Ctxs...)
+		res.Dtxs = append(res.Dtxs, bres.Dtxs...)
+		res.UpdatedRows += bres.UpdatedRows
+
+		for t, pk := range bres.LastInsertedPKs {
+			res.LastInsertedPKs[t] = pk
+		}
+
+		rows = nil
+		params = nil
+
+		return nil
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(record) != len(header) {
+			return nil, ErrInvalidDump
+		}
+
+		ps := make([]string, len(colPos))
+
+		for i, pos := range colPos {
+			v, err := csvValue(record[pos], colTypes[i])
+			if err != nil {
+				return nil, err
+			}
+
+			name := fmt.Sprintf("r%dc%d", len(rows), i)
+
+			params = append(params, &schema.NamedParam{Name: name, Value: v})
+			ps[i] = "@" + name
+		}
+
+		rows = append(rows, "("+strings.Join(ps, ", ")+")")
+
+		if len(rows) == batch {
+			err = flush()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = flush()
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
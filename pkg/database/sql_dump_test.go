@@ -0,0 +1,126 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLDumpAndLoad(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.SQLExec(&schema.SQLExecRequest{Sql: `
+		CREATE TABLE table1(id INTEGER AUTO_INCREMENT, title VARCHAR NOT NULL, active BOOLEAN, payload BLOB, PRIMARY KEY id);
+		CREATE INDEX ON table1(title);
+		CREATE TABLE table2(name VARCHAR, doc JSON, PRIMARY KEY name);
+		CREATE TABLE table3(id INTEGER, code VARCHAR UNIQUE, PRIMARY KEY id);
+		INSERT INTO table1(title, active, payload) VALUES ('it''s the first', TRUE, x'ED0393'), ('second', NULL, NULL);
+		UPSERT INTO table2(name, doc) VALUES ('name1', '{"a": [1, 2]}');
+	`})
+	require.NoError(t, err)
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+
+	_, err = db.SQLExec(&schema.SQLExecRequest{Sql: "UPSERT INTO table2(name, doc) VALUES ('name2', NULL)"})
+	require.NoError(t, err)
+
+	err = db.SQLDump(nil, &bytes.Buffer{})
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	err = db.SQLDump(&schema.SQLDumpRequest{Format: "xml"}, &bytes.Buffer{})
+	require.ErrorIs(t, err, ErrUnsupportedDumpFormat)
+
+	err = db.SQLDump(&schema.SQLDumpRequest{Format: SQLDumpFormatCSV}, &bytes.Buffer{})
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	err = db.SQLDump(&schema.SQLDumpRequest{AtTx: state.TxId + 10}, &bytes.Buffer{})
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	var dump bytes.Buffer
+
+	err = db.SQLDump(&schema.SQLDumpRequest{AtTx: state.TxId}, &dump)
+	require.NoError(t, err)
+	require.Contains(t, dump.String(), "CREATE TABLE table1 (id INTEGER AUTO_INCREMENT NOT NULL, title VARCHAR NOT NULL, active BOOLEAN, payload BLOB, PRIMARY KEY id);")
+	require.Contains(t, dump.String(), "CREATE INDEX ON table1(title);")
+	require.Contains(t, dump.String(), "CREATE TABLE table3 (id INTEGER NOT NULL, code VARCHAR UNIQUE, PRIMARY KEY id);")
+	require.NotContains(t, dump.String(), "CREATE INDEX ON table3(code);")
+	require.NotContains(t, dump.String(), "name2")
+
+	_, err = db.SQLLoad(nil)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = db.SQLLoad(&bytes.Buffer{})
+	require.ErrorIs(t, err, ErrInvalidDump)
+
+	db2, closer2 := makeDb()
+	defer closer2()
+
+	res, err := db2.SQLLoad(&dump)
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), res.UpdatedRows)
+
+	res1, err := db2.SQLQuery(&schema.SQLQueryRequest{Sql: "SELECT id, title, active, payload FROM table1"})
+	require.NoError(t, err)
+	require.Len(t, res1.Rows, 2)
+	require.Equal(t, uint64(1), res1.Rows[0].Values[0].GetN())
+	require.Equal(t, "it's the first", res1.Rows[0].Values[1].GetS())
+	require.True(t, res1.Rows[0].Values[2].GetB())
+	require.Equal(t, []byte{0xED, 0x03, 0x93}, res1.Rows[0].Values[3].GetBs())
+	require.Equal(t, uint64(2), res1.Rows[1].Values[0].GetN())
+	require.IsType(t, &schema.SQLValue_Null{}, res1.Rows[1].Values[2].Value)
+
+	res2, err := db2.SQLQuery(&schema.SQLQueryRequest{Sql: "SELECT name, JSON_EXTRACT(doc, '$.a[1]') FROM table2"})
+	require.NoError(t, err)
+	require.Len(t, res2.Rows, 1)
+	require.Equal(t, "2", res2.Rows[0].Values[1].GetS())
+
+	t.Run("csv", func(t *testing.T) {
+		var csvDump bytes.Buffer
+
+		err = db.SQLDump(&schema.SQLDumpRequest{Format: SQLDumpFormatCSV, Tables: []string{"table1"}, AtTx: state.TxId}, &csvDump)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(csvDump.String(), "# database: db\n# table: table1\n"))
+		require.Contains(t, csvDump.String(), "id,title,active,payload\n1,it's the first,true,ed0393\n2,second,\\N,\\N\n")
+
+		_, err = db.SQLLoad(strings.NewReader("# database: db\nid,title\n"))
+		require.ErrorIs(t, err, ErrInvalidDump)
+
+		_, err = db.SQLLoad(strings.NewReader("# table: table1\nid,title\n1\n"))
+		require.Error(t, err)
+
+		_, err = db.SQLLoad(strings.NewReader("# table: table1\nid,active\n1,maybe\n"))
+		require.ErrorIs(t, err, ErrInvalidDump)
+
+		// rows get new keys, as the primary key of the table is auto-incremental
+		res, err := db2.SQLLoad(&csvDump)
+		require.NoError(t, err)
+		require.Equal(t, uint32(2), res.UpdatedRows)
+		require.Equal(t, uint64(4), res.LastInsertedPKs["table1"].GetN())
+
+		res1, err := db2.SQLQuery(&schema.SQLQueryRequest{Sql: "SELECT id, title FROM table1 WHERE id > 2"})
+		require.NoError(t, err)
+		require.Len(t, res1.Rows, 2)
+		require.Equal(t, "it's the first", res1.Rows[0].Values[1].GetS())
+	})
+}
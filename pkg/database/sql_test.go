@@ -16,8 +16,11 @@ limitations under the License.
 package database
 
 import (
+	"fmt"
+	"strconv"
 	"testing"
 
+	"github.com/codenotary/immudb/embedded/cache"
 	"github.com/codenotary/immudb/embedded/sql"
 	"github.com/codenotary/immudb/embedded/store"
 	"github.com/codenotary/immudb/pkg/api/schema"
@@ -149,3 +152,124 @@ func TestSQLExecAndQuery(t *testing.T) {
 	require.Equal(t, store.ErrKeyNotFound, err)
 
 }
+
+func TestSQLStmtCache(t *testing.T) {
+	for _, cacheSize := range []int{0, 1, DefaultSQLStmtCacheSize} {
+		rootPath := "data_sql_stmt_cache_" + strconv.Itoa(cacheSize)
+
+		options := DefaultOption().WithDbRootPath(rootPath).WithDbName("db").WithSQLStmtCacheSize(cacheSize)
+		require.Equal(t, cacheSize, options.GetSQLStmtCacheSize())
+
+		d, closer := makeDbWith(options)
+
+		_, err := d.SQLExec(&schema.SQLExecRequest{Sql: "CREATE TABLE table1(id INTEGER, title VARCHAR, PRIMARY KEY id)"})
+		require.NoError(t, err)
+
+		upsert := "UPSERT INTO table1(id, title) VALUES (@id, @title)"
+		query := "SELECT title FROM table1 WHERE id = @id"
+
+		for i := 1; i <= 3; i++ {
+			_, err = d.SQLExec(&schema.SQLExecRequest{Sql: upsert, Params: []*schema.NamedParam{
+				{Name: "id", Value: &schema.SQLValue{Value: &schema.SQLValue_N{N: uint64(i)}}},
+				{Name: "title", Value: &schema.SQLValue{Value: &schema.SQLValue_S{S: fmt.Sprintf("title%d", i)}}},
+			}})
+			require.NoError(t, err)
+		}
+
+		for i := 1; i <= 3; i++ {
+			res, err := d.SQLQuery(&schema.SQLQueryRequest{Sql: query, Params: []*schema.NamedParam{
+				{Name: "id", Value: &schema.SQLValue{Value: &schema.SQLValue_N{N: uint64(i)}}},
+			}})
+			require.NoError(t, err)
+			require.Len(t, res.Rows, 1)
+			require.Equal(t, fmt.Sprintf("title%d", i), res.Rows[0].Values[0].GetS())
+		}
+
+		stmtCache := d.(*db).sqlStmts
+
+		if cacheSize == 0 {
+			require.Nil(t, stmtCache)
+		} else {
+			_, err = stmtCache.Get(query)
+			require.NoError(t, err)
+		}
+
+		if cacheSize == 1 {
+			_, err = stmtCache.Get(upsert)
+			require.ErrorIs(t, err, cache.ErrKeyNotFound)
+		}
+
+		closer()
+	}
+}
+
+func TestSQLJSON(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.SQLExec(&schema.SQLExecRequest{Sql: "CREATE TABLE table1(id INTEGER, doc JSON, PRIMARY KEY id)"})
+	require.NoError(t, err)
+
+	_, err = db.SQLExec(&schema.SQLExecRequest{Sql: "UPSERT INTO table1(id, doc) VALUES (@id, @doc)", Params: []*schema.NamedParam{
+		{Name: "id", Value: &schema.SQLValue{Value: &schema.SQLValue_N{N: 1}}},
+		{Name: "doc", Value: &schema.SQLValue{Value: &schema.SQLValue_S{S: `{"name": "alice", "age": 32}`}}},
+	}})
+	require.NoError(t, err)
+
+	res, err := db.SQLQuery(&schema.SQLQueryRequest{Sql: "SELECT doc, JSON_EXTRACT(doc, '$.name') AS name FROM table1 WHERE JSON_VALUE(doc, '$.age', INTEGER) > 18"})
+	require.NoError(t, err)
+	require.Equal(t, sql.JSONType, res.Columns[0].Type)
+	require.Equal(t, sql.JSONType, res.Columns[1].Type)
+	require.Len(t, res.Rows, 1)
+	require.Equal(t, `{"name": "alice", "age": 32}`, res.Rows[0].Values[0].GetS())
+	require.Equal(t, `"alice"`, res.Rows[0].Values[1].GetS())
+}
+
+func TestSQLGroupBy(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.SQLExec(&schema.SQLExecRequest{Sql: `
+		CREATE TABLE table1(id INTEGER, country VARCHAR, amount INTEGER, PRIMARY KEY id);
+		CREATE INDEX ON table1(country);
+	`})
+	require.NoError(t, err)
+
+	_, err = db.SQLExec(&schema.SQLExecRequest{Sql: "UPSERT INTO table1(id, country, amount) VALUES (1, 'IT', 10), (2, 'ES', NULL), (3, 'IT', 20)"})
+	require.NoError(t, err)
+
+	res, err := db.SQLQuery(&schema.SQLQueryRequest{Sql: "SELECT country, COUNT(), MAX(amount) FROM table1 GROUP BY country"})
+	require.NoError(t, err)
+	require.Len(t, res.Rows, 2)
+
+	require.Equal(t, "ES", res.Rows[0].Values[0].GetS())
+	require.Equal(t, uint64(1), res.Rows[0].Values[1].GetN())
+	require.IsType(t, &schema.SQLValue_Null{}, res.Rows[0].Values[2].Value)
+
+	require.Equal(t, "IT", res.Rows[1].Values[0].GetS())
+	require.Equal(t, uint64(2), res.Rows[1].Values[1].GetN())
+	require.Equal(t, uint64(20), res.Rows[1].Values[2].GetN())
+}
+
+func TestSQLExplain(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	_, err := db.SQLExec(&schema.SQLExecRequest{Sql: "CREATE TABLE table1(id INTEGER, title VARCHAR, PRIMARY KEY id)"})
+	require.NoError(t, err)
+
+	_, err = db.SQLExec(&schema.SQLExecRequest{Sql: "UPSERT INTO table1(id, title) VALUES (1, 'title1'), (2, 'title2')"})
+	require.NoError(t, err)
+
+	res, err := db.SQLQuery(&schema.SQLQueryRequest{Sql: "EXPLAIN SELECT title FROM table1 WHERE id > 1"})
+	require.NoError(t, err)
+	require.Len(t, res.Columns, 5)
+	require.Equal(t, "(db.plan.operation)", res.Columns[1].Name)
+	require.Len(t, res.Rows, 3)
+
+	require.Equal(t, "SCAN", res.Rows[0].Values[1].GetS())
+	require.Equal(t, uint64(2), res.Rows[0].Values[4].GetN())
+	require.Equal(t, "FILTER", res.Rows[1].Values[1].GetS())
+	require.Equal(t, "PROJECT", res.Rows[2].Values[1].GetS())
+	require.IsType(t, &schema.SQLValue_Null{}, res.Rows[2].Values[4].Value)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"github.com/codenotary/immudb/embedded/watchers"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// StreamTxs sends the committed transactions from req.SinceTx on, with their key-value entries,
+// and then keeps following the new commits until cancellation is requested, unless req.NoFollow is set.
+// Each message carries the cursor to be used as SinceTx to resume the stream after it
+func (d *db) StreamTxs(req *schema.StreamTxsRequest, cancellation <-chan struct{}, send func(*schema.TxEntries) error) error {
+	if req == nil || send == nil {
+		return ErrIllegalArguments
+	}
+
+	txID := req.SinceTx
+	if txID == 0 {
+		txID = 1
+	}
+
+	lastTxID, _ := d.st.Alh()
+
+	tx := d.st.NewTx()
+	valTx := d.st.NewTx()
+
+	for ; !req.NoFollow || txID <= lastTxID; txID++ {
+		err := d.st.WaitForTx(txID, cancellation)
+		if err == watchers.ErrCancellationRequested {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = d.st.ReadTx(txID, tx)
+		if err != nil {
+			return err
+		}
+
+		txEntries := &schema.TxEntries{
+			Tx:     schema.TxMetatadaTo(tx.Metadata()),
+			Cursor: txID + 1,
+		}
+
+		for _, e := range tx.Entries() {
+			if e.Key()[0] == PrefixTombstoneKeyPrefix {
+				txEntries.DeletedPrefixes = append(txEntries.DeletedPrefixes, TrimPrefix(e.Key()))
+				continue
+			}
+
+			if e.Key()[0] != SetKeyPrefix {
+				continue
+			}
+
+			entry, err := d.getEntryAt(e.Key(), txID, 0, d.st, valTx)
+			if err != nil {
+				return err
+			}
+
+			txEntries.Entries = append(txEntries.Entries, entry)
+		}
+
+		err = send(txEntries)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamTxs(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	err := db.StreamTxs(nil, nil, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	md1, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte("k1"), Value: []byte("v1")},
+		{Key: []byte("k2"), Value: []byte("v2")},
+	}})
+	require.NoError(t, err)
+
+	md2, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k1"), Value: []byte("v3")}}})
+	require.NoError(t, err)
+
+	var replayed []*schema.TxEntries
+
+	err = db.StreamTxs(&schema.StreamTxsRequest{SinceTx: md1.Id, NoFollow: true}, nil, func(txe *schema.TxEntries) error {
+		replayed = append(replayed, txe)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, replayed, 2)
+
+	require.Equal(t, md1.Id, replayed[0].Tx.Id)
+	require.Equal(t, md1.Id+1, replayed[0].Cursor)
+	require.Len(t, replayed[0].Entries, 2)
+	require.Equal(t, []byte("k2"), replayed[0].Entries[1].Key)
+	require.Equal(t, []byte("v2"), replayed[0].Entries[1].Value)
+
+	require.Equal(t, md2.Id, replayed[1].Tx.Id)
+	require.Equal(t, []byte("v3"), replayed[1].Entries[0].Value)
+
+	// resuming from the last cursor follows the new commits
+	cancellation := make(chan struct{})
+	received := make(chan *schema.TxEntries)
+	done := make(chan error)
+
+	go func() {
+		done <- db.StreamTxs(&schema.StreamTxsRequest{SinceTx: replayed[1].Cursor}, cancellation, func(txe *schema.TxEntries) error {
+			received <- txe
+			return nil
+		})
+	}()
+
+	md3, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k3"), Value: []byte("v4")}}})
+	require.NoError(t, err)
+
+	txe := <-received
+	require.Equal(t, md3.Id, txe.Tx.Id)
+	require.Equal(t, []byte("k3"), txe.Entries[0].Key)
+
+	close(cancellation)
+	require.NoError(t, <-done)
+}
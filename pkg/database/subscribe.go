@@ -0,0 +1,132 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/embedded/watchers"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// Subscribe sends the entries of the committed transactions having keys with the requested prefix,
+// waiting for new transactions until cancellation is requested.
+// Entries are sent from req.SinceTx on, or from the next committed transaction when it's not set
+func (d *db) Subscribe(req *schema.SubscribeRequest, cancellation <-chan struct{}, send func(*schema.VerifiableEntry) error) error {
+	if req == nil || send == nil {
+		return ErrIllegalArguments
+	}
+
+	lastTxID, _ := d.st.Alh()
+	if lastTxID < req.ProveSinceTx {
+		return ErrIllegalState
+	}
+
+	txID := req.SinceTx
+	if txID == 0 {
+		txID = lastTxID + 1
+	}
+
+	prefix := EncodeKey(req.Prefix)
+
+	// buffers are owned by the subscription, so d.tx1 and d.tx2 are not held while waiting
+	tx := d.st.NewTx()
+	valTx := d.st.NewTx()
+
+	var rootTx *store.Tx
+	if req.WithProof && req.ProveSinceTx > 0 {
+		rootTx = d.st.NewTx()
+
+		err := d.st.ReadTx(req.ProveSinceTx, rootTx)
+		if err != nil {
+			return err
+		}
+	}
+
+	for ; ; txID++ {
+		err := d.st.WaitForTx(txID, cancellation)
+		if err == watchers.ErrCancellationRequested {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = d.st.ReadTx(txID, tx)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range tx.Entries() {
+			if !bytes.HasPrefix(e.Key(), prefix) {
+				continue
+			}
+
+			entry, err := d.getEntryAt(e.Key(), txID, 0, d.st, valTx)
+			if err != nil {
+				return err
+			}
+
+			ventry := &schema.VerifiableEntry{Entry: entry}
+
+			if req.WithProof {
+				err = d.proveSubscribedEntry(ventry, e.Key(), tx, rootTx)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = send(ventry)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// proveSubscribedEntry adds the inclusion proof of the key in tx and the dual proof between tx and rootTx.
+// When rootTx is nil, the dual proof is built against tx itself
+func (d *db) proveSubscribedEntry(ventry *schema.VerifiableEntry, key []byte, tx, rootTx *store.Tx) error {
+	inclusionProof, err := tx.Proof(key)
+	if err != nil {
+		return err
+	}
+
+	sourceTx, targetTx := tx, tx
+
+	if rootTx != nil {
+		if rootTx.ID <= tx.ID {
+			sourceTx = rootTx
+		} else {
+			targetTx = rootTx
+		}
+	}
+
+	dualProof, err := d.st.DualProof(sourceTx, targetTx)
+	if err != nil {
+		return err
+	}
+
+	ventry.InclusionProof = schema.InclusionProofTo(inclusionProof)
+	ventry.VerifiableTx = &schema.VerifiableTx{
+		Tx:        schema.TxTo(tx),
+		DualProof: schema.DualProofTo(dualProof),
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribe(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	err := db.Subscribe(nil, nil, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = db.Subscribe(&schema.SubscribeRequest{ProveSinceTx: 100}, nil, func(*schema.VerifiableEntry) error { return nil })
+	require.Equal(t, ErrIllegalState, err)
+
+	md, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("orders/0"), Value: []byte("v0")}}})
+	require.NoError(t, err)
+
+	cancellation := make(chan struct{})
+	received := make(chan *schema.VerifiableEntry)
+	done := make(chan error)
+
+	go func() {
+		done <- db.Subscribe(
+			&schema.SubscribeRequest{Prefix: []byte("orders/"), SinceTx: md.Id + 1, WithProof: true, ProveSinceTx: md.Id},
+			cancellation,
+			func(e *schema.VerifiableEntry) error {
+				received <- e
+				return nil
+			})
+	}()
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{
+		{Key: []byte("users/1"), Value: []byte("v1")},
+		{Key: []byte("orders/1"), Value: []byte("v1")},
+	}})
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("orders/2"), Value: []byte("v2")}}})
+	require.NoError(t, err)
+
+	for _, k := range []string{"orders/1", "orders/2"} {
+		e := <-received
+		require.Equal(t, []byte(k), e.Entry.Key)
+
+		inclusionProof := schema.InclusionProofFrom(e.InclusionProof)
+		dualProof := schema.DualProofFrom(e.VerifiableTx.DualProof)
+
+		verifies := store.VerifyInclusion(
+			inclusionProof,
+			EncodeKV(e.Entry.Key, e.Entry.Value),
+			dualProof.TargetTxMetadata.Eh,
+		)
+		require.True(t, verifies)
+
+		verifies = store.VerifyDualProof(
+			dualProof,
+			md.Id,
+			e.Entry.Tx,
+			schema.TxMetadataFrom(md).Alh(),
+			dualProof.TargetTxMetadata.Alh(),
+		)
+		require.True(t, verifies)
+	}
+
+	close(cancellation)
+	require.NoError(t, <-done)
+
+	// entries of already committed transactions are sent from sinceTx on
+	cancellation = make(chan struct{})
+	go func() {
+		done <- db.Subscribe(
+			&schema.SubscribeRequest{Prefix: []byte("orders/"), SinceTx: md.Id},
+			cancellation,
+			func(e *schema.VerifiableEntry) error {
+				received <- e
+				return nil
+			})
+	}()
+
+	e := <-received
+	require.Equal(t, []byte("orders/0"), e.Entry.Key)
+	require.Nil(t, e.InclusionProof)
+
+	close(cancellation)
+	<-received
+	<-received
+	require.NoError(t, <-done)
+}
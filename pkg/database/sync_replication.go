@@ -0,0 +1,118 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// ConfirmReplication records that the replica durably holds the transactions up to txID,
+// txHash must be the accumulated linear hash of the local transaction
+func (d *db) ConfirmReplication(replicaID string, txID uint64, txHash []byte) error {
+	if replicaID == "" || txID == 0 {
+		return ErrIllegalArguments
+	}
+
+	lastTxID, _ := d.st.Alh()
+	if txID > lastTxID {
+		return ErrIllegalArguments
+	}
+
+	tx := d.st.NewTx()
+
+	err := d.st.ReadTx(txID, tx)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(tx.Alh[:], txHash) {
+		d.Logger.Errorf("Replica '%s' of database '%s' confirmed tx %d with hash %x instead of %x, replication to it is stopped",
+			replicaID, d.name, txID, txHash, tx.Alh[:])
+
+		d.replicas.diverged(replicaID, &ReplicaDivergence{
+			TxID:          txID,
+			ReplicaTxHash: txHash,
+			TxHash:        tx.Alh[:],
+			DetectedAt:    time.Now(),
+		})
+
+		return ErrReplicaDiverged
+	}
+
+	d.replicas.confirm(replicaID, txID)
+
+	return nil
+}
+
+// waitForReplicas blocks, under synchronous replication, until enough replicas confirmed the transaction or the timeout expires
+func (d *db) waitForReplicas(txID uint64) error {
+	rOpts := d.options.GetReplicationOptions()
+	if txID == 0 || rOpts == nil || rOpts.SyncAcks <= 0 {
+		return nil
+	}
+
+	timeout := rOpts.SyncTimeout
+	if timeout <= 0 {
+		timeout = DefaultSyncTimeout
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		n, changed := d.replicas.confirmedBy(txID, rOpts.ReplicaFilters)
+		if n >= rOpts.SyncAcks {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			if rOpts.SyncFallback == SyncFallbackFail {
+				return ErrSyncReplicationTimeout
+			}
+
+			d.Logger.Warningf("Tx %d of database '%s' confirmed by %d of %d replicas in time, acknowledged asynchronously", txID, d.name, n, rOpts.SyncAcks)
+			return nil
+		}
+	}
+}
+
+// awaitReplicas waits, under synchronous replication, for the replicas to confirm the transaction committed by
+// a write operation. It must be deferred before acquiring the database lock, as replicas need it to export the transaction
+func (d *db) awaitReplicas(txID uint64, err error) error {
+	if err != nil {
+		return err
+	}
+
+	return d.waitForReplicas(txID)
+}
+
+func lastTxOf(res *schema.SQLExecResult) uint64 {
+	lastTxID := uint64(0)
+
+	for _, md := range append(res.GetCtxs(), res.GetDtxs()...) {
+		if md.Id > lastTxID {
+			lastTxID = md.Id
+		}
+	}
+
+	return lastTxID
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncReplication(t *testing.T) {
+	rootPath := "data_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	rOpts := (&ReplicationOptions{}).
+		WithSyncAcks(1).
+		WithSyncTimeout(50 * time.Millisecond).
+		WithSyncFallback(SyncFallbackFail)
+
+	options := DefaultOption().WithDbRootPath(rootPath).WithDbName("db").WithReplicationOptions(rOpts)
+
+	db, closer := makeDbWith(options)
+	defer closer()
+
+	initialState, err := db.CurrentState()
+	require.NoError(t, err)
+
+	kvs := []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}
+
+	_, err = db.Set(&schema.SetRequest{KVs: kvs})
+	require.Equal(t, ErrSyncReplicationTimeout, err)
+
+	// the transaction is committed locally anyway
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, initialState.TxId+1, state.TxId)
+
+	err = db.ConfirmReplication("replica1", state.TxId, []byte("wrong"))
+	require.Equal(t, ErrReplicaDiverged, err)
+
+	err = db.ConfirmReplication("replica1", state.TxId+1, state.TxHash)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = db.ConfirmReplication("", state.TxId, state.TxHash)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = db.ConfirmReplication("replica1", state.TxId, state.TxHash)
+	require.NoError(t, err)
+
+	rOpts.WithSyncTimeout(5 * time.Second)
+
+	done := make(chan error)
+
+	go func() {
+		_, err := db.Set(&schema.SetRequest{KVs: kvs})
+		done <- err
+	}()
+
+	for {
+		state, err = db.CurrentState()
+		require.NoError(t, err)
+
+		if state.TxId == initialState.TxId+2 {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	err = db.ConfirmReplication("replica1", state.TxId, state.TxHash)
+	require.NoError(t, err)
+
+	require.NoError(t, <-done)
+
+	rOpts.WithSyncTimeout(10 * time.Millisecond).WithSyncFallback(SyncFallbackAsync)
+
+	_, err = db.Set(&schema.SetRequest{KVs: kvs})
+	require.NoError(t, err)
+
+	rOpts.WithSyncAcks(0)
+
+	_, err = db.Set(&schema.SetRequest{KVs: kvs})
+	require.NoError(t, err)
+}
+
+func TestSyncReplicationFilteredReplicas(t *testing.T) {
+	rootPath := "data_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	rOpts := (&ReplicationOptions{}).
+		WithSyncAcks(1).
+		WithSyncTimeout(50 * time.Millisecond).
+		WithSyncFallback(SyncFallbackFail).
+		WithReplicaFilters(map[string][][]byte{"edge1": {[]byte("tenant1/")}})
+
+	options := DefaultOption().WithDbRootPath(rootPath).WithDbName("db").WithReplicationOptions(rOpts)
+
+	d, closer := makeDbWith(options)
+	defer closer()
+
+	_, err := d.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("tenant1/key"), Value: []byte("value")}}})
+	require.Equal(t, ErrSyncReplicationTimeout, err)
+
+	state, err := d.CurrentState()
+	require.NoError(t, err)
+
+	// a filtered replica only holds part of the transaction, it does not acknowledge it
+	err = d.ConfirmReplication("edge1", state.TxId, state.TxHash)
+	require.NoError(t, err)
+
+	db := d.(*db)
+
+	err = db.waitForReplicas(state.TxId)
+	require.Equal(t, ErrSyncReplicationTimeout, err)
+
+	err = d.ConfirmReplication("replica1", state.TxId, state.TxHash)
+	require.NoError(t, err)
+
+	err = db.waitForReplicas(state.TxId)
+	require.NoError(t, err)
+}
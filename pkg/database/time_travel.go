@@ -0,0 +1,149 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// getAsOf resolves the key as it was right after the transaction req.AsOfTx was committed,
+// or as it was at req.AsOfTime, when the latest transaction committed by then is used
+func (d *db) getAsOf(req *schema.KeyRequest) (*schema.Entry, error) {
+	if req.AtTx > 0 || req.SinceTx > 0 || req.Snapshot != "" || (req.AsOfTx > 0 && req.AsOfTime > 0) {
+		return nil, ErrIllegalArguments
+	}
+
+	lastTxID, _ := d.st.Alh()
+
+	if req.AsOfTx > lastTxID {
+		return nil, ErrIllegalArguments
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	asOfTx := req.AsOfTx
+
+	if req.AsOfTime > 0 {
+		txID, err := d.lastTxUntil(req.AsOfTime, lastTxID, d.tx1)
+		if err != nil {
+			return nil, err
+		}
+
+		asOfTx = txID
+	}
+
+	key := EncodeKey(req.Key)
+
+	ktx, err := d.keyTxAsOf(key, asOfTx)
+	if err != nil {
+		return nil, err
+	}
+
+	if ktx == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+
+	entry, err := d.getAt(key, ktx, 0, d.st, d.tx1)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := d.deletedByPrefix(key, ktx, asOfTx)
+	if err != nil {
+		return nil, err
+	}
+
+	if deleted {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return entry, nil
+}
+
+// keyTxAsOf returns the tx of the latest version of the encoded key, tombstones included, committed up to asOfTx.
+// Zero is returned when the key was not yet written by then
+func (d *db) keyTxAsOf(key []byte, asOfTx uint64) (uint64, error) {
+	if asOfTx == 0 {
+		return 0, nil
+	}
+
+	err := d.st.WaitForIndexingUpto(asOfTx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	snap, err := d.st.SnapshotSince(asOfTx)
+	if err != nil {
+		return 0, err
+	}
+	defer snap.Close()
+
+	r, err := snap.NewKeyReader(&store.KeyReaderSpec{SeekKey: key, Prefix: key, InclusiveSeek: true})
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	rkey, _, ktx, err := r.ReadAsBefore(asOfTx + 1)
+	if err == store.ErrNoMoreEntries {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// the reader moves to the following keys when this one was not yet written
+	if !bytes.Equal(rkey, key) {
+		return 0, nil
+	}
+
+	return ktx, nil
+}
+
+// lastTxUntil returns the latest transaction committed at or before ts, up to lastTxID
+func (d *db) lastTxUntil(ts int64, lastTxID uint64, tx *store.Tx) (uint64, error) {
+	var txID uint64
+
+	// transaction timestamps follow the commit order, so a binary search is enough
+	left, right := uint64(1), lastTxID
+
+	for left <= right {
+		mid := left + (right-left)/2
+
+		err := d.st.ReadTx(mid, tx)
+		if err != nil {
+			return 0, err
+		}
+
+		if tx.Ts <= ts {
+			txID = mid
+			left = mid + 1
+		} else {
+			right = mid - 1
+		}
+	}
+
+	if txID == 0 {
+		return 0, store.ErrKeyNotFound
+	}
+
+	return txID, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAsOfTx(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	md1, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k1"), Value: []byte("v1")}}})
+	require.NoError(t, err)
+
+	md2, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k0"), Value: []byte("v0")}}})
+	require.NoError(t, err)
+
+	md3, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k1"), Value: []byte("v2")}}})
+	require.NoError(t, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("k1"), AsOfTx: md3.Id + 1})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("k1"), AsOfTx: md2.Id, AtTx: md1.Id})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("k1"), AsOfTx: md2.Id, AsOfTime: time.Now().Unix()})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("k1"), AsOfTx: md2.Id})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v1"), entry.Value)
+	require.Equal(t, md1.Id, entry.Tx)
+
+	entry, err = db.Get(&schema.KeyRequest{Key: []byte("k1"), AsOfTx: md3.Id})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v2"), entry.Value)
+
+	// k0 was written after md1
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("k0"), AsOfTx: md1.Id})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	dmd, err := db.Delete(&schema.DeleteKeysRequest{Keys: [][]byte{[]byte("k1")}})
+	require.NoError(t, err)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("k1"), AsOfTx: dmd.Id})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	vitem, err := db.VerifiableGet(&schema.VerifiableGetRequest{
+		KeyRequest:   &schema.KeyRequest{Key: []byte("k1"), AsOfTx: md2.Id},
+		ProveSinceTx: md3.Id,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v1"), vitem.Entry.Value)
+}
+
+func TestGetAsOfTime(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	md1, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k1"), Value: []byte("v1")}}})
+	require.NoError(t, err)
+
+	// transaction timestamps have a resolution of one second
+	time.Sleep(1100 * time.Millisecond)
+
+	md2, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k1"), Value: []byte("v2")}}})
+	require.NoError(t, err)
+	require.Greater(t, md2.Ts, md1.Ts)
+
+	_, err = db.Get(&schema.KeyRequest{Key: []byte("k1"), AsOfTime: md1.Ts - 1})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("k1"), AsOfTime: md1.Ts})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v1"), entry.Value)
+
+	entry, err = db.Get(&schema.KeyRequest{Key: []byte("k1"), AsOfTime: md2.Ts})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v2"), entry.Value)
+
+	entry, err = db.Get(&schema.KeyRequest{Key: []byte("k1"), AsOfTime: time.Now().Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	require.Equal(t, []byte("v2"), entry.Value)
+}
@@ -16,20 +16,28 @@ limitations under the License.
 
 package database
 
-import "sync"
+import (
+	"sort"
+	"sync"
+)
 
 // DatabaseList interface
 type DatabaseList interface {
 	Append(database DB)
+	Register(dbname string, opener func() (DB, error))
 	GetByIndex(index int64) DB
 	GetByName(string) (DB, error)
+	Delete(dbname string) (DB, error)
 	GetId(dbname string) int64
 	Length() int
+	Unloaded() []string
 }
 
 type databaseList struct {
 	databases           []DB
 	databasenameToIndex map[string]int64
+	openers             map[string]func() (DB, error)
+	loadMutex           sync.Mutex
 	sync.RWMutex
 }
 
@@ -38,6 +46,7 @@ func NewDatabaseList() DatabaseList {
 	return &databaseList{
 		databasenameToIndex: make(map[string]int64),
 		databases:           make([]DB, 0),
+		openers:             make(map[string]func() (DB, error)),
 	}
 }
 
@@ -49,6 +58,65 @@ func (d *databaseList) Append(database DB) {
 	d.databases = append(d.databases, database)
 }
 
+// Register adds a database which gets opened the first time it's accessed by name,
+// it gets an index only once opened
+func (d *databaseList) Register(dbname string, opener func() (DB, error)) {
+	d.Lock()
+	defer d.Unlock()
+
+	d.openers[dbname] = opener
+}
+
+// Unloaded returns the sorted names of registered databases not opened yet
+func (d *databaseList) Unloaded() []string {
+	d.RLock()
+	defer d.RUnlock()
+
+	names := make([]string, 0, len(d.openers))
+	for name := range d.openers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// load opens a registered database and appends it to the list
+func (d *databaseList) load(dbname string) (int64, DB, error) {
+	d.loadMutex.Lock()
+	defer d.loadMutex.Unlock()
+
+	d.RLock()
+	index, loaded := d.databasenameToIndex[dbname]
+	opener, registered := d.openers[dbname]
+	d.RUnlock()
+
+	if loaded {
+		return index, d.GetByIndex(index), nil
+	}
+
+	if !registered {
+		return -1, nil, ErrDatabaseNotExists
+	}
+
+	db, err := opener()
+	if err != nil {
+		return -1, nil, err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	delete(d.openers, dbname)
+
+	index = int64(len(d.databases))
+	d.databasenameToIndex[dbname] = index
+	d.databases = append(d.databases, db)
+
+	return index, db, nil
+}
+
 func (d *databaseList) GetByIndex(index int64) DB {
 	d.RLock()
 	defer d.RUnlock()
@@ -56,15 +124,46 @@ func (d *databaseList) GetByIndex(index int64) DB {
 	return d.databases[index]
 }
 
+// GetByName returns the database, registered databases are opened if needed
 func (d *databaseList) GetByName(dbname string) (DB, error) {
 	d.RLock()
-	defer d.RUnlock()
+	index, ok := d.databasenameToIndex[dbname]
+	d.RUnlock()
+
+	if ok {
+		return d.GetByIndex(index), nil
+	}
+
+	_, db, err := d.load(dbname)
+	return db, err
+}
 
-	if _, ok := d.databasenameToIndex[dbname]; !ok {
+// Delete removes the database from the list. Indexes of the remaining databases are preserved,
+// so the slot of the removed one is left empty and GetByIndex returns nil for it.
+// A nil database is returned when the removed one was registered but not opened yet
+func (d *databaseList) Delete(dbname string) (DB, error) {
+	d.loadMutex.Lock()
+	defer d.loadMutex.Unlock()
+
+	d.Lock()
+	defer d.Unlock()
+
+	if _, ok := d.openers[dbname]; ok {
+		delete(d.openers, dbname)
+		return nil, nil
+	}
+
+	index, ok := d.databasenameToIndex[dbname]
+	if !ok {
 		return nil, ErrDatabaseNotExists
 	}
 
-	return d.databases[d.databasenameToIndex[dbname]], nil
+	db := d.databases[index]
+
+	d.databases[index] = nil
+	delete(d.databasenameToIndex, dbname)
+
+	return db, nil
 }
 
 func (d *databaseList) Length() int {
@@ -73,14 +172,20 @@ func (d *databaseList) Length() int {
 	return len(d.databases)
 }
 
-// GetById returns the database id number. -1 if database is not present
+// GetById returns the database id number, registered databases are opened if needed. -1 if database is not present
 func (d *databaseList) GetId(dbname string) int64 {
 	d.RLock()
-	defer d.RUnlock()
+	id, ok := d.databasenameToIndex[dbname]
+	d.RUnlock()
 
-	if id, ok := d.databasenameToIndex[dbname]; ok {
+	if ok {
 		return id
 	}
 
-	return -1
+	id, _, err := d.load(dbname)
+	if err != nil {
+		return -1
+	}
+
+	return id
 }
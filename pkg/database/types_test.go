@@ -0,0 +1,86 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseListLazyLoading(t *testing.T) {
+	dbList := NewDatabaseList()
+
+	db1, closer1 := makeDb()
+	defer closer1()
+
+	db2, closer2 := makeDb()
+	defer closer2()
+
+	dbList.Append(db1)
+
+	opened := 0
+
+	dbList.Register("db2", func() (DB, error) {
+		opened++
+		return db2, nil
+	})
+	dbList.Register("db3", func() (DB, error) {
+		return nil, errors.New("open error")
+	})
+	dbList.Register("db4", func() (DB, error) {
+		return nil, errors.New("open error")
+	})
+
+	require.Equal(t, 1, dbList.Length())
+	require.Equal(t, []string{"db2", "db3", "db4"}, dbList.Unloaded())
+
+	require.Equal(t, int64(1), dbList.GetId("db2"))
+	require.Equal(t, int64(1), dbList.GetId("db2"))
+	require.Equal(t, 1, opened)
+	require.Equal(t, 2, dbList.Length())
+	require.Equal(t, db2, dbList.GetByIndex(1))
+	require.Equal(t, []string{"db3", "db4"}, dbList.Unloaded())
+
+	db, err := dbList.GetByName("db2")
+	require.NoError(t, err)
+	require.Equal(t, db2, db)
+
+	_, err = dbList.GetByName("db3")
+	require.EqualError(t, err, "open error")
+	require.Equal(t, int64(-1), dbList.GetId("db3"))
+	require.Equal(t, []string{"db3", "db4"}, dbList.Unloaded())
+
+	db, err = dbList.Delete("db3")
+	require.NoError(t, err)
+	require.Nil(t, db)
+
+	_, err = dbList.GetByName("db3")
+	require.Equal(t, ErrDatabaseNotExists, err)
+
+	db, err = dbList.Delete("db2")
+	require.NoError(t, err)
+	require.Equal(t, db2, db)
+	require.Nil(t, dbList.GetByIndex(1))
+	require.Equal(t, int64(-1), dbList.GetId("db2"))
+
+	_, err = dbList.Delete("db2")
+	require.Equal(t, ErrDatabaseNotExists, err)
+
+	require.Equal(t, []string{"db4"}, dbList.Unloaded())
+}
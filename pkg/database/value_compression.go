@@ -0,0 +1,65 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+var ErrInvalidValueCompression = errors.New("invalid value compression")
+
+const (
+	ValueCompressionZStd   = "zstd"
+	ValueCompressionSnappy = "snappy"
+)
+
+var valueCompressionFormats = map[string]int{
+	ValueCompressionZStd:   appendable.ZStdCompression,
+	ValueCompressionSnappy: appendable.SnappyCompression,
+}
+
+// ValidateValueCompression checks the compression is empty or one of the supported algorithms
+func ValidateValueCompression(valueCompression string) error {
+	if valueCompression == "" {
+		return nil
+	}
+
+	if _, ok := valueCompressionFormats[valueCompression]; !ok {
+		return fmt.Errorf("%w: '%s', expected %s or %s", ErrInvalidValueCompression, valueCompression, ValueCompressionZStd, ValueCompressionSnappy)
+	}
+
+	return nil
+}
+
+// withValueCompression returns a copy of the store options compressing values in the value logs.
+// Entries keep the digest of the uncompressed value so proofs are not affected.
+// Value logs keep the compression they were created with
+func withValueCompression(opts *store.Options, valueCompression string) *store.Options {
+	format, ok := valueCompressionFormats[valueCompression]
+	if !ok {
+		return opts
+	}
+
+	compressedOpts := *opts
+	compressedOpts.CompressionFormat = format
+
+	return &compressedOpts
+}
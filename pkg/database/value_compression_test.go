@@ -0,0 +1,78 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueCompression(t *testing.T) {
+	require.NoError(t, ValidateValueCompression(""))
+	require.ErrorIs(t, ValidateValueCompression("lz4"), ErrInvalidValueCompression)
+
+	_, err := NewDb(DefaultOption().WithDbRootPath("data_invalid_compression").WithValueCompression("lz4"), nil, logger.NewSimpleLogger("immudb ", os.Stderr))
+	require.ErrorIs(t, err, ErrInvalidValueCompression)
+
+	value := bytes.Repeat([]byte(`{"customer":"acme","amount":100,"currency":"EUR"},`), 64)
+
+	for _, compression := range []string{ValueCompressionZStd, ValueCompressionSnappy} {
+		t.Run(compression, func(t *testing.T) {
+			rootPath := "data_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+			options := DefaultOption().WithDbRootPath(rootPath).WithDbName("db").WithValueCompression(compression)
+
+			db, closer := makeDbWith(options)
+			defer closer()
+
+			require.Equal(t, appendable.NoCompression, options.GetStoreOptions().CompressionFormat)
+
+			_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("payload"), Value: value}}})
+			require.NoError(t, err)
+
+			vitem, err := db.VerifiableGet(&schema.VerifiableGetRequest{KeyRequest: &schema.KeyRequest{Key: []byte("payload")}})
+			require.NoError(t, err)
+			require.Equal(t, value, vitem.Entry.Value)
+
+			inclusionProof := schema.InclusionProofFrom(vitem.InclusionProof)
+			dualProof := schema.DualProofFrom(vitem.VerifiableTx.DualProof)
+
+			verifies := store.VerifyInclusion(inclusionProof, EncodeKV(vitem.Entry.Key, value), dualProof.TargetTxMetadata.Eh)
+			require.True(t, verifies)
+
+			var vLogSize int64
+			err = filepath.Walk(filepath.Join(rootPath, "db", "val_0"), func(_ string, info os.FileInfo, err error) error {
+				if err == nil && !info.IsDir() {
+					vLogSize += info.Size()
+				}
+				return err
+			})
+			require.NoError(t, err)
+			require.Less(t, vLogSize, int64(len(value)/4))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// valueFilter evaluates the value predicates of a scan, all of them must hold for a value to match
+type valueFilter struct {
+	prefix    []byte
+	minSize   uint64
+	maxSize   uint64
+	jsonPath  []string
+	jsonValue interface{}
+}
+
+func newValueFilter(f *schema.ValueFilter) (*valueFilter, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	if f.MaxSize > 0 && f.MinSize > f.MaxSize {
+		return nil, ErrInvalidValueFilter
+	}
+
+	vf := &valueFilter{
+		prefix:  f.Prefix,
+		minSize: f.MinSize,
+		maxSize: f.MaxSize,
+	}
+
+	if f.JsonField != "" {
+		err := json.Unmarshal([]byte(f.JsonValue), &vf.jsonValue)
+		if err != nil {
+			return nil, ErrInvalidValueFilter
+		}
+
+		vf.jsonPath = strings.Split(f.JsonField, ".")
+	}
+
+	return vf, nil
+}
+
+func (f *valueFilter) matches(value []byte) bool {
+	if f == nil {
+		return true
+	}
+
+	size := uint64(len(value))
+
+	if size < f.minSize || (f.maxSize > 0 && size > f.maxSize) {
+		return false
+	}
+
+	if !bytes.HasPrefix(value, f.prefix) {
+		return false
+	}
+
+	if len(f.jsonPath) == 0 {
+		return true
+	}
+
+	var doc interface{}
+
+	err := json.Unmarshal(value, &doc)
+	if err != nil {
+		return false
+	}
+
+	for _, field := range f.jsonPath {
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return false
+		}
+
+		doc, ok = obj[field]
+		if !ok {
+			return false
+		}
+	}
+
+	return reflect.DeepEqual(doc, f.jsonValue)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+var ErrValueTransformerAlreadyRegistered = errors.New("value transformer already registered")
+var ErrValueTransformerNotFound = errors.New("value transformer not found")
+var ErrTransformedValueNotVerifiable = errors.New("value was modified by the database transformers and can not be verified by the client")
+
+// ValueTransformer rewrites values before they get committed (e.g. PII masking, field redaction or normalization).
+// Implementations must be deterministic and safe for concurrent use
+type ValueTransformer interface {
+	Transform(key []byte, value []byte) ([]byte, error)
+}
+
+// ValueTransformerFunc adapts a plain function to a ValueTransformer
+type ValueTransformerFunc func(key []byte, value []byte) ([]byte, error)
+
+// Transform ...
+func (f ValueTransformerFunc) Transform(key []byte, value []byte) ([]byte, error) {
+	return f(key, value)
+}
+
+var valueTransformers = struct {
+	m map[string]ValueTransformer
+	sync.RWMutex
+}{
+	m: map[string]ValueTransformer{
+		"trimspace": ValueTransformerFunc(func(_ []byte, value []byte) ([]byte, error) {
+			return bytes.TrimSpace(value), nil
+		}),
+	},
+}
+
+// RegisterValueTransformer makes a transformer available under the provided name,
+// so it can be referenced from the database settings
+func RegisterValueTransformer(name string, t ValueTransformer) error {
+	if name == "" || t == nil {
+		return ErrIllegalArguments
+	}
+
+	valueTransformers.Lock()
+	defer valueTransformers.Unlock()
+
+	if _, ok := valueTransformers.m[name]; ok {
+		return fmt.Errorf("%w: '%s'", ErrValueTransformerAlreadyRegistered, name)
+	}
+
+	valueTransformers.m[name] = t
+
+	return nil
+}
+
+// GetValueTransformer returns the transformer registered under the provided name
+func GetValueTransformer(name string) (ValueTransformer, error) {
+	valueTransformers.RLock()
+	defer valueTransformers.RUnlock()
+
+	t, ok := valueTransformers.m[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrValueTransformerNotFound, name)
+	}
+
+	return t, nil
+}
+
+// ValidateValueTransformers checks all the provided transformer names are registered
+func ValidateValueTransformers(names []string) error {
+	for _, name := range names {
+		if _, err := GetValueTransformer(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// transformValue runs the database pipeline over the value. When the value gets modified and
+// the database is configured to retain it, the digest of the original value is returned as metadata
+func (d *db) transformValue(key []byte, value []byte) ([]byte, *schema.KVMetadata, error) {
+	if len(d.options.valueTransformers) == 0 {
+		return value, nil, nil
+	}
+
+	tv := value
+
+	for _, name := range d.options.valueTransformers {
+		t, err := GetValueTransformer(name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tv, err = t.Transform(key, tv)
+		if err != nil {
+			return nil, nil, fmt.Errorf("value transformer '%s' failed: %w", name, err)
+		}
+	}
+
+	if bytes.Equal(tv, value) || !d.options.retainOriginalDigest {
+		return tv, nil, nil
+	}
+
+	digest := sha256.Sum256(value)
+
+	return tv, &schema.KVMetadata{OriginalDigest: digest[:]}, nil
+}
+
+// encodeKV encodes the key-value pair after running it through the database pipeline.
+// Verifiable writes are rejected if the value gets modified as the client won't be able to verify it
+func (d *db) encodeKV(key []byte, value []byte, verifiable bool) (*store.KV, error) {
+	return d.encodeKVWithMetadata(key, value, verifiable, nil)
+}
+
+// encodeKVWithMetadata is like encodeKV but the provided metadata, if any, is stored along with the one
+// produced by the database pipeline
+func (d *db) encodeKVWithMetadata(key []byte, value []byte, verifiable bool, md *schema.KVMetadata) (*store.KV, error) {
+	tv, tmd, err := d.transformValue(key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if verifiable && !bytes.Equal(tv, value) {
+		return nil, ErrTransformedValueNotVerifiable
+	}
+
+	if tmd != nil {
+		if md == nil {
+			md = &schema.KVMetadata{}
+		}
+		md.OriginalDigest = tmd.OriginalDigest
+	}
+
+	return EncodeKVWithMetadata(key, tv, md), nil
+}
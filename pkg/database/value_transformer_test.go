@@ -0,0 +1,158 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueTransformerRegistry(t *testing.T) {
+	err := RegisterValueTransformer("", nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = RegisterValueTransformer("trimspace", ValueTransformerFunc(func(k, v []byte) ([]byte, error) { return v, nil }))
+	require.True(t, errors.Is(err, ErrValueTransformerAlreadyRegistered))
+
+	_, err = GetValueTransformer("unknown")
+	require.True(t, errors.Is(err, ErrValueTransformerNotFound))
+
+	err = ValidateValueTransformers([]string{"trimspace", "unknown"})
+	require.True(t, errors.Is(err, ErrValueTransformerNotFound))
+
+	require.NoError(t, ValidateValueTransformers([]string{"trimspace"}))
+}
+
+func TestMetadataEncoding(t *testing.T) {
+	val := WrapWithPrefix([]byte("value"), PlainValuePrefix)
+
+	require.Equal(t, val, WrapWithMetadata(val, nil))
+	require.Equal(t, val, WrapWithMetadata(val, &schema.KVMetadata{}))
+
+	md := &schema.KVMetadata{OriginalDigest: []byte("digest")}
+	wv := WrapWithMetadata(val, md)
+	require.Equal(t, byte(MetadataValuePrefix), wv[0])
+
+	umd, uval, err := UnwrapMetadata(wv)
+	require.NoError(t, err)
+	require.Equal(t, md.OriginalDigest, umd.OriginalDigest)
+	require.Equal(t, val, uval)
+
+	umd, uval, err = UnwrapMetadata(val)
+	require.NoError(t, err)
+	require.Nil(t, umd)
+	require.Equal(t, val, uval)
+
+	_, _, err = UnwrapMetadata(wv[:3])
+	require.Equal(t, ErrInvalidMetadata, err)
+
+	_, _, err = UnwrapMetadata(wv[:len(wv)-len(val)])
+	require.Equal(t, ErrInvalidMetadata, err)
+
+	_, err = DecodeMetadata([]byte{99, 0, 0, 0, 0})
+	require.Equal(t, ErrInvalidMetadata, err)
+
+	_, err = DecodeMetadata([]byte{mdAttrOriginalDigest, 0, 0, 0, 10})
+	require.Equal(t, ErrInvalidMetadata, err)
+}
+
+func TestValueTransformersPipeline(t *testing.T) {
+	err := RegisterValueTransformer("mask_digits", ValueTransformerFunc(func(k, v []byte) ([]byte, error) {
+		return bytes.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return '*'
+			}
+			return r
+		}, v), nil
+	}))
+	require.NoError(t, err)
+
+	err = RegisterValueTransformer("reject_empty", ValueTransformerFunc(func(k, v []byte) ([]byte, error) {
+		if len(v) == 0 {
+			return nil, errors.New("empty value")
+		}
+		return v, nil
+	}))
+	require.NoError(t, err)
+
+	rootPath := "data_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	options := DefaultOption().
+		WithDbRootPath(rootPath).
+		WithDbName("db").
+		WithValueTransformers([]string{"trimspace", "mask_digits", "reject_empty"}).
+		WithRetainOriginalDigest(true)
+
+	db, closer := makeDbWith(options)
+	defer closer()
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("card"), Value: []byte(" 4111-1111 ")}}})
+	require.NoError(t, err)
+
+	entry, err := db.Get(&schema.KeyRequest{Key: []byte("card")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("****-****"), entry.Value)
+
+	digest := sha256.Sum256([]byte(" 4111-1111 "))
+	require.Equal(t, digest[:], entry.Metadata.OriginalDigest)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("name"), Value: []byte("john")}}})
+	require.NoError(t, err)
+
+	entry, err = db.Get(&schema.KeyRequest{Key: []byte("name")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("john"), entry.Value)
+	require.Nil(t, entry.Metadata)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("blank"), Value: []byte("  ")}}})
+	require.Error(t, err)
+
+	_, err = db.ExecAll(&schema.ExecAllRequest{Operations: []*schema.Op{
+		{Operation: &schema.Op_Kv{Kv: &schema.KeyValue{Key: []byte("phone"), Value: []byte("555 1234")}}},
+	}})
+	require.NoError(t, err)
+
+	entries, err := db.History(&schema.HistoryRequest{Key: []byte("phone")})
+	require.NoError(t, err)
+	require.Len(t, entries.Entries, 1)
+	require.Equal(t, []byte("*** ****"), entries.Entries[0].Value)
+	require.NotNil(t, entries.Entries[0].Metadata)
+
+	_, err = db.VerifiableSet(&schema.VerifiableSetRequest{
+		SetRequest: &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("card"), Value: []byte("4111")}}},
+	})
+	require.Equal(t, ErrTransformedValueNotVerifiable, err)
+
+	_, err = db.VerifiableSet(&schema.VerifiableSetRequest{
+		SetRequest: &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("name"), Value: []byte("jane")}}},
+	})
+	require.NoError(t, err)
+
+	vEntry, err := db.VerifiableGet(&schema.VerifiableGetRequest{KeyRequest: &schema.KeyRequest{Key: []byte("card")}})
+	require.NoError(t, err)
+	require.NotNil(t, vEntry.Entry.Metadata)
+
+	_, err = NewDb(DefaultOption().WithDbRootPath(rootPath).WithDbName("db1").WithValueTransformers([]string{"unknown"}), nil, nil)
+	require.True(t, errors.Is(err, ErrValueTransformerNotFound))
+}
@@ -37,7 +37,7 @@ func buildNamedParams(paramsType []*schema.Column, paramsVal []interface{}) ([]*
 					return nil, err
 				}
 				pMap[param.Name] = int64(int)
-			case "VARCHAR":
+			case "VARCHAR", "JSON":
 				pMap[param.Name] = p
 			case "BOOLEAN":
 				pMap[param.Name] = p == "true"
@@ -58,7 +58,7 @@ func buildNamedParams(paramsType []*schema.Column, paramsVal []interface{}) ([]*
 					return nil, err
 				}
 				pMap[param.Name] = i
-			case "VARCHAR":
+			case "VARCHAR", "JSON":
 				pMap[param.Name] = string(p)
 			case "BOOLEAN":
 				v := false
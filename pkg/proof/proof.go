@@ -0,0 +1,316 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package proof defines a stable, versioned encoding of inclusion and
+// consistency proofs so they can be archived and verified later without
+// linking the immudb client.
+package proof
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/codenotary/immudb/embedded/htree"
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// Version is the encoding version produced by this package
+const Version = 1
+
+var ErrIllegalArguments = errors.New("illegal arguments")
+var ErrUnsupportedVersion = errors.New("unsupported proof version")
+var ErrInvalidDigest = errors.New("invalid digest")
+var ErrProofVerificationFailed = errors.New("proof verification failed")
+var ErrUntrustedState = errors.New("proof does not match trusted state")
+
+// Digest is a sha256 digest encoded as a hex string
+type Digest [sha256.Size]byte
+
+func (d Digest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(d[:]))
+}
+
+func (d *Digest) UnmarshalJSON(data []byte) error {
+	var s string
+
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != sha256.Size {
+		return ErrInvalidDigest
+	}
+
+	copy(d[:], b)
+
+	return nil
+}
+
+// Proof holds a dual proof between two transactions and, optionally, the
+// inclusion proof of an entry within one of them
+type Proof struct {
+	Version   int             `json:"version"`
+	Entry     *Entry          `json:"entry,omitempty"`
+	Inclusion *InclusionProof `json:"inclusion,omitempty"`
+	Dual      *DualProof      `json:"dual"`
+}
+
+// Entry is a key-value pair as it was written into transaction Tx,
+// i.e. including any prefix or metadata encoding applied by the database
+type Entry struct {
+	Tx    uint64 `json:"tx"`
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type InclusionProof struct {
+	Leaf  int      `json:"leaf"`
+	Width int      `json:"width"`
+	Terms []Digest `json:"terms"`
+}
+
+type TxMetadata struct {
+	ID       uint64 `json:"id"`
+	PrevAlh  Digest `json:"prevAlh"`
+	Ts       int64  `json:"ts"`
+	NEntries int    `json:"nentries"`
+	Eh       Digest `json:"eh"`
+	BlTxID   uint64 `json:"blTxId"`
+	BlRoot   Digest `json:"blRoot"`
+}
+
+type LinearProof struct {
+	SourceTxID uint64   `json:"sourceTxId"`
+	TargetTxID uint64   `json:"targetTxId"`
+	Terms      []Digest `json:"terms"`
+}
+
+type DualProof struct {
+	SourceTxMetadata   *TxMetadata  `json:"sourceTxMetadata"`
+	TargetTxMetadata   *TxMetadata  `json:"targetTxMetadata"`
+	InclusionProof     []Digest     `json:"inclusionProof"`
+	ConsistencyProof   []Digest     `json:"consistencyProof"`
+	TargetBlTxAlh      Digest       `json:"targetBlTxAlh"`
+	LastInclusionProof []Digest     `json:"lastInclusionProof"`
+	LinearProof        *LinearProof `json:"linearProof"`
+}
+
+// New builds a portable proof. kv and inclusionProof may be nil when only
+// the consistency between two transactions needs to be proven
+func New(tx uint64, kv *store.KV, inclusionProof *htree.InclusionProof, dualProof *store.DualProof) (*Proof, error) {
+	if dualProof == nil || dualProof.SourceTxMetadata == nil || dualProof.TargetTxMetadata == nil ||
+		(kv == nil) != (inclusionProof == nil) {
+		return nil, ErrIllegalArguments
+	}
+
+	p := &Proof{
+		Version: Version,
+		Dual:    dualProofTo(dualProof),
+	}
+
+	if kv != nil {
+		p.Entry = &Entry{Tx: tx, Key: kv.Key, Value: kv.Value}
+		p.Inclusion = &InclusionProof{
+			Leaf:  inclusionProof.Leaf,
+			Width: inclusionProof.Width,
+			Terms: digestsTo(inclusionProof.Terms),
+		}
+	}
+
+	return p, nil
+}
+
+// Marshal encodes the proof as JSON
+func (p *Proof) Marshal() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// Unmarshal decodes a JSON encoded proof
+func Unmarshal(data []byte) (*Proof, error) {
+	var p Proof
+
+	err := json.Unmarshal(data, &p)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Version != Version {
+		return nil, ErrUnsupportedVersion
+	}
+
+	return &p, nil
+}
+
+// Verify checks that the dual proof links its source and target
+// transactions and, if present, that the entry is included in its transaction
+func Verify(p *Proof) error {
+	if p == nil {
+		return ErrIllegalArguments
+	}
+
+	if p.Version != Version {
+		return ErrUnsupportedVersion
+	}
+
+	if p.Dual == nil || p.Dual.SourceTxMetadata == nil || p.Dual.TargetTxMetadata == nil ||
+		(p.Entry == nil) != (p.Inclusion == nil) {
+		return ErrIllegalArguments
+	}
+
+	dualProof := dualProofFrom(p.Dual)
+
+	verifies := store.VerifyDualProof(
+		dualProof,
+		dualProof.SourceTxMetadata.ID,
+		dualProof.TargetTxMetadata.ID,
+		dualProof.SourceTxMetadata.Alh(),
+		dualProof.TargetTxMetadata.Alh(),
+	)
+	if !verifies {
+		return ErrProofVerificationFailed
+	}
+
+	if p.Entry == nil {
+		return nil
+	}
+
+	var eh [sha256.Size]byte
+
+	switch p.Entry.Tx {
+	case dualProof.SourceTxMetadata.ID:
+		eh = dualProof.SourceTxMetadata.Eh
+	case dualProof.TargetTxMetadata.ID:
+		eh = dualProof.TargetTxMetadata.Eh
+	default:
+		return ErrProofVerificationFailed
+	}
+
+	inclusionProof := &htree.InclusionProof{
+		Leaf:  p.Inclusion.Leaf,
+		Width: p.Inclusion.Width,
+		Terms: digestsFrom(p.Inclusion.Terms),
+	}
+
+	verifies = store.VerifyInclusion(inclusionProof, &store.KV{Key: p.Entry.Key, Value: p.Entry.Value}, eh)
+	if !verifies {
+		return ErrProofVerificationFailed
+	}
+
+	return nil
+}
+
+// VerifyAgainst verifies the proof and checks that one of its ends matches
+// the trusted state given by txID and alh
+func VerifyAgainst(p *Proof, txID uint64, alh [sha256.Size]byte) error {
+	err := Verify(p)
+	if err != nil {
+		return err
+	}
+
+	for _, md := range []*TxMetadata{p.Dual.SourceTxMetadata, p.Dual.TargetTxMetadata} {
+		if md.ID == txID && txMetadataFrom(md).Alh() == alh {
+			return nil
+		}
+	}
+
+	return ErrUntrustedState
+}
+
+func dualProofTo(dualProof *store.DualProof) *DualProof {
+	dp := &DualProof{
+		SourceTxMetadata:   txMetadataTo(dualProof.SourceTxMetadata),
+		TargetTxMetadata:   txMetadataTo(dualProof.TargetTxMetadata),
+		InclusionProof:     digestsTo(dualProof.InclusionProof),
+		ConsistencyProof:   digestsTo(dualProof.ConsistencyProof),
+		TargetBlTxAlh:      dualProof.TargetBlTxAlh,
+		LastInclusionProof: digestsTo(dualProof.LastInclusionProof),
+	}
+
+	if dualProof.LinearProof != nil {
+		dp.LinearProof = &LinearProof{
+			SourceTxID: dualProof.LinearProof.SourceTxID,
+			TargetTxID: dualProof.LinearProof.TargetTxID,
+			Terms:      digestsTo(dualProof.LinearProof.Terms),
+		}
+	}
+
+	return dp
+}
+
+func dualProofFrom(dp *DualProof) *store.DualProof {
+	dualProof := &store.DualProof{
+		SourceTxMetadata:   txMetadataFrom(dp.SourceTxMetadata),
+		TargetTxMetadata:   txMetadataFrom(dp.TargetTxMetadata),
+		InclusionProof:     digestsFrom(dp.InclusionProof),
+		ConsistencyProof:   digestsFrom(dp.ConsistencyProof),
+		TargetBlTxAlh:      dp.TargetBlTxAlh,
+		LastInclusionProof: digestsFrom(dp.LastInclusionProof),
+	}
+
+	if dp.LinearProof != nil {
+		dualProof.LinearProof = &store.LinearProof{
+			SourceTxID: dp.LinearProof.SourceTxID,
+			TargetTxID: dp.LinearProof.TargetTxID,
+			Terms:      digestsFrom(dp.LinearProof.Terms),
+		}
+	}
+
+	return dualProof
+}
+
+func txMetadataTo(md *store.TxMetadata) *TxMetadata {
+	return &TxMetadata{
+		ID:       md.ID,
+		PrevAlh:  md.PrevAlh,
+		Ts:       md.Ts,
+		NEntries: md.NEntries,
+		Eh:       md.Eh,
+		BlTxID:   md.BlTxID,
+		BlRoot:   md.BlRoot,
+	}
+}
+
+func txMetadataFrom(md *TxMetadata) *store.TxMetadata {
+	return &store.TxMetadata{
+		ID:       md.ID,
+		PrevAlh:  md.PrevAlh,
+		Ts:       md.Ts,
+		NEntries: md.NEntries,
+		Eh:       md.Eh,
+		BlTxID:   md.BlTxID,
+		BlRoot:   md.BlRoot,
+	}
+}
+
+func digestsTo(terms [][sha256.Size]byte) []Digest {
+	digests := make([]Digest, len(terms))
+	for i, t := range terms {
+		digests[i] = t
+	}
+	return digests
+}
+
+func digestsFrom(digests []Digest) [][sha256.Size]byte {
+	terms := make([][sha256.Size]byte, len(digests))
+	for i, d := range digests {
+		terms[i] = d
+	}
+	return terms
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proof
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProof(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proof")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st, err := store.Open(dir, store.DefaultOptions())
+	require.NoError(t, err)
+	defer st.Close()
+
+	for i := 0; i < 10; i++ {
+		_, err = st.Commit([]*store.KV{
+			{Key: []byte(fmt.Sprintf("key%d", i)), Value: []byte(fmt.Sprintf("value%d", i))},
+			{Key: []byte(fmt.Sprintf("other%d", i)), Value: []byte("other")},
+		}, true)
+		require.NoError(t, err)
+	}
+
+	sourceTx := st.NewTx()
+	targetTx := st.NewTx()
+
+	err = st.ReadTx(3, sourceTx)
+	require.NoError(t, err)
+
+	err = st.ReadTx(10, targetTx)
+	require.NoError(t, err)
+
+	dualProof, err := st.DualProof(sourceTx, targetTx)
+	require.NoError(t, err)
+
+	kv := &store.KV{Key: []byte("key2"), Value: []byte("value2")}
+
+	inclusionProof, err := sourceTx.Proof(kv.Key)
+	require.NoError(t, err)
+
+	_, err = New(3, kv, nil, dualProof)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = New(3, kv, inclusionProof, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	p, err := New(3, kv, inclusionProof, dualProof)
+	require.NoError(t, err)
+
+	data, err := p.Marshal()
+	require.NoError(t, err)
+
+	decoded, err := Unmarshal(data)
+	require.NoError(t, err)
+	require.Equal(t, p, decoded)
+
+	err = Verify(decoded)
+	require.NoError(t, err)
+
+	err = VerifyAgainst(decoded, 10, targetTx.Alh)
+	require.NoError(t, err)
+
+	err = VerifyAgainst(decoded, 3, sourceTx.Alh)
+	require.NoError(t, err)
+
+	err = VerifyAgainst(decoded, 10, sourceTx.Alh)
+	require.Equal(t, ErrUntrustedState, err)
+
+	t.Run("consistency only", func(t *testing.T) {
+		p, err := New(0, nil, nil, dualProof)
+		require.NoError(t, err)
+
+		data, err := p.Marshal()
+		require.NoError(t, err)
+
+		decoded, err := Unmarshal(data)
+		require.NoError(t, err)
+
+		err = VerifyAgainst(decoded, 10, targetTx.Alh)
+		require.NoError(t, err)
+	})
+
+	t.Run("tampered entry", func(t *testing.T) {
+		decoded, err := Unmarshal(data)
+		require.NoError(t, err)
+
+		decoded.Entry.Value = []byte("tampered")
+
+		err = Verify(decoded)
+		require.Equal(t, ErrProofVerificationFailed, err)
+	})
+
+	t.Run("entry in unrelated tx", func(t *testing.T) {
+		decoded, err := Unmarshal(data)
+		require.NoError(t, err)
+
+		decoded.Entry.Tx = 5
+
+		err = Verify(decoded)
+		require.Equal(t, ErrProofVerificationFailed, err)
+	})
+
+	t.Run("tampered tx metadata", func(t *testing.T) {
+		decoded, err := Unmarshal(data)
+		require.NoError(t, err)
+
+		decoded.Dual.TargetTxMetadata.Ts++
+
+		err = Verify(decoded)
+		require.Equal(t, ErrProofVerificationFailed, err)
+	})
+
+	t.Run("invalid encodings", func(t *testing.T) {
+		_, err := Unmarshal([]byte(`{"version":2}`))
+		require.Equal(t, ErrUnsupportedVersion, err)
+
+		_, err = Unmarshal([]byte(`{"version":1,"dual":{"targetBlTxAlh":"00"}}`))
+		require.Equal(t, ErrInvalidDigest, err)
+
+		_, err = Unmarshal([]byte(`{`))
+		require.Error(t, err)
+
+		err = Verify(nil)
+		require.Equal(t, ErrIllegalArguments, err)
+
+		err = Verify(&Proof{Version: Version})
+		require.Equal(t, ErrIllegalArguments, err)
+
+		err = Verify(&Proof{})
+		require.Equal(t, ErrUnsupportedVersion, err)
+	})
+}
@@ -0,0 +1,430 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/backup"
+	"github.com/golang/protobuf/ptypes/empty"
+)
+
+// scheduledBackupsDir is the directory, inside the backup storage, where scheduled backups are written
+const scheduledBackupsDir = "scheduled"
+
+const scheduledBackupTimeFormat = "20060102T150405Z"
+
+// BackupSchedule triggers backups of a database following a cron-like expression
+type BackupSchedule struct {
+	Database    string
+	Incremental bool
+	Schedule    string
+}
+
+// ParseBackupSchedule parses schedules in the form "<database> <full|incremental> <cron expression>",
+// e.g. "defaultdb incremental 0 2 * * 1-6"
+func ParseBackupSchedule(s string) (*BackupSchedule, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("%w: backup schedule '%s'", ErrIllegalArguments, s)
+	}
+
+	bs := &BackupSchedule{
+		Database: fields[0],
+		Schedule: strings.Join(fields[2:], " "),
+	}
+
+	switch fields[1] {
+	case "full":
+	case "incremental":
+		bs.Incremental = true
+	default:
+		return nil, fmt.Errorf("%w: backup schedule '%s'", ErrIllegalArguments, s)
+	}
+
+	_, err := backup.ParseSchedule(bs.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("%w: backup schedule '%s'", err, s)
+	}
+
+	return bs, nil
+}
+
+func (bs *BackupSchedule) kind() string {
+	if bs.Incremental {
+		return "incremental"
+	}
+	return "full"
+}
+
+func (bs *BackupSchedule) String() string {
+	return fmt.Sprintf("%s %s %s", bs.Database, bs.kind(), bs.Schedule)
+}
+
+type backupJob struct {
+	schedule *BackupSchedule
+	cron     *backup.Schedule
+
+	nextRunAt     time.Time
+	lastRunAt     time.Time
+	lastSuccessAt time.Time
+	lastBackup    string
+	lastTxID      uint64
+	lastErr       error
+}
+
+type backupScheduler struct {
+	mutex    sync.Mutex
+	jobs     []*backupJob
+	cancel   context.CancelFunc
+	finished chan struct{}
+}
+
+func newBackupScheduler(schedules []*BackupSchedule) (*backupScheduler, error) {
+	bs := &backupScheduler{}
+
+	for _, schedule := range schedules {
+		cron, err := backup.ParseSchedule(schedule.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("%w: backup schedule '%s'", err, schedule)
+		}
+
+		bs.jobs = append(bs.jobs, &backupJob{schedule: schedule, cron: cron})
+	}
+
+	return bs, nil
+}
+
+// nextRun schedules the jobs not yet scheduled and returns the earliest activation
+func (bs *backupScheduler) nextRun(now time.Time) time.Time {
+	bs.mutex.Lock()
+	defer bs.mutex.Unlock()
+
+	var next time.Time
+
+	for _, job := range bs.jobs {
+		if job.nextRunAt.IsZero() {
+			job.nextRunAt = job.cron.Next(now)
+		}
+		if !job.nextRunAt.IsZero() && (next.IsZero() || job.nextRunAt.Before(next)) {
+			next = job.nextRunAt
+		}
+	}
+
+	return next
+}
+
+func (bs *backupScheduler) dueJobs(now time.Time) []*backupJob {
+	bs.mutex.Lock()
+	defer bs.mutex.Unlock()
+
+	var due []*backupJob
+
+	for _, job := range bs.jobs {
+		if !job.nextRunAt.IsZero() && !job.nextRunAt.After(now) {
+			due = append(due, job)
+		}
+	}
+
+	return due
+}
+
+// startBackupScheduler runs in background the configured backup schedules
+func (s *ImmuServer) startBackupScheduler() {
+	if s.backupScheduler == nil || len(s.backupScheduler.jobs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.backupScheduler.cancel = cancel
+	s.backupScheduler.finished = make(chan struct{})
+
+	s.Logger.Infof("Backup scheduler started with %d schedules", len(s.backupScheduler.jobs))
+
+	go s.backupSchedulerLoop(ctx, s.backupScheduler.finished)
+}
+
+// stopBackupScheduler interrupts the scheduler and waits for the backup in progress, if any
+func (s *ImmuServer) stopBackupScheduler() {
+	if s.backupScheduler == nil || s.backupScheduler.cancel == nil {
+		return
+	}
+
+	s.backupScheduler.cancel()
+	<-s.backupScheduler.finished
+
+	s.backupScheduler.cancel = nil
+	s.backupScheduler.finished = nil
+}
+
+func (s *ImmuServer) backupSchedulerLoop(ctx context.Context, finished chan<- struct{}) {
+	defer close(finished)
+
+	for {
+		next := s.backupScheduler.nextRun(time.Now())
+		if next.IsZero() {
+			<-ctx.Done()
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		for _, job := range s.backupScheduler.dueJobs(time.Now()) {
+			s.runBackupJob(ctx, job)
+		}
+	}
+}
+
+func (s *ImmuServer) runBackupJob(ctx context.Context, job *backupJob) {
+	db := job.schedule.Database
+	startedAt := time.Now()
+
+	name, txID, err := s.scheduledBackup(ctx, job.schedule, startedAt)
+
+	s.backupScheduler.mutex.Lock()
+	job.nextRunAt = job.cron.Next(time.Now())
+	job.lastRunAt = startedAt
+	job.lastErr = err
+	if err == nil && name != "" {
+		job.lastSuccessAt = startedAt
+		job.lastBackup = name
+		job.lastTxID = txID
+	}
+	s.backupScheduler.mutex.Unlock()
+
+	result := "success"
+
+	switch {
+	case err != nil:
+		result = "failure"
+		s.Logger.Errorf("Scheduled %s backup of database '%s' failed: %v", job.schedule.kind(), db, err)
+	case name == "":
+		result = "skipped"
+		s.Logger.Infof("Scheduled %s backup of database '%s' skipped, no new transactions", job.schedule.kind(), db)
+	default:
+		Metrics.LastBackupAtGauges.WithLabelValues(db).Set(float64(startedAt.Unix()))
+	}
+
+	Metrics.BackupsCounters.WithLabelValues(db, job.schedule.kind(), result).Inc()
+
+	if err != nil || name == "" || s.Options.BackupRetention <= 0 {
+		return
+	}
+
+	err = s.pruneScheduledBackups(ctx, db, s.Options.BackupRetention)
+	if err != nil {
+		s.Logger.Errorf("Pruning of scheduled backups of database '%s' failed: %v", db, err)
+	}
+}
+
+type scheduledBackup struct {
+	name    string
+	ts      string
+	fromTx  uint64
+	untilTx uint64
+}
+
+// scheduled backups are named after the time they were taken and the range of transactions they hold,
+// so the chain can be followed and pruned without reading them
+func scheduledBackupName(db string, ts time.Time, fromTx, untilTx uint64) string {
+	return fmt.Sprintf("%s/%s/%s-%d-%d.bak", scheduledBackupsDir, db, ts.UTC().Format(scheduledBackupTimeFormat), fromTx, untilTx)
+}
+
+func parseScheduledBackupName(name string) (*scheduledBackup, bool) {
+	fields := strings.Split(strings.TrimSuffix(path.Base(name), ".bak"), "-")
+	if len(fields) != 3 || !strings.HasSuffix(name, ".bak") {
+		return nil, false
+	}
+
+	fromTx, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil || fromTx == 0 {
+		return nil, false
+	}
+
+	untilTx, err := strconv.ParseUint(fields[2], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	return &scheduledBackup{name: name, ts: fields[0], fromTx: fromTx, untilTx: untilTx}, true
+}
+
+// listScheduledBackups returns the scheduled backups of a database, oldest first
+func (s *ImmuServer) listScheduledBackups(ctx context.Context, db string) ([]*scheduledBackup, error) {
+	names, err := s.backupStorage.List(ctx, path.Join(scheduledBackupsDir, db))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []*scheduledBackup
+
+	for _, name := range names {
+		b, ok := parseScheduledBackupName(name)
+		if ok {
+			backups = append(backups, b)
+		}
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].ts != backups[j].ts {
+			return backups[i].ts < backups[j].ts
+		}
+		return backups[i].fromTx < backups[j].fromTx
+	})
+
+	return backups, nil
+}
+
+// scheduledBackup takes a backup of the database, incremental backups follow the most recent scheduled backup
+// or fall back to a full one when there is none. No backup is taken when there are no new transactions
+func (s *ImmuServer) scheduledBackup(ctx context.Context, schedule *BackupSchedule, now time.Time) (name string, txID uint64, err error) {
+	db, err := s.dbList.GetByName(schedule.Database)
+	if err != nil {
+		return "", 0, err
+	}
+
+	state, err := db.CurrentState()
+	if err != nil {
+		return "", 0, err
+	}
+
+	var sinceTx uint64
+
+	if schedule.Incremental {
+		backups, err := s.listScheduledBackups(ctx, schedule.Database)
+		if err != nil {
+			return "", 0, err
+		}
+
+		if len(backups) > 0 && backups[len(backups)-1].untilTx <= state.TxId {
+			sinceTx = backups[len(backups)-1].untilTx
+
+			if sinceTx == state.TxId {
+				return "", 0, nil
+			}
+		}
+	}
+
+	name = scheduledBackupName(schedule.Database, now, sinceTx+1, state.TxId)
+
+	trailer, err := s.backupToStorage(ctx, db, name, sinceTx, state.TxId)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return name, trailer.TxId, nil
+}
+
+// pruneScheduledBackups keeps the most recent full backups of a database, along with the incremental ones following them
+func (s *ImmuServer) pruneScheduledBackups(ctx context.Context, db string, retention int) error {
+	backups, err := s.listScheduledBackups(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	var fulls []int
+
+	for i, b := range backups {
+		if b.fromTx == 1 {
+			fulls = append(fulls, i)
+		}
+	}
+
+	if len(fulls) <= retention {
+		return nil
+	}
+
+	for _, b := range backups[:fulls[len(fulls)-retention]] {
+		err = s.backupStorage.Delete(ctx, b.name)
+		if err != nil {
+			return err
+		}
+
+		s.Logger.Infof("Scheduled backup '%s' pruned", b.name)
+	}
+
+	return nil
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// BackupStatus returns the status of the scheduled backups
+func (s *ImmuServer) BackupStatus(ctx context.Context, _ *empty.Empty) (*schema.BackupStatusResponse, error) {
+	if !s.Options.GetAuth() {
+		return nil, ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin {
+		return nil, fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+
+	res := &schema.BackupStatusResponse{}
+
+	if s.backupScheduler == nil {
+		return res, nil
+	}
+
+	s.backupScheduler.mutex.Lock()
+	defer s.backupScheduler.mutex.Unlock()
+
+	for _, job := range s.backupScheduler.jobs {
+		status := &schema.BackupScheduleStatus{
+			DatabaseName:  job.schedule.Database,
+			Incremental:   job.schedule.Incremental,
+			Schedule:      job.schedule.Schedule,
+			NextRunAt:     unixOrZero(job.nextRunAt),
+			LastRunAt:     unixOrZero(job.lastRunAt),
+			LastSuccessAt: unixOrZero(job.lastSuccessAt),
+			LastBackup:    job.lastBackup,
+			LastTxId:      job.lastTxID,
+		}
+
+		if job.lastErr != nil {
+			status.LastError = job.lastErr.Error()
+		}
+
+		res.Schedules = append(res.Schedules, status)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/backup"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestParseBackupSchedule(t *testing.T) {
+	for _, s := range []string{"", "defaultdb", "defaultdb full", "defaultdb weekly 0 3 * * 0"} {
+		_, err := ParseBackupSchedule(s)
+		require.ErrorIs(t, err, ErrIllegalArguments, s)
+	}
+
+	_, err := ParseBackupSchedule("defaultdb full 0 3 * *")
+	require.ErrorIs(t, err, backup.ErrInvalidSchedule)
+
+	bs, err := ParseBackupSchedule("defaultdb incremental  0 2 * * 1-6")
+	require.NoError(t, err)
+	require.Equal(t, &BackupSchedule{Database: "defaultdb", Incremental: true, Schedule: "0 2 * * 1-6"}, bs)
+	require.Equal(t, "defaultdb incremental 0 2 * * 1-6", bs.String())
+
+	_, err = newBackupScheduler([]*BackupSchedule{{Database: "defaultdb", Schedule: "* *"}})
+	require.ErrorIs(t, err, backup.ErrInvalidSchedule)
+}
+
+func TestScheduledBackups(t *testing.T) {
+	full, err := ParseBackupSchedule("defaultdb full 0 3 * * 0")
+	require.NoError(t, err)
+
+	incremental, err := ParseBackupSchedule("defaultdb incremental 0 3 * * 1-6")
+	require.NoError(t, err)
+
+	serverOptions := DefaultOptions().
+		WithDir("data_scheduled_backups").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithBackupSchedules([]*BackupSchedule{full, incremental}).
+		WithBackupRetention(1)
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err = s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	db, err := s.dbList.GetByName(DefaultdbName)
+	require.NoError(t, err)
+
+	set := func(n int) {
+		for i := 0; i < n; i++ {
+			_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte{byte(i)}}}})
+			require.NoError(t, err)
+		}
+	}
+
+	ctx := context.Background()
+	now := time.Date(2021, time.March, 7, 3, 0, 0, 0, time.UTC)
+
+	// incremental backups fall back to a full one
+	set(3)
+	name, txID, err := s.scheduledBackup(ctx, incremental, now)
+	require.NoError(t, err)
+	require.Equal(t, "scheduled/defaultdb/20210307T030000Z-1-4.bak", name)
+	require.Equal(t, uint64(4), txID)
+
+	// no new transactions
+	name, _, err = s.scheduledBackup(ctx, incremental, now.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, name)
+
+	set(2)
+	name, txID, err = s.scheduledBackup(ctx, incremental, now.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, "scheduled/defaultdb/20210308T030000Z-5-6.bak", name)
+	require.Equal(t, uint64(6), txID)
+
+	// the chain can be restored
+	res, err := s.Restore(adminCtx, &schema.RestoreRequest{
+		DatabaseName: "restored",
+		Paths:        []string{"scheduled/defaultdb/20210307T030000Z-1-4.bak", "scheduled/defaultdb/20210308T030000Z-5-6.bak"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), res.TxId)
+
+	set(1)
+	name, _, err = s.scheduledBackup(ctx, full, now.Add(7*24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, "scheduled/defaultdb/20210314T030000Z-1-7.bak", name)
+
+	err = s.pruneScheduledBackups(ctx, DefaultdbName, 2)
+	require.NoError(t, err)
+
+	backups, err := s.listScheduledBackups(ctx, DefaultdbName)
+	require.NoError(t, err)
+	require.Len(t, backups, 3)
+
+	// older chains are pruned
+	set(1)
+	s.runBackupJob(ctx, s.backupScheduler.jobs[1])
+
+	backups, err = s.listScheduledBackups(ctx, DefaultdbName)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	require.Equal(t, "scheduled/defaultdb/20210314T030000Z-1-7.bak", backups[0].name)
+	require.Equal(t, uint64(8), backups[1].fromTx)
+	require.Equal(t, uint64(8), backups[1].untilTx)
+
+	_, err = s.BackupStatus(context.Background(), &empty.Empty{})
+	require.Error(t, err)
+
+	status, err := s.BackupStatus(adminCtx, &empty.Empty{})
+	require.NoError(t, err)
+	require.Len(t, status.Schedules, 2)
+	require.Equal(t, DefaultdbName, status.Schedules[1].DatabaseName)
+	require.True(t, status.Schedules[1].Incremental)
+	require.Equal(t, "0 3 * * 1-6", status.Schedules[1].Schedule)
+	require.Equal(t, backups[1].name, status.Schedules[1].LastBackup)
+	require.Equal(t, uint64(8), status.Schedules[1].LastTxId)
+	require.Empty(t, status.Schedules[1].LastError)
+	require.Equal(t, status.Schedules[1].LastRunAt, status.Schedules[1].LastSuccessAt)
+	require.Zero(t, status.Schedules[0].LastRunAt)
+
+	// failures are reported
+	missing, err := ParseBackupSchedule("missingdb full * * * * *")
+	require.NoError(t, err)
+
+	s.backupScheduler.jobs[0].schedule = missing
+	s.runBackupJob(ctx, s.backupScheduler.jobs[0])
+
+	status, err = s.BackupStatus(adminCtx, &empty.Empty{})
+	require.NoError(t, err)
+	require.NotEmpty(t, status.Schedules[0].LastError)
+	require.Zero(t, status.Schedules[0].LastSuccessAt)
+	require.NotZero(t, status.Schedules[0].NextRunAt)
+
+	s.startBackupScheduler()
+	s.stopBackupScheduler()
+
+	s.CloseDatabases()
+}
@@ -0,0 +1,992 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// clusterReplicaID identifies the Raft group among the replicas confirming the transactions of a database led by the node
+const clusterReplicaID = "cluster"
+
+const (
+	clusterApplyTimeout     = 10 * time.Second
+	clusterTransportTimeout = 10 * time.Second
+	clusterRetryDelay       = time.Second
+)
+
+// ParseClusterPeer parses peers in the form "<node id>=<raft address>,<address>",
+// e.g. "node2=10.0.0.2:3325,10.0.0.2:3322"
+func ParseClusterPeer(s string) (*ClusterPeer, error) {
+	idAndAddresses := strings.SplitN(s, "=", 2)
+	if len(idAndAddresses) != 2 {
+		return nil, fmt.Errorf("%w: cluster peer '%s'", ErrIllegalArguments, s)
+	}
+
+	addresses := strings.Split(idAndAddresses[1], ",")
+	if len(addresses) != 2 {
+		return nil, fmt.Errorf("%w: cluster peer '%s'", ErrIllegalArguments, s)
+	}
+
+	peer := &ClusterPeer{
+		NodeID:      strings.TrimSpace(idAndAddresses[0]),
+		RaftAddress: strings.TrimSpace(addresses[0]),
+		Address:     strings.TrimSpace(addresses[1]),
+	}
+
+	if peer.NodeID == "" || peer.RaftAddress == "" || peer.Address == "" {
+		return nil, fmt.Errorf("%w: cluster peer '%s'", ErrIllegalArguments, s)
+	}
+
+	return peer, nil
+}
+
+// validateClusterOptions checks the node and its peers form a cluster able to survive the loss of one of them
+func validateClusterOptions(opts *ClusterOptions) error {
+	if opts.NodeID == "" || opts.RaftAddress == "" {
+		return fmt.Errorf("%w: a node id and a raft address are required", ErrInvalidClusterOptions)
+	}
+
+	if len(opts.Peers) < 2 {
+		return fmt.Errorf("%w: a cluster is made of at least three nodes", ErrInvalidClusterOptions)
+	}
+
+	ids := map[string]bool{opts.NodeID: true}
+	raftAddresses := map[string]bool{opts.RaftAddress: true}
+
+	for _, peer := range opts.Peers {
+		if peer.NodeID == "" || peer.RaftAddress == "" || peer.Address == "" {
+			return fmt.Errorf("%w: peers require a node id, a raft address and an address", ErrInvalidClusterOptions)
+		}
+
+		if ids[peer.NodeID] || raftAddresses[peer.RaftAddress] {
+			return fmt.Errorf("%w: duplicated peer '%s'", ErrInvalidClusterOptions, peer.NodeID)
+		}
+
+		ids[peer.NodeID] = true
+		raftAddresses[peer.RaftAddress] = true
+	}
+
+	return nil
+}
+
+// cluster runs a Raft group for each database of the node, along with the databases of the same name of its peers.
+// The leader of a group commits the writes and ships the resulting transactions through the Raft log, a write is
+// acknowledged once a majority of the nodes holds its transaction. The other nodes keep their database as a replica
+type cluster struct {
+	opts *ClusterOptions
+	// address is where clients are redirected to when the node leads a database
+	address string
+	dir     string
+	mux     *clusterMux
+	logger  logger.Logger
+	// audit records the changes of role, catch-ups and divergences of the nodes, if set
+	audit func(database, kind, actor string, txID uint64, details string)
+
+	mutex sync.RWMutex
+	nodes map[string]*clusterNode
+}
+
+func newCluster(opts *ClusterOptions, address, dir string, tlsConfig *tls.Config, log logger.Logger) (*cluster, error) {
+	err := validateClusterOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// catch-ups ship whole transactions, connections must be authenticated before serving them
+	if opts.Secret == "" && (tlsConfig == nil || len(tlsConfig.Certificates) == 0 || tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert) {
+		return nil, fmt.Errorf("%w: a cluster secret or the replication certificates are required to authenticate the nodes", ErrInvalidClusterOptions)
+	}
+
+	mux, err := newClusterMux(opts.RaftAddress, opts.Secret, tlsConfig, log)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &cluster{
+		opts:    opts,
+		address: address,
+		dir:     dir,
+		mux:     mux,
+		logger:  log,
+		nodes:   make(map[string]*clusterNode),
+	}
+
+	mux.catchUp = c.serveCatchUp
+
+	return c, nil
+}
+
+func (c *cluster) configuration() raft.Configuration {
+	servers := []raft.Server{{
+		Suffrage: raft.Voter,
+		ID:       raft.ServerID(c.opts.NodeID),
+		Address:  raft.ServerAddress(c.opts.RaftAddress),
+	}}
+
+	for _, peer := range c.opts.Peers {
+		servers = append(servers, raft.Server{
+			Suffrage: raft.Voter,
+			ID:       raft.ServerID(peer.NodeID),
+			Address:  raft.ServerAddress(peer.RaftAddress),
+		})
+	}
+
+	return raft.Configuration{Servers: servers}
+}
+
+// member returns the node serving the consensus traffic at the given address
+func (c *cluster) member(raftAddress string) (*schema.ClusterMember, bool) {
+	if raftAddress == c.opts.RaftAddress {
+		return &schema.ClusterMember{NodeId: c.opts.NodeID, RaftAddress: c.opts.RaftAddress, Address: c.address}, true
+	}
+
+	for _, peer := range c.opts.Peers {
+		if peer.RaftAddress == raftAddress {
+			return &schema.ClusterMember{NodeId: peer.NodeID, RaftAddress: peer.RaftAddress, Address: peer.Address}, true
+		}
+	}
+
+	return nil, false
+}
+
+// join makes the database part of its Raft group, the group is bootstrapped on its first join
+func (c *cluster) join(db database.DB) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	name := db.GetName()
+
+	if _, ok := c.nodes[name]; ok {
+		return nil
+	}
+
+	dir := filepath.Join(c.dir, name)
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	logOutput := &raftLogWriter{logger: c.logger}
+
+	logStore, err := raftboltdb.NewBoltStore(filepath.Join(dir, "raft.db"))
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := raft.NewFileSnapshotStore(dir, 2, logOutput)
+	if err != nil {
+		logStore.Close()
+		return err
+	}
+
+	n := &clusterNode{
+		cluster:  c,
+		db:       db,
+		rOpts:    *db.GetOptions().GetReplicationOptions(),
+		logStore: logStore,
+		leaderCh: make(chan bool, 16),
+		done:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	n.fsm = &clusterFSM{node: n}
+	n.transport = raft.NewNetworkTransport(c.mux.register(name), 3, clusterTransportTimeout, logOutput)
+
+	config := raft.DefaultConfig()
+	config.LocalID = raft.ServerID(c.opts.NodeID)
+	config.NotifyCh = n.leaderCh
+	config.LogOutput = logOutput
+	config.LogLevel = "WARN"
+
+	hasState, err := raft.HasExistingState(logStore, logStore, snapshots)
+	if err == nil && !hasState {
+		err = raft.BootstrapCluster(config, logStore, logStore, snapshots, n.transport, c.configuration())
+	}
+	if err != nil {
+		n.transport.Close()
+		logStore.Close()
+		return err
+	}
+
+	r, err := raft.NewRaft(config, n.fsm, logStore, logStore, snapshots, n.transport)
+	if err != nil {
+		n.transport.Close()
+		logStore.Close()
+		return err
+	}
+
+	n.setRaft(r)
+
+	go n.monitor()
+
+	c.nodes[name] = n
+
+	c.logger.Infof("Database '%s' joined the cluster as node '%s'", name, c.opts.NodeID)
+
+	return nil
+}
+
+// record adds an entry to the replication audit trail of the database on behalf of the node
+func (c *cluster) record(dbName, kind string, txID uint64, details string) {
+	if c.audit == nil {
+		return
+	}
+
+	c.audit(dbName, kind, fmt.Sprintf("cluster node '%s'", c.opts.NodeID), txID, details)
+}
+
+// leave stops the participation of the database in its Raft group
+func (c *cluster) leave(dbName string) {
+	c.mutex.Lock()
+	n, ok := c.nodes[dbName]
+	delete(c.nodes, dbName)
+	c.mutex.Unlock()
+
+	if ok {
+		n.shutdown()
+	}
+}
+
+func (c *cluster) close() {
+	c.mutex.RLock()
+	names := make([]string, 0, len(c.nodes))
+	for name := range c.nodes {
+		names = append(names, name)
+	}
+	c.mutex.RUnlock()
+
+	for _, name := range names {
+		c.leave(name)
+	}
+
+	c.mux.Close()
+}
+
+func (c *cluster) node(dbName string) (*clusterNode, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	n, ok := c.nodes[dbName]
+	return n, ok
+}
+
+// leaderOf returns the leader of the database, nil when there is none at the moment.
+// It returns false when the database is not part of the cluster
+func (c *cluster) leaderOf(dbName string) (*schema.ClusterLeader, bool) {
+	n, ok := c.node(dbName)
+	if !ok {
+		return nil, false
+	}
+
+	member, ok := c.member(n.leader())
+	if !ok {
+		return nil, true
+	}
+
+	return &schema.ClusterLeader{Database: dbName, NodeId: member.NodeId, Address: member.Address}, true
+}
+
+// updateReplicationOptions keeps the role of the database in the cluster when its replication options get updated
+func (c *cluster) updateReplicationOptions(db database.DB, rOpts *database.ReplicationOptions) bool {
+	n, ok := c.node(db.GetName())
+	if !ok {
+		return false
+	}
+
+	n.updateReplicationOptions(rOpts)
+
+	return true
+}
+
+// isMember tells whether the connection was opened by a member of the cluster. Members prove it through the cluster
+// secret, checked as soon as the connection is accepted, or through their replication certificates
+func (c *cluster) isMember(conn net.Conn) bool {
+	if c.opts.Secret != "" {
+		return true
+	}
+
+	tlsConn, ok := conn.(*tls.Conn)
+
+	return ok && len(tlsConn.ConnectionState().VerifiedChains) > 0
+}
+
+// serveCatchUp sends to a lagging peer the transactions it asked for
+func (c *cluster) serveCatchUp(dbName string, conn net.Conn) {
+	defer conn.Close()
+
+	n, ok := c.node(dbName)
+	if !ok {
+		return
+	}
+
+	if !c.isMember(conn) {
+		c.logger.Warningf("Refused to send txs of database '%s' to %s, not a member of the cluster", dbName, conn.RemoteAddr())
+		return
+	}
+
+	conn.SetDeadline(time.Now().Add(clusterTransportTimeout))
+
+	var req [16]byte
+
+	_, err := io.ReadFull(conn, req[:])
+	if err != nil {
+		return
+	}
+
+	fromTxID := binary.BigEndian.Uint64(req[:])
+	toTxID := binary.BigEndian.Uint64(req[8:])
+
+	for txID := fromTxID; txID <= toTxID; txID++ {
+		etx, err := n.db.ExportTxByID(&schema.TxRequest{Tx: txID})
+		if err != nil {
+			c.logger.Warningf("Unable to send tx %d of database '%s' to a lagging peer: %v", txID, dbName, err)
+			return
+		}
+
+		conn.SetDeadline(time.Now().Add(clusterTransportTimeout))
+
+		err = writeCatchUpTx(conn, etx)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// clusterNode is the participation of a database in its Raft group
+type clusterNode struct {
+	cluster *cluster
+	db      database.DB
+
+	mutex sync.Mutex
+	raft  *raft.Raft
+	// rOpts are the replication options of the database, the role in the cluster determines if it is a replica
+	rOpts   database.ReplicationOptions
+	leading bool
+
+	transport *raft.NetworkTransport
+	logStore  *raftboltdb.BoltStore
+	fsm       *clusterFSM
+
+	leaderCh chan bool
+	done     chan struct{}
+	finished chan struct{}
+
+	shipperDone     chan struct{}
+	shipperFinished chan struct{}
+}
+
+func (n *clusterNode) setRaft(r *raft.Raft) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.raft = r
+}
+
+// leader returns the raft address of the leader of the group, empty when unknown
+func (n *clusterNode) leader() string {
+	n.mutex.Lock()
+	r := n.raft
+	n.mutex.Unlock()
+
+	if r == nil {
+		return ""
+	}
+
+	return string(r.Leader())
+}
+
+func (n *clusterNode) shutdown() {
+	err := n.raft.Shutdown().Error()
+	if err != nil {
+		n.cluster.logger.Warningf("Error stopping the Raft group of database '%s': %v", n.db.GetName(), err)
+	}
+
+	close(n.done)
+	<-n.finished
+
+	n.transport.Close()
+	n.logStore.Close()
+}
+
+// monitor follows the leadership changes of the node, switching the database between leader and replica
+func (n *clusterNode) monitor() {
+	defer close(n.finished)
+
+	for {
+		select {
+		case leading := <-n.leaderCh:
+			if leading {
+				n.lead()
+			} else {
+				n.follow()
+			}
+		case <-n.done:
+			n.stopShipper()
+			return
+		}
+	}
+}
+
+func (n *clusterNode) lead() {
+	// every entry committed by the previous leaders must be applied before accepting writes
+	err := n.raft.Barrier(clusterApplyTimeout).Error()
+	if err != nil {
+		n.cluster.logger.Warningf("Node '%s' unable to take the lead of database '%s': %v", n.cluster.opts.NodeID, n.db.GetName(), err)
+		return
+	}
+
+	appliedTxID, _, diverged := n.fsm.state()
+
+	state, err := n.db.CurrentState()
+	if err != nil || diverged || state.TxId < appliedTxID {
+		n.cluster.logger.Errorf("Node '%s' can not lead database '%s' as it is not in sync with the cluster, transferring the leadership", n.cluster.opts.NodeID, n.db.GetName())
+		n.raft.LeadershipTransfer()
+		return
+	}
+
+	n.mutex.Lock()
+	n.leading = true
+	n.applyRole()
+	n.mutex.Unlock()
+
+	n.startShipper(appliedTxID)
+
+	n.cluster.logger.Infof("Node '%s' is the leader of database '%s'", n.cluster.opts.NodeID, n.db.GetName())
+
+	n.cluster.record(n.db.GetName(), ReplicationAuditPromoted, appliedTxID, "took the lead of the cluster")
+}
+
+func (n *clusterNode) follow() {
+	n.stopShipper()
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if !n.leading {
+		return
+	}
+
+	n.leading = false
+	n.applyRole()
+
+	n.cluster.logger.Infof("Node '%s' is no longer the leader of database '%s'", n.cluster.opts.NodeID, n.db.GetName())
+
+	n.cluster.record(n.db.GetName(), ReplicationAuditDemoted, 0, "lost the lead of the cluster")
+}
+
+func (n *clusterNode) updateReplicationOptions(rOpts *database.ReplicationOptions) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.rOpts = *rOpts
+	n.applyRole()
+}
+
+// applyRole must be called holding the node mutex. The leader acknowledges a write once its transaction
+// is committed by the Raft group, which is confirmed by the shipper
+func (n *clusterNode) applyRole() {
+	rOpts := n.rOpts
+	rOpts.Replica = !n.leading
+
+	if n.leading {
+		rOpts.SyncAcks = 1
+		rOpts.SyncFallback = database.SyncFallbackFail
+	}
+
+	n.db.UpdateReplicationOptions(&rOpts)
+}
+
+func (n *clusterNode) startShipper(fromTxID uint64) {
+	n.stopShipper()
+
+	n.shipperDone = make(chan struct{})
+	n.shipperFinished = make(chan struct{})
+
+	go n.ship(fromTxID, n.shipperDone, n.shipperFinished)
+}
+
+func (n *clusterNode) stopShipper() {
+	if n.shipperDone == nil {
+		return
+	}
+
+	close(n.shipperDone)
+	<-n.shipperFinished
+
+	n.shipperDone = nil
+	n.shipperFinished = nil
+}
+
+// ship appends, in order, the transactions committed by the leader to the Raft log
+func (n *clusterNode) ship(txID uint64, done <-chan struct{}, finished chan<- struct{}) {
+	defer close(finished)
+
+	for {
+		err := n.db.WaitForTx(txID+1, done)
+		if err != nil {
+			return
+		}
+
+		etx, err := n.db.ExportTxByID(&schema.TxRequest{Tx: txID + 1})
+		if err == nil {
+			err = n.raft.Apply(etx, clusterApplyTimeout).Error()
+		}
+		if err == raft.ErrNotLeader || err == raft.ErrLeadershipLost || err == raft.ErrRaftShutdown {
+			return
+		}
+		if err != nil {
+			n.cluster.logger.Warningf("Unable to replicate tx %d of database '%s' through the cluster: %v", txID+1, n.db.GetName(), err)
+
+			select {
+			case <-done:
+				return
+			case <-time.After(clusterRetryDelay):
+				continue
+			}
+		}
+
+		txID++
+
+		md, _, err := store.ExportedTxEntries(etx)
+		if err != nil {
+			n.cluster.logger.Errorf("Unable to confirm tx %d of database '%s': %v", txID, n.db.GetName(), err)
+			continue
+		}
+
+		alh := md.Alh()
+
+		err = n.db.ConfirmReplication(clusterReplicaID, txID, alh[:])
+		if err != nil {
+			n.cluster.logger.Errorf("Unable to confirm tx %d of database '%s': %v", txID, n.db.GetName(), err)
+		}
+	}
+}
+
+// catchUp fetches from the leader the transactions the database is missing
+func (n *clusterNode) catchUp(fromTxID, toTxID uint64) error {
+	leader := n.leader()
+	if leader == "" || leader == n.cluster.opts.RaftAddress {
+		return ErrNoClusterLeader
+	}
+
+	conn, err := n.cluster.mux.dial(leader, clusterCatchUpConn, n.db.GetName(), clusterTransportTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var req [16]byte
+	binary.BigEndian.PutUint64(req[:], fromTxID)
+	binary.BigEndian.PutUint64(req[8:], toTxID)
+
+	_, err = conn.Write(req[:])
+	if err != nil {
+		return err
+	}
+
+	for txID := fromTxID; txID <= toTxID; txID++ {
+		conn.SetReadDeadline(time.Now().Add(clusterTransportTimeout))
+
+		etx, err := readCatchUpTx(conn)
+		if err != nil {
+			return fmt.Errorf("unable to fetch tx %d from the cluster leader: %w", txID, err)
+		}
+
+		_, err = n.db.ReplicateTx(etx)
+		if err != nil {
+			return err
+		}
+	}
+
+	n.cluster.logger.Infof("Database '%s' caught up with its cluster leader up to tx %d", n.db.GetName(), toTxID)
+
+	n.cluster.record(n.db.GetName(), ReplicationAuditResynced, toTxID,
+		fmt.Sprintf("fetched txs %d to %d from the cluster leader at %s", fromTxID, toTxID, leader))
+
+	return nil
+}
+
+func (n *clusterNode) status() *schema.ClusterDatabaseStatus {
+	stats := n.raft.Stats()
+
+	appliedTxID, _, diverged := n.fsm.state()
+
+	st := &schema.ClusterDatabaseStatus{
+		Database:    n.db.GetName(),
+		Role:        strings.ToLower(n.raft.State().String()),
+		AppliedTxId: appliedTxID,
+		Diverged:    diverged,
+	}
+
+	st.Term, _ = strconv.ParseUint(stats["term"], 10, 64)
+	st.CommitIndex, _ = strconv.ParseUint(stats["commit_index"], 10, 64)
+	st.AppliedIndex, _ = strconv.ParseUint(stats["applied_index"], 10, 64)
+
+	if member, ok := n.cluster.member(n.leader()); ok {
+		st.Leader = &schema.ClusterLeader{Database: st.Database, NodeId: member.NodeId, Address: member.Address}
+	}
+
+	cf := n.raft.GetConfiguration()
+	if cf.Error() == nil {
+		for _, server := range cf.Configuration().Servers {
+			member, ok := n.cluster.member(string(server.Address))
+			if !ok {
+				member = &schema.ClusterMember{NodeId: string(server.ID), RaftAddress: string(server.Address)}
+			}
+
+			st.Members = append(st.Members, member)
+		}
+	}
+
+	return st
+}
+
+// clusterFSM applies to the database the transactions committed by the Raft group. Transactions already held by
+// the database, as the ones committed by the leader itself, are checked to be the same as the committed ones
+type clusterFSM struct {
+	node *clusterNode
+
+	mutex       sync.Mutex
+	appliedTxID uint64
+	appliedAlh  [sha256.Size]byte
+	diverged    bool
+}
+
+func (f *clusterFSM) state() (appliedTxID uint64, appliedAlh [sha256.Size]byte, diverged bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.appliedTxID, f.appliedAlh, f.diverged
+}
+
+func (f *clusterFSM) Apply(l *raft.Log) interface{} {
+	if l.Type != raft.LogCommand {
+		return nil
+	}
+
+	err := f.apply(l.Data)
+	if err != nil {
+		f.node.cluster.logger.Warningf("Unable to apply the cluster log entry %d to database '%s': %v", l.Index, f.node.db.GetName(), err)
+	}
+
+	return err
+}
+
+func (f *clusterFSM) apply(etx []byte) error {
+	md, _, err := store.ExportedTxEntries(etx)
+	if err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.diverged {
+		return ErrClusterDiverged
+	}
+
+	state, err := f.node.db.CurrentState()
+	if err != nil {
+		return err
+	}
+
+	if md.ID <= state.TxId {
+		err = f.verify(md.ID, md.Alh())
+		if err != nil {
+			return err
+		}
+	} else {
+		if md.ID > state.TxId+1 {
+			err = f.node.catchUp(state.TxId+1, md.ID-1)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = f.node.db.ReplicateTx(etx)
+		if err != nil {
+			return err
+		}
+	}
+
+	f.appliedTxID = md.ID
+	f.appliedAlh = md.Alh()
+
+	return nil
+}
+
+// verify checks the transaction held by the database is the committed one, it must be called holding the mutex
+func (f *clusterFSM) verify(txID uint64, alh [sha256.Size]byte) error {
+	tx, err := f.node.db.TxByID(&schema.TxRequest{Tx: txID})
+	if err != nil {
+		return err
+	}
+
+	if schema.TxMetadataFrom(tx.Metadata).Alh() != alh {
+		f.diverged = true
+		f.node.cluster.logger.Errorf("Tx %d of database '%s' diverges from the one committed by the cluster, the node stops applying the cluster log", txID, f.node.db.GetName())
+		f.node.cluster.record(f.node.db.GetName(), ReplicationAuditDiverged, txID, "tx diverges from the one committed by the cluster")
+		return ErrClusterDiverged
+	}
+
+	return nil
+}
+
+// Snapshot only records the last applied transaction, as the transactions are held by the database itself
+func (f *clusterFSM) Snapshot() (raft.FSMSnapshot, error) {
+	txID, alh, _ := f.state()
+	return &clusterSnapshot{txID: txID, alh: alh}, nil
+}
+
+// Restore brings the database up to the snapshot, fetching from the leader the transactions it misses.
+// When no leader is known yet, the transactions are fetched along with the next applied entry
+func (f *clusterFSM) Restore(rc io.ReadCloser) error {
+	defer rc.Close()
+
+	var b [8 + sha256.Size]byte
+
+	_, err := io.ReadFull(rc, b[:])
+	if err != nil {
+		return err
+	}
+
+	txID := binary.BigEndian.Uint64(b[:])
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	state, err := f.node.db.CurrentState()
+	if err != nil {
+		return err
+	}
+
+	if state.TxId < txID {
+		err = f.node.catchUp(state.TxId+1, txID)
+		if err != nil {
+			f.node.cluster.logger.Warningf("Database '%s' lags behind the cluster snapshot at tx %d: %v", f.node.db.GetName(), txID, err)
+		} else {
+			state.TxId = txID
+		}
+	}
+
+	f.appliedTxID = txID
+	copy(f.appliedAlh[:], b[8:])
+
+	if txID > 0 && state.TxId >= txID {
+		return f.verify(txID, f.appliedAlh)
+	}
+
+	return nil
+}
+
+type clusterSnapshot struct {
+	txID uint64
+	alh  [sha256.Size]byte
+}
+
+func (s *clusterSnapshot) Persist(sink raft.SnapshotSink) error {
+	var b [8 + sha256.Size]byte
+	binary.BigEndian.PutUint64(b[:], s.txID)
+	copy(b[8:], s.alh[:])
+
+	_, err := sink.Write(b[:])
+	if err != nil {
+		sink.Cancel()
+		return err
+	}
+
+	return sink.Close()
+}
+
+func (s *clusterSnapshot) Release() {
+}
+
+// raftLogWriter forwards the logs of the Raft library to the server logger
+type raftLogWriter struct {
+	logger logger.Logger
+}
+
+func (w *raftLogWriter) Write(p []byte) (int, error) {
+	w.logger.Infof("%s", strings.TrimSpace(string(p)))
+	return len(p), nil
+}
+
+// startCluster makes the databases of the server join their Raft groups
+func (s *ImmuServer) startCluster() error {
+	if s.Options.ClusterOptions == nil {
+		return nil
+	}
+
+	address := s.Options.advertisedAddress()
+	if s.Options.ClusterOptions.Address != "" {
+		address = s.Options.ClusterOptions.Address
+	}
+
+	c, err := newCluster(s.Options.ClusterOptions, address, s.OS.Join(s.Options.Dir, clusterDirName), s.Options.ReplicationTLSConfig, s.Logger)
+	if err != nil {
+		return err
+	}
+
+	c.audit = s.auditReplication
+
+	for i := 0; i < s.dbList.Length(); i++ {
+		db := s.dbList.GetByIndex(int64(i))
+		if db == nil {
+			continue
+		}
+
+		err = c.join(db)
+		if err != nil {
+			c.close()
+			return fmt.Errorf("database '%s' unable to join the cluster: %w", db.GetName(), err)
+		}
+	}
+
+	s.cluster = c
+
+	return nil
+}
+
+func (s *ImmuServer) stopCluster() {
+	if s.cluster == nil {
+		return
+	}
+
+	s.cluster.close()
+	s.cluster = nil
+}
+
+// updateReplicationOptions applies the replication options to the database, keeping its role when it is part of the cluster
+func (s *ImmuServer) updateReplicationOptions(db database.DB, rOpts *database.ReplicationOptions) {
+	if s.cluster != nil && s.cluster.updateReplicationOptions(db, rOpts) {
+		return
+	}
+
+	db.UpdateReplicationOptions(rOpts)
+}
+
+// ClusterStatus returns the role of the node in the Raft group of each database
+func (s *ImmuServer) ClusterStatus(ctx context.Context, req *schema.ClusterStatusRequest) (*schema.ClusterStatusResponse, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if !s.Options.GetAuth() {
+		return nil, ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin {
+		return nil, fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+
+	if s.cluster == nil {
+		return nil, ErrClusterNotEnabled
+	}
+
+	res := &schema.ClusterStatusResponse{NodeId: s.Options.ClusterOptions.NodeID}
+
+	for i := 0; i < s.dbList.Length(); i++ {
+		db := s.dbList.GetByIndex(int64(i))
+		if db == nil || (req.Database != "" && db.GetName() != req.Database) {
+			continue
+		}
+
+		n, ok := s.cluster.node(db.GetName())
+		if !ok {
+			continue
+		}
+
+		res.Databases = append(res.Databases, n.status())
+	}
+
+	if req.Database != "" && len(res.Databases) == 0 {
+		return nil, database.ErrDatabaseNotExists
+	}
+
+	return res, nil
+}
+
+// notLeaderError turns the rejection of a write by a database the node does not lead into an error
+// pointing the client to the leader
+func (s *ImmuServer) notLeaderError(ctx context.Context, fullMethod string, err error) error {
+	if s.cluster == nil || !errors.Is(err, database.ErrIsReplica) {
+		return err
+	}
+
+	db, dbErr := s.getDBFromCtx(ctx, fullMethod[strings.LastIndex(fullMethod, "/")+1:])
+	if dbErr != nil {
+		return err
+	}
+
+	leader, clustered := s.cluster.leaderOf(db.GetName())
+	if !clustered {
+		return err
+	}
+
+	if leader == nil {
+		return status.Errorf(codes.Unavailable, "database '%s' has no cluster leader at the moment", db.GetName())
+	}
+
+	st, detailsErr := status.Newf(codes.FailedPrecondition, "node '%s' is not the leader of database '%s', the leader is node '%s' at %s",
+		s.Options.ClusterOptions.NodeID, db.GetName(), leader.NodeId, leader.Address).WithDetails(leader)
+	if detailsErr != nil {
+		return err
+	}
+
+	return st.Err()
+}
+
+// ClusterRedirector points the clients writing to a database not led by the node to its leader
+func (s *ImmuServer) ClusterRedirector(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	m, err := handler(ctx, req)
+	return m, s.notLeaderError(ctx, info.FullMethod, err)
+}
+
+// ClusterRedirectorStream points the clients writing to a database not led by the node to its leader
+func (s *ImmuServer) ClusterRedirectorStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := handler(srv, ss)
+	return s.notLeaderError(ss.Context(), info.FullMethod, err)
+}
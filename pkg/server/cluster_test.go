@@ -0,0 +1,324 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestParseClusterPeer(t *testing.T) {
+	peer, err := ParseClusterPeer("node2=127.0.0.1:3325,127.0.0.1:3322")
+	require.NoError(t, err)
+	require.Equal(t, &ClusterPeer{NodeID: "node2", RaftAddress: "127.0.0.1:3325", Address: "127.0.0.1:3322"}, peer)
+
+	for _, s := range []string{"", "node2", "node2=127.0.0.1:3325", "=127.0.0.1:3325,127.0.0.1:3322", "node2=,127.0.0.1:3322", "node2=a,b,c"} {
+		_, err = ParseClusterPeer(s)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	}
+}
+
+func TestValidateClusterOptions(t *testing.T) {
+	peer2 := &ClusterPeer{NodeID: "node2", RaftAddress: "127.0.0.1:3326", Address: "127.0.0.1:3323"}
+	peer3 := &ClusterPeer{NodeID: "node3", RaftAddress: "127.0.0.1:3327", Address: "127.0.0.1:3324"}
+
+	opts := DefaultClusterOptions().WithNodeID("node1").WithRaftAddress("127.0.0.1:3325")
+
+	require.ErrorIs(t, validateClusterOptions(DefaultClusterOptions()), ErrInvalidClusterOptions)
+	require.ErrorIs(t, validateClusterOptions(opts.WithPeers([]*ClusterPeer{peer2})), ErrInvalidClusterOptions)
+	require.ErrorIs(t, validateClusterOptions(opts.WithPeers([]*ClusterPeer{peer2, peer2})), ErrInvalidClusterOptions)
+	require.ErrorIs(t, validateClusterOptions(opts.WithPeers([]*ClusterPeer{peer2, {NodeID: "node1", RaftAddress: "127.0.0.1:3328", Address: "127.0.0.1:3329"}})), ErrInvalidClusterOptions)
+	require.ErrorIs(t, validateClusterOptions(opts.WithPeers([]*ClusterPeer{peer2, {NodeID: "node3"}})), ErrInvalidClusterOptions)
+	require.NoError(t, validateClusterOptions(opts.WithPeers([]*ClusterPeer{peer2, peer3})))
+}
+
+type clusterTestNode struct {
+	s   *ImmuServer
+	ctx context.Context
+}
+
+func freeLocalAddress(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	return l.Addr().String()
+}
+
+func waitForClusterLeader(t *testing.T, nodes map[string]*clusterTestNode) *clusterTestNode {
+	for i := 0; i < 300; i++ {
+		for _, n := range nodes {
+			leader, _ := n.s.cluster.leaderOf(DefaultdbName)
+			if leader == nil {
+				continue
+			}
+
+			ln, ok := nodes[leader.NodeId]
+			if ok && !ln.s.dbList.GetByIndex(defaultDbIndex).IsReplica() {
+				return ln
+			}
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	require.Fail(t, "no cluster leader elected")
+	return nil
+}
+
+func TestClusterMuxSecret(t *testing.T) {
+	log := logger.NewSimpleLogger("immudb ", os.Stderr)
+
+	m, err := newClusterMux(freeLocalAddress(t), "secret", nil, log)
+	require.NoError(t, err)
+	defer m.Close()
+
+	m.catchUp = func(dbName string, conn net.Conn) {
+		conn.Write([]byte(dbName))
+		conn.Close()
+	}
+
+	for _, c := range []struct {
+		secret        string
+		authenticated bool
+	}{
+		{"secret", true},
+		{"wrong", false},
+		{"", false},
+	} {
+		peer, err := newClusterMux(freeLocalAddress(t), c.secret, nil, log)
+		require.NoError(t, err)
+		defer peer.Close()
+
+		conn, err := peer.dial(string(m.addr), clusterCatchUpConn, "db1", time.Second)
+		if err != nil {
+			require.False(t, c.authenticated)
+			continue
+		}
+
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+
+		res := make([]byte, 3)
+		_, err = io.ReadFull(conn, res)
+		conn.Close()
+
+		if c.authenticated {
+			require.NoError(t, err)
+			require.Equal(t, "db1", string(res))
+		} else {
+			// peers without the secret are disconnected, or get the challenge instead
+			require.NotEqual(t, "db1", string(res))
+		}
+	}
+}
+
+func TestClusterCatchUpMembership(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	go func() {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// being on the host of a peer is not enough
+	c := &cluster{opts: DefaultClusterOptions().WithPeers([]*ClusterPeer{
+		{NodeID: "node2", RaftAddress: "127.0.0.1:3325", Address: "127.0.0.1:3322"},
+	})}
+	require.False(t, c.isMember(conn))
+
+	c.opts.Secret = "secret"
+	require.True(t, c.isMember(conn))
+}
+
+func TestClusterAuthenticationRequired(t *testing.T) {
+	opts := DefaultClusterOptions().
+		WithNodeID("node1").
+		WithRaftAddress("127.0.0.1:0").
+		WithPeers([]*ClusterPeer{
+			{NodeID: "node2", RaftAddress: "127.0.0.1:3326", Address: "127.0.0.1:3323"},
+			{NodeID: "node3", RaftAddress: "127.0.0.1:3327", Address: "127.0.0.1:3324"},
+		})
+	log := logger.NewSimpleLogger("immudb ", os.Stderr)
+
+	_, err := newCluster(opts, "127.0.0.1:3322", "data_cluster_auth", nil, log)
+	require.ErrorIs(t, err, ErrInvalidClusterOptions)
+	require.Contains(t, err.Error(), "cluster secret")
+
+	// certificates without client authentication do not identify the nodes
+	_, err = newCluster(opts, "127.0.0.1:3322", "data_cluster_auth", &tls.Config{Certificates: []tls.Certificate{{}}}, log)
+	require.ErrorIs(t, err, ErrInvalidClusterOptions)
+
+	c, err := newCluster(opts.WithSecret("secret"), "127.0.0.1:3322", "data_cluster_auth", nil, log)
+	require.NoError(t, err)
+	c.mux.Close()
+}
+
+func TestCluster(t *testing.T) {
+	ids := []string{"node1", "node2", "node3"}
+
+	raftAddresses := make(map[string]string)
+	for _, id := range ids {
+		raftAddresses[id] = freeLocalAddress(t)
+	}
+
+	nodes := make(map[string]*clusterTestNode)
+
+	for i, id := range ids {
+		var peers []*ClusterPeer
+		for j, peerID := range ids {
+			if peerID != id {
+				peers = append(peers, &ClusterPeer{NodeID: peerID, RaftAddress: raftAddresses[peerID], Address: fmt.Sprintf("127.0.0.1:%d", 3322+j)})
+			}
+		}
+
+		options := DefaultOptions().
+			WithDir(fmt.Sprintf("data_cluster_%s", id)).
+			WithListener(bufconn.Listen(1024 * 1024)).
+			WithAddress("127.0.0.1").
+			WithPort(3322 + i).
+			WithMetricsServer(false).
+			WithWebServer(false).
+			WithPgsqlServer(false).
+			WithAdminPassword(auth.SysAdminPassword).
+			WithClusterOptions(DefaultClusterOptions().
+				WithNodeID(id).
+				WithRaftAddress(raftAddresses[id]).
+				WithPeers(peers).
+				WithSecret("secret"))
+		defer os.RemoveAll(options.Dir)
+
+		s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+		err := s.Initialize()
+		require.NoError(t, err)
+		defer s.CloseDatabases()
+
+		require.True(t, s.dbList.GetByIndex(defaultDbIndex).IsReplica())
+
+		err = s.startCluster()
+		require.NoError(t, err)
+		defer s.stopCluster()
+
+		lr, err := s.Login(context.Background(), &schema.LoginRequest{
+			User:     []byte(auth.SysAdminUsername),
+			Password: []byte(auth.SysAdminPassword),
+		})
+		require.NoError(t, err)
+
+		nodes[id] = &clusterTestNode{
+			s:   s,
+			ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token)),
+		}
+	}
+
+	leader := waitForClusterLeader(t, nodes)
+
+	waitForTx := func(n *clusterTestNode, txID uint64) {
+		err := n.s.dbList.GetByIndex(defaultDbIndex).WaitForTx(txID, nil)
+		require.NoError(t, err)
+	}
+
+	t.Run("writes to the leader are replicated to every node", func(t *testing.T) {
+		hdr, err := leader.s.Set(leader.ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+		require.NoError(t, err)
+
+		for _, n := range nodes {
+			waitForTx(n, hdr.Id)
+
+			entry, err := n.s.Get(n.ctx, &schema.KeyRequest{Key: []byte("key1"), SinceTx: hdr.Id})
+			require.NoError(t, err)
+			require.Equal(t, []byte("value1"), entry.Value)
+		}
+	})
+
+	t.Run("writes to a follower point to the leader", func(t *testing.T) {
+		for _, n := range nodes {
+			if n == leader {
+				continue
+			}
+
+			_, err := n.s.Set(n.ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+			require.Error(t, err)
+
+			err = n.s.notLeaderError(n.ctx, "/immudb.schema.ImmuService/Set", err)
+			require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+			details := status.Convert(err).Details()
+			require.Len(t, details, 1)
+
+			redirectTo, ok := details[0].(*schema.ClusterLeader)
+			require.True(t, ok)
+			require.Equal(t, leader.s.Options.ClusterOptions.NodeID, redirectTo.NodeId)
+			require.Equal(t, fmt.Sprintf("127.0.0.1:%d", leader.s.Options.Port), redirectTo.Address)
+		}
+	})
+
+	t.Run("the cluster status reports the role of the node", func(t *testing.T) {
+		_, err := leader.s.ClusterStatus(leader.ctx, nil)
+		require.Equal(t, ErrIllegalArguments, err)
+
+		res, err := leader.s.ClusterStatus(leader.ctx, &schema.ClusterStatusRequest{Database: DefaultdbName})
+		require.NoError(t, err)
+		require.Equal(t, leader.s.Options.ClusterOptions.NodeID, res.NodeId)
+		require.Len(t, res.Databases, 1)
+		require.Equal(t, "leader", res.Databases[0].Role)
+		require.Len(t, res.Databases[0].Members, 3)
+		require.Positive(t, res.Databases[0].AppliedTxId)
+
+		_, err = leader.s.ClusterStatus(leader.ctx, &schema.ClusterStatusRequest{Database: "nodb"})
+		require.Error(t, err)
+	})
+
+	t.Run("a new leader is elected when the leader stops", func(t *testing.T) {
+		leader.s.stopCluster()
+		delete(nodes, leader.s.Options.ClusterOptions.NodeID)
+
+		newLeader := waitForClusterLeader(t, nodes)
+		require.NotEqual(t, leader, newLeader)
+
+		hdr, err := newLeader.s.Set(newLeader.ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key3"), Value: []byte("value3")}}})
+		require.NoError(t, err)
+
+		for _, n := range nodes {
+			waitForTx(n, hdr.Id)
+		}
+	})
+}
@@ -0,0 +1,361 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/hashicorp/raft"
+)
+
+const (
+	clusterRaftConn    byte = 1
+	clusterCatchUpConn byte = 2
+)
+
+// clusterHeaderTimeout bounds the time a peer gets to identify the kind of connection it opened
+const clusterHeaderTimeout = 10 * time.Second
+
+// clusterChallengeSize is the size of the nonce peers sign with the cluster secret to authenticate their connections
+const clusterChallengeSize = 32
+
+// clusterAddr is the address of the node advertised to its peers
+type clusterAddr string
+
+func (a clusterAddr) Network() string {
+	return "tcp"
+}
+
+func (a clusterAddr) String() string {
+	return string(a)
+}
+
+// clusterMux shares the consensus address of the node among the Raft groups of its databases.
+// Every connection starts with its kind and the name of the database it belongs to
+type clusterMux struct {
+	listener net.Listener
+	addr     clusterAddr
+	// tlsConfig is used to dial the peers, connections go in the clear when nil
+	tlsConfig *tls.Config
+	// secret authenticates the connections between peers, if set
+	secret []byte
+
+	mutex  sync.Mutex
+	groups map[string]*clusterStreamLayer
+
+	// catchUp serves the transactions of a database to a peer lagging behind
+	catchUp func(dbName string, conn net.Conn)
+
+	logger logger.Logger
+}
+
+func newClusterMux(address string, secret string, serverTLSConfig *tls.Config, log logger.Logger) (*clusterMux, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &clusterMux{
+		listener: listener,
+		addr:     clusterAddr(address),
+		groups:   make(map[string]*clusterStreamLayer),
+		logger:   log,
+	}
+
+	if secret != "" {
+		m.secret = []byte(secret)
+	}
+
+	// nodes authenticate each other through the certificates of the replication channel
+	if serverTLSConfig != nil {
+		m.listener = tls.NewListener(listener, serverTLSConfig)
+		m.tlsConfig = &tls.Config{
+			Certificates: serverTLSConfig.Certificates,
+			RootCAs:      serverTLSConfig.ClientCAs,
+		}
+	}
+
+	go m.serve()
+
+	return m, nil
+}
+
+func (m *clusterMux) serve() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go m.handle(conn)
+	}
+}
+
+func (m *clusterMux) handle(conn net.Conn) {
+	conn.SetReadDeadline(time.Now().Add(clusterHeaderTimeout))
+
+	kind, dbName, err := readClusterHeader(conn)
+	if err == nil && m.secret != nil {
+		err = m.challenge(conn, kind, dbName)
+	}
+	if err != nil {
+		m.logger.Warningf("Invalid cluster connection from %s: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	conn.SetReadDeadline(time.Time{})
+
+	if kind == clusterCatchUpConn && m.catchUp != nil {
+		m.catchUp(dbName, conn)
+		return
+	}
+
+	m.mutex.Lock()
+	group, ok := m.groups[dbName]
+	m.mutex.Unlock()
+
+	if !ok || kind != clusterRaftConn {
+		conn.Close()
+		return
+	}
+
+	select {
+	case group.conns <- conn:
+	case <-group.closed:
+		conn.Close()
+	}
+}
+
+func (m *clusterMux) dial(address string, kind byte, dbName string, timeout time.Duration) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	var err error
+
+	if m.tlsConfig == nil {
+		conn, err = dialer.Dial("tcp", address)
+	} else {
+		host, _, splitErr := net.SplitHostPort(address)
+		if splitErr != nil {
+			return nil, splitErr
+		}
+
+		tlsConfig := m.tlsConfig.Clone()
+		tlsConfig.ServerName = host
+
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeClusterHeader(conn, kind, dbName)
+	if err == nil && m.secret != nil {
+		conn.SetDeadline(time.Now().Add(timeout))
+		err = m.respond(conn, kind, dbName)
+		conn.SetDeadline(time.Time{})
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// challenge checks the peer which opened the connection knows the cluster secret
+func (m *clusterMux) challenge(conn net.Conn, kind byte, dbName string) error {
+	nonce := make([]byte, clusterChallengeSize)
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Write(nonce)
+	if err != nil {
+		return err
+	}
+
+	mac := make([]byte, sha256.Size)
+
+	_, err = io.ReadFull(conn, mac)
+	if err != nil {
+		return err
+	}
+
+	if !hmac.Equal(mac, m.sign(nonce, kind, dbName)) {
+		return ErrClusterAuthFailed
+	}
+
+	return nil
+}
+
+// respond proves to the peer accepting the connection that the node knows the cluster secret
+func (m *clusterMux) respond(conn net.Conn, kind byte, dbName string) error {
+	nonce := make([]byte, clusterChallengeSize)
+
+	_, err := io.ReadFull(conn, nonce)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Write(m.sign(nonce, kind, dbName))
+	return err
+}
+
+func (m *clusterMux) sign(nonce []byte, kind byte, dbName string) []byte {
+	h := hmac.New(sha256.New, m.secret)
+	h.Write(nonce)
+	h.Write([]byte{kind})
+	h.Write([]byte(dbName))
+	return h.Sum(nil)
+}
+
+// register returns the stream layer used by the Raft group of the database
+func (m *clusterMux) register(dbName string) *clusterStreamLayer {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	sl := &clusterStreamLayer{
+		mux:    m,
+		dbName: dbName,
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+
+	m.groups[dbName] = sl
+
+	return sl
+}
+
+func (m *clusterMux) unregister(sl *clusterStreamLayer) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.groups[sl.dbName] == sl {
+		delete(m.groups, sl.dbName)
+	}
+}
+
+func (m *clusterMux) Close() error {
+	return m.listener.Close()
+}
+
+func writeClusterHeader(w io.Writer, kind byte, dbName string) error {
+	if len(dbName) > 255 {
+		return ErrIllegalArguments
+	}
+
+	_, err := w.Write(append([]byte{kind, byte(len(dbName))}, dbName...))
+	return err
+}
+
+func readClusterHeader(r io.Reader) (kind byte, dbName string, err error) {
+	var b [2]byte
+
+	_, err = io.ReadFull(r, b[:])
+	if err != nil {
+		return 0, "", err
+	}
+
+	if b[0] != clusterRaftConn && b[0] != clusterCatchUpConn {
+		return 0, "", fmt.Errorf("unknown connection kind %d", b[0])
+	}
+
+	name := make([]byte, b[1])
+
+	_, err = io.ReadFull(r, name)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return b[0], string(name), nil
+}
+
+// clusterStreamLayer carries the Raft traffic of a database over the shared consensus address
+type clusterStreamLayer struct {
+	mux    *clusterMux
+	dbName string
+
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (sl *clusterStreamLayer) Accept() (net.Conn, error) {
+	select {
+	case conn := <-sl.conns:
+		return conn, nil
+	case <-sl.closed:
+		return nil, ErrClusterTransportClosed
+	}
+}
+
+func (sl *clusterStreamLayer) Close() error {
+	sl.closeOnce.Do(func() {
+		sl.mux.unregister(sl)
+		close(sl.closed)
+	})
+	return nil
+}
+
+func (sl *clusterStreamLayer) Addr() net.Addr {
+	return sl.mux.addr
+}
+
+func (sl *clusterStreamLayer) Dial(address raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	return sl.mux.dial(string(address), clusterRaftConn, sl.dbName, timeout)
+}
+
+// writeCatchUpTx sends a length-prefixed exported transaction to a lagging peer
+func writeCatchUpTx(w io.Writer, etx []byte) error {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(etx)))
+
+	_, err := w.Write(append(size[:], etx...))
+	return err
+}
+
+func readCatchUpTx(r io.Reader) ([]byte, error) {
+	var size [4]byte
+
+	_, err := io.ReadFull(r, size[:])
+	if err != nil {
+		return nil, err
+	}
+
+	etx := make([]byte, binary.BigEndian.Uint32(size[:]))
+
+	_, err = io.ReadFull(r, etx)
+	if err != nil {
+		return nil, err
+	}
+
+	return etx, nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/codenotary/immudb/pkg/signer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerComplianceReportSigned(t *testing.T) {
+	dbRootpath := database.DefaultOption().GetDbRootPath()
+	s := DefaultServer()
+
+	defer os.RemoveAll(s.Options.Dir)
+
+	sig, err := signer.NewSigner("./../../test/signer/ec3.key")
+	require.NoError(t, err)
+
+	s = s.WithOptions(s.Options.WithAuth(false).WithSigningKey("foo")).WithStateSigner(NewStateSigner(sig)).(*ImmuServer)
+
+	err = s.loadSystemDatabase(dbRootpath, nil, s.Options.AdminPassword)
+	require.NoError(t, err)
+
+	err = s.loadDefaultDatabase(dbRootpath, nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	_, err = s.ComplianceReport(ctx, &schema.ComplianceReportRequest{SinceTime: 10, UntilTime: 5})
+	require.Error(t, err)
+
+	report, err := s.ComplianceReport(ctx, &schema.ComplianceReportRequest{})
+	require.NoError(t, err)
+	require.Equal(t, DefaultdbName, report.Db)
+	require.Equal(t, report.TxCount, report.VerifiedTxCount)
+	require.Zero(t, report.FailedVerifications)
+	require.NotNil(t, report.Signature)
+
+	ecdsaPK, err := signer.UnmarshalKey(report.Signature.PublicKey)
+	require.NoError(t, err)
+
+	ok, err := report.CheckSignature(ecdsaPK)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	report.TxCount++
+
+	ok, err = report.CheckSignature(ecdsaPK)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
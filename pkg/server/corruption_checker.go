@@ -15,174 +15,639 @@ limitations under the License.
 */
 package server
 
-/*
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+)
+
+// corruptionAlertTimeout bounds each webhook call made when corruption is detected
+const corruptionAlertTimeout = 10 * time.Second
+
+// corruptionCheckBatchSize is the number of transactions verified at once, the database is not locked between batches
+const corruptionCheckBatchSize = 100
+
+// corruptionCheckpointInterval is the minimum time between two checkpoints persisted during a scan
+const corruptionCheckpointInterval = time.Minute
+
+// corruptionAlert is the payload sent to the alert webhooks when a database fails its verification
+type corruptionAlert struct {
+	Server              string    `json:"server"`
+	Database            string    `json:"database"`
+	DetectedAt          time.Time `json:"detectedAt"`
+	FailedVerifications uint64    `json:"failedVerifications"`
+	FirstTx             uint64    `json:"firstTx"`
+	LastTx              uint64    `json:"lastTx"`
+	Quarantined         bool      `json:"quarantined"`
+	// Divergence is only set when a replica could not be proven consistent with its primary
+	Divergence *replicaDivergence `json:"divergence,omitempty"`
+}
+
+// startCorruptionChecker runs in background the periodic verification of the databases with the corruption checker enabled
+func (s *ImmuServer) startCorruptionChecker() {
+	s.checkerMux.Lock()
+	defer s.checkerMux.Unlock()
+
+	s.checkerInterval = s.Options.CorruptionCheckInterval
+	s.setCorruptionCheckIORateLimit(s.Options.CorruptionCheckIORateLimit)
+
+	s.runCorruptionChecker()
+}
+
+// stopCorruptionChecker interrupts the checker and waits for the verification in progress, if any
+func (s *ImmuServer) stopCorruptionChecker() {
+	s.checkerMux.Lock()
+	defer s.checkerMux.Unlock()
+
+	s.haltCorruptionChecker()
+}
+
+// runCorruptionChecker must be called while holding checkerMux
+func (s *ImmuServer) runCorruptionChecker() {
+	if s.checkerInterval <= 0 {
+		return
+	}
+
+	s.checkerDone = make(chan struct{})
+	s.checkerFinished = make(chan struct{})
+
+	go s.corruptionChecker(time.Duration(s.checkerInterval)*time.Second, s.checkerDone, s.checkerFinished)
+}
+
+// haltCorruptionChecker must be called while holding checkerMux
+func (s *ImmuServer) haltCorruptionChecker() {
+	if s.checkerDone == nil {
+		return
+	}
+
+	close(s.checkerDone)
+	<-s.checkerFinished
 
-// ErrConsistencyFail happens when a consistency check fails. Check the log to retrieve details on which element is failing
-const ErrConsistencyFail = "consistency check fail at index %d"
+	s.checkerDone = nil
+	s.checkerFinished = nil
+}
+
+func (s *ImmuServer) corruptionCheckIORateLimit() uint64 {
+	s.checkerRateMux.RLock()
+	defer s.checkerRateMux.RUnlock()
 
-type CCOptions struct {
-	singleiteration    bool
-	iterationSleepTime time.Duration
-	frequencySleepTime time.Duration
+	return s.checkerIORateLimit
 }
 
-type corruptionChecker struct {
-	options        CCOptions
-	dbList         DatabaseList
-	Logger         logger.Logger
-	exit           bool
-	muxit          sync.Mutex
-	Trusted        bool
-	mux            sync.Mutex
-	currentDbIndex int
-	rg             RandomGenerator
+func (s *ImmuServer) setCorruptionCheckIORateLimit(ioRateLimit uint64) {
+	s.checkerRateMux.Lock()
+	defer s.checkerRateMux.Unlock()
+
+	s.checkerIORateLimit = ioRateLimit
 }
 
-// CorruptionChecker corruption checker interface
-type CorruptionChecker interface {
-	Start(context.Context) (err error)
-	Stop()
-	GetStatus() bool
+// UpdateCorruptionChecker changes the interval between the verifications and the IO rate limit of the corruption checker.
+// Changes are not persisted, the configured options are applied again on restart
+func (s *ImmuServer) UpdateCorruptionChecker(ctx context.Context, req *schema.UpdateCorruptionCheckerRequest) (*schema.CorruptionCheckerSettings, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if !s.Options.GetAuth() {
+		return nil, ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin {
+		return nil, fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+
+	if req.IoRateLimit != nil {
+		s.setCorruptionCheckIORateLimit(req.IoRateLimit.Value)
+	}
+
+	s.checkerMux.Lock()
+	defer s.checkerMux.Unlock()
+
+	if req.Interval != nil && int(req.Interval.Value) != s.checkerInterval {
+		s.haltCorruptionChecker()
+		s.checkerInterval = int(req.Interval.Value)
+		s.runCorruptionChecker()
+	}
+
+	s.Logger.Infof("Corruption checker updated by %s: interval %ds, IO rate limit %d bytes/s",
+		user.Username, s.checkerInterval, s.corruptionCheckIORateLimit())
+
+	return &schema.CorruptionCheckerSettings{
+		Interval:    uint32(s.checkerInterval),
+		IoRateLimit: s.corruptionCheckIORateLimit(),
+	}, nil
 }
 
-// NewCorruptionChecker returns new trust checker service
-func NewCorruptionChecker(opt CCOptions, d DatabaseList, l logger.Logger, rg RandomGenerator) CorruptionChecker {
-	return &corruptionChecker{
-		options:        opt,
-		dbList:         d,
-		Logger:         l,
-		exit:           false,
-		Trusted:        true,
-		currentDbIndex: 0,
-		rg:             rg,
+// CorruptionCheckStatus returns the last completed and the running integrity scan of the opened databases,
+// or only of the requested one
+func (s *ImmuServer) CorruptionCheckStatus(ctx context.Context, req *schema.CorruptionCheckStatusRequest) (*schema.CorruptionCheckStatusResponse, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if !s.Options.GetAuth() {
+		return nil, ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin {
+		return nil, fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+
+	var dbs []database.DB
+
+	if req.Database != "" {
+		db, err := s.dbList.GetByName(req.Database)
+		if err != nil {
+			return nil, err
+		}
+
+		dbs = append(dbs, db)
+	} else {
+		for i := 0; i < s.dbList.Length(); i++ {
+			db := s.dbList.GetByIndex(int64(i))
+			if db != nil {
+				dbs = append(dbs, db)
+			}
+		}
+	}
+
+	s.checkerMux.Lock()
+	res := &schema.CorruptionCheckStatusResponse{
+		Settings: &schema.CorruptionCheckerSettings{
+			Interval:    uint32(s.checkerInterval),
+			IoRateLimit: s.corruptionCheckIORateLimit(),
+		},
+	}
+	s.checkerMux.Unlock()
+
+	s.degradedMux.Lock()
+	defer s.degradedMux.Unlock()
+
+	for _, db := range dbs {
+		_, degraded := s.degraded[db.GetName()]
+
+		res.Databases = append(res.Databases, &schema.CorruptionCheckDatabaseStatus{
+			Database:    db.GetName(),
+			Enabled:     db.GetOptions().GetCorruptionChecker(),
+			Degraded:    degraded,
+			LastScan:    s.lastScans[db.GetName()].toProto(),
+			RunningScan: s.runningScans[db.GetName()].toProto(),
+			Quarantined: db.IsQuarantined(),
+		})
 	}
+
+	return res, nil
 }
 
-// Start start the trust checker loop
-func (s *corruptionChecker) Start(ctx context.Context) (err error) {
-	s.Logger.Debugf("Start scanning ...")
+func (s *ImmuServer) corruptionChecker(interval time.Duration, done <-chan struct{}, finished chan<- struct{}) {
+	defer close(finished)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
 	for {
-		s.mux.Lock()
-		err = s.checkLevel0(ctx)
-		s.mux.Unlock()
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
 
-		if err != nil || s.isTerminated() || s.options.singleiteration {
-			return err
+		s.checkDatabases(done)
+	}
+}
+
+// checkDatabases verifies the transactions committed since the previous check of every opened database
+// with the corruption checker enabled, databases not loaded yet or already degraded are skipped
+func (s *ImmuServer) checkDatabases(done <-chan struct{}) {
+	for i := 0; i < s.dbList.Length(); i++ {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		db := s.dbList.GetByIndex(int64(i))
+		if db == nil || !db.GetOptions().GetCorruptionChecker() || s.isDegraded(db.GetName()) {
+			continue
 		}
 
-		time.Sleep(s.options.iterationSleepTime)
+		err := s.checkDatabase(db, done)
+		if err != nil {
+			s.Logger.Warningf("Unable to check database '%s' for corruption: %v", db.GetName(), err)
+		}
 	}
 }
 
-func (s *corruptionChecker) isTerminated() bool {
-	s.muxit.Lock()
-	defer s.muxit.Unlock()
-	return s.exit
+// corruptionScan tracks the verification of the transactions of a database committed since its previous scan
+type corruptionScan struct {
+	startedAt    time.Time
+	endedAt      time.Time
+	firstTx      uint64
+	lastTx       uint64
+	targetTx     uint64
+	txCount      uint64
+	entriesCount uint64
+	failedTxs    []uint64
+}
+
+func (scan *corruptionScan) add(verification *database.TxsVerification) {
+	if scan.txCount == 0 {
+		scan.firstTx = verification.FirstTx
+	}
+
+	scan.lastTx = verification.LastTx
+	scan.txCount += verification.TxCount
+	scan.entriesCount += verification.EntriesCount
+	scan.failedTxs = append(scan.failedTxs, verification.FailedTxs...)
 }
 
-// Stop stop the trust checker loop
-func (s *corruptionChecker) Stop() {
-	s.muxit.Lock()
-	s.exit = true
-	s.muxit.Unlock()
-	s.Logger.Infof("Waiting for consistency checker to shut down")
-	s.mux.Lock()
+func (scan *corruptionScan) toProto() *schema.CorruptionCheckScan {
+	if scan == nil {
+		return nil
+	}
+
+	return &schema.CorruptionCheckScan{
+		StartedAt:    scan.startedAt.Unix(),
+		EndedAt:      unixOrZero(scan.endedAt),
+		FirstTx:      scan.firstTx,
+		LastTx:       scan.lastTx,
+		TargetTx:     scan.targetTx,
+		TxCount:      scan.txCount,
+		EntriesCount: scan.entriesCount,
+		FailedTxs:    append([]uint64(nil), scan.failedTxs...),
+	}
 }
 
-func (s *corruptionChecker) checkLevel0(ctx context.Context) (err error) {
-	if s.currentDbIndex == s.dbList.Length() {
-		s.currentDbIndex = 0
+// checkDatabase verifies in batches the transactions committed up to the start of the scan,
+// pausing between them as needed to stay within the IO rate limit.
+// Progress is checkpointed in the system database so the verification is resumed after a restart
+func (s *ImmuServer) checkDatabase(db database.DB, done <-chan struct{}) error {
+	state, err := db.CurrentState()
+	if err != nil {
+		return err
 	}
-	db := s.dbList.GetByIndex(int64(s.currentDbIndex))
-	s.currentDbIndex++
-	var r *schema.Root
-	s.Logger.Debugf("Retrieving a fresh root ...")
-	if r, err = db.CurrentRoot(); err != nil {
-		s.Logger.Errorf("Error retrieving root: %s", err)
-		return
+
+	s.degradedMux.Lock()
+	checkedTx, resumed := s.checkedUntil[db.GetName()]
+	s.degradedMux.Unlock()
+
+	if !resumed {
+		checkedTx = s.resumeCorruptionCheck(db)
 	}
-	if r.GetRoot() == nil {
-		s.Logger.Debugf("Immudb is empty ...")
-	} else {
-		// create a shuffle range with all indexes presents in immudb
-		ids := s.rg.getList(0, r.GetIndex())
-		s.Logger.Debugf("Start scanning %d elements", len(ids))
-		for _, id := range ids {
-			if s.isTerminated() {
-				return
-			}
 
-			var item *schema.VerifiedTx
-			if item, err = db.BySafeIndex(&schema.SafeIndexOptions{
-				Index: id,
-				RootIndex: &schema.Index{
-					Index: r.GetIndex(),
-				},
-			}); err != nil {
-				if err == store.ErrInconsistentDigest {
-					auth.IsTampered = true
-					s.Logger.Errorf("insertion order index %d was tampered", id)
-					return
-				}
-				s.Logger.Errorf("Error retrieving element at index %d: %s", id, err)
-				return
-			}
-			//verified := item.Proof.Verify(item.Item.Value, *r)
-			verified := item != nil
-			s.Logger.Debugf("Item index %d, verified %t", item.Tx.Metadata.Id, verified)
-			if !verified {
-				s.Trusted = false
-				auth.IsTampered = true
-				s.Logger.Errorf(ErrConsistencyFail, item.Tx.Metadata.Id)
-				return
+	s.degradedMux.Lock()
+	scan := &corruptionScan{startedAt: time.Now(), targetTx: state.TxId}
+	s.runningScans[db.GetName()] = scan
+	s.degradedMux.Unlock()
+
+	// interrupted scans are not reported, the next one resumes from the last verified transaction
+	completed := false
+
+	checkpointedTx := checkedTx
+	checkpointedAt := time.Now()
+
+	var checkedAlh [sha256.Size]byte
+
+	checkpoint := func() {
+		if checkedTx == checkpointedTx {
+			return
+		}
+
+		err := s.saveCorruptionCheckpoint(db.GetName(), checkedTx, checkedAlh)
+		if err != nil {
+			s.Logger.Warningf("Unable to checkpoint the corruption checker of database '%s': %v", db.GetName(), err)
+			return
+		}
+
+		checkpointedTx = checkedTx
+		checkpointedAt = time.Now()
+	}
+
+	defer func() {
+		checkpoint()
+
+		s.degradedMux.Lock()
+		defer s.degradedMux.Unlock()
+
+		delete(s.runningScans, db.GetName())
+
+		if completed {
+			scan.endedAt = time.Now()
+			s.lastScans[db.GetName()] = scan
+
+			Metrics.ScanDurationHistograms.WithLabelValues(db.GetName()).Observe(scan.endedAt.Sub(scan.startedAt).Seconds())
+
+			if len(scan.failedTxs) == 0 {
+				Metrics.LastSuccessfulScanAtGauges.WithLabelValues(db.GetName()).Set(float64(scan.endedAt.Unix()))
 			}
-			time.Sleep(s.options.frequencySleepTime)
+		}
+	}()
+
+	for checkedTx < state.TxId {
+		limit := corruptionCheckBatchSize
+		if state.TxId-checkedTx < uint64(limit) {
+			limit = int(state.TxId - checkedTx)
+		}
+
+		verification, err := db.VerifyTxs(checkedTx+1, limit)
+		if err != nil {
+			return err
+		}
+
+		if verification.TxCount == 0 {
+			break
+		}
+
+		Metrics.UpdateVerificationMetrics(db.GetName(), verificationSourceCorruptionChecker, verification.EntriesCount, verification.FailedVerifications)
+
+		s.degradedMux.Lock()
+		scan.add(verification)
+		if verification.FailedVerifications == 0 {
+			checkedTx = verification.LastTx
+			checkedAlh = verification.LastAlh
+			s.checkedUntil[db.GetName()] = checkedTx
+		}
+		s.degradedMux.Unlock()
+
+		if verification.FailedVerifications > 0 {
+			completed = true
+			s.reportCorruption(db, verification)
+			return nil
+		}
+
+		if time.Since(checkpointedAt) >= corruptionCheckpointInterval {
+			checkpoint()
+		}
+
+		if !s.throttleCorruptionCheck(verification.BytesRead, done) {
+			return nil
 		}
 	}
 
+	completed = true
+
 	return nil
 }
 
-// GetStatus return status of the trust checker. False means that a consistency checks was failed
-func (s *corruptionChecker) GetStatus() bool {
-	return s.Trusted
+// corruptionCheckpoint is the last transaction verified by the corruption checker in a database, as stored in the system database
+type corruptionCheckpoint struct {
+	TxID    uint64    `json:"txId"`
+	Alh     []byte    `json:"alh"`
+	SavedAt time.Time `json:"savedAt"`
 }
 
-*/
+func corruptionCheckpointKey(database string) []byte {
+	key := make([]byte, 1+len(database))
+	key[0] = KeyPrefixCorruptionCheckpoint
+	copy(key[1:], []byte(database))
+	return key
+}
 
-/*
-type cryptoRandSource struct{}
+func (s *ImmuServer) saveCorruptionCheckpoint(database string, txID uint64, alh [sha256.Size]byte) error {
+	checkpointData, err := json.Marshal(&corruptionCheckpoint{
+		TxID:    txID,
+		Alh:     alh[:],
+		SavedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
 
-func newCryptoRandSource() cryptoRandSource {
-	return cryptoRandSource{}
+	_, err = s.sysDB.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: corruptionCheckpointKey(database), Value: checkpointData}}})
+
+	return err
 }
 
-func (cryptoRandSource) Int63() int64 {
-	var b [8]byte
-	_, _ = rand.Read(b[:])
-	return int64(binary.LittleEndian.Uint64(b[:]) & (1<<63 - 1))
+// loadCorruptionCheckpoint returns the persisted checkpoint of the database, or nil if it was never checked
+func (s *ImmuServer) loadCorruptionCheckpoint(database string) (*corruptionCheckpoint, error) {
+	e, err := s.sysDB.Get(&schema.KeyRequest{Key: corruptionCheckpointKey(database)})
+	if err == store.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint corruptionCheckpoint
+
+	err = json.Unmarshal(e.Value, &checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
 }
 
-func (cryptoRandSource) Seed(_ int64) {}
-*/
+// resumeCorruptionCheck returns the transaction the verification of the database can be resumed from.
+// The checkpoint is only trusted if the checkpointed transaction still verifies with the same accumulated hash,
+// otherwise, e.g. if the database was recreated meanwhile, it is checked from the start
+func (s *ImmuServer) resumeCorruptionCheck(db database.DB) uint64 {
+	checkpoint, err := s.loadCorruptionCheckpoint(db.GetName())
+	if err != nil {
+		s.Logger.Warningf("Unable to read the corruption checker checkpoint of database '%s': %v", db.GetName(), err)
+		return 0
+	}
 
-/*
-type randomGenerator struct{}
+	if checkpoint == nil {
+		return 0
+	}
+
+	verification, err := db.VerifyTxs(checkpoint.TxID, 1)
+	if err != nil ||
+		verification.TxCount == 0 ||
+		verification.FailedVerifications > 0 ||
+		!bytes.Equal(verification.LastAlh[:], checkpoint.Alh) {
+		s.Logger.Warningf("Corruption checker checkpoint of database '%s' at tx %d does not match the database, it will be checked from the start",
+			db.GetName(), checkpoint.TxID)
+		return 0
+	}
+
+	s.Logger.Infof("Corruption checker of database '%s' resumed from tx %d", db.GetName(), checkpoint.TxID)
 
-type RandomGenerator interface {
-	getList(uint64, uint64) []uint64
+	return checkpoint.TxID
 }
 
-func (rg randomGenerator) getList(start, end uint64) []uint64 {
-	ids := make([]uint64, end-start+1)
-	var i uint64
-	for i = start; i <= end; i++ {
-		ids[i] = i
+// throttleCorruptionCheck waits the time needed to read the given bytes within the IO rate limit,
+// it returns false if the checker was stopped meanwhile
+func (s *ImmuServer) throttleCorruptionCheck(bytesRead uint64, done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return false
+	default:
+	}
+
+	rateLimit := s.corruptionCheckIORateLimit()
+	if rateLimit == 0 {
+		return true
+	}
+
+	wait := time.Duration(float64(bytesRead) / float64(rateLimit) * float64(time.Second))
+
+	select {
+	case <-done:
+		return false
+	case <-time.After(wait):
+		return true
 	}
-	rn := mrand.New(newCryptoRandSource())
-	// shuffle indexes
-	rn.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
-	return ids
 }
-*/
+
+func (s *ImmuServer) reportCorruption(db database.DB, verification *database.TxsVerification) {
+	s.Logger.Errorf("Corruption detected in database '%s': %d transactions could not be verified", db.GetName(), verification.FailedVerifications)
+
+	s.degradedMux.Lock()
+	s.degraded[db.GetName()] = time.Now()
+	s.degradedMux.Unlock()
+
+	Metrics.DBCorruptedGauges.WithLabelValues(db.GetName()).Set(1)
+
+	quarantined := s.Options.CorruptionPolicy == CorruptionPolicyQuarantine
+	if quarantined {
+		s.quarantineDatabase(db, verification)
+	}
+
+	s.sendCorruptionAlert(&corruptionAlert{
+		Server:              s.UUID.String(),
+		Database:            db.GetName(),
+		DetectedAt:          time.Now(),
+		FailedVerifications: verification.FailedVerifications,
+		FirstTx:             verification.FirstTx,
+		LastTx:              verification.LastTx,
+		Quarantined:         quarantined,
+	})
+}
+
+func (s *ImmuServer) isDegraded(database string) bool {
+	s.degradedMux.Lock()
+	defer s.degradedMux.Unlock()
+
+	_, degraded := s.degraded[database]
+	return degraded
+}
+
+// clearDegraded marks the database as healthy again, e.g. once its damaged transactions got repaired
+func (s *ImmuServer) clearDegraded(database string) {
+	s.degradedMux.Lock()
+	defer s.degradedMux.Unlock()
+
+	if _, degraded := s.degraded[database]; !degraded {
+		return
+	}
+
+	delete(s.degraded, database)
+
+	Metrics.DBCorruptedGauges.WithLabelValues(database).Set(0)
+}
+
+// degradedDatabases returns the sorted names of the databases where corruption was detected
+func (s *ImmuServer) degradedDatabases() []string {
+	s.degradedMux.Lock()
+	defer s.degradedMux.Unlock()
+
+	var dbs []string
+	for db := range s.degraded {
+		dbs = append(dbs, db)
+	}
+
+	sort.Strings(dbs)
+
+	return dbs
+}
+
+// sendCorruptionAlert notifies the configured webhooks and email recipients, failures are only logged
+func (s *ImmuServer) sendCorruptionAlert(alert *corruptionAlert) {
+	opts := s.Options.CorruptionAlertOptions
+	if opts == nil {
+		return
+	}
+
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		s.Logger.Errorf("Unable to encode corruption alert: %v", err)
+		return
+	}
+
+	client := &http.Client{Timeout: corruptionAlertTimeout}
+
+	for _, url := range opts.WebhookURLs {
+		resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			s.Logger.Errorf("Unable to send corruption alert to '%s': %v", url, err)
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			s.Logger.Errorf("Corruption alert rejected by '%s' with status %d", url, resp.StatusCode)
+		}
+	}
+
+	if opts.SMTPAddress != "" && len(opts.EmailTo) > 0 {
+		var smtpAuth smtp.Auth
+		if opts.SMTPUsername != "" {
+			host := strings.Split(opts.SMTPAddress, ":")[0]
+			smtpAuth = smtp.PlainAuth("", opts.SMTPUsername, opts.SMTPPassword, host)
+		}
+
+		err = smtp.SendMail(opts.SMTPAddress, smtpAuth, opts.EmailFrom, opts.EmailTo, corruptionAlertEmail(opts.EmailFrom, opts.EmailTo, alert))
+		if err != nil {
+			s.Logger.Errorf("Unable to send corruption alert email: %v", err)
+		}
+	}
+}
+
+func corruptionAlertEmail(from string, to []string, alert *corruptionAlert) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+
+	if alert.Divergence != nil {
+		fmt.Fprintf(&b, "Subject: immudb: database '%s' diverged from its primary\r\n", alert.Database)
+		fmt.Fprintf(&b, "\r\n")
+		fmt.Fprintf(&b, "Replica database '%s' of server %s could not be proven consistent with its primary, %s, at %s.\r\n",
+			alert.Database, alert.Server, alert.Divergence.Primary, alert.DetectedAt.UTC().Format(time.RFC3339))
+		fmt.Fprintf(&b, "The replica is at tx %d and the primary at tx %d.\r\n", alert.Divergence.ReplicaTx, alert.Divergence.PrimaryTx)
+		if alert.Divergence.DivergentTx > 0 {
+			fmt.Fprintf(&b, "The first divergent transaction is tx %d, with hash %x on the replica and %x on the primary.\r\n",
+				alert.Divergence.DivergentTx, alert.Divergence.ReplicaTxHash, alert.Divergence.PrimaryTxHash)
+		}
+		fmt.Fprintf(&b, "Replication to the replica is stopped until it is consistent again with its primary.\r\n")
+		fmt.Fprintf(&b, "Both databases should be checked manually.\r\n")
+
+		return b.Bytes()
+	}
+
+	fmt.Fprintf(&b, "Subject: immudb: corruption detected in database '%s'\r\n", alert.Database)
+	fmt.Fprintf(&b, "\r\n")
+	fmt.Fprintf(&b, "The corruption checker of server %s detected tampering in database '%s' at %s.\r\n",
+		alert.Server, alert.Database, alert.DetectedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "%d transactions between tx %d and tx %d could not be verified.\r\n",
+		alert.FailedVerifications, alert.FirstTx, alert.LastTx)
+	if alert.Quarantined {
+		fmt.Fprintf(&b, "The database is quarantined, writes are rejected until the corruption is acknowledged.\r\n")
+	}
+	fmt.Fprintf(&b, "The database is marked as degraded and should be checked manually.\r\n")
+
+	return b.Bytes()
+}
@@ -17,9 +17,414 @@ limitations under the License.
 package server
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
 	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
 )
 
+type corruptedDB struct {
+	database.DB
+	failedVerifications uint64
+}
+
+func (db *corruptedDB) VerifyTxs(sinceTx uint64, limit int) (*database.TxsVerification, error) {
+	verification, err := db.DB.VerifyTxs(sinceTx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if verification.TxCount > 0 {
+		verification.FailedVerifications = db.failedVerifications
+	}
+
+	return verification, nil
+}
+
+func TestServerCorruptionChecker(t *testing.T) {
+	var alertsMux sync.Mutex
+	var alerts []*corruptionAlert
+
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert corruptionAlert
+
+		err := json.NewDecoder(r.Body).Decode(&alert)
+		require.NoError(t, err)
+
+		alertsMux.Lock()
+		alerts = append(alerts, &alert)
+		alertsMux.Unlock()
+	}))
+	defer webhook.Close()
+
+	options := DefaultOptions().
+		WithDir("data_corruption_checker").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithCorruptionCheckInterval(1).
+		WithCorruptionAlertOptions(DefaultCorruptionAlertOptions().WithWebhookURLs([]string{webhook.URL}))
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	db := &corruptedDB{DB: s.dbList.GetByIndex(defaultDbIndex)}
+
+	s.dbList = database.NewDatabaseList()
+	s.dbList.Append(db)
+
+	// databases without the corruption checker are not verified
+	db.failedVerifications = 1
+	s.checkDatabases(nil)
+	require.Empty(t, s.degradedDatabases())
+
+	db.UpdateCorruptionChecker(true)
+
+	db.failedVerifications = 0
+	s.checkDatabases(nil)
+	require.Empty(t, s.degradedDatabases())
+	require.NotZero(t, s.checkedUntil[DefaultdbName])
+
+	health, err := s.Health(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	require.True(t, health.Status)
+
+	db.failedVerifications = 2
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.NoError(t, err)
+
+	s.startCorruptionChecker()
+
+	require.Eventually(t, func() bool {
+		return s.isDegraded(DefaultdbName)
+	}, 10*time.Second, 100*time.Millisecond)
+
+	s.stopCorruptionChecker()
+	s.stopCorruptionChecker()
+
+	// degraded databases are not verified again, so the alert is sent once
+	s.checkDatabases(nil)
+
+	alertsMux.Lock()
+	require.Len(t, alerts, 1)
+	require.Equal(t, DefaultdbName, alerts[0].Database)
+	require.Equal(t, uint64(2), alerts[0].FailedVerifications)
+	require.Equal(t, s.UUID.String(), alerts[0].Server)
+	alertsMux.Unlock()
+
+	require.Equal(t, float64(1), testutil.ToFloat64(Metrics.DBCorruptedGauges.WithLabelValues(DefaultdbName)))
+
+	health, err = s.Health(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	require.False(t, health.Status)
+	require.Equal(t, []string{DefaultdbName}, health.DegradedDatabases)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
+
+func TestServerCorruptionCheckerResume(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_corruption_checker_resume").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	db := s.dbList.GetByIndex(defaultDbIndex)
+	db.UpdateCorruptionChecker(true)
+
+	for i := 0; i < 3; i++ {
+		_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte(fmt.Sprintf("key%d", i)), Value: []byte("value")}}})
+		require.NoError(t, err)
+	}
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+
+	s.checkDatabases(nil)
+
+	checkpoint, err := s.loadCorruptionCheckpoint(DefaultdbName)
+	require.NoError(t, err)
+	require.NotNil(t, checkpoint)
+	require.Equal(t, state.TxId, checkpoint.TxID)
+	require.Equal(t, state.TxHash, checkpoint.Alh)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key3"), Value: []byte("value")}}})
+	require.NoError(t, err)
+
+	// after a restart only the transactions committed since the checkpoint are verified
+	s.checkedUntil = make(map[string]uint64)
+	s.checkDatabases(nil)
+
+	require.Equal(t, state.TxId+1, s.lastScans[DefaultdbName].firstTx)
+	require.Equal(t, uint64(1), s.lastScans[DefaultdbName].txCount)
+
+	checkpoint, err = s.loadCorruptionCheckpoint(DefaultdbName)
+	require.NoError(t, err)
+	require.Equal(t, state.TxId+1, checkpoint.TxID)
+
+	// a checkpoint not matching the database is discarded
+	err = s.saveCorruptionCheckpoint(DefaultdbName, state.TxId, sha256.Sum256(nil))
+	require.NoError(t, err)
+
+	s.checkedUntil = make(map[string]uint64)
+	s.checkDatabases(nil)
+
+	require.Equal(t, uint64(1), s.lastScans[DefaultdbName].firstTx)
+	require.Equal(t, state.TxId+1, s.lastScans[DefaultdbName].txCount)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
+
+func TestServerUpdateCorruptionChecker(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_update_corruption_checker").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithCorruptionCheckInterval(0).
+		WithCorruptionCheckIORateLimit(100)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	s.startCorruptionChecker()
+	require.Nil(t, s.checkerDone)
+	require.Equal(t, uint64(100), s.corruptionCheckIORateLimit())
+
+	_, err = s.UpdateCorruptionChecker(context.Background(), nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = s.UpdateCorruptionChecker(context.Background(), &schema.UpdateCorruptionCheckerRequest{})
+	require.Error(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	settings, err := s.UpdateCorruptionChecker(ctx, &schema.UpdateCorruptionCheckerRequest{})
+	require.NoError(t, err)
+	require.Zero(t, settings.Interval)
+	require.Equal(t, uint64(100), settings.IoRateLimit)
+
+	settings, err = s.UpdateCorruptionChecker(ctx, &schema.UpdateCorruptionCheckerRequest{
+		Interval:    &schema.NullableUint32{Value: 60},
+		IoRateLimit: &schema.NullableUint64{Value: 0},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(60), settings.Interval)
+	require.Zero(t, settings.IoRateLimit)
+	require.NotNil(t, s.checkerDone)
+
+	// the checker is restarted only when the interval changes
+	checkerDone := s.checkerDone
+
+	_, err = s.UpdateCorruptionChecker(ctx, &schema.UpdateCorruptionCheckerRequest{Interval: &schema.NullableUint32{Value: 60}})
+	require.NoError(t, err)
+	require.Equal(t, checkerDone, s.checkerDone)
+
+	settings, err = s.UpdateCorruptionChecker(ctx, &schema.UpdateCorruptionCheckerRequest{Interval: &schema.NullableUint32{Value: 0}})
+	require.NoError(t, err)
+	require.Zero(t, settings.Interval)
+	require.Nil(t, s.checkerDone)
+
+	s.stopCorruptionChecker()
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
+
+func TestServerCorruptionCheckStatus(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_corruption_check_status").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithCorruptionCheckInterval(0)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	_, err = s.CorruptionCheckStatus(context.Background(), nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = s.CorruptionCheckStatus(context.Background(), &schema.CorruptionCheckStatusRequest{})
+	require.Error(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	for i := 0; i < 3; i++ {
+		_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+		require.NoError(t, err)
+	}
+
+	res, err := s.CorruptionCheckStatus(ctx, &schema.CorruptionCheckStatusRequest{Database: DefaultdbName})
+	require.NoError(t, err)
+	require.Len(t, res.Databases, 1)
+	require.False(t, res.Databases[0].Enabled)
+	require.Nil(t, res.Databases[0].LastScan)
+	require.Nil(t, res.Databases[0].RunningScan)
+
+	db := s.dbList.GetByIndex(defaultDbIndex)
+	db.UpdateCorruptionChecker(true)
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+
+	verifiedEntries := testutil.ToFloat64(Metrics.VerifiedEntriesCounters.WithLabelValues(DefaultdbName, verificationSourceCorruptionChecker))
+
+	s.checkDatabases(nil)
+
+	res, err = s.CorruptionCheckStatus(ctx, &schema.CorruptionCheckStatusRequest{Database: DefaultdbName})
+	require.NoError(t, err)
+	require.True(t, res.Databases[0].Enabled)
+	require.False(t, res.Databases[0].Degraded)
+
+	scan := res.Databases[0].LastScan
+	require.NotNil(t, scan)
+	require.NotZero(t, scan.StartedAt)
+	require.GreaterOrEqual(t, scan.EndedAt, scan.StartedAt)
+	require.Equal(t, uint64(1), scan.FirstTx)
+	require.Equal(t, state.TxId, scan.LastTx)
+	require.Equal(t, state.TxId, scan.TargetTx)
+	require.Equal(t, state.TxId, scan.TxCount)
+	require.GreaterOrEqual(t, scan.EntriesCount, uint64(3))
+	require.Empty(t, scan.FailedTxs)
+
+	require.Equal(t, verifiedEntries+float64(scan.EntriesCount), testutil.ToFloat64(Metrics.VerifiedEntriesCounters.WithLabelValues(DefaultdbName, verificationSourceCorruptionChecker)))
+	require.Equal(t, float64(scan.EndedAt), testutil.ToFloat64(Metrics.LastSuccessfulScanAtGauges.WithLabelValues(DefaultdbName)))
+	require.NotZero(t, testutil.CollectAndCount(Metrics.ScanDurationHistograms))
+
+	s.degradedMux.Lock()
+	s.runningScans[DefaultdbName] = &corruptionScan{startedAt: time.Now(), lastTx: 2, targetTx: 5, failedTxs: []uint64{2}}
+	s.degradedMux.Unlock()
+
+	res, err = s.CorruptionCheckStatus(ctx, &schema.CorruptionCheckStatusRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Databases, 1)
+	require.Equal(t, DefaultdbName, res.Databases[0].Database)
+	require.NotNil(t, res.Databases[0].RunningScan)
+	require.Zero(t, res.Databases[0].RunningScan.EndedAt)
+	require.Equal(t, uint64(5), res.Databases[0].RunningScan.TargetTx)
+	require.Equal(t, []uint64{2}, res.Databases[0].RunningScan.FailedTxs)
+
+	_, err = s.CorruptionCheckStatus(ctx, &schema.CorruptionCheckStatusRequest{Database: "nodb"})
+	require.Error(t, err)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
+
+func TestThrottleCorruptionCheck(t *testing.T) {
+	s := DefaultServer()
+
+	require.True(t, s.throttleCorruptionCheck(1<<30, nil))
+
+	s.setCorruptionCheckIORateLimit(1000)
+
+	start := time.Now()
+	require.True(t, s.throttleCorruptionCheck(100, nil))
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(100*time.Millisecond))
+
+	done := make(chan struct{})
+	close(done)
+
+	require.False(t, s.throttleCorruptionCheck(1<<30, done))
+	require.False(t, s.throttleCorruptionCheck(0, done))
+}
+
+func TestCorruptionAlertEmail(t *testing.T) {
+	msg := string(corruptionAlertEmail("immudb@example.com", []string{"ops@example.com", "sec@example.com"}, &corruptionAlert{
+		Server:              "server1",
+		Database:            "db1",
+		DetectedAt:          time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC),
+		FailedVerifications: 3,
+		FirstTx:             1,
+		LastTx:              10,
+	}))
+
+	require.Contains(t, msg, "From: immudb@example.com\r\n")
+	require.Contains(t, msg, "To: ops@example.com, sec@example.com\r\n")
+	require.Contains(t, msg, "Subject: immudb: corruption detected in database 'db1'\r\n")
+	require.Contains(t, msg, "server server1 detected tampering in database 'db1' at 2021-09-01T10:00:00Z")
+	require.Contains(t, msg, "3 transactions between tx 1 and tx 10")
+}
+
+func TestReplicaDivergenceAlertEmail(t *testing.T) {
+	msg := string(corruptionAlertEmail("immudb@example.com", []string{"ops@example.com"}, &corruptionAlert{
+		Server:     "server1",
+		Database:   "db1",
+		DetectedAt: time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC),
+		Divergence: &replicaDivergence{Primary: "database 'db1' at primary:3322", ReplicaTx: 8, PrimaryTx: 10},
+	}))
+
+	require.Contains(t, msg, "Subject: immudb: database 'db1' diverged from its primary\r\n")
+	require.Contains(t, msg, "could not be proven consistent with its primary, database 'db1' at primary:3322, at 2021-09-01T10:00:00Z")
+	require.Contains(t, msg, "The replica is at tx 8 and the primary at tx 10")
+}
+
 /*
 import (
 	"testing"
@@ -0,0 +1,174 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/golang/protobuf/ptypes/empty"
+)
+
+// quarantineRecord is the quarantine state of a database, as stored in the system database.
+// Every change is written as a new revision of the same key, so the history of the key is the quarantine log of the database
+type quarantineRecord struct {
+	Quarantined    bool      `json:"quarantined"`
+	QuarantinedAt  time.Time `json:"quarantinedAt"`
+	FailedTxs      []uint64  `json:"failedTxs,omitempty"`
+	AcknowledgedBy string    `json:"acknowledgedBy,omitempty"`
+	AcknowledgedAt time.Time `json:"acknowledgedAt,omitempty"`
+}
+
+func quarantineKey(database string) []byte {
+	key := make([]byte, 1+len(database))
+	key[0] = KeyPrefixQuarantine
+	copy(key[1:], []byte(database))
+	return key
+}
+
+func (s *ImmuServer) saveQuarantineRecord(database string, record *quarantineRecord) error {
+	recordData, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sysDB.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: quarantineKey(database), Value: recordData}}})
+
+	return err
+}
+
+// loadQuarantineRecord returns the last quarantine state of the database, or nil if it was never quarantined
+func (s *ImmuServer) loadQuarantineRecord(database string) (*quarantineRecord, error) {
+	e, err := s.sysDB.Get(&schema.KeyRequest{Key: quarantineKey(database)})
+	if err == store.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record quarantineRecord
+
+	err = json.Unmarshal(e.Value, &record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// quarantineDatabase rejects writes to the database until the corruption is acknowledged, also after a restart
+func (s *ImmuServer) quarantineDatabase(db database.DB, verification *database.TxsVerification) {
+	db.Quarantine(true)
+
+	err := s.saveQuarantineRecord(db.GetName(), &quarantineRecord{
+		Quarantined:   true,
+		QuarantinedAt: time.Now(),
+		FailedTxs:     verification.FailedTxs,
+	})
+	if err != nil {
+		s.Logger.Errorf("Unable to persist the quarantine of database '%s', it will accept writes again after a restart: %v", db.GetName(), err)
+	}
+
+	s.Logger.Warningf("Database '%s' quarantined, writes are rejected until the corruption is acknowledged", db.GetName())
+}
+
+// restoreQuarantine quarantines again a database being loaded if its corruption was not acknowledged yet
+func (s *ImmuServer) restoreQuarantine(db database.DB) error {
+	record, err := s.loadQuarantineRecord(db.GetName())
+	if err != nil {
+		return fmt.Errorf("could not read the quarantine state of database '%s': %w", db.GetName(), err)
+	}
+
+	if record == nil || !record.Quarantined {
+		return nil
+	}
+
+	db.Quarantine(true)
+
+	s.degradedMux.Lock()
+	s.degraded[db.GetName()] = record.QuarantinedAt
+	s.degradedMux.Unlock()
+
+	Metrics.DBCorruptedGauges.WithLabelValues(db.GetName()).Set(1)
+
+	s.Logger.Warningf("Database '%s' is quarantined since %s, writes are rejected until the corruption is acknowledged",
+		db.GetName(), record.QuarantinedAt.UTC().Format(time.RFC3339))
+
+	return nil
+}
+
+// AcknowledgeCorruption lifts the quarantine of a database where corruption was detected, so it accepts writes again.
+// The database is no longer considered degraded, so it gets verified again by the corruption checker:
+// unless its damaged transactions got repaired meanwhile, e.g. through Scrub, it will be quarantined again
+func (s *ImmuServer) AcknowledgeCorruption(ctx context.Context, req *schema.AcknowledgeCorruptionRequest) (*empty.Empty, error) {
+	if req == nil || req.Database == "" {
+		return nil, ErrIllegalArguments
+	}
+
+	if !s.Options.GetAuth() {
+		return nil, ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin {
+		return nil, fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+
+	db, err := s.dbList.GetByName(req.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	if !db.IsQuarantined() {
+		return nil, ErrNotQuarantined
+	}
+
+	record, err := s.loadQuarantineRecord(db.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	if record == nil {
+		record = &quarantineRecord{}
+	}
+
+	record.Quarantined = false
+	record.AcknowledgedBy = user.Username
+	record.AcknowledgedAt = time.Now()
+
+	err = s.saveQuarantineRecord(db.GetName(), record)
+	if err != nil {
+		return nil, err
+	}
+
+	db.Quarantine(false)
+	s.clearDegraded(db.GetName())
+
+	s.Logger.Infof("Corruption of database '%s' acknowledged by %s, writes are accepted again", db.GetName(), user.Username)
+
+	return &empty.Empty{}, nil
+}
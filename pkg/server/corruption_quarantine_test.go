@@ -0,0 +1,140 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServerCorruptionQuarantine(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_corruption_quarantine").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithCorruptionPolicy(CorruptionPolicyQuarantine)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	db := &corruptedDB{DB: s.dbList.GetByIndex(defaultDbIndex), failedVerifications: 1}
+	db.UpdateCorruptionChecker(true)
+
+	s.dbList = database.NewDatabaseList()
+	s.dbList.Append(db)
+
+	s.checkDatabases(nil)
+	require.True(t, s.isDegraded(DefaultdbName))
+	require.True(t, db.IsQuarantined())
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.Equal(t, database.ErrQuarantined, err)
+
+	// reads are still served
+	entry, err := s.Get(ctx, &schema.KeyRequest{Key: []byte("key1")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), entry.Value)
+
+	status, err := s.CorruptionCheckStatus(ctx, &schema.CorruptionCheckStatusRequest{Database: DefaultdbName})
+	require.NoError(t, err)
+	require.True(t, status.Databases[0].Quarantined)
+
+	// the quarantine is kept across restarts
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+
+	s = DefaultServer().WithOptions(options.WithListener(bufconn.Listen(1024 * 1024))).(*ImmuServer)
+
+	err = s.Initialize()
+	require.NoError(t, err)
+
+	require.True(t, s.dbList.GetByIndex(defaultDbIndex).IsQuarantined())
+	require.True(t, s.isDegraded(DefaultdbName))
+
+	lr, err = s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.AcknowledgeCorruption(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = s.AcknowledgeCorruption(context.Background(), &schema.AcknowledgeCorruptionRequest{Database: DefaultdbName})
+	require.Error(t, err)
+
+	_, err = s.AcknowledgeCorruption(ctx, &schema.AcknowledgeCorruptionRequest{Database: DefaultdbName})
+	require.NoError(t, err)
+	require.False(t, s.isDegraded(DefaultdbName))
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.NoError(t, err)
+
+	_, err = s.AcknowledgeCorruption(ctx, &schema.AcknowledgeCorruptionRequest{Database: DefaultdbName})
+	require.Equal(t, ErrNotQuarantined, err)
+
+	record, err := s.loadQuarantineRecord(DefaultdbName)
+	require.NoError(t, err)
+	require.False(t, record.Quarantined)
+	require.Equal(t, auth.SysAdminUsername, record.AcknowledgedBy)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
+
+func TestServerInvalidCorruptionPolicy(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_invalid_corruption_policy").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithCorruptionPolicy("shutdown")
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.Equal(t, ErrInvalidCorruptionPolicy, err)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/backup"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/codenotary/immudb/pkg/stream"
+)
+
+// Backup takes an archive of a database while it keeps serving reads and writes.
+// The archive holds all the transactions committed when the backup starts, or up to untilTx when provided.
+// Incremental backups only hold the ones committed after sinceTx. It's streamed back or, when a path is provided,
+// written into the server backup storage
+func (s *ImmuServer) Backup(req *schema.BackupRequest, backupServer schema.ImmuService_BackupServer) error {
+	if req == nil || backupServer == nil {
+		return ErrIllegalArguments
+	}
+
+	if !s.Options.GetAuth() {
+		return ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(backupServer.Context())
+	if err != nil {
+		return fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin && !user.HasPermission(req.DatabaseName, auth.PermissionAdmin) {
+		return fmt.Errorf("you do not have permission on this database")
+	}
+
+	// files are written on the server host
+	if req.Path != "" && !user.IsSysAdmin {
+		return fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+
+	if req.DatabaseName == SystemdbName {
+		return ErrReservedDatabase
+	}
+
+	db, err := s.dbList.GetByName(req.DatabaseName)
+	if err != nil {
+		return err
+	}
+
+	state, err := db.CurrentState()
+	if err != nil {
+		return err
+	}
+
+	untilTx := state.TxId
+
+	if req.UntilTx > 0 {
+		if req.UntilTx > state.TxId {
+			return ErrIllegalArguments
+		}
+		untilTx = req.UntilTx
+	}
+
+	if req.SinceTx > untilTx {
+		return ErrIllegalArguments
+	}
+
+	if req.Path != "" {
+		_, err = s.backupToStorage(backupServer.Context(), db, req.Path, req.SinceTx, untilTx)
+		return err
+	}
+
+	cw := stream.NewChunkWriter(backupServer, s.Options.StreamChunkSize)
+
+	_, err = s.writeArchive(db, cw, req.SinceTx, untilTx)
+	if err != nil {
+		return err
+	}
+
+	return cw.Close()
+}
+
+// backupToStorage writes the archive into the backup storage, discarding it on failure
+func (s *ImmuServer) backupToStorage(ctx context.Context, db database.DB, name string, sinceTx, untilTx uint64) (*backup.Trailer, error) {
+	_, err := backup.CleanName(name)
+	if err != nil {
+		return nil, ErrIllegalArguments
+	}
+
+	upload, err := s.backupStorage.Create(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var w io.Writer = upload
+	var ew io.WriteCloser
+
+	if s.backupKeys != nil {
+		ew, err = backup.NewEncryptingWriter(upload, s.backupKeys)
+		if err != nil {
+			upload.Abort()
+			return nil, err
+		}
+		w = ew
+	}
+
+	trailer, err := s.writeArchive(db, w, sinceTx, untilTx)
+	if err == nil && ew != nil {
+		// seals the last chunk of the encrypted archive
+		err = ew.Close()
+	}
+	if err != nil {
+		upload.Abort()
+		return nil, err
+	}
+
+	err = upload.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	s.Logger.Infof("Database '%s' backed up from tx %d up to tx %d into '%s' at %s", db.GetOptions().GetDbName(), sinceTx+1, trailer.TxId, name, s.backupStorage)
+
+	return trailer, nil
+}
+
+func (s *ImmuServer) createBackupStorageInstance() (backup.Storage, error) {
+	if s.Options.BackupStorageOptions.S3Storage {
+		return backup.OpenS3Storage(
+			s.Options.BackupStorageOptions.S3Endpoint,
+			s.Options.BackupStorageOptions.S3AccessKeyID,
+			s.Options.BackupStorageOptions.S3SecretKey,
+			s.Options.BackupStorageOptions.S3BucketName,
+			s.Options.BackupStorageOptions.S3PathPrefix,
+		)
+	}
+
+	return backup.NewLocalStorage(s.Options.GetBackupDir()), nil
+}
+
+// createBackupKeyProvider loads the key used to encrypt server-side backups, if any
+func (s *ImmuServer) createBackupKeyProvider() (backup.KeyProvider, error) {
+	if s.Options.BackupEncryptionKey == "" {
+		return nil, nil
+	}
+
+	return backup.NewFileKeyProvider(s.Options.BackupEncryptionKey)
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/backup"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func readArchive(t *testing.T, r io.Reader) *backup.Reader {
+	ar, err := backup.NewReader(r)
+	require.NoError(t, err)
+
+	for {
+		_, err = ar.Next()
+		if err == io.EOF {
+			return ar
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestServerBackup(t *testing.T) {
+	serverOptions := DefaultOptions().
+		WithDir("data_backup").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	err = s.Backup(nil, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	db, err := s.dbList.GetByName(DefaultdbName)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte{byte(i)}}}})
+		require.NoError(t, err)
+	}
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: SystemdbName}, &immuServiceExportDatabaseServer{ctx: adminCtx})
+	require.Equal(t, ErrReservedDatabase, err)
+
+	// writes keep being served while the backup is taken
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			_, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte{byte(i)}}}})
+			require.NoError(t, err)
+		}
+	}()
+
+	backupServer := &immuServiceExportDatabaseServer{ctx: adminCtx}
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName}, backupServer)
+	require.NoError(t, err)
+
+	wg.Wait()
+
+	ar := readArchive(t, bytes.NewReader(backupServer.buf.Bytes()))
+	require.Equal(t, DefaultdbName, ar.Header().Database)
+	require.GreaterOrEqual(t, ar.Trailer().TxId, uint64(10))
+
+	tx, err := db.TxByID(&schema.TxRequest{Tx: ar.Trailer().TxId})
+	require.NoError(t, err)
+
+	alh := schema.TxMetadataFrom(tx.Metadata).Alh()
+	require.Equal(t, alh[:], ar.Trailer().TxHash)
+
+	// server-side backups
+	for _, path := range []string{"", "/tmp/backup", "../backup", "dir/../../backup"} {
+		err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName, Path: path}, &immuServiceExportDatabaseServer{ctx: adminCtx})
+		if path == "" {
+			require.NoError(t, err)
+		} else {
+			require.Equal(t, ErrIllegalArguments, err)
+		}
+	}
+
+	fileServer := &immuServiceExportDatabaseServer{ctx: adminCtx}
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName, Path: "nightly/defaultdb.bak"}, fileServer)
+	require.NoError(t, err)
+	require.Zero(t, fileServer.buf.Len())
+
+	f, err := os.Open(filepath.Join(s.Options.Dir, backupDirName, "nightly", "defaultdb.bak"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+
+	ar = readArchive(t, f)
+	require.Equal(t, state.TxId, ar.Trailer().TxId)
+
+	_, err = os.Stat(filepath.Join(s.Options.Dir, backupDirName, "nightly", "defaultdb.bak.tmp"))
+	require.True(t, os.IsNotExist(err))
+
+	// the backup dir is not loaded as a database
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+
+	s = DefaultServer().WithOptions(serverOptions.WithListener(bufconn.Listen(1024 * 1024))).(*ImmuServer)
+
+	err = s.Initialize()
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), s.dbList.GetId(backupDirName))
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
+
+func TestServerIncrementalBackup(t *testing.T) {
+	serverOptions := DefaultOptions().
+		WithDir("data_incremental_backup").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	db, err := s.dbList.GetByName(DefaultdbName)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte{byte(i)}}}})
+		require.NoError(t, err)
+	}
+
+	fullServer := &immuServiceExportDatabaseServer{ctx: adminCtx}
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName}, fullServer)
+	require.NoError(t, err)
+
+	full := readArchive(t, bytes.NewReader(fullServer.buf.Bytes()))
+	require.False(t, full.Header().IsIncremental())
+
+	for i := 0; i < 3; i++ {
+		_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte{byte(i)}}}})
+		require.NoError(t, err)
+	}
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName, SinceTx: state.TxId + 1}, &immuServiceExportDatabaseServer{ctx: adminCtx})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	incServer := &immuServiceExportDatabaseServer{ctx: adminCtx}
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName, SinceTx: full.Trailer().TxId}, incServer)
+	require.NoError(t, err)
+
+	inc := readArchive(t, bytes.NewReader(incServer.buf.Bytes()))
+	require.True(t, inc.Header().IsIncremental())
+	require.Equal(t, full.Trailer().TxId+1, inc.Header().FromTx)
+	require.Equal(t, full.Trailer().TxHash, inc.Header().PrevAlh)
+	require.Equal(t, uint64(3), inc.Trailer().TxCount)
+	require.Equal(t, state.TxId, inc.Trailer().TxId)
+	require.Equal(t, state.TxHash, inc.Trailer().TxHash)
+
+	// no transactions committed since the previous backup
+	emptyServer := &immuServiceExportDatabaseServer{ctx: adminCtx}
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName, SinceTx: state.TxId}, emptyServer)
+	require.NoError(t, err)
+
+	empty := readArchive(t, bytes.NewReader(emptyServer.buf.Bytes()))
+	require.Zero(t, empty.Trailer().TxCount)
+	require.Equal(t, state.TxHash, empty.Trailer().TxHash)
+
+	// archives can be limited to a given transaction
+	err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName, UntilTx: state.TxId + 1}, &immuServiceExportDatabaseServer{ctx: adminCtx})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName, SinceTx: 3, UntilTx: 2}, &immuServiceExportDatabaseServer{ctx: adminCtx})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	untilServer := &immuServiceExportDatabaseServer{ctx: adminCtx}
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName, SinceTx: full.Trailer().TxId, UntilTx: state.TxId - 1}, untilServer)
+	require.NoError(t, err)
+
+	until := readArchive(t, bytes.NewReader(untilServer.buf.Bytes()))
+	require.Equal(t, uint64(2), until.Trailer().TxCount)
+	require.Equal(t, state.TxId-1, until.Trailer().TxId)
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName, SinceTx: full.Trailer().TxId, Path: "defaultdb.inc"}, &immuServiceExportDatabaseServer{ctx: adminCtx})
+	require.NoError(t, err)
+
+	// incremental archives can't be imported as a new database
+	err = s.ImportDatabase(&immuServiceImportDatabaseServer{ctx: adminCtx, archive: incServer.buf.Bytes()})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
+
+func TestServerEncryptedBackup(t *testing.T) {
+	dir := "data_encrypted_backup"
+	defer os.RemoveAll(dir)
+
+	err := os.MkdirAll(dir, 0700)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(dir, "backup.key")
+
+	err = ioutil.WriteFile(keyFile, []byte("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"), 0600)
+	require.NoError(t, err)
+
+	serverOptions := DefaultOptions().
+		WithDir(filepath.Join(dir, "data")).
+		WithBackupDir(filepath.Join(dir, "backups")).
+		WithBackupEncryptionKey(keyFile).
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+
+	err = s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	db, err := s.dbList.GetByName(DefaultdbName)
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("secret-key"), Value: []byte("secret-value")}}})
+	require.NoError(t, err)
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName, Path: "full.bak"}, &immuServiceExportDatabaseServer{ctx: adminCtx})
+	require.NoError(t, err)
+
+	stored, err := ioutil.ReadFile(filepath.Join(dir, "backups", "full.bak"))
+	require.NoError(t, err)
+	require.False(t, bytes.Contains(stored, []byte("secret-value")))
+	require.False(t, bytes.Contains(stored, []byte("IMMUDBAR")))
+
+	// streamed backups are not encrypted
+	streamed := &immuServiceExportDatabaseServer{ctx: adminCtx}
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName}, streamed)
+	require.NoError(t, err)
+	readArchive(t, bytes.NewReader(streamed.buf.Bytes()))
+
+	res, err := s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "restored", Paths: []string{"full.bak"}})
+	require.NoError(t, err)
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, state.TxId, res.TxId)
+
+	// archives can not be restored without the key
+	s.backupKeys = nil
+
+	_, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "nokey", Paths: []string{"full.bak"}})
+	require.Equal(t, backup.ErrEncryptionKeyNotFound, err)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+
+	err = ioutil.WriteFile(keyFile, []byte("invalid"), 0600)
+	require.NoError(t, err)
+
+	s = DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	require.Error(t, s.Initialize())
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+)
+
+// CloneDatabase creates a new database holding a copy of an existing one, optionally up to a given transaction.
+// Transactions are replicated as they are, so the clone shares the same history and state up to the cloned one.
+// The clone keeps the store settings of the source database but it's neither a replica nor read-only
+func (s *ImmuServer) CloneDatabase(ctx context.Context, req *schema.CloneDatabaseRequest) (*schema.CloneDatabaseResponse, error) {
+	s.Logger.Debugf("clonedatabase")
+
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	if !s.Options.GetAuth() {
+		return nil, ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin {
+		return nil, fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+
+	err = s.validateNewDatabaseName(req.DstDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	srcDB, err := s.dbList.GetByName(req.SrcDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := srcDB.CurrentState()
+	if err != nil {
+		return nil, err
+	}
+
+	untilTx := req.UntilTx
+	if untilTx == 0 {
+		untilTx = state.TxId
+	}
+
+	if untilTx > state.TxId {
+		return nil, ErrIllegalArguments
+	}
+
+	settings, err := s.loadSettings(req.SrcDatabase)
+	if err == store.ErrKeyNotFound {
+		settings = &dbSettings{}
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = s.replayIntoNewDatabase(settings.derive(req.DstDatabase, user.Username), func(dstDB database.DB) error {
+		return replicateTxs(srcDB, dstDB, untilTx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Logger.Infof("Database '%s' cloned from '%s' up to tx %d", req.DstDatabase, req.SrcDatabase, untilTx)
+
+	return &schema.CloneDatabaseResponse{
+		DatabaseName: req.DstDatabase,
+		TxId:         untilTx,
+	}, nil
+}
+
+func replicateTxs(srcDB, dstDB database.DB, untilTx uint64) error {
+	for txID := uint64(1); txID <= untilTx; txID++ {
+		etx, err := srcDB.ExportTxByID(&schema.TxRequest{Tx: txID})
+		if err != nil {
+			return err
+		}
+
+		_, err = dstDB.ReplicateTx(etx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// derive returns the settings of a new database holding the same transactions,
+// which is neither a replica nor read-only
+func (s *dbSettings) derive(database, createdBy string) *dbSettings {
+	return &dbSettings{
+		Database:             database,
+		ValueTransformers:    s.ValueTransformers,
+		RetainOriginalDigest: s.RetainOriginalDigest,
+		ValueCompression:     s.ValueCompression,
+		CorruptionChecker:    s.CorruptionChecker,
+		StoreSettings:        s.StoreSettings,
+		MaxDiskSize:          s.MaxDiskSize,
+		ParanoidReads:        s.ParanoidReads,
+		CreatedBy:            createdBy,
+		CreatedAt:            time.Now(),
+	}
+}
+
+func (s *ImmuServer) validateNewDatabaseName(name string) error {
+	if name == SystemdbName {
+		return ErrReservedDatabase
+	}
+
+	if strings.ToLower(name) != name {
+		return fmt.Errorf("provide a lowercase database name")
+	}
+
+	err := isValidDBName(name)
+	if err != nil {
+		return err
+	}
+
+	if s.dbList.GetId(name) >= 0 {
+		return fmt.Errorf("database %s already exists", name)
+	}
+
+	return nil
+}
+
+// replayIntoNewDatabase creates a new database as a replica so existing transactions can be replicated into it.
+// Once replay succeeds, the database gets reopened with its own settings, which are then persisted.
+// Partially created databases are removed on failure
+func (s *ImmuServer) replayIntoNewDatabase(settings *dbSettings, replay func(db database.DB) error) error {
+	dir := s.OS.Join(s.Options.Dir, settings.Database)
+
+	op := database.DefaultOption().
+		WithDbName(settings.Database).
+		WithDbRootPath(s.Options.Dir).
+		WithStoreOptions(settings.StoreSettings.apply(s.storeOptionsForDb(settings.Database, s.remoteStorage))).
+		WithReplicationOptions(&database.ReplicationOptions{Replica: true}).
+		WithValueTransformers(settings.ValueTransformers).
+		WithRetainOriginalDigest(settings.RetainOriginalDigest).
+		WithValueCompression(settings.ValueCompression)
+
+	db, err := database.NewDb(op, s.sysDB, s.Logger)
+	if err != nil {
+		return err
+	}
+
+	err = replay(db)
+
+	closeErr := db.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		s.removeDatabaseDir(dir)
+		return err
+	}
+
+	op.WithReplicationOptions(settings.replicationOptions()).
+		WithCorruptionChecker(settings.CorruptionChecker).
+		WithMaxDiskSize(settings.MaxDiskSize).
+		WithParanoidReads(settings.ParanoidReads).
+		WithQuotaWarningThreshold(s.Options.QuotaWarningThld).
+		WithSQLStmtCacheSize(s.Options.SQLStmtCacheSize)
+
+	db, err = database.OpenDb(op, s.sysDB, s.Logger)
+	if err != nil {
+		s.removeDatabaseDir(dir)
+		return err
+	}
+
+	err = s.saveSettings(settings)
+	if err != nil {
+		db.Close()
+		s.removeDatabaseDir(dir)
+		return err
+	}
+
+	s.dbList.Append(db)
+	s.multidbmode = true
+
+	return nil
+}
+
+func (s *ImmuServer) removeDatabaseDir(dir string) {
+	err := s.OS.RemoveAll(dir)
+	if err != nil {
+		s.Logger.Warningf("Error removing partially created database '%s': %v", dir, err)
+	}
+}
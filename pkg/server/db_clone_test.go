@@ -0,0 +1,157 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServerCloneDatabase(t *testing.T) {
+	ctx := context.Background()
+
+	serverOptions := DefaultOptions().
+		WithDir("data_clone").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	_, err = s.CloneDatabase(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	lr, err := s.Login(ctx, &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.CreateDatabaseWith(adminCtx, &schema.DatabaseSettings{DatabaseName: "source"})
+	require.NoError(t, err)
+
+	srcDB, err := s.dbList.GetByName("source")
+	require.NoError(t, err)
+
+	_, err = srcDB.SQLExec(&schema.SQLExecRequest{Sql: "CREATE TABLE mytable(id INTEGER, PRIMARY KEY id)"})
+	require.NoError(t, err)
+
+	_, err = srcDB.SQLExec(&schema.SQLExecRequest{Sql: "INSERT INTO mytable(id) VALUES (1)"})
+	require.NoError(t, err)
+
+	md1, err := srcDB.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	_, err = srcDB.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.NoError(t, err)
+
+	srcState, err := srcDB.CurrentState()
+	require.NoError(t, err)
+
+	_, err = s.CloneDatabase(adminCtx, &schema.CloneDatabaseRequest{SrcDatabase: "source", DstDatabase: SystemdbName})
+	require.Equal(t, ErrReservedDatabase, err)
+
+	_, err = s.CloneDatabase(adminCtx, &schema.CloneDatabaseRequest{SrcDatabase: "source", DstDatabase: "Clone"})
+	require.Error(t, err)
+
+	_, err = s.CloneDatabase(adminCtx, &schema.CloneDatabaseRequest{SrcDatabase: "source", DstDatabase: "source"})
+	require.Error(t, err)
+
+	_, err = s.CloneDatabase(adminCtx, &schema.CloneDatabaseRequest{SrcDatabase: "nodb", DstDatabase: "clone"})
+	require.Equal(t, database.ErrDatabaseNotExists, err)
+
+	_, err = s.CloneDatabase(adminCtx, &schema.CloneDatabaseRequest{SrcDatabase: "source", DstDatabase: "clone", UntilTx: srcState.TxId + 1})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	res, err := s.CloneDatabase(adminCtx, &schema.CloneDatabaseRequest{SrcDatabase: "source", DstDatabase: "clone"})
+	require.NoError(t, err)
+	require.Equal(t, "clone", res.DatabaseName)
+	require.Equal(t, srcState.TxId, res.TxId)
+
+	cloneDB, err := s.dbList.GetByName("clone")
+	require.NoError(t, err)
+	require.False(t, cloneDB.IsReplica())
+
+	cloneState, err := cloneDB.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, srcState.TxId, cloneState.TxId)
+	require.Equal(t, srcState.TxHash, cloneState.TxHash)
+
+	e, err := cloneDB.Get(&schema.KeyRequest{Key: []byte("key2"), SinceTx: cloneState.TxId})
+	require.NoError(t, err)
+	require.Equal(t, []byte("value2"), e.Value)
+
+	require.Eventually(t, func() bool {
+		qres, err := cloneDB.SQLQuery(&schema.SQLQueryRequest{Sql: "SELECT id FROM mytable"})
+		return err == nil && len(qres.Rows) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// the clone is writable and independent from the source database
+	_, err = cloneDB.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key3"), Value: []byte("value3")}}})
+	require.NoError(t, err)
+
+	_, err = srcDB.Get(&schema.KeyRequest{Key: []byte("key3")})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	res, err = s.CloneDatabase(adminCtx, &schema.CloneDatabaseRequest{SrcDatabase: "source", DstDatabase: "partial", UntilTx: md1.Id})
+	require.NoError(t, err)
+	require.Equal(t, md1.Id, res.TxId)
+
+	partialDB, err := s.dbList.GetByName("partial")
+	require.NoError(t, err)
+
+	partialState, err := partialDB.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, md1.Id, partialState.TxId)
+
+	_, err = partialDB.Get(&schema.KeyRequest{Key: []byte("key1"), SinceTx: md1.Id})
+	require.NoError(t, err)
+
+	_, err = partialDB.Get(&schema.KeyRequest{Key: []byte("key2")})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	// clones are loaded again on restart
+	s.CloseDatabases()
+
+	s = DefaultServer().WithOptions(serverOptions.WithListener(bufconn.Listen(1024 * 1024))).(*ImmuServer)
+
+	err = s.Initialize()
+	require.NoError(t, err)
+
+	cloneDB, err = s.dbList.GetByName("clone")
+	require.NoError(t, err)
+	require.False(t, cloneDB.IsReplica())
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
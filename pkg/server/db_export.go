@@ -0,0 +1,287 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/backup"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/codenotary/immudb/pkg/stream"
+)
+
+// ExportDatabase streams a self-describing archive holding all the transactions of a database
+// up to its current state, together with the database settings.
+// The state at the end of the archive gets signed when the server is configured with a signing key
+func (s *ImmuServer) ExportDatabase(req *schema.ExportDatabaseRequest, exportServer schema.ImmuService_ExportDatabaseServer) error {
+	if req == nil || exportServer == nil {
+		return ErrIllegalArguments
+	}
+
+	if !s.Options.GetAuth() {
+		return ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(exportServer.Context())
+	if err != nil {
+		return fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin && !user.HasPermission(req.DatabaseName, auth.PermissionAdmin) {
+		return fmt.Errorf("you do not have permission on this database")
+	}
+
+	if req.DatabaseName == SystemdbName {
+		return ErrReservedDatabase
+	}
+
+	db, err := s.dbList.GetByName(req.DatabaseName)
+	if err != nil {
+		return err
+	}
+
+	state, err := db.CurrentState()
+	if err != nil {
+		return err
+	}
+
+	cw := stream.NewChunkWriter(exportServer, s.Options.StreamChunkSize)
+
+	_, err = s.writeArchive(db, cw, 0, state.TxId)
+	if err != nil {
+		return err
+	}
+
+	return cw.Close()
+}
+
+// writeArchive writes an archive holding the transactions of a database after sinceTx and up to untilTx.
+// Committed transactions are immutable, so the archive is consistent even if new transactions get committed meanwhile.
+// Incremental archives record the state they start from, so they can only be applied on top of it
+func (s *ImmuServer) writeArchive(db database.DB, w io.Writer, sinceTx, untilTx uint64) (*backup.Trailer, error) {
+	dbName := db.GetOptions().GetDbName()
+
+	var prevAlh []byte
+
+	if sinceTx > 0 {
+		tx, err := db.TxByID(&schema.TxRequest{Tx: sinceTx})
+		if err != nil {
+			return nil, err
+		}
+
+		alh := schema.TxMetadataFrom(tx.Metadata).Alh()
+		prevAlh = alh[:]
+	}
+
+	settings, err := s.loadSettings(dbName)
+	if err == store.ErrKeyNotFound {
+		settings = &dbSettings{Database: dbName}
+	} else if err != nil {
+		return nil, err
+	}
+
+	settingsBs, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	aw, err := backup.NewWriter(w, &backup.Header{
+		Database:      dbName,
+		FromTx:        sinceTx + 1,
+		UntilTx:       untilTx,
+		PrevAlh:       prevAlh,
+		CreatedAt:     time.Now(),
+		ImmudbVersion: Version.Version,
+		Settings:      settingsBs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = exportTxs(db, aw, sinceTx+1, untilTx)
+	if err != nil {
+		return nil, err
+	}
+
+	trailer := aw.Trailer()
+
+	if s.Options.SigningKey != "" {
+		signedState := &schema.ImmutableState{
+			Db:     dbName,
+			TxId:   trailer.TxId,
+			TxHash: trailer.TxHash,
+		}
+
+		err = s.StateSigner.Sign(signedState)
+		if err != nil {
+			return nil, err
+		}
+
+		trailer.Signature = signedState.Signature
+	}
+
+	return trailer, aw.Close(trailer)
+}
+
+func exportTxs(db database.DB, aw *backup.Writer, fromTx, untilTx uint64) error {
+	for txID := fromTx; txID <= untilTx; txID++ {
+		tx, err := db.TxByID(&schema.TxRequest{Tx: txID})
+		if err != nil {
+			return err
+		}
+
+		etx, err := db.ExportTxByID(&schema.TxRequest{Tx: txID})
+		if err != nil {
+			return err
+		}
+
+		err = aw.WriteTx(&backup.TxRecord{
+			TxID: txID,
+			Ts:   tx.Metadata.Ts,
+			Alh:  schema.TxMetadataFrom(tx.Metadata).Alh(),
+			Tx:   etx,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ImportDatabase creates a new database from an archive produced by ExportDatabase.
+// The database gets the name recorded in the archive and its transactions are replicated
+// as they are, thus the integrity of each of them is verified by the store before being committed
+func (s *ImmuServer) ImportDatabase(importServer schema.ImmuService_ImportDatabaseServer) error {
+	if importServer == nil {
+		return ErrIllegalArguments
+	}
+
+	if s.Options.GetMaintenance() {
+		return ErrNotAllowedInMaintenanceMode
+	}
+
+	if !s.Options.GetAuth() {
+		return ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(importServer.Context())
+	if err != nil {
+		return fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin {
+		return fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+
+	ar, err := backup.NewReader(stream.NewChunkReader(importServer))
+	if err != nil {
+		return err
+	}
+
+	hdr := ar.Header()
+
+	// only full archives can be imported into a new database
+	if hdr.IsIncremental() {
+		return ErrIllegalArguments
+	}
+
+	err = s.validateNewDatabaseName(hdr.Database)
+	if err != nil {
+		return err
+	}
+
+	settings := &dbSettings{}
+
+	if len(hdr.Settings) > 0 {
+		err = json.Unmarshal(hdr.Settings, settings)
+		if err != nil {
+			return backup.ErrInvalidArchive
+		}
+	}
+
+	err = s.replayIntoNewDatabase(settings.derive(hdr.Database, user.Username), func(db database.DB) error {
+		_, _, err := replayArchive(ar, db, 0, 0)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.Logger.Infof("Database '%s' imported up to tx %d", hdr.Database, ar.Trailer().TxId)
+
+	return importServer.SendAndClose(&schema.ImportDatabaseResponse{
+		DatabaseName: hdr.Database,
+		TxId:         ar.Trailer().TxId,
+	})
+}
+
+// replayArchive replicates the transactions of an archive into a database, optionally up to a given
+// transaction or timestamp. Every transaction is verified against the hashes recorded in the archive before
+// being replicated, and the whole archive is read anyway, so its checksum gets verified.
+// It returns the last replicated transaction, if any, and whether replay was stopped before the end of the archive
+func replayArchive(ar *backup.Reader, db database.DB, untilTx uint64, untilTs int64) (lastTx uint64, stopped bool, err error) {
+	// the first transaction is chained to the empty state
+	prevAlh := sha256.Sum256(nil)
+
+	if ar.Header().IsIncremental() {
+		copy(prevAlh[:], ar.Header().PrevAlh)
+	}
+
+	for {
+		rec, err := ar.Next()
+		if err == io.EOF {
+			return lastTx, stopped, nil
+		}
+		if err != nil {
+			return lastTx, stopped, err
+		}
+
+		if stopped {
+			continue
+		}
+
+		if (untilTx > 0 && rec.TxID > untilTx) || (untilTs > 0 && rec.Ts > untilTs) {
+			stopped = true
+			continue
+		}
+
+		err = rec.Verify(prevAlh)
+		if err != nil {
+			return lastTx, stopped, err
+		}
+
+		md, err := db.ReplicateTx(rec.Tx)
+		if err != nil {
+			return lastTx, stopped, err
+		}
+
+		if alh := schema.TxMetadataFrom(md).Alh(); md.Id != rec.TxID || alh != rec.Alh {
+			return lastTx, stopped, &backup.IntegrityError{TxID: rec.TxID, Hash: "restored alh", Expected: rec.Alh[:], Actual: alh[:]}
+		}
+
+		prevAlh = rec.Alh
+		lastTx = rec.TxID
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/backup"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type immuServiceExportDatabaseServer struct {
+	grpc.ServerStream
+	ctx context.Context
+	buf bytes.Buffer
+}
+
+func (s *immuServiceExportDatabaseServer) Send(chunk *schema.Chunk) error {
+	s.buf.Write(chunk.Content)
+	return nil
+}
+
+func (s *immuServiceExportDatabaseServer) Context() context.Context {
+	return s.ctx
+}
+
+type immuServiceImportDatabaseServer struct {
+	grpc.ServerStream
+	ctx     context.Context
+	archive []byte
+	res     *schema.ImportDatabaseResponse
+}
+
+func (s *immuServiceImportDatabaseServer) Recv() (*schema.Chunk, error) {
+	if len(s.archive) == 0 {
+		return nil, io.EOF
+	}
+
+	n := 100
+	if len(s.archive) < n {
+		n = len(s.archive)
+	}
+
+	chunk := &schema.Chunk{Content: s.archive[:n]}
+	s.archive = s.archive[n:]
+
+	return chunk, nil
+}
+
+func (s *immuServiceImportDatabaseServer) SendAndClose(res *schema.ImportDatabaseResponse) error {
+	s.res = res
+	return nil
+}
+
+func (s *immuServiceImportDatabaseServer) Context() context.Context {
+	return s.ctx
+}
+
+func TestServerExportImportDatabase(t *testing.T) {
+	serverOptions := DefaultOptions().
+		WithDir("data_export").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithSigningKey("./../../test/signer/ec1.key").
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	err = s.ExportDatabase(nil, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = s.ImportDatabase(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.CreateDatabaseWith(adminCtx, &schema.DatabaseSettings{DatabaseName: "source"})
+	require.NoError(t, err)
+
+	srcDB, err := s.dbList.GetByName("source")
+	require.NoError(t, err)
+
+	_, err = srcDB.SQLExec(&schema.SQLExecRequest{Sql: "CREATE TABLE mytable(id INTEGER, PRIMARY KEY id)"})
+	require.NoError(t, err)
+
+	_, err = srcDB.SQLExec(&schema.SQLExecRequest{Sql: "INSERT INTO mytable(id) VALUES (1)"})
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, err = srcDB.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte{byte(i)}}}})
+		require.NoError(t, err)
+	}
+
+	srcState, err := srcDB.CurrentState()
+	require.NoError(t, err)
+
+	err = s.ExportDatabase(&schema.ExportDatabaseRequest{DatabaseName: SystemdbName}, &immuServiceExportDatabaseServer{ctx: adminCtx})
+	require.Equal(t, ErrReservedDatabase, err)
+
+	exportServer := &immuServiceExportDatabaseServer{ctx: adminCtx}
+
+	err = s.ExportDatabase(&schema.ExportDatabaseRequest{DatabaseName: "source"}, exportServer)
+	require.NoError(t, err)
+
+	archive := exportServer.buf.Bytes()
+
+	ar, err := backup.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	require.Equal(t, "source", ar.Header().Database)
+	require.Equal(t, srcState.TxId, ar.Header().UntilTx)
+
+	for {
+		_, err = ar.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, srcState.TxHash, ar.Trailer().TxHash)
+	require.NotNil(t, ar.Trailer().Signature)
+
+	// the exported database already exists
+	err = s.ImportDatabase(&immuServiceImportDatabaseServer{ctx: adminCtx, archive: archive})
+	require.Error(t, err)
+
+	_, err = s.DeleteDatabase(adminCtx, &schema.DeleteDatabaseRequest{DatabaseName: "source"})
+	require.NoError(t, err)
+
+	tampered := append([]byte{}, archive...)
+	tampered[len(archive)/2] ^= 0xFF
+
+	err = s.ImportDatabase(&immuServiceImportDatabaseServer{ctx: adminCtx, archive: tampered})
+	require.Error(t, err)
+	require.Equal(t, int64(-1), s.dbList.GetId("source"))
+
+	importServer := &immuServiceImportDatabaseServer{ctx: adminCtx, archive: archive}
+
+	err = s.ImportDatabase(importServer)
+	require.NoError(t, err)
+	require.Equal(t, "source", importServer.res.DatabaseName)
+	require.Equal(t, srcState.TxId, importServer.res.TxId)
+
+	importedDB, err := s.dbList.GetByName("source")
+	require.NoError(t, err)
+	require.False(t, importedDB.IsReplica())
+
+	importedState, err := importedDB.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, srcState.TxHash, importedState.TxHash)
+
+	e, err := importedDB.Get(&schema.KeyRequest{Key: []byte("key"), SinceTx: importedState.TxId})
+	require.NoError(t, err)
+	require.Equal(t, []byte{9}, e.Value)
+
+	require.Eventually(t, func() bool {
+		qres, err := importedDB.SQLQuery(&schema.SQLQueryRequest{Sql: "SELECT id FROM mytable"})
+		return err == nil && len(qres.Rows) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
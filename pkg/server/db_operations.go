@@ -32,6 +32,48 @@ func (s *ImmuServer) CurrentState(ctx context.Context, _ *empty.Empty) (*schema.
 	return state, nil
 }
 
+// ComplianceReport produces a summary of the activity and integrity of the selected database during the requested period.
+// The report gets signed when the server is configured with a signing key
+func (s *ImmuServer) ComplianceReport(ctx context.Context, req *schema.ComplianceReportRequest) (*schema.ComplianceReportResponse, error) {
+	db, err := s.getDBFromCtx(ctx, "ComplianceReport")
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := db.ComplianceReport(req)
+	if err != nil {
+		return nil, err
+	}
+
+	Metrics.UpdateVerificationMetrics(db.GetName(), verificationSourceComplianceReport, report.EntriesCount, report.FailedVerifications)
+
+	if s.Options.SigningKey != "" {
+		err = s.StateSigner.SignComplianceReport(report)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// VerifyRange verifies on demand the requested transactions or key prefix of the selected database
+func (s *ImmuServer) VerifyRange(ctx context.Context, req *schema.VerifyRangeRequest) (*schema.VerifyRangeResponse, error) {
+	db, err := s.getDBFromCtx(ctx, "VerifyRange")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := db.VerifyRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	Metrics.UpdateVerificationMetrics(db.GetName(), verificationSourceVerifyRange, res.EntriesCount, uint64(len(res.FailedTxs)))
+
+	return res, nil
+}
+
 // Set ...
 func (s *ImmuServer) Set(ctx context.Context, kv *schema.SetRequest) (*schema.TxMetadata, error) {
 	if s.Options.GetMaintenance() {
@@ -46,6 +88,76 @@ func (s *ImmuServer) Set(ctx context.Context, kv *schema.SetRequest) (*schema.Tx
 	return db.Set(kv)
 }
 
+// SetConditional writes a key only if it holds the expected value or the value written at the expected transaction
+func (s *ImmuServer) SetConditional(ctx context.Context, req *schema.SetConditionalRequest) (*schema.TxMetadata, error) {
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	db, err := s.getDBFromCtx(ctx, "SetConditional")
+	if err != nil {
+		return nil, err
+	}
+
+	return db.SetConditional(req)
+}
+
+// ExpirableSet ...
+func (s *ImmuServer) ExpirableSet(ctx context.Context, req *schema.ExpirableSetRequest) (*schema.TxMetadata, error) {
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	db, err := s.getDBFromCtx(ctx, "ExpirableSet")
+	if err != nil {
+		return nil, err
+	}
+
+	return db.ExpirableSet(req)
+}
+
+// Append ...
+func (s *ImmuServer) Append(ctx context.Context, req *schema.AppendRequest) (*schema.AppendResponse, error) {
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	db, err := s.getDBFromCtx(ctx, "Append")
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Append(req)
+}
+
+// Delete ...
+func (s *ImmuServer) Delete(ctx context.Context, req *schema.DeleteKeysRequest) (*schema.TxMetadata, error) {
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	db, err := s.getDBFromCtx(ctx, "Delete")
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Delete(req)
+}
+
+// DeletePrefix ...
+func (s *ImmuServer) DeletePrefix(ctx context.Context, req *schema.DeletePrefixRequest) (*schema.TxMetadata, error) {
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	db, err := s.getDBFromCtx(ctx, "DeletePrefix")
+	if err != nil {
+		return nil, err
+	}
+
+	return db.DeletePrefix(req)
+}
+
 // VerifiableSet ...
 func (s *ImmuServer) VerifiableSet(ctx context.Context, req *schema.VerifiableSetRequest) (*schema.VerifiableTx, error) {
 	if s.Options.GetMaintenance() {
@@ -136,6 +248,26 @@ func (s *ImmuServer) Scan(ctx context.Context, req *schema.ScanRequest) (*schema
 	return db.Scan(req)
 }
 
+// OpenSnapshot opens a named read snapshot of the database in use
+func (s *ImmuServer) OpenSnapshot(ctx context.Context, req *schema.OpenSnapshotRequest) (*schema.OpenSnapshotResponse, error) {
+	db, err := s.getDBFromCtx(ctx, "OpenSnapshot")
+	if err != nil {
+		return nil, err
+	}
+
+	return db.OpenSnapshot(req)
+}
+
+// ReleaseSnapshot releases a named read snapshot of the database in use
+func (s *ImmuServer) ReleaseSnapshot(ctx context.Context, req *schema.ReleaseSnapshotRequest) (*empty.Empty, error) {
+	db, err := s.getDBFromCtx(ctx, "ReleaseSnapshot")
+	if err != nil {
+		return nil, err
+	}
+
+	return new(empty.Empty), db.ReleaseSnapshot(req)
+}
+
 // Count ...
 func (s *ImmuServer) Count(ctx context.Context, prefix *schema.KeyPrefix) (*schema.EntryCount, error) {
 	return nil, ErrNotSupported
@@ -199,6 +331,38 @@ func (s *ImmuServer) TxScan(ctx context.Context, req *schema.TxScanRequest) (*sc
 	return db.TxScan(req)
 }
 
+// StateHistory returns the past states of the database, signed when the server has a signing key
+func (s *ImmuServer) StateHistory(ctx context.Context, req *schema.StateHistoryRequest) (*schema.StateList, error) {
+	db, err := s.getDBFromCtx(ctx, "StateHistory")
+	if err != nil {
+		return nil, err
+	}
+
+	stateList, err := db.StateHistory(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Options.SigningKey != "" {
+		for _, hs := range stateList.States {
+			state := &schema.ImmutableState{
+				Db:     stateList.Db,
+				TxId:   hs.TxId,
+				TxHash: hs.TxHash,
+			}
+
+			err = s.StateSigner.Sign(state)
+			if err != nil {
+				return nil, err
+			}
+
+			hs.Signature = state.Signature
+		}
+	}
+
+	return stateList, nil
+}
+
 // History ...
 func (s *ImmuServer) History(ctx context.Context, req *schema.HistoryRequest) (*schema.Entries, error) {
 	db, err := s.getDBFromCtx(ctx, "History")
@@ -260,6 +424,39 @@ func (s *ImmuServer) VerifiableSetReference(ctx context.Context, req *schema.Ver
 	return vtx, nil
 }
 
+// ResolveReferenceHistory ...
+func (s *ImmuServer) ResolveReferenceHistory(ctx context.Context, req *schema.ReferenceHistoryRequest) (*schema.VerifiableReferenceHistory, error) {
+	db, err := s.getDBFromCtx(ctx, "ResolveReferenceHistory")
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := db.ResolveReferenceHistory(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Options.SigningKey != "" {
+		md := schema.TxMetadataFrom(history.VerifiableTx.DualProof.TargetTxMetadata)
+		alh := md.Alh()
+
+		newState := &schema.ImmutableState{
+			Db:     db.GetOptions().GetDbName(),
+			TxId:   md.ID,
+			TxHash: alh[:],
+		}
+
+		err = s.StateSigner.Sign(newState)
+		if err != nil {
+			return nil, err
+		}
+
+		history.VerifiableTx.Signature = newState.Signature
+	}
+
+	return history, nil
+}
+
 // ZAdd ...
 func (s *ImmuServer) ZAdd(ctx context.Context, req *schema.ZAddRequest) (*schema.TxMetadata, error) {
 	if s.Options.GetMaintenance() {
@@ -274,6 +471,20 @@ func (s *ImmuServer) ZAdd(ctx context.Context, req *schema.ZAddRequest) (*schema
 	return db.ZAdd(req)
 }
 
+// ZRem ...
+func (s *ImmuServer) ZRem(ctx context.Context, req *schema.ZRemRequest) (*schema.TxMetadata, error) {
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	db, err := s.getDBFromCtx(ctx, "ZRem")
+	if err != nil {
+		return nil, err
+	}
+
+	return db.ZRem(req)
+}
+
 // ZScan ...
 func (s *ImmuServer) ZScan(ctx context.Context, req *schema.ZScanRequest) (*schema.ZEntries, error) {
 	db, err := s.getDBFromCtx(ctx, "ZScan")
@@ -284,6 +495,102 @@ func (s *ImmuServer) ZScan(ctx context.Context, req *schema.ZScanRequest) (*sche
 	return db.ZScan(req)
 }
 
+// VerifiableZScan ...
+func (s *ImmuServer) VerifiableZScan(ctx context.Context, req *schema.VerifiableZScanRequest) (*schema.VerifiableZEntries, error) {
+	db, err := s.getDBFromCtx(ctx, "VerifiableZScan")
+	if err != nil {
+		return nil, err
+	}
+
+	vEntries, err := db.VerifiableZScan(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Options.SigningKey != "" {
+		md := schema.TxMetadataFrom(vEntries.VerifiableTx.DualProof.TargetTxMetadata)
+		alh := md.Alh()
+
+		newState := &schema.ImmutableState{
+			Db:     db.GetOptions().GetDbName(),
+			TxId:   md.ID,
+			TxHash: alh[:],
+		}
+
+		err = s.StateSigner.Sign(newState)
+		if err != nil {
+			return nil, err
+		}
+
+		vEntries.VerifiableTx.Signature = newState.Signature
+	}
+
+	return vEntries, nil
+}
+
+// MultiInclusion ...
+func (s *ImmuServer) MultiInclusion(ctx context.Context, req *schema.MultiInclusionRequest) (*schema.MultiInclusionResponse, error) {
+	db, err := s.getDBFromCtx(ctx, "MultiInclusion")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := db.MultiInclusion(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Options.SigningKey != "" {
+		md := schema.TxMetadataFrom(res.VerifiableTx.DualProof.TargetTxMetadata)
+		alh := md.Alh()
+
+		newState := &schema.ImmutableState{
+			Db:     db.GetOptions().GetDbName(),
+			TxId:   md.ID,
+			TxHash: alh[:],
+		}
+
+		err = s.StateSigner.Sign(newState)
+		if err != nil {
+			return nil, err
+		}
+
+		res.VerifiableTx.Signature = newState.Signature
+	}
+
+	return res, nil
+}
+
+// ReferencesTo ...
+func (s *ImmuServer) ReferencesTo(ctx context.Context, req *schema.ReferencesToRequest) (*schema.References, error) {
+	db, err := s.getDBFromCtx(ctx, "ReferencesTo")
+	if err != nil {
+		return nil, err
+	}
+
+	return db.ReferencesTo(req)
+}
+
+// ZCard ...
+func (s *ImmuServer) ZCard(ctx context.Context, req *schema.ZCardRequest) (*schema.EntryCount, error) {
+	db, err := s.getDBFromCtx(ctx, "ZCard")
+	if err != nil {
+		return nil, err
+	}
+
+	return db.ZCard(req)
+}
+
+// ZCount ...
+func (s *ImmuServer) ZCount(ctx context.Context, req *schema.ZCountRequest) (*schema.EntryCount, error) {
+	db, err := s.getDBFromCtx(ctx, "ZCount")
+	if err != nil {
+		return nil, err
+	}
+
+	return db.ZCount(req)
+}
+
 // VerifiableZAdd ...
 func (s *ImmuServer) VerifiableZAdd(ctx context.Context, req *schema.VerifiableZAddRequest) (*schema.VerifiableTx, error) {
 	if s.Options.GetMaintenance() {
@@ -0,0 +1,162 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+)
+
+// UpdateReplication switches a database between primary and replica, or changes the primary it replicates from.
+// Ongoing writes are fenced while switching and every change of role bumps the replication epoch of the database,
+// a change is rejected if the epoch differs from the expected one, if provided. A replica is promoted only once it
+// holds the given transaction, so that no transaction acknowledged by the former primary is lost
+func (s *ImmuServer) UpdateReplication(ctx context.Context, req *schema.UpdateReplicationRequest) (*schema.UpdateReplicationResponse, error) {
+	s.Logger.Debugf("updatereplication")
+
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	if !s.Options.GetAuth() {
+		return nil, ErrAuthMustBeEnabled
+	}
+
+	if req.DatabaseName == s.Options.defaultDbName {
+		return nil, ErrReservedDatabase
+	}
+
+	db, err := s.dbList.GetByName(req.DatabaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin && !user.HasPermission(req.DatabaseName, auth.PermissionAdmin) {
+		return nil, fmt.Errorf("you do not have permission on this database")
+	}
+
+	if s.cluster != nil {
+		if _, ok := s.cluster.node(req.DatabaseName); ok {
+			return nil, ErrClusterManagedReplication
+		}
+	}
+
+	s.replicationMux.Lock()
+	defer s.replicationMux.Unlock()
+
+	settings, err := s.loadSettings(req.DatabaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ExpectedEpoch != nil && req.ExpectedEpoch.Value != settings.ReplicationEpoch {
+		return nil, ErrStaleReplicationEpoch
+	}
+
+	previous := *settings
+
+	settings.Replica = req.Replica
+
+	if req.SrcDatabase != "" {
+		settings.SrcDatabase = req.SrcDatabase
+	}
+	if req.SrcAddress != "" {
+		settings.SrcAddress = req.SrcAddress
+	}
+	if req.SrcPort != 0 {
+		settings.SrcPort = int(req.SrcPort)
+	}
+	if req.FollowerUsr != "" {
+		settings.FollowerUsr = req.FollowerUsr
+	}
+	if req.FollowerPwd != "" {
+		settings.FollowerPwd = req.FollowerPwd
+	}
+
+	now := time.Now()
+
+	if previous.Replica && !settings.Replica {
+		settings.PromotedAt = now
+		settings.PromotedFrom = fmt.Sprintf("database '%s' at %s:%d", previous.SrcDatabase, previous.SrcAddress, previous.SrcPort)
+	} else if settings.Replica {
+		settings.PromotedAt = time.Time{}
+		settings.PromotedFrom = ""
+	}
+
+	settings.ReplicationEpoch++
+	settings.UpdatedBy = user.Username
+	settings.UpdatedAt = now
+
+	txID, err := db.SwitchReplication(settings.replicationOptions(), req.MinTxId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.saveSettings(settings)
+	if err != nil {
+		db.UpdateReplicationOptions(previous.replicationOptions())
+		return nil, err
+	}
+
+	// the divergence was found against the former primary or role
+	s.divergedMux.Lock()
+	delete(s.diverged, req.DatabaseName)
+	s.divergedMux.Unlock()
+
+	s.auditReplicationChange(&previous, settings, user.Username, txID)
+
+	if previous.Replica != settings.Replica {
+		s.Logger.Infof("Database '%s' switched to %s at tx %d, replication epoch %d",
+			req.DatabaseName, replicationRole(settings.Replica), txID, settings.ReplicationEpoch)
+	}
+
+	if previous.Replica && !settings.Replica {
+		s.emitReplicationEvent(&replicationEvent{
+			Kind:     ReplicationEventPromoted,
+			Database: req.DatabaseName,
+			TxID:     txID,
+			Message:  fmt.Sprintf("promoted by %s, replacing %s", user.Username, settings.PromotedFrom),
+		})
+	}
+
+	return &schema.UpdateReplicationResponse{
+		DatabaseName: req.DatabaseName,
+		Replica:      settings.Replica,
+		Epoch:        settings.ReplicationEpoch,
+		TxId:         txID,
+	}, nil
+}
+
+func replicationRole(replica bool) string {
+	if replica {
+		return "replica"
+	}
+	return "primary"
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServerUpdateReplication(t *testing.T) {
+	ctx := context.Background()
+
+	serverOptions := DefaultOptions().
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	_, err = s.UpdateReplication(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	lr, err := s.Login(ctx, &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.UpdateReplication(adminCtx, &schema.UpdateReplicationRequest{DatabaseName: DefaultdbName, Replica: true})
+	require.Equal(t, ErrReservedDatabase, err)
+
+	_, err = s.UpdateReplication(adminCtx, &schema.UpdateReplicationRequest{DatabaseName: "nodb", Replica: true})
+	require.Equal(t, database.ErrDatabaseNotExists, err)
+
+	_, err = s.CreateDatabaseWith(adminCtx, &schema.DatabaseSettings{DatabaseName: "porto"})
+	require.NoError(t, err)
+
+	db, err := s.dbList.GetByName("porto")
+	require.NoError(t, err)
+
+	md, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	res, err := s.UpdateReplication(adminCtx, &schema.UpdateReplicationRequest{
+		DatabaseName:  "porto",
+		Replica:       true,
+		SrcDatabase:   "lisbon",
+		SrcAddress:    "127.0.0.1",
+		SrcPort:       3323,
+		FollowerUsr:   "follower",
+		FollowerPwd:   "follower",
+		ExpectedEpoch: &schema.NullableUint64{Value: 0},
+	})
+	require.NoError(t, err)
+	require.True(t, res.Replica)
+	require.Equal(t, uint64(1), res.Epoch)
+	require.Equal(t, md.Id, res.TxId)
+
+	require.True(t, db.IsReplica())
+	require.Equal(t, "127.0.0.1", db.GetOptions().GetReplicationOptions().SrcAddress)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.Equal(t, database.ErrIsReplica, err)
+
+	// the switch was concurrently done by someone else
+	_, err = s.UpdateReplication(adminCtx, &schema.UpdateReplicationRequest{
+		DatabaseName:  "porto",
+		ExpectedEpoch: &schema.NullableUint64{Value: 0},
+	})
+	require.Equal(t, ErrStaleReplicationEpoch, err)
+	require.True(t, db.IsReplica())
+
+	// the replica lacks transactions acknowledged by its primary
+	_, err = s.UpdateReplication(adminCtx, &schema.UpdateReplicationRequest{
+		DatabaseName:  "porto",
+		ExpectedEpoch: &schema.NullableUint64{Value: 1},
+		MinTxId:       md.Id + 1,
+	})
+	require.Equal(t, database.ErrReplicaBehind, err)
+	require.True(t, db.IsReplica())
+
+	settings, err := s.loadSettings("porto")
+	require.NoError(t, err)
+	require.True(t, settings.Replica)
+	require.Equal(t, uint64(1), settings.ReplicationEpoch)
+
+	s.divergedMux.Lock()
+	s.diverged["porto"] = &replicaDivergence{Primary: "lisbon"}
+	s.divergedMux.Unlock()
+	require.NotNil(t, s.divergenceOf("porto"))
+
+	res, err = s.UpdateReplication(adminCtx, &schema.UpdateReplicationRequest{
+		DatabaseName:  "porto",
+		ExpectedEpoch: &schema.NullableUint64{Value: 1},
+		MinTxId:       md.Id,
+	})
+	require.NoError(t, err)
+	require.False(t, res.Replica)
+	require.Equal(t, uint64(2), res.Epoch)
+	require.Nil(t, s.divergenceOf("porto"))
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.NoError(t, err)
+
+	settings, err = s.loadSettings("porto")
+	require.NoError(t, err)
+	require.False(t, settings.Replica)
+	require.Equal(t, uint64(2), settings.ReplicationEpoch)
+	require.Equal(t, "127.0.0.1", settings.SrcAddress)
+	require.Equal(t, "database 'lisbon' at 127.0.0.1:3323", settings.PromotedFrom)
+	require.False(t, settings.PromotedAt.IsZero())
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/backup"
+	"github.com/codenotary/immudb/pkg/database"
+)
+
+// Restore creates a new database out of a chain of archives in the server backup storage,
+// a full backup followed by incremental ones, optionally up to a given transaction or timestamp.
+// Each archive must start right where the previous one ended and every transaction is verified
+// by the store before being committed, so the restored database has the same history as the original one.
+// When restored under a different name, the permissions granted on the backed up database can be copied
+// to the restored one, optionally as read-only permissions.
+// With replication settings, the database is restored as a replica which then only needs to receive
+// the transactions committed by its primary after the last restored one. When the primary can be reached,
+// the restored history is first checked to be consistent with its own
+func (s *ImmuServer) Restore(ctx context.Context, req *schema.RestoreRequest) (*schema.RestoreResponse, error) {
+	s.Logger.Debugf("restore")
+
+	if req == nil || len(req.Paths) == 0 || (req.ReadOnlyPermissions && !req.CopyPermissions) {
+		return nil, ErrIllegalArguments
+	}
+
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	if !s.Options.GetAuth() {
+		return nil, ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin {
+		return nil, fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+
+	ar, closer, err := s.openArchive(ctx, req.Paths[0])
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	hdr := ar.Header()
+
+	// the chain must start from a full backup
+	if hdr.IsIncremental() {
+		return nil, ErrIllegalArguments
+	}
+
+	dbName := req.DatabaseName
+	if dbName == "" {
+		dbName = hdr.Database
+	}
+
+	err = s.validateNewDatabaseName(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &dbSettings{}
+
+	if len(hdr.Settings) > 0 {
+		err = json.Unmarshal(hdr.Settings, settings)
+		if err != nil {
+			return nil, backup.ErrInvalidArchive
+		}
+	}
+
+	restored := settings.derive(dbName, user.Username)
+
+	if req.ReplicationSettings != nil {
+		restored.update(&schema.DatabaseNullableSettings{ReplicationSettings: req.ReplicationSettings})
+
+		// a partial replica holds its own transactions, not the history of its primary
+		if !restored.Replica || restored.Partial || !validSyncFallback(restored.SyncFallback) ||
+			(restored.TLSCert == "") != (restored.TLSKey == "") {
+			return nil, ErrIllegalArguments
+		}
+	}
+
+	var txID uint64
+
+	err = s.replayIntoNewDatabase(restored, func(db database.DB) error {
+		txID, err = s.replayChain(ctx, db, ar, req.Paths[1:], req.UntilTx, req.UntilTs)
+		if err != nil || !restored.Replica {
+			return err
+		}
+
+		return s.verifyRestoredReplica(db, restored.replicationOptions())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Logger.Infof("Database '%s' restored from %d archives up to tx %d", dbName, len(req.Paths), txID)
+
+	if restored.Replica {
+		s.Logger.Infof("Replica '%s' expects its primary to resume replication from tx %d", dbName, txID+1)
+
+		s.auditReplication(dbName, ReplicationAuditResynced, user.Username, txID,
+			fmt.Sprintf("restored from %d archives as %s", len(req.Paths), replicationTopology(restored)))
+	}
+
+	var copiedPermissions uint32
+
+	if req.CopyPermissions && dbName != hdr.Database {
+		copiedPermissions, err = s.copyDatabasePermissions(hdr.Database, dbName, req.ReadOnlyPermissions)
+		if err != nil {
+			return nil, err
+		}
+
+		s.Logger.Infof("Permissions of %d users on database '%s' copied to database '%s'", copiedPermissions, hdr.Database, dbName)
+	}
+
+	return &schema.RestoreResponse{
+		DatabaseName:      dbName,
+		TxId:              txID,
+		CopiedPermissions: copiedPermissions,
+	}, nil
+}
+
+// verifyRestoredReplica checks that a replica restored from archives shares the history of its primary,
+// a primary which can not be reached is verified later on by the replica verifier
+func (s *ImmuServer) verifyRestoredReplica(db database.DB, rOpts *database.ReplicationOptions) error {
+	if rOpts.SrcAddress == "" {
+		return nil
+	}
+
+	tlsConfig, err := replicationClientTLSConfig(rOpts)
+	if err != nil {
+		return err
+	}
+
+	primary, primaryName, err := dialRemoteDatabase(rOpts.SrcAddress, rOpts.SrcPort, rOpts.SrcDatabase, rOpts.FollowerUsr, rOpts.FollowerPwd, tlsConfig)
+	if err != nil {
+		s.Logger.Warningf("Unable to verify restored replica '%s' against its primary: %v", db.GetName(), err)
+		return nil
+	}
+	defer primary.conn.Close()
+
+	verification, err := verifyReplica(db, primary)
+	if err != nil {
+		s.Logger.Warningf("Unable to verify restored replica '%s' against its primary: %v", db.GetName(), err)
+		return nil
+	}
+
+	if !verification.consistent {
+		s.Logger.Errorf("Restored replica '%s' at tx %d diverges from its primary, %s, at tx %d",
+			db.GetName(), verification.replicaTx, primaryName, verification.primaryTx)
+		return ErrRestoredReplicaDiverged
+	}
+
+	return nil
+}
+
+// replayChain replays the first archive and then the following ones, as long as replay is not stopped
+func (s *ImmuServer) replayChain(ctx context.Context, db database.DB, ar *backup.Reader, paths []string, untilTx uint64, untilTs int64) (uint64, error) {
+	var txID uint64
+
+	for i := 0; ; i++ {
+		lastTx, stopped, err := replayArchive(ar, db, untilTx, untilTs)
+		if err != nil {
+			return txID, err
+		}
+
+		if lastTx > 0 {
+			txID = lastTx
+		}
+
+		if stopped || i == len(paths) {
+			return txID, nil
+		}
+
+		prev := ar.Trailer()
+
+		next, closer, err := s.openArchive(ctx, paths[i])
+		if err != nil {
+			return txID, err
+		}
+		defer closer.Close()
+
+		if !next.Header().Follows(prev) {
+			return txID, backup.ErrBrokenChain
+		}
+
+		ar = next
+	}
+}
+
+func (s *ImmuServer) openArchive(ctx context.Context, name string) (*backup.Reader, io.Closer, error) {
+	_, err := backup.CleanName(name)
+	if err != nil {
+		return nil, nil, ErrIllegalArguments
+	}
+
+	f, err := s.backupStorage.Open(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ar, err := backup.OpenReader(f, s.backupKeys)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	return ar, f, nil
+}
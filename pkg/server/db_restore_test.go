@@ -0,0 +1,374 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/backup"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServerRestore(t *testing.T) {
+	serverOptions := DefaultOptions().
+		WithDir("data_restore").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	_, err = s.Restore(context.Background(), nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	db, err := s.dbList.GetByName(DefaultdbName)
+	require.NoError(t, err)
+
+	set := func(n int) *schema.TxMetadata {
+		var md *schema.TxMetadata
+
+		for i := 0; i < n; i++ {
+			md, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte{byte(i)}}}})
+			require.NoError(t, err)
+		}
+
+		return md
+	}
+
+	backupTo := func(path string, sinceTx uint64) {
+		err := s.Backup(&schema.BackupRequest{DatabaseName: DefaultdbName, Path: path, SinceTx: sinceTx}, &immuServiceExportDatabaseServer{ctx: adminCtx})
+		require.NoError(t, err)
+	}
+
+	firstMd := set(5)
+	backupTo("full.bak", 0)
+
+	midMd := set(3)
+	backupTo("inc1.bak", firstMd.Id)
+
+	lastMd := set(2)
+	backupTo("inc2.bak", midMd.Id)
+
+	srcState, err := db.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, lastMd.Id, srcState.TxId)
+
+	_, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "restored", Paths: []string{"inc1.bak"}})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "restored", Paths: []string{"full.bak", "inc2.bak"}})
+	require.Equal(t, backup.ErrBrokenChain, err)
+	require.Equal(t, int64(-1), s.dbList.GetId("restored"))
+
+	_, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "restored", Paths: []string{"../full.bak"}})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "restored", Paths: []string{"missing.bak"}})
+	require.Error(t, err)
+
+	// the database recorded in the archive already exists
+	_, err = s.Restore(adminCtx, &schema.RestoreRequest{Paths: []string{"full.bak"}})
+	require.Error(t, err)
+
+	res, err := s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "restored", Paths: []string{"full.bak", "inc1.bak", "inc2.bak"}})
+	require.NoError(t, err)
+	require.Equal(t, "restored", res.DatabaseName)
+	require.Equal(t, srcState.TxId, res.TxId)
+
+	restoredDB, err := s.dbList.GetByName("restored")
+	require.NoError(t, err)
+	require.False(t, restoredDB.IsReplica())
+
+	restoredState, err := restoredDB.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, srcState.TxHash, restoredState.TxHash)
+
+	// point-in-time restore by transaction
+	pitTx := firstMd.Id + 2
+
+	res, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "pit", Paths: []string{"full.bak", "inc1.bak", "inc2.bak"}, UntilTx: pitTx})
+	require.NoError(t, err)
+	require.Equal(t, pitTx, res.TxId)
+
+	pitDB, err := s.dbList.GetByName("pit")
+	require.NoError(t, err)
+
+	pitState, err := pitDB.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, pitTx, pitState.TxId)
+
+	tx, err := db.TxByID(&schema.TxRequest{Tx: pitTx})
+	require.NoError(t, err)
+
+	alh := schema.TxMetadataFrom(tx.Metadata).Alh()
+	require.Equal(t, alh[:], pitState.TxHash)
+
+	// point-in-time restore by timestamp
+	initialTx, err := db.TxByID(&schema.TxRequest{Tx: 1})
+	require.NoError(t, err)
+
+	res, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "early", Paths: []string{"full.bak"}, UntilTs: initialTx.Metadata.Ts - 1})
+	require.NoError(t, err)
+	require.Zero(t, res.TxId)
+
+	res, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "latest", Paths: []string{"full.bak", "inc1.bak", "inc2.bak"}, UntilTs: lastMd.Ts})
+	require.NoError(t, err)
+	require.Equal(t, srcState.TxId, res.TxId)
+
+	// tampered archives are rejected
+	inc2Path := filepath.Join(s.Options.GetBackupDir(), "inc2.bak")
+
+	inc2, err := ioutil.ReadFile(inc2Path)
+	require.NoError(t, err)
+
+	inc2[len(inc2)/2] ^= 0xFF
+
+	err = ioutil.WriteFile(inc2Path, inc2, 0644)
+	require.NoError(t, err)
+
+	_, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "tampered", Paths: []string{"full.bak", "inc1.bak", "inc2.bak"}})
+	require.True(t, errors.Is(err, backup.ErrCorruptedArchive))
+	require.Equal(t, int64(-1), s.dbList.GetId("tampered"))
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
+
+func TestServerRestoreWithPermissions(t *testing.T) {
+	serverOptions := DefaultOptions().
+		WithDir("data_restore_permissions").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.CreateDatabaseWith(adminCtx, &schema.DatabaseSettings{DatabaseName: "orders"})
+	require.NoError(t, err)
+
+	for _, u := range []struct {
+		name       string
+		permission uint32
+		database   string
+	}{
+		{"alice", auth.PermissionRW, "orders"},
+		{"bob", auth.PermissionAdmin, "orders"},
+		{"carol", auth.PermissionR, DefaultdbName},
+	} {
+		_, err = s.CreateUser(adminCtx, &schema.CreateUserRequest{
+			User:       []byte(u.name),
+			Password:   []byte("Passw0rd!-"),
+			Permission: u.permission,
+			Database:   u.database,
+		})
+		require.NoError(t, err)
+	}
+
+	db, err := s.dbList.GetByName("orders")
+	require.NoError(t, err)
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("order1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	err = s.Backup(&schema.BackupRequest{DatabaseName: "orders", Path: "orders.bak"}, &immuServiceExportDatabaseServer{ctx: adminCtx})
+	require.NoError(t, err)
+
+	_, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "ordersaudit", Paths: []string{"orders.bak"}, ReadOnlyPermissions: true})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	// restoring over the live database is not allowed
+	_, err = s.Restore(adminCtx, &schema.RestoreRequest{Paths: []string{"orders.bak"}})
+	require.Error(t, err)
+
+	res, err := s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "orderscopy", Paths: []string{"orders.bak"}})
+	require.NoError(t, err)
+	require.Zero(t, res.CopiedPermissions)
+
+	res, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "ordersrw", Paths: []string{"orders.bak"}, CopyPermissions: true})
+	require.NoError(t, err)
+	require.Equal(t, "ordersrw", res.DatabaseName)
+	require.Equal(t, uint32(2), res.CopiedPermissions)
+
+	res, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "ordersaudit", Paths: []string{"orders.bak"}, CopyPermissions: true, ReadOnlyPermissions: true})
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), res.CopiedPermissions)
+
+	alice, err := s.getUser([]byte("alice"), true)
+	require.NoError(t, err)
+	require.Equal(t, uint32(auth.PermissionRW), alice.WhichPermission("orders"))
+	require.Equal(t, uint32(auth.PermissionNone), alice.WhichPermission("orderscopy"))
+	require.Equal(t, uint32(auth.PermissionRW), alice.WhichPermission("ordersrw"))
+	require.Equal(t, uint32(auth.PermissionR), alice.WhichPermission("ordersaudit"))
+
+	bob, err := s.getUser([]byte("bob"), true)
+	require.NoError(t, err)
+	require.Equal(t, uint32(auth.PermissionAdmin), bob.WhichPermission("ordersrw"))
+	require.Equal(t, uint32(auth.PermissionR), bob.WhichPermission("ordersaudit"))
+
+	carol, err := s.getUser([]byte("carol"), true)
+	require.NoError(t, err)
+	require.Equal(t, uint32(auth.PermissionNone), carol.WhichPermission("ordersrw"))
+	require.Equal(t, uint32(auth.PermissionNone), carol.WhichPermission("ordersaudit"))
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
+
+func TestServerRestoreAsReplica(t *testing.T) {
+	serverOptions := DefaultOptions().
+		WithDir("data_restore_replica").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	// the server itself is the primary of the restored replicas
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer()
+	schema.RegisterImmuServiceServer(grpcSrv, s)
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.CreateDatabaseWith(adminCtx, &schema.DatabaseSettings{DatabaseName: "otherdb"})
+	require.NoError(t, err)
+
+	for _, name := range []string{DefaultdbName, "otherdb"} {
+		db, err := s.dbList.GetByName(name)
+		require.NoError(t, err)
+
+		_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte(name)}}})
+		require.NoError(t, err)
+
+		err = s.Backup(&schema.BackupRequest{DatabaseName: name, Path: name + ".bak"}, &immuServiceExportDatabaseServer{ctx: adminCtx})
+		require.NoError(t, err)
+	}
+
+	db, err := s.dbList.GetByName(DefaultdbName)
+	require.NoError(t, err)
+
+	// the primary moved on after the backup
+	md, err := db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value2")}}})
+	require.NoError(t, err)
+
+	replicaOf := func(address string, port int) *schema.ReplicationNullableSettings {
+		return &schema.ReplicationNullableSettings{
+			Replica:     &schema.NullableBool{Value: true},
+			SrcDatabase: &schema.NullableString{Value: DefaultdbName},
+			SrcAddress:  &schema.NullableString{Value: address},
+			SrcPort:     &schema.NullableUint32{Value: uint32(port)},
+			FollowerUsr: &schema.NullableString{Value: auth.SysAdminUsername},
+			FollowerPwd: &schema.NullableString{Value: auth.SysAdminPassword},
+		}
+	}
+
+	port := lis.Addr().(*net.TCPAddr).Port
+
+	partial := replicaOf("127.0.0.1", port)
+	partial.Partial = &schema.NullableBool{Value: true}
+
+	_, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "replica", Paths: []string{DefaultdbName + ".bak"}, ReplicationSettings: partial})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	// the archive of another database does not share the history of the primary
+	_, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "replica", Paths: []string{"otherdb.bak"}, ReplicationSettings: replicaOf("127.0.0.1", port)})
+	require.Equal(t, ErrRestoredReplicaDiverged, err)
+	require.Equal(t, int64(-1), s.dbList.GetId("replica"))
+
+	res, err := s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "replica", Paths: []string{DefaultdbName + ".bak"}, ReplicationSettings: replicaOf("127.0.0.1", port)})
+	require.NoError(t, err)
+	require.Equal(t, md.Id-1, res.TxId)
+
+	replica, err := s.dbList.GetByName("replica")
+	require.NoError(t, err)
+	require.True(t, replica.IsReplica())
+
+	settings, err := s.loadSettings("replica")
+	require.NoError(t, err)
+	require.True(t, settings.Replica)
+	require.Equal(t, DefaultdbName, settings.SrcDatabase)
+	require.Equal(t, port, settings.SrcPort)
+
+	// replication resumes right after the restored transactions
+	etx, err := db.ExportTxByID(&schema.TxRequest{Tx: md.Id})
+	require.NoError(t, err)
+
+	_, err = replica.ReplicateTx(etx)
+	require.NoError(t, err)
+
+	replicaState, err := replica.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, md.Id, replicaState.TxId)
+
+	// an unreachable primary is verified later on
+	grpcSrv.Stop()
+
+	_, err = s.Restore(adminCtx, &schema.RestoreRequest{DatabaseName: "unverified", Paths: []string{"otherdb.bak"}, ReplicationSettings: replicaOf("127.0.0.1", port)})
+	require.NoError(t, err)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
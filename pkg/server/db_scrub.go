@@ -0,0 +1,253 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/codenotary/immudb/pkg/stream"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// repairAudit records a scrub which repaired damaged transactions, as stored in the system database.
+// Every scrub is written as a new revision of the same key, so the history of the key is the repair log of the database
+type repairAudit struct {
+	Source          string    `json:"source"`
+	RepairedBy      string    `json:"repairedBy"`
+	RepairedAt      time.Time `json:"repairedAt"`
+	RepairedTxs     []uint64  `json:"repairedTxs"`
+	RepairedEntries uint64    `json:"repairedEntries"`
+	UnrepairedTxs   []uint64  `json:"unrepairedTxs,omitempty"`
+}
+
+func repairAuditKey(database string) []byte {
+	key := make([]byte, 1+len(database))
+	key[0] = KeyPrefixRepairAudit
+	copy(key[1:], []byte(database))
+	return key
+}
+
+// Scrub verifies every transaction of the database and recovers the damaged values out of the requested source:
+// another database on this server, a remote one or, when none is given, the source of the database if it is a replica.
+// Repairs are recorded in the system database
+func (s *ImmuServer) Scrub(ctx context.Context, req *schema.ScrubRequest) (*schema.ScrubResponse, error) {
+	if req == nil || req.Database == "" {
+		return nil, ErrIllegalArguments
+	}
+
+	if !s.Options.GetAuth() {
+		return nil, ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin {
+		return nil, fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+
+	db, err := s.dbList.GetByName(req.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	source, sourceName, closeSource, err := s.scrubSource(db, req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSource()
+
+	s.Logger.Infof("Scrubbing database '%s' using %s as source", db.GetName(), sourceName)
+
+	report, err := db.Scrub(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(report.RepairedTxs) > 0 {
+		auditData, err := json.Marshal(&repairAudit{
+			Source:          sourceName,
+			RepairedBy:      user.Username,
+			RepairedAt:      time.Now(),
+			RepairedTxs:     report.RepairedTxs,
+			RepairedEntries: report.RepairedEntries,
+			UnrepairedTxs:   report.UnrepairedTxs,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = s.sysDB.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: repairAuditKey(db.GetName()), Value: auditData}}})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(report.UnrepairedTxs) == 0 {
+		s.clearDegraded(db.GetName())
+	}
+
+	return &schema.ScrubResponse{
+		Database:        db.GetName(),
+		TxCount:         report.TxCount,
+		RepairedTxs:     report.RepairedTxs,
+		RepairedEntries: report.RepairedEntries,
+		UnrepairedTxs:   report.UnrepairedTxs,
+	}, nil
+}
+
+func (s *ImmuServer) scrubSource(db database.DB, req *schema.ScrubRequest) (source database.TxSource, name string, closeSource func(), err error) {
+	if req.SourceAddress != "" {
+		srcDatabase := req.SourceDatabase
+		if srcDatabase == "" {
+			srcDatabase = req.Database
+		}
+
+		port := int(req.SourcePort)
+		if port == 0 {
+			port = DefaultOptions().Port
+		}
+
+		return dialRemoteTxSource(req.SourceAddress, port, srcDatabase, req.SourceUsername, req.SourcePassword, nil)
+	}
+
+	if req.SourceDatabase != "" {
+		if req.SourceDatabase == req.Database {
+			return nil, "", nil, ErrIllegalArguments
+		}
+
+		srcDB, err := s.dbList.GetByName(req.SourceDatabase)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		source := database.TxSourceFunc(func(txID uint64) ([]byte, error) {
+			return srcDB.ExportTxByID(&schema.TxRequest{Tx: txID})
+		})
+
+		return source, fmt.Sprintf("database '%s'", srcDB.GetName()), func() {}, nil
+	}
+
+	rOpts := db.GetOptions().GetReplicationOptions()
+	if rOpts == nil || !rOpts.Replica || rOpts.Partial || rOpts.SrcAddress == "" {
+		return nil, "", nil, ErrScrubSourceRequired
+	}
+
+	tlsConfig, err := replicationClientTLSConfig(rOpts)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return dialRemoteTxSource(rOpts.SrcAddress, rOpts.SrcPort, rOpts.SrcDatabase, rOpts.FollowerUsr, rOpts.FollowerPwd, tlsConfig)
+}
+
+// remoteTxSource gives access to a database of another immudb server, it exports its transactions
+// and provides its state and proofs
+type remoteTxSource struct {
+	conn   *grpc.ClientConn
+	client schema.ImmuServiceClient
+	ctx    context.Context
+}
+
+func dialRemoteTxSource(address string, port int, dbName, username, password string, tlsConfig *tls.Config) (database.TxSource, string, func(), error) {
+	src, name, err := dialRemoteDatabase(address, port, dbName, username, password, tlsConfig)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return src, name, func() { src.conn.Close() }, nil
+}
+
+// dialRemoteDatabase connects to a database of another immudb server, in the clear when no TLS configuration is given
+func dialRemoteDatabase(address string, port int, dbName, username, password string, tlsConfig *tls.Config) (*remoteTxSource, string, error) {
+	target := fmt.Sprintf("%s:%d", address, port)
+
+	transportOpt := grpc.WithInsecure()
+	if tlsConfig != nil {
+		transportOpt = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+
+	conn, err := grpc.Dial(target, transportOpt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	src := &remoteTxSource{
+		conn:   conn,
+		client: schema.NewImmuServiceClient(conn),
+	}
+
+	err = src.login(dbName, username, password)
+	if err != nil {
+		conn.Close()
+		return nil, "", fmt.Errorf("unable to connect to the source database '%s' at %s: %w", dbName, target, err)
+	}
+
+	return src, fmt.Sprintf("database '%s' at %s", dbName, target), nil
+}
+
+func (src *remoteTxSource) login(dbName, username, password string) error {
+	lr, err := src.client.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(username),
+		Password: []byte(password),
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	ur, err := src.client.UseDatabase(ctx, &schema.Database{DatabaseName: dbName})
+	if err != nil {
+		return err
+	}
+
+	src.ctx = metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", ur.Token))
+
+	return nil
+}
+
+func (src *remoteTxSource) ExportTx(txID uint64) ([]byte, error) {
+	md, _ := metadata.FromOutgoingContext(src.ctx)
+	ctx := metadata.NewOutgoingContext(src.ctx, metadata.Join(md, schema.LocalReplicationVersions().MD()))
+
+	exportTxStream, err := src.client.ExportTx(ctx, &schema.TxRequest{Tx: txID})
+	if err != nil {
+		return nil, err
+	}
+
+	return stream.NewMsgReceiver(exportTxStream).ReadFully()
+}
+
+func (src *remoteTxSource) CurrentState() (*schema.ImmutableState, error) {
+	return src.client.CurrentState(src.ctx, &empty.Empty{})
+}
+
+func (src *remoteTxSource) VerifiableTxByID(req *schema.VerifiableTxRequest) (*schema.VerifiableTx, error) {
+	return src.client.VerifiableTxById(src.ctx, req)
+}
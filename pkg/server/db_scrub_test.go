@@ -0,0 +1,155 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type repairedDB struct {
+	database.DB
+	report *database.ScrubReport
+}
+
+func (db *repairedDB) Scrub(source database.TxSource) (*database.ScrubReport, error) {
+	_, err := db.DB.Scrub(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.report, nil
+}
+
+func TestServerScrub(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_scrub").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	_, err = s.Scrub(context.Background(), nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = s.Scrub(context.Background(), &schema.ScrubRequest{Database: DefaultdbName})
+	require.Error(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{DatabaseName: "db1"})
+	require.NoError(t, err)
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	_, err = s.Scrub(ctx, &schema.ScrubRequest{Database: DefaultdbName})
+	require.Equal(t, ErrScrubSourceRequired, err)
+
+	_, err = s.Scrub(ctx, &schema.ScrubRequest{Database: DefaultdbName, SourceDatabase: DefaultdbName})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = s.Scrub(ctx, &schema.ScrubRequest{Database: DefaultdbName, SourceDatabase: "nodb"})
+	require.Error(t, err)
+
+	_, err = s.Scrub(ctx, &schema.ScrubRequest{Database: DefaultdbName, SourceAddress: "127.0.0.1", SourcePort: 1})
+	require.Error(t, err)
+
+	res, err := s.Scrub(ctx, &schema.ScrubRequest{Database: DefaultdbName, SourceDatabase: "db1"})
+	require.NoError(t, err)
+	require.Equal(t, DefaultdbName, res.Database)
+	require.NotZero(t, res.TxCount)
+	require.Empty(t, res.RepairedTxs)
+	require.Empty(t, res.UnrepairedTxs)
+
+	// nothing was repaired, so nothing was audited
+	_, err = s.sysDB.Get(&schema.KeyRequest{Key: repairAuditKey(DefaultdbName)})
+	require.Error(t, err)
+
+	db1, err := s.dbList.GetByName("db1")
+	require.NoError(t, err)
+
+	db := &repairedDB{
+		DB:     s.dbList.GetByIndex(defaultDbIndex),
+		report: &database.ScrubReport{TxCount: 2, RepairedTxs: []uint64{2}, RepairedEntries: 1},
+	}
+
+	s.dbList = database.NewDatabaseList()
+	s.dbList.Append(db)
+	s.dbList.Append(db1)
+
+	s.degradedMux.Lock()
+	s.degraded[DefaultdbName] = time.Now()
+	s.degradedMux.Unlock()
+
+	res, err = s.Scrub(ctx, &schema.ScrubRequest{Database: DefaultdbName, SourceDatabase: "db1"})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2}, res.RepairedTxs)
+	require.Equal(t, uint64(1), res.RepairedEntries)
+
+	// repairs are audited and heal the database
+	require.False(t, s.isDegraded(DefaultdbName))
+
+	e, err := s.sysDB.Get(&schema.KeyRequest{Key: repairAuditKey(DefaultdbName)})
+	require.NoError(t, err)
+
+	var audit repairAudit
+	err = json.Unmarshal(e.Value, &audit)
+	require.NoError(t, err)
+	require.Equal(t, "database 'db1'", audit.Source)
+	require.Equal(t, auth.SysAdminUsername, audit.RepairedBy)
+	require.Equal(t, []uint64{2}, audit.RepairedTxs)
+	require.Equal(t, uint64(1), audit.RepairedEntries)
+
+	// databases with unrecoverable transactions stay degraded
+	db.report = &database.ScrubReport{TxCount: 2, RepairedTxs: []uint64{1}, RepairedEntries: 1, UnrepairedTxs: []uint64{2}}
+
+	s.degradedMux.Lock()
+	s.degraded[DefaultdbName] = time.Now()
+	s.degradedMux.Unlock()
+
+	res, err = s.Scrub(ctx, &schema.ScrubRequest{Database: DefaultdbName, SourceDatabase: "db1"})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2}, res.UnrepairedTxs)
+	require.True(t, s.isDegraded(DefaultdbName))
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
@@ -0,0 +1,400 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/embedded/tbtree"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
+)
+
+// dbStoreSettings holds the store parameters overriding server defaults, unset fields are not overridden.
+// Parameters stored as metadata when the database gets created can not be changed
+type dbStoreSettings struct {
+	Synced                  *bool `json:"synced,omitempty"`
+	MaxConcurrency          *int  `json:"maxConcurrency,omitempty"`
+	MaxIOConcurrency        *int  `json:"maxIOConcurrency,omitempty"`
+	TxLogCacheSize          *int  `json:"txLogCacheSize,omitempty"`
+	VLogMaxOpenedFiles      *int  `json:"vLogMaxOpenedFiles,omitempty"`
+	TxLogMaxOpenedFiles     *int  `json:"txLogMaxOpenedFiles,omitempty"`
+	CommitLogMaxOpenedFiles *int  `json:"commitLogMaxOpenedFiles,omitempty"`
+	IndexCacheSize          *int  `json:"indexCacheSize,omitempty"`
+	IndexFlushThld          *int  `json:"indexFlushThld,omitempty"`
+	IndexMaxActiveSnapshots *int  `json:"indexMaxActiveSnapshots,omitempty"`
+	IndexCompactionThld     *int  `json:"indexCompactionThld,omitempty"`
+}
+
+// apply overrides store options with the configured settings
+func (ss *dbStoreSettings) apply(opts *store.Options) *store.Options {
+	if ss == nil {
+		return opts
+	}
+
+	// index options may be shared with other databases
+	indexOpts := *opts.IndexOpts
+	opts.IndexOpts = &indexOpts
+
+	setBool(&opts.Synced, ss.Synced)
+	setInt(&opts.MaxConcurrency, ss.MaxConcurrency)
+	setInt(&opts.MaxIOConcurrency, ss.MaxIOConcurrency)
+	setInt(&opts.TxLogCacheSize, ss.TxLogCacheSize)
+	setInt(&opts.VLogMaxOpenedFiles, ss.VLogMaxOpenedFiles)
+	setInt(&opts.TxLogMaxOpenedFiles, ss.TxLogMaxOpenedFiles)
+	setInt(&opts.CommitLogMaxOpenedFiles, ss.CommitLogMaxOpenedFiles)
+	setInt(&opts.IndexOpts.CacheSize, ss.IndexCacheSize)
+	setInt(&opts.IndexOpts.FlushThld, ss.IndexFlushThld)
+	setInt(&opts.IndexOpts.MaxActiveSnapshots, ss.IndexMaxActiveSnapshots)
+	setInt(&opts.IndexOpts.CompactionThld, ss.IndexCompactionThld)
+
+	return opts
+}
+
+func setBool(dst *bool, v *bool) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+func setInt(dst *int, v *int) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+func nullableInt(v *schema.NullableUint32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(v.Value)
+	return &i
+}
+
+func validStoreSettings(opts *store.Options) bool {
+	return opts.MaxConcurrency > 0 &&
+		opts.MaxIOConcurrency > 0 &&
+		opts.MaxIOConcurrency <= store.MaxParallelIO &&
+		opts.VLogMaxOpenedFiles > 0 &&
+		opts.TxLogMaxOpenedFiles > 0 &&
+		opts.CommitLogMaxOpenedFiles > 0 &&
+		opts.IndexOpts.CacheSize >= tbtree.MinCacheSize &&
+		opts.IndexOpts.FlushThld > 0 &&
+		opts.IndexOpts.MaxActiveSnapshots > 0
+}
+
+func validSyncFallback(fallback string) bool {
+	return fallback == "" || fallback == database.SyncFallbackAsync || fallback == database.SyncFallbackFail
+}
+
+func validReplicaFilters(filters map[string][][]byte) bool {
+	for replicaID, prefixes := range filters {
+		if replicaID == "" || len(prefixes) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func replicaFilters(filters map[string][][]byte) *schema.ReplicaFilters {
+	res := &schema.ReplicaFilters{}
+
+	for replicaID, prefixes := range filters {
+		res.Filters = append(res.Filters, &schema.ReplicaFilter{ReplicaId: replicaID, KeyPrefixes: prefixes})
+	}
+
+	sort.Slice(res.Filters, func(i, j int) bool { return res.Filters[i].ReplicaId < res.Filters[j].ReplicaId })
+
+	return res
+}
+
+// update merges the provided settings, only set fields are changed
+func (s *dbSettings) update(req *schema.DatabaseNullableSettings) {
+	if req.CorruptionChecker != nil {
+		s.CorruptionChecker = req.CorruptionChecker.Value
+	}
+
+	if req.MaxDiskSize != nil {
+		s.MaxDiskSize = int64(req.MaxDiskSize.Value)
+	}
+
+	if req.ReadOnly != nil {
+		s.ReadOnly = req.ReadOnly.Value
+	}
+
+	if req.ParanoidReads != nil {
+		s.ParanoidReads = req.ParanoidReads.Value
+	}
+
+	if rs := req.ReplicationSettings; rs != nil {
+		if rs.Replica != nil {
+			s.Replica = rs.Replica.Value
+		}
+		if rs.SrcDatabase != nil {
+			s.SrcDatabase = rs.SrcDatabase.Value
+		}
+		if rs.SrcAddress != nil {
+			s.SrcAddress = rs.SrcAddress.Value
+		}
+		if rs.SrcPort != nil {
+			s.SrcPort = int(rs.SrcPort.Value)
+		}
+		if rs.FollowerUsr != nil {
+			s.FollowerUsr = rs.FollowerUsr.Value
+		}
+		if rs.FollowerPwd != nil {
+			s.FollowerPwd = rs.FollowerPwd.Value
+		}
+		if rs.SyncAcks != nil {
+			s.SyncAcks = int(rs.SyncAcks.Value)
+		}
+		if rs.SyncTimeout != nil {
+			s.SyncTimeout = int(rs.SyncTimeout.Value)
+		}
+		if rs.SyncFallback != nil {
+			s.SyncFallback = rs.SyncFallback.Value
+		}
+		if rs.MaxReplicaTxRate != nil {
+			s.MaxReplicaTxRate = int(rs.MaxReplicaTxRate.Value)
+		}
+		if rs.MaxReplicaByteRate != nil {
+			s.MaxReplicaByteRate = int(rs.MaxReplicaByteRate.Value)
+		}
+		if rs.MaxReplicationTxRate != nil {
+			s.MaxReplicationTxRate = int(rs.MaxReplicationTxRate.Value)
+		}
+		if rs.MaxReplicationByteRate != nil {
+			s.MaxReplicationByteRate = int(rs.MaxReplicationByteRate.Value)
+		}
+		if rs.ReplicaFilters != nil {
+			s.ReplicaFilters = make(map[string][][]byte, len(rs.ReplicaFilters.Filters))
+			for _, f := range rs.ReplicaFilters.Filters {
+				s.ReplicaFilters[f.ReplicaId] = f.KeyPrefixes
+			}
+		}
+		if rs.Partial != nil {
+			s.Partial = rs.Partial.Value
+		}
+		if rs.TlsCert != nil {
+			s.TLSCert = rs.TlsCert.Value
+		}
+		if rs.TlsKey != nil {
+			s.TLSKey = rs.TlsKey.Value
+		}
+		if rs.TlsCa != nil {
+			s.TLSCA = rs.TlsCa.Value
+		}
+		if rs.TlsServerName != nil {
+			s.TLSServerName = rs.TlsServerName.Value
+		}
+		if rs.AutoFailover != nil {
+			s.AutoFailover = rs.AutoFailover.Value
+		}
+	}
+
+	if s.StoreSettings == nil {
+		s.StoreSettings = &dbStoreSettings{}
+	}
+
+	ss := s.StoreSettings
+
+	if req.Synced != nil {
+		synced := req.Synced.Value
+		ss.Synced = &synced
+	}
+
+	for _, f := range []struct {
+		dst **int
+		v   *schema.NullableUint32
+	}{
+		{&ss.MaxConcurrency, req.MaxConcurrency},
+		{&ss.MaxIOConcurrency, req.MaxIOConcurrency},
+		{&ss.TxLogCacheSize, req.TxLogCacheSize},
+		{&ss.VLogMaxOpenedFiles, req.VLogMaxOpenedFiles},
+		{&ss.TxLogMaxOpenedFiles, req.TxLogMaxOpenedFiles},
+		{&ss.CommitLogMaxOpenedFiles, req.CommitLogMaxOpenedFiles},
+		{&ss.IndexCacheSize, req.GetIndexSettings().GetCacheSize()},
+		{&ss.IndexFlushThld, req.GetIndexSettings().GetFlushThld()},
+		{&ss.IndexMaxActiveSnapshots, req.GetIndexSettings().GetMaxActiveSnapshots()},
+		{&ss.IndexCompactionThld, req.GetIndexSettings().GetCompactionThld()},
+	} {
+		if f.v != nil {
+			*f.dst = nullableInt(f.v)
+		}
+	}
+}
+
+func (s *dbSettings) replicationOptions() *database.ReplicationOptions {
+	return &database.ReplicationOptions{
+		Replica:                s.Replica,
+		SrcDatabase:            s.SrcDatabase,
+		SrcAddress:             s.SrcAddress,
+		SrcPort:                s.SrcPort,
+		FollowerUsr:            s.FollowerUsr,
+		FollowerPwd:            s.FollowerPwd,
+		SyncAcks:               s.SyncAcks,
+		SyncTimeout:            time.Duration(s.SyncTimeout) * time.Millisecond,
+		SyncFallback:           s.SyncFallback,
+		MaxReplicaTxRate:       s.MaxReplicaTxRate,
+		MaxReplicaByteRate:     s.MaxReplicaByteRate,
+		MaxReplicationTxRate:   s.MaxReplicationTxRate,
+		MaxReplicationByteRate: s.MaxReplicationByteRate,
+		ReplicaFilters:         s.ReplicaFilters,
+		Partial:                s.Partial,
+		TLSCert:                s.TLSCert,
+		TLSKey:                 s.TLSKey,
+		TLSCA:                  s.TLSCA,
+		TLSServerName:          s.TLSServerName,
+	}
+}
+
+// nullableSettings returns the effective settings given the store options the database is loaded with
+func (s *dbSettings) nullableSettings(opts *store.Options) *schema.DatabaseNullableSettings {
+	return &schema.DatabaseNullableSettings{
+		CorruptionChecker: &schema.NullableBool{Value: s.CorruptionChecker},
+		ReplicationSettings: &schema.ReplicationNullableSettings{
+			Replica:                &schema.NullableBool{Value: s.Replica},
+			SrcDatabase:            &schema.NullableString{Value: s.SrcDatabase},
+			SrcAddress:             &schema.NullableString{Value: s.SrcAddress},
+			SrcPort:                &schema.NullableUint32{Value: uint32(s.SrcPort)},
+			FollowerUsr:            &schema.NullableString{Value: s.FollowerUsr},
+			SyncAcks:               &schema.NullableUint32{Value: uint32(s.SyncAcks)},
+			SyncTimeout:            &schema.NullableUint32{Value: uint32(s.SyncTimeout)},
+			SyncFallback:           &schema.NullableString{Value: s.SyncFallback},
+			MaxReplicaTxRate:       &schema.NullableUint32{Value: uint32(s.MaxReplicaTxRate)},
+			MaxReplicaByteRate:     &schema.NullableUint64{Value: uint64(s.MaxReplicaByteRate)},
+			MaxReplicationTxRate:   &schema.NullableUint32{Value: uint32(s.MaxReplicationTxRate)},
+			MaxReplicationByteRate: &schema.NullableUint64{Value: uint64(s.MaxReplicationByteRate)},
+			ReplicaFilters:         replicaFilters(s.ReplicaFilters),
+			Partial:                &schema.NullableBool{Value: s.Partial},
+			TlsCert:                &schema.NullableString{Value: s.TLSCert},
+			TlsKey:                 &schema.NullableString{Value: s.TLSKey},
+			TlsCa:                  &schema.NullableString{Value: s.TLSCA},
+			TlsServerName:          &schema.NullableString{Value: s.TLSServerName},
+			AutoFailover:           &schema.NullableBool{Value: s.AutoFailover},
+		},
+		MaxDiskSize:             &schema.NullableUint64{Value: uint64(s.MaxDiskSize)},
+		ReadOnly:                &schema.NullableBool{Value: s.ReadOnly},
+		ParanoidReads:           &schema.NullableBool{Value: s.ParanoidReads},
+		Synced:                  &schema.NullableBool{Value: opts.Synced},
+		MaxConcurrency:          &schema.NullableUint32{Value: uint32(opts.MaxConcurrency)},
+		MaxIOConcurrency:        &schema.NullableUint32{Value: uint32(opts.MaxIOConcurrency)},
+		TxLogCacheSize:          &schema.NullableUint32{Value: uint32(opts.TxLogCacheSize)},
+		VLogMaxOpenedFiles:      &schema.NullableUint32{Value: uint32(opts.VLogMaxOpenedFiles)},
+		TxLogMaxOpenedFiles:     &schema.NullableUint32{Value: uint32(opts.TxLogMaxOpenedFiles)},
+		CommitLogMaxOpenedFiles: &schema.NullableUint32{Value: uint32(opts.CommitLogMaxOpenedFiles)},
+		IndexSettings: &schema.IndexNullableSettings{
+			CacheSize:          &schema.NullableUint32{Value: uint32(opts.IndexOpts.CacheSize)},
+			FlushThld:          &schema.NullableUint32{Value: uint32(opts.IndexOpts.FlushThld)},
+			MaxActiveSnapshots: &schema.NullableUint32{Value: uint32(opts.IndexOpts.MaxActiveSnapshots)},
+			CompactionThld:     &schema.NullableUint32{Value: uint32(opts.IndexOpts.CompactionThld)},
+		},
+	}
+}
+
+// UpdateDatabaseSettings changes the settings of an existing database, only the provided settings are changed.
+// Corruption checker, replication settings, storage quota, read-only mode and paranoid reads are applied right away,
+// store settings take effect the next time the database gets loaded
+func (s *ImmuServer) UpdateDatabaseSettings(ctx context.Context, req *schema.UpdateDatabaseSettingsRequest) (*schema.UpdateDatabaseSettingsResponse, error) {
+	s.Logger.Debugf("updatedatabasesettings")
+
+	if req == nil || req.Settings == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	if !s.Options.GetAuth() {
+		return nil, ErrAuthMustBeEnabled
+	}
+
+	if req.DatabaseName == s.Options.defaultDbName {
+		return nil, ErrReservedDatabase
+	}
+
+	db, err := s.dbList.GetByName(req.DatabaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin && !user.HasPermission(req.DatabaseName, auth.PermissionAdmin) {
+		return nil, fmt.Errorf("you do not have permission on this database")
+	}
+
+	s.replicationMux.Lock()
+	defer s.replicationMux.Unlock()
+
+	settings, err := s.loadSettings(req.DatabaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *settings
+
+	settings.update(req.Settings)
+
+	if settings.Replica != previous.Replica {
+		settings.ReplicationEpoch++
+	}
+
+	storeOpts := settings.StoreSettings.apply(s.storeOptionsForDb(req.DatabaseName, s.remoteStorage))
+	if !validStoreSettings(storeOpts) || !validSyncFallback(settings.SyncFallback) || !validReplicaFilters(settings.ReplicaFilters) ||
+		(settings.TLSCert == "") != (settings.TLSKey == "") {
+		return nil, ErrIllegalArguments
+	}
+
+	settings.UpdatedBy = user.Username
+	settings.UpdatedAt = time.Now()
+
+	err = s.saveSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	s.updateReplicationOptions(db, settings.replicationOptions())
+	db.UpdateCorruptionChecker(settings.CorruptionChecker)
+	db.UpdateMaxDiskSize(settings.MaxDiskSize)
+	db.UpdateReadOnly(settings.ReadOnly)
+	db.UpdateParanoidReads(settings.ParanoidReads)
+
+	s.auditReplicationChange(&previous, settings, user.Username, 0)
+
+	if previous.Replica && !settings.Replica {
+		s.emitReplicationEvent(&replicationEvent{
+			Kind:     ReplicationEventPromoted,
+			Database: req.DatabaseName,
+			Message:  fmt.Sprintf("promoted by %s", user.Username),
+		})
+	}
+
+	return &schema.UpdateDatabaseSettingsResponse{
+		DatabaseName: req.DatabaseName,
+		Settings:     settings.nullableSettings(storeOpts),
+	}, nil
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestStoreSettingsApply(t *testing.T) {
+	var ss *dbStoreSettings
+
+	defaultOpts := store.DefaultOptions()
+	require.Equal(t, defaultOpts, ss.apply(defaultOpts))
+
+	maxConcurrency := 5
+	cacheSize := 1000
+	synced := false
+
+	ss = &dbStoreSettings{
+		Synced:         &synced,
+		MaxConcurrency: &maxConcurrency,
+		IndexCacheSize: &cacheSize,
+	}
+
+	sharedIndexOpts := store.DefaultIndexOptions()
+
+	opts := ss.apply(store.DefaultOptions().WithIndexOptions(sharedIndexOpts))
+	require.False(t, opts.Synced)
+	require.Equal(t, 5, opts.MaxConcurrency)
+	require.Equal(t, store.DefaultMaxIOConcurrency, opts.MaxIOConcurrency)
+	require.Equal(t, 1000, opts.IndexOpts.CacheSize)
+	require.Equal(t, store.DefaultIndexOptions().CacheSize, sharedIndexOpts.CacheSize)
+}
+
+func TestServerUpdateDatabaseSettings(t *testing.T) {
+	ctx := context.Background()
+
+	serverOptions := DefaultOptions().
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	_, err = s.UpdateDatabaseSettings(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	lr, err := s.Login(ctx, &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: DefaultdbName,
+		Settings:     &schema.DatabaseNullableSettings{},
+	})
+	require.Equal(t, ErrReservedDatabase, err)
+
+	_, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "nodb",
+		Settings:     &schema.DatabaseNullableSettings{},
+	})
+	require.Equal(t, database.ErrDatabaseNotExists, err)
+
+	_, err = s.CreateDatabaseWith(adminCtx, &schema.DatabaseSettings{DatabaseName: "lisbon"})
+	require.NoError(t, err)
+
+	_, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			MaxIOConcurrency: &schema.NullableUint32{Value: store.MaxParallelIO + 1},
+		},
+	})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	res, err := s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			CorruptionChecker: &schema.NullableBool{Value: true},
+			ReplicationSettings: &schema.ReplicationNullableSettings{
+				SrcAddress: &schema.NullableString{Value: "127.0.0.1"},
+			},
+			MaxConcurrency: &schema.NullableUint32{Value: 20},
+			IndexSettings: &schema.IndexNullableSettings{
+				FlushThld: &schema.NullableUint32{Value: 1000},
+			},
+			MaxDiskSize: &schema.NullableUint64{Value: 1},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "lisbon", res.DatabaseName)
+	require.True(t, res.Settings.CorruptionChecker.Value)
+	require.Equal(t, "127.0.0.1", res.Settings.ReplicationSettings.SrcAddress.Value)
+	require.Equal(t, uint32(20), res.Settings.MaxConcurrency.Value)
+	require.Equal(t, uint32(store.DefaultMaxIOConcurrency), res.Settings.MaxIOConcurrency.Value)
+	require.Equal(t, uint32(1000), res.Settings.IndexSettings.FlushThld.Value)
+	require.Equal(t, uint64(1), res.Settings.MaxDiskSize.Value)
+
+	db, err := s.dbList.GetByName("lisbon")
+	require.NoError(t, err)
+	require.True(t, db.GetOptions().GetCorruptionChecker())
+	require.Equal(t, "127.0.0.1", db.GetOptions().GetReplicationOptions().SrcAddress)
+	require.Equal(t, int64(1), db.GetOptions().GetMaxDiskSize())
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+	require.Equal(t, database.ErrQuotaExceeded, err)
+
+	quotaUsage := s.metricFuncComputeDBQuotaUsage()
+	require.Len(t, quotaUsage, 1)
+	require.Greater(t, quotaUsage["lisbon"], float64(1))
+
+	_, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			ReplicationSettings: &schema.ReplicationNullableSettings{
+				SyncFallback: &schema.NullableString{Value: "retry"},
+			},
+		},
+	})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	res, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			ReplicationSettings: &schema.ReplicationNullableSettings{
+				SyncAcks:               &schema.NullableUint32{Value: 2},
+				SyncTimeout:            &schema.NullableUint32{Value: 100},
+				SyncFallback:           &schema.NullableString{Value: database.SyncFallbackFail},
+				MaxReplicaTxRate:       &schema.NullableUint32{Value: 50},
+				MaxReplicaByteRate:     &schema.NullableUint64{Value: 1 << 20},
+				MaxReplicationTxRate:   &schema.NullableUint32{Value: 100},
+				MaxReplicationByteRate: &schema.NullableUint64{Value: 4 << 20},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), res.Settings.ReplicationSettings.SyncAcks.Value)
+	require.Equal(t, uint32(100), res.Settings.ReplicationSettings.SyncTimeout.Value)
+	require.Equal(t, database.SyncFallbackFail, res.Settings.ReplicationSettings.SyncFallback.Value)
+	require.Equal(t, 2, db.GetOptions().GetReplicationOptions().SyncAcks)
+	require.Equal(t, 100*time.Millisecond, db.GetOptions().GetReplicationOptions().SyncTimeout)
+	require.Equal(t, uint32(50), res.Settings.ReplicationSettings.MaxReplicaTxRate.Value)
+	require.Equal(t, 50, db.GetOptions().GetReplicationOptions().MaxReplicaTxRate)
+	require.Equal(t, uint64(1<<20), res.Settings.ReplicationSettings.MaxReplicaByteRate.Value)
+	require.Equal(t, 1<<20, db.GetOptions().GetReplicationOptions().MaxReplicaByteRate)
+	require.Equal(t, uint32(100), res.Settings.ReplicationSettings.MaxReplicationTxRate.Value)
+	require.Equal(t, 100, db.GetOptions().GetReplicationOptions().MaxReplicationTxRate)
+	require.Equal(t, uint64(4<<20), res.Settings.ReplicationSettings.MaxReplicationByteRate.Value)
+	require.Equal(t, 4<<20, db.GetOptions().GetReplicationOptions().MaxReplicationByteRate)
+
+	_, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			ReplicationSettings: &schema.ReplicationNullableSettings{
+				ReplicaFilters: &schema.ReplicaFilters{Filters: []*schema.ReplicaFilter{{ReplicaId: "edge1"}}},
+			},
+		},
+	})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	res, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			ReplicationSettings: &schema.ReplicationNullableSettings{
+				ReplicaFilters: &schema.ReplicaFilters{Filters: []*schema.ReplicaFilter{
+					{ReplicaId: "edge2", KeyPrefixes: [][]byte{[]byte("tenant2/")}},
+					{ReplicaId: "edge1", KeyPrefixes: [][]byte{[]byte("tenant1/"), []byte("shared/")}},
+				}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Settings.ReplicationSettings.ReplicaFilters.Filters, 2)
+	require.Equal(t, "edge1", res.Settings.ReplicationSettings.ReplicaFilters.Filters[0].ReplicaId)
+	require.Equal(t, [][]byte{[]byte("tenant2/")}, db.GetOptions().GetReplicationOptions().ReplicaFilters["edge2"])
+
+	// filters are preserved unless explicitly provided
+	res, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			ReplicationSettings: &schema.ReplicationNullableSettings{
+				Partial: &schema.NullableBool{Value: true},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, res.Settings.ReplicationSettings.Partial.Value)
+	require.Len(t, res.Settings.ReplicationSettings.ReplicaFilters.Filters, 2)
+
+	res, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			ReplicationSettings: &schema.ReplicationNullableSettings{
+				ReplicaFilters: &schema.ReplicaFilters{},
+				Partial:        &schema.NullableBool{Value: false},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Empty(t, res.Settings.ReplicationSettings.ReplicaFilters.Filters)
+	require.Empty(t, db.GetOptions().GetReplicationOptions().ReplicaFilters)
+
+	// a client certificate requires its private key
+	_, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			ReplicationSettings: &schema.ReplicationNullableSettings{
+				TlsCert: &schema.NullableString{Value: "replica.pem"},
+			},
+		},
+	})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	res, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			ReplicationSettings: &schema.ReplicationNullableSettings{
+				TlsCert:       &schema.NullableString{Value: "replica.pem"},
+				TlsKey:        &schema.NullableString{Value: "replica.key"},
+				TlsCa:         &schema.NullableString{Value: "ca.pem"},
+				TlsServerName: &schema.NullableString{Value: "primary"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "replica.pem", res.Settings.ReplicationSettings.TlsCert.Value)
+	require.Equal(t, "ca.pem", res.Settings.ReplicationSettings.TlsCa.Value)
+	require.Equal(t, "primary", db.GetOptions().GetReplicationOptions().TLSServerName)
+	require.Equal(t, "replica.key", db.GetOptions().GetReplicationOptions().TLSKey)
+
+	res, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			ReplicationSettings: &schema.ReplicationNullableSettings{
+				SyncAcks: &schema.NullableUint32{Value: 0},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, db.GetOptions().GetReplicationOptions().SyncAcks)
+
+	// previously changed settings are preserved
+	res, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			CorruptionChecker: &schema.NullableBool{Value: false},
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, res.Settings.CorruptionChecker.Value)
+	require.Equal(t, uint32(20), res.Settings.MaxConcurrency.Value)
+	require.False(t, db.GetOptions().GetCorruptionChecker())
+
+	res, err = s.UpdateDatabaseSettings(adminCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings: &schema.DatabaseNullableSettings{
+			ReadOnly:      &schema.NullableBool{Value: true},
+			MaxDiskSize:   &schema.NullableUint64{Value: 0},
+			ParanoidReads: &schema.NullableBool{Value: true},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, res.Settings.ReadOnly.Value)
+	require.True(t, db.GetOptions().GetReadOnly())
+	require.True(t, res.Settings.ParanoidReads.Value)
+	require.True(t, db.GetOptions().GetParanoidReads())
+
+	_, err = db.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+	require.Equal(t, database.ErrIsReadOnly, err)
+
+	_, err = s.CreateUser(adminCtx, &schema.CreateUserRequest{
+		User:       []byte("alice"),
+		Password:   []byte("Alice1234!"),
+		Permission: auth.PermissionRW,
+		Database:   "lisbon",
+	})
+	require.NoError(t, err)
+
+	lr, err = s.Login(ctx, &schema.LoginRequest{User: []byte("alice"), Password: []byte("Alice1234!")})
+	require.NoError(t, err)
+
+	userCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.UpdateDatabaseSettings(userCtx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "lisbon",
+		Settings:     &schema.DatabaseNullableSettings{},
+	})
+	require.Error(t, err)
+
+	// store settings are used when the database gets loaded again
+	s.CloseDatabases()
+
+	s = DefaultServer().WithOptions(serverOptions.WithListener(bufconn.Listen(1024 * 1024))).(*ImmuServer)
+
+	err = s.Initialize()
+	require.NoError(t, err)
+
+	db, err = s.dbList.GetByName("lisbon")
+	require.NoError(t, err)
+	require.Equal(t, 20, db.GetOptions().GetStoreOptions().MaxConcurrency)
+	require.Equal(t, 1000, db.GetOptions().GetStoreOptions().IndexOpts.FlushThld)
+	require.Equal(t, "127.0.0.1", db.GetOptions().GetReplicationOptions().SrcAddress)
+	require.True(t, db.GetOptions().GetParanoidReads())
+	require.Equal(t, int64(0), db.GetOptions().GetMaxDiskSize())
+	require.True(t, db.GetOptions().GetReadOnly())
+
+	defaultDB, err := s.dbList.GetByName(DefaultdbName)
+	require.NoError(t, err)
+	require.Equal(t, s.Options.StoreOptions.MaxConcurrency, defaultDB.GetOptions().GetStoreOptions().MaxConcurrency)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"time"
+
+	"github.com/codenotary/immudb/pkg/database"
+)
+
+// databaseOpener returns the function used to open a database registered for lazy loading
+func (s *ImmuServer) databaseOpener(op *database.DbOptions) func() (database.DB, error) {
+	return func() (database.DB, error) {
+		start := time.Now()
+
+		db, err := database.OpenDb(op, s.sysDB, s.Logger)
+		if err != nil {
+			s.Logger.Errorf("Could not open database '%s': %v", op.GetDbName(), err)
+			return nil, err
+		}
+
+		err = s.restoreQuarantine(db)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		s.Logger.Infof("Database '%s' lazily loaded in %s", op.GetDbName(), time.Since(start))
+
+		return db, nil
+	}
+}
+
+// startDatabasesWarmUp opens in background the databases registered for lazy loading
+func (s *ImmuServer) startDatabasesWarmUp() {
+	if len(s.dbList.Unloaded()) == 0 {
+		return
+	}
+
+	s.warmUpDone = make(chan struct{})
+	s.warmUpFinished = make(chan struct{})
+
+	go s.databasesWarmUp(s.warmUpDone, s.warmUpFinished)
+}
+
+// stopDatabasesWarmUp interrupts the warm-up and waits for the database being opened, if any
+func (s *ImmuServer) stopDatabasesWarmUp() {
+	if s.warmUpDone == nil {
+		return
+	}
+
+	close(s.warmUpDone)
+	<-s.warmUpFinished
+
+	s.warmUpDone = nil
+	s.warmUpFinished = nil
+}
+
+func (s *ImmuServer) databasesWarmUp(done <-chan struct{}, finished chan<- struct{}) {
+	defer close(finished)
+
+	dbnames := s.dbList.Unloaded()
+
+	s.Logger.Infof("Warming up %d databases...", len(dbnames))
+
+	for _, dbname := range dbnames {
+		select {
+		case <-done:
+			s.Logger.Infof("Databases warm-up interrupted")
+			return
+		default:
+		}
+
+		// errors are logged by the opener, databases deleted in the meantime are skipped
+		s.dbList.GetByName(dbname)
+	}
+
+	s.Logger.Infof("Databases warm-up completed")
+}
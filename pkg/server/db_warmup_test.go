@@ -0,0 +1,111 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestServerLazyDatabaseLoading(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_lazy_loading").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	login := func(s *ImmuServer) context.Context {
+		lr, err := s.Login(context.Background(), &schema.LoginRequest{
+			User:     []byte(auth.SysAdminUsername),
+			Password: []byte(auth.SysAdminPassword),
+		})
+		require.NoError(t, err)
+
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+	}
+
+	ctx := login(s)
+
+	for i := 0; i < 3; i++ {
+		_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{DatabaseName: fmt.Sprintf("db%d", i)})
+		require.NoError(t, err)
+	}
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+
+	s = DefaultServer().WithOptions(options.WithLazyDatabaseLoading(true)).(*ImmuServer)
+
+	err = s.Initialize()
+	require.NoError(t, err)
+
+	require.True(t, s.multidbmode)
+	require.Equal(t, 1, s.dbList.Length())
+	require.Equal(t, []string{"db0", "db1", "db2"}, s.dbList.Unloaded())
+
+	ctx = login(s)
+
+	dbs, err := s.DatabaseList(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, dbs.Databases, 4)
+
+	ur, err := s.UseDatabase(ctx, &schema.Database{DatabaseName: "db1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"db0", "db2"}, s.dbList.Unloaded())
+
+	dbCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", ur.Token))
+
+	_, err = s.Set(dbCtx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+	require.NoError(t, err)
+
+	_, err = s.DeleteDatabase(ctx, &schema.DeleteDatabaseRequest{DatabaseName: "db2"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"db0"}, s.dbList.Unloaded())
+
+	s.startDatabasesWarmUp()
+
+	require.Eventually(t, func() bool {
+		return len(s.dbList.Unloaded()) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+
+	dbs, err = s.DatabaseList(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, dbs.Databases, 3)
+
+	s.stopDatabasesWarmUp()
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
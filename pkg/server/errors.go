@@ -38,6 +38,25 @@ var (
 	ErrPermissionDenied            = errors.New("permission denied")
 	ErrNotSupported                = errors.New("operation not supported")
 	ErrNotLoggedIn                 = errors.New("not logged in")
+	ErrScrubSourceRequired         = status.Error(codes.InvalidArgument, "a source is required to scrub a database which is not a replica")
+	ErrNotQuarantined              = status.Error(codes.FailedPrecondition, "database is not quarantined")
+	ErrInvalidCorruptionPolicy     = errors.New("invalid corruption policy")
+	ErrReplicationTLSRequired      = errors.New("the replication port requires TLS with client certificates")
+	ErrNotReplicationMethod        = status.Error(codes.PermissionDenied, "only replication operations are served on the replication port")
+	ErrClusterNotEnabled           = status.Error(codes.FailedPrecondition, "the server is not a node of a cluster")
+	ErrInvalidClusterOptions       = errors.New("invalid cluster options")
+	ErrClusterDiverged             = errors.New("the database diverges from the one of the cluster leader")
+	ErrNoClusterLeader             = errors.New("the database has no cluster leader")
+	ErrClusterTransportClosed      = errors.New("cluster transport closed")
+	ErrClusterAuthFailed           = errors.New("the peer failed to authenticate with the cluster secret")
+	ErrRestoredReplicaDiverged     = status.Error(codes.FailedPrecondition, "the restored database diverges from its primary")
+	ErrDivergedFromPrimary         = status.Error(codes.FailedPrecondition, "replication stopped, the database diverges from its primary as reported by its replication status")
+	ErrStaleReplicationEpoch       = status.Error(codes.Aborted, "the replication of the database was changed concurrently, the expected epoch is stale")
+	ErrClusterManagedReplication   = status.Error(codes.FailedPrecondition, "the replication role of the database is managed by its cluster")
+	ErrNotOwnReplica               = status.Error(codes.PermissionDenied, "replication accounts can only replicate as the replica named after them")
+	ErrFilteredSnapshot            = status.Error(codes.FailedPrecondition, "snapshots can not be taken for replicas with key filters")
+	ErrSnapshotSourceRequired      = status.Error(codes.FailedPrecondition, "snapshots can only be synced by full replicas with the address of their primary")
+	ErrSnapshotDiverged            = status.Error(codes.FailedPrecondition, "the snapshot does not follow the last transaction held by the replica")
 )
 
 func mapServerError(err error) error {
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"time"
+)
+
+// expirationSweepLimit is the max number of expired keys deleted from a database in a single sweep
+const expirationSweepLimit = 1000
+
+// startExpirationSweeper runs in background the periodic deletion of expired keys
+func (s *ImmuServer) startExpirationSweeper() {
+	if s.Options.ExpirySweepInterval <= 0 {
+		return
+	}
+
+	s.sweeperDone = make(chan struct{})
+	s.sweeperFinished = make(chan struct{})
+
+	go s.expirationSweeper(time.Duration(s.Options.ExpirySweepInterval)*time.Second, s.sweeperDone, s.sweeperFinished)
+}
+
+// stopExpirationSweeper interrupts the sweeper and waits for the sweep in progress, if any
+func (s *ImmuServer) stopExpirationSweeper() {
+	if s.sweeperDone == nil {
+		return
+	}
+
+	close(s.sweeperDone)
+	<-s.sweeperFinished
+
+	s.sweeperDone = nil
+	s.sweeperFinished = nil
+}
+
+func (s *ImmuServer) expirationSweeper(interval time.Duration, done <-chan struct{}, finished chan<- struct{}) {
+	defer close(finished)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		s.sweepExpiredKeys(done)
+	}
+}
+
+// sweepExpiredKeys deletes the expired keys of the opened databases, databases not loaded yet are skipped
+func (s *ImmuServer) sweepExpiredKeys(done <-chan struct{}) {
+	for i := 0; i < s.dbList.Length(); i++ {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		db := s.dbList.GetByIndex(int64(i))
+		if db == nil {
+			continue
+		}
+
+		n, err := db.SweepExpiredKeys(expirationSweepLimit)
+		if err != nil {
+			s.Logger.Warningf("Unable to delete expired keys of database '%s': %v", db.GetName(), err)
+			continue
+		}
+
+		if n > 0 {
+			s.Logger.Infof("%d expired keys deleted from database '%s'", n, db.GetName())
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServerExpirationSweeper(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_expiration_sweeper").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithExpirySweepInterval(1)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.ExpirableSet(ctx, &schema.ExpirableSetRequest{
+		KVs:       []*schema.KeyValue{{Key: []byte("session1"), Value: []byte("active")}},
+		ExpiresAt: time.Now().Add(-time.Second).Unix(),
+	})
+	require.NoError(t, err)
+
+	_, err = s.Get(ctx, &schema.KeyRequest{Key: []byte("session1")})
+	require.Error(t, err)
+
+	s.startExpirationSweeper()
+
+	require.Eventually(t, func() bool {
+		history, err := s.History(ctx, &schema.HistoryRequest{Key: []byte("session1")})
+		require.NoError(t, err)
+
+		return len(history.Entries) == 2 && history.Entries[1].Metadata.GetDeleted()
+	}, 10*time.Second, 100*time.Millisecond)
+
+	s.stopExpirationSweeper()
+	s.stopExpirationSweeper()
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+const failoverProbeTimeout = 5 * time.Second
+
+// startFailoverSupervisor runs in background the periodic probing of the primary of the replicas with automatic failover.
+// Cluster mode already elects a new leader on its own, so the supervisor is not needed there
+func (s *ImmuServer) startFailoverSupervisor() {
+	if s.Options.FailoverProbeInterval <= 0 || s.Options.ClusterOptions != nil {
+		return
+	}
+
+	s.failoverDone = make(chan struct{})
+	s.failoverFinished = make(chan struct{})
+	s.failedProbes = make(map[string]int)
+
+	go s.failoverSupervisor(time.Duration(s.Options.FailoverProbeInterval)*time.Second, s.failoverDone, s.failoverFinished)
+}
+
+// stopFailoverSupervisor interrupts the supervisor and waits for the probing in progress, if any
+func (s *ImmuServer) stopFailoverSupervisor() {
+	if s.failoverDone == nil {
+		return
+	}
+
+	close(s.failoverDone)
+	<-s.failoverFinished
+
+	s.failoverDone = nil
+	s.failoverFinished = nil
+}
+
+func (s *ImmuServer) failoverSupervisor(interval time.Duration, done <-chan struct{}, finished chan<- struct{}) {
+	defer close(finished)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		s.probePrimaries(done)
+	}
+}
+
+// probePrimaries probes the primary of every opened replica with automatic failover
+// and promotes the replicas whose primary failed too many consecutive probes
+func (s *ImmuServer) probePrimaries(done <-chan struct{}) {
+	for i := 0; i < s.dbList.Length(); i++ {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		db := s.dbList.GetByIndex(int64(i))
+		if db == nil {
+			continue
+		}
+
+		// partial replicas do not hold the whole history of their primary, they can not replace it
+		rOpts := db.GetOptions().GetReplicationOptions()
+		if rOpts == nil || !rOpts.Replica || rOpts.Partial || rOpts.SrcAddress == "" {
+			delete(s.failedProbes, db.GetName())
+			continue
+		}
+
+		settings, err := s.loadSettings(db.GetName())
+		if err != nil || !settings.AutoFailover {
+			delete(s.failedProbes, db.GetName())
+			continue
+		}
+
+		tlsConfig, err := replicationClientTLSConfig(rOpts)
+		if err != nil {
+			s.Logger.Warningf("Unable to probe the primary of replica '%s': %v", db.GetName(), err)
+			continue
+		}
+
+		if probeRemoteServer(rOpts.SrcAddress, rOpts.SrcPort, tlsConfig, failoverProbeTimeout) {
+			delete(s.failedProbes, db.GetName())
+			continue
+		}
+
+		s.failedProbes[db.GetName()]++
+
+		failures := s.failedProbes[db.GetName()]
+		if failures < s.Options.FailoverThreshold {
+			s.Logger.Warningf("Primary of replica '%s' at %s:%d is unreachable (%d/%d)",
+				db.GetName(), rOpts.SrcAddress, rOpts.SrcPort, failures, s.Options.FailoverThreshold)
+			continue
+		}
+
+		// a diverged replica holds a different history, promoting it would lose or rewrite transactions
+		if s.divergenceOf(db.GetName()) != nil {
+			s.Logger.Errorf("Primary of replica '%s' is down but the replica diverges from it, automatic failover skipped", db.GetName())
+			continue
+		}
+
+		err = s.promoteReplica(db)
+		if err != nil {
+			s.Logger.Errorf("Unable to promote replica '%s': %v", db.GetName(), err)
+			continue
+		}
+
+		delete(s.failedProbes, db.GetName())
+	}
+}
+
+// promoteReplica turns a replica into a primary, recording which primary it replaces
+func (s *ImmuServer) promoteReplica(db database.DB) error {
+	s.replicationMux.Lock()
+	defer s.replicationMux.Unlock()
+
+	// the replication role may have been changed since the probe
+	settings, err := s.loadSettings(db.GetName())
+	if err != nil {
+		return err
+	}
+
+	if !settings.Replica {
+		return nil
+	}
+
+	promotedFrom := fmt.Sprintf("database '%s' at %s:%d", settings.SrcDatabase, settings.SrcAddress, settings.SrcPort)
+
+	settings.Replica = false
+	settings.PromotedAt = time.Now()
+	settings.PromotedFrom = promotedFrom
+	settings.UpdatedBy = "failover supervisor"
+	settings.UpdatedAt = settings.PromotedAt
+	settings.ReplicationEpoch++
+
+	err = s.saveSettings(settings)
+	if err != nil {
+		return err
+	}
+
+	s.updateReplicationOptions(db, settings.replicationOptions())
+
+	s.Logger.Errorf("Primary of replica '%s', %s, failed %d consecutive probes, replica promoted to primary",
+		db.GetName(), promotedFrom, s.Options.FailoverThreshold)
+
+	s.auditReplication(db.GetName(), ReplicationAuditPromoted, settings.UpdatedBy, 0,
+		fmt.Sprintf("primary %s failed %d consecutive probes", promotedFrom, s.Options.FailoverThreshold))
+
+	s.emitReplicationEvent(&replicationEvent{
+		Kind:     ReplicationEventPromoted,
+		Database: db.GetName(),
+		Message:  fmt.Sprintf("promoted by the failover supervisor, replacing %s", promotedFrom),
+	})
+
+	return nil
+}
+
+// probeRemoteServer reports whether the server at the given address answers a health check.
+// Only a server which can not be reached is considered down, any other error comes from a live server
+func probeRemoteServer(address string, port int, tlsConfig *tls.Config, timeout time.Duration) bool {
+	transportOpt := grpc.WithInsecure()
+	if tlsConfig != nil {
+		transportOpt = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+
+	conn, err := grpc.Dial(fmt.Sprintf("%s:%d", address, port), transportOpt)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	_, err = schema.NewImmuServiceClient(conn).Health(ctx, &empty.Empty{})
+
+	code := status.Code(err)
+
+	return code != codes.Unavailable && code != codes.DeadlineExceeded
+}
+
+// PrimaryEndpoint returns the address of the server accepting the writes of the database in use,
+// that is the primary of a replica or the server itself, possibly after the promotion of the replica
+func (s *ImmuServer) PrimaryEndpoint(ctx context.Context, _ *empty.Empty) (*schema.PrimaryEndpointResponse, error) {
+	db, err := s.getDBFromCtx(ctx, "PrimaryEndpoint")
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.loadSettings(db.GetName())
+	if err == store.ErrKeyNotFound {
+		settings = &dbSettings{Database: db.GetName()}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if settings.Replica && settings.SrcAddress != "" {
+		return &schema.PrimaryEndpointResponse{
+			Database: settings.SrcDatabase,
+			Address:  fmt.Sprintf("%s:%d", settings.SrcAddress, settings.SrcPort),
+		}, nil
+	}
+
+	res := &schema.PrimaryEndpointResponse{
+		Database: db.GetName(),
+		Address:  s.Options.advertisedAddress(),
+	}
+
+	if !settings.PromotedAt.IsZero() {
+		res.Promoted = true
+		res.PromotedAt = settings.PromotedAt.Unix()
+		res.PromotedFrom = settings.PromotedFrom
+	}
+
+	return res, nil
+}
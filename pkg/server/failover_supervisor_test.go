@@ -0,0 +1,158 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestProbeRemoteServer(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	// a server not exposing the immudb service is still alive
+	grpcSrv := grpc.NewServer()
+	go grpcSrv.Serve(lis)
+
+	port := lis.Addr().(*net.TCPAddr).Port
+
+	require.True(t, probeRemoteServer("127.0.0.1", port, nil, failoverProbeTimeout))
+
+	grpcSrv.Stop()
+
+	require.False(t, probeRemoteServer("127.0.0.1", port, nil, failoverProbeTimeout))
+}
+
+func TestServerFailoverSupervisor(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_failover_supervisor").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithFailoverThreshold(2).
+		WithAdvertisedAddress("replica.example.com:3322")
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	s.failedProbes = make(map[string]int)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	// the primary of the replica is down
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	port := lis.Addr().(*net.TCPAddr).Port
+	lis.Close()
+
+	_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{
+		DatabaseName: "replicadb",
+		Replica:      true,
+		SrcDatabase:  "primarydb",
+		SrcAddress:   "127.0.0.1",
+		SrcPort:      uint32(port),
+	})
+	require.NoError(t, err)
+
+	ur, err := s.UseDatabase(ctx, &schema.Database{DatabaseName: "replicadb"})
+	require.NoError(t, err)
+
+	dbCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", ur.Token))
+
+	endpoint, err := s.PrimaryEndpoint(dbCtx, &empty.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, "primarydb", endpoint.Database)
+	require.Equal(t, fmt.Sprintf("127.0.0.1:%d", port), endpoint.Address)
+	require.False(t, endpoint.Promoted)
+
+	// replicas are not promoted unless they opted in
+	for i := 0; i < 3; i++ {
+		s.probePrimaries(make(chan struct{}))
+	}
+
+	replica, err := s.dbList.GetByName("replicadb")
+	require.NoError(t, err)
+	require.True(t, replica.IsReplica())
+
+	_, err = s.UpdateDatabaseSettings(ctx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "replicadb",
+		Settings: &schema.DatabaseNullableSettings{
+			ReplicationSettings: &schema.ReplicationNullableSettings{
+				AutoFailover: &schema.NullableBool{Value: true},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	s.probePrimaries(make(chan struct{}))
+	require.True(t, replica.IsReplica())
+	require.Equal(t, 1, s.failedProbes["replicadb"])
+
+	// a diverged replica is never promoted
+	s.diverged["replicadb"] = &replicaDivergence{Primary: "primarydb"}
+
+	s.probePrimaries(make(chan struct{}))
+	require.True(t, replica.IsReplica())
+
+	s.diverged["replicadb"] = nil
+
+	s.probePrimaries(make(chan struct{}))
+	require.False(t, replica.IsReplica())
+	require.Empty(t, s.failedProbes)
+
+	settings, err := s.loadSettings("replicadb")
+	require.NoError(t, err)
+	require.False(t, settings.Replica)
+	require.True(t, settings.AutoFailover)
+	require.False(t, settings.PromotedAt.IsZero())
+
+	endpoint, err = s.PrimaryEndpoint(dbCtx, &empty.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, "replicadb", endpoint.Database)
+	require.Equal(t, "replica.example.com:3322", endpoint.Address)
+	require.True(t, endpoint.Promoted)
+	require.Equal(t, settings.PromotedAt.Unix(), endpoint.PromotedAt)
+	require.Contains(t, endpoint.PromotedFrom, "primarydb")
+
+	// the promoted database accepts writes
+	_, err = s.Set(dbCtx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+}
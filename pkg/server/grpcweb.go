@@ -0,0 +1,262 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	grpcWebContentType     = "application/grpc-web"
+	grpcWebTextContentType = "application/grpc-web-text"
+	grpcContentType        = "application/grpc"
+
+	// grpcWebTrailerFlag marks the message frame holding the trailers, which can not be sent as HTTP/1.1 trailers
+	grpcWebTrailerFlag byte = 0x80
+)
+
+// GrpcWebHandler serves gRPC-Web requests through the gRPC server, so that browsers can call
+// the gRPC API over HTTP/1.1 without a separate proxy
+type GrpcWebHandler struct {
+	grpcServer     *grpc.Server
+	allowedOrigins map[string]struct{}
+	anyOrigin      bool
+}
+
+// NewGrpcWebHandler returns a handler of the gRPC-Web requests. Cross-origin requests are accepted from
+// the allowed origins only, "*" allows any origin but without credentials
+func NewGrpcWebHandler(grpcServer *grpc.Server, allowedOrigins []string) *GrpcWebHandler {
+	h := &GrpcWebHandler{
+		grpcServer:     grpcServer,
+		allowedOrigins: make(map[string]struct{}, len(allowedOrigins)),
+	}
+
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			h.anyOrigin = true
+			continue
+		}
+		h.allowedOrigins[strings.TrimSuffix(origin, "/")] = struct{}{}
+	}
+
+	return h
+}
+
+// IsGrpcWebRequest reports if the request is a gRPC-Web call or the CORS preflight request of one
+func (h *GrpcWebHandler) IsGrpcWebRequest(r *http.Request) bool {
+	if r.Method == http.MethodPost {
+		return strings.HasPrefix(r.Header.Get("Content-Type"), grpcWebContentType)
+	}
+
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+			if strings.EqualFold(strings.TrimSpace(header), "x-grpc-web") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (h *GrpcWebHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+
+	if origin != "" {
+		// credentials are only shared with the origins explicitly allowed, never with any origin
+		switch {
+		case h.isListedOrigin(origin):
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+			w.Header().Add("Vary", "Origin")
+		case h.anyOrigin:
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		default:
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+	}
+
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", http.MethodPost)
+		w.Header().Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+		w.Header().Set("Access-Control-Max-Age", "600")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	textMode := strings.HasPrefix(contentType, grpcWebTextContentType)
+
+	req := r.Clone(r.Context())
+	req.ProtoMajor = 2
+	req.ProtoMinor = 0
+	req.Header.Set("Content-Type", grpcContentType+strings.TrimPrefix(strings.TrimPrefix(contentType, grpcWebTextContentType), grpcWebContentType))
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+
+	if textMode {
+		req.Body = ioutil.NopCloser(base64.NewDecoder(base64.StdEncoding, r.Body))
+	}
+
+	resp := newGrpcWebResponse(w, contentType, textMode)
+
+	h.grpcServer.ServeHTTP(resp, req)
+
+	resp.finish()
+}
+
+func (h *GrpcWebHandler) isListedOrigin(origin string) bool {
+	_, listed := h.allowedOrigins[strings.TrimSuffix(origin, "/")]
+	return listed
+}
+
+// grpcWebResponse turns the response of the gRPC server into a gRPC-Web one,
+// trailers are written as the last message frame of the body
+type grpcWebResponse struct {
+	w             http.ResponseWriter
+	header        http.Header
+	contentType   string
+	textMode      bool
+	headerWritten bool
+}
+
+func newGrpcWebResponse(w http.ResponseWriter, contentType string, textMode bool) *grpcWebResponse {
+	return &grpcWebResponse{
+		w:           w,
+		header:      make(http.Header),
+		contentType: contentType,
+		textMode:    textMode,
+	}
+}
+
+func (r *grpcWebResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *grpcWebResponse) WriteHeader(statusCode int) {
+	if r.headerWritten {
+		return
+	}
+
+	trailers := r.declaredTrailers()
+
+	h := r.w.Header()
+
+	var exposed []string
+
+	for k, vv := range r.header {
+		if k == "Trailer" || k == "Content-Type" || k == "Content-Length" {
+			continue
+		}
+		if _, isTrailer := trailers[k]; isTrailer || strings.HasPrefix(k, http.TrailerPrefix) {
+			continue
+		}
+
+		h[k] = vv
+		exposed = append(exposed, k)
+	}
+
+	h.Set("Content-Type", r.contentType)
+
+	// browsers only expose the headers listed in cross-origin responses
+	if h.Get("Access-Control-Allow-Origin") != "" {
+		sort.Strings(exposed)
+		h.Set("Access-Control-Expose-Headers", strings.Join(append(exposed, "Grpc-Status", "Grpc-Message"), ", "))
+	}
+
+	r.w.WriteHeader(statusCode)
+	r.headerWritten = true
+}
+
+func (r *grpcWebResponse) Write(b []byte) (int, error) {
+	if !r.headerWritten {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.textMode {
+		_, err := io.WriteString(r.w, base64.StdEncoding.EncodeToString(b))
+		return len(b), err
+	}
+
+	return r.w.Write(b)
+}
+
+func (r *grpcWebResponse) Flush() {
+	if !r.headerWritten {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if f, ok := r.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// finish writes the trailers set by the gRPC server as a message frame
+func (r *grpcWebResponse) finish() {
+	var trailers bytes.Buffer
+
+	declared := r.declaredTrailers()
+
+	keys := make([]string, 0, len(r.header))
+	for k := range r.header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		name := strings.TrimPrefix(k, http.TrailerPrefix)
+
+		_, isTrailer := declared[k]
+		if !isTrailer && name == k {
+			continue
+		}
+
+		for _, v := range r.header[k] {
+			trailers.WriteString(strings.ToLower(name) + ": " + v + "\r\n")
+		}
+	}
+
+	frame := make([]byte, 5+trailers.Len())
+	frame[0] = grpcWebTrailerFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(trailers.Len()))
+	copy(frame[5:], trailers.Bytes())
+
+	r.Write(frame)
+	r.Flush()
+}
+
+func (r *grpcWebResponse) declaredTrailers() map[string]struct{} {
+	trailers := make(map[string]struct{})
+
+	for _, vv := range r.header["Trailer"] {
+		for _, k := range strings.Split(vv, ",") {
+			trailers[http.CanonicalHeaderKey(strings.TrimSpace(k))] = struct{}{}
+		}
+	}
+
+	return trailers
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func grpcWebCall(t *testing.T, url, contentType, token, origin string, req, res proto.Message) (*http.Response, map[string]string) {
+	msg, err := proto.Marshal(req)
+	require.NoError(t, err)
+
+	body := make([]byte, 5+len(msg))
+	binary.BigEndian.PutUint32(body[1:], uint32(len(msg)))
+	copy(body[5:], msg)
+
+	if contentType == grpcWebTextContentType {
+		body = []byte(base64.StdEncoding.EncodeToString(body))
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+
+	httpReq.Header.Set("Content-Type", contentType)
+	httpReq.Header.Set("X-Grpc-Web", "1")
+	if token != "" {
+		httpReq.Header.Set("Authorization", token)
+	}
+	if origin != "" {
+		httpReq.Header.Set("Origin", origin)
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	trailers := make(map[string]string)
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, trailers
+	}
+
+	if contentType == grpcWebTextContentType {
+		var decoded []byte
+
+		// each write of the server is encoded on its own
+		for len(respBody) > 0 {
+			n := strings.Index(string(respBody), "=")
+			if n < 0 {
+				n = len(respBody)
+			} else {
+				for n < len(respBody) && respBody[n] == '=' {
+					n++
+				}
+			}
+
+			b, err := base64.StdEncoding.DecodeString(string(respBody[:n]))
+			require.NoError(t, err)
+
+			decoded = append(decoded, b...)
+			respBody = respBody[n:]
+		}
+
+		respBody = decoded
+	}
+
+	for len(respBody) > 0 {
+		require.GreaterOrEqual(t, len(respBody), 5)
+
+		flag := respBody[0]
+		size := int(binary.BigEndian.Uint32(respBody[1:]))
+		frame := respBody[5 : 5+size]
+		respBody = respBody[5+size:]
+
+		if flag == grpcWebTrailerFlag {
+			for _, line := range strings.Split(strings.TrimSpace(string(frame)), "\r\n") {
+				kv := strings.SplitN(line, ": ", 2)
+				require.Len(t, kv, 2)
+				trailers[kv[0]] = kv[1]
+			}
+			continue
+		}
+
+		err = proto.Unmarshal(frame, res)
+		require.NoError(t, err)
+	}
+
+	return resp, trailers
+}
+
+func TestGrpcWeb(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_grpcweb").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+	defer os.RemoveAll(options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	grpcWeb := NewGrpcWebHandler(s.GrpcServer, []string{"https://app.immudb.io/"})
+
+	httpServer := httptest.NewServer(grpcWeb)
+	defer httpServer.Close()
+
+	t.Run("binary mode", func(t *testing.T) {
+		var lr schema.LoginResponse
+
+		resp, trailers := grpcWebCall(t,
+			httpServer.URL+"/immudb.schema.ImmuService/Login",
+			grpcWebContentType+"+proto", "", "",
+			&schema.LoginRequest{User: []byte(auth.SysAdminUsername), Password: []byte(auth.SysAdminPassword)},
+			&lr,
+		)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, grpcWebContentType+"+proto", resp.Header.Get("Content-Type"))
+		require.Equal(t, "0", trailers["grpc-status"])
+		require.NotEmpty(t, lr.Token)
+
+		t.Run("text mode", func(t *testing.T) {
+			var state schema.ImmutableState
+
+			resp, trailers := grpcWebCall(t,
+				httpServer.URL+"/immudb.schema.ImmuService/CurrentState",
+				grpcWebTextContentType, lr.Token, "https://app.immudb.io",
+				&emptypb.Empty{},
+				&state,
+			)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, "https://app.immudb.io", resp.Header.Get("Access-Control-Allow-Origin"))
+			require.Contains(t, resp.Header.Get("Access-Control-Expose-Headers"), "Grpc-Status")
+			require.Equal(t, "0", trailers["grpc-status"])
+			require.Equal(t, DefaultdbName, state.Db)
+		})
+	})
+
+	t.Run("errors are returned in the trailers", func(t *testing.T) {
+		var state schema.ImmutableState
+
+		resp, trailers := grpcWebCall(t,
+			httpServer.URL+"/immudb.schema.ImmuService/CurrentState",
+			grpcWebContentType, "", "",
+			&emptypb.Empty{},
+			&state,
+		)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NotEqual(t, "0", trailers["grpc-status"])
+		require.NotEmpty(t, trailers["grpc-message"])
+	})
+
+	t.Run("cors", func(t *testing.T) {
+		preflight := func(origin string) *http.Response {
+			req, err := http.NewRequest(http.MethodOptions, httpServer.URL+"/immudb.schema.ImmuService/CurrentState", nil)
+			require.NoError(t, err)
+
+			req.Header.Set("Origin", origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			req.Header.Set("Access-Control-Request-Headers", "content-type, x-grpc-web, authorization")
+
+			require.True(t, grpcWeb.IsGrpcWebRequest(req))
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			return resp
+		}
+
+		resp := preflight("https://app.immudb.io")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		require.Equal(t, "https://app.immudb.io", resp.Header.Get("Access-Control-Allow-Origin"))
+		require.Equal(t, "content-type, x-grpc-web, authorization", resp.Header.Get("Access-Control-Allow-Headers"))
+
+		resp = preflight("https://evil.io")
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+		require.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+		var lr schema.LoginResponse
+
+		resp, _ = grpcWebCall(t,
+			httpServer.URL+"/immudb.schema.ImmuService/Login",
+			grpcWebContentType, "", "https://evil.io",
+			&schema.LoginRequest{User: []byte(auth.SysAdminUsername), Password: []byte(auth.SysAdminPassword)},
+			&lr,
+		)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("other requests are not grpc-web ones", func(t *testing.T) {
+		require.False(t, grpcWeb.IsGrpcWebRequest(httptest.NewRequest(http.MethodGet, "/api/health", nil)))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/login", nil)
+		req.Header.Set("Content-Type", "application/json")
+		require.False(t, grpcWeb.IsGrpcWebRequest(req))
+
+		req = httptest.NewRequest(http.MethodOptions, "/api/login", nil)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		require.False(t, grpcWeb.IsGrpcWebRequest(req))
+	})
+}
+
+func TestGrpcWebAnyOrigin(t *testing.T) {
+	preflight := func(h *GrpcWebHandler, origin string) *http.Response {
+		req := httptest.NewRequest(http.MethodOptions, "/immudb.schema.ImmuService/CurrentState", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "content-type, x-grpc-web")
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w.Result()
+	}
+
+	h := NewGrpcWebHandler(nil, []string{"*", "https://app.immudb.io/"})
+
+	// any origin is allowed, but browsers do not send it credentials
+	resp := preflight(h, "https://other.io")
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+	require.Empty(t, resp.Header.Get("Access-Control-Allow-Credentials"))
+
+	resp = preflight(h, "https://app.immudb.io")
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.Equal(t, "https://app.immudb.io", resp.Header.Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "true", resp.Header.Get("Access-Control-Allow-Credentials"))
+
+	resp = preflight(NewGrpcWebHandler(nil, []string{"https://app.immudb.io"}), "https://other.io")
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	require.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/database"
+)
+
+// startIndexVerifier runs in background the periodic verification of the indexes of the databases against their commit logs
+func (s *ImmuServer) startIndexVerifier() {
+	if s.Options.IndexVerificationInterval <= 0 {
+		return
+	}
+
+	s.indexVerifierDone = make(chan struct{})
+	s.indexVerifierFinished = make(chan struct{})
+
+	go s.indexVerifier(time.Duration(s.Options.IndexVerificationInterval)*time.Second, s.indexVerifierDone, s.indexVerifierFinished)
+}
+
+// stopIndexVerifier interrupts the verifier and waits for the verification in progress, if any
+func (s *ImmuServer) stopIndexVerifier() {
+	if s.indexVerifierDone == nil {
+		return
+	}
+
+	close(s.indexVerifierDone)
+	<-s.indexVerifierFinished
+
+	s.indexVerifierDone = nil
+	s.indexVerifierFinished = nil
+}
+
+func (s *ImmuServer) indexVerifier(interval time.Duration, done <-chan struct{}, finished chan<- struct{}) {
+	defer close(finished)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		s.verifyIndexes(done)
+	}
+}
+
+// verifyIndexes verifies the index of every opened database, databases not loaded yet are skipped
+func (s *ImmuServer) verifyIndexes(done <-chan struct{}) {
+	for i := 0; i < s.dbList.Length(); i++ {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		db := s.dbList.GetByIndex(int64(i))
+		if db == nil {
+			continue
+		}
+
+		_, err := s.verifyIndex(db, done)
+		if err == store.ErrIndexVerificationCancelled {
+			return
+		}
+		if err != nil {
+			s.Logger.Warningf("Unable to verify the index of database '%s': %v", db.GetName(), err)
+		}
+	}
+}
+
+// verifyIndex checks the index of the database against its commit log and, when enabled,
+// rebuilds it if found inconsistent. The returned verification is the one taken after the rebuild, if any
+func (s *ImmuServer) verifyIndex(db database.DB, done <-chan struct{}) (*store.IndexVerification, error) {
+	verification, err := db.VerifyIndex(done)
+	if err != nil {
+		return nil, err
+	}
+
+	Metrics.UpdateVerificationMetrics(db.GetName(), verificationSourceIndexVerifier, verification.EntriesCount, indexMismatches(verification))
+
+	if verification.Consistent {
+		return verification, nil
+	}
+
+	s.Logger.Errorf("Index of database '%s' is inconsistent with its commit log up to tx %d: %d dangling keys, %d indexed revisions, %d committed entries",
+		db.GetName(), verification.IndexedTx, verification.DanglingKeys, verification.RevisionCount, verification.EntriesCount)
+
+	if !s.Options.IndexAutoRebuild {
+		return verification, nil
+	}
+
+	err = db.RebuildIndex()
+	if err != nil {
+		return verification, err
+	}
+
+	verification, err = db.VerifyIndex(done)
+	if err != nil {
+		return nil, err
+	}
+
+	if verification.Consistent {
+		s.Logger.Infof("Index of database '%s' rebuilt", db.GetName())
+	} else {
+		s.Logger.Errorf("Index of database '%s' is still inconsistent with its commit log after being rebuilt", db.GetName())
+	}
+
+	return verification, nil
+}
+
+// indexMismatches is the number of dangling keys found in the index, or one when only the revision counts or digests differ
+func indexMismatches(verification *store.IndexVerification) uint64 {
+	if verification.Consistent {
+		return 0
+	}
+
+	if verification.DanglingKeys > 0 {
+		return verification.DanglingKeys
+	}
+
+	return 1
+}
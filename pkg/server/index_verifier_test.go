@@ -0,0 +1,116 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// inconsistentIndexDB reports its index as inconsistent until it gets rebuilt
+type inconsistentIndexDB struct {
+	database.DB
+	rebuilds int
+}
+
+func (db *inconsistentIndexDB) VerifyIndex(cancellation <-chan struct{}) (*store.IndexVerification, error) {
+	verification, err := db.DB.VerifyIndex(cancellation)
+	if err != nil {
+		return nil, err
+	}
+
+	if db.rebuilds == 0 {
+		verification.DanglingKeys = 1
+		verification.Consistent = false
+	}
+
+	return verification, nil
+}
+
+func (db *inconsistentIndexDB) RebuildIndex() error {
+	db.rebuilds++
+	return db.DB.RebuildIndex()
+}
+
+func TestServerIndexVerifier(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_index_verifier").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithIndexVerificationInterval(1)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	db := &inconsistentIndexDB{DB: s.dbList.GetByIndex(defaultDbIndex)}
+
+	mismatches := testutil.ToFloat64(Metrics.VerificationMismatchesCounters.WithLabelValues(DefaultdbName, verificationSourceIndexVerifier))
+
+	verification, err := s.verifyIndex(db, nil)
+	require.NoError(t, err)
+	require.False(t, verification.Consistent)
+	require.Zero(t, db.rebuilds)
+	require.Equal(t, mismatches+1, testutil.ToFloat64(Metrics.VerificationMismatchesCounters.WithLabelValues(DefaultdbName, verificationSourceIndexVerifier)))
+
+	s.Options.WithIndexAutoRebuild(true)
+
+	verification, err = s.verifyIndex(db, nil)
+	require.NoError(t, err)
+	require.True(t, verification.Consistent)
+	require.Equal(t, 1, db.rebuilds)
+
+	done := make(chan struct{})
+	close(done)
+
+	_, err = s.verifyIndex(db, done)
+	require.Equal(t, store.ErrIndexVerificationCancelled, err)
+
+	s.startIndexVerifier()
+
+	s.stopIndexVerifier()
+	s.stopIndexVerifier()
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
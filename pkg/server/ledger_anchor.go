@@ -0,0 +1,239 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/anchor"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+)
+
+// anchorPublishTimeout bounds each publication to the external ledger
+const anchorPublishTimeout = 30 * time.Second
+
+// anchorReceipt is the receipt of a database root published to an external ledger as stored in the system database.
+// Every receipt is written as a new revision of the same key, so the history of the key is the receipt chain
+type anchorReceipt struct {
+	TxID        uint64    `json:"txId"`
+	TxHash      []byte    `json:"txHash"`
+	Ledger      string    `json:"ledger"`
+	PublishedAt time.Time `json:"publishedAt"`
+	Receipt     []byte    `json:"receipt"`
+}
+
+func anchorReceiptKey(database string) []byte {
+	key := make([]byte, 1+len(database))
+	key[0] = KeyPrefixAnchorReceipt
+	copy(key[1:], []byte(database))
+	return key
+}
+
+// startLedgerAnchoring runs in background the periodic publication of the database roots to the external ledger
+func (s *ImmuServer) startLedgerAnchoring() {
+	if s.Options.AnchorBackend == "" || s.Options.AnchorInterval <= 0 {
+		return
+	}
+
+	if s.sysDB.IsReplica() {
+		s.Logger.Warningf("Anchoring of database roots is disabled on replicas")
+		return
+	}
+
+	if s.anchorPublisher == nil {
+		publisher, err := anchor.NewPublisher(s.Options.AnchorBackend, s.Options.AnchorURL, anchorPublishTimeout)
+		if err != nil {
+			s.Logger.Errorf("Unable to create anchoring publisher: %v", err)
+			return
+		}
+
+		s.anchorPublisher = publisher
+	}
+
+	s.anchorDone = make(chan struct{})
+	s.anchorFinished = make(chan struct{})
+
+	go s.ledgerAnchoring(time.Duration(s.Options.AnchorInterval)*time.Second, s.anchorDone, s.anchorFinished)
+}
+
+// stopLedgerAnchoring interrupts the anchoring and waits for the publication in progress, if any
+func (s *ImmuServer) stopLedgerAnchoring() {
+	if s.anchorDone == nil {
+		return
+	}
+
+	close(s.anchorDone)
+	<-s.anchorFinished
+
+	s.anchorDone = nil
+	s.anchorFinished = nil
+}
+
+func (s *ImmuServer) ledgerAnchoring(interval time.Duration, done <-chan struct{}, finished chan<- struct{}) {
+	defer close(finished)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		s.publishRoots(done)
+	}
+}
+
+// publishRoots publishes the roots of the opened databases which changed since their last receipt,
+// databases not loaded yet are skipped
+func (s *ImmuServer) publishRoots(done <-chan struct{}) {
+	for i := 0; i < s.dbList.Length(); i++ {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		db := s.dbList.GetByIndex(int64(i))
+		if db == nil {
+			continue
+		}
+
+		receipt, err := s.publishRoot(db)
+		if err != nil {
+			s.Logger.Warningf("Unable to publish root of database '%s': %v", db.GetName(), err)
+			continue
+		}
+
+		if receipt != nil {
+			s.Logger.Infof("Root of database '%s' at tx %d published to %s", db.GetName(), receipt.TxID, receipt.Ledger)
+		}
+	}
+}
+
+// publishRoot publishes the current root of db to the external ledger and stores the returned receipt.
+// It returns nil when the root is already published
+func (s *ImmuServer) publishRoot(db database.DB) (*anchorReceipt, error) {
+	state, err := db.CurrentState()
+	if err != nil {
+		return nil, err
+	}
+
+	if state.TxId == 0 {
+		return nil, nil
+	}
+
+	key := anchorReceiptKey(db.GetName())
+
+	e, err := s.sysDB.Get(&schema.KeyRequest{Key: key})
+	if err == nil {
+		var last anchorReceipt
+
+		err = json.Unmarshal(e.Value, &last)
+		if err != nil {
+			return nil, err
+		}
+
+		if last.TxID == state.TxId {
+			return nil, nil
+		}
+	} else if err != store.ErrKeyNotFound {
+		return nil, err
+	}
+
+	receipt, err := s.anchorPublisher.Publish(&anchor.Root{
+		Database: db.GetName(),
+		TxID:     state.TxId,
+		TxHash:   state.TxHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	published := &anchorReceipt{
+		TxID:        state.TxId,
+		TxHash:      state.TxHash,
+		Ledger:      receipt.Ledger,
+		PublishedAt: receipt.PublishedAt,
+		Receipt:     receipt.Data,
+	}
+
+	receiptData, err := json.Marshal(published)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.sysDB.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: key, Value: receiptData}}})
+	if err != nil {
+		return nil, err
+	}
+
+	return published, nil
+}
+
+// AnchorReceipts returns the chain of receipts of the roots of the database in use published to the external ledger
+func (s *ImmuServer) AnchorReceipts(ctx context.Context, req *schema.AnchorReceiptsRequest) (*schema.AnchorReceiptList, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	db, err := s.getDBFromCtx(ctx, "AnchorReceipts")
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.sysDB.History(&schema.HistoryRequest{
+		Key:   anchorReceiptKey(db.GetName()),
+		Limit: req.Limit,
+		Desc:  req.Desc,
+	})
+	if err == store.ErrKeyNotFound || err == store.ErrNoMoreEntries {
+		return &schema.AnchorReceiptList{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := &schema.AnchorReceiptList{
+		Receipts: make([]*schema.AnchorReceipt, len(entries.Entries)),
+	}
+
+	for i, e := range entries.Entries {
+		var receipt anchorReceipt
+
+		err = json.Unmarshal(e.Value, &receipt)
+		if err != nil {
+			return nil, err
+		}
+
+		list.Receipts[i] = &schema.AnchorReceipt{
+			TxId:        receipt.TxID,
+			TxHash:      receipt.TxHash,
+			Ledger:      receipt.Ledger,
+			PublishedAt: receipt.PublishedAt.Unix(),
+			Receipt:     receipt.Receipt,
+		}
+	}
+
+	return list, nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/anchor"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type mockPublisher struct {
+	err   error
+	roots []*anchor.Root
+}
+
+func (p *mockPublisher) Ledger() string {
+	return "mock"
+}
+
+func (p *mockPublisher) Publish(root *anchor.Root) (*anchor.Receipt, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	p.roots = append(p.roots, root)
+
+	return &anchor.Receipt{
+		Ledger:      p.Ledger(),
+		PublishedAt: time.Unix(1630490400, 0),
+		Data:        append([]byte("receipt"), root.TxHash...),
+	}, nil
+}
+
+func TestServerLedgerAnchoring(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_ledger_anchoring").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithAnchorBackend("unknown").
+		WithAnchorInterval(1)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	// unknown backends are reported and anchoring is not started
+	require.Nil(t, s.anchorDone)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.AnchorReceipts(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	receipts, err := s.AnchorReceipts(ctx, &schema.AnchorReceiptsRequest{})
+	require.NoError(t, err)
+	require.Empty(t, receipts.Receipts)
+
+	publisher := &mockPublisher{}
+	s.anchorPublisher = publisher
+
+	hdr1, err := s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	s.publishRoots(nil)
+
+	// roots already published are not published again
+	s.publishRoots(nil)
+	require.Len(t, publisher.roots, 1)
+	require.Equal(t, DefaultdbName, publisher.roots[0].Database)
+	require.Equal(t, hdr1.Id, publisher.roots[0].TxID)
+
+	publisher.err = errors.New("ledger unavailable")
+
+	hdr2, err := s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.NoError(t, err)
+
+	s.publishRoots(nil)
+
+	receipts, err = s.AnchorReceipts(ctx, &schema.AnchorReceiptsRequest{})
+	require.NoError(t, err)
+	require.Len(t, receipts.Receipts, 1)
+	require.Equal(t, hdr1.Id, receipts.Receipts[0].TxId)
+	require.Equal(t, "mock", receipts.Receipts[0].Ledger)
+	require.Equal(t, int64(1630490400), receipts.Receipts[0].PublishedAt)
+
+	publisher.err = nil
+
+	s.Options.AnchorBackend = anchor.BackendWebhook
+	s.startLedgerAnchoring()
+
+	require.Eventually(t, func() bool {
+		receipts, err := s.AnchorReceipts(ctx, &schema.AnchorReceiptsRequest{})
+		require.NoError(t, err)
+
+		return len(receipts.Receipts) == 2
+	}, 10*time.Second, 100*time.Millisecond)
+
+	s.stopLedgerAnchoring()
+	s.stopLedgerAnchoring()
+
+	receipts, err = s.AnchorReceipts(ctx, &schema.AnchorReceiptsRequest{Desc: true})
+	require.NoError(t, err)
+	require.Len(t, receipts.Receipts, 2)
+	require.Equal(t, hdr2.Id, receipts.Receipts[0].TxId)
+	require.Equal(t, hdr1.Id, receipts.Receipts[1].TxId)
+
+	state, err := s.dbList.GetByIndex(defaultDbIndex).CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, state.TxHash, receipts.Receipts[0].TxHash)
+	require.Equal(t, append([]byte("receipt"), state.TxHash...), receipts.Receipts[0].Receipt)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
@@ -53,10 +53,34 @@ type MetricsCollection struct {
 	computeDBEntries func() map[string]float64
 	DBEntriesGauges  *prometheus.GaugeVec
 
+	computeDBQuotaUsage func() map[string]float64
+	DBQuotaUsageGauges  *prometheus.GaugeVec
+
 	RPCsPerClientCounters        *prometheus.CounterVec
 	LastMessageAtPerClientGauges *prometheus.GaugeVec
+
+	BackupsCounters    *prometheus.CounterVec
+	LastBackupAtGauges *prometheus.GaugeVec
+
+	DBCorruptedGauges *prometheus.GaugeVec
+
+	VerifiedEntriesCounters        *prometheus.CounterVec
+	VerificationMismatchesCounters *prometheus.CounterVec
+	LastSuccessfulScanAtGauges     *prometheus.GaugeVec
+	ScanDurationHistograms         *prometheus.HistogramVec
+
+	ReplicaLagGauges      *prometheus.GaugeVec
+	ReplicaDivergedGauges *prometheus.GaugeVec
 }
 
+// Sources of the verification metrics
+const (
+	verificationSourceCorruptionChecker = "corruption_checker"
+	verificationSourceVerifyRange       = "verify_range"
+	verificationSourceComplianceReport  = "compliance_report"
+	verificationSourceIndexVerifier     = "index_verifier"
+)
+
 var metricsNamespace = "immudb"
 
 // WithUptimeCounter ...
@@ -93,6 +117,11 @@ func (mc *MetricsCollection) WithComputeDBEntries(f func() map[string]float64) {
 	mc.computeDBEntries = f
 }
 
+// WithComputeDBQuotaUsage ...
+func (mc *MetricsCollection) WithComputeDBQuotaUsage(f func() map[string]float64) {
+	mc.computeDBQuotaUsage = f
+}
+
 // UpdateDBMetrics ...
 func (mc *MetricsCollection) UpdateDBMetrics() {
 	if mc.computeDBSizes != nil {
@@ -105,6 +134,18 @@ func (mc *MetricsCollection) UpdateDBMetrics() {
 			mc.DBEntriesGauges.WithLabelValues(db).Set(nbEntries)
 		}
 	}
+	if mc.computeDBQuotaUsage != nil {
+		for db, usage := range mc.computeDBQuotaUsage() {
+			mc.DBQuotaUsageGauges.WithLabelValues(db).Set(usage)
+		}
+	}
+}
+
+// UpdateVerificationMetrics accounts the entries of a database verified by one of the verification paths
+// and the mismatches found along
+func (mc *MetricsCollection) UpdateVerificationMetrics(db, source string, entries, mismatches uint64) {
+	mc.VerifiedEntriesCounters.WithLabelValues(db, source).Add(float64(entries))
+	mc.VerificationMismatchesCounters.WithLabelValues(db, source).Add(float64(mismatches))
 }
 
 // Metrics immudb Prometheus metrics collection
@@ -133,6 +174,14 @@ var Metrics = MetricsCollection{
 		},
 		[]string{"db"},
 	),
+	DBQuotaUsageGauges: promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "db_quota_usage_ratio",
+			Help:      "Database size relative to its storage quota, only reported for databases with a quota.",
+		},
+		[]string{"db"},
+	),
 	LastMessageAtPerClientGauges: promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: metricsNamespace,
@@ -141,6 +190,79 @@ var Metrics = MetricsCollection{
 		},
 		[]string{"ip"},
 	),
+	BackupsCounters: promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "number_of_scheduled_backups",
+			Help:      "Number of scheduled backups by database, type and result.",
+		},
+		[]string{"db", "type", "result"},
+	),
+	LastBackupAtGauges: promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "last_scheduled_backup_at_unix_seconds",
+			Help:      "Timestamp at which the most recent successful scheduled backup of a database was started.",
+		},
+		[]string{"db"},
+	),
+	DBCorruptedGauges: promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "db_corrupted",
+			Help:      "Set to 1 when the corruption checker detected tampering in the database, meant to be alerted on.",
+		},
+		[]string{"db"},
+	),
+	VerifiedEntriesCounters: promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "number_of_verified_entries",
+			Help:      "Number of entries verified by database and verification source.",
+		},
+		[]string{"db", "source"},
+	),
+	VerificationMismatchesCounters: promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "number_of_verification_mismatches",
+			Help:      "Number of transactions or keys that failed their verification, by database and verification source.",
+		},
+		[]string{"db", "source"},
+	),
+	LastSuccessfulScanAtGauges: promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "last_successful_scan_at_unix_seconds",
+			Help:      "Timestamp at which the most recent scan of the corruption checker verifying a database up to its state ended.",
+		},
+		[]string{"db"},
+	),
+	ScanDurationHistograms: promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "scan_duration_seconds",
+			Help:      "Duration of the completed scans of the corruption checker.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 4, 10),
+		},
+		[]string{"db"},
+	),
+	ReplicaLagGauges: promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "replica_lag_txs",
+			Help:      "Number of transactions committed by the primary of a replicated database not yet replicated, as of its latest verification.",
+		},
+		[]string{"db"},
+	),
+	ReplicaDivergedGauges: promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "replica_diverged",
+			Help:      "Set to 1 when a replicated database and its primary could not be proven consistent with each other, meant to be alerted on.",
+		},
+		[]string{"db"},
+	),
 }
 
 // StartMetrics listens and servers the HTTP metrics server in a new goroutine.
@@ -53,6 +53,9 @@ func (s *ImmuServer) metricFuncComputeDBSizes() (dbSizes map[string]float64) {
 	if s.dbList != nil {
 		for i := 0; i < s.dbList.Length(); i++ {
 			db := s.dbList.GetByIndex(int64(i))
+			if db == nil {
+				continue
+			}
 			dbName := db.GetOptions().GetDbName()
 			dbSize, err := dirSize(filepath.Join(s.Options.Dir, dbName))
 			if err != nil {
@@ -89,6 +92,9 @@ func (s *ImmuServer) metricFuncComputeDBEntries() (nbEntriesPerDB map[string]flo
 	if s.dbList != nil {
 		for i := 0; i < s.dbList.Length(); i++ {
 			db := s.dbList.GetByIndex(int64(i))
+			if db == nil {
+				continue
+			}
 			dbName := db.GetOptions().GetDbName()
 			state, err := db.CurrentState()
 			if err != nil {
@@ -122,3 +128,31 @@ func (s *ImmuServer) metricFuncComputeDBEntries() (nbEntriesPerDB map[string]flo
 
 	return
 }
+
+func (s *ImmuServer) metricFuncComputeDBQuotaUsage() (quotaUsagePerDB map[string]float64) {
+	quotaUsagePerDB = make(map[string]float64)
+
+	if s.dbList == nil {
+		return
+	}
+
+	for i := 0; i < s.dbList.Length(); i++ {
+		db := s.dbList.GetByIndex(int64(i))
+		if db == nil {
+			continue
+		}
+		maxDiskSize := db.GetOptions().GetMaxDiskSize()
+		if maxDiskSize <= 0 {
+			continue
+		}
+		dbName := db.GetOptions().GetDbName()
+		dbSize, err := dirSize(filepath.Join(s.Options.Dir, dbName))
+		if err != nil {
+			s.Logger.Errorf("error updating quota usage metric for db %s: %v", dbName, err)
+			continue
+		}
+		quotaUsagePerDB[dbName] = float64(dbSize) / float64(maxDiskSize)
+	}
+
+	return
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/codenotary/immudb/pkg/api/openapi"
+)
+
+// openAPIPath is the path of the OpenAPI v3 specification of the REST API
+const openAPIPath = "/api/openapi.json"
+
+// swaggerUIPath is the path of the Swagger UI exploring the REST API
+const swaggerUIPath = "/api/docs/"
+
+// swaggerUIAssetsURL is where browsers load the Swagger UI scripts and styles from, unless a local copy is served
+const swaggerUIAssetsURL = "https://unpkg.com/swagger-ui-dist@3.52.5"
+
+// swaggerUIAssets are the files of swagger-ui-dist served from the assets directory, when one is set
+var swaggerUIAssets = []string{"swagger-ui.css", "swagger-ui-bundle.js"}
+
+// swaggerUIPage returns the page loading the Swagger UI assets from assetsURL, pointing it to the served specification.
+// Assets loaded from the CDN are fetched without credentials
+func swaggerUIPage(assetsURL string, fromCDN bool) string {
+	crossOrigin := ""
+	if fromCDN {
+		crossOrigin = ` crossorigin="anonymous"`
+	}
+
+	return `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>immudb REST API</title>
+  <link rel="stylesheet" href="` + assetsURL + `/swagger-ui.css"` + crossOrigin + `>
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="` + assetsURL + `/swagger-ui-bundle.js"` + crossOrigin + `></script>
+  <script>
+    window.onload = function () {
+      window.ui = SwaggerUIBundle({
+        url: "` + openAPIPath + `",
+        dom_id: "#swagger-ui",
+        persistAuthorization: true
+      });
+    };
+  </script>
+</body>
+</html>
+`
+}
+
+// openAPIHandler serves the OpenAPI v3 specification of the REST API, no authentication is required
+func openAPIHandler() http.Handler {
+	spec := openapi.Spec()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Write(spec)
+	})
+}
+
+// swaggerUIHandler serves the Swagger UI page exploring the REST API. Its assets are served from assetsDir
+// if set, so that browsers do not depend on the CDN, otherwise they are loaded from the CDN
+func swaggerUIHandler(assetsDir string) http.Handler {
+	page := swaggerUIPage(swaggerUIAssetsURL, true)
+	if assetsDir != "" {
+		page = swaggerUIPage(strings.TrimSuffix(swaggerUIPath, "/"), false)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == swaggerUIPath {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(page))
+			return
+		}
+
+		if assetsDir != "" {
+			for _, asset := range swaggerUIAssets {
+				if r.URL.Path == swaggerUIPath+asset {
+					http.ServeFile(w, r, filepath.Join(assetsDir, asset))
+					return
+				}
+			}
+		}
+
+		http.NotFound(w, r)
+	})
+}
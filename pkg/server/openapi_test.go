@@ -0,0 +1,106 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/openapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI(t *testing.T) {
+	h := openAPIHandler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, openAPIPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var spec map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &spec)
+	require.NoError(t, err)
+	require.Equal(t, openapi.Version, spec["openapi"])
+	require.Contains(t, spec["paths"], "/db/set")
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, openAPIPath, nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestSwaggerUI(t *testing.T) {
+	h := swaggerUIHandler("")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, swaggerUIPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `url: "`+openAPIPath+`"`)
+	require.Contains(t, string(body), `<script src="`+swaggerUIAssetsURL+`/swagger-ui-bundle.js" crossorigin="anonymous">`)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, swaggerUIPath+"missing", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, swaggerUIPath+"swagger-ui.css", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestSwaggerUIAssetsDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swagger_ui_assets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, asset := range append(swaggerUIAssets, "other.js") {
+		err = ioutil.WriteFile(filepath.Join(dir, asset), []byte("/* "+asset+" */"), 0644)
+		require.NoError(t, err)
+	}
+
+	h := swaggerUIHandler(dir)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, swaggerUIPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// assets are served by the server itself, the CDN is not involved
+	body := rec.Body.String()
+	require.Contains(t, body, `<script src="`+swaggerUIPath+`swagger-ui-bundle.js">`)
+	require.Contains(t, body, `<link rel="stylesheet" href="`+swaggerUIPath+`swagger-ui.css">`)
+	require.NotContains(t, body, swaggerUIAssetsURL)
+
+	for _, asset := range swaggerUIAssets {
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, swaggerUIPath+asset, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "/* "+asset+" */", rec.Body.String())
+	}
+
+	// only the Swagger UI assets are served out of the directory
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, swaggerUIPath+"other.js", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -20,6 +20,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -28,11 +29,19 @@ import (
 
 	"github.com/codenotary/immudb/embedded/store"
 	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
 )
 
 const SystemdbName = "systemdb"
 const DefaultdbName = "defaultdb"
 
+const (
+	// CorruptionPolicyAlert only alerts about the databases where tampering is detected
+	CorruptionPolicyAlert = "alert"
+	// CorruptionPolicyQuarantine also quarantines them, writes are rejected until the corruption is acknowledged
+	CorruptionPolicyQuarantine = "quarantine"
+)
+
 // Options server options list
 type Options struct {
 	Dir                  string
@@ -65,6 +74,99 @@ type Options struct {
 	TokenExpiryTimeMin   int
 	PgsqlServer          bool
 	PgsqlServerPort      int
+	Plugins              []string
+	LazyDatabaseLoading  bool
+	QuotaWarningThld     int
+	SQLStmtCacheSize     int
+	BackupDir            string
+	BackupStorageOptions *RemoteStorageOptions
+	BackupSchedules      []*BackupSchedule
+	BackupRetention      int
+	BackupEncryptionKey  string
+	ExpirySweepInterval  int
+	TSAURL               string
+	TSAInterval          int
+	AnchorBackend        string
+	AnchorURL            string
+	AnchorInterval       int
+	// CorruptionCheckInterval is the interval in seconds between the verifications of the databases with the corruption checker enabled
+	CorruptionCheckInterval int
+	// CorruptionCheckIORateLimit caps the bytes per second read by the corruption checker, zero means unlimited
+	CorruptionCheckIORateLimit uint64
+	CorruptionAlertOptions     *CorruptionAlertOptions
+	// CorruptionPolicy is the action taken on the databases where the corruption checker detects tampering
+	CorruptionPolicy string
+	// IndexVerificationInterval is the interval in seconds between the verifications of the indexes against the commit logs, zero disables them
+	IndexVerificationInterval int
+	// IndexAutoRebuild rebuilds the indexes found inconsistent with their commit logs
+	IndexAutoRebuild bool
+	// ReplicaVerificationInterval is the interval in seconds between the verifications of the replicas against their primary, zero disables them
+	ReplicaVerificationInterval int
+
+	// ReplicationPort enables a listener dedicated to replication, serving only replication operations
+	// to clients authenticated through ReplicationTLSConfig
+	ReplicationPort      int
+	ReplicationTLSConfig *tls.Config
+
+	// ClusterOptions makes the server a node of a cluster, nil disables the cluster mode
+	ClusterOptions *ClusterOptions
+
+	// FailoverProbeInterval is the interval in seconds between the probes of the primary of the replicas with
+	// automatic failover, zero disables them
+	FailoverProbeInterval int
+	// FailoverThreshold is the number of consecutive failed probes after which a replica gets promoted
+	FailoverThreshold int
+	// AdvertisedAddress is the address (host:port) clients are told to reach the server at, Address and Port by default
+	AdvertisedAddress string
+
+	// ReplicationHookURLs are notified with a JSON payload of every replication event
+	ReplicationHookURLs []string
+	// ReplicationLagThreshold is the number of transactions a replica may lag behind before a lag event fires,
+	// zero disables lag events
+	ReplicationLagThreshold uint64
+
+	// GrpcWeb serves gRPC-Web requests on the web server port, so that browsers can call the gRPC API directly
+	GrpcWeb bool
+	// GrpcWebAllowedOrigins are the origins allowed to send cross-origin gRPC-Web requests, "*" allows any of them without credentials
+	GrpcWebAllowedOrigins []string
+
+	// SwaggerUI serves the Swagger UI exploring the REST API on the web server port,
+	// its assets are loaded by the browser from the unpkg.com CDN unless SwaggerUIAssetsDir is set
+	SwaggerUI bool
+	// SwaggerUIAssetsDir holds the swagger-ui.css and swagger-ui-bundle.js files of swagger-ui-dist,
+	// served along with the Swagger UI page instead of loading them from the CDN
+	SwaggerUIAssetsDir string
+}
+
+// CorruptionAlertOptions holds the recipients of the alerts sent when the corruption checker detects tampering
+type CorruptionAlertOptions struct {
+	WebhookURLs  []string
+	SMTPAddress  string
+	SMTPUsername string
+	SMTPPassword string `json:"-"`
+	EmailFrom    string
+	EmailTo      []string
+}
+
+// ClusterOptions identifies the node and its peers in the cluster, where each database forms a Raft group
+// along with the databases of the same name of the other nodes
+type ClusterOptions struct {
+	NodeID string
+	// RaftAddress is the address, reachable by the peers, where the consensus traffic of the node is served
+	RaftAddress string
+	// Address is where clients are redirected to when the node leads a database, the server address and port by default
+	Address string
+	Peers   []*ClusterPeer
+	// Secret is shared by the nodes to authenticate the connections between them
+	Secret string `json:"-"`
+}
+
+// ClusterPeer is another node of the cluster
+type ClusterPeer struct {
+	NodeID      string
+	RaftAddress string
+	// Address is the address of the gRPC server of the node, where clients are redirected when it leads a database
+	Address string
 }
 
 type RemoteStorageOptions struct {
@@ -107,6 +209,18 @@ func DefaultOptions() *Options {
 		TokenExpiryTimeMin:   1440,
 		PgsqlServer:          false,
 		PgsqlServerPort:      5432,
+		QuotaWarningThld:     database.DefaultQuotaWarningThreshold,
+		SQLStmtCacheSize:     database.DefaultSQLStmtCacheSize,
+		BackupStorageOptions: DefaultRemoteStorageOptions(),
+		ExpirySweepInterval:  60,
+		TSAInterval:          3600,
+		AnchorInterval:       3600,
+
+		CorruptionCheckInterval: 3600,
+		CorruptionAlertOptions:  DefaultCorruptionAlertOptions(),
+		CorruptionPolicy:        CorruptionPolicyAlert,
+
+		FailoverThreshold: 3,
 	}
 }
 
@@ -128,6 +242,16 @@ func DefaultRemoteStorageOptions() *RemoteStorageOptions {
 	}
 }
 
+// DefaultCorruptionAlertOptions returns alert options without recipients
+func DefaultCorruptionAlertOptions() *CorruptionAlertOptions {
+	return &CorruptionAlertOptions{}
+}
+
+// DefaultClusterOptions returns cluster options without identity nor peers
+func DefaultClusterOptions() *ClusterOptions {
+	return &ClusterOptions{}
+}
+
 // WithDir sets dir
 func (o *Options) WithDir(dir string) *Options {
 	o.Dir = dir
@@ -259,6 +383,79 @@ func (o *Options) String() string {
 		opts = append(opts, rightPad("   bucket name", o.RemoteStorageOptions.S3BucketName))
 		opts = append(opts, rightPad("   prefix", o.RemoteStorageOptions.S3PathPrefix))
 	}
+	if len(o.Plugins) > 0 {
+		opts = append(opts, rightPad("Plugins", strings.Join(o.Plugins, ", ")))
+	}
+	if o.LazyDatabaseLoading {
+		opts = append(opts, rightPad("Lazy db loading", o.LazyDatabaseLoading))
+	}
+	if o.BackupStorageOptions.S3Storage {
+		opts = append(opts, "S3 backups")
+		opts = append(opts, rightPad("   endpoint", o.BackupStorageOptions.S3Endpoint))
+		opts = append(opts, rightPad("   bucket name", o.BackupStorageOptions.S3BucketName))
+		opts = append(opts, rightPad("   prefix", o.BackupStorageOptions.S3PathPrefix))
+	}
+	if o.BackupEncryptionKey != "" {
+		opts = append(opts, rightPad("Backup key file", o.BackupEncryptionKey))
+	}
+	if len(o.BackupSchedules) > 0 {
+		opts = append(opts, "Backup schedules")
+		for _, bs := range o.BackupSchedules {
+			opts = append(opts, rightPad("   "+bs.Database, bs.kind()+" "+bs.Schedule))
+		}
+		if o.BackupRetention > 0 {
+			opts = append(opts, rightPad("   retention", o.BackupRetention))
+		}
+	}
+	if o.TSAURL != "" {
+		opts = append(opts, rightPad("Time-stamp authority", o.TSAURL))
+	}
+	if len(o.CorruptionAlertOptions.WebhookURLs) > 0 || len(o.CorruptionAlertOptions.EmailTo) > 0 {
+		opts = append(opts, "Corruption alerts")
+		if len(o.CorruptionAlertOptions.WebhookURLs) > 0 {
+			opts = append(opts, rightPad("   webhooks", strings.Join(o.CorruptionAlertOptions.WebhookURLs, ", ")))
+		}
+		if len(o.CorruptionAlertOptions.EmailTo) > 0 {
+			opts = append(opts, rightPad("   emails", strings.Join(o.CorruptionAlertOptions.EmailTo, ", ")))
+		}
+	}
+	if o.CorruptionPolicy == CorruptionPolicyQuarantine {
+		opts = append(opts, rightPad("Corruption policy", o.CorruptionPolicy))
+	}
+	if o.IndexVerificationInterval > 0 {
+		indexVerification := fmt.Sprintf("every %ds", o.IndexVerificationInterval)
+		if o.IndexAutoRebuild {
+			indexVerification += ", auto rebuild"
+		}
+		opts = append(opts, rightPad("Index verification", indexVerification))
+	}
+	if o.ReplicaVerificationInterval > 0 {
+		opts = append(opts, rightPad("Replica verification", fmt.Sprintf("every %ds", o.ReplicaVerificationInterval)))
+	}
+	if o.ReplicationPort > 0 {
+		opts = append(opts, rightPad("Replication port", fmt.Sprintf("%d (mTLS)", o.ReplicationPort)))
+	}
+	if o.ClusterOptions != nil {
+		opts = append(opts, rightPad("Cluster node", fmt.Sprintf("%s at %s, %d peer(s)", o.ClusterOptions.NodeID, o.ClusterOptions.RaftAddress, len(o.ClusterOptions.Peers))))
+	}
+	if o.FailoverProbeInterval > 0 {
+		opts = append(opts, rightPad("Failover probes", fmt.Sprintf("every %ds, promotion after %d failures", o.FailoverProbeInterval, o.FailoverThreshold)))
+	}
+	if len(o.ReplicationHookURLs) > 0 {
+		opts = append(opts, rightPad("Replication hooks", strings.Join(o.ReplicationHookURLs, ", ")))
+	}
+	if o.AnchorBackend != "" {
+		opts = append(opts, rightPad("Anchoring", o.AnchorBackend+" "+o.AnchorURL))
+	}
+	if o.WebServer && o.GrpcWeb {
+		opts = append(opts, rightPad("gRPC-Web", fmt.Sprintf("%s:%d", o.Address, o.WebServerPort)))
+		if len(o.GrpcWebAllowedOrigins) > 0 {
+			opts = append(opts, rightPad("   origins", strings.Join(o.GrpcWebAllowedOrigins, ", ")))
+		}
+	}
+	if o.WebServer && o.SwaggerUI {
+		opts = append(opts, rightPad("Swagger UI", fmt.Sprintf("%s:%d%s", o.Address, o.WebServerPort, swaggerUIPath)))
+	}
 	opts = append(opts, "----------------------------------------")
 	opts = append(opts, "Superadmin default credentials")
 	opts = append(opts, rightPad("   Username", auth.SysAdminUsername))
@@ -357,11 +554,243 @@ func (o *Options) WithPgsqlServerPort(port int) *Options {
 	return o
 }
 
+// WithPlugins sets the paths of the Go plugins to be loaded at startup
+func (o *Options) WithPlugins(plugins []string) *Options {
+	o.Plugins = plugins
+	return o
+}
+
+// WithLazyDatabaseLoading sets if user databases are opened on first use or by the background warm-up
+// instead of during startup
+func (o *Options) WithLazyDatabaseLoading(lazyDatabaseLoading bool) *Options {
+	o.LazyDatabaseLoading = lazyDatabaseLoading
+	return o
+}
+
+// WithQuotaWarningThld sets the percentage of a database quota at which a warning is logged
+func (o *Options) WithQuotaWarningThld(quotaWarningThld int) *Options {
+	o.QuotaWarningThld = quotaWarningThld
+	return o
+}
+
+// WithSQLStmtCacheSize sets the number of parsed SQL statements each database keeps for reuse, zero disables the cache
+func (o *Options) WithSQLStmtCacheSize(sqlStmtCacheSize int) *Options {
+	o.SQLStmtCacheSize = sqlStmtCacheSize
+	return o
+}
+
+// WithBackupDir sets the directory where server-side backups are written.
+// When not set, backups are written into a directory inside the data dir
+func (o *Options) WithBackupDir(backupDir string) *Options {
+	o.BackupDir = backupDir
+	return o
+}
+
+// GetBackupDir returns the directory where server-side backups are written
+func (o *Options) GetBackupDir() string {
+	if o.BackupDir == "" {
+		return filepath.Join(o.Dir, backupDirName)
+	}
+	return o.BackupDir
+}
+
 func (o *Options) WithRemoteStorageOptions(remoteStorageOptions *RemoteStorageOptions) *Options {
 	o.RemoteStorageOptions = remoteStorageOptions
 	return o
 }
 
+// WithBackupSchedules sets the schedules of the backups automatically taken by the server
+func (o *Options) WithBackupSchedules(backupSchedules []*BackupSchedule) *Options {
+	o.BackupSchedules = backupSchedules
+	return o
+}
+
+// WithBackupRetention sets the number of scheduled full backups kept per database, along with their incremental ones.
+// Older backups are pruned, all of them are kept when it's zero
+func (o *Options) WithBackupRetention(backupRetention int) *Options {
+	o.BackupRetention = backupRetention
+	return o
+}
+
+// WithBackupEncryptionKey sets the file holding the hex-encoded AES key used to encrypt server-side backups.
+// Backups are not encrypted when it's not set
+func (o *Options) WithBackupEncryptionKey(keyFile string) *Options {
+	o.BackupEncryptionKey = keyFile
+	return o
+}
+
+// WithExpirySweepInterval sets the interval in seconds between the deletions of expired keys, zero disables them
+func (o *Options) WithExpirySweepInterval(expirySweepInterval int) *Options {
+	o.ExpirySweepInterval = expirySweepInterval
+	return o
+}
+
+// WithTSAURL sets the RFC 3161 time-stamp authority the database roots are periodically submitted to.
+// Roots are not time-stamped when it's not set
+func (o *Options) WithTSAURL(tsaURL string) *Options {
+	o.TSAURL = tsaURL
+	return o
+}
+
+// WithTSAInterval sets the interval in seconds between the submissions of the database roots to the time-stamp authority
+func (o *Options) WithTSAInterval(tsaInterval int) *Options {
+	o.TSAInterval = tsaInterval
+	return o
+}
+
+// WithAnchorBackend sets the kind of external ledger the database roots are periodically published to,
+// either webhook or opentimestamps. Roots are not published when it's not set
+func (o *Options) WithAnchorBackend(anchorBackend string) *Options {
+	o.AnchorBackend = anchorBackend
+	return o
+}
+
+// WithAnchorURL sets the url of the external ledger the database roots are published to
+func (o *Options) WithAnchorURL(anchorURL string) *Options {
+	o.AnchorURL = anchorURL
+	return o
+}
+
+// WithAnchorInterval sets the interval in seconds between the publications of the database roots to the external ledger
+func (o *Options) WithAnchorInterval(anchorInterval int) *Options {
+	o.AnchorInterval = anchorInterval
+	return o
+}
+
+// WithCorruptionCheckInterval sets the interval in seconds between the verifications of the databases
+// with the corruption checker enabled, zero disables them
+func (o *Options) WithCorruptionCheckInterval(corruptionCheckInterval int) *Options {
+	o.CorruptionCheckInterval = corruptionCheckInterval
+	return o
+}
+
+// WithCorruptionCheckIORateLimit sets the maximum bytes per second read by the corruption checker, zero means unlimited
+func (o *Options) WithCorruptionCheckIORateLimit(corruptionCheckIORateLimit uint64) *Options {
+	o.CorruptionCheckIORateLimit = corruptionCheckIORateLimit
+	return o
+}
+
+// WithCorruptionPolicy sets the action taken on the databases where tampering is detected,
+// either CorruptionPolicyAlert or CorruptionPolicyQuarantine
+func (o *Options) WithCorruptionPolicy(corruptionPolicy string) *Options {
+	o.CorruptionPolicy = corruptionPolicy
+	return o
+}
+
+// WithIndexVerificationInterval sets the interval in seconds between the verifications of the indexes
+// against the commit logs of the databases, zero disables them
+func (o *Options) WithIndexVerificationInterval(indexVerificationInterval int) *Options {
+	o.IndexVerificationInterval = indexVerificationInterval
+	return o
+}
+
+// WithIndexAutoRebuild sets whether the indexes found inconsistent with their commit logs get rebuilt
+func (o *Options) WithIndexAutoRebuild(indexAutoRebuild bool) *Options {
+	o.IndexAutoRebuild = indexAutoRebuild
+	return o
+}
+
+// WithReplicaVerificationInterval sets the interval in seconds between the verifications of the replicated databases
+// against their primary, zero disables them
+func (o *Options) WithReplicaVerificationInterval(replicaVerificationInterval int) *Options {
+	o.ReplicaVerificationInterval = replicaVerificationInterval
+	return o
+}
+
+// WithReplicationPort sets the port of the listener dedicated to replication, zero disables it
+func (o *Options) WithReplicationPort(replicationPort int) *Options {
+	o.ReplicationPort = replicationPort
+	return o
+}
+
+// WithClusterOptions sets the identity of the node and its peers in the cluster, nil disables the cluster mode
+func (o *Options) WithClusterOptions(clusterOptions *ClusterOptions) *Options {
+	o.ClusterOptions = clusterOptions
+	return o
+}
+
+// WithFailoverProbeInterval sets the interval in seconds between the probes of the primary of the replicas
+// with automatic failover, zero disables them
+func (o *Options) WithFailoverProbeInterval(failoverProbeInterval int) *Options {
+	o.FailoverProbeInterval = failoverProbeInterval
+	return o
+}
+
+// WithFailoverThreshold sets the number of consecutive failed probes after which a replica gets promoted
+func (o *Options) WithFailoverThreshold(failoverThreshold int) *Options {
+	o.FailoverThreshold = failoverThreshold
+	return o
+}
+
+// WithAdvertisedAddress sets the address (host:port) clients are told to reach the server at
+func (o *Options) WithAdvertisedAddress(advertisedAddress string) *Options {
+	o.AdvertisedAddress = advertisedAddress
+	return o
+}
+
+// WithReplicationHookURLs sets the urls notified with a JSON payload of every replication event
+func (o *Options) WithReplicationHookURLs(replicationHookURLs []string) *Options {
+	o.ReplicationHookURLs = replicationHookURLs
+	return o
+}
+
+// WithGrpcWeb enables or disables serving gRPC-Web requests on the web server port
+func (o *Options) WithGrpcWeb(grpcWeb bool) *Options {
+	o.GrpcWeb = grpcWeb
+	return o
+}
+
+// WithGrpcWebAllowedOrigins sets the origins allowed to send cross-origin gRPC-Web requests, "*" allows any of them without credentials
+func (o *Options) WithGrpcWebAllowedOrigins(allowedOrigins []string) *Options {
+	o.GrpcWebAllowedOrigins = allowedOrigins
+	return o
+}
+
+// WithSwaggerUI enables or disables serving the Swagger UI exploring the REST API
+func (o *Options) WithSwaggerUI(swaggerUI bool) *Options {
+	o.SwaggerUI = swaggerUI
+	return o
+}
+
+// WithSwaggerUIAssetsDir sets the directory the Swagger UI assets are served from, instead of the CDN
+func (o *Options) WithSwaggerUIAssetsDir(dir string) *Options {
+	o.SwaggerUIAssetsDir = dir
+	return o
+}
+
+// WithReplicationLagThreshold sets the number of transactions a replica may lag behind before a lag event fires,
+// zero disables lag events
+func (o *Options) WithReplicationLagThreshold(replicationLagThreshold uint64) *Options {
+	o.ReplicationLagThreshold = replicationLagThreshold
+	return o
+}
+
+// advertisedAddress returns the address clients are told to reach the server at
+func (o *Options) advertisedAddress() string {
+	if o.AdvertisedAddress != "" {
+		return o.AdvertisedAddress
+	}
+	return fmt.Sprintf("%s:%d", o.Address, o.Port)
+}
+
+// WithReplicationTLSConfig sets the TLS configuration of the replication listener, client certificates are required
+func (o *Options) WithReplicationTLSConfig(tlsConfig *tls.Config) *Options {
+	o.ReplicationTLSConfig = tlsConfig
+	return o
+}
+
+// WithCorruptionAlertOptions sets the recipients of the alerts sent when tampering is detected
+func (o *Options) WithCorruptionAlertOptions(corruptionAlertOptions *CorruptionAlertOptions) *Options {
+	o.CorruptionAlertOptions = corruptionAlertOptions
+	return o
+}
+
+// WithBackupStorageOptions sets the s3-compatible storage where server-side backups are written instead of the backup dir
+func (o *Options) WithBackupStorageOptions(backupStorageOptions *RemoteStorageOptions) *Options {
+	o.BackupStorageOptions = backupStorageOptions
+	return o
+}
+
 // RemoteStorageOptions
 
 func (opts *RemoteStorageOptions) WithS3Storage(S3Storage bool) *RemoteStorageOptions {
@@ -393,3 +822,62 @@ func (opts *RemoteStorageOptions) WithS3PathPrefix(s3PathPrefix string) *RemoteS
 	opts.S3PathPrefix = s3PathPrefix
 	return opts
 }
+
+// CorruptionAlertOptions
+
+func (opts *CorruptionAlertOptions) WithWebhookURLs(webhookURLs []string) *CorruptionAlertOptions {
+	opts.WebhookURLs = webhookURLs
+	return opts
+}
+
+func (opts *CorruptionAlertOptions) WithSMTPAddress(smtpAddress string) *CorruptionAlertOptions {
+	opts.SMTPAddress = smtpAddress
+	return opts
+}
+
+func (opts *CorruptionAlertOptions) WithSMTPUsername(smtpUsername string) *CorruptionAlertOptions {
+	opts.SMTPUsername = smtpUsername
+	return opts
+}
+
+func (opts *CorruptionAlertOptions) WithSMTPPassword(smtpPassword string) *CorruptionAlertOptions {
+	opts.SMTPPassword = smtpPassword
+	return opts
+}
+
+func (opts *CorruptionAlertOptions) WithEmailFrom(emailFrom string) *CorruptionAlertOptions {
+	opts.EmailFrom = emailFrom
+	return opts
+}
+
+func (opts *CorruptionAlertOptions) WithEmailTo(emailTo []string) *CorruptionAlertOptions {
+	opts.EmailTo = emailTo
+	return opts
+}
+
+// ClusterOptions
+
+func (opts *ClusterOptions) WithNodeID(nodeID string) *ClusterOptions {
+	opts.NodeID = nodeID
+	return opts
+}
+
+func (opts *ClusterOptions) WithRaftAddress(raftAddress string) *ClusterOptions {
+	opts.RaftAddress = raftAddress
+	return opts
+}
+
+func (opts *ClusterOptions) WithAddress(address string) *ClusterOptions {
+	opts.Address = address
+	return opts
+}
+
+func (opts *ClusterOptions) WithPeers(peers []*ClusterPeer) *ClusterOptions {
+	opts.Peers = peers
+	return opts
+}
+
+func (opts *ClusterOptions) WithSecret(secret string) *ClusterOptions {
+	opts.Secret = secret
+	return opts
+}
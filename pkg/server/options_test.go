@@ -72,7 +72,16 @@ func TestSetOptions(t *testing.T) {
 		WithStoreOptions(storeOptions).
 		WithTLS(tlsConfig).
 		WithPgsqlServer(true).
-		WithPgsqlServerPort(123456)
+		WithPgsqlServerPort(123456).
+		WithPlugins([]string{"plugin.so"}).
+		WithLazyDatabaseLoading(true).
+		WithQuotaWarningThld(80).
+		WithBackupDir("backups").
+		WithBackupStorageOptions(DefaultRemoteStorageOptions().WithS3Storage(true)).
+		WithBackupSchedules([]*BackupSchedule{{Database: "defaultdb", Schedule: "0 3 * * *"}}).
+		WithBackupRetention(2).
+		WithBackupEncryptionKey("backup.key").
+		WithExpirySweepInterval(30)
 
 	if op.GetAuth() != false ||
 		op.Dir != "immudb_dir" ||
@@ -98,7 +107,16 @@ func TestSetOptions(t *testing.T) {
 		op.TLSConfig != tlsConfig ||
 		op.TokenExpiryTimeMin != 52 ||
 		!op.PgsqlServer ||
-		op.PgsqlServerPort != 123456 {
+		op.PgsqlServerPort != 123456 ||
+		len(op.Plugins) != 1 ||
+		!op.LazyDatabaseLoading ||
+		op.QuotaWarningThld != 80 ||
+		op.GetBackupDir() != "backups" ||
+		!op.BackupStorageOptions.S3Storage ||
+		len(op.BackupSchedules) != 1 ||
+		op.BackupRetention != 2 ||
+		op.BackupEncryptionKey != "backup.key" ||
+		op.ExpirySweepInterval != 30 {
 		t.Errorf("database default options mismatch")
 	}
 }
@@ -0,0 +1,196 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"plugin"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+var ErrPluginAlreadyRegistered = errors.New("plugin already registered")
+var ErrInvalidPlugin = errors.New("invalid plugin")
+
+// PluginSymbol is the name of the symbol looked up in Go plugins. It must be either
+// a variable of type Plugin or a function with signature func() Plugin
+const PluginSymbol = "ImmudbPlugin"
+
+// Plugin is a custom subsystem (auth backend, CDC sink, storage tier, ...) hooked into the server lifecycle
+type Plugin interface {
+	// Name uniquely identifies the plugin
+	Name() string
+	// Init is invoked once all databases are loaded, before the grpc server gets created
+	Init(s *ImmuServer) error
+	// Start is invoked right before the server starts serving requests, which are not served if it fails
+	Start() error
+	// Stop is invoked when the server is shutting down, before databases get closed
+	Stop() error
+}
+
+// InterceptorsPlugin may be implemented by plugins willing to intercept grpc calls.
+// Interceptors are chained after the built-in ones, so calls are already authenticated
+type InterceptorsPlugin interface {
+	UnaryInterceptors() []grpc.UnaryServerInterceptor
+	StreamInterceptors() []grpc.StreamServerInterceptor
+}
+
+// ServicesPlugin may be implemented by plugins exposing additional grpc services
+type ServicesPlugin interface {
+	RegisterServices(grpcServer *grpc.Server)
+}
+
+var registeredPlugins = struct {
+	plugins []Plugin
+	sync.Mutex
+}{}
+
+// RegisterPlugin makes the plugin available to every server initialized afterwards.
+// It's meant to be called by embedders, usually from an init function
+func RegisterPlugin(p Plugin) error {
+	if p == nil || p.Name() == "" {
+		return ErrIllegalArguments
+	}
+
+	registeredPlugins.Lock()
+	defer registeredPlugins.Unlock()
+
+	for _, rp := range registeredPlugins.plugins {
+		if rp.Name() == p.Name() {
+			return fmt.Errorf("%w: '%s'", ErrPluginAlreadyRegistered, p.Name())
+		}
+	}
+
+	registeredPlugins.plugins = append(registeredPlugins.plugins, p)
+
+	return nil
+}
+
+// openPlugin loads a Go plugin from the provided path
+func openPlugin(path string) (Plugin, error) {
+	pl, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := pl.Lookup(PluginSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p := sym.(type) {
+	case *Plugin:
+		if *p != nil {
+			return *p, nil
+		}
+	case func() Plugin:
+		if np := p(); np != nil {
+			return np, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: symbol '%s' in '%s' is not a plugin", ErrInvalidPlugin, PluginSymbol, path)
+}
+
+// initPlugins collects registered plugins and the ones loaded from the configured paths, then initializes them
+func (s *ImmuServer) initPlugins() error {
+	registeredPlugins.Lock()
+	plugins := make([]Plugin, len(registeredPlugins.plugins))
+	copy(plugins, registeredPlugins.plugins)
+	registeredPlugins.Unlock()
+
+	names := make(map[string]struct{}, len(plugins))
+	for _, p := range plugins {
+		names[p.Name()] = struct{}{}
+	}
+
+	for _, path := range s.Options.Plugins {
+		p, err := openPlugin(path)
+		if err != nil {
+			return fmt.Errorf("unable to load plugin '%s': %w", path, err)
+		}
+
+		if _, ok := names[p.Name()]; ok {
+			return fmt.Errorf("%w: '%s'", ErrPluginAlreadyRegistered, p.Name())
+		}
+		names[p.Name()] = struct{}{}
+
+		plugins = append(plugins, p)
+	}
+
+	for _, p := range plugins {
+		if err := p.Init(s); err != nil {
+			return fmt.Errorf("unable to initialize plugin '%s': %w", p.Name(), err)
+		}
+
+		s.Logger.Infof("Plugin '%s' initialized", p.Name())
+	}
+
+	s.plugins = plugins
+
+	return nil
+}
+
+func (s *ImmuServer) pluginsInterceptors() ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor) {
+	var uis []grpc.UnaryServerInterceptor
+	var sis []grpc.StreamServerInterceptor
+
+	for _, p := range s.plugins {
+		if ip, ok := p.(InterceptorsPlugin); ok {
+			uis = append(uis, ip.UnaryInterceptors()...)
+			sis = append(sis, ip.StreamInterceptors()...)
+		}
+	}
+
+	return uis, sis
+}
+
+func (s *ImmuServer) registerPluginsServices() {
+	for _, p := range s.plugins {
+		if sp, ok := p.(ServicesPlugin); ok {
+			sp.RegisterServices(s.GrpcServer)
+		}
+	}
+}
+
+func (s *ImmuServer) startPlugins() error {
+	for i, p := range s.plugins {
+		if err := p.Start(); err != nil {
+			// the plugins already started get stopped
+			for j := i - 1; j >= 0; j-- {
+				if err := s.plugins[j].Stop(); err != nil {
+					s.Logger.Errorf("Unable to stop plugin '%s': %v", s.plugins[j].Name(), err)
+				}
+			}
+
+			return fmt.Errorf("unable to start plugin '%s': %w", p.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// stopPlugins stops plugins in reverse order, errors are logged so every plugin gets the chance to stop
+func (s *ImmuServer) stopPlugins() {
+	for i := len(s.plugins) - 1; i >= 0; i-- {
+		if err := s.plugins[i].Stop(); err != nil {
+			s.Logger.Errorf("Unable to stop plugin '%s': %v", s.plugins[i].Name(), err)
+		}
+	}
+}
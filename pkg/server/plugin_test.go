@@ -0,0 +1,233 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testPlugin struct {
+	name     string
+	initErr  error
+	startErr error
+
+	mux         sync.Mutex
+	initialized bool
+	started     bool
+	stopped     bool
+	calls       []string
+}
+
+func (p *testPlugin) Name() string {
+	return p.name
+}
+
+func (p *testPlugin) Init(s *ImmuServer) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.initialized = true
+	return p.initErr
+}
+
+func (p *testPlugin) Start() error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.started = true
+	return p.startErr
+}
+
+func (p *testPlugin) Stop() error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.stopped = true
+	return nil
+}
+
+func (p *testPlugin) UnaryInterceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			p.mux.Lock()
+			p.calls = append(p.calls, info.FullMethod)
+			p.mux.Unlock()
+
+			return handler(ctx, req)
+		},
+	}
+}
+
+func (p *testPlugin) StreamInterceptors() []grpc.StreamServerInterceptor {
+	return nil
+}
+
+func resetRegisteredPlugins() {
+	registeredPlugins.Lock()
+	registeredPlugins.plugins = nil
+	registeredPlugins.Unlock()
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	defer resetRegisteredPlugins()
+
+	err := RegisterPlugin(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = RegisterPlugin(&testPlugin{})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = RegisterPlugin(&testPlugin{name: "plugin1"})
+	require.NoError(t, err)
+
+	err = RegisterPlugin(&testPlugin{name: "plugin1"})
+	require.True(t, errors.Is(err, ErrPluginAlreadyRegistered))
+}
+
+func TestServerPlugins(t *testing.T) {
+	defer resetRegisteredPlugins()
+
+	p := &testPlugin{name: "plugin1"}
+
+	err := RegisterPlugin(p)
+	require.NoError(t, err)
+
+	l := bufconn.Listen(1024 * 1024)
+
+	options := DefaultOptions().
+		WithAuth(false).
+		WithTLS(nil).
+		WithDir("data_plugins").
+		WithListener(l).
+		WithMetricsServer(false).
+		WithWebServer(false)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err = s.Initialize()
+	require.NoError(t, err)
+	require.True(t, p.initialized)
+
+	go s.Start()
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return l.Dial() }),
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = schema.NewImmuServiceClient(conn).Health(context.Background(), &empty.Empty{})
+	require.NoError(t, err)
+
+	err = s.Stop()
+	require.NoError(t, err)
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	require.True(t, p.started)
+	require.True(t, p.stopped)
+	require.Equal(t, []string{"/immudb.schema.ImmuService/Health"}, p.calls)
+}
+
+func TestServerPluginsInitErrors(t *testing.T) {
+	defer resetRegisteredPlugins()
+
+	options := DefaultOptions().
+		WithDir("data_plugins_errors").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithPlugins([]string{"./nonexistent.so"})
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.Error(t, err)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+
+	options.WithPlugins(nil)
+
+	err = RegisterPlugin(&testPlugin{name: "plugin1", initErr: errors.New("init error")})
+	require.NoError(t, err)
+
+	s = DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err = s.Initialize()
+	require.Error(t, err)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
+
+func TestServerPluginsStartError(t *testing.T) {
+	defer resetRegisteredPlugins()
+
+	p1 := &testPlugin{name: "plugin1"}
+	p2 := &testPlugin{name: "plugin2", startErr: errors.New("start error")}
+
+	err := RegisterPlugin(p1)
+	require.NoError(t, err)
+
+	err = RegisterPlugin(p2)
+	require.NoError(t, err)
+
+	options := DefaultOptions().
+		WithAuth(false).
+		WithDir("data_plugins_start_error").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err = s.Initialize()
+	require.NoError(t, err)
+	defer s.CloseDatabases()
+
+	err = s.Start()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "start error")
+
+	require.True(t, p1.started)
+	require.True(t, p1.stopped)
+	require.True(t, p2.started)
+	require.False(t, p2.stopped)
+
+	// the server is left unlocked
+	s.mux.Lock()
+	s.mux.Unlock()
+	s.pgsqlMux.Lock()
+	s.pgsqlMux.Unlock()
+}
@@ -0,0 +1,334 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+)
+
+// primaryDatabase is the view of the primary of a replica needed to verify them against each other
+type primaryDatabase interface {
+	CurrentState() (*schema.ImmutableState, error)
+	VerifiableTxByID(req *schema.VerifiableTxRequest) (*schema.VerifiableTx, error)
+}
+
+// replicaDivergence describes a replica which could not be proven consistent with its primary
+type replicaDivergence struct {
+	Primary   string `json:"primary"`
+	ReplicaTx uint64 `json:"replicaTx"`
+	PrimaryTx uint64 `json:"primaryTx"`
+	// DivergentTx is the first transaction holding different hashes on both sides, zero if it could not be located
+	DivergentTx   uint64    `json:"divergentTx,omitempty"`
+	ReplicaTxHash []byte    `json:"replicaTxHash,omitempty"`
+	PrimaryTxHash []byte    `json:"primaryTxHash,omitempty"`
+	DetectedAt    time.Time `json:"-"`
+}
+
+// replicaVerification is the outcome of the verification of a replica against its primary
+type replicaVerification struct {
+	replicaTx  uint64
+	primaryTx  uint64
+	consistent bool
+	// divergentTx, along with its hashes, is only set when the replica is inconsistent with its primary
+	divergentTx   uint64
+	replicaTxHash []byte
+	primaryTxHash []byte
+}
+
+// startReplicaVerifier runs in background the periodic verification of the replicated databases against their primary
+func (s *ImmuServer) startReplicaVerifier() {
+	if s.Options.ReplicaVerificationInterval <= 0 {
+		return
+	}
+
+	s.replicaVerifierDone = make(chan struct{})
+	s.replicaVerifierFinished = make(chan struct{})
+
+	go s.replicaVerifier(time.Duration(s.Options.ReplicaVerificationInterval)*time.Second, s.replicaVerifierDone, s.replicaVerifierFinished)
+}
+
+// stopReplicaVerifier interrupts the verifier and waits for the verification in progress, if any
+func (s *ImmuServer) stopReplicaVerifier() {
+	if s.replicaVerifierDone == nil {
+		return
+	}
+
+	close(s.replicaVerifierDone)
+	<-s.replicaVerifierFinished
+
+	s.replicaVerifierDone = nil
+	s.replicaVerifierFinished = nil
+}
+
+func (s *ImmuServer) replicaVerifier(interval time.Duration, done <-chan struct{}, finished chan<- struct{}) {
+	defer close(finished)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		s.verifyReplicas(done)
+	}
+}
+
+// verifyReplicas verifies every opened replica with a known primary, databases not loaded yet are skipped
+func (s *ImmuServer) verifyReplicas(done <-chan struct{}) {
+	for i := 0; i < s.dbList.Length(); i++ {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		db := s.dbList.GetByIndex(int64(i))
+		if db == nil {
+			continue
+		}
+
+		// partial replicas hold their own transactions, their tree is not a prefix of the one of the primary
+		rOpts := db.GetOptions().GetReplicationOptions()
+		if rOpts == nil || !rOpts.Replica || rOpts.Partial || rOpts.SrcAddress == "" {
+			continue
+		}
+
+		tlsConfig, err := replicationClientTLSConfig(rOpts)
+		if err != nil {
+			s.Logger.Warningf("Unable to verify replica '%s' against its primary: %v", db.GetName(), err)
+			continue
+		}
+
+		primary, primaryName, err := dialRemoteDatabase(rOpts.SrcAddress, rOpts.SrcPort, rOpts.SrcDatabase, rOpts.FollowerUsr, rOpts.FollowerPwd, tlsConfig)
+		if err != nil {
+			s.Logger.Warningf("Unable to verify replica '%s' against its primary: %v", db.GetName(), err)
+			continue
+		}
+
+		verification, err := verifyReplica(db, primary)
+		primary.conn.Close()
+		if err != nil {
+			s.Logger.Warningf("Unable to verify replica '%s' against its primary: %v", db.GetName(), err)
+			continue
+		}
+
+		s.reportReplicaVerification(db.GetName(), primaryName, verification)
+	}
+}
+
+// verifyReplica checks through a consistency proof that the tree of the replica is a prefix of the one of its primary
+// or, when the replica is ahead, the other way around. The proof is provided by the database holding the longer tree
+func verifyReplica(replica database.DB, primary primaryDatabase) (*replicaVerification, error) {
+	replicaState, err := replica.CurrentState()
+	if err != nil {
+		return nil, err
+	}
+
+	primaryState, err := primary.CurrentState()
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &replicaVerification{
+		replicaTx: replicaState.TxId,
+		primaryTx: primaryState.TxId,
+	}
+
+	if replicaState.TxId == 0 || primaryState.TxId == 0 {
+		verification.consistent = true
+		return verification, nil
+	}
+
+	var prover primaryDatabase = primary
+	sourceState, targetState := replicaState, primaryState
+
+	if replicaState.TxId > primaryState.TxId {
+		prover = replica
+		sourceState, targetState = primaryState, replicaState
+	}
+
+	vTx, err := prover.VerifiableTxByID(&schema.VerifiableTxRequest{
+		Tx:           targetState.TxId,
+		ProveSinceTx: sourceState.TxId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	verification.consistent = store.VerifyDualProof(
+		schema.DualProofFrom(vTx.DualProof),
+		sourceState.TxId,
+		targetState.TxId,
+		schema.DigestFrom(sourceState.TxHash),
+		schema.DigestFrom(targetState.TxHash),
+	)
+
+	if verification.consistent {
+		return verification, nil
+	}
+
+	upToTx := replicaState.TxId
+	if primaryState.TxId < upToTx {
+		upToTx = primaryState.TxId
+	}
+
+	// the divergence is still reported when it can not be located
+	verification.divergentTx, verification.replicaTxHash, verification.primaryTxHash, _ = locateDivergence(replica, primary, upToTx)
+
+	return verification, nil
+}
+
+// locateDivergence finds through a binary search the first transaction up to upToTx holding different hashes
+// on the replica and on its primary. As each hash accumulates the ones of the previous transactions,
+// all the transactions following the first divergent one differ as well
+func locateDivergence(replica database.DB, primary primaryDatabase, upToTx uint64) (txID uint64, replicaTxHash, primaryTxHash []byte, err error) {
+	lo, hi := uint64(1), upToTx
+
+	for lo <= hi {
+		mid := lo + (hi-lo)/2
+
+		replicaAlh, err := txHashOf(replica, mid)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+
+		primaryAlh, err := txHashOf(primary, mid)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+
+		if bytes.Equal(replicaAlh, primaryAlh) {
+			lo = mid + 1
+			continue
+		}
+
+		txID, replicaTxHash, primaryTxHash = mid, replicaAlh, primaryAlh
+		hi = mid - 1
+	}
+
+	if txID == 0 {
+		return 0, nil, nil, ErrIllegalState
+	}
+
+	return txID, replicaTxHash, primaryTxHash, nil
+}
+
+// txHashOf returns the accumulated linear hash of the given transaction
+func txHashOf(db primaryDatabase, txID uint64) ([]byte, error) {
+	vTx, err := db.VerifiableTxByID(&schema.VerifiableTxRequest{Tx: txID, ProveSinceTx: txID})
+	if err != nil {
+		return nil, err
+	}
+
+	alh := schema.TxMetadataFrom(vTx.Tx.Metadata).Alh()
+
+	return alh[:], nil
+}
+
+// reportReplicaVerification updates the replica metrics and alerts when a replica is first found diverging from its primary
+func (s *ImmuServer) reportReplicaVerification(database, primaryName string, verification *replicaVerification) {
+	lag := uint64(0)
+	if verification.primaryTx > verification.replicaTx {
+		lag = verification.primaryTx - verification.replicaTx
+	}
+
+	Metrics.ReplicaLagGauges.WithLabelValues(database).Set(float64(lag))
+
+	var divergence *replicaDivergence
+
+	if !verification.consistent {
+		divergence = &replicaDivergence{
+			Primary:       primaryName,
+			ReplicaTx:     verification.replicaTx,
+			PrimaryTx:     verification.primaryTx,
+			DivergentTx:   verification.divergentTx,
+			ReplicaTxHash: verification.replicaTxHash,
+			PrimaryTxHash: verification.primaryTxHash,
+			DetectedAt:    time.Now(),
+		}
+	}
+
+	s.divergedMux.Lock()
+	wasDiverged := s.diverged[database] != nil
+	if divergence == nil || !wasDiverged {
+		s.diverged[database] = divergence
+	}
+	s.divergedMux.Unlock()
+
+	if verification.consistent {
+		Metrics.ReplicaDivergedGauges.WithLabelValues(database).Set(0)
+
+		s.replicaProgressed(database, "", verification.replicaTx, lag)
+
+		if wasDiverged {
+			s.Logger.Infof("Replica '%s' is consistent again with its primary, %s, replication resumed", database, primaryName)
+
+			s.auditReplication(database, ReplicationAuditConverged, "replica verifier", verification.replicaTx,
+				fmt.Sprintf("consistent again with primary %s at tx %d", primaryName, verification.primaryTx))
+		}
+
+		if verification.replicaTx > verification.primaryTx {
+			s.Logger.Warningf("Replica '%s' is at tx %d, ahead of its primary at tx %d", database, verification.replicaTx, verification.primaryTx)
+		}
+
+		return
+	}
+
+	Metrics.ReplicaDivergedGauges.WithLabelValues(database).Set(1)
+
+	if wasDiverged {
+		return
+	}
+
+	if divergence.DivergentTx > 0 {
+		s.Logger.Errorf("Replica '%s' at tx %d diverges from its primary, %s, at tx %d: tx %d has hash %x on the replica and %x on the primary, replication stopped",
+			database, verification.replicaTx, primaryName, verification.primaryTx, divergence.DivergentTx, divergence.ReplicaTxHash, divergence.PrimaryTxHash)
+	} else {
+		s.Logger.Errorf("Replica '%s' at tx %d diverges from its primary, %s, at tx %d, replication stopped",
+			database, verification.replicaTx, primaryName, verification.primaryTx)
+	}
+
+	s.sendCorruptionAlert(&corruptionAlert{
+		Server:     s.UUID.String(),
+		Database:   database,
+		DetectedAt: divergence.DetectedAt,
+		Divergence: divergence,
+	})
+
+	s.replicationFailed(database, "", divergence.DivergentTx, ErrDivergedFromPrimary)
+
+	s.auditReplication(database, ReplicationAuditDiverged, "replica verifier", divergence.DivergentTx,
+		fmt.Sprintf("replica at tx %d diverges from primary %s at tx %d", verification.replicaTx, primaryName, verification.primaryTx))
+}
+
+// divergenceOf returns the divergence of a replica from its primary, nil unless found diverging
+func (s *ImmuServer) divergenceOf(database string) *replicaDivergence {
+	s.divergedMux.Lock()
+	defer s.divergedMux.Unlock()
+
+	return s.diverged[database]
+}
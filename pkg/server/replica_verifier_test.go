@@ -0,0 +1,258 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServerReplicaVerifier(t *testing.T) {
+	var alertsMux sync.Mutex
+	var alerts []*corruptionAlert
+
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert corruptionAlert
+
+		err := json.NewDecoder(r.Body).Decode(&alert)
+		require.NoError(t, err)
+
+		alertsMux.Lock()
+		alerts = append(alerts, &alert)
+		alertsMux.Unlock()
+	}))
+	defer webhook.Close()
+
+	options := DefaultOptions().
+		WithDir("data_replica_verifier").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithReplicaVerificationInterval(1).
+		WithCorruptionAlertOptions(DefaultCorruptionAlertOptions().WithWebhookURLs([]string{webhook.URL}))
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{DatabaseName: "replicadb", Replica: true})
+	require.NoError(t, err)
+
+	_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{DatabaseName: "otherdb"})
+	require.NoError(t, err)
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	primary := s.dbList.GetByIndex(defaultDbIndex)
+
+	replica, err := s.dbList.GetByName("replicadb")
+	require.NoError(t, err)
+
+	other, err := s.dbList.GetByName("otherdb")
+	require.NoError(t, err)
+
+	_, err = other.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("other")}}})
+	require.NoError(t, err)
+
+	verification, err := verifyReplica(replica, primary)
+	require.NoError(t, err)
+	require.True(t, verification.consistent)
+	require.Zero(t, verification.replicaTx)
+
+	primaryState, err := primary.CurrentState()
+	require.NoError(t, err)
+
+	err = replicateTxs(primary, replica, primaryState.TxId)
+	require.NoError(t, err)
+
+	verification, err = verifyReplica(replica, primary)
+	require.NoError(t, err)
+	require.True(t, verification.consistent)
+	require.NotZero(t, verification.replicaTx)
+	require.Equal(t, verification.primaryTx, verification.replicaTx)
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.NoError(t, err)
+
+	// the replica lags behind
+	verification, err = verifyReplica(replica, primary)
+	require.NoError(t, err)
+	require.True(t, verification.consistent)
+	require.Equal(t, verification.replicaTx+1, verification.primaryTx)
+
+	s.reportReplicaVerification("replicadb", "primary", verification)
+	require.Equal(t, float64(1), testutil.ToFloat64(Metrics.ReplicaLagGauges.WithLabelValues("replicadb")))
+	require.Zero(t, testutil.ToFloat64(Metrics.ReplicaDivergedGauges.WithLabelValues("replicadb")))
+
+	// the proof is then provided by the replica
+	verification, err = verifyReplica(primary, replica)
+	require.NoError(t, err)
+	require.True(t, verification.consistent)
+	require.Equal(t, verification.primaryTx+1, verification.replicaTx)
+
+	verification, err = verifyReplica(other, primary)
+	require.NoError(t, err)
+	require.False(t, verification.consistent)
+
+	// the divergence is alerted once
+	s.reportReplicaVerification("otherdb", "primary", verification)
+	s.reportReplicaVerification("otherdb", "primary", verification)
+	require.Equal(t, float64(1), testutil.ToFloat64(Metrics.ReplicaDivergedGauges.WithLabelValues("otherdb")))
+
+	alertsMux.Lock()
+	require.Len(t, alerts, 1)
+	require.Equal(t, "otherdb", alerts[0].Database)
+	require.NotNil(t, alerts[0].Divergence)
+	require.Equal(t, "primary", alerts[0].Divergence.Primary)
+	require.Equal(t, verification.replicaTx, alerts[0].Divergence.ReplicaTx)
+	alertsMux.Unlock()
+
+	s.reportReplicaVerification("otherdb", "primary", &replicaVerification{replicaTx: 1, primaryTx: 1, consistent: true})
+	require.Zero(t, testutil.ToFloat64(Metrics.ReplicaDivergedGauges.WithLabelValues("otherdb")))
+
+	// replicas without a known primary are skipped
+	s.verifyReplicas(nil)
+
+	s.startReplicaVerifier()
+
+	s.stopReplicaVerifier()
+	s.stopReplicaVerifier()
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
+
+func TestLocateDivergence(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_replica_divergence").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{DatabaseName: "forkdb", Replica: true})
+	require.NoError(t, err)
+
+	primary := s.dbList.GetByIndex(defaultDbIndex)
+
+	fork, err := s.dbList.GetByName("forkdb")
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		_, err = primary.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte{byte(i)}}}})
+		require.NoError(t, err)
+	}
+
+	sharedState, err := primary.CurrentState()
+	require.NoError(t, err)
+
+	err = replicateTxs(primary, fork, sharedState.TxId)
+	require.NoError(t, err)
+
+	// the replica gets wrongly promoted while its primary keeps on committing
+	fork.UpdateReplicationOptions(&database.ReplicationOptions{})
+
+	for i := 0; i < 3; i++ {
+		_, err = fork.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("fork")}}})
+		require.NoError(t, err)
+
+		_, err = primary.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("primary")}}})
+		require.NoError(t, err)
+	}
+
+	_, err = primary.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("primary")}}})
+	require.NoError(t, err)
+
+	verification, err := verifyReplica(fork, primary)
+	require.NoError(t, err)
+	require.False(t, verification.consistent)
+	require.Equal(t, sharedState.TxId+3, verification.replicaTx)
+	require.Equal(t, sharedState.TxId+4, verification.primaryTx)
+	require.Equal(t, sharedState.TxId+1, verification.divergentTx)
+
+	forkTx, err := fork.TxByID(&schema.TxRequest{Tx: verification.divergentTx})
+	require.NoError(t, err)
+
+	forkAlh := schema.TxMetadataFrom(forkTx.Metadata).Alh()
+	require.Equal(t, forkAlh[:], verification.replicaTxHash)
+
+	primaryTx, err := primary.TxByID(&schema.TxRequest{Tx: verification.divergentTx})
+	require.NoError(t, err)
+
+	primaryAlh := schema.TxMetadataFrom(primaryTx.Metadata).Alh()
+	require.Equal(t, primaryAlh[:], verification.primaryTxHash)
+
+	// matching histories have no divergent transaction
+	_, _, _, err = locateDivergence(fork, primary, sharedState.TxId)
+	require.Equal(t, ErrIllegalState, err)
+
+	s.reportReplicaVerification("forkdb", "primary", verification)
+
+	divergence := s.divergenceOf("forkdb")
+	require.NotNil(t, divergence)
+	require.Equal(t, verification.divergentTx, divergence.DivergentTx)
+	require.Equal(t, verification.replicaTxHash, divergence.ReplicaTxHash)
+	require.Equal(t, verification.primaryTxHash, divergence.PrimaryTxHash)
+
+	// replication resumes once the replica is consistent again
+	s.reportReplicaVerification("forkdb", "primary", &replicaVerification{replicaTx: 1, primaryTx: 1, consistent: true})
+	require.Nil(t, s.divergenceOf("forkdb"))
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
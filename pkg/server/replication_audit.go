@@ -0,0 +1,180 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+)
+
+// Kinds of the entries of the replication audit trail
+const (
+	// ReplicationAuditTopologyChanged records a change of the primary a replica replicates from, or a new replica
+	ReplicationAuditTopologyChanged = "topology_changed"
+	// ReplicationAuditPromoted records a replica becoming a primary, or a cluster node becoming the leader
+	ReplicationAuditPromoted = "promoted"
+	// ReplicationAuditDemoted records a primary becoming a replica, or a cluster node losing the leadership
+	ReplicationAuditDemoted = "demoted"
+	// ReplicationAuditResynced records a replica brought back in sync with its primary out of band
+	ReplicationAuditResynced = "resynced"
+	// ReplicationAuditDiverged records a replica found diverging from its primary
+	ReplicationAuditDiverged = "diverged"
+	// ReplicationAuditConverged records a diverged replica found consistent again with its primary
+	ReplicationAuditConverged = "converged"
+)
+
+// replicationAudit is an entry of the replication audit trail of a database, as stored in the system database.
+// Every entry is written as a new revision of the same key, so the history of the key is the trail of the database
+type replicationAudit struct {
+	Kind    string    `json:"kind"`
+	Actor   string    `json:"actor"`
+	Details string    `json:"details"`
+	TxID    uint64    `json:"txId,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+func replicationAuditKey(database string) []byte {
+	key := make([]byte, 1+len(database))
+	key[0] = KeyPrefixReplicationAudit
+	copy(key[1:], []byte(database))
+	return key
+}
+
+// auditReplication appends an entry to the replication audit trail of the database.
+// The change being recorded already took place, so failures are only logged
+func (s *ImmuServer) auditReplication(database, kind, actor string, txID uint64, details string) {
+	auditData, err := json.Marshal(&replicationAudit{
+		Kind:    kind,
+		Actor:   actor,
+		Details: details,
+		TxID:    txID,
+		At:      time.Now(),
+	})
+	if err != nil {
+		s.Logger.Errorf("Unable to encode the replication audit entry of database '%s': %v", database, err)
+		return
+	}
+
+	_, err = s.sysDB.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: replicationAuditKey(database), Value: auditData}}})
+	if err != nil {
+		s.Logger.Errorf("Unable to record the replication audit entry of database '%s': %v", database, err)
+	}
+}
+
+// auditReplicationChange records the change of role or of primary between the given settings, if any
+func (s *ImmuServer) auditReplicationChange(previous, current *dbSettings, actor string, txID uint64) {
+	kind := ReplicationAuditTopologyChanged
+
+	switch {
+	case previous.Replica && !current.Replica:
+		kind = ReplicationAuditPromoted
+	case !previous.Replica && current.Replica:
+		kind = ReplicationAuditDemoted
+	case !current.Replica || replicationTopology(previous) == replicationTopology(current):
+		return
+	}
+
+	s.auditReplication(current.Database, kind, actor, txID,
+		fmt.Sprintf("from %s to %s", replicationTopology(previous), replicationTopology(current)))
+}
+
+// replicationTopology describes the role of the database and the primary it replicates from
+func replicationTopology(settings *dbSettings) string {
+	if !settings.Replica {
+		return "primary"
+	}
+
+	if settings.SrcAddress == "" {
+		return fmt.Sprintf("replica of database '%s'", settings.SrcDatabase)
+	}
+
+	return fmt.Sprintf("replica of database '%s' at %s:%d", settings.SrcDatabase, settings.SrcAddress, settings.SrcPort)
+}
+
+// ReplicationAudit returns the replication audit trail of the database in use or, for sysadmins and the admins
+// of the database, of the requested one, even if it was deleted
+func (s *ImmuServer) ReplicationAudit(ctx context.Context, req *schema.ReplicationAuditRequest) (*schema.ReplicationAuditList, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	dbName := req.Database
+
+	if dbName == "" {
+		db, err := s.getDBFromCtx(ctx, "ReplicationAudit")
+		if err != nil {
+			return nil, err
+		}
+
+		dbName = db.GetName()
+	} else {
+		if !s.Options.GetAuth() {
+			return nil, ErrAuthMustBeEnabled
+		}
+
+		_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+		if err != nil {
+			return nil, ErrNotLoggedIn
+		}
+
+		if !user.IsSysAdmin && !user.HasPermission(dbName, auth.PermissionAdmin) {
+			return nil, ErrPermissionDenied
+		}
+	}
+
+	list := &schema.ReplicationAuditList{Database: dbName}
+
+	entries, err := s.sysDB.History(&schema.HistoryRequest{
+		Key:    replicationAuditKey(dbName),
+		Offset: req.Offset,
+		Limit:  req.Limit,
+		Desc:   req.Desc,
+	})
+	if err == store.ErrKeyNotFound || err == store.ErrNoMoreEntries {
+		return list, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list.Entries = make([]*schema.ReplicationAuditEntry, len(entries.Entries))
+
+	for i, e := range entries.Entries {
+		var entry replicationAudit
+
+		err = json.Unmarshal(e.Value, &entry)
+		if err != nil {
+			return nil, err
+		}
+
+		list.Entries[i] = &schema.ReplicationAuditEntry{
+			Kind:      entry.Kind,
+			Actor:     entry.Actor,
+			Details:   entry.Details,
+			TxId:      entry.TxID,
+			Timestamp: entry.At.Unix(),
+		}
+	}
+
+	return list, nil
+}
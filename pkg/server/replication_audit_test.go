@@ -0,0 +1,147 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestReplicationAudit(t *testing.T) {
+	serverOptions := DefaultOptions().
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	_, err = s.ReplicationAudit(context.Background(), nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = s.ReplicationAudit(context.Background(), &schema.ReplicationAuditRequest{Database: "porto"})
+	require.Equal(t, ErrNotLoggedIn, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	// nothing recorded yet
+	list, err := s.ReplicationAudit(ctx, &schema.ReplicationAuditRequest{})
+	require.NoError(t, err)
+	require.Equal(t, DefaultdbName, list.Database)
+	require.Empty(t, list.Entries)
+
+	_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{
+		DatabaseName: "porto",
+		Replica:      true,
+		SrcDatabase:  "lisbon",
+		SrcAddress:   "127.0.0.1",
+		SrcPort:      3323,
+	})
+	require.NoError(t, err)
+
+	_, err = s.UpdateReplication(ctx, &schema.UpdateReplicationRequest{DatabaseName: "porto", Replica: true, SrcDatabase: "madrid"})
+	require.NoError(t, err)
+
+	// the same topology is not recorded again
+	_, err = s.UpdateReplication(ctx, &schema.UpdateReplicationRequest{DatabaseName: "porto", Replica: true})
+	require.NoError(t, err)
+
+	s.reportReplicaVerification("porto", "madrid", &replicaVerification{replicaTx: 2, primaryTx: 3, divergentTx: 2})
+	s.reportReplicaVerification("porto", "madrid", &replicaVerification{replicaTx: 2, primaryTx: 3, divergentTx: 2})
+	s.reportReplicaVerification("porto", "madrid", &replicaVerification{replicaTx: 3, primaryTx: 3, consistent: true})
+
+	res, err := s.UpdateReplication(ctx, &schema.UpdateReplicationRequest{DatabaseName: "porto"})
+	require.NoError(t, err)
+
+	_, err = s.UpdateDatabaseSettings(ctx, &schema.UpdateDatabaseSettingsRequest{
+		DatabaseName: "porto",
+		Settings: &schema.DatabaseNullableSettings{
+			ReplicationSettings: &schema.ReplicationNullableSettings{Replica: &schema.NullableBool{Value: true}},
+		},
+	})
+	require.NoError(t, err)
+
+	list, err = s.ReplicationAudit(ctx, &schema.ReplicationAuditRequest{Database: "porto"})
+	require.NoError(t, err)
+	require.Equal(t, "porto", list.Database)
+	require.Len(t, list.Entries, 6)
+
+	expected := []struct {
+		kind  string
+		actor string
+	}{
+		{ReplicationAuditTopologyChanged, auth.SysAdminUsername},
+		{ReplicationAuditTopologyChanged, auth.SysAdminUsername},
+		{ReplicationAuditDiverged, "replica verifier"},
+		{ReplicationAuditConverged, "replica verifier"},
+		{ReplicationAuditPromoted, auth.SysAdminUsername},
+		{ReplicationAuditDemoted, auth.SysAdminUsername},
+	}
+
+	for i, exp := range expected {
+		require.Equal(t, exp.kind, list.Entries[i].Kind)
+		require.Equal(t, exp.actor, list.Entries[i].Actor)
+		require.NotZero(t, list.Entries[i].Timestamp)
+	}
+
+	require.Equal(t, "created as replica of database 'lisbon' at 127.0.0.1:3323", list.Entries[0].Details)
+	require.Equal(t, "from replica of database 'lisbon' at 127.0.0.1:3323 to replica of database 'madrid' at 127.0.0.1:3323", list.Entries[1].Details)
+	require.Equal(t, uint64(2), list.Entries[2].TxId)
+	require.Equal(t, res.TxId, list.Entries[4].TxId)
+
+	list, err = s.ReplicationAudit(ctx, &schema.ReplicationAuditRequest{Database: "porto", Limit: 2, Desc: true})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 2)
+	require.Equal(t, ReplicationAuditDemoted, list.Entries[0].Kind)
+	require.Equal(t, ReplicationAuditPromoted, list.Entries[1].Kind)
+
+	list, err = s.ReplicationAudit(ctx, &schema.ReplicationAuditRequest{Database: "porto", Offset: 6})
+	require.NoError(t, err)
+	require.Empty(t, list.Entries)
+
+	// only admins of the database can query its audit trail
+	_, err = s.CreateUser(ctx, &schema.CreateUserRequest{
+		User:       []byte("reader"),
+		Password:   []byte("Reader1!"),
+		Permission: auth.PermissionR,
+		Database:   "porto",
+	})
+	require.NoError(t, err)
+
+	lr, err = s.Login(context.Background(), &schema.LoginRequest{User: []byte("reader"), Password: []byte("Reader1!")})
+	require.NoError(t, err)
+
+	readerCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.ReplicationAudit(readerCtx, &schema.ReplicationAuditRequest{Database: "porto"})
+	require.Equal(t, ErrPermissionDenied, err)
+}
@@ -0,0 +1,378 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// Kinds of the replication events
+const (
+	// ReplicationEventSyncStarted fires when a replica starts fetching transactions, or resumes after an error
+	ReplicationEventSyncStarted = "sync_started"
+	// ReplicationEventCaughtUp fires when a replica holds every transaction of its primary
+	ReplicationEventCaughtUp = "caught_up"
+	// ReplicationEventLagExceeded fires when a replica lags behind its primary more than the configured threshold
+	ReplicationEventLagExceeded = "lag_exceeded"
+	// ReplicationEventError fires when the replication fails, repeated failures are reported once
+	ReplicationEventError = "error"
+	// ReplicationEventPromoted fires when a replica gets promoted to primary
+	ReplicationEventPromoted = "promoted"
+)
+
+// replicationHookTimeout bounds each webhook call made for a replication event
+const replicationHookTimeout = 10 * time.Second
+
+// replicationEventsBufferSize is the number of events kept for a slow subscriber or webhook before dropping them
+const replicationEventsBufferSize = 128
+
+// replicationEvent is the payload sent to the replication webhooks
+type replicationEvent struct {
+	Kind     string `json:"kind"`
+	Server   string `json:"server"`
+	Database string `json:"database"`
+	// ReplicaID is empty for the events of the replica itself
+	ReplicaID string    `json:"replicaId,omitempty"`
+	TxID      uint64    `json:"txId,omitempty"`
+	Lag       uint64    `json:"lag,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func (e *replicationEvent) toProto() *schema.ReplicationEvent {
+	return &schema.ReplicationEvent{
+		Kind:      e.Kind,
+		Server:    e.Server,
+		Database:  e.Database,
+		ReplicaId: e.ReplicaID,
+		TxId:      e.TxID,
+		Lag:       e.Lag,
+		Message:   e.Message,
+		Timestamp: e.Timestamp.Unix(),
+	}
+}
+
+type replicaKey struct {
+	database  string
+	replicaID string
+}
+
+// replicaEventState holds what was last reported about a replica, so events only fire on changes
+type replicaEventState struct {
+	syncing     bool
+	caughtUp    bool
+	lagExceeded bool
+	lastError   string
+}
+
+type replicationSubscriber struct {
+	// database is empty for subscribers of every database
+	database string
+	events   chan *replicationEvent
+}
+
+// replicationEventHub fans out the replication events to the subscribed streams and to the webhooks
+type replicationEventHub struct {
+	mutex       sync.Mutex
+	subscribers map[*replicationSubscriber]struct{}
+	hooks       chan *replicationEvent
+	replicas    map[replicaKey]*replicaEventState
+}
+
+func newReplicationEventHub() *replicationEventHub {
+	return &replicationEventHub{
+		subscribers: make(map[*replicationSubscriber]struct{}),
+		replicas:    make(map[replicaKey]*replicaEventState),
+	}
+}
+
+func (h *replicationEventHub) subscribe(database string) *replicationSubscriber {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	sub := &replicationSubscriber{
+		database: database,
+		events:   make(chan *replicationEvent, replicationEventsBufferSize),
+	}
+
+	h.subscribers[sub] = struct{}{}
+
+	return sub
+}
+
+func (h *replicationEventHub) unsubscribe(sub *replicationSubscriber) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	delete(h.subscribers, sub)
+}
+
+func (h *replicationEventHub) setHooks(hooks chan *replicationEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.hooks = hooks
+}
+
+// publish never blocks, events are dropped for the subscribers not keeping up
+func (h *replicationEventHub) publish(e *replicationEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for sub := range h.subscribers {
+		if sub.database != "" && sub.database != e.Database {
+			continue
+		}
+
+		select {
+		case sub.events <- e:
+		default:
+		}
+	}
+
+	if h.hooks != nil {
+		select {
+		case h.hooks <- e:
+		default:
+		}
+	}
+}
+
+func (h *replicationEventHub) stateOf(database, replicaID string) *replicaEventState {
+	key := replicaKey{database: database, replicaID: replicaID}
+
+	state, ok := h.replicas[key]
+	if !ok {
+		state = &replicaEventState{}
+		h.replicas[key] = state
+	}
+
+	return state
+}
+
+// exporting records that transactions are being sent to the replica, it reports whether its sync just started
+func (h *replicationEventHub) exporting(database, replicaID string) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	state := h.stateOf(database, replicaID)
+
+	started := !state.syncing
+	state.syncing = true
+
+	return started
+}
+
+// progressed records the lag of the replica, it reports whether it just caught up or just exceeded the lag threshold
+func (h *replicationEventHub) progressed(database, replicaID string, lag, lagThreshold uint64) (caughtUp, lagExceeded bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	state := h.stateOf(database, replicaID)
+
+	state.lastError = ""
+
+	caughtUp = lag == 0 && !state.caughtUp
+	state.caughtUp = lag == 0
+
+	exceeded := lagThreshold > 0 && lag > lagThreshold
+	lagExceeded = exceeded && !state.lagExceeded
+	state.lagExceeded = exceeded
+
+	return caughtUp, lagExceeded
+}
+
+// failed records a replication failure, it reports whether it differs from the last one reported.
+// The sync of the replica is considered interrupted until it fetches transactions again
+func (h *replicationEventHub) failed(database, replicaID string, err error) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	state := h.stateOf(database, replicaID)
+
+	state.syncing = false
+	state.caughtUp = false
+
+	if state.lastError == err.Error() {
+		return false
+	}
+
+	state.lastError = err.Error()
+
+	return true
+}
+
+// recovered records a successful replication step, so that a later failure gets reported again
+func (h *replicationEventHub) recovered(database, replicaID string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if state, ok := h.replicas[replicaKey{database: database, replicaID: replicaID}]; ok {
+		state.lastError = ""
+	}
+}
+
+// emitReplicationEvent publishes the event to the subscribed streams and queues it for the webhooks
+func (s *ImmuServer) emitReplicationEvent(e *replicationEvent) {
+	e.Server = s.UUID.String()
+	e.Timestamp = time.Now()
+
+	s.replicationEvents.publish(e)
+}
+
+// replicaProgressed fires the events due to the lag of a replica, the replica itself when replicaID is empty
+func (s *ImmuServer) replicaProgressed(database, replicaID string, txID, lag uint64) {
+	caughtUp, lagExceeded := s.replicationEvents.progressed(database, replicaID, lag, s.Options.ReplicationLagThreshold)
+
+	if caughtUp {
+		s.emitReplicationEvent(&replicationEvent{Kind: ReplicationEventCaughtUp, Database: database, ReplicaID: replicaID, TxID: txID})
+	}
+
+	if lagExceeded {
+		s.emitReplicationEvent(&replicationEvent{Kind: ReplicationEventLagExceeded, Database: database, ReplicaID: replicaID, TxID: txID, Lag: lag})
+	}
+}
+
+// replicationFailed fires an error event unless the same error was the last one reported
+func (s *ImmuServer) replicationFailed(database, replicaID string, txID uint64, err error) {
+	if !s.replicationEvents.failed(database, replicaID, err) {
+		return
+	}
+
+	s.emitReplicationEvent(&replicationEvent{Kind: ReplicationEventError, Database: database, ReplicaID: replicaID, TxID: txID, Message: err.Error()})
+}
+
+// startReplicationHooks runs in background the delivery of the replication events to the configured webhooks
+func (s *ImmuServer) startReplicationHooks() {
+	if len(s.Options.ReplicationHookURLs) == 0 {
+		return
+	}
+
+	hooks := make(chan *replicationEvent, replicationEventsBufferSize)
+
+	s.replicationHooksDone = make(chan struct{})
+	s.replicationHooksFinished = make(chan struct{})
+
+	s.replicationEvents.setHooks(hooks)
+
+	go s.replicationHooksDispatcher(hooks, s.replicationHooksDone, s.replicationHooksFinished)
+}
+
+// stopReplicationHooks interrupts the delivery of the events, the ones still queued are dropped
+func (s *ImmuServer) stopReplicationHooks() {
+	if s.replicationHooksDone == nil {
+		return
+	}
+
+	s.replicationEvents.setHooks(nil)
+
+	close(s.replicationHooksDone)
+	<-s.replicationHooksFinished
+
+	s.replicationHooksDone = nil
+	s.replicationHooksFinished = nil
+}
+
+func (s *ImmuServer) replicationHooksDispatcher(hooks <-chan *replicationEvent, done <-chan struct{}, finished chan<- struct{}) {
+	defer close(finished)
+
+	client := &http.Client{Timeout: replicationHookTimeout}
+
+	for {
+		select {
+		case <-done:
+			return
+		case e := <-hooks:
+			s.sendReplicationHook(client, e)
+		}
+	}
+}
+
+// sendReplicationHook notifies the configured webhooks in order, failures are only logged
+func (s *ImmuServer) sendReplicationHook(client *http.Client, e *replicationEvent) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		s.Logger.Errorf("Unable to encode replication event: %v", err)
+		return
+	}
+
+	for _, url := range s.Options.ReplicationHookURLs {
+		resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			s.Logger.Errorf("Unable to send replication event to '%s': %v", url, err)
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			s.Logger.Errorf("Replication event rejected by '%s' with status %d", url, resp.StatusCode)
+		}
+	}
+}
+
+// ReplicationEvents streams the replication events of the database in use, or of every database for sysadmins,
+// until the client cancels. Events are dropped when the client does not keep up
+func (s *ImmuServer) ReplicationEvents(req *schema.ReplicationEventsRequest, eventsServer schema.ImmuService_ReplicationEventsServer) error {
+	if req == nil || eventsServer == nil {
+		return ErrIllegalArguments
+	}
+
+	var database string
+
+	if req.AllDatabases {
+		if !s.Options.GetAuth() {
+			return ErrAuthMustBeEnabled
+		}
+
+		_, user, err := s.getLoggedInUserdataFromCtx(eventsServer.Context())
+		if err != nil {
+			return ErrNotLoggedIn
+		}
+
+		if !user.IsSysAdmin {
+			return ErrPermissionDenied
+		}
+	} else {
+		db, err := s.getDBFromCtx(eventsServer.Context(), "ReplicationEvents")
+		if err != nil {
+			return err
+		}
+
+		database = db.GetName()
+	}
+
+	sub := s.replicationEvents.subscribe(database)
+	defer s.replicationEvents.unsubscribe(sub)
+
+	for {
+		select {
+		case <-eventsServer.Context().Done():
+			return nil
+		case e := <-sub.events:
+			err := eventsServer.Send(e.toProto())
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type replicationEventsServer struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *schema.ReplicationEvent
+}
+
+func (s *replicationEventsServer) Send(e *schema.ReplicationEvent) error {
+	s.events <- e
+	return nil
+}
+
+func (s *replicationEventsServer) Context() context.Context {
+	return s.ctx
+}
+
+type exportTxServer struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *exportTxServer) Send(chunk *schema.Chunk) error {
+	return nil
+}
+
+func (s *exportTxServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *exportTxServer) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func nextReplicationEvent(t *testing.T, events <-chan *schema.ReplicationEvent) *schema.ReplicationEvent {
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "replication event not received")
+		return nil
+	}
+}
+
+func TestReplicationEventHub(t *testing.T) {
+	h := newReplicationEventHub()
+
+	require.True(t, h.exporting("db1", "replica1"))
+	require.False(t, h.exporting("db1", "replica1"))
+	require.True(t, h.exporting("db2", "replica1"))
+
+	caughtUp, lagExceeded := h.progressed("db1", "replica1", 10, 5)
+	require.False(t, caughtUp)
+	require.True(t, lagExceeded)
+
+	caughtUp, lagExceeded = h.progressed("db1", "replica1", 8, 5)
+	require.False(t, caughtUp)
+	require.False(t, lagExceeded)
+
+	caughtUp, lagExceeded = h.progressed("db1", "replica1", 0, 5)
+	require.True(t, caughtUp)
+	require.False(t, lagExceeded)
+
+	caughtUp, _ = h.progressed("db1", "replica1", 0, 5)
+	require.False(t, caughtUp)
+
+	// no lag events without a threshold
+	_, lagExceeded = h.progressed("db1", "replica2", 100, 0)
+	require.False(t, lagExceeded)
+
+	require.True(t, h.failed("db1", "replica1", ErrDivergedFromPrimary))
+	require.False(t, h.failed("db1", "replica1", ErrDivergedFromPrimary))
+	require.True(t, h.failed("db1", "replica1", ErrIllegalArguments))
+
+	h.recovered("db1", "replica1")
+	require.True(t, h.failed("db1", "replica1", ErrIllegalArguments))
+
+	// the sync restarts after a failure
+	require.True(t, h.exporting("db1", "replica1"))
+
+	sub1 := h.subscribe("db1")
+	sub2 := h.subscribe("")
+
+	h.publish(&replicationEvent{Kind: ReplicationEventPromoted, Database: "db2"})
+	h.publish(&replicationEvent{Kind: ReplicationEventCaughtUp, Database: "db1"})
+
+	require.Len(t, sub1.events, 1)
+	require.Equal(t, ReplicationEventCaughtUp, (<-sub1.events).Kind)
+	require.Len(t, sub2.events, 2)
+
+	h.unsubscribe(sub1)
+
+	// slow subscribers lose events instead of blocking the replication
+	for i := 0; i < replicationEventsBufferSize; i++ {
+		h.publish(&replicationEvent{Kind: ReplicationEventCaughtUp, Database: "db1"})
+	}
+	require.Len(t, sub1.events, 0)
+	require.Len(t, sub2.events, replicationEventsBufferSize)
+}
+
+func TestReplicationEvents(t *testing.T) {
+	hooked := make(chan *replicationEvent, 10)
+
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e replicationEvent
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&e))
+		hooked <- &e
+	}))
+	defer hook.Close()
+
+	serverOptions := DefaultOptions().
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithReplicationLagThreshold(1).
+		WithReplicationHookURLs([]string{hook.URL}).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	s.Initialize()
+
+	s.startReplicationHooks()
+	defer s.stopReplicationHooks()
+
+	err := s.ReplicationEvents(nil, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = s.ReplicationEvents(&schema.ReplicationEventsRequest{AllDatabases: true}, &replicationEventsServer{ctx: context.Background()})
+	require.Error(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	eventsCtx, cancel := context.WithCancel(ctx)
+
+	eventsServer := &replicationEventsServer{ctx: eventsCtx, events: make(chan *schema.ReplicationEvent, 10)}
+	eventsErr := make(chan error, 1)
+
+	go func() {
+		eventsErr <- s.ReplicationEvents(&schema.ReplicationEventsRequest{}, eventsServer)
+	}()
+
+	require.Eventually(t, func() bool {
+		s.replicationEvents.mutex.Lock()
+		defer s.replicationEvents.mutex.Unlock()
+		return len(s.replicationEvents.subscribers) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+	require.NoError(t, err)
+
+	db, err := s.getDBFromCtx(ctx, "ReplicationEvents")
+	require.NoError(t, err)
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		err = s.ExportTx(&schema.TxRequest{Tx: state.TxId, ReplicaId: "replica1"}, &exportTxServer{ctx: ctx})
+		require.NoError(t, err)
+	}
+
+	_, err = s.ConfirmReplication(ctx, &schema.ConfirmReplicationRequest{ReplicaId: "replica1", TxId: state.TxId, TxHash: state.TxHash})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+		require.NoError(t, err)
+	}
+
+	_, err = s.ConfirmReplication(ctx, &schema.ConfirmReplicationRequest{ReplicaId: "replica1", TxId: state.TxId, TxHash: state.TxHash})
+	require.NoError(t, err)
+
+	// the same failure is only reported once
+	for i := 0; i < 2; i++ {
+		_, err = s.ConfirmReplication(ctx, &schema.ConfirmReplicationRequest{ReplicaId: "replica1", TxId: state.TxId + 1, TxHash: state.TxHash})
+		require.Error(t, err)
+	}
+
+	expected := []struct {
+		kind string
+		lag  uint64
+	}{
+		{ReplicationEventSyncStarted, 0},
+		{ReplicationEventCaughtUp, 0},
+		{ReplicationEventLagExceeded, 2},
+		{ReplicationEventError, 0},
+	}
+
+	for _, exp := range expected {
+		e := nextReplicationEvent(t, eventsServer.events)
+		require.Equal(t, exp.kind, e.Kind)
+		require.Equal(t, DefaultdbName, e.Database)
+		require.Equal(t, "replica1", e.ReplicaId)
+		require.Equal(t, exp.lag, e.Lag)
+		require.Equal(t, s.UUID.String(), e.Server)
+	}
+
+	for _, exp := range expected {
+		select {
+		case e := <-hooked:
+			require.Equal(t, exp.kind, e.Kind)
+			require.Equal(t, "replica1", e.ReplicaID)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "replication event not delivered to the webhook")
+		}
+	}
+
+	require.Len(t, eventsServer.events, 0)
+
+	cancel()
+	require.NoError(t, <-eventsErr)
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/backup"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/codenotary/immudb/pkg/stream"
+	"github.com/klauspost/compress/zstd"
+)
+
+// ExportSnapshot streams to a replica, in bulk, the transactions committed after the last one it holds.
+// Transactions are sent as a zstd-compressed archive, chunked over the stream, instead of one request each.
+// The archive is chained to the state of the replica at sinceTx, so an interrupted snapshot is resumed
+// by requesting a new one from the last transaction the replica committed
+func (s *ImmuServer) ExportSnapshot(req *schema.SnapshotRequest, snapshotServer schema.ImmuService_ExportSnapshotServer) error {
+	if req == nil || snapshotServer == nil {
+		return ErrIllegalArguments
+	}
+
+	db, err := s.getDBFromCtx(snapshotServer.Context(), "ExportSnapshot")
+	if err != nil {
+		return err
+	}
+
+	replicaID, err := s.ownReplicaID(snapshotServer.Context(), db, req.ReplicaId)
+	if err != nil {
+		return err
+	}
+
+	// a filtered replica does not hold the history of its primary
+	if rOpts := db.GetOptions().GetReplicationOptions(); rOpts != nil && replicaID != "" {
+		if _, filtered := rOpts.ReplicaFilters[replicaID]; filtered {
+			return ErrFilteredSnapshot
+		}
+	}
+
+	state, err := db.CurrentState()
+	if err != nil {
+		return err
+	}
+
+	untilTx := state.TxId
+
+	if req.UntilTx > 0 {
+		if req.UntilTx > state.TxId {
+			return ErrIllegalArguments
+		}
+		untilTx = req.UntilTx
+	}
+
+	if req.SinceTx > untilTx {
+		return ErrIllegalArguments
+	}
+
+	if replicaID != "" && s.replicationEvents.exporting(db.GetName(), replicaID) {
+		s.emitReplicationEvent(&replicationEvent{Kind: ReplicationEventSyncStarted, Database: db.GetName(), ReplicaID: replicaID, TxID: req.SinceTx + 1})
+	}
+
+	cw := stream.NewChunkWriter(snapshotServer, s.Options.StreamChunkSize)
+
+	err = writeSnapshot(db, cw, req.SinceTx, untilTx)
+	if err != nil {
+		if replicaID != "" {
+			s.replicationFailed(db.GetName(), replicaID, req.SinceTx+1, err)
+		}
+		return err
+	}
+
+	return cw.Close()
+}
+
+// writeSnapshot writes the compressed archive of the transactions after sinceTx and up to untilTx.
+// Unlike backups, snapshots do not carry the database settings, as they are sent to replication accounts
+func writeSnapshot(db database.DB, w io.Writer, sinceTx, untilTx uint64) error {
+	var prevAlh []byte
+
+	if sinceTx > 0 {
+		tx, err := db.TxByID(&schema.TxRequest{Tx: sinceTx})
+		if err != nil {
+			return err
+		}
+
+		alh := schema.TxMetadataFrom(tx.Metadata).Alh()
+		prevAlh = alh[:]
+	}
+
+	zw, err := zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.SpeedFastest))
+	if err != nil {
+		return err
+	}
+
+	aw, err := backup.NewWriter(zw, &backup.Header{
+		Database:  db.GetName(),
+		FromTx:    sinceTx + 1,
+		UntilTx:   untilTx,
+		PrevAlh:   prevAlh,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		zw.Close()
+		return err
+	}
+
+	err = exportTxs(db, aw, sinceTx+1, untilTx)
+	if err == nil {
+		err = aw.Close(aw.Trailer())
+	}
+	if err != nil {
+		zw.Close()
+		return err
+	}
+
+	return zw.Close()
+}
+
+// SyncSnapshot brings a replica in sync with its primary through a snapshot of the transactions it misses,
+// optionally up to a given one. It's meant for the first sync of a replica, or after a long disconnection,
+// then replication goes on one transaction at a time. Transactions are verified and committed as they are
+// received, so an interrupted sync is resumed from the last committed transaction by syncing again
+func (s *ImmuServer) SyncSnapshot(ctx context.Context, req *schema.SyncSnapshotRequest) (*schema.SyncSnapshotResponse, error) {
+	s.Logger.Debugf("syncsnapshot")
+
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	if !s.Options.GetAuth() {
+		return nil, ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin && !user.HasPermission(req.DatabaseName, auth.PermissionAdmin) {
+		return nil, fmt.Errorf("you do not have permission on this database")
+	}
+
+	db, err := s.dbList.GetByName(req.DatabaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	rOpts := db.GetOptions().GetReplicationOptions()
+	if rOpts == nil || !rOpts.Replica || rOpts.Partial || rOpts.SrcAddress == "" {
+		return nil, ErrSnapshotSourceRequired
+	}
+
+	if s.divergenceOf(db.GetName()) != nil {
+		return nil, ErrDivergedFromPrimary
+	}
+
+	tlsConfig, err := replicationClientTLSConfig(rOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	primary, primaryName, err := dialRemoteDatabase(rOpts.SrcAddress, rOpts.SrcPort, rOpts.SrcDatabase, rOpts.FollowerUsr, rOpts.FollowerPwd, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer primary.conn.Close()
+
+	state, err := db.CurrentState()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotCtx, cancel := context.WithCancel(primary.ctx)
+	defer cancel()
+
+	snapshotStream, err := primary.client.ExportSnapshot(snapshotCtx, &schema.SnapshotRequest{SinceTx: state.TxId, UntilTx: req.UntilTx})
+	if err != nil {
+		return nil, err
+	}
+
+	cr := &countingReader{r: stream.NewChunkReader(snapshotStream)}
+
+	start := time.Now()
+
+	lastTx, err := applySnapshot(db, cr, state)
+	if lastTx > state.TxId {
+		s.Logger.Infof("Replica '%s' synced from tx %d up to tx %d through a snapshot of %s, %d bytes in %s",
+			db.GetName(), state.TxId+1, lastTx, primaryName, cr.n, time.Since(start))
+
+		s.auditReplication(db.GetName(), ReplicationAuditResynced, user.Username, lastTx,
+			fmt.Sprintf("synced txs %d to %d through a snapshot of %s", state.TxId+1, lastTx, primaryName))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.SyncSnapshotResponse{
+		DatabaseName:     db.GetName(),
+		FromTxId:         state.TxId + 1,
+		TxId:             lastTx,
+		TxCount:          lastTx - state.TxId,
+		TransferredBytes: cr.n,
+	}, nil
+}
+
+// applySnapshot replicates the transactions of a snapshot taken from the given state of the replica,
+// it returns the last transaction held by the replica, even when the snapshot could only be partially applied
+func applySnapshot(db database.DB, r io.Reader, state *schema.ImmutableState) (uint64, error) {
+	zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return state.TxId, err
+	}
+	defer zr.Close()
+
+	ar, err := backup.NewReader(zr)
+	if err != nil {
+		return state.TxId, err
+	}
+
+	hdr := ar.Header()
+
+	if hdr.FromTx != state.TxId+1 || (state.TxId > 0 && !bytes.Equal(hdr.PrevAlh, state.TxHash)) {
+		return state.TxId, ErrSnapshotDiverged
+	}
+
+	lastTx, _, err := replayArchive(ar, db, 0, 0)
+	if lastTx == 0 {
+		lastTx = state.TxId
+	}
+
+	return lastTx, err
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n uint64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += uint64(n)
+	return n, err
+}
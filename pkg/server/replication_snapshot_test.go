@@ -0,0 +1,239 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package server
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestSnapshotResume(t *testing.T) {
+	serverOptions := DefaultOptions().
+		WithDir("data_snapshot_resume").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+	defer s.CloseDatabases()
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{DatabaseName: "primarydb"})
+	require.NoError(t, err)
+
+	_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{DatabaseName: "replicadb", Replica: true})
+	require.NoError(t, err)
+
+	primary, err := s.dbList.GetByName("primarydb")
+	require.NoError(t, err)
+
+	replica, err := s.dbList.GetByName("replicadb")
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		_, err = primary.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: bytes.Repeat([]byte{byte(i)}, 256)}}})
+		require.NoError(t, err)
+	}
+
+	primaryState, err := primary.CurrentState()
+	require.NoError(t, err)
+
+	var snapshot bytes.Buffer
+
+	err = writeSnapshot(primary, &snapshot, 0, primaryState.TxId)
+	require.NoError(t, err)
+
+	replicaState, err := replica.CurrentState()
+	require.NoError(t, err)
+	require.Zero(t, replicaState.TxId)
+
+	// the sync gets interrupted in the middle of the snapshot
+	lastTx, err := applySnapshot(replica, bytes.NewReader(snapshot.Bytes()[:snapshot.Len()/2]), replicaState)
+	require.Error(t, err)
+	require.Less(t, lastTx, primaryState.TxId)
+
+	replicaState, err = replica.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, lastTx, replicaState.TxId)
+
+	// a snapshot not following the state of the replica is rejected
+	snapshot.Reset()
+
+	err = writeSnapshot(primary, &snapshot, lastTx+1, primaryState.TxId)
+	require.NoError(t, err)
+
+	_, err = applySnapshot(replica, &snapshot, replicaState)
+	require.Equal(t, ErrSnapshotDiverged, err)
+
+	// the sync is resumed from the last committed transaction
+	snapshot.Reset()
+
+	err = writeSnapshot(primary, &snapshot, replicaState.TxId, primaryState.TxId)
+	require.NoError(t, err)
+
+	lastTx, err = applySnapshot(replica, &snapshot, replicaState)
+	require.NoError(t, err)
+	require.Equal(t, primaryState.TxId, lastTx)
+
+	replicaState, err = replica.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, primaryState.TxHash, replicaState.TxHash)
+
+	// nothing left to sync
+	snapshot.Reset()
+
+	err = writeSnapshot(primary, &snapshot, replicaState.TxId, primaryState.TxId)
+	require.NoError(t, err)
+
+	lastTx, err = applySnapshot(replica, &snapshot, replicaState)
+	require.NoError(t, err)
+	require.Equal(t, primaryState.TxId, lastTx)
+}
+
+func TestSyncSnapshot(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	primaryPort := l.Addr().(*net.TCPAddr).Port
+
+	primaryOptions := DefaultOptions().
+		WithDir("data_snapshot_primary").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithTLS(nil).
+		WithListener(l)
+
+	primary := DefaultServer().WithOptions(primaryOptions).(*ImmuServer)
+	defer os.RemoveAll(primary.Options.Dir)
+
+	err = primary.Initialize()
+	require.NoError(t, err)
+	defer primary.CloseDatabases()
+
+	go primary.GrpcServer.Serve(l)
+	defer primary.GrpcServer.Stop()
+
+	lr, err := primary.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	primaryCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	err = primary.ExportSnapshot(nil, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = primary.ExportSnapshot(&schema.SnapshotRequest{SinceTx: 1000}, &exportTxServer{ctx: primaryCtx})
+	require.Equal(t, ErrIllegalArguments, err)
+
+	for i := 0; i < 10; i++ {
+		_, err = primary.Set(primaryCtx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+		require.NoError(t, err)
+	}
+
+	primaryState, err := primary.CurrentState(primaryCtx, nil)
+	require.NoError(t, err)
+
+	replicaOptions := DefaultOptions().
+		WithDir("data_snapshot_replica").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+
+	s := DefaultServer().WithOptions(replicaOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err = s.Initialize()
+	require.NoError(t, err)
+	defer s.CloseDatabases()
+
+	_, err = s.SyncSnapshot(context.Background(), nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	lr, err = s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{DatabaseName: "primarydb"})
+	require.NoError(t, err)
+
+	_, err = s.SyncSnapshot(ctx, &schema.SyncSnapshotRequest{DatabaseName: "primarydb"})
+	require.Equal(t, ErrSnapshotSourceRequired, err)
+
+	_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{
+		DatabaseName: "replicadb",
+		Replica:      true,
+		SrcDatabase:  DefaultdbName,
+		SrcAddress:   "127.0.0.1",
+		SrcPort:      uint32(primaryPort),
+		FollowerUsr:  auth.SysAdminUsername,
+		FollowerPwd:  auth.SysAdminPassword,
+	})
+	require.NoError(t, err)
+
+	res, err := s.SyncSnapshot(ctx, &schema.SyncSnapshotRequest{DatabaseName: "replicadb", UntilTx: 5})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), res.FromTxId)
+	require.Equal(t, uint64(5), res.TxId)
+	require.Equal(t, uint64(5), res.TxCount)
+	require.NotZero(t, res.TransferredBytes)
+
+	res, err = s.SyncSnapshot(ctx, &schema.SyncSnapshotRequest{DatabaseName: "replicadb"})
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), res.FromTxId)
+	require.Equal(t, primaryState.TxId, res.TxId)
+
+	replica, err := s.dbList.GetByName("replicadb")
+	require.NoError(t, err)
+
+	replicaState, err := replica.CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, primaryState.TxHash, replicaState.TxHash)
+
+	res, err = s.SyncSnapshot(ctx, &schema.SyncSnapshotRequest{DatabaseName: "replicadb"})
+	require.NoError(t, err)
+	require.Zero(t, res.TxCount)
+
+	list, err := s.ReplicationAudit(ctx, &schema.ReplicationAuditRequest{Database: "replicadb", Desc: true})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 3)
+	require.Equal(t, ReplicationAuditResynced, list.Entries[0].Kind)
+	require.Equal(t, primaryState.TxId, list.Entries[0].TxId)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// replicationMethods are the only methods served through the replication listener,
+// the ones needed by replicas to fetch transactions from their primary and to verify them
+var replicationMethods = map[string]struct{}{
+	"Login":              {},
+	"Logout":             {},
+	"UseDatabase":        {},
+	"CurrentState":       {},
+	"VerifiableTxById":   {},
+	"ExportTx":           {},
+	"exportSnapshot":     {},
+	"ConfirmReplication": {},
+	"ReplicationStatus":  {},
+}
+
+func isReplicationMethod(fullMethod string) bool {
+	_, ok := replicationMethods[fullMethod[strings.LastIndex(fullMethod, "/")+1:]]
+	return ok
+}
+
+func replicationUnaryFilter(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !isReplicationMethod(info.FullMethod) {
+		return nil, ErrNotReplicationMethod
+	}
+	return handler(ctx, req)
+}
+
+func replicationStreamFilter(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !isReplicationMethod(info.FullMethod) {
+		return ErrNotReplicationMethod
+	}
+	return handler(srv, ss)
+}
+
+// setUpReplicationServer prepares the listener dedicated to replication, separated from the client-facing one
+// and only accepting connections authenticated through client certificates
+func (s *ImmuServer) setUpReplicationServer(uis []grpc.UnaryServerInterceptor, sss []grpc.StreamServerInterceptor) error {
+	tlsConfig := s.Options.ReplicationTLSConfig
+	if tlsConfig == nil || len(tlsConfig.Certificates) == 0 || tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert {
+		return ErrReplicationTLSRequired
+	}
+
+	listener, err := net.Listen(s.Options.Network, fmt.Sprintf("%s:%d", s.Options.Address, s.Options.ReplicationPort))
+	if err != nil {
+		return err
+	}
+
+	s.replicationListener = listener
+
+	s.ReplicationGrpcServer = grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(append([]grpc.UnaryServerInterceptor{replicationUnaryFilter}, uis...)...)),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(append([]grpc.StreamServerInterceptor{replicationStreamFilter}, sss...)...)),
+		grpc.MaxRecvMsgSize(s.Options.MaxRecvMsgSize),
+	)
+	schema.RegisterImmuServiceServer(s.ReplicationGrpcServer, s)
+
+	return nil
+}
+
+// replicationClientTLSConfig returns the TLS configuration used by a replica to connect to its source database,
+// nil when replication goes in the clear
+func replicationClientTLSConfig(rOpts *database.ReplicationOptions) (*tls.Config, error) {
+	if rOpts == nil || (rOpts.TLSCA == "" && rOpts.TLSCert == "") {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{ServerName: rOpts.TLSServerName}
+
+	if rOpts.TLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(rOpts.TLSCert, rOpts.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read replication certificate or private key: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	// the host's root CA set is used when no CA is provided
+	if rOpts.TLSCA != "" {
+		bs, err := ioutil.ReadFile(rOpts.TLSCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read replication CA certificate: %v", err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(bs) {
+			return nil, fmt.Errorf("failed to append replication CA certificate")
+		}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCertificate(t *testing.T, template *x509.Certificate, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	signerCert, signerKey := template, key
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func TestReplicationTLS(t *testing.T) {
+	certsDir, err := ioutil.TempDir("", "replication_tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(certsDir)
+
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := time.Now().Add(time.Hour)
+
+	ca := newTestCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "replication-ca"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil)
+
+	primaryCert := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "primary"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}, ca)
+
+	replicaCert := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "replica"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+
+	writeFile := func(name string, content []byte) string {
+		path := filepath.Join(certsDir, name)
+		require.NoError(t, ioutil.WriteFile(path, content, 0600))
+		return path
+	}
+
+	caFile := writeFile("ca.pem", ca.certPEM)
+	replicaCertFile := writeFile("replica.pem", replicaCert.certPEM)
+	replicaKeyFile := writeFile("replica.key", replicaCert.keyPEM)
+
+	primaryKeyPair, err := tls.X509KeyPair(primaryCert.certPEM, primaryCert.keyPEM)
+	require.NoError(t, err)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	// a free port for the replication listener
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	replicationPort := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	options := DefaultOptions().
+		WithDir("data_replication_tls").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithAddress("127.0.0.1").
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithReplicationPort(replicationPort)
+	defer os.RemoveAll(options.Dir)
+
+	t.Run("the replication port requires client certificates", func(t *testing.T) {
+		s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+		err := s.Initialize()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), ErrReplicationTLSRequired.Error())
+		s.CloseDatabases()
+	})
+
+	options.WithReplicationTLSConfig(&tls.Config{
+		Certificates: []tls.Certificate{primaryKeyPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	})
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err = s.Initialize()
+	require.NoError(t, err)
+	defer s.CloseDatabases()
+
+	go s.ReplicationGrpcServer.Serve(s.replicationListener)
+	defer s.ReplicationGrpcServer.Stop()
+
+	rOpts := (&database.ReplicationOptions{}).
+		WithTLSCert(replicaCertFile).
+		WithTLSKey(replicaKeyFile).
+		WithTLSCA(caFile)
+
+	tlsConfig, err := replicationClientTLSConfig(rOpts)
+	require.NoError(t, err)
+	require.Len(t, tlsConfig.Certificates, 1)
+
+	t.Run("replicas are authenticated through their certificate", func(t *testing.T) {
+		primary, _, err := dialRemoteDatabase("127.0.0.1", replicationPort, DefaultdbName, auth.SysAdminUsername, auth.SysAdminPassword, tlsConfig)
+		require.NoError(t, err)
+		defer primary.conn.Close()
+
+		state, err := primary.CurrentState()
+		require.NoError(t, err)
+		require.Equal(t, DefaultdbName, state.Db)
+
+		_, err = primary.client.Set(primary.ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("connections without client certificate are refused", func(t *testing.T) {
+		_, err := replicationClientTLSConfig((&database.ReplicationOptions{}).WithTLSCert(replicaCertFile).WithTLSKey(caFile))
+		require.Error(t, err)
+
+		noCertConfig, err := replicationClientTLSConfig((&database.ReplicationOptions{}).WithTLSCA(caFile))
+		require.NoError(t, err)
+		require.Empty(t, noCertConfig.Certificates)
+
+		_, _, err = dialRemoteDatabase("127.0.0.1", replicationPort, DefaultdbName, auth.SysAdminUsername, auth.SysAdminPassword, noCertConfig)
+		require.Error(t, err)
+	})
+
+	t.Run("plaintext connections are refused", func(t *testing.T) {
+		_, _, err := dialRemoteDatabase("127.0.0.1", replicationPort, DefaultdbName, auth.SysAdminUsername, auth.SysAdminPassword, nil)
+		require.Error(t, err)
+	})
+}
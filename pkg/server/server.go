@@ -62,6 +62,18 @@ const (
 	KeyPrefixUser = iota + 1
 	//KeyPrefixDBSettings is used for entries related to database settings
 	KeyPrefixDBSettings
+	//KeyPrefixTimestampToken is used for the time-stamp tokens of the database roots
+	KeyPrefixTimestampToken
+	//KeyPrefixAnchorReceipt is used for the receipts of the database roots published to an external ledger
+	KeyPrefixAnchorReceipt
+	//KeyPrefixRepairAudit is used for the audit entries of the repairs made by scrubbing damaged databases
+	KeyPrefixRepairAudit
+	//KeyPrefixCorruptionCheckpoint is used for the last transaction verified by the corruption checker in each database
+	KeyPrefixCorruptionCheckpoint
+	//KeyPrefixQuarantine is used for the quarantine state of the databases where corruption was detected
+	KeyPrefixQuarantine
+	//KeyPrefixReplicationAudit is used for the audit trail of the replication changes of each database
+	KeyPrefixReplicationAudit
 )
 
 var startedAt time.Time
@@ -92,6 +104,20 @@ func (s *ImmuServer) Initialize() error {
 		return ErrEmptyAdminPassword
 	}
 
+	switch s.Options.CorruptionPolicy {
+	case "", CorruptionPolicyAlert, CorruptionPolicyQuarantine:
+	default:
+		s.Logger.Errorf("%v: '%s'", ErrInvalidCorruptionPolicy, s.Options.CorruptionPolicy)
+		return ErrInvalidCorruptionPolicy
+	}
+
+	if s.Options.ClusterOptions != nil {
+		err = validateClusterOptions(s.Options.ClusterOptions)
+		if err != nil {
+			return logErr(s.Logger, "%v", err)
+		}
+	}
+
 	dataDir := s.Options.Dir
 	err = os.MkdirAll(dataDir, s.Options.StoreOptions.FileMode)
 	if err != nil {
@@ -109,6 +135,21 @@ func (s *ImmuServer) Initialize() error {
 		return logErr(s.Logger, "Unable to initialize remote storage: %v", err)
 	}
 
+	s.backupStorage, err = s.createBackupStorageInstance()
+	if err != nil {
+		return logErr(s.Logger, "Unable to open backup storage: %v", err)
+	}
+
+	s.backupKeys, err = s.createBackupKeyProvider()
+	if err != nil {
+		return logErr(s.Logger, "Unable to load backup encryption key: %v", err)
+	}
+
+	s.backupScheduler, err = newBackupScheduler(s.Options.BackupSchedules)
+	if err != nil {
+		return logErr(s.Logger, "Unable to initialize backup scheduler: %v", err)
+	}
+
 	if err = s.loadSystemDatabase(dataDir, remoteStorage, adminPassword); err != nil {
 		return logErr(s.Logger, "Unable to load system database: %v", err)
 	}
@@ -137,6 +178,10 @@ func (s *ImmuServer) Initialize() error {
 		return ErrAuthMustBeEnabled
 	}
 
+	if err = s.initPlugins(); err != nil {
+		return logErr(s.Logger, "%v", err)
+	}
+
 	grpcSrvOpts := []grpc.ServerOption{}
 	if s.Options.TLSConfig != nil {
 		grpcSrvOpts = []grpc.ServerOption{grpc.Creds(credentials.NewTLS(s.Options.TLSConfig))}
@@ -209,6 +254,16 @@ func (s *ImmuServer) Initialize() error {
 		grpc_prometheus.StreamServerInterceptor,
 		auth.ServerStreamInterceptor,
 	}
+
+	if s.Options.ClusterOptions != nil {
+		uis = append(uis, s.ClusterRedirector)
+		sss = append(sss, s.ClusterRedirectorStream)
+	}
+
+	pluginsUIs, pluginsSSs := s.pluginsInterceptors()
+	uis = append(uis, pluginsUIs...)
+	sss = append(sss, pluginsSSs...)
+
 	grpcSrvOpts = append(
 		grpcSrvOpts,
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(uis...)),
@@ -218,8 +273,15 @@ func (s *ImmuServer) Initialize() error {
 
 	s.GrpcServer = grpc.NewServer(grpcSrvOpts...)
 	schema.RegisterImmuServiceServer(s.GrpcServer, s)
+	s.registerPluginsServices()
 	grpc_prometheus.Register(s.GrpcServer)
 
+	if s.Options.ReplicationPort > 0 {
+		if err = s.setUpReplicationServer(uis, sss); err != nil {
+			return logErr(s.Logger, "Unable to set up the replication server: %v", err)
+		}
+	}
+
 	s.PgsqlSrv = pgsqlsrv.New(pgsqlsrv.Address(s.Options.Address), pgsqlsrv.Port(s.Options.PgsqlServerPort), pgsqlsrv.DatabaseList(s.dbList), pgsqlsrv.SysDb(s.sysDB), pgsqlsrv.TlsConfig(s.Options.TLSConfig), pgsqlsrv.Logger(s.Logger))
 	if s.Options.PgsqlServer {
 		if err = s.PgsqlSrv.Initialize(); err != nil {
@@ -251,6 +313,18 @@ func (s *ImmuServer) Start() (err error) {
 
 	s.installShutdownHandler()
 
+	if err := s.startCluster(); err != nil {
+		return logErr(s.Logger, "Unable to start the cluster: %v", err)
+	}
+
+	// plugins are started before serving, no request gets served should any of them fail
+	if err := s.startPlugins(); err != nil {
+		s.stopCluster()
+		s.mux.Unlock()
+		s.pgsqlMux.Unlock()
+		return logErr(s.Logger, "Unable to start plugins: %v", err)
+	}
+
 	go func() {
 		if err := s.GrpcServer.Serve(s.listener); err != nil {
 			s.mux.Unlock()
@@ -258,6 +332,15 @@ func (s *ImmuServer) Start() (err error) {
 		}
 	}()
 
+	if s.ReplicationGrpcServer != nil {
+		go func() {
+			s.Logger.Infof("Replication server is running at port %d", s.Options.ReplicationPort)
+			if err := s.ReplicationGrpcServer.Serve(s.replicationListener); err != nil {
+				s.Logger.Errorf("Replication server stopped: %v", err)
+			}
+		}()
+	}
+
 	if s.Options.PgsqlServer {
 		go func() {
 			s.Logger.Infof("pgsl server is running at port %d", s.Options.PgsqlServerPort)
@@ -281,6 +364,28 @@ func (s *ImmuServer) Start() (err error) {
 
 	go s.printUsageCallToAction()
 
+	s.startDatabasesWarmUp()
+
+	s.startBackupScheduler()
+
+	s.startExpirationSweeper()
+
+	s.startTimestampAnchoring()
+
+	s.startLedgerAnchoring()
+
+	s.startCorruptionChecker()
+
+	s.startIndexVerifier()
+
+	s.startReplicaVerifier()
+
+	s.startFailoverSupervisor()
+
+	s.startReplicationHooks()
+
+	s.notifySystemdReady()
+
 	s.mux.Unlock()
 	s.pgsqlMux.Unlock()
 	<-s.quit
@@ -306,6 +411,8 @@ func (s *ImmuServer) setupPidFile() error {
 }
 
 func (s *ImmuServer) setUpMetricsServer() error {
+	Metrics.WithComputeDBQuotaUsage(s.metricFuncComputeDBQuotaUsage)
+
 	s.metricsServer = StartMetrics(
 		1*time.Minute,
 		s.Options.MetricsBind(),
@@ -318,10 +425,18 @@ func (s *ImmuServer) setUpMetricsServer() error {
 }
 
 func (s *ImmuServer) setUpWebServer() error {
+	var grpcWeb *GrpcWebHandler
+	if s.Options.GrpcWeb {
+		grpcWeb = NewGrpcWebHandler(s.GrpcServer, s.Options.GrpcWebAllowedOrigins)
+	}
+
 	server, err := StartWebServer(
 		s.Options.WebBind(),
 		s.Options.TLSConfig,
 		s,
+		grpcWeb,
+		s.Options.SwaggerUI,
+		s.Options.SwaggerUIAssetsDir,
 		s.Logger,
 	)
 	if err != nil {
@@ -380,7 +495,13 @@ func (s *ImmuServer) loadSystemDatabase(dataDir string, remoteStorage remotestor
 			return err
 		}
 
-		return nil
+		// users must be readable as soon as the server gets initialized
+		state, err := s.sysDB.CurrentState()
+		if err != nil {
+			return err
+		}
+
+		return s.sysDB.WaitForIndexingUpto(state.TxId, nil)
 	}
 
 	if !s.OS.IsNotExist(err) {
@@ -423,6 +544,8 @@ func (s *ImmuServer) loadDefaultDatabase(dataDir string, remoteStorage remotesto
 		WithDbRootPath(dataDir).
 		WithStoreOptions(s.storeOptionsForDb(s.Options.GetDefaultDbName(), remoteStorage))
 
+	op.GetReplicationOptions().AsReplica(s.Options.ClusterOptions != nil)
+
 	_, err := s.OS.Stat(defaultDbRootDir)
 	if err == nil {
 		db, err := database.OpenDb(op, s.sysDB, s.Logger)
@@ -439,6 +562,11 @@ func (s *ImmuServer) loadDefaultDatabase(dataDir string, remoteStorage remotesto
 			return err
 		}
 
+		err = s.restoreQuarantine(db)
+		if err != nil {
+			return err
+		}
+
 		s.dbList.Append(db)
 	}
 
@@ -446,7 +574,8 @@ func (s *ImmuServer) loadDefaultDatabase(dataDir string, remoteStorage remotesto
 		return err
 	}
 
-	op.GetReplicationOptions().AsReplica(s.Options.GetMaintenance())
+	// databases of a cluster node are written only through the cluster log
+	op.GetReplicationOptions().AsReplica(s.Options.GetMaintenance() || s.Options.ClusterOptions != nil)
 	db, err := database.NewDb(op, s.sysDB, s.Logger)
 	if err != nil {
 		return err
@@ -469,7 +598,10 @@ func (s *ImmuServer) loadUserDatabases(dataDir string, remoteStorage remotestora
 	for _, f := range files {
 		if !f.IsDir() ||
 			f.Name() == s.Options.GetSystemAdminDbName() ||
-			f.Name() == s.Options.GetDefaultDbName() {
+			f.Name() == s.Options.GetDefaultDbName() ||
+			f.Name() == archiveDirName ||
+			f.Name() == backupDirName ||
+			f.Name() == clusterDirName {
 			continue
 		}
 
@@ -501,26 +633,40 @@ func (s *ImmuServer) loadUserDatabases(dataDir string, remoteStorage remotestora
 			}
 		}
 
-		replicationOpts := &database.ReplicationOptions{
-			Replica:     settings.Replica,
-			SrcDatabase: settings.Database,
-			SrcAddress:  settings.SrcAddress,
-			SrcPort:     settings.SrcPort,
-			FollowerUsr: settings.FollowerUsr,
-			FollowerPwd: settings.FollowerPwd,
-		}
+		replicationOpts := settings.replicationOptions()
+		replicationOpts.Replica = replicationOpts.Replica || s.Options.ClusterOptions != nil
 
 		op := database.DefaultOption().
 			WithDbName(dbname).
 			WithDbRootPath(dataDir).
-			WithStoreOptions(s.storeOptionsForDb(dbname, remoteStorage)).
-			WithReplicationOptions(replicationOpts)
+			WithStoreOptions(settings.StoreSettings.apply(s.storeOptionsForDb(dbname, remoteStorage))).
+			WithReplicationOptions(replicationOpts).
+			WithValueTransformers(settings.ValueTransformers).
+			WithRetainOriginalDigest(settings.RetainOriginalDigest).
+			WithValueCompression(settings.ValueCompression).
+			WithCorruptionChecker(settings.CorruptionChecker).
+			WithMaxDiskSize(settings.MaxDiskSize).
+			WithReadOnly(settings.ReadOnly).
+			WithParanoidReads(settings.ParanoidReads).
+			WithQuotaWarningThreshold(s.Options.QuotaWarningThld).
+			WithSQLStmtCacheSize(s.Options.SQLStmtCacheSize)
+
+		// databases must be opened to join the cluster
+		if s.Options.LazyDatabaseLoading && s.Options.ClusterOptions == nil {
+			s.dbList.Register(dbname, s.databaseOpener(op))
+			continue
+		}
 
 		db, err := database.OpenDb(op, s.sysDB, s.Logger)
 		if err != nil {
 			return fmt.Errorf("could not open database '%s': %w", dbname, err)
 		}
 
+		err = s.restoreQuarantine(db)
+		if err != nil {
+			return err
+		}
+
 		s.dbList.Append(db)
 	}
 
@@ -534,8 +680,39 @@ func (s *ImmuServer) Stop() error {
 
 	s.Logger.Infof("Stopping immudb:\n%v", s.Options)
 
+	s.notifySystemdStopping()
+
+	s.stopPlugins()
+
+	s.stopDatabasesWarmUp()
+
+	s.stopBackupScheduler()
+
+	s.stopExpirationSweeper()
+
+	s.stopTimestampAnchoring()
+
+	s.stopLedgerAnchoring()
+
+	s.stopCorruptionChecker()
+
+	s.stopIndexVerifier()
+
+	s.stopReplicaVerifier()
+
+	s.stopFailoverSupervisor()
+
+	s.stopReplicationHooks()
+
+	s.stopCluster()
+
 	defer func() { s.quit <- struct{}{} }()
 
+	if s.ReplicationGrpcServer != nil {
+		s.ReplicationGrpcServer.Stop()
+		s.ReplicationGrpcServer = nil
+	}
+
 	if !s.Options.usingCustomListener {
 		s.GrpcServer.Stop()
 		defer func() { s.GrpcServer = nil }()
@@ -548,6 +725,9 @@ func (s *ImmuServer) Stop() error {
 func (s *ImmuServer) CloseDatabases() error {
 	for i := 0; i < s.dbList.Length(); i++ {
 		val := s.dbList.GetByIndex(int64(i))
+		if val == nil {
+			continue
+		}
 		val.Close()
 	}
 
@@ -609,7 +789,13 @@ func (s *ImmuServer) UpdateMTLSConfig(ctx context.Context, req *schema.MTLSConfi
 
 // Health ...
 func (s *ImmuServer) Health(ctx context.Context, _ *empty.Empty) (*schema.HealthResponse, error) {
-	return &schema.HealthResponse{Status: true, Version: fmt.Sprintf("%s", Version.Version)}, nil
+	degraded := s.degradedDatabases()
+
+	return &schema.HealthResponse{
+		Status:            len(degraded) == 0,
+		Version:           fmt.Sprintf("%s", Version.Version),
+		DegradedDatabases: degraded,
+	}, nil
 }
 
 func (s *ImmuServer) installShutdownHandler() {
@@ -678,16 +864,29 @@ func (s *ImmuServer) CreateDatabaseWith(ctx context.Context, req *schema.Databas
 		return nil, fmt.Errorf("database %s already exists", req.GetDatabaseName())
 	}
 
+	err = database.ValidateValueTransformers(req.ValueTransformers)
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.ValidateValueCompression(req.ValueCompression)
+	if err != nil {
+		return nil, err
+	}
+
 	settings := &dbSettings{
-		Database:    req.DatabaseName,
-		Replica:     req.Replica,
-		SrcDatabase: req.SrcDatabase,
-		SrcAddress:  req.SrcAddress,
-		SrcPort:     int(req.SrcPort),
-		FollowerUsr: req.FollowerUsr,
-		FollowerPwd: req.FollowerPwd,
-		CreatedBy:   user.Username,
-		CreatedAt:   time.Now(),
+		Database:             req.DatabaseName,
+		Replica:              req.Replica,
+		SrcDatabase:          req.SrcDatabase,
+		SrcAddress:           req.SrcAddress,
+		SrcPort:              int(req.SrcPort),
+		FollowerUsr:          req.FollowerUsr,
+		FollowerPwd:          req.FollowerPwd,
+		ValueTransformers:    req.ValueTransformers,
+		RetainOriginalDigest: req.RetainOriginalDigest,
+		ValueCompression:     req.ValueCompression,
+		CreatedBy:            user.Username,
+		CreatedAt:            time.Now(),
 	}
 
 	err = s.saveSettings(settings)
@@ -695,14 +894,8 @@ func (s *ImmuServer) CreateDatabaseWith(ctx context.Context, req *schema.Databas
 		return nil, err
 	}
 
-	replicationOpts := &database.ReplicationOptions{
-		Replica:     settings.Replica,
-		SrcDatabase: settings.Database,
-		SrcAddress:  settings.SrcAddress,
-		SrcPort:     settings.SrcPort,
-		FollowerUsr: settings.FollowerUsr,
-		FollowerPwd: settings.FollowerPwd,
-	}
+	replicationOpts := settings.replicationOptions()
+	replicationOpts.Replica = replicationOpts.Replica || s.cluster != nil
 
 	dataDir := s.Options.Dir
 
@@ -710,7 +903,12 @@ func (s *ImmuServer) CreateDatabaseWith(ctx context.Context, req *schema.Databas
 		WithDbName(req.DatabaseName).
 		WithDbRootPath(dataDir).
 		WithStoreOptions(s.storeOptionsForDb(req.DatabaseName, s.remoteStorage)).
-		WithReplicationOptions(replicationOpts)
+		WithReplicationOptions(replicationOpts).
+		WithValueTransformers(settings.ValueTransformers).
+		WithRetainOriginalDigest(settings.RetainOriginalDigest).
+		WithValueCompression(settings.ValueCompression).
+		WithQuotaWarningThreshold(s.Options.QuotaWarningThld).
+		WithSQLStmtCacheSize(s.Options.SQLStmtCacheSize)
 
 	db, err := database.NewDb(op, s.sysDB, s.Logger)
 	if err != nil {
@@ -720,6 +918,18 @@ func (s *ImmuServer) CreateDatabaseWith(ctx context.Context, req *schema.Databas
 	s.dbList.Append(db)
 	s.multidbmode = true
 
+	if settings.Replica {
+		s.auditReplication(req.DatabaseName, ReplicationAuditTopologyChanged, user.Username, 0,
+			fmt.Sprintf("created as %s", replicationTopology(settings)))
+	}
+
+	if s.cluster != nil {
+		err = s.cluster.join(db)
+		if err != nil {
+			return nil, fmt.Errorf("database '%s' unable to join the cluster: %w", req.DatabaseName, err)
+		}
+	}
+
 	return &empty.Empty{}, nil
 }
 
@@ -764,6 +974,8 @@ func (s *ImmuServer) UpdateDatabase(ctx context.Context, req *schema.DatabaseSet
 		return nil, ErrEmptyAdminPassword
 	}
 
+	previous := *settings
+
 	settings.Replica = req.Replica
 	settings.SrcDatabase = req.SrcDatabase
 	settings.SrcAddress = req.SrcAddress
@@ -778,14 +990,100 @@ func (s *ImmuServer) UpdateDatabase(ctx context.Context, req *schema.DatabaseSet
 		return nil, err
 	}
 
-	db.UpdateReplicationOptions(&database.ReplicationOptions{
-		Replica:     settings.Replica,
-		SrcDatabase: settings.Database,
-		SrcAddress:  settings.SrcAddress,
-		SrcPort:     settings.SrcPort,
-		FollowerUsr: settings.FollowerUsr,
-		FollowerPwd: settings.FollowerPwd,
-	})
+	s.updateReplicationOptions(db, settings.replicationOptions())
+
+	s.auditReplicationChange(&previous, settings, user.Username, 0)
+
+	return &empty.Empty{}, nil
+}
+
+// DeleteDatabase unloads a database, then removes or archives its directory and revokes the permissions granted on it
+func (s *ImmuServer) DeleteDatabase(ctx context.Context, req *schema.DeleteDatabaseRequest) (*empty.Empty, error) {
+	s.Logger.Debugf("deletedatabase")
+
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	if !s.Options.GetAuth() {
+		return nil, ErrAuthMustBeEnabled
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+
+	if !user.IsSysAdmin {
+		return nil, fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+
+	if req.DatabaseName == SystemdbName || req.DatabaseName == s.Options.defaultDbName {
+		return nil, ErrReservedDatabase
+	}
+
+	// remotely stored databases would be restored from the remote storage on next startup
+	if s.remoteStorage != nil {
+		return nil, ErrNotSupported
+	}
+
+	db, err := s.dbList.Delete(req.DatabaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cluster != nil {
+		s.cluster.leave(req.DatabaseName)
+
+		err = s.OS.RemoveAll(s.OS.Join(s.Options.Dir, clusterDirName, req.DatabaseName))
+		if err != nil {
+			s.Logger.Warningf("Error removing the cluster state of database '%s': %v", req.DatabaseName, err)
+		}
+	}
+
+	// databases registered for lazy loading may not be opened yet
+	if db != nil {
+		err = db.Close()
+		if err != nil {
+			s.Logger.Warningf("Error closing database '%s': %v", req.DatabaseName, err)
+		}
+	}
+
+	dbDir := s.OS.Join(s.Options.Dir, req.DatabaseName)
+
+	if req.Archive {
+		archiveDir := s.OS.Join(s.Options.Dir, archiveDirName)
+
+		err = s.OS.MkdirAll(archiveDir, 0755)
+		if err != nil {
+			return nil, err
+		}
+
+		archivedDbDir := s.OS.Join(archiveDir, fmt.Sprintf("%s_%d", req.DatabaseName, time.Now().Unix()))
+
+		err = s.OS.Rename(dbDir, archivedDbDir)
+		if err != nil {
+			return nil, err
+		}
+
+		s.Logger.Infof("Database '%s' archived into '%s'", req.DatabaseName, archivedDbDir)
+	} else {
+		err = s.OS.RemoveAll(dbDir)
+		if err != nil {
+			return nil, err
+		}
+
+		s.Logger.Infof("Database '%s' deleted", req.DatabaseName)
+	}
+
+	err = s.revokeDatabasePermissions(req.DatabaseName)
+	if err != nil {
+		return nil, err
+	}
 
 	return &empty.Empty{}, nil
 }
@@ -810,6 +1108,9 @@ func (s *ImmuServer) DatabaseList(ctx context.Context, _ *empty.Empty) (*schema.
 	if loggedInuser.IsSysAdmin || s.Options.GetMaintenance() {
 		for i := 0; i < s.dbList.Length(); i++ {
 			val := s.dbList.GetByIndex(int64(i))
+			if val == nil {
+				continue
+			}
 			if val.GetOptions().GetDbName() == SystemdbName {
 				//do not put sysemdb in the list
 				continue
@@ -819,6 +1120,10 @@ func (s *ImmuServer) DatabaseList(ctx context.Context, _ *empty.Empty) (*schema.
 			}
 			dbList.Databases = append(dbList.Databases, db)
 		}
+
+		for _, dbname := range s.dbList.Unloaded() {
+			dbList.Databases = append(dbList.Databases, &schema.Database{DatabaseName: dbname})
+		}
 	} else {
 		for _, val := range loggedInuser.Permissions {
 			db := &schema.Database{
@@ -875,7 +1180,8 @@ func (s *ImmuServer) UseDatabase(ctx context.Context, req *schema.Database) (*sc
 	if (!user.IsSysAdmin) &&
 		(!user.HasPermission(req.DatabaseName, auth.PermissionAdmin)) &&
 		(!user.HasPermission(req.DatabaseName, auth.PermissionR)) &&
-		(!user.HasPermission(req.DatabaseName, auth.PermissionRW)) {
+		(!user.HasPermission(req.DatabaseName, auth.PermissionRW)) &&
+		(!user.HasPermission(req.DatabaseName, auth.PermissionReplication)) {
 
 		return nil, status.Errorf(codes.PermissionDenied, "Logged in user does not have permission on this database")
 	}
@@ -930,11 +1236,16 @@ func (s *ImmuServer) getDBFromCtx(ctx context.Context, methodName string) (datab
 		db = s.dbList.GetByIndex(ind)
 	}
 
+	// the selected database may have been deleted
+	if db == nil {
+		return nil, database.ErrDatabaseNotExists
+	}
+
 	if usr.IsSysAdmin {
 		return db, nil
 	}
 
-	if ok := auth.HasPermissionForMethod(usr.WhichPermission(s.dbList.GetByIndex(ind).GetOptions().GetDbName()), methodName); !ok {
+	if ok := auth.HasPermissionForMethod(usr.WhichPermission(db.GetOptions().GetDbName()), methodName); !ok {
 		return nil, ErrPermissionDenied
 	}
 
@@ -942,17 +1253,42 @@ func (s *ImmuServer) getDBFromCtx(ctx context.Context, methodName string) (datab
 }
 
 type dbSettings struct {
-	Database    string    `json:"database"`
-	Replica     bool      `json:"replica"`
-	SrcDatabase string    `json:"srcDatabase"`
-	SrcAddress  string    `json:"srcAddress"`
-	SrcPort     int       `json:"srcPort"`
-	FollowerUsr string    `json:"followerUsr"`
-	FollowerPwd string    `json:"followerPwd"`
-	CreatedBy   string    `json:"createdBy"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedBy   string    `json:"updatedBy"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	Database               string              `json:"database"`
+	Replica                bool                `json:"replica"`
+	SrcDatabase            string              `json:"srcDatabase"`
+	SrcAddress             string              `json:"srcAddress"`
+	SrcPort                int                 `json:"srcPort"`
+	FollowerUsr            string              `json:"followerUsr"`
+	FollowerPwd            string              `json:"followerPwd"`
+	SyncAcks               int                 `json:"syncAcks,omitempty"`
+	SyncTimeout            int                 `json:"syncTimeout,omitempty"`
+	SyncFallback           string              `json:"syncFallback,omitempty"`
+	MaxReplicaTxRate       int                 `json:"maxReplicaTxRate,omitempty"`
+	MaxReplicaByteRate     int                 `json:"maxReplicaByteRate,omitempty"`
+	MaxReplicationTxRate   int                 `json:"maxReplicationTxRate,omitempty"`
+	MaxReplicationByteRate int                 `json:"maxReplicationByteRate,omitempty"`
+	ReplicaFilters         map[string][][]byte `json:"replicaFilters,omitempty"`
+	Partial                bool                `json:"partial,omitempty"`
+	TLSCert                string              `json:"tlsCert,omitempty"`
+	TLSKey                 string              `json:"tlsKey,omitempty"`
+	TLSCA                  string              `json:"tlsCA,omitempty"`
+	TLSServerName          string              `json:"tlsServerName,omitempty"`
+	AutoFailover           bool                `json:"autoFailover,omitempty"`
+	PromotedAt             time.Time           `json:"promotedAt,omitempty"`
+	PromotedFrom           string              `json:"promotedFrom,omitempty"`
+	ReplicationEpoch       uint64              `json:"replicationEpoch,omitempty"`
+	ValueTransformers      []string            `json:"valueTransformers,omitempty"`
+	RetainOriginalDigest   bool                `json:"retainOriginalDigest,omitempty"`
+	ValueCompression       string              `json:"valueCompression,omitempty"`
+	CorruptionChecker      bool                `json:"corruptionChecker,omitempty"`
+	MaxDiskSize            int64               `json:"maxDiskSize,omitempty"`
+	ReadOnly               bool                `json:"readOnly,omitempty"`
+	ParanoidReads          bool                `json:"paranoidReads,omitempty"`
+	StoreSettings          *dbStoreSettings    `json:"storeSettings,omitempty"`
+	CreatedBy              string              `json:"createdBy"`
+	CreatedAt              time.Time           `json:"createdAt"`
+	UpdatedBy              string              `json:"updatedBy"`
+	UpdatedAt              time.Time           `json:"updatedAt"`
 }
 
 func (s *ImmuServer) loadSettings(database string) (*dbSettings, error) {
@@ -1026,12 +1362,18 @@ func (s *ImmuServer) mandatoryAuth() bool {
 	//check if there are user created databases, should be zero for auth to be off
 	for i := 0; i < s.dbList.Length(); i++ {
 		val := s.dbList.GetByIndex(int64(i))
-		if (val.GetOptions().GetDbName() != s.Options.defaultDbName) &&
+		if val != nil &&
+			(val.GetOptions().GetDbName() != s.Options.defaultDbName) &&
 			(val.GetOptions().GetDbName() != s.Options.systemAdminDbName) {
 			return true
 		}
 	}
 
+	//databases registered for lazy loading are user created ones
+	if len(s.dbList.Unloaded()) > 0 {
+		return true
+	}
+
 	//check if there is only default database
 	if (s.dbList.Length() == 1) && (s.dbList.GetByIndex(defaultDbIndex).GetOptions().GetDbName() == s.Options.defaultDbName) {
 		return false
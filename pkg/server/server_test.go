@@ -43,6 +43,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -363,6 +364,132 @@ func TestServerUpdateDatabase(t *testing.T) {
 	require.Equal(t, false, settings.Replica)
 }
 
+func TestServerDeleteDatabase(t *testing.T) {
+	ctx := context.Background()
+
+	serverOptions := DefaultOptions().
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithAuth(true)
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	s.Initialize()
+
+	_, err := s.DeleteDatabase(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	lr, err := s.Login(ctx, &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.DeleteDatabase(adminCtx, &schema.DeleteDatabaseRequest{DatabaseName: DefaultdbName})
+	require.Equal(t, ErrReservedDatabase, err)
+
+	_, err = s.DeleteDatabase(adminCtx, &schema.DeleteDatabaseRequest{DatabaseName: SystemdbName})
+	require.Equal(t, ErrReservedDatabase, err)
+
+	_, err = s.DeleteDatabase(adminCtx, &schema.DeleteDatabaseRequest{DatabaseName: "nodb"})
+	require.Equal(t, database.ErrDatabaseNotExists, err)
+
+	for _, dbname := range []string{"lisbon", "porto"} {
+		_, err = s.CreateDatabaseWith(adminCtx, &schema.DatabaseSettings{DatabaseName: dbname})
+		require.NoError(t, err)
+	}
+
+	_, err = s.CreateUser(adminCtx, &schema.CreateUserRequest{
+		User:       []byte("alice"),
+		Password:   []byte("Alice1234!"),
+		Permission: auth.PermissionRW,
+		Database:   "lisbon",
+	})
+	require.NoError(t, err)
+
+	_, err = s.ChangePermission(adminCtx, &schema.ChangePermissionRequest{
+		Action:     schema.PermissionAction_GRANT,
+		Username:   "alice",
+		Database:   "porto",
+		Permission: auth.PermissionR,
+	})
+	require.NoError(t, err)
+
+	lr, err = s.Login(ctx, &schema.LoginRequest{User: []byte("alice"), Password: []byte("Alice1234!")})
+	require.NoError(t, err)
+
+	userCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	ur, err := s.UseDatabase(userCtx, &schema.Database{DatabaseName: "lisbon"})
+	require.NoError(t, err)
+
+	userCtx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", ur.Token))
+
+	_, err = s.Set(userCtx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k"), Value: []byte("v")}}})
+	require.NoError(t, err)
+
+	_, err = s.DeleteDatabase(userCtx, &schema.DeleteDatabaseRequest{DatabaseName: "lisbon"})
+	require.Error(t, err)
+
+	ur, err = s.UseDatabase(adminCtx, &schema.Database{DatabaseName: "lisbon"})
+	require.NoError(t, err)
+
+	lisbonCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", ur.Token))
+
+	_, err = s.DeleteDatabase(adminCtx, &schema.DeleteDatabaseRequest{DatabaseName: "lisbon", Archive: true})
+	require.NoError(t, err)
+
+	_, err = s.Get(lisbonCtx, &schema.KeyRequest{Key: []byte("k")})
+	require.Equal(t, database.ErrDatabaseNotExists, err)
+
+	_, err = os.Stat(path.Join(s.Options.Dir, "lisbon"))
+	require.True(t, os.IsNotExist(err))
+
+	archived, err := ioutil.ReadDir(path.Join(s.Options.Dir, archiveDirName))
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	require.True(t, strings.HasPrefix(archived[0].Name(), "lisbon_"))
+
+	_, err = s.DeleteDatabase(adminCtx, &schema.DeleteDatabaseRequest{DatabaseName: "porto"})
+	require.NoError(t, err)
+
+	_, err = os.Stat(path.Join(s.Options.Dir, "porto"))
+	require.True(t, os.IsNotExist(err))
+
+	dbs, err := s.DatabaseList(adminCtx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, dbs.Databases, 1)
+	require.Equal(t, DefaultdbName, dbs.Databases[0].DatabaseName)
+
+	usr, err := s.getUser([]byte("alice"), true)
+	require.NoError(t, err)
+	require.Empty(t, usr.Permissions)
+
+	// deleted databases are not loaded on restart and can be created again
+	s.CloseDatabases()
+
+	s = DefaultServer().WithOptions(serverOptions.WithListener(bufconn.Listen(1024 * 1024))).(*ImmuServer)
+
+	err = s.Initialize()
+	require.NoError(t, err)
+
+	require.Equal(t, int64(-1), s.dbList.GetId("lisbon"))
+
+	lr, err = s.Login(ctx, &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.CreateDatabaseWith(adminCtx, &schema.DatabaseSettings{DatabaseName: "lisbon"})
+	require.NoError(t, err)
+}
+
 func TestServerLoaduserDatabase(t *testing.T) {
 	serverOptions := DefaultOptions().WithMetricsServer(false).WithAdminPassword(auth.SysAdminPassword)
 	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
@@ -73,14 +73,78 @@ func (s *ServerMock) StreamHistory(request *schema.HistoryRequest, historyServer
 	return s.Srv.StreamHistory(request, historyServer)
 }
 
+func (s *ServerMock) Subscribe(req *schema.SubscribeRequest, subscribeServer schema.ImmuService_SubscribeServer) error {
+	return s.Srv.Subscribe(req, subscribeServer)
+}
+
+func (s *ServerMock) StreamTxs(req *schema.StreamTxsRequest, txsServer schema.ImmuService_StreamTxsServer) error {
+	return s.Srv.StreamTxs(req, txsServer)
+}
+
 func (s *ServerMock) ExportTx(req *schema.TxRequest, txsServer schema.ImmuService_ExportTxServer) error {
 	return s.Srv.ExportTx(req, txsServer)
 }
 
+func (s *ServerMock) ExportSnapshot(req *schema.SnapshotRequest, snapshotServer schema.ImmuService_ExportSnapshotServer) error {
+	return s.Srv.ExportSnapshot(req, snapshotServer)
+}
+
+func (s *ServerMock) SyncSnapshot(ctx context.Context, req *schema.SyncSnapshotRequest) (*schema.SyncSnapshotResponse, error) {
+	return s.Srv.SyncSnapshot(ctx, req)
+}
+
+func (s *ServerMock) ReplicationEvents(req *schema.ReplicationEventsRequest, eventsServer schema.ImmuService_ReplicationEventsServer) error {
+	return s.Srv.ReplicationEvents(req, eventsServer)
+}
+
 func (s *ServerMock) ReplicateTx(replicateTxServer schema.ImmuService_ReplicateTxServer) error {
 	return s.Srv.ReplicateTx(replicateTxServer)
 }
 
+func (s *ServerMock) ConfirmReplication(ctx context.Context, req *schema.ConfirmReplicationRequest) (*empty.Empty, error) {
+	return s.Srv.ConfirmReplication(ctx, req)
+}
+
+func (s *ServerMock) ClusterStatus(ctx context.Context, req *schema.ClusterStatusRequest) (*schema.ClusterStatusResponse, error) {
+	return s.Srv.ClusterStatus(ctx, req)
+}
+
+func (s *ServerMock) PrimaryEndpoint(ctx context.Context, req *empty.Empty) (*schema.PrimaryEndpointResponse, error) {
+	return s.Srv.PrimaryEndpoint(ctx, req)
+}
+
+func (s *ServerMock) UpdateReplication(ctx context.Context, req *schema.UpdateReplicationRequest) (*schema.UpdateReplicationResponse, error) {
+	return s.Srv.UpdateReplication(ctx, req)
+}
+
+func (s *ServerMock) ReplicationAudit(ctx context.Context, req *schema.ReplicationAuditRequest) (*schema.ReplicationAuditList, error) {
+	return s.Srv.ReplicationAudit(ctx, req)
+}
+
+func (s *ServerMock) ReplicationStatus(ctx context.Context, req *empty.Empty) (*schema.ReplicationStatusResponse, error) {
+	return s.Srv.ReplicationStatus(ctx, req)
+}
+
+func (s *ServerMock) ExportDatabase(req *schema.ExportDatabaseRequest, exportServer schema.ImmuService_ExportDatabaseServer) error {
+	return s.Srv.ExportDatabase(req, exportServer)
+}
+
+func (s *ServerMock) ImportDatabase(importServer schema.ImmuService_ImportDatabaseServer) error {
+	return s.Srv.ImportDatabase(importServer)
+}
+
+func (s *ServerMock) SQLDump(req *schema.SQLDumpRequest, dumpServer schema.ImmuService_SQLDumpServer) error {
+	return s.Srv.SQLDump(req, dumpServer)
+}
+
+func (s *ServerMock) SQLLoad(loadServer schema.ImmuService_SQLLoadServer) error {
+	return s.Srv.SQLLoad(loadServer)
+}
+
+func (s *ServerMock) Backup(req *schema.BackupRequest, backupServer schema.ImmuService_BackupServer) error {
+	return s.Srv.Backup(req, backupServer)
+}
+
 func (s *ServerMock) ListUsers(ctx context.Context, req *empty.Empty) (*schema.UserList, error) {
 	return s.Srv.ListUsers(ctx, req)
 }
@@ -118,6 +182,46 @@ func (s *ServerMock) Set(ctx context.Context, req *schema.SetRequest) (*schema.T
 	return s.PostSetFn(ctx, req, rsp, err)
 }
 
+func (s *ServerMock) SetConditional(ctx context.Context, req *schema.SetConditionalRequest) (*schema.TxMetadata, error) {
+	return s.Srv.SetConditional(ctx, req)
+}
+
+func (s *ServerMock) ExpirableSet(ctx context.Context, req *schema.ExpirableSetRequest) (*schema.TxMetadata, error) {
+	return s.Srv.ExpirableSet(ctx, req)
+}
+
+func (s *ServerMock) BeginTx(ctx context.Context, req *empty.Empty) (*schema.BeginTxResponse, error) {
+	return s.Srv.BeginTx(ctx, req)
+}
+
+func (s *ServerMock) TxSet(ctx context.Context, req *schema.TxSetRequest) (*empty.Empty, error) {
+	return s.Srv.TxSet(ctx, req)
+}
+
+func (s *ServerMock) TxGet(ctx context.Context, req *schema.TxGetRequest) (*schema.Entry, error) {
+	return s.Srv.TxGet(ctx, req)
+}
+
+func (s *ServerMock) Commit(ctx context.Context, req *schema.TransactionRequest) (*schema.TxMetadata, error) {
+	return s.Srv.Commit(ctx, req)
+}
+
+func (s *ServerMock) Rollback(ctx context.Context, req *schema.TransactionRequest) (*empty.Empty, error) {
+	return s.Srv.Rollback(ctx, req)
+}
+
+func (s *ServerMock) Append(ctx context.Context, req *schema.AppendRequest) (*schema.AppendResponse, error) {
+	return s.Srv.Append(ctx, req)
+}
+
+func (s *ServerMock) Delete(ctx context.Context, req *schema.DeleteKeysRequest) (*schema.TxMetadata, error) {
+	return s.Srv.Delete(ctx, req)
+}
+
+func (s *ServerMock) DeletePrefix(ctx context.Context, req *schema.DeletePrefixRequest) (*schema.TxMetadata, error) {
+	return s.Srv.DeletePrefix(ctx, req)
+}
+
 func (s *ServerMock) VerifiableSet(ctx context.Context, req *schema.VerifiableSetRequest) (*schema.VerifiableTx, error) {
 	if s.PostVerifiableSetFn == nil {
 		return s.Srv.VerifiableSet(ctx, req)
@@ -152,6 +256,14 @@ func (s *ServerMock) Scan(ctx context.Context, req *schema.ScanRequest) (*schema
 	return s.Srv.Scan(ctx, req)
 }
 
+func (s *ServerMock) OpenSnapshot(ctx context.Context, req *schema.OpenSnapshotRequest) (*schema.OpenSnapshotResponse, error) {
+	return s.Srv.OpenSnapshot(ctx, req)
+}
+
+func (s *ServerMock) ReleaseSnapshot(ctx context.Context, req *schema.ReleaseSnapshotRequest) (*empty.Empty, error) {
+	return s.Srv.ReleaseSnapshot(ctx, req)
+}
+
 func (s *ServerMock) Count(ctx context.Context, req *schema.KeyPrefix) (*schema.EntryCount, error) {
 	return s.Srv.Count(ctx, req)
 }
@@ -220,10 +332,50 @@ func (s *ServerMock) VerifiableZAdd(ctx context.Context, req *schema.VerifiableZ
 	return s.PostVerifiableZAddFn(ctx, req, rsp, err)
 }
 
+func (s *ServerMock) ZRem(ctx context.Context, req *schema.ZRemRequest) (*schema.TxMetadata, error) {
+	return s.Srv.ZRem(ctx, req)
+}
+
 func (s *ServerMock) ZScan(ctx context.Context, req *schema.ZScanRequest) (*schema.ZEntries, error) {
 	return s.Srv.ZScan(ctx, req)
 }
 
+func (s *ServerMock) ResolveReferenceHistory(ctx context.Context, req *schema.ReferenceHistoryRequest) (*schema.VerifiableReferenceHistory, error) {
+	return s.Srv.ResolveReferenceHistory(ctx, req)
+}
+
+func (s *ServerMock) MultiInclusion(ctx context.Context, req *schema.MultiInclusionRequest) (*schema.MultiInclusionResponse, error) {
+	return s.Srv.MultiInclusion(ctx, req)
+}
+
+func (s *ServerMock) StateHistory(ctx context.Context, req *schema.StateHistoryRequest) (*schema.StateList, error) {
+	return s.Srv.StateHistory(ctx, req)
+}
+
+func (s *ServerMock) TimestampTokens(ctx context.Context, req *schema.TimestampTokensRequest) (*schema.TimestampTokenList, error) {
+	return s.Srv.TimestampTokens(ctx, req)
+}
+
+func (s *ServerMock) AnchorReceipts(ctx context.Context, req *schema.AnchorReceiptsRequest) (*schema.AnchorReceiptList, error) {
+	return s.Srv.AnchorReceipts(ctx, req)
+}
+
+func (s *ServerMock) ReferencesTo(ctx context.Context, req *schema.ReferencesToRequest) (*schema.References, error) {
+	return s.Srv.ReferencesTo(ctx, req)
+}
+
+func (s *ServerMock) VerifiableZScan(ctx context.Context, req *schema.VerifiableZScanRequest) (*schema.VerifiableZEntries, error) {
+	return s.Srv.VerifiableZScan(ctx, req)
+}
+
+func (s *ServerMock) ZCard(ctx context.Context, req *schema.ZCardRequest) (*schema.EntryCount, error) {
+	return s.Srv.ZCard(ctx, req)
+}
+
+func (s *ServerMock) ZCount(ctx context.Context, req *schema.ZCountRequest) (*schema.EntryCount, error) {
+	return s.Srv.ZCount(ctx, req)
+}
+
 func (s *ServerMock) CreateDatabase(ctx context.Context, req *schema.Database) (*empty.Empty, error) {
 	return s.Srv.CreateDatabase(ctx, req)
 }
@@ -244,6 +396,42 @@ func (s *ServerMock) UpdateDatabase(ctx context.Context, req *schema.DatabaseSet
 	return s.Srv.UpdateDatabase(ctx, req)
 }
 
+func (s *ServerMock) UpdateDatabaseSettings(ctx context.Context, req *schema.UpdateDatabaseSettingsRequest) (*schema.UpdateDatabaseSettingsResponse, error) {
+	return s.Srv.UpdateDatabaseSettings(ctx, req)
+}
+
+func (s *ServerMock) DeleteDatabase(ctx context.Context, req *schema.DeleteDatabaseRequest) (*empty.Empty, error) {
+	return s.Srv.DeleteDatabase(ctx, req)
+}
+
+func (s *ServerMock) CloneDatabase(ctx context.Context, req *schema.CloneDatabaseRequest) (*schema.CloneDatabaseResponse, error) {
+	return s.Srv.CloneDatabase(ctx, req)
+}
+
+func (s *ServerMock) Restore(ctx context.Context, req *schema.RestoreRequest) (*schema.RestoreResponse, error) {
+	return s.Srv.Restore(ctx, req)
+}
+
+func (s *ServerMock) BackupStatus(ctx context.Context, req *empty.Empty) (*schema.BackupStatusResponse, error) {
+	return s.Srv.BackupStatus(ctx, req)
+}
+
+func (s *ServerMock) UpdateCorruptionChecker(ctx context.Context, req *schema.UpdateCorruptionCheckerRequest) (*schema.CorruptionCheckerSettings, error) {
+	return s.Srv.UpdateCorruptionChecker(ctx, req)
+}
+
+func (s *ServerMock) CorruptionCheckStatus(ctx context.Context, req *schema.CorruptionCheckStatusRequest) (*schema.CorruptionCheckStatusResponse, error) {
+	return s.Srv.CorruptionCheckStatus(ctx, req)
+}
+
+func (s *ServerMock) Scrub(ctx context.Context, req *schema.ScrubRequest) (*schema.ScrubResponse, error) {
+	return s.Srv.Scrub(ctx, req)
+}
+
+func (s *ServerMock) AcknowledgeCorruption(ctx context.Context, req *schema.AcknowledgeCorruptionRequest) (*empty.Empty, error) {
+	return s.Srv.AcknowledgeCorruption(ctx, req)
+}
+
 func (s *ServerMock) CleanIndex(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
 	return s.Srv.CleanIndex(ctx, req)
 }
@@ -295,3 +483,11 @@ func (s *ServerMock) DescribeTable(ctx context.Context, req *schema.Table) (*sch
 func (s *ServerMock) VerifiableSQLGet(ctx context.Context, req *schema.VerifiableSQLGetRequest) (*schema.VerifiableSQLEntry, error) {
 	return s.Srv.VerifiableSQLGet(ctx, req)
 }
+
+func (s *ServerMock) ComplianceReport(ctx context.Context, req *schema.ComplianceReportRequest) (*schema.ComplianceReportResponse, error) {
+	return s.Srv.ComplianceReport(ctx, req)
+}
+
+func (s *ServerMock) VerifyRange(ctx context.Context, req *schema.VerifyRangeRequest) (*schema.VerifyRangeResponse, error) {
+	return s.Srv.VerifyRange(ctx, req)
+}
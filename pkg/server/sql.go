@@ -19,6 +19,7 @@ import (
 	"context"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/stream"
 	"github.com/golang/protobuf/ptypes/empty"
 )
 
@@ -28,7 +29,30 @@ func (s *ImmuServer) VerifiableSQLGet(ctx context.Context, req *schema.Verifiabl
 		return nil, err
 	}
 
-	return db.VerifiableSQLGet(req)
+	vEntry, err := db.VerifiableSQLGet(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Options.SigningKey != "" {
+		md := schema.TxMetadataFrom(vEntry.VerifiableTx.DualProof.TargetTxMetadata)
+		alh := md.Alh()
+
+		newState := &schema.ImmutableState{
+			Db:     db.GetOptions().GetDbName(),
+			TxId:   md.ID,
+			TxHash: alh[:],
+		}
+
+		err = s.StateSigner.Sign(newState)
+		if err != nil {
+			return nil, err
+		}
+
+		vEntry.VerifiableTx.Signature = newState.Signature
+	}
+
+	return vEntry, nil
 }
 
 func (s *ImmuServer) SQLExec(ctx context.Context, req *schema.SQLExecRequest) (*schema.SQLExecResult, error) {
@@ -83,3 +107,47 @@ func (s *ImmuServer) DescribeTable(ctx context.Context, req *schema.Table) (*sch
 
 	return db.DescribeTable(req.TableName)
 }
+
+// SQLDump streams the schema and the rows of the tables of the database in use, as a SQL script or as CSV records
+func (s *ImmuServer) SQLDump(req *schema.SQLDumpRequest, dumpServer schema.ImmuService_SQLDumpServer) error {
+	if req == nil || dumpServer == nil {
+		return ErrIllegalArguments
+	}
+
+	db, err := s.getDBFromCtx(dumpServer.Context(), "SQLDump")
+	if err != nil {
+		return err
+	}
+
+	cw := stream.NewChunkWriter(dumpServer, s.Options.StreamChunkSize)
+
+	err = db.SQLDump(req, cw)
+	if err != nil {
+		return err
+	}
+
+	return cw.Close()
+}
+
+// SQLLoad executes a dump produced by SQLDump in the database in use
+func (s *ImmuServer) SQLLoad(loadServer schema.ImmuService_SQLLoadServer) error {
+	if loadServer == nil {
+		return ErrIllegalArguments
+	}
+
+	if s.Options.GetMaintenance() {
+		return ErrNotAllowedInMaintenanceMode
+	}
+
+	db, err := s.getDBFromCtx(loadServer.Context(), "SQLLoad")
+	if err != nil {
+		return err
+	}
+
+	res, err := db.SQLLoad(stream.NewChunkReader(loadServer))
+	if err != nil {
+		return err
+	}
+
+	return loadServer.SendAndClose(res)
+}
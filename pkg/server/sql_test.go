@@ -17,13 +17,17 @@ package server
 
 import (
 	"context"
+	"io"
 	"os"
 	"testing"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/signer"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -100,3 +104,134 @@ func TestSQLInteraction(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, e)
 }
+
+func TestVerifiableSQLGetSigned(t *testing.T) {
+	serverOptions := DefaultOptions().
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithSigningKey("./../../test/signer/ec3.key").
+		WithListener(bufconn.Listen(1024 * 1024))
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	_, err = s.SQLExec(ctx, &schema.SQLExecRequest{Sql: "CREATE TABLE table1 (id INTEGER, PRIMARY KEY id)"})
+	require.NoError(t, err)
+
+	_, err = s.SQLExec(ctx, &schema.SQLExecRequest{Sql: "INSERT INTO table1 (id) VALUES (1)"})
+	require.NoError(t, err)
+
+	e, err := s.VerifiableSQLGet(ctx, &schema.VerifiableSQLGetRequest{
+		SqlGetRequest: &schema.SQLGetRequest{Table: "table1", PkValue: &schema.SQLValue{Value: &schema.SQLValue_N{N: 1}}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, e.VerifiableTx.Signature)
+
+	targetMd := schema.TxMetadataFrom(e.VerifiableTx.DualProof.TargetTxMetadata)
+	alh := targetMd.Alh()
+
+	state := &schema.ImmutableState{
+		Db:        s.Options.defaultDbName,
+		TxId:      targetMd.ID,
+		TxHash:    alh[:],
+		Signature: e.VerifiableTx.Signature,
+	}
+
+	pk, err := signer.ParsePublicKeyFile("./../../test/signer/ec3.pub")
+	require.NoError(t, err)
+
+	ok, err := state.CheckSignature(pk)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+type immuServiceSQLLoadServer struct {
+	grpc.ServerStream
+	ctx  context.Context
+	dump []byte
+	res  *schema.SQLExecResult
+}
+
+func (s *immuServiceSQLLoadServer) Recv() (*schema.Chunk, error) {
+	if len(s.dump) == 0 {
+		return nil, io.EOF
+	}
+
+	chunk := &schema.Chunk{Content: s.dump}
+	s.dump = nil
+
+	return chunk, nil
+}
+
+func (s *immuServiceSQLLoadServer) SendAndClose(res *schema.SQLExecResult) error {
+	s.res = res
+	return nil
+}
+
+func (s *immuServiceSQLLoadServer) Context() context.Context {
+	return s.ctx
+}
+
+func TestSQLDumpLoad(t *testing.T) {
+	serverOptions := DefaultOptions().WithDir("data_sqldump").WithMetricsServer(false).WithAdminPassword(auth.SysAdminPassword)
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	s.Initialize()
+
+	err := s.SQLDump(nil, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	err = s.SQLLoad(nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.SQLExec(ctx, &schema.SQLExecRequest{Sql: "CREATE TABLE table1 (id INTEGER, title VARCHAR, PRIMARY KEY id)"})
+	require.NoError(t, err)
+
+	_, err = s.SQLExec(ctx, &schema.SQLExecRequest{Sql: "INSERT INTO table1 (id, title) VALUES (1, 'title1'), (2, 'title2')"})
+	require.NoError(t, err)
+
+	dumpServer := &immuServiceExportDatabaseServer{ctx: ctx}
+
+	err = s.SQLDump(&schema.SQLDumpRequest{}, dumpServer)
+	require.NoError(t, err)
+	require.Contains(t, dumpServer.buf.String(), "CREATE TABLE table1")
+
+	_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{DatabaseName: "target"})
+	require.NoError(t, err)
+
+	ur, err := s.UseDatabase(ctx, &schema.Database{DatabaseName: "target"})
+	require.NoError(t, err)
+
+	targetCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", ur.Token))
+
+	loadServer := &immuServiceSQLLoadServer{ctx: targetCtx, dump: dumpServer.buf.Bytes()}
+
+	err = s.SQLLoad(loadServer)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), loadServer.res.UpdatedRows)
+
+	res, err := s.SQLQuery(targetCtx, &schema.SQLQueryRequest{Sql: "SELECT title FROM table1"})
+	require.NoError(t, err)
+	require.Len(t, res.Rows, 2)
+	require.Equal(t, "title2", res.Rows[1].Values[0].GetS())
+}
@@ -24,6 +24,7 @@ import (
 
 type StateSigner interface {
 	Sign(state *schema.ImmutableState) error
+	SignComplianceReport(report *schema.ComplianceReportResponse) error
 }
 
 type stateSigner struct {
@@ -50,3 +51,18 @@ func (sts *stateSigner) Sign(state *schema.ImmutableState) error {
 
 	return err
 }
+
+func (sts *stateSigner) SignComplianceReport(report *schema.ComplianceReportResponse) error {
+	if report == nil {
+		return store.ErrIllegalArguments
+	}
+
+	signature, publicKey, err := sts.Signer.Sign(report.ToBytes())
+
+	report.Signature = &schema.Signature{
+		Signature: signature,
+		PublicKey: publicKey,
+	}
+
+	return err
+}
@@ -46,3 +46,16 @@ func TestStateSigner_Err(t *testing.T) {
 	err := stSigner.Sign(nil)
 	assert.Error(t, store.ErrIllegalArguments, err)
 }
+
+func TestStateSigner_SignComplianceReport(t *testing.T) {
+	s, _ := signer.NewSigner("./../../test/signer/ec3.key")
+	stSigner := NewStateSigner(s)
+
+	err := stSigner.SignComplianceReport(nil)
+	assert.Error(t, store.ErrIllegalArguments, err)
+
+	report := &schema.ComplianceReportResponse{Db: "db1", TxCount: 1}
+	err = stSigner.SignComplianceReport(report)
+	assert.NoError(t, err)
+	assert.NotNil(t, report.Signature)
+}
@@ -18,8 +18,16 @@ package server
 
 import (
 	"bytes"
+	"context"
+	"strconv"
+	"time"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func (s *ImmuServer) ExportTx(req *schema.TxRequest, txsServer schema.ImmuService_ExportTxServer) error {
@@ -32,16 +40,33 @@ func (s *ImmuServer) ExportTx(req *schema.TxRequest, txsServer schema.ImmuServic
 		return err
 	}
 
+	err = s.negotiateReplicationProtocol(db.GetName(), txsServer)
+	if err != nil {
+		return err
+	}
+
+	replicaID, err := s.ownReplicaID(txsServer.Context(), db, req.ReplicaId)
+	if err != nil {
+		return err
+	}
+
 	err = db.WaitForTx(req.Tx, nil)
 	if err != nil {
 		return err
 	}
 
-	bs, err := db.ExportTxByID(&schema.TxRequest{Tx: req.Tx})
+	bs, err := db.ExportTxByID(&schema.TxRequest{Tx: req.Tx, ReplicaId: replicaID})
 	if err != nil {
+		if replicaID != "" {
+			s.replicationFailed(db.GetName(), replicaID, req.Tx, err)
+		}
 		return err
 	}
 
+	if replicaID != "" && s.replicationEvents.exporting(db.GetName(), replicaID) {
+		s.emitReplicationEvent(&replicationEvent{Kind: ReplicationEventSyncStarted, Database: db.GetName(), ReplicaID: replicaID, TxID: req.Tx})
+	}
+
 	sender := s.StreamServiceFactory.NewMsgSender(txsServer)
 
 	err = sender.Send(bytes.NewReader(bs), len(bs))
@@ -62,6 +87,16 @@ func (s *ImmuServer) ReplicateTx(replicateTxServer schema.ImmuService_ReplicateT
 		return err
 	}
 
+	err = s.negotiateReplicationProtocol(db.GetName(), replicateTxServer)
+	if err != nil {
+		return err
+	}
+
+	// replicating on top of a diverged history would only make it harder to repair
+	if s.divergenceOf(db.GetName()) != nil {
+		return ErrDivergedFromPrimary
+	}
+
 	receiver := s.StreamServiceFactory.NewMsgReceiver(replicateTxServer)
 
 	bs, err := receiver.ReadFully()
@@ -71,8 +106,255 @@ func (s *ImmuServer) ReplicateTx(replicateTxServer schema.ImmuService_ReplicateT
 
 	md, err := db.ReplicateTx(bs)
 	if err != nil {
+		s.replicationFailed(db.GetName(), "", 0, err)
 		return err
 	}
 
+	s.replicationEvents.recovered(db.GetName(), "")
+
 	return replicateTxServer.SendAndClose(md)
 }
+
+// negotiateReplicationProtocol checks the versions advertised by the replication peer before any transaction
+// gets exchanged, so that mixed-version peers fail fast. The local versions and the negotiated protocol version
+// are sent back right away, the peer can check them before streaming its transactions
+func (s *ImmuServer) negotiateReplicationProtocol(dbName string, stream grpc.ServerStream) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+
+	peer, err := schema.ReplicationVersionsFromMD(md)
+	if err != nil {
+		return err
+	}
+
+	local := schema.LocalReplicationVersions()
+
+	version, err := schema.NegotiateReplicationProtocol(local, peer)
+	if err != nil {
+		s.Logger.Warningf("Replication of database '%s' refused: %v", dbName, err)
+		return err
+	}
+
+	header := local.MD()
+	header.Set(schema.NegotiatedReplicationProtocolKey, strconv.Itoa(version))
+
+	return stream.SendHeader(header)
+}
+
+// ConfirmReplication is called on behalf of a replica once it durably holds the transactions up to the given one,
+// commits waiting for synchronous replication are acknowledged once enough replicas confirmed them.
+// A replica feeding replicas of its own reports their progress as well, so the whole tree can be followed from its root
+func (s *ImmuServer) ConfirmReplication(ctx context.Context, req *schema.ConfirmReplicationRequest) (*empty.Empty, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	db, err := s.getDBFromCtx(ctx, "ConfirmReplication")
+	if err != nil {
+		return nil, err
+	}
+
+	replicaID, err := s.ownReplicaID(ctx, db, req.ReplicaId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.ConfirmReplication(replicaID, req.TxId, req.TxHash)
+	if err != nil {
+		s.replicationFailed(db.GetName(), replicaID, req.TxId, err)
+		return nil, err
+	}
+
+	state, err := db.CurrentState()
+	if err != nil {
+		return nil, err
+	}
+
+	lag := uint64(0)
+	if state.TxId > req.TxId {
+		lag = state.TxId - req.TxId
+	}
+
+	s.replicaProgressed(db.GetName(), replicaID, req.TxId, lag)
+
+	// an intermediate replica of a replication tree reports the replicas it feeds along with its confirmations
+	db.ReportDownstreamReplicas(replicaID, downstreamProgress(req.Downstream))
+
+	return &empty.Empty{}, nil
+}
+
+// ownReplicaID restricts users other than the sysadmin to fetch and confirm transactions as the replica named after them,
+// so that nobody can take over the progress, throttling or synchronous acks of another replica
+func (s *ImmuServer) ownReplicaID(ctx context.Context, db database.DB, replicaID string) (string, error) {
+	if !s.Options.GetAuth() {
+		return replicaID, nil
+	}
+
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if user.IsSysAdmin {
+		return replicaID, nil
+	}
+
+	// replication accounts are the replica when none is given, other users are just reading transactions
+	if replicaID == "" && user.WhichPermission(db.GetName()) == auth.PermissionReplication {
+		return user.Username, nil
+	}
+
+	if replicaID == "" {
+		return replicaID, nil
+	}
+
+	if replicaID != user.Username {
+		return "", ErrNotOwnReplica
+	}
+
+	return replicaID, nil
+}
+
+// ReplicationStatus returns the progress of each replica fetching transactions from the current database
+func (s *ImmuServer) ReplicationStatus(ctx context.Context, _ *empty.Empty) (*schema.ReplicationStatusResponse, error) {
+	db, err := s.getDBFromCtx(ctx, "ReplicationStatus")
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := db.CurrentState()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &schema.ReplicationStatusResponse{
+		Database: db.GetName(),
+		TxId:     state.TxId,
+	}
+
+	if db.IsReplica() {
+		res.ReplicatedTxId, err = db.ReplicatedTxID()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if d := s.divergenceOf(db.GetName()); d != nil {
+		res.Divergence = &schema.ReplicationDivergence{
+			TxId:          d.DivergentTx,
+			ReplicaTxHash: d.ReplicaTxHash,
+			PrimaryTxHash: d.PrimaryTxHash,
+			ReplicaTxId:   d.ReplicaTx,
+			PrimaryTxId:   d.PrimaryTx,
+			Primary:       d.Primary,
+			DetectedAt:    d.DetectedAt.Unix(),
+		}
+	}
+
+	for _, p := range db.ReplicationProgress() {
+		rs := replicaStatusOf(p, state.TxId)
+
+		if rs.Divergence != nil {
+			rs.Divergence.PrimaryTxId = state.TxId
+		}
+
+		res.Replicas = append(res.Replicas, rs)
+	}
+
+	res.Summary = &schema.ReplicationSummary{}
+	summarizeReplicas(res.Summary, res.Replicas, 1)
+
+	return res, nil
+}
+
+// replicaStatusOf returns the status of a replica and of the replicas it feeds,
+// their lag is the one from the root of the replication tree, whose last transaction is txID
+func replicaStatusOf(p *database.ReplicaProgress, txID uint64) *schema.ReplicaStatus {
+	rs := &schema.ReplicaStatus{
+		ReplicaId:     p.ReplicaID,
+		ExportedTxId:  p.ExportedTxID,
+		ConfirmedTxId: p.ConfirmedTxID,
+		KeyPrefixes:   p.KeyPrefixes,
+	}
+
+	if !p.LastSeenAt.IsZero() {
+		rs.LastSeenAt = p.LastSeenAt.Unix()
+	}
+
+	if txID > p.ConfirmedTxID {
+		rs.Lag = txID - p.ConfirmedTxID
+	}
+
+	if d := p.Divergence; d != nil {
+		rs.Divergence = &schema.ReplicationDivergence{
+			TxId:          d.TxID,
+			ReplicaTxHash: d.ReplicaTxHash,
+			PrimaryTxHash: d.TxHash,
+			ReplicaTxId:   d.TxID,
+			DetectedAt:    d.DetectedAt.Unix(),
+		}
+	}
+
+	for _, dp := range p.Downstream {
+		rs.Downstream = append(rs.Downstream, replicaStatusOf(dp, txID))
+	}
+
+	return rs
+}
+
+// summarizeReplicas rolls up the progress and health of the replicas at the given depth of the replication tree
+// and of the ones below them
+func summarizeReplicas(summary *schema.ReplicationSummary, replicas []*schema.ReplicaStatus, depth uint32) {
+	for _, rs := range replicas {
+		summary.Replicas++
+
+		if summary.Depth < depth {
+			summary.Depth = depth
+		}
+
+		if summary.MaxLag < rs.Lag {
+			summary.MaxLag = rs.Lag
+		}
+
+		if rs.Divergence != nil {
+			summary.Diverged++
+		}
+
+		summarizeReplicas(summary, rs.Downstream, depth+1)
+	}
+}
+
+// downstreamProgress converts the status of the replicas reported by a replica for the ones it feeds
+func downstreamProgress(downstream []*schema.ReplicaStatus) []*database.ReplicaProgress {
+	if len(downstream) == 0 {
+		return nil
+	}
+
+	progress := make([]*database.ReplicaProgress, len(downstream))
+
+	for i, rs := range downstream {
+		p := &database.ReplicaProgress{
+			ReplicaID:     rs.ReplicaId,
+			ExportedTxID:  rs.ExportedTxId,
+			ConfirmedTxID: rs.ConfirmedTxId,
+			KeyPrefixes:   rs.KeyPrefixes,
+			Downstream:    downstreamProgress(rs.Downstream),
+		}
+
+		if rs.LastSeenAt > 0 {
+			p.LastSeenAt = time.Unix(rs.LastSeenAt, 0)
+		}
+
+		if d := rs.Divergence; d != nil {
+			p.Divergence = &database.ReplicaDivergence{
+				TxID:          d.TxId,
+				ReplicaTxHash: d.ReplicaTxHash,
+				TxHash:        d.PrimaryTxHash,
+				DetectedAt:    time.Unix(d.DetectedAt, 0),
+			}
+		}
+
+		progress[i] = p
+	}
+
+	return progress
+}
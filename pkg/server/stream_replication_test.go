@@ -21,6 +21,7 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/immudb/pkg/auth"
@@ -110,6 +111,10 @@ func (s *immuServiceExportTxServer) Context() context.Context {
 	return context.TODO()
 }
 
+func (s *immuServiceExportTxServer) SendHeader(metadata.MD) error {
+	return nil
+}
+
 type immuServiceReplicateTxServer struct {
 	grpc.ServerStream
 	ctx context.Context
@@ -126,3 +131,331 @@ func (s *immuServiceReplicateTxServer) SendAndClose(md *schema.TxMetadata) error
 func (s *immuServiceReplicateTxServer) Context() context.Context {
 	return s.ctx
 }
+
+func (s *immuServiceReplicateTxServer) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func TestReplicationStatus(t *testing.T) {
+	serverOptions := DefaultOptions().WithMetricsServer(false).WithAdminPassword(auth.SysAdminPassword)
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	s.Initialize()
+
+	_, err := s.ReplicationStatus(context.Background(), nil)
+	require.Error(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+	require.NoError(t, err)
+
+	db, err := s.getDBFromCtx(ctx, "ReplicationStatus")
+	require.NoError(t, err)
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+
+	for _, replicaID := range []string{"replica1", "replica2"} {
+		_, err = db.ExportTxByID(&schema.TxRequest{Tx: state.TxId, ReplicaId: replicaID})
+		require.NoError(t, err)
+	}
+
+	_, err = s.ConfirmReplication(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = s.ConfirmReplication(ctx, &schema.ConfirmReplicationRequest{
+		ReplicaId: "replica1",
+		TxId:      state.TxId,
+		TxHash:    state.TxHash,
+	})
+	require.NoError(t, err)
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value2")}}})
+	require.NoError(t, err)
+
+	res, err := s.ReplicationStatus(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, DefaultdbName, res.Database)
+	require.Equal(t, state.TxId+1, res.TxId)
+	require.Len(t, res.Replicas, 2)
+
+	require.Equal(t, "replica1", res.Replicas[0].ReplicaId)
+	require.Equal(t, state.TxId, res.Replicas[0].ExportedTxId)
+	require.Equal(t, state.TxId, res.Replicas[0].ConfirmedTxId)
+	require.Equal(t, uint64(1), res.Replicas[0].Lag)
+
+	require.Equal(t, "replica2", res.Replicas[1].ReplicaId)
+	require.Equal(t, state.TxId, res.Replicas[1].ExportedTxId)
+	require.Equal(t, uint64(0), res.Replicas[1].ConfirmedTxId)
+	require.Equal(t, state.TxId+1, res.Replicas[1].Lag)
+
+	db.GetOptions().GetReplicationOptions().WithReplicaFilters(map[string][][]byte{"edge1": {[]byte("tenant1/")}})
+
+	res, err = s.ReplicationStatus(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Replicas, 3)
+	require.Equal(t, "edge1", res.Replicas[0].ReplicaId)
+	require.Equal(t, [][]byte{[]byte("tenant1/")}, res.Replicas[0].KeyPrefixes)
+	require.Equal(t, int64(0), res.Replicas[0].LastSeenAt)
+	require.Empty(t, res.Replicas[1].KeyPrefixes)
+	require.Nil(t, res.Divergence)
+
+	// a replica confirming another history is reported along with both hashes
+	_, err = s.ConfirmReplication(ctx, &schema.ConfirmReplicationRequest{
+		ReplicaId: "replica2",
+		TxId:      state.TxId,
+		TxHash:    make([]byte, len(state.TxHash)),
+	})
+	require.Error(t, err)
+
+	res, err = s.ReplicationStatus(ctx, nil)
+	require.NoError(t, err)
+	require.Nil(t, res.Replicas[1].Divergence)
+	require.NotNil(t, res.Replicas[2].Divergence)
+	require.Equal(t, state.TxId, res.Replicas[2].Divergence.TxId)
+	require.Equal(t, make([]byte, len(state.TxHash)), res.Replicas[2].Divergence.ReplicaTxHash)
+	require.Equal(t, state.TxHash, res.Replicas[2].Divergence.PrimaryTxHash)
+
+	// so is the divergence of the database from its own primary, which stops its replication
+	s.diverged[DefaultdbName] = &replicaDivergence{
+		Primary:       "primary",
+		ReplicaTx:     state.TxId + 1,
+		PrimaryTx:     state.TxId + 2,
+		DivergentTx:   state.TxId,
+		ReplicaTxHash: state.TxHash,
+		PrimaryTxHash: []byte{1},
+		DetectedAt:    time.Now(),
+	}
+
+	res, err = s.ReplicationStatus(ctx, nil)
+	require.NoError(t, err)
+	require.NotNil(t, res.Divergence)
+	require.Equal(t, "primary", res.Divergence.Primary)
+	require.Equal(t, state.TxId, res.Divergence.TxId)
+	require.Equal(t, state.TxHash, res.Divergence.ReplicaTxHash)
+	require.Equal(t, []byte{1}, res.Divergence.PrimaryTxHash)
+	require.Equal(t, state.TxId+1, res.Divergence.ReplicaTxId)
+	require.Equal(t, state.TxId+2, res.Divergence.PrimaryTxId)
+
+	err = s.ReplicateTx(&immuServiceReplicateTxServer{ctx: ctx})
+	require.Equal(t, ErrDivergedFromPrimary, err)
+}
+
+func TestCascadingReplicationStatus(t *testing.T) {
+	serverOptions := DefaultOptions().WithMetricsServer(false).WithAdminPassword(auth.SysAdminPassword)
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	s.Initialize()
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	for i := 0; i < 3; i++ {
+		_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte{byte(i)}}}})
+		require.NoError(t, err)
+	}
+
+	db, err := s.getDBFromCtx(ctx, "ReplicationStatus")
+	require.NoError(t, err)
+
+	state, err := db.CurrentState()
+	require.NoError(t, err)
+
+	// the regional replica feeds two edge replicas, one of them feeding another one in turn
+	_, err = s.ConfirmReplication(ctx, &schema.ConfirmReplicationRequest{
+		ReplicaId: "regional",
+		TxId:      state.TxId,
+		TxHash:    state.TxHash,
+		Downstream: []*schema.ReplicaStatus{
+			{
+				ReplicaId:     "edge1",
+				ExportedTxId:  state.TxId,
+				ConfirmedTxId: state.TxId - 1,
+				LastSeenAt:    time.Now().Unix(),
+				Downstream: []*schema.ReplicaStatus{
+					{ReplicaId: "edge1a", ConfirmedTxId: state.TxId - 2},
+				},
+			},
+			{
+				ReplicaId:     "edge2",
+				ConfirmedTxId: state.TxId - 1,
+				Divergence:    &schema.ReplicationDivergence{TxId: state.TxId - 1, ReplicaTxHash: []byte{1}, PrimaryTxHash: []byte{2}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	res, err := s.ReplicationStatus(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Replicas, 1)
+
+	regional := res.Replicas[0]
+	require.Equal(t, "regional", regional.ReplicaId)
+	require.Zero(t, regional.Lag)
+	require.Len(t, regional.Downstream, 2)
+
+	edge1 := regional.Downstream[0]
+	require.Equal(t, "edge1", edge1.ReplicaId)
+	require.Equal(t, uint64(1), edge1.Lag)
+	require.NotZero(t, edge1.LastSeenAt)
+	require.Len(t, edge1.Downstream, 1)
+	require.Equal(t, "edge1a", edge1.Downstream[0].ReplicaId)
+	require.Equal(t, uint64(2), edge1.Downstream[0].Lag)
+
+	edge2 := regional.Downstream[1]
+	require.NotNil(t, edge2.Divergence)
+	require.Equal(t, state.TxId-1, edge2.Divergence.TxId)
+	require.Equal(t, []byte{1}, edge2.Divergence.ReplicaTxHash)
+	require.Equal(t, []byte{2}, edge2.Divergence.PrimaryTxHash)
+
+	require.Equal(t, uint32(4), res.Summary.Replicas)
+	require.Equal(t, uint32(3), res.Summary.Depth)
+	require.Equal(t, uint64(2), res.Summary.MaxLag)
+	require.Equal(t, uint32(1), res.Summary.Diverged)
+
+	// the replicas fed by a replica are replaced on each confirmation
+	_, err = s.ConfirmReplication(ctx, &schema.ConfirmReplicationRequest{
+		ReplicaId: "regional",
+		TxId:      state.TxId,
+		TxHash:    state.TxHash,
+	})
+	require.NoError(t, err)
+
+	res, err = s.ReplicationStatus(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, res.Replicas[0].Downstream)
+	require.Equal(t, uint32(1), res.Summary.Replicas)
+	require.Equal(t, uint32(1), res.Summary.Depth)
+}
+
+func TestReplicationAccount(t *testing.T) {
+	serverOptions := DefaultOptions().WithMetricsServer(false).WithAdminPassword(auth.SysAdminPassword)
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+	defer os.RemoveAll(s.Options.Dir)
+
+	s.Initialize()
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.Set(adminCtx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+	require.NoError(t, err)
+
+	for _, replicaID := range []string{"replica1", "replica2"} {
+		_, err = s.CreateUser(adminCtx, &schema.CreateUserRequest{
+			User:       []byte(replicaID),
+			Password:   []byte("Passw0rd!-"),
+			Permission: auth.PermissionReplication,
+			Database:   DefaultdbName,
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = s.ChangePermission(adminCtx, &schema.ChangePermissionRequest{
+		Action:     schema.PermissionAction_GRANT,
+		Username:   "replica1",
+		Database:   SystemdbName,
+		Permission: auth.PermissionReplication,
+	})
+	require.Equal(t, ErrPermissionDenied, err)
+
+	lr, err = s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte("replica1"),
+		Password: []byte("Passw0rd!-"),
+	})
+	require.NoError(t, err)
+
+	ur, err := s.UseDatabase(metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token)),
+		&schema.Database{DatabaseName: DefaultdbName})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", ur.Token))
+
+	state, err := s.CurrentState(ctx, nil)
+	require.NoError(t, err)
+
+	// transactions can only be streamed, not read or written
+	_, err = s.Get(ctx, &schema.KeyRequest{Key: []byte("key")})
+	require.Equal(t, ErrPermissionDenied, err)
+
+	_, err = s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key"), Value: []byte("value")}}})
+	require.Equal(t, ErrPermissionDenied, err)
+
+	err = s.ExportTx(&schema.TxRequest{Tx: state.TxId, ReplicaId: "replica2"}, &exportTxServer{ctx: ctx})
+	require.Equal(t, ErrNotOwnReplica, err)
+
+	// the account is the replica when none is given
+	err = s.ExportTx(&schema.TxRequest{Tx: state.TxId}, &exportTxServer{ctx: ctx})
+	require.NoError(t, err)
+
+	_, err = s.ConfirmReplication(ctx, &schema.ConfirmReplicationRequest{ReplicaId: "replica2", TxId: state.TxId, TxHash: state.TxHash})
+	require.Equal(t, ErrNotOwnReplica, err)
+
+	_, err = s.ConfirmReplication(ctx, &schema.ConfirmReplicationRequest{ReplicaId: "replica1", TxId: state.TxId, TxHash: state.TxHash})
+	require.NoError(t, err)
+
+	res, err := s.ReplicationStatus(adminCtx, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Replicas, 1)
+	require.Equal(t, "replica1", res.Replicas[0].ReplicaId)
+	require.Equal(t, state.TxId, res.Replicas[0].ConfirmedTxId)
+
+	for user, permission := range map[string]uint32{"reader": auth.PermissionR, "dbadmin": auth.PermissionAdmin} {
+		_, err = s.CreateUser(adminCtx, &schema.CreateUserRequest{
+			User:       []byte(user),
+			Password:   []byte("Passw0rd!-"),
+			Permission: permission,
+			Database:   DefaultdbName,
+		})
+		require.NoError(t, err)
+	}
+
+	userCtx := func(user string) context.Context {
+		lr, err := s.Login(context.Background(), &schema.LoginRequest{User: []byte(user), Password: []byte("Passw0rd!-")})
+		require.NoError(t, err)
+
+		ur, err := s.UseDatabase(metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token)),
+			&schema.Database{DatabaseName: DefaultdbName})
+		require.NoError(t, err)
+
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", ur.Token))
+	}
+
+	// other users can neither confirm transactions nor fetch them on behalf of a replica
+	readerCtx := userCtx("reader")
+
+	_, err = s.ConfirmReplication(readerCtx, &schema.ConfirmReplicationRequest{ReplicaId: "replica2", TxId: state.TxId, TxHash: state.TxHash})
+	require.Equal(t, ErrPermissionDenied, err)
+
+	err = s.ExportTx(&schema.TxRequest{Tx: state.TxId, ReplicaId: "replica2"}, &exportTxServer{ctx: readerCtx})
+	require.Equal(t, ErrNotOwnReplica, err)
+
+	err = s.ExportTx(&schema.TxRequest{Tx: state.TxId}, &exportTxServer{ctx: readerCtx})
+	require.NoError(t, err)
+
+	_, err = s.ConfirmReplication(userCtx("dbadmin"), &schema.ConfirmReplicationRequest{ReplicaId: "replica2", TxId: state.TxId, TxHash: state.TxHash})
+	require.Equal(t, ErrNotOwnReplica, err)
+
+	res, err = s.ReplicationStatus(adminCtx, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Replicas, 1)
+}
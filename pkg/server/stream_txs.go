@@ -0,0 +1,35 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// StreamTxs replays the committed transactions from the requested one and then follows the new commits
+func (s *ImmuServer) StreamTxs(req *schema.StreamTxsRequest, txsServer schema.ImmuService_StreamTxsServer) error {
+	if req == nil || txsServer == nil {
+		return ErrIllegalArguments
+	}
+
+	db, err := s.getDBFromCtx(txsServer.Context(), "StreamTxs")
+	if err != nil {
+		return err
+	}
+
+	return db.StreamTxs(req, txsServer.Context().Done(), txsServer.Send)
+}
@@ -157,6 +157,7 @@ func (s *ImmuServer) StreamVerifiableGet(req *schema.VerifiableGetRequest, str s
 		Tx:           vEntry.GetEntry().GetTx(),
 		Key:          vEntry.GetEntry().GetKey(),
 		ReferencedBy: vEntry.GetEntry().GetReferencedBy(),
+		Metadata:     vEntry.GetEntry().GetMetadata(),
 	}
 
 	entryWithoutValueProto, err := proto.Marshal(&entryWithoutValue)
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/utilities"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// subscribeWSPath is the path of the WebSocket endpoint streaming the subscribed entries
+const subscribeWSPath = "/api/ws/subscribe"
+
+// subscribeWSTokenParam is the query parameter holding the token, as browsers can not set headers on WebSockets
+const subscribeWSTokenParam = "token"
+
+// subscribeWSError is the last event sent when the subscription fails
+type subscribeWSError struct {
+	Error string `json:"error"`
+}
+
+// subscribeWSHandler streams over a WebSocket the entries committed with keys having the requested prefix,
+// as JSON encoded verifiable entries. The subscription is taken from the query parameters, e.g.
+// /api/ws/subscribe?prefix=<base64 prefix>&withProof=true&token=<token>
+func subscribeWSHandler(s schema.ImmuServiceServer) http.Handler {
+	marshaler := &runtime.JSONPb{OrigName: true}
+	filter := utilities.NewDoubleArray([][]string{{subscribeWSTokenParam}})
+
+	return websocket.Server{
+		// the token is required to subscribe, so any origin is accepted
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			r := ws.Request()
+
+			sendError := func(err error) {
+				b, _ := json.Marshal(&subscribeWSError{Error: err.Error()})
+				websocket.Message.Send(ws, string(b))
+			}
+
+			req := &schema.SubscribeRequest{}
+
+			err := runtime.PopulateQueryParameters(req, r.URL.Query(), filter)
+			if err != nil {
+				sendError(err)
+				return
+			}
+
+			md := metadata.MD{}
+
+			if token := r.URL.Query().Get(subscribeWSTokenParam); token != "" {
+				md.Set("authorization", token)
+			} else if token := r.Header.Get("Authorization"); token != "" {
+				md.Set("authorization", token)
+			}
+
+			ctx, cancel := context.WithCancel(metadata.NewIncomingContext(r.Context(), md))
+			defer cancel()
+
+			// the subscription ends when the client closes the connection
+			go func() {
+				var msg string
+				for websocket.Message.Receive(ws, &msg) == nil {
+				}
+				cancel()
+			}()
+
+			err = s.Subscribe(req, &wsSubscribeServer{ctx: ctx, ws: ws, marshaler: marshaler})
+			if err != nil && ctx.Err() == nil {
+				sendError(err)
+			}
+		},
+	}
+}
+
+// wsSubscribeServer sends the subscribed entries to the WebSocket
+type wsSubscribeServer struct {
+	grpc.ServerStream
+
+	ctx       context.Context
+	ws        *websocket.Conn
+	marshaler runtime.Marshaler
+}
+
+func (s *wsSubscribeServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *wsSubscribeServer) Send(entry *schema.VerifiableEntry) error {
+	b, err := s.marshaler.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return websocket.Message.Send(s.ws, string(b))
+}
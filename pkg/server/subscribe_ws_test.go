@@ -0,0 +1,124 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestSubscribeWebSocket(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_subscribe_ws").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+	defer os.RemoveAll(options.Dir)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	webServer, err := StartWebServer("127.0.0.1:8092", nil, s, nil, false, "", &mockLogger{})
+	require.NoError(t, err)
+	defer webServer.Close()
+
+	require.Eventually(t, func() bool {
+		_, err = http.Get("http://127.0.0.1:8092")
+		return err == nil
+	}, 1*time.Second, 30*time.Millisecond)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	subscribe := func(params url.Values) *websocket.Conn {
+		ws, err := websocket.Dial("ws://127.0.0.1:8092"+subscribeWSPath+"?"+params.Encode(), "", "http://127.0.0.1:8092")
+		require.NoError(t, err)
+		return ws
+	}
+
+	t.Run("unauthenticated subscriptions should fail", func(t *testing.T) {
+		ws := subscribe(url.Values{})
+		defer ws.Close()
+
+		var event subscribeWSError
+		err = websocket.JSON.Receive(ws, &event)
+		require.NoError(t, err)
+		require.NotEmpty(t, event.Error)
+	})
+
+	ws := subscribe(url.Values{
+		subscribeWSTokenParam: {lr.Token},
+		"prefix":              {base64.StdEncoding.EncodeToString([]byte("sensor"))},
+		"withProof":           {"true"},
+	})
+	defer ws.Close()
+
+	// the subscription starts after the connection, the set is retried until the first entry arrives
+	received := make(chan string, 1)
+
+	go func() {
+		var msg string
+		if websocket.Message.Receive(ws, &msg) == nil {
+			received <- msg
+		}
+	}()
+
+	var msg string
+
+	require.Eventually(t, func() bool {
+		_, err := s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{
+			{Key: []byte("other1"), Value: []byte("value0")},
+			{Key: []byte("sensor1"), Value: []byte("value1")},
+		}})
+		require.NoError(t, err)
+
+		select {
+		case msg = <-received:
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var ventry schema.VerifiableEntry
+
+	err = (&runtime.JSONPb{OrigName: true}).Unmarshal([]byte(msg), &ventry)
+	require.NoError(t, err)
+	require.Equal(t, []byte("sensor1"), ventry.Entry.Key)
+	require.Equal(t, []byte("value1"), ventry.Entry.Value)
+	require.NotNil(t, ventry.VerifiableTx)
+	require.NotNil(t, ventry.InclusionProof)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	sdReady    = "READY=1"
+	sdStopping = "STOPPING=1"
+	sdWatchdog = "WATCHDOG=1"
+)
+
+// sdNotify sends a state notification to the service manager, as described in sd_notify(3).
+// It returns false without error when immudb was not started with notify support
+func sdNotify(state string) (bool, error) {
+	socketAddr := &net.UnixAddr{
+		Name: os.Getenv("NOTIFY_SOCKET"),
+		Net:  "unixgram",
+	}
+
+	if socketAddr.Name == "" {
+		return false, nil
+	}
+
+	conn, err := net.DialUnix(socketAddr.Net, nil, socketAddr)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// sdWatchdogInterval returns the keepalive interval expected by the service manager,
+// zero is returned when the watchdog is not enabled for this process
+func sdWatchdogInterval() (time.Duration, error) {
+	wusec := os.Getenv("WATCHDOG_USEC")
+	if wusec == "" {
+		return 0, nil
+	}
+
+	usec, err := strconv.ParseInt(wusec, 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, fmt.Errorf("invalid WATCHDOG_USEC value '%s'", wusec)
+	}
+
+	wpid := os.Getenv("WATCHDOG_PID")
+	if wpid != "" {
+		pid, err := strconv.Atoi(wpid)
+		if err != nil {
+			return 0, fmt.Errorf("invalid WATCHDOG_PID value '%s'", wpid)
+		}
+
+		if pid != os.Getpid() {
+			return 0, nil
+		}
+	}
+
+	return time.Duration(usec) * time.Microsecond, nil
+}
+
+// notifySystemdReady informs the service manager that all databases were loaded
+// and starts sending watchdog keepalives if requested
+func (s *ImmuServer) notifySystemdReady() {
+	notified, err := sdNotify(fmt.Sprintf("%s\nMAINPID=%d", sdReady, os.Getpid()))
+	if err != nil {
+		s.Logger.Warningf("Unable to notify readiness to systemd: %v", err)
+		return
+	}
+	if !notified {
+		return
+	}
+
+	interval, err := sdWatchdogInterval()
+	if err != nil {
+		s.Logger.Warningf("Systemd watchdog disabled: %v", err)
+		return
+	}
+	if interval == 0 {
+		return
+	}
+
+	s.Logger.Infof("Systemd watchdog enabled, keepalives will be sent every %s", interval/2)
+
+	s.watchdogDone = make(chan struct{})
+
+	go s.systemdWatchdog(interval, s.watchdogDone)
+}
+
+// notifySystemdStopping informs the service manager that immudb is shutting down
+func (s *ImmuServer) notifySystemdStopping() {
+	if s.watchdogDone != nil {
+		close(s.watchdogDone)
+		s.watchdogDone = nil
+	}
+
+	if _, err := sdNotify(sdStopping); err != nil {
+		s.Logger.Warningf("Unable to notify shutdown to systemd: %v", err)
+	}
+}
+
+// systemdWatchdog sends keepalives only while the health check succeeds in time,
+// so a wedged instance gets restarted by the service manager
+func (s *ImmuServer) systemdWatchdog(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+
+	healthCh := make(chan error, 1)
+	checking := false
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		// a health check still in progress from a previous tick means immudb is not responsive
+		if !checking {
+			checking = true
+			go func() { healthCh <- s.checkDatabasesHealth() }()
+		}
+
+		select {
+		case err := <-healthCh:
+			checking = false
+			if err != nil {
+				s.Logger.Errorf("Health check failed, skipping watchdog keepalive: %v", err)
+				continue
+			}
+
+			if _, err := sdNotify(sdWatchdog); err != nil {
+				s.Logger.Warningf("Unable to send watchdog keepalive to systemd: %v", err)
+			}
+		case <-time.After(interval / 4):
+			s.Logger.Warningf("Health check is taking too long, skipping watchdog keepalive")
+		}
+	}
+}
+
+// checkDatabasesHealth ensures every loaded database is able to serve requests
+func (s *ImmuServer) checkDatabasesHealth() error {
+	if s.sysDB != nil {
+		if _, err := s.sysDB.CurrentState(); err != nil {
+			return fmt.Errorf("database '%s': %w", SystemdbName, err)
+		}
+	}
+
+	for i := 0; i < s.dbList.Length(); i++ {
+		db := s.dbList.GetByIndex(int64(i))
+		if db == nil {
+			continue
+		}
+
+		if _, err := db.CurrentState(); err != nil {
+			return fmt.Errorf("database '%s': %w", db.GetOptions().GetDbName(), err)
+		}
+	}
+
+	return nil
+}
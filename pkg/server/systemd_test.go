@@ -0,0 +1,157 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/stretchr/testify/require"
+)
+
+func listenNotifySocket(t *testing.T) (*net.UnixConn, func()) {
+	dir, err := ioutil.TempDir("", "immudb_sd")
+	require.NoError(t, err)
+
+	addr := &net.UnixAddr{Name: filepath.Join(dir, "notify.sock"), Net: "unixgram"}
+
+	conn, err := net.ListenUnixgram(addr.Net, addr)
+	require.NoError(t, err)
+
+	os.Setenv("NOTIFY_SOCKET", addr.Name)
+
+	return conn, func() {
+		os.Unsetenv("NOTIFY_SOCKET")
+		conn.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func readNotification(t *testing.T, conn *net.UnixConn) string {
+	b := make([]byte, 256)
+
+	err := conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	require.NoError(t, err)
+
+	n, err := conn.Read(b)
+	require.NoError(t, err)
+
+	return string(b[:n])
+}
+
+func TestSdNotify(t *testing.T) {
+	os.Unsetenv("NOTIFY_SOCKET")
+
+	notified, err := sdNotify(sdReady)
+	require.NoError(t, err)
+	require.False(t, notified)
+
+	os.Setenv("NOTIFY_SOCKET", "/nonexistent/notify.sock")
+
+	_, err = sdNotify(sdReady)
+	require.Error(t, err)
+
+	conn, closer := listenNotifySocket(t)
+	defer closer()
+
+	notified, err = sdNotify(sdReady)
+	require.NoError(t, err)
+	require.True(t, notified)
+	require.Equal(t, sdReady, readNotification(t, conn))
+}
+
+func TestSdWatchdogInterval(t *testing.T) {
+	defer os.Unsetenv("WATCHDOG_USEC")
+	defer os.Unsetenv("WATCHDOG_PID")
+
+	interval, err := sdWatchdogInterval()
+	require.NoError(t, err)
+	require.Zero(t, interval)
+
+	os.Setenv("WATCHDOG_USEC", "invalid")
+	_, err = sdWatchdogInterval()
+	require.Error(t, err)
+
+	os.Setenv("WATCHDOG_USEC", "2000000")
+	interval, err = sdWatchdogInterval()
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Second, interval)
+
+	os.Setenv("WATCHDOG_PID", "invalid")
+	_, err = sdWatchdogInterval()
+	require.Error(t, err)
+
+	os.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	interval, err = sdWatchdogInterval()
+	require.NoError(t, err)
+	require.Zero(t, interval)
+
+	os.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+	interval, err = sdWatchdogInterval()
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Second, interval)
+}
+
+func TestSystemdWatchdog(t *testing.T) {
+	dbRootpath := database.DefaultOption().GetDbRootPath()
+	s := DefaultServer()
+
+	defer os.RemoveAll(s.Options.Dir)
+
+	s.WithOptions(s.Options.WithAuth(false))
+
+	err := s.loadSystemDatabase(dbRootpath, nil, s.Options.AdminPassword)
+	require.NoError(t, err)
+
+	err = s.loadDefaultDatabase(dbRootpath, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, s.checkDatabasesHealth())
+
+	conn, closer := listenNotifySocket(t)
+	defer closer()
+
+	os.Setenv("WATCHDOG_USEC", "100000")
+	defer os.Unsetenv("WATCHDOG_USEC")
+
+	s.notifySystemdReady()
+	require.NotNil(t, s.watchdogDone)
+
+	require.True(t, strings.HasPrefix(readNotification(t, conn), sdReady))
+	require.Equal(t, sdWatchdog, readNotification(t, conn))
+
+	s.notifySystemdStopping()
+	require.Nil(t, s.watchdogDone)
+
+	for {
+		msg := readNotification(t, conn)
+		if msg == sdStopping {
+			break
+		}
+		require.Equal(t, sdWatchdog, msg)
+	}
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
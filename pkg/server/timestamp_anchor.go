@@ -0,0 +1,232 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/codenotary/immudb/pkg/tsa"
+)
+
+// tsaRequestTimeout bounds each request sent to the time-stamp authority
+const tsaRequestTimeout = 30 * time.Second
+
+// timestampToken is the time-stamp token of a database root as stored in the system database.
+// Every token is written as a new revision of the same key, so the history of the key is the token chain
+type timestampToken struct {
+	TxID    uint64    `json:"txId"`
+	TxHash  []byte    `json:"txHash"`
+	GenTime time.Time `json:"genTime"`
+	Token   []byte    `json:"token"`
+}
+
+func timestampTokenKey(database string) []byte {
+	key := make([]byte, 1+len(database))
+	key[0] = KeyPrefixTimestampToken
+	copy(key[1:], []byte(database))
+	return key
+}
+
+// startTimestampAnchoring runs in background the periodic submission of the database roots to the time-stamp authority
+func (s *ImmuServer) startTimestampAnchoring() {
+	if s.Options.TSAURL == "" || s.Options.TSAInterval <= 0 {
+		return
+	}
+
+	if s.sysDB.IsReplica() {
+		s.Logger.Warningf("Time-stamping of database roots is disabled on replicas")
+		return
+	}
+
+	if s.tsaClient == nil {
+		tsaClient, err := tsa.NewHTTPClient(s.Options.TSAURL, tsaRequestTimeout)
+		if err != nil {
+			s.Logger.Errorf("Unable to create time-stamp authority client: %v", err)
+			return
+		}
+
+		s.tsaClient = tsaClient
+	}
+
+	s.tsaDone = make(chan struct{})
+	s.tsaFinished = make(chan struct{})
+
+	go s.timestampAnchoring(time.Duration(s.Options.TSAInterval)*time.Second, s.tsaDone, s.tsaFinished)
+}
+
+// stopTimestampAnchoring interrupts the anchoring and waits for the submission in progress, if any
+func (s *ImmuServer) stopTimestampAnchoring() {
+	if s.tsaDone == nil {
+		return
+	}
+
+	close(s.tsaDone)
+	<-s.tsaFinished
+
+	s.tsaDone = nil
+	s.tsaFinished = nil
+}
+
+func (s *ImmuServer) timestampAnchoring(interval time.Duration, done <-chan struct{}, finished chan<- struct{}) {
+	defer close(finished)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		s.anchorTimestamps(done)
+	}
+}
+
+// anchorTimestamps time-stamps the roots of the opened databases which changed since their last token,
+// databases not loaded yet are skipped
+func (s *ImmuServer) anchorTimestamps(done <-chan struct{}) {
+	for i := 0; i < s.dbList.Length(); i++ {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		db := s.dbList.GetByIndex(int64(i))
+		if db == nil {
+			continue
+		}
+
+		anchored, err := s.anchorTimestamp(db)
+		if err != nil {
+			s.Logger.Warningf("Unable to time-stamp root of database '%s': %v", db.GetName(), err)
+			continue
+		}
+
+		if anchored != nil {
+			s.Logger.Infof("Root of database '%s' at tx %d time-stamped at %v", db.GetName(), anchored.TxID, anchored.GenTime)
+		}
+	}
+}
+
+// anchorTimestamp submits the current root of db to the time-stamp authority and stores the returned token.
+// It returns nil when the root is already time-stamped
+func (s *ImmuServer) anchorTimestamp(db database.DB) (*timestampToken, error) {
+	state, err := db.CurrentState()
+	if err != nil {
+		return nil, err
+	}
+
+	if state.TxId == 0 {
+		return nil, nil
+	}
+
+	key := timestampTokenKey(db.GetName())
+
+	e, err := s.sysDB.Get(&schema.KeyRequest{Key: key})
+	if err == nil {
+		var last timestampToken
+
+		err = json.Unmarshal(e.Value, &last)
+		if err != nil {
+			return nil, err
+		}
+
+		if last.TxID == state.TxId {
+			return nil, nil
+		}
+	} else if err != store.ErrKeyNotFound {
+		return nil, err
+	}
+
+	token, err := s.tsaClient.Timestamp(schema.DigestFrom(state.TxHash))
+	if err != nil {
+		return nil, err
+	}
+
+	anchored := &timestampToken{
+		TxID:    state.TxId,
+		TxHash:  state.TxHash,
+		GenTime: token.GenTime,
+		Token:   token.Raw,
+	}
+
+	tokenData, err := json.Marshal(anchored)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.sysDB.Set(&schema.SetRequest{KVs: []*schema.KeyValue{{Key: key, Value: tokenData}}})
+	if err != nil {
+		return nil, err
+	}
+
+	return anchored, nil
+}
+
+// TimestampTokens returns the chain of time-stamp tokens of the roots of the database in use
+func (s *ImmuServer) TimestampTokens(ctx context.Context, req *schema.TimestampTokensRequest) (*schema.TimestampTokenList, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	db, err := s.getDBFromCtx(ctx, "TimestampTokens")
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.sysDB.History(&schema.HistoryRequest{
+		Key:   timestampTokenKey(db.GetName()),
+		Limit: req.Limit,
+		Desc:  req.Desc,
+	})
+	if err == store.ErrKeyNotFound || err == store.ErrNoMoreEntries {
+		return &schema.TimestampTokenList{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := &schema.TimestampTokenList{
+		Tokens: make([]*schema.TimestampToken, len(entries.Entries)),
+	}
+
+	for i, e := range entries.Entries {
+		var token timestampToken
+
+		err = json.Unmarshal(e.Value, &token)
+		if err != nil {
+			return nil, err
+		}
+
+		list.Tokens[i] = &schema.TimestampToken{
+			TxId:    token.TxID,
+			TxHash:  token.TxHash,
+			GenTime: token.GenTime.Unix(),
+			Token:   token.Token,
+		}
+	}
+
+	return list, nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/tsa"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type mockTSAClient struct {
+	err error
+}
+
+func (c *mockTSAClient) Timestamp(digest [sha256.Size]byte) (*tsa.Token, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &tsa.Token{
+		GenTime:       time.Unix(1630490400, 0),
+		HashedMessage: digest[:],
+		Raw:           append([]byte("token"), digest[:]...),
+	}, nil
+}
+
+func TestServerTimestampAnchoring(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_timestamp_anchoring").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithTSAURL("http://tsa.example").
+		WithTSAInterval(1)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.TimestampTokens(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	tokens, err := s.TimestampTokens(ctx, &schema.TimestampTokensRequest{})
+	require.NoError(t, err)
+	require.Empty(t, tokens.Tokens)
+
+	tsaClient := &mockTSAClient{}
+	s.tsaClient = tsaClient
+
+	hdr1, err := s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key1"), Value: []byte("value1")}}})
+	require.NoError(t, err)
+
+	s.anchorTimestamps(nil)
+
+	// roots already time-stamped are not submitted again
+	s.anchorTimestamps(nil)
+
+	tsaClient.err = errors.New("tsa unavailable")
+
+	hdr2, err := s.Set(ctx, &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("key2"), Value: []byte("value2")}}})
+	require.NoError(t, err)
+
+	s.anchorTimestamps(nil)
+
+	tokens, err = s.TimestampTokens(ctx, &schema.TimestampTokensRequest{})
+	require.NoError(t, err)
+	require.Len(t, tokens.Tokens, 1)
+	require.Equal(t, hdr1.Id, tokens.Tokens[0].TxId)
+	require.Equal(t, int64(1630490400), tokens.Tokens[0].GenTime)
+
+	state, err := s.dbList.GetByIndex(defaultDbIndex).CurrentState()
+	require.NoError(t, err)
+	require.NotEqual(t, state.TxHash, tokens.Tokens[0].TxHash)
+
+	tsaClient.err = nil
+
+	s.startTimestampAnchoring()
+
+	require.Eventually(t, func() bool {
+		tokens, err := s.TimestampTokens(ctx, &schema.TimestampTokensRequest{Desc: true})
+		require.NoError(t, err)
+
+		return len(tokens.Tokens) == 2
+	}, 10*time.Second, 100*time.Millisecond)
+
+	s.stopTimestampAnchoring()
+	s.stopTimestampAnchoring()
+
+	tokens, err = s.TimestampTokens(ctx, &schema.TimestampTokensRequest{Desc: true})
+	require.NoError(t, err)
+	require.Len(t, tokens.Tokens, 2)
+	require.Equal(t, hdr2.Id, tokens.Tokens[0].TxId)
+	require.Equal(t, hdr1.Id, tokens.Tokens[1].TxId)
+
+	state, err = s.dbList.GetByIndex(defaultDbIndex).CurrentState()
+	require.NoError(t, err)
+	require.Equal(t, state.TxHash, tokens.Tokens[0].TxHash)
+	require.Equal(t, append([]byte("token"), state.TxHash...), tokens.Tokens[0].Token)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/rs/xid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ongoingTxTimeout is the time after which an idle interactive transaction is rolled back
+const ongoingTxTimeout = 5 * time.Minute
+
+// maxOngoingTxs is the max number of interactive transactions open at the same time in the server
+const maxOngoingTxs = 1000
+
+var (
+	ErrTxNotFound            = status.Error(codes.NotFound, "transaction not found")
+	ErrMaxOngoingTxsExceeded = status.Error(codes.ResourceExhausted, "max number of ongoing transactions exceeded")
+)
+
+// ongoingTx is an interactive transaction bound to the session of the user who started it
+type ongoingTx struct {
+	tx         *database.OngoingTx
+	username   string
+	dbName     string
+	lastUsedAt time.Time
+}
+
+type ongoingTxs struct {
+	m map[string]*ongoingTx
+	sync.Mutex
+}
+
+// BeginTx starts an interactive transaction on the database in use
+func (s *ImmuServer) BeginTx(ctx context.Context, _ *empty.Empty) (*schema.BeginTxResponse, error) {
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	db, err := s.getDBFromCtx(ctx, "BeginTx")
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.NewOngoingTx()
+	if err != nil {
+		return nil, err
+	}
+
+	s.ongoingTxs.Lock()
+	defer s.ongoingTxs.Unlock()
+
+	s.removeIdleTxs(time.Now())
+
+	if len(s.ongoingTxs.m) >= maxOngoingTxs {
+		return nil, ErrMaxOngoingTxsExceeded
+	}
+
+	if s.ongoingTxs.m == nil {
+		s.ongoingTxs.m = make(map[string]*ongoingTx)
+	}
+
+	id := xid.New().String()
+
+	s.ongoingTxs.m[id] = &ongoingTx{
+		tx:         tx,
+		username:   sessionUsername(ctx),
+		dbName:     db.GetName(),
+		lastUsedAt: time.Now(),
+	}
+
+	return &schema.BeginTxResponse{TransactionId: id}, nil
+}
+
+// TxSet stages key-value pairs in an interactive transaction
+func (s *ImmuServer) TxSet(ctx context.Context, req *schema.TxSetRequest) (*empty.Empty, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	tx, err := s.getOngoingTx(ctx, "TxSet", req.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(empty.Empty), tx.Set(req.KVs)
+}
+
+// TxGet reads a key as seen by an interactive transaction, including its uncommitted writes
+func (s *ImmuServer) TxGet(ctx context.Context, req *schema.TxGetRequest) (*schema.Entry, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	tx, err := s.getOngoingTx(ctx, "TxGet", req.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.Get(req.Key)
+}
+
+// Commit atomically writes the entries of an interactive transaction
+func (s *ImmuServer) Commit(ctx context.Context, req *schema.TransactionRequest) (*schema.TxMetadata, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	tx, err := s.getOngoingTx(ctx, "Commit", req.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.removeOngoingTx(req.TransactionId)
+
+	return tx.Commit()
+}
+
+// Rollback discards an interactive transaction
+func (s *ImmuServer) Rollback(ctx context.Context, req *schema.TransactionRequest) (*empty.Empty, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	tx, err := s.getOngoingTx(ctx, "Rollback", req.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.removeOngoingTx(req.TransactionId)
+
+	return new(empty.Empty), tx.Rollback()
+}
+
+// getOngoingTx returns the transaction provided it was started by the same user on the database in use
+func (s *ImmuServer) getOngoingTx(ctx context.Context, methodName string, id string) (*database.OngoingTx, error) {
+	if s.Options.GetMaintenance() {
+		return nil, ErrNotAllowedInMaintenanceMode
+	}
+
+	db, err := s.getDBFromCtx(ctx, methodName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ongoingTxs.Lock()
+	defer s.ongoingTxs.Unlock()
+
+	now := time.Now()
+
+	s.removeIdleTxs(now)
+
+	otx, ok := s.ongoingTxs.m[id]
+	if !ok || otx.username != sessionUsername(ctx) || otx.dbName != db.GetName() {
+		return nil, ErrTxNotFound
+	}
+
+	otx.lastUsedAt = now
+
+	return otx.tx, nil
+}
+
+func (s *ImmuServer) removeOngoingTx(id string) {
+	s.ongoingTxs.Lock()
+	defer s.ongoingTxs.Unlock()
+
+	delete(s.ongoingTxs.m, id)
+}
+
+// removeIdleTxs rolls back the transactions not used for longer than the timeout, the caller must hold the lock
+func (s *ImmuServer) removeIdleTxs(now time.Time) {
+	for id, otx := range s.ongoingTxs.m {
+		if now.Sub(otx.lastUsedAt) > ongoingTxTimeout {
+			otx.tx.Rollback()
+			delete(s.ongoingTxs.m, id)
+		}
+	}
+}
+
+// sessionUsername returns the user logged in, empty when running without authentication
+func sessionUsername(ctx context.Context) string {
+	jsUser, err := auth.GetLoggedInUser(ctx)
+	if err != nil {
+		return ""
+	}
+
+	return jsUser.Username
+}
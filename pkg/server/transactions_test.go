@@ -0,0 +1,127 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestServerInteractiveTx(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_interactive_tx").
+		WithListener(bufconn.Listen(1024 * 1024)).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithAdminPassword(auth.SysAdminPassword)
+	defer os.RemoveAll(options.Dir)
+
+	s := DefaultServer().WithOptions(options).(*ImmuServer)
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	lr, err := s.Login(context.Background(), &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	_, err = s.TxSet(ctx, nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = s.TxSet(ctx, &schema.TxSetRequest{TransactionId: "unknown"})
+	require.Equal(t, ErrTxNotFound, err)
+
+	res, err := s.BeginTx(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.NotEmpty(t, res.TransactionId)
+
+	_, err = s.TxSet(ctx, &schema.TxSetRequest{
+		TransactionId: res.TransactionId,
+		KVs:           []*schema.KeyValue{{Key: []byte("order1"), Value: []byte("pending")}},
+	})
+	require.NoError(t, err)
+
+	entry, err := s.TxGet(ctx, &schema.TxGetRequest{TransactionId: res.TransactionId, Key: []byte("order1")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("pending"), entry.Value)
+
+	_, err = s.Get(ctx, &schema.KeyRequest{Key: []byte("order1")})
+	require.Error(t, err)
+
+	// transactions are bound to the database they were started on
+	_, err = s.CreateDatabaseWith(ctx, &schema.DatabaseSettings{DatabaseName: "otherdb"})
+	require.NoError(t, err)
+
+	ur, err := s.UseDatabase(ctx, &schema.Database{DatabaseName: "otherdb"})
+	require.NoError(t, err)
+
+	otherCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", ur.Token))
+
+	_, err = s.TxGet(otherCtx, &schema.TxGetRequest{TransactionId: res.TransactionId, Key: []byte("order1")})
+	require.Equal(t, ErrTxNotFound, err)
+
+	md, err := s.Commit(ctx, &schema.TransactionRequest{TransactionId: res.TransactionId})
+	require.NoError(t, err)
+
+	_, err = s.Commit(ctx, &schema.TransactionRequest{TransactionId: res.TransactionId})
+	require.Equal(t, ErrTxNotFound, err)
+
+	entry, err = s.Get(ctx, &schema.KeyRequest{Key: []byte("order1"), SinceTx: md.Id})
+	require.NoError(t, err)
+	require.Equal(t, []byte("pending"), entry.Value)
+
+	res, err = s.BeginTx(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+
+	_, err = s.Rollback(ctx, &schema.TransactionRequest{TransactionId: res.TransactionId})
+	require.NoError(t, err)
+
+	_, err = s.Rollback(ctx, &schema.TransactionRequest{TransactionId: res.TransactionId})
+	require.Equal(t, ErrTxNotFound, err)
+
+	// idle transactions get rolled back
+	res, err = s.BeginTx(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+
+	s.ongoingTxs.Lock()
+	otx := s.ongoingTxs.m[res.TransactionId]
+	otx.lastUsedAt = otx.lastUsedAt.Add(-2 * ongoingTxTimeout)
+	s.ongoingTxs.Unlock()
+
+	_, err = s.TxGet(ctx, &schema.TxGetRequest{TransactionId: res.TransactionId, Key: []byte("order1")})
+	require.Equal(t, ErrTxNotFound, err)
+
+	err = otx.tx.Rollback()
+	require.Equal(t, database.ErrTxClosed, err)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+}
@@ -22,8 +22,10 @@ import (
 	"net/http"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/codenotary/immudb/embedded/remotestorage"
+	"github.com/codenotary/immudb/pkg/anchor"
 	pgsqlsrv "github.com/codenotary/immudb/pkg/pgsql/server"
 	"github.com/codenotary/immudb/pkg/stream"
 
@@ -33,8 +35,10 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/backup"
 	"github.com/codenotary/immudb/pkg/immuos"
 	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/tsa"
 )
 
 // userDatabasePairs keeps an associacion of username to userdata
@@ -47,6 +51,15 @@ type usernameToUserdataMap struct {
 const defaultDbIndex = 0
 const sysDBIndex = int64(math.MaxInt64)
 
+// archiveDirName is the directory, inside the data dir, where deleted databases get archived
+const archiveDirName = ".archive"
+
+// backupDirName is the directory, inside the data dir, where server-side backups are written by default
+const backupDirName = ".backups"
+
+// clusterDirName is the directory, inside the data dir, holding the Raft state of the databases in cluster mode
+const clusterDirName = ".cluster"
+
 // ImmuServer ...
 type ImmuServer struct {
 	OS          immuos.OS
@@ -71,6 +84,75 @@ type ImmuServer struct {
 	PgsqlSrv             pgsqlsrv.Server
 
 	remoteStorage remotestorage.Storage
+	backupStorage backup.Storage
+	backupKeys    backup.KeyProvider
+
+	watchdogDone chan struct{}
+
+	warmUpDone     chan struct{}
+	warmUpFinished chan struct{}
+
+	backupScheduler *backupScheduler
+
+	sweeperDone     chan struct{}
+	sweeperFinished chan struct{}
+
+	indexVerifierDone     chan struct{}
+	indexVerifierFinished chan struct{}
+
+	replicaVerifierDone     chan struct{}
+	replicaVerifierFinished chan struct{}
+
+	failoverDone     chan struct{}
+	failoverFinished chan struct{}
+	// failedProbes counts the consecutive failed probes of the primary of each replica with automatic failover
+	failedProbes map[string]int
+
+	// replicationEvents delivers the replication lifecycle events to streams and webhooks
+	replicationEvents        *replicationEventHub
+	replicationHooksDone     chan struct{}
+	replicationHooksFinished chan struct{}
+
+	// replicationMux serializes the changes of the replication role of the databases
+	replicationMux sync.Mutex
+
+	// diverged holds the replicas found diverging from their primary
+	diverged    map[string]*replicaDivergence
+	divergedMux sync.Mutex
+
+	// ReplicationGrpcServer serves replication through its own mutually authenticated listener
+	replicationListener   net.Listener
+	ReplicationGrpcServer *grpc.Server
+
+	cluster *cluster
+
+	tsaClient   tsa.Client
+	tsaDone     chan struct{}
+	tsaFinished chan struct{}
+
+	anchorPublisher anchor.Publisher
+	anchorDone      chan struct{}
+	anchorFinished  chan struct{}
+
+	checkerMux      sync.Mutex
+	checkerInterval int
+	checkerDone     chan struct{}
+	checkerFinished chan struct{}
+
+	checkerIORateLimit uint64
+	checkerRateMux     sync.RWMutex
+
+	// degraded holds the databases where corruption was detected and when,
+	// checkedUntil the last verified transaction of each database
+	degraded     map[string]time.Time
+	checkedUntil map[string]uint64
+	lastScans    map[string]*corruptionScan
+	runningScans map[string]*corruptionScan
+	degradedMux  sync.Mutex
+
+	ongoingTxs ongoingTxs
+
+	plugins []Plugin
 }
 
 // DefaultServer ...
@@ -84,6 +166,12 @@ func DefaultServer() *ImmuServer {
 		userdata:             &usernameToUserdataMap{Userdata: make(map[string]*auth.User)},
 		GrpcServer:           grpc.NewServer(),
 		StreamServiceFactory: stream.NewStreamServiceFactory(DefaultOptions().StreamChunkSize),
+		degraded:             make(map[string]time.Time),
+		checkedUntil:         make(map[string]uint64),
+		lastScans:            make(map[string]*corruptionScan),
+		runningScans:         make(map[string]*corruptionScan),
+		diverged:             make(map[string]*replicaDivergence),
+		replicationEvents:    newReplicationEventHub(),
 	}
 }
 
@@ -117,7 +117,7 @@ func (s *ImmuServer) CreateUser(ctx context.Context, r *schema.CreateUserRequest
 
 	//check permission is a known value
 	if (r.Permission == auth.PermissionNone) ||
-		(r.Permission > auth.PermissionRW && r.Permission < auth.PermissionAdmin) {
+		(r.Permission > auth.PermissionReplication && r.Permission < auth.PermissionAdmin) {
 		return nil, fmt.Errorf("unrecognized permission")
 	}
 
@@ -209,9 +209,10 @@ func (s *ImmuServer) ListUsers(ctx context.Context, req *empty.Empty) (*schema.U
 
 		return userlist, nil
 
-	} else if loggedInuser.WhichPermission(s.dbList.GetByIndex(dbInd).GetOptions().GetDbName()) == auth.PermissionAdmin {
+	} else if selectedDb := s.dbList.GetByIndex(dbInd); selectedDb != nil &&
+		loggedInuser.WhichPermission(selectedDb.GetOptions().GetDbName()) == auth.PermissionAdmin {
 		// for admin users return only users for the database that is has selected
-		selectedDbname := s.dbList.GetByIndex(dbInd).GetOptions().GetDbName()
+		selectedDbname := selectedDb.GetOptions().GetDbName()
 		userlist := &schema.UserList{}
 
 		for i := 0; i < len(itemList.Entries); i++ {
@@ -366,7 +367,7 @@ func (s *ImmuServer) ChangePermission(ctx context.Context, r *schema.ChangePermi
 			return nil, status.Errorf(codes.InvalidArgument, "action not recognized")
 		}
 		if (r.Permission == auth.PermissionNone) ||
-			((r.Permission > auth.PermissionRW) &&
+			((r.Permission > auth.PermissionReplication) &&
 				(r.Permission < auth.PermissionAdmin)) {
 			return nil, status.Errorf(codes.InvalidArgument, "unrecognized permission")
 		}
@@ -386,7 +387,7 @@ func (s *ImmuServer) ChangePermission(ctx context.Context, r *schema.ChangePermi
 		return nil, status.Errorf(codes.InvalidArgument, "changing sysadmin permisions is not allowed")
 	}
 
-	if r.Database == SystemdbName && r.Permission == auth.PermissionRW {
+	if r.Database == SystemdbName && (r.Permission == auth.PermissionRW || r.Permission == auth.PermissionReplication) {
 		return nil, ErrPermissionDenied
 	}
 
@@ -519,7 +520,7 @@ func (s *ImmuServer) insertNewUser(username []byte, plainPassword []byte, permis
 		userdata.IsSysAdmin = true
 	}
 
-	if (permission > auth.PermissionRW) && (permission < auth.PermissionAdmin) {
+	if (permission > auth.PermissionReplication) && (permission < auth.PermissionAdmin) {
 		return nil, nil, fmt.Errorf("unknown permission")
 	}
 
@@ -587,6 +588,92 @@ func (s *ImmuServer) saveUser(user *auth.User) error {
 	return logErr(s.Logger, "error saving user: %v", err)
 }
 
+// revokeDatabasePermissions removes the permissions granted on the database from every user
+func (s *ImmuServer) revokeDatabasePermissions(dbname string) error {
+	itemList, err := s.sysDB.Scan(&schema.ScanRequest{
+		Prefix:  []byte{KeyPrefixUser},
+		SinceTx: math.MaxUint64,
+		NoWait:  true,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range itemList.Entries {
+		var user auth.User
+
+		err = json.Unmarshal(item.Value, &user)
+		if err != nil {
+			return err
+		}
+
+		if !user.RevokePermission(dbname) {
+			continue
+		}
+
+		if err := s.saveUser(&user); err != nil {
+			return err
+		}
+
+		//remove user from loggedin users
+		s.removeUserFromLoginList(user.Username)
+	}
+
+	return nil
+}
+
+// copyDatabasePermissions grants every user holding a permission on the source database the same one
+// on the destination database, or just read permission when readOnly is set.
+// It returns the number of users granted a permission
+func (s *ImmuServer) copyDatabasePermissions(srcDB, dstDB string, readOnly bool) (uint32, error) {
+	itemList, err := s.sysDB.Scan(&schema.ScanRequest{
+		Prefix:  []byte{KeyPrefixUser},
+		SinceTx: math.MaxUint64,
+		NoWait:  true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var copied uint32
+
+	for _, item := range itemList.Entries {
+		var user auth.User
+
+		err = json.Unmarshal(item.Value, &user)
+		if err != nil {
+			return copied, err
+		}
+
+		// sysadmin has already access to every database
+		if user.IsSysAdmin {
+			continue
+		}
+
+		permission := user.WhichPermission(srcDB)
+		if permission == auth.PermissionNone {
+			continue
+		}
+
+		if readOnly {
+			permission = auth.PermissionR
+		}
+
+		user.GrantPermission(dstDB, permission)
+
+		if err := s.saveUser(&user); err != nil {
+			return copied, err
+		}
+
+		//remove user from loggedin users
+		s.removeUserFromLoginList(user.Username)
+
+		copied++
+	}
+
+	return copied, nil
+}
+
 func (s *ImmuServer) removeUserFromLoginList(username string) {
 	s.userdata.Lock()
 	defer s.userdata.Unlock()
@@ -4,13 +4,16 @@ import (
 	"context"
 	"crypto/tls"
 	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
 	"github.com/codenotary/immudb/pkg/logger"
 	"github.com/codenotary/immudb/webconsole"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc/peer"
+	"net"
 	"net/http"
 )
 
-func StartWebServer(addr string, tlsConfig *tls.Config, s schema.ImmuServiceServer, l logger.Logger) (*http.Server, error) {
+func StartWebServer(addr string, tlsConfig *tls.Config, s schema.ImmuServiceServer, grpcWeb *GrpcWebHandler, swaggerUI bool, swaggerUIAssetsDir string, l logger.Logger) (*http.Server, error) {
 	proxyMux := runtime.NewServeMux()
 	err := schema.RegisterImmuServiceHandlerServer(context.Background(), proxyMux, s)
 	if err != nil {
@@ -18,14 +21,40 @@ func StartWebServer(addr string, tlsConfig *tls.Config, s schema.ImmuServiceServ
 	}
 
 	webMux := http.NewServeMux()
-	webMux.Handle("/api/", http.StripPrefix("/api", proxyMux))
+	webMux.Handle("/api/", http.StripPrefix("/api", apiAuthGuard(proxyMux, proxyMux)))
+	webMux.Handle(subscribeWSPath, apiAuthGuard(proxyMux, subscribeWSHandler(s)))
+	webMux.Handle(openAPIPath, openAPIHandler())
+
+	if swaggerUI {
+		webMux.Handle(swaggerUIPath, swaggerUIHandler(swaggerUIAssetsDir))
+
+		if swaggerUIAssetsDir != "" {
+			l.Infof("Swagger UI enabled on %s%s, its assets are served from %s", addr, swaggerUIPath, swaggerUIAssetsDir)
+		} else {
+			l.Infof("Swagger UI enabled on %s%s, its assets are loaded from %s", addr, swaggerUIPath, swaggerUIAssetsURL)
+		}
+	}
 
 	err = webconsole.SetupWebconsole(webMux, l, addr)
 	if err != nil {
 		return nil, err
 	}
 
-	httpServer := &http.Server{Addr: addr, Handler: webMux}
+	var handler http.Handler = webMux
+
+	if grpcWeb != nil {
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if grpcWeb.IsGrpcWebRequest(r) {
+				grpcWeb.ServeHTTP(w, r)
+				return
+			}
+			webMux.ServeHTTP(w, r)
+		})
+
+		l.Infof("gRPC-Web enabled on %s", addr)
+	}
+
+	httpServer := &http.Server{Addr: addr, Handler: handler}
 	httpServer.TLSConfig = tlsConfig
 
 	go func() {
@@ -47,3 +76,24 @@ func StartWebServer(addr string, tlsConfig *tls.Config, s schema.ImmuServiceServ
 
 	return httpServer, nil
 }
+
+// apiAuthGuard runs the API requests through the auth checks of the gRPC interceptor before passing them to next,
+// as the gateway invokes the server methods directly. Errors are rendered by mux
+func apiAuthGuard(mux *runtime.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+		if err == nil {
+			ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+		}
+
+		_, err = auth.ServerUnaryInterceptor(ctx, nil, nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return nil, nil
+		})
+		if err != nil {
+			runtime.HTTPError(ctx, mux, &runtime.JSONPb{}, w, r, err)
+		}
+	})
+}
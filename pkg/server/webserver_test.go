@@ -17,10 +17,19 @@ limitations under the License.
 package server
 
 import (
+	"bytes"
 	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/codenotary/immudb/pkg/api/openapi"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/test/bufconn"
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 )
@@ -38,6 +47,9 @@ func TestStartWebServerHTTP(t *testing.T) {
 		"0.0.0.0:8080",
 		tlsConfig,
 		server,
+		nil,
+		false,
+		"",
 		&mockLogger{})
 	require.NoError(t, err)
 	defer webServer.Close()
@@ -79,6 +91,9 @@ EKTcWGekdmdDPsHloRNtsiCa697B2O9IFA==
 		"0.0.0.0:8080",
 		tlsConfig,
 		server,
+		nil,
+		false,
+		"",
 		&mockLogger{})
 	require.NoError(t, err)
 	defer webServer.Close()
@@ -92,3 +107,153 @@ EKTcWGekdmdDPsHloRNtsiCa697B2O9IFA==
 		return err == nil
 	}, 1*time.Second, 30*time.Millisecond)
 }
+
+func TestWebServerAPI(t *testing.T) {
+	options := DefaultOptions().
+		WithDir("data_webserver_api").
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithListener(bufconn.Listen(1024 * 1024))
+	server := DefaultServer().WithOptions(options).(*ImmuServer)
+	defer os.RemoveAll(options.Dir)
+
+	err := server.Initialize()
+	require.NoError(t, err)
+
+	webServer, err := StartWebServer("127.0.0.1:8091", nil, server, nil, false, "", &mockLogger{})
+	require.NoError(t, err)
+	defer webServer.Close()
+
+	client := &http.Client{}
+	assert.Eventually(t, func() bool {
+		_, err = client.Get("http://127.0.0.1:8091")
+		return err == nil
+	}, 1*time.Second, 30*time.Millisecond)
+
+	call := func(method, path, token string, body interface{}, res interface{}) int {
+		var reqBody bytes.Buffer
+
+		if body != nil {
+			err := json.NewEncoder(&reqBody).Encode(body)
+			require.NoError(t, err)
+		}
+
+		req, err := http.NewRequest(method, "http://127.0.0.1:8091/api"+path, &reqBody)
+		require.NoError(t, err)
+
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if res != nil && resp.StatusCode == http.StatusOK {
+			err = json.NewDecoder(resp.Body).Decode(res)
+			require.NoError(t, err)
+		}
+
+		return resp.StatusCode
+	}
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+	}
+	status := call("GET", "/openapi.json", "", nil, &spec)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, openapi.Version, spec.OpenAPI)
+
+	status = call("GET", "/docs/", "", nil, nil)
+	require.Equal(t, http.StatusNotFound, status)
+
+	b64 := base64.StdEncoding.EncodeToString
+
+	var login struct {
+		Token string `json:"token"`
+	}
+	status = call("POST", "/login", "", map[string]string{
+		"user":     b64([]byte(auth.SysAdminUsername)),
+		"password": b64([]byte(auth.SysAdminPassword)),
+	}, &login)
+	require.Equal(t, http.StatusOK, status)
+	require.NotEmpty(t, login.Token)
+
+	kv := map[string]string{"key": b64([]byte("key1")), "value": b64([]byte("value1"))}
+
+	status = call("POST", "/db/set", "", map[string]interface{}{"KVs": []interface{}{kv}}, nil)
+	require.NotEqual(t, http.StatusOK, status)
+
+	status = call("POST", "/db/set", login.Token, map[string]interface{}{"KVs": []interface{}{kv}}, nil)
+	require.Equal(t, http.StatusOK, status)
+
+	var entry struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+	status = call("GET", "/db/get/"+base64.URLEncoding.EncodeToString([]byte("key1")), login.Token, nil, &entry)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, kv["value"], entry.Value)
+
+	var verifiableEntry struct {
+		Entry struct {
+			Value string `json:"value"`
+		} `json:"entry"`
+		VerifiableTx json.RawMessage `json:"verifiableTx"`
+	}
+	status = call("POST", "/db/verifiable/get", login.Token, map[string]interface{}{
+		"keyRequest": map[string]string{"key": kv["key"]},
+	}, &verifiableEntry)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, kv["value"], verifiableEntry.Entry.Value)
+	require.NotEmpty(t, verifiableEntry.VerifiableTx)
+
+	var entries struct {
+		Entries []struct {
+			Key string `json:"key"`
+		} `json:"entries"`
+	}
+	status = call("POST", "/db/scan", login.Token, map[string]string{"prefix": b64([]byte("key"))}, &entries)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, entries.Entries, 1)
+	require.Equal(t, kv["key"], entries.Entries[0].Key)
+
+	status = call("POST", "/db/create", login.Token, map[string]string{"databaseName": "db1"}, nil)
+	require.Equal(t, http.StatusOK, status)
+
+	var dbs struct {
+		Databases []struct {
+			DatabaseName string `json:"databaseName"`
+		} `json:"databases"`
+	}
+	status = call("POST", "/db/list", login.Token, map[string]string{}, &dbs)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, dbs.Databases, 2)
+}
+
+func TestWebServerAPIAuthDisabled(t *testing.T) {
+	authEnabled, devMode := auth.AuthEnabled, auth.DevMode
+	defer func() {
+		auth.AuthEnabled, auth.DevMode = authEnabled, devMode
+	}()
+
+	auth.AuthEnabled = false
+	auth.DevMode = false
+
+	mux := runtime.NewServeMux()
+	handler := apiAuthGuard(mux, mux)
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	req.RemoteAddr = "10.0.0.1:54321"
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	// local requests reach the gateway, which has no routes registered in this case
+	req.RemoteAddr = "127.0.0.1:54321"
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stdlib
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strconv"
+	"strings"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
+	"google.golang.org/grpc/metadata"
+)
+
+// conn is a connection to an immudb database, database/sql never uses a connection concurrently
+type conn struct {
+	client client.ImmuClient
+	token  string
+	verify bool
+
+	// pks are the primary keys of the tables read with verification
+	pks map[string]string
+
+	tx *tx
+}
+
+func (c *conn) authCtx(ctx context.Context) context.Context {
+	return metadata.NewOutgoingContext(ctx, metadata.Pairs("authorization", c.token))
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext returns a statement for the query, statements are parsed and cached by the server
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	err := c.client.Logout(c.authCtx(context.Background()))
+
+	if derr := c.client.Disconnect(); err == nil {
+		err = derr
+	}
+
+	return err
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx starts a transaction, its statements are sent to the server all together when it is committed
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if c.tx != nil {
+		return nil, ErrTxInProgress
+	}
+
+	if sql.IsolationLevel(opts.Isolation) != sql.LevelDefault {
+		return nil, ErrIsolationNotSupported
+	}
+
+	c.tx = &tx{conn: c, readOnly: opts.ReadOnly}
+
+	return c.tx, nil
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	return c.client.HealthCheck(c.authCtx(ctx))
+}
+
+// CheckNamedValue accepts the unsigned integers the server supports, leaving any other value to the default conversion
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	switch nv.Value.(type) {
+	case uint, uint64:
+		return nil
+	}
+
+	return driver.ErrSkip
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.tx != nil {
+		return c.tx.exec(query, args)
+	}
+
+	res, err := c.client.SQLExec(c.authCtx(ctx), query, namedParams("", args))
+	if err != nil {
+		return nil, err
+	}
+
+	return &result{res: res}, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	res, err := c.client.SQLQuery(c.authCtx(ctx), query, namedParams("", args), true)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.verify {
+		err = c.verifyRows(ctx, res)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &rows{res: res}, nil
+}
+
+// verifyRows verifies the rows of the query result against the state of the database
+func (c *conn) verifyRows(ctx context.Context, res *schema.SQLQueryResult) error {
+	if len(res.Rows) == 0 {
+		return nil
+	}
+
+	var table string
+	cols := make([]string, len(res.Columns))
+
+	for i, col := range res.Columns {
+		// only plain column selectors, as (db.table.col), can be matched against the stored rows
+		if !strings.HasPrefix(col.Name, "(") || !strings.HasSuffix(col.Name, ")") {
+			return ErrUnverifiableQuery
+		}
+
+		sel := strings.Split(col.Name[1:len(col.Name)-1], ".")
+		if len(sel) != 3 || (table != "" && table != sel[1]) {
+			return ErrUnverifiableQuery
+		}
+
+		table = sel[1]
+		cols[i] = sel[2]
+	}
+
+	pk, err := c.primaryKey(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	pkIndex := -1
+
+	for i, col := range cols {
+		if col == pk {
+			pkIndex = i
+			break
+		}
+	}
+
+	if pkIndex < 0 {
+		return ErrUnverifiableQuery
+	}
+
+	for _, row := range res.Rows {
+		err = c.client.VerifyRow(c.authCtx(ctx), row, table, row.Values[pkIndex])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *conn) primaryKey(ctx context.Context, table string) (string, error) {
+	pk, ok := c.pks[table]
+	if ok {
+		return pk, nil
+	}
+
+	res, err := c.client.DescribeTable(c.authCtx(ctx), table)
+	if err != nil {
+		return "", err
+	}
+
+	for _, row := range res.Rows {
+		if row.Values[3].GetS() == "PRIMARY KEY" {
+			c.pks[table] = row.Values[0].GetS()
+			return c.pks[table], nil
+		}
+	}
+
+	return "", ErrUnverifiableQuery
+}
+
+// namedParams maps the arguments to the parameters of the statements, positional arguments are named
+// as the server names the positional parameters
+func namedParams(prefix string, args []driver.NamedValue) map[string]interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+
+	params := make(map[string]interface{}, len(args))
+
+	for _, arg := range args {
+		name := arg.Name
+		if name == "" {
+			name = "param" + strconv.Itoa(arg.Ordinal)
+		}
+
+		params[prefix+name] = arg.Value
+	}
+
+	return params
+}
+
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+// NumInput returns -1 as the parameters are only known to the server
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	nargs := make([]driver.NamedValue, len(args))
+
+	for i, arg := range args {
+		nargs[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+
+	return nargs
+}
+
+type result struct {
+	res *schema.SQLExecResult
+}
+
+// LastInsertId returns the primary key assigned to the inserted row, when the statement inserted rows into a single
+// table with an auto-incremental primary key
+func (r *result) LastInsertId() (int64, error) {
+	if len(r.res.LastInsertedPKs) != 1 {
+		return 0, ErrNoLastInsertID
+	}
+
+	for _, pk := range r.res.LastInsertedPKs {
+		return int64(pk.GetN()), nil
+	}
+
+	return 0, ErrNoLastInsertID
+}
+
+func (r *result) RowsAffected() (int64, error) {
+	return int64(r.res.UpdatedRows), nil
+}
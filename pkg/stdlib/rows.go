@@ -0,0 +1,90 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stdlib
+
+import (
+	"database/sql/driver"
+	"io"
+	"strings"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// rows iterates over the rows of a query result, which the server returns all at once
+type rows struct {
+	res  *schema.SQLQueryResult
+	next int
+}
+
+// Columns returns the names of the columns, or of their aliases, without the database and table they belong to.
+// Aggregations keep the selector returned by the server
+func (r *rows) Columns() []string {
+	cols := make([]string, len(r.res.Columns))
+
+	for i, col := range r.res.Columns {
+		cols[i] = columnName(col.Name)
+	}
+
+	return cols
+}
+
+func columnName(selector string) string {
+	if !strings.HasPrefix(selector, "(") || !strings.HasSuffix(selector, ")") {
+		return selector
+	}
+
+	return selector[strings.LastIndex(selector, ".")+1 : len(selector)-1]
+}
+
+// ColumnTypeDatabaseTypeName returns the SQL type of the column
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.res.Columns[index].Type
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.res.Rows) {
+		return io.EOF
+	}
+
+	for i, v := range r.res.Rows[r.next].Values {
+		dest[i] = driverValue(v)
+	}
+
+	r.next++
+
+	return nil
+}
+
+// driverValue converts the value to one of the types database/sql supports, integers are returned as int64
+func driverValue(v *schema.SQLValue) driver.Value {
+	switch tv := v.Value.(type) {
+	case *schema.SQLValue_N:
+		return int64(tv.N)
+	case *schema.SQLValue_S:
+		return tv.S
+	case *schema.SQLValue_B:
+		return tv.B
+	case *schema.SQLValue_Bs:
+		return tv.Bs
+	}
+
+	return nil
+}
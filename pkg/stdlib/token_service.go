@@ -0,0 +1,56 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stdlib
+
+import (
+	"errors"
+
+	"github.com/codenotary/immudb/pkg/client"
+)
+
+var errNoToken = errors.New("no token")
+
+// tokenService keeps no token, connections send their own token with every request
+type tokenService struct{}
+
+func (ts *tokenService) SetToken(database string, token string) error {
+	return nil
+}
+
+func (ts *tokenService) WithHds(hds client.HomedirService) client.TokenService {
+	return ts
+}
+
+func (ts *tokenService) WithTokenFileName(tfn string) client.TokenService {
+	return ts
+}
+
+func (ts *tokenService) IsTokenPresent() (bool, error) {
+	return false, nil
+}
+
+func (ts *tokenService) DeleteToken() error {
+	return nil
+}
+
+func (ts *tokenService) GetToken() (string, error) {
+	return "", errNoToken
+}
+
+func (ts *tokenService) GetDatabase() (string, error) {
+	return "", errNoToken
+}
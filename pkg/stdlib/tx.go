@@ -0,0 +1,151 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stdlib
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tx collects the statements executed in a transaction and sends them to the server as a single
+// BEGIN TRANSACTION ... COMMIT statement once committed, so either all of them are applied or none.
+// Queries executed in a transaction are served right away and do not see its statements
+type tx struct {
+	conn     *conn
+	readOnly bool
+
+	stmts  []string
+	params map[string]interface{}
+}
+
+// exec adds the statement to the transaction, renaming its parameters apart from those of the other statements
+func (t *tx) exec(query string, args []driver.NamedValue) (driver.Result, error) {
+	if t.readOnly {
+		return nil, ErrReadOnlyTx
+	}
+
+	prefix := fmt.Sprintf("tx%d_", len(t.stmts))
+
+	if t.params == nil {
+		t.params = make(map[string]interface{})
+	}
+
+	for name, v := range namedParams(prefix, args) {
+		t.params[name] = v
+	}
+
+	t.stmts = append(t.stmts, prefixParams(strings.TrimRight(query, "; \t\r\n"), prefix))
+
+	return txResult{}, nil
+}
+
+func (t *tx) Commit() error {
+	t.conn.tx = nil
+
+	if len(t.stmts) == 0 {
+		return nil
+	}
+
+	stmt := "BEGIN TRANSACTION\n" + strings.Join(t.stmts, ";\n") + ";\nCOMMIT"
+
+	_, err := t.conn.client.SQLExec(t.conn.authCtx(context.Background()), stmt, t.params)
+
+	return err
+}
+
+func (t *tx) Rollback() error {
+	t.conn.tx = nil
+	return nil
+}
+
+// prefixParams rewrites every parameter of the statement as a named parameter with the given prefix,
+// positional parameters are named as the server names them
+func prefixParams(stmt string, prefix string) string {
+	var b strings.Builder
+
+	unnamed := 0
+
+	for i := 0; i < len(stmt); i++ {
+		ch := stmt[i]
+
+		switch {
+		case ch == '\'':
+			end := strings.IndexByte(stmt[i+1:], '\'')
+			if end < 0 {
+				b.WriteString(stmt[i:])
+				return b.String()
+			}
+
+			b.WriteString(stmt[i : i+end+2])
+			i += end + 1
+		case ch == '/' && i+1 < len(stmt) && stmt[i+1] == '*':
+			end := strings.Index(stmt[i+2:], "*/")
+			if end < 0 {
+				b.WriteString(stmt[i:])
+				return b.String()
+			}
+
+			b.WriteString(stmt[i : i+end+4])
+			i += end + 3
+		case ch == '@':
+			name := readWhile(stmt[i+1:], isParamNameChar)
+			b.WriteString("@" + prefix + name)
+			i += len(name)
+		case ch == '$':
+			pos := readWhile(stmt[i+1:], isDigit)
+			b.WriteString("@" + prefix + "param" + pos)
+			i += len(pos)
+		case ch == '?':
+			unnamed++
+			b.WriteString("@" + prefix + "param" + strconv.Itoa(unnamed))
+		default:
+			b.WriteByte(ch)
+		}
+	}
+
+	return b.String()
+}
+
+func readWhile(s string, condFn func(ch byte) bool) string {
+	i := 0
+	for i < len(s) && condFn(s[i]) {
+		i++
+	}
+	return s[:i]
+}
+
+func isDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+func isParamNameChar(ch byte) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_' || isDigit(ch)
+}
+
+// txResult is the result of a statement executed in a transaction, which is only known once committed
+type txResult struct{}
+
+func (r txResult) LastInsertId() (int64, error) {
+	return 0, ErrTxResultUnavailable
+}
+
+func (r txResult) RowsAffected() (int64, error) {
+	return 0, ErrTxResultUnavailable
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stdlib
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixParams(t *testing.T) {
+	require.Equal(t,
+		"UPSERT INTO t(id, title) VALUES (@tx0_id, @tx0_title)",
+		prefixParams("UPSERT INTO t(id, title) VALUES (@id, @title)", "tx0_"))
+
+	require.Equal(t,
+		"UPSERT INTO t(id, title) VALUES (@tx1_param2, @tx1_param1)",
+		prefixParams("UPSERT INTO t(id, title) VALUES ($2, $1)", "tx1_"))
+
+	require.Equal(t,
+		"UPSERT INTO t(id, title, payload) VALUES (@tx2_param1, '@title ? $1', x'AD') /* @id? */",
+		prefixParams("UPSERT INTO t(id, title, payload) VALUES (?, '@title ? $1', x'AD') /* @id? */", "tx2_"))
+
+	require.Equal(t, "SELECT 'unterminated @id", prefixParams("SELECT 'unterminated @id", "tx0_"))
+	require.Equal(t, "SELECT /* unterminated @id", prefixParams("SELECT /* unterminated @id", "tx0_"))
+}
+
+func TestTx(t *testing.T) {
+	connector, closer := testConnector(t)
+	defer closer()
+
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, "CREATE TABLE table1(id INTEGER, title VARCHAR, PRIMARY KEY id)")
+	require.NoError(t, err)
+
+	_, err = db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	require.ErrorIs(t, err, ErrIsolationNotSupported)
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	require.NoError(t, err)
+
+	_, err = tx.ExecContext(ctx, "UPSERT INTO table1(id, title) VALUES (1, 'title1')")
+	require.ErrorIs(t, err, ErrReadOnlyTx)
+	require.NoError(t, tx.Rollback())
+
+	tx, err = db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	res, err := tx.ExecContext(ctx, "UPSERT INTO table1(id, title) VALUES ($1, $2);", 1, "title1")
+	require.NoError(t, err)
+
+	_, err = res.RowsAffected()
+	require.ErrorIs(t, err, ErrTxResultUnavailable)
+
+	_, err = res.LastInsertId()
+	require.ErrorIs(t, err, ErrTxResultUnavailable)
+
+	_, err = tx.ExecContext(ctx, "UPSERT INTO table1(id, title) VALUES (@id, @title)", sql.Named("id", 2), sql.Named("title", "title2"))
+	require.NoError(t, err)
+
+	// statements are only sent once the transaction is committed
+	var count int64
+	err = tx.QueryRowContext(ctx, "SELECT COUNT() FROM table1").Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count)
+
+	require.NoError(t, tx.Commit())
+
+	err = db.QueryRowContext(ctx, "SELECT COUNT() FROM table1").Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	tx, err = db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	_, err = tx.ExecContext(ctx, "UPSERT INTO table1(id, title) VALUES (?, ?)", 3, "title3")
+	require.NoError(t, err)
+
+	require.NoError(t, tx.Rollback())
+
+	err = db.QueryRowContext(ctx, "SELECT COUNT() FROM table1").Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	// a failing statement discards the whole transaction
+	tx, err = db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	_, err = tx.ExecContext(ctx, "UPSERT INTO table1(id, title) VALUES (?, ?)", 3, "title3")
+	require.NoError(t, err)
+
+	_, err = tx.ExecContext(ctx, "UPSERT INTO table2(id, title) VALUES (?, ?)", 4, "title4")
+	require.NoError(t, err)
+
+	require.Error(t, tx.Commit())
+
+	err = db.QueryRowContext(ctx, "SELECT COUNT() FROM table1").Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	sqlConn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sqlConn.Close()
+
+	err = sqlConn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*conn)
+
+		_, err := c.Begin()
+		require.NoError(t, err)
+
+		_, err = c.Begin()
+		require.ErrorIs(t, err, ErrTxInProgress)
+
+		return c.tx.Rollback()
+	})
+	require.NoError(t, err)
+}
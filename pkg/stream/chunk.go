@@ -0,0 +1,97 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stream
+
+import (
+	"io"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+type chunkWriter struct {
+	stream ImmuServiceSender_Stream
+	b      []byte
+}
+
+// NewChunkWriter returns a writer sending raw content on a stream, split in chunks of the given size.
+// Unlike MsgSender, the size of the content doesn't need to be known in advance. Close flushes pending data
+func NewChunkWriter(s ImmuServiceSender_Stream, chunkSize int) io.WriteCloser {
+	return &chunkWriter{
+		stream: s,
+		b:      make([]byte, 0, chunkSize),
+	}
+}
+
+func (cw *chunkWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		c := copy(cw.b[len(cw.b):cap(cw.b)], p)
+		cw.b = cw.b[:len(cw.b)+c]
+		p = p[c:]
+		n += c
+
+		if len(cw.b) == cap(cw.b) {
+			err = cw.flush()
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+func (cw *chunkWriter) flush() error {
+	if len(cw.b) == 0 {
+		return nil
+	}
+
+	content := make([]byte, len(cw.b))
+	copy(content, cw.b)
+
+	cw.b = cw.b[:0]
+
+	return cw.stream.Send(&schema.Chunk{Content: content})
+}
+
+func (cw *chunkWriter) Close() error {
+	return cw.flush()
+}
+
+type chunkReader struct {
+	stream ImmuServiceReceiver_Stream
+	b      []byte
+}
+
+// NewChunkReader returns a reader over the raw content received on a stream, io.EOF is returned when the stream ends
+func NewChunkReader(s ImmuServiceReceiver_Stream) io.Reader {
+	return &chunkReader{stream: s}
+}
+
+func (cr *chunkReader) Read(p []byte) (int, error) {
+	for len(cr.b) == 0 {
+		chunk, err := cr.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		cr.b = chunk.Content
+	}
+
+	n := copy(p, cr.b)
+	cr.b = cr.b[n:]
+
+	return n, nil
+}
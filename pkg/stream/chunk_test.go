@@ -0,0 +1,88 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/stream/streamtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkWriterAndReader(t *testing.T) {
+	var chunks []*streamtest.ChunkError
+
+	sm := streamtest.DefaultImmuServiceSenderStreamMock()
+	sm.SendF = func(c *schema.Chunk) error {
+		require.LessOrEqual(t, len(c.Content), 10)
+		chunks = append(chunks, &streamtest.ChunkError{C: c})
+		return nil
+	}
+
+	content := make([]byte, 25)
+	for i := range content {
+		content[i] = byte(i)
+	}
+
+	cw := NewChunkWriter(sm, 10)
+
+	n, err := cw.Write(content[:7])
+	require.NoError(t, err)
+	require.Equal(t, 7, n)
+
+	n, err = cw.Write(content[7:])
+	require.NoError(t, err)
+	require.Equal(t, 18, n)
+	require.Len(t, chunks, 2)
+
+	err = cw.Close()
+	require.NoError(t, err)
+	require.Len(t, chunks, 3)
+
+	chunks = append(chunks, &streamtest.ChunkError{E: io.EOF})
+
+	cr := NewChunkReader(streamtest.DefaultImmuServiceReceiverStreamMock(chunks))
+
+	read, err := ioutil.ReadAll(cr)
+	require.NoError(t, err)
+	require.True(t, bytes.Equal(content, read))
+}
+
+func TestChunkWriterError(t *testing.T) {
+	errSend := errors.New("send error")
+
+	sm := streamtest.DefaultImmuServiceSenderStreamMock()
+	sm.SendF = func(c *schema.Chunk) error {
+		return errSend
+	}
+
+	cw := NewChunkWriter(sm, 10)
+
+	_, err := cw.Write(make([]byte, 5))
+	require.NoError(t, err)
+
+	err = cw.Close()
+	require.Equal(t, errSend, err)
+
+	_, err = cw.Write(make([]byte, 15))
+	require.Equal(t, errSend, err)
+}
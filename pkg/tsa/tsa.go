@@ -0,0 +1,305 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tsa implements a minimal RFC 3161 time-stamp protocol client
+package tsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+var ErrIllegalArguments = errors.New("illegal arguments")
+var ErrMalformedResponse = errors.New("malformed time-stamp response")
+var ErrTimestampRejected = errors.New("time-stamp request rejected")
+var ErrImprintMismatch = errors.New("time-stamp token does not match the requested digest")
+var ErrNonceMismatch = errors.New("time-stamp token does not match the request nonce")
+
+const maxResponseSize = 1 << 20
+
+var oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+
+// Client requests time-stamp tokens for sha256 digests
+type Client interface {
+	Timestamp(digest [sha256.Size]byte) (*Token, error)
+}
+
+// Token is a time-stamp token issued by a TSA
+type Token struct {
+	GenTime       time.Time
+	HashedMessage []byte
+	Nonce         *big.Int
+	// Raw is the DER encoded TimeStampToken as returned by the TSA
+	Raw []byte
+}
+
+type httpClient struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPClient returns a client submitting time-stamp requests to the TSA at url
+func NewHTTPClient(url string, timeout time.Duration) (Client, error) {
+	if url == "" {
+		return nil, ErrIllegalArguments
+	}
+
+	return &httpClient{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (c *httpClient) Timestamp(digest [sha256.Size]byte) (*Token, error) {
+	nonce, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := NewRequest(digest, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Post(c.url, "application/timestamp-query", bytes.NewReader(req))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: http status %d", ErrTimestampRejected, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := ParseResponse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(token.HashedMessage, digest[:]) {
+		return nil, ErrImprintMismatch
+	}
+
+	if token.Nonce == nil || token.Nonce.Cmp(nonce) != 0 {
+		return nil, ErrNonceMismatch
+	}
+
+	return token, nil
+}
+
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+type messageImprint struct {
+	HashAlgorithm algorithmIdentifier
+	HashedMessage []byte
+}
+
+type timeStampReq struct {
+	Version        int
+	MessageImprint messageImprint
+	Nonce          *big.Int
+	CertReq        bool `asn1:"optional"`
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type encapsulatedContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     []byte `asn1:"explicit,tag:0"`
+}
+
+// NewRequest returns the DER encoded TimeStampReq for a sha256 digest.
+// The TSA is asked to include its certificate so that tokens can be verified offline
+func NewRequest(digest [sha256.Size]byte, nonce *big.Int) ([]byte, error) {
+	if nonce == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	return asn1.Marshal(timeStampReq{
+		Version: 1,
+		MessageImprint: messageImprint{
+			HashAlgorithm: algorithmIdentifier{
+				Algorithm:  oidSHA256,
+				Parameters: asn1.NullRawValue,
+			},
+			HashedMessage: digest[:],
+		},
+		Nonce:   nonce,
+		CertReq: true,
+	})
+}
+
+// ParseResponse decodes a DER encoded TimeStampResp and returns its token
+func ParseResponse(data []byte) (*Token, error) {
+	var resp asn1.RawValue
+
+	rest, err := asn1.Unmarshal(data, &resp)
+	if err != nil || len(rest) > 0 || resp.Tag != asn1.TagSequence {
+		return nil, ErrMalformedResponse
+	}
+
+	var statusInfo asn1.RawValue
+
+	rest, err = asn1.Unmarshal(resp.Bytes, &statusInfo)
+	if err != nil || statusInfo.Tag != asn1.TagSequence {
+		return nil, ErrMalformedResponse
+	}
+
+	var status int
+
+	_, err = asn1.Unmarshal(statusInfo.Bytes, &status)
+	if err != nil {
+		return nil, ErrMalformedResponse
+	}
+
+	// granted (0) and grantedWithMods (1)
+	if status != 0 && status != 1 {
+		return nil, fmt.Errorf("%w: status %d", ErrTimestampRejected, status)
+	}
+
+	if len(rest) == 0 {
+		return nil, ErrMalformedResponse
+	}
+
+	return ParseToken(rest)
+}
+
+// ParseToken decodes a DER encoded TimeStampToken. The signature of the token
+// is not verified, tools such as openssl ts can be used for that
+func ParseToken(data []byte) (*Token, error) {
+	var ci contentInfo
+
+	rest, err := asn1.Unmarshal(data, &ci)
+	if err != nil {
+		return nil, ErrMalformedResponse
+	}
+
+	raw := data[:len(data)-len(rest)]
+
+	var signedData asn1.RawValue
+
+	_, err = asn1.Unmarshal(ci.Content.Bytes, &signedData)
+	if err != nil || signedData.Tag != asn1.TagSequence {
+		return nil, ErrMalformedResponse
+	}
+
+	// SignedData ::= SEQUENCE { version, digestAlgorithms, encapContentInfo, ... }
+	var version int
+	var digestAlgorithms asn1.RawValue
+	var encapContent encapsulatedContentInfo
+
+	rest, err = asn1.Unmarshal(signedData.Bytes, &version)
+	if err == nil {
+		rest, err = asn1.Unmarshal(rest, &digestAlgorithms)
+	}
+	if err == nil {
+		_, err = asn1.Unmarshal(rest, &encapContent)
+	}
+	if err != nil {
+		return nil, ErrMalformedResponse
+	}
+
+	token, err := parseTSTInfo(encapContent.EContent)
+	if err != nil {
+		return nil, err
+	}
+
+	token.Raw = append([]byte{}, raw...)
+
+	return token, nil
+}
+
+// parseTSTInfo decodes TSTInfo ::= SEQUENCE { version, policy, messageImprint,
+// serialNumber, genTime, accuracy OPTIONAL, ordering DEFAULT FALSE, nonce OPTIONAL, ... }
+func parseTSTInfo(data []byte) (*Token, error) {
+	var tstInfo asn1.RawValue
+
+	_, err := asn1.Unmarshal(data, &tstInfo)
+	if err != nil || tstInfo.Tag != asn1.TagSequence {
+		return nil, ErrMalformedResponse
+	}
+
+	var version int
+	var policy asn1.ObjectIdentifier
+	var imprint messageImprint
+	var serialNumber *big.Int
+	var genTime time.Time
+
+	rest, err := asn1.Unmarshal(tstInfo.Bytes, &version)
+	if err == nil {
+		rest, err = asn1.Unmarshal(rest, &policy)
+	}
+	if err == nil {
+		rest, err = asn1.Unmarshal(rest, &imprint)
+	}
+	if err == nil {
+		rest, err = asn1.Unmarshal(rest, &serialNumber)
+	}
+	if err == nil {
+		rest, err = asn1.UnmarshalWithParams(rest, &genTime, "generalized")
+	}
+	if err != nil {
+		return nil, ErrMalformedResponse
+	}
+
+	if !imprint.HashAlgorithm.Algorithm.Equal(oidSHA256) {
+		return nil, ErrMalformedResponse
+	}
+
+	token := &Token{
+		GenTime:       genTime,
+		HashedMessage: imprint.HashedMessage,
+	}
+
+	for len(rest) > 0 {
+		var field asn1.RawValue
+
+		rest, err = asn1.Unmarshal(rest, &field)
+		if err != nil {
+			return nil, ErrMalformedResponse
+		}
+
+		if field.Class == asn1.ClassUniversal && field.Tag == asn1.TagInteger {
+			_, err = asn1.Unmarshal(field.FullBytes, &token.Nonce)
+			if err != nil {
+				return nil, ErrMalformedResponse
+			}
+			break
+		}
+	}
+
+	return token, nil
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2021 CodeNotary, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsa
+
+import (
+	"crypto/sha256"
+	"encoding/asn1"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type pkiStatusInfo struct {
+	Status int
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []algorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapsulatedContentInfo
+	SignerInfos      []asn1.RawValue `asn1:"set"`
+}
+
+type accuracy struct {
+	Seconds int
+}
+
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint messageImprint
+	SerialNumber   *big.Int
+	GenTime        time.Time `asn1:"generalized"`
+	Accuracy       accuracy
+	Nonce          *big.Int
+}
+
+func marshalResponse(t *testing.T, status int, imprint messageImprint, nonce *big.Int, genTime time.Time) []byte {
+	info, err := asn1.Marshal(tstInfo{
+		Version:        1,
+		Policy:         asn1.ObjectIdentifier{1, 2, 3},
+		MessageImprint: imprint,
+		SerialNumber:   big.NewInt(42),
+		GenTime:        genTime,
+		Accuracy:       accuracy{Seconds: 1},
+		Nonce:          nonce,
+	})
+	require.NoError(t, err)
+
+	sd, err := asn1.Marshal(signedData{
+		Version:          3,
+		DigestAlgorithms: []algorithmIdentifier{imprint.HashAlgorithm},
+		EncapContentInfo: encapsulatedContentInfo{
+			EContentType: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4},
+			EContent:     info,
+		},
+		SignerInfos: []asn1.RawValue{asn1.NullRawValue},
+	})
+	require.NoError(t, err)
+
+	token, err := asn1.Marshal(struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue
+	}{
+		ContentType: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2},
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: sd},
+	})
+	require.NoError(t, err)
+
+	statusInfo, err := asn1.Marshal(pkiStatusInfo{Status: status})
+	require.NoError(t, err)
+
+	if status != 0 {
+		resp, err := asn1.Marshal(struct{ Status asn1.RawValue }{asn1.RawValue{FullBytes: statusInfo}})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp, err := asn1.Marshal(struct {
+		Status asn1.RawValue
+		Token  asn1.RawValue
+	}{asn1.RawValue{FullBytes: statusInfo}, asn1.RawValue{FullBytes: token}})
+	require.NoError(t, err)
+
+	return resp
+}
+
+func TestHTTPClient(t *testing.T) {
+	_, err := NewHTTPClient("", time.Second)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	genTime := time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	var status int
+	var tamperImprint, tamperNonce bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/timestamp-query", r.Header.Get("Content-Type"))
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var req timeStampReq
+		_, err = asn1.Unmarshal(body, &req)
+		require.NoError(t, err)
+		require.Equal(t, 1, req.Version)
+		require.True(t, req.CertReq)
+
+		if tamperImprint {
+			req.MessageImprint.HashedMessage = make([]byte, sha256.Size)
+		}
+		if tamperNonce {
+			req.Nonce = big.NewInt(1)
+		}
+
+		w.Header().Set("Content-Type", "application/timestamp-reply")
+		w.Write(marshalResponse(t, status, req.MessageImprint, req.Nonce, genTime))
+	}))
+	defer srv.Close()
+
+	client, err := NewHTTPClient(srv.URL, time.Second)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("root"))
+
+	token, err := client.Timestamp(digest)
+	require.NoError(t, err)
+	require.Equal(t, genTime, token.GenTime.UTC())
+	require.Equal(t, digest[:], token.HashedMessage)
+	require.NotEmpty(t, token.Raw)
+
+	parsed, err := ParseToken(token.Raw)
+	require.NoError(t, err)
+	require.Equal(t, token, parsed)
+
+	tamperImprint = true
+	_, err = client.Timestamp(digest)
+	require.Equal(t, ErrImprintMismatch, err)
+
+	tamperImprint = false
+	tamperNonce = true
+	_, err = client.Timestamp(digest)
+	require.Equal(t, ErrNonceMismatch, err)
+
+	tamperNonce = false
+	status = 2
+	_, err = client.Timestamp(digest)
+	require.ErrorIs(t, err, ErrTimestampRejected)
+}
+
+func TestHTTPClientErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+
+	client, err := NewHTTPClient(srv.URL, time.Second)
+	require.NoError(t, err)
+
+	_, err = client.Timestamp(sha256.Sum256(nil))
+	require.ErrorIs(t, err, ErrTimestampRejected)
+
+	srv.Close()
+
+	_, err = client.Timestamp(sha256.Sum256(nil))
+	require.Error(t, err)
+}
+
+func TestParseMalformed(t *testing.T) {
+	_, err := NewRequest(sha256.Sum256(nil), nil)
+	require.Equal(t, ErrIllegalArguments, err)
+
+	_, err = ParseResponse([]byte{1, 2, 3})
+	require.Equal(t, ErrMalformedResponse, err)
+
+	_, err = ParseResponse([]byte{0x30, 0x00})
+	require.Equal(t, ErrMalformedResponse, err)
+
+	_, err = ParseToken([]byte{0x30, 0x00})
+	require.Equal(t, ErrMalformedResponse, err)
+
+	_, err = parseTSTInfo([]byte{0x30, 0x00})
+	require.Equal(t, ErrMalformedResponse, err)
+}